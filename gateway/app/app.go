@@ -9,6 +9,7 @@ import (
 	"gateway/internal/clients/users"
 	"gateway/internal/service"
 	"gateway/pkg/jaeger"
+	"gateway/pkg/oidc"
 	"github.com/opentracing/opentracing-go"
 	"golang.org/x/sync/errgroup"
 
@@ -35,7 +36,14 @@ func NewApp(cfg *config.Config) (*App, error) {
 		return nil, fmt.Errorf("[NewApp] grpc users: %w", err)
 	}
 
-	gatewayService := service.NewGatewayService(&cfg.JWT, todosClient, usersClient)
+	// без провайдера маршруты входа через OpenID Connect отвечают 404
+	var identityProvider service.IdentityProvider
+	if cfg.OIDC.Enabled {
+		identityProvider = oidc.NewProvider(cfg.OIDC)
+		logger.Info().Msgf("external login enabled, issuer '%s'", cfg.OIDC.IssuerURL)
+	}
+
+	gatewayService := service.NewGatewayService(&cfg.JWT, todosClient, usersClient, identityProvider)
 
 	return &App{
 		cfg:            cfg,
@@ -0,0 +1,282 @@
+// oidc-mock - локальный провайдер OpenID Connect для проверки входа через провайдера без настоящего SSO.
+// Пароли не спрашивает: /authorize сразу возвращает код для пользователя из login_hint
+// (по умолчанию MOCK_OIDC_DEFAULT_EMAIL). Ключ подписи создается заново при каждом запуске.
+package main
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"log"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"gateway/pkg/oidc"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/kelseyhightower/envconfig"
+)
+
+const keyID = "mock-key"
+
+type config struct {
+	Addr         string `envconfig:"MOCK_OIDC_ADDR" default:":9000"`
+	Issuer       string `envconfig:"MOCK_OIDC_ISSUER" default:"http://localhost:9000"`
+	ClientID     string `envconfig:"MOCK_OIDC_CLIENT_ID" default:"todo-gateway"`
+	ClientSecret string `envconfig:"MOCK_OIDC_CLIENT_SECRET"`
+	DefaultEmail string `envconfig:"MOCK_OIDC_DEFAULT_EMAIL" default:"sso.user@example.com"`
+	// EmailVerified - что провайдер сообщает о почте пользователя, false проверяет отказ во входе
+	EmailVerified bool          `envconfig:"MOCK_OIDC_EMAIL_VERIFIED" default:"true"`
+	TokenExp      time.Duration `envconfig:"MOCK_OIDC_TOKEN_EXP" default:"5m"`
+}
+
+// authorization - выданный, но еще не обмененный код
+type authorization struct {
+	redirectURI   string
+	nonce         string
+	codeChallenge string
+	email         string
+	expiresAt     time.Time
+}
+
+type mockProvider struct {
+	cfg config
+	key *rsa.PrivateKey
+
+	mu           sync.Mutex
+	codes        map[string]*authorization
+	accessTokens map[string]jwt.MapClaims
+}
+
+func main() {
+	var cfg config
+	envconfig.MustProcess("", &cfg)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		log.Fatalf("generate key: %s", err)
+	}
+
+	p := &mockProvider{
+		cfg:          cfg,
+		key:          key,
+		codes:        map[string]*authorization{},
+		accessTokens: map[string]jwt.MapClaims{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", p.discovery)
+	mux.HandleFunc("/jwks", p.jwks)
+	mux.HandleFunc("/authorize", p.authorize)
+	mux.HandleFunc("/token", p.token)
+	mux.HandleFunc("/userinfo", p.userinfo)
+
+	log.Printf("mock OIDC issuer %s listening on %s", cfg.Issuer, cfg.Addr)
+	log.Fatal(http.ListenAndServe(cfg.Addr, mux))
+}
+
+func (p *mockProvider) discovery(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"issuer":                                p.cfg.Issuer,
+		"authorization_endpoint":                p.cfg.Issuer + "/authorize",
+		"token_endpoint":                        p.cfg.Issuer + "/token",
+		"userinfo_endpoint":                     p.cfg.Issuer + "/userinfo",
+		"jwks_uri":                              p.cfg.Issuer + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+	})
+}
+
+func (p *mockProvider) jwks(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kid": keyID,
+			"kty": "RSA",
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(p.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(p.key.E)).Bytes()),
+		}},
+	})
+}
+
+func (p *mockProvider) authorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	redirectURI, err := url.Parse(query.Get("redirect_uri"))
+	if err != nil || redirectURI.Scheme == "" {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+	if query.Get("client_id") != p.cfg.ClientID {
+		http.Error(w, "unknown client_id", http.StatusBadRequest)
+		return
+	}
+
+	// дальше ошибки по протоколу возвращаются клиенту через redirect_uri
+	redirectQuery := redirectURI.Query()
+	redirectQuery.Set("state", query.Get("state"))
+
+	switch {
+	case query.Get("response_type") != "code":
+		redirectQuery.Set("error", "unsupported_response_type")
+	case query.Get("code_challenge_method") != "S256" || query.Get("code_challenge") == "":
+		redirectQuery.Set("error", "invalid_request")
+		redirectQuery.Set("error_description", "PKCE with S256 is required")
+	default:
+		email := query.Get("login_hint")
+		if email == "" {
+			email = p.cfg.DefaultEmail
+		}
+
+		code := randomString()
+		p.mu.Lock()
+		p.codes[code] = &authorization{
+			redirectURI:   query.Get("redirect_uri"),
+			nonce:         query.Get("nonce"),
+			codeChallenge: query.Get("code_challenge"),
+			email:         email,
+			expiresAt:     time.Now().Add(time.Minute),
+		}
+		p.mu.Unlock()
+
+		redirectQuery.Set("code", code)
+	}
+
+	redirectURI.RawQuery = redirectQuery.Encode()
+	http.Redirect(w, r, redirectURI.String(), http.StatusFound)
+}
+
+func (p *mockProvider) token(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		oauthError(w, http.StatusBadRequest, "invalid_request")
+		return
+	}
+
+	if !p.authenticateClient(r) {
+		oauthError(w, http.StatusUnauthorized, "invalid_client")
+		return
+	}
+	if r.PostForm.Get("grant_type") != "authorization_code" {
+		oauthError(w, http.StatusBadRequest, "unsupported_grant_type")
+		return
+	}
+
+	// код одноразовый: удаляется при первом предъявлении, даже неудачном
+	code := r.PostForm.Get("code")
+	p.mu.Lock()
+	auth, ok := p.codes[code]
+	delete(p.codes, code)
+	p.mu.Unlock()
+
+	if !ok || time.Now().After(auth.expiresAt) || auth.redirectURI != r.PostForm.Get("redirect_uri") ||
+		oidc.CodeChallenge(r.PostForm.Get("code_verifier")) != auth.codeChallenge {
+		oauthError(w, http.StatusBadRequest, "invalid_grant")
+		return
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":                p.cfg.Issuer,
+		"sub":                "mock|" + auth.email,
+		"aud":                p.cfg.ClientID,
+		"iat":                now.Unix(),
+		"exp":                now.Add(p.cfg.TokenExp).Unix(),
+		"email":              auth.email,
+		"email_verified":     p.cfg.EmailVerified,
+		"preferred_username": strings.SplitN(auth.email, "@", 2)[0],
+	}
+	if auth.nonce != "" {
+		claims["nonce"] = auth.nonce
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = keyID
+	idToken, err := token.SignedString(p.key)
+	if err != nil {
+		oauthError(w, http.StatusInternalServerError, "server_error")
+		return
+	}
+
+	accessToken := randomString()
+	p.mu.Lock()
+	p.accessTokens[accessToken] = claims
+	p.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": accessToken,
+		"token_type":   "Bearer",
+		"expires_in":   int(p.cfg.TokenExp.Seconds()),
+		"id_token":     idToken,
+	})
+}
+
+func (p *mockProvider) userinfo(w http.ResponseWriter, r *http.Request) {
+	accessToken := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+	p.mu.Lock()
+	claims, ok := p.accessTokens[accessToken]
+	p.mu.Unlock()
+
+	if !ok {
+		oauthError(w, http.StatusUnauthorized, "invalid_token")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"sub":                claims["sub"],
+		"email":              claims["email"],
+		"email_verified":     claims["email_verified"],
+		"preferred_username": claims["preferred_username"],
+	})
+}
+
+// authenticateClient проверяет client_secret_basic, если секрет задан, иначе client_id публичного клиента
+func (p *mockProvider) authenticateClient(r *http.Request) bool {
+	if p.cfg.ClientSecret == "" {
+		clientID, _, ok := r.BasicAuth()
+		if !ok {
+			clientID = r.PostForm.Get("client_id")
+		}
+		return clientID == p.cfg.ClientID
+	}
+
+	clientID, secret, ok := r.BasicAuth()
+	if !ok {
+		return false
+	}
+	clientID, _ = url.QueryUnescape(clientID)
+	secret, _ = url.QueryUnescape(secret)
+
+	return clientID == p.cfg.ClientID && subtle.ConstantTimeCompare([]byte(secret), []byte(p.cfg.ClientSecret)) == 1
+}
+
+func oauthError(w http.ResponseWriter, status int, code string) {
+	writeJSON(w, status, map[string]string{"error": code})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func randomString() string {
+	value, err := oidc.RandomString()
+	if err != nil {
+		panic(err)
+	}
+	return value
+}
@@ -4,6 +4,7 @@ import (
 	"gateway/pkg/jaeger"
 	"gateway/pkg/jwtutil"
 	"gateway/pkg/logging"
+	"gateway/pkg/oidc"
 	"github.com/kelseyhightower/envconfig"
 )
 
@@ -14,11 +15,17 @@ type Config struct {
 	Jaeger      jaeger.JaegerConfig  `envconfig:"JAEGER"`
 	UsersClient UsersClient          `envconfig:"USERS"`
 	TodosClient TodosClient          `envconfig:"TODOS"`
+	OIDC        oidc.Config          `envconfig:"OIDC"`
 }
 
 type App struct {
 	AppHost string `envconfig:"APP_HOST" required:"true" default:"0.0.0.0"`
 	AppPort string `envconfig:"APP_PORT" required:"true" default:"3009"`
+	// PublicURL - адрес gateway, по которому его видят внешние клиенты, из него собираются публичные ссылки
+	PublicURL string `envconfig:"APP_PUBLIC_URL" required:"true" default:"http://localhost:3009"`
+	// TrustProxyHeaders - брать адрес клиента из X-Forwarded-For и X-Real-IP. Включать, только если gateway
+	// стоит за прокси, который сам выставляет эти заголовки, иначе клиент может подставить любой адрес
+	TrustProxyHeaders bool `envconfig:"APP_TRUST_PROXY_HEADERS" default:"false"`
 }
 
 type UsersClient struct {
@@ -11,13 +11,72 @@ type GatewayService interface {
 	UpdateUser(ctx context.Context, updatedUser *models.UserDTO) (*models.UserDTO, error)
 	UpdatePassword(ctx context.Context, updatePassword *models.UpdateUserPasswordDTO) error
 	DeleteUser(ctx context.Context, userID int) error
+	GetUserDeletion(ctx context.Context, userID int) (*models.UserDeletionDTO, error)
 	GetUserByID(ctx context.Context, userID int) (*models.UserDTO, error)
+	ListUsers(ctx context.Context, filter *models.ListUsersDTO) (*models.UsersPageDTO, error)
+	SetUserRole(ctx context.Context, userID int, request *models.SetUserRoleDTO) error
+	UnlockUser(ctx context.Context, userID int) error
 	Login(ctx context.Context, login *models.UserLoginDTO) (*models.UserTokens, error)
 	Refresh(ctx context.Context, refresh string) (*models.UserTokens, error)
+	Logout(ctx context.Context) error
+	LogoutAll(ctx context.Context) error
+	LoginTwoFactor(ctx context.Context, request *models.TwoFactorLoginDTO) (*models.UserTokens, error)
+	StartExternalLogin(ctx context.Context) (*models.ExternalLoginStartDTO, error)
+	CompleteExternalLogin(ctx context.Context, callback *models.ExternalLoginCallbackDTO) (*models.UserTokens, error)
+	CreateApiToken(ctx context.Context, request *models.CreateApiTokenDTO) (*models.ApiTokenDTO, error)
+	GetApiTokens(ctx context.Context) ([]models.ApiTokenDTO, error)
+	RevokeApiToken(ctx context.Context, tokenID uuid.UUID) error
+	AuthenticateApiToken(ctx context.Context, token string) (*models.ApiTokenAuthDTO, error)
+	EnrollTwoFactor(ctx context.Context) (*models.TwoFactorEnrollmentDTO, error)
+	ConfirmTwoFactor(ctx context.Context, request *models.TwoFactorCodeDTO) (*models.RecoveryCodesDTO, error)
+	DisableTwoFactor(ctx context.Context, request *models.TwoFactorCodeDTO) error
+	RegenerateRecoveryCodes(ctx context.Context, request *models.TwoFactorCodeDTO) (*models.RecoveryCodesDTO, error)
+	VerifyEmail(ctx context.Context, token string) error
+	ResendVerificationEmail(ctx context.Context, email string) error
+	RequestPasswordReset(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, request *models.ResetPasswordDTO) error
+	RequestEmailChange(ctx context.Context, request *models.RequestEmailChangeDTO) error
+	ConfirmEmailChange(ctx context.Context, token string) error
+	RequestDataExport(ctx context.Context) (*models.DataExportDTO, error)
+	GetDataExport(ctx context.Context) (*models.DataExportDTO, error)
+	DownloadDataExport(ctx context.Context, token string) (*models.DataExportArchive, error)
+	RequestErasure(ctx context.Context, request *models.RequestErasureDTO) error
+	ConfirmErasure(ctx context.Context, token string) (*models.UserDeletionDTO, error)
+
+	CreateOrganization(ctx context.Context, request *models.CreateOrganizationDTO) (*models.OrganizationDTO, error)
+	GetOrganizations(ctx context.Context) ([]models.OrganizationDTO, error)
+	ListOrganizationMembers(ctx context.Context, organizationID int) ([]models.OrganizationMemberDTO, error)
+	InviteMember(ctx context.Context, organizationID int, request *models.InviteMemberDTO) (*models.InvitationDTO, error)
+	AcceptInvitation(ctx context.Context, request *models.AcceptInvitationDTO) (*models.OrganizationDTO, error)
+	SetMemberRole(ctx context.Context, organizationID, memberID int, request *models.SetMemberRoleDTO) error
+	RemoveMember(ctx context.Context, organizationID, memberID int) error
+	CreateTeam(ctx context.Context, organizationID int, request *models.CreateTeamDTO) (*models.TeamDTO, error)
+	GetTeams(ctx context.Context, organizationID int) ([]models.TeamDTO, error)
+	AddTeamMember(ctx context.Context, organizationID, teamID int, request *models.TeamMemberDTO) error
+	RemoveTeamMember(ctx context.Context, organizationID, teamID, memberID int) error
+	ListTeamMembers(ctx context.Context, organizationID, teamID int) ([]models.OrganizationMemberDTO, error)
+
+	UploadAvatar(ctx context.Context, image []byte) (*models.UserDTO, error)
+	DeleteAvatar(ctx context.Context) (*models.UserDTO, error)
+
+	GetNotificationPreferences(ctx context.Context) (*models.NotificationPreferencesDTO, error)
+	UpdateNotificationPreferences(ctx context.Context, request *models.NotificationPreferencesDTO) (*models.NotificationPreferencesDTO, error)
 
 	CreateToDo(ctx context.Context, newTodo *models.CreateTodoDTO) (*models.TodoDTO, error)
 	UpdateToDo(ctx context.Context, newTodo *models.TodoDTO) (*models.TodoDTO, error)
 	GetToDos(ctx context.Context, todos *models.GetTodosDTO) ([]models.TodoDTO, error)
+	GetMyToDos(ctx context.Context, todos *models.GetTodosDTO) ([]models.TodoDTO, error)
 	GetToDo(ctx context.Context, todoID uuid.UUID) (*models.TodoDTO, error)
 	DeleteToDo(ctx context.Context, todoID uuid.UUID) error
+
+	CreateSavedFilter(ctx context.Context, newFilter *models.SavedFilterDTO) (*models.SavedFilterDTO, error)
+	UpdateSavedFilter(ctx context.Context, newFilter *models.SavedFilterDTO) (*models.SavedFilterDTO, error)
+	DeleteSavedFilter(ctx context.Context, filterID uuid.UUID) error
+	GetSavedFilters(ctx context.Context) ([]models.SavedFilterDTO, error)
+	RunSavedFilter(ctx context.Context, filterID uuid.UUID) ([]models.TodoDTO, error)
+
+	CreateShareLink(ctx context.Context, todoID uuid.UUID, request *models.CreateShareLinkDTO) (*models.ShareLinkDTO, error)
+	GetShareLinks(ctx context.Context, todoID uuid.UUID) ([]models.ShareLinkDTO, error)
+	RevokeShareLink(ctx context.Context, linkID uuid.UUID) error
+	GetSharedTodo(ctx context.Context, token string) (*models.SharedTodoDTO, error)
 }
@@ -0,0 +1,157 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"gateway/internal/app_errors"
+	"gateway/internal/models"
+	"gateway/pkg/ctxutil"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/opentracing/opentracing-go"
+	"net/http"
+	"strings"
+)
+
+// apiTokenRoute - области, которые нужны персональному токену на маршрутах с префиксом prefix.
+// Для чтения (GET, HEAD) хватает read или write, для остальных методов нужна write.
+// Маршрут без областей с токеном недоступен
+type apiTokenRoute struct {
+	prefix string
+	read   string
+	write  string
+}
+
+// apiTokenRoutes проверяются по порядку до первого совпадения префикса. Маршрут, которого нет в списке,
+// с персональным токеном недоступен, поэтому новые маршруты не открываются токенам случайно
+var apiTokenRoutes = []apiTokenRoute{
+	// пароль, email, второй фактор, сессии и сами токены меняются только из сессии, начатой входом.
+	// Выгрузить все данные и удалить аккаунт тоже можно только из нее
+	{prefix: "/api/v1/users/tokens"},
+	{prefix: "/api/v1/users/update-password"},
+	{prefix: "/api/v1/users/email"},
+	{prefix: "/api/v1/users/2fa"},
+	{prefix: "/api/v1/users/logout"},
+	{prefix: "/api/v1/users/logout-all"},
+	{prefix: "/api/v1/users/delete"},
+	{prefix: "/api/v1/users/export"},
+	{prefix: "/api/v1/users/erasure"},
+	{prefix: "/api/v1/users", read: models.ApiTokenScopeReadProfile, write: models.ApiTokenScopeWriteProfile},
+	// POST /todos/batch только читает задачи
+	{prefix: "/api/v1/todos/batch", read: models.ApiTokenScopeReadTodos, write: models.ApiTokenScopeReadTodos},
+	{prefix: "/api/v1/todos", read: models.ApiTokenScopeReadTodos, write: models.ApiTokenScopeWriteTodos},
+	{prefix: "/api/v1/filters", read: models.ApiTokenScopeReadTodos, write: models.ApiTokenScopeWriteTodos},
+	{prefix: "/api/v1/me", read: models.ApiTokenScopeReadTodos, write: models.ApiTokenScopeWriteTodos},
+}
+
+// apiTokenAllows сообщает, разрешают ли области токена запрос
+func apiTokenAllows(auth *models.ApiTokenAuthDTO, r *http.Request) bool {
+	for _, route := range apiTokenRoutes {
+		if r.URL.Path != route.prefix && !strings.HasPrefix(r.URL.Path, route.prefix+"/") {
+			continue
+		}
+
+		if route.write == "" {
+			return false
+		}
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			return auth.HasScope(route.read) || auth.HasScope(route.write)
+		}
+		return auth.HasScope(route.write)
+	}
+
+	return false
+}
+
+// CreateApiTokenHandler выпускает персональный токен для скриптов и интеграций. Токен есть только в этом ответе
+func (h *GatewayHandler) CreateApiTokenHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.CreateApiToken")
+	defer span.Finish()
+
+	request := new(models.CreateApiTokenDTO)
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[CreateApiTokenHandler] unmarshal: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	token, err := h.gatewayService.CreateApiToken(ctx, request)
+	if err != nil {
+		h.apiTokenErrorRespond(w, requestId, "[CreateApiTokenHandler] create token", err)
+		return
+	}
+
+	h.JSONSuccessRespond(w, token)
+}
+
+// GetApiTokensHandler возвращает действующие токены пользователя без самих токенов
+func (h *GatewayHandler) GetApiTokensHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.GetApiTokens")
+	defer span.Finish()
+
+	tokens, err := h.gatewayService.GetApiTokens(ctx)
+	if err != nil {
+		h.apiTokenErrorRespond(w, requestId, "[GetApiTokensHandler] get tokens", err)
+		return
+	}
+
+	h.JSONSuccessRespond(w, tokens)
+}
+
+func (h *GatewayHandler) RevokeApiTokenHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.RevokeApiToken")
+	defer span.Finish()
+
+	tokenID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[RevokeApiTokenHandler] parse id from url: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	err = h.gatewayService.RevokeApiToken(ctx, tokenID)
+	if err != nil {
+		h.apiTokenErrorRespond(w, requestId, "[RevokeApiTokenHandler] revoke token", err)
+		return
+	}
+
+	h.JSONSuccessRespond(w, nil)
+}
+
+// apiTokenErrorRespond отвечает клиенту на ошибку операции с персональным токеном
+func (h *GatewayHandler) apiTokenErrorRespond(w http.ResponseWriter, requestId, operation string, err error) {
+	for _, validationErr := range []error{
+		app_errors.ErrApiTokenNameInvalid,
+		app_errors.ErrUnknownApiTokenScope,
+		app_errors.ErrApiTokenTTLInvalid,
+		app_errors.ErrTooManyApiTokens,
+	} {
+		if errors.Is(err, validationErr) {
+			h.ErrorRequestValidation(w, validationErr.Error())
+			return
+		}
+	}
+
+	if errors.Is(err, app_errors.ErrNotFound) {
+		h.ErrorNotFound(w)
+		return
+	}
+
+	h.logger.Error().
+		Str("requestId", requestId).
+		Msgf("%s: %s", operation, err)
+	h.ErrorInternalApi(w)
+}
@@ -0,0 +1,50 @@
+package rest
+
+import (
+	"gateway/pkg/ctxutil"
+	"github.com/gorilla/mux"
+	"net/http"
+	"strconv"
+)
+
+// AccessRule решает, можно ли пользователю из контекста запроса обратиться к маршруту
+type AccessRule func(r *http.Request) bool
+
+// RequirePermission пускает пользователей, у роли которых есть разрешение
+func RequirePermission(permission string) AccessRule {
+	return func(r *http.Request) bool {
+		return ctxutil.HasPermission(r.Context(), permission)
+	}
+}
+
+// SelfOrPermission пускает пользователя к его собственной записи, чей ID указан в переменной маршрута idVar,
+// а к чужим записям - только при наличии разрешения
+func SelfOrPermission(idVar, permission string) AccessRule {
+	return func(r *http.Request) bool {
+		userID, ok := ctxutil.GetUserIDFromContext(r.Context())
+		if ok {
+			if targetID, err := strconv.Atoi(mux.Vars(r)[idVar]); err == nil && targetID == userID {
+				return true
+			}
+		}
+
+		return ctxutil.HasPermission(r.Context(), permission)
+	}
+}
+
+// Authorize проверяет правило доступа до вызова хэндлера и отвечает 403, если доступа нет
+func (h *GatewayHandler) Authorize(rule AccessRule, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !rule(r) {
+			requestId, _ := ctxutil.GetRequestIDFromContext(r.Context())
+			userID, _ := ctxutil.GetUserIDFromContext(r.Context())
+			h.logger.Warn().
+				Str("requestId", requestId).
+				Msgf("[Authorize] user %d has no access to %s %s", userID, r.Method, r.URL.Path)
+			h.ErrorForbidden(w)
+			return
+		}
+
+		next(w, r)
+	}
+}
@@ -8,11 +8,20 @@ const (
 	ErrCodeBadRequest             ErrorCode = "BAD_REQUEST"
 	ErrCodeNotFound               ErrorCode = "NOT_FOUND"
 	ErrCodeInvalidJsonFormat      ErrorCode = "JSON_FORMAT_ERROR"
+	ErrCodeInvalidQuery           ErrorCode = "INVALID_QUERY"
+	ErrCodeForbidden              ErrorCode = "FORBIDDEN"
+	ErrCodeAlreadyExists          ErrorCode = "ALREADY_EXISTS"
+	ErrCodeEmailNotVerified       ErrorCode = "EMAIL_NOT_VERIFIED"
+	ErrCodeTooManyRequests        ErrorCode = "TOO_MANY_REQUESTS"
+	ErrCodeUnauthorized           ErrorCode = "UNAUTHORIZED"
+	ErrCodeAccountLocked          ErrorCode = "ACCOUNT_LOCKED"
 )
 
 type ApiError struct {
 	Message string
 	ErrCode ErrorCode
+	// Details - дополнительные сведения об ошибке, которые отдаются клиенту как есть
+	Details interface{}
 }
 
 func NewApiError(message string, errCode ErrorCode) *ApiError {
@@ -22,6 +31,15 @@ func NewApiError(message string, errCode ErrorCode) *ApiError {
 	}
 }
 
+// WithDetails возвращает копию ошибки с дополнительными сведениями для клиента
+func (e *ApiError) WithDetails(details interface{}) *ApiError {
+	return &ApiError{
+		Message: e.Message,
+		ErrCode: e.ErrCode,
+		Details: details,
+	}
+}
+
 func (e *ApiError) Error() string {
 	return e.Message
 }
@@ -38,11 +56,36 @@ func (e *ApiError) As(target interface{}) bool {
 }
 
 var (
-	ErrInternalApi                = NewApiError("an error occurred while processing the request", ErrCodeInternalAPIError)
-	ErrBadRequest                 = NewApiError("bad request", ErrCodeBadRequest)
-	ErrUsernameOrEmailAlreadyUsed = NewApiError("username or email already used", ErrCodeBadRequest)
-	ErrWrongCredentials           = NewApiError("wrong credentials", ErrCodeBadRequest)
-	ErrNotFound                   = NewApiError("not found", ErrCodeNotFound)
+	ErrInternalApi                     = NewApiError("an error occurred while processing the request", ErrCodeInternalAPIError)
+	ErrBadRequest                      = NewApiError("bad request", ErrCodeBadRequest)
+	ErrUsernameOrEmailAlreadyUsed      = NewApiError("username or email already used", ErrCodeBadRequest)
+	ErrWrongCredentials                = NewApiError("wrong credentials", ErrCodeBadRequest)
+	ErrNotFound                        = NewApiError("not found", ErrCodeNotFound)
+	ErrForbidden                       = NewApiError("forbidden", ErrCodeForbidden)
+	ErrEmailNotVerified                = NewApiError("email is not verified", ErrCodeEmailNotVerified)
+	ErrInvalidVerificationToken        = NewApiError("verification token is invalid or expired", ErrCodeBadRequest)
+	ErrInvalidPasswordResetToken       = NewApiError("password reset token is invalid or expired", ErrCodeBadRequest)
+	ErrPassAndConfirmationDoesNotMatch = NewApiError("password and confirmation does not match", ErrCodeBadRequest)
+	ErrIncorrectOldPassword            = NewApiError("incorrect old password", ErrCodeBadRequest)
+	ErrInvalidRefreshToken             = NewApiError("invalid or expired refresh token", ErrCodeUnauthorized)
+	ErrTwoFactorNotEnabled             = NewApiError("two-factor authentication is not enabled", ErrCodeBadRequest)
+	ErrTwoFactorAlreadyEnabled         = NewApiError("two-factor authentication is already enabled", ErrCodeAlreadyExists)
+	ErrInvalidTwoFactorCode            = NewApiError("invalid two-factor code", ErrCodeBadRequest)
+	ErrInvalidTwoFactorChallenge       = NewApiError("two-factor challenge is invalid or expired, log in again", ErrCodeUnauthorized)
+	ErrTwoFactorRequiredByRole         = NewApiError("two-factor authentication is required for your role and cannot be disabled", ErrCodeForbidden)
+	ErrLoginThrottled                  = NewApiError("too many failed login attempts, try again later", ErrCodeTooManyRequests)
+	ErrAccountLocked                   = NewApiError("account is temporarily locked after too many failed login attempts", ErrCodeAccountLocked)
+	ErrExternalLoginFailed             = NewApiError("external login failed, try again", ErrCodeUnauthorized)
+	ErrExternalEmailNotVerified        = NewApiError("identity provider did not confirm the email", ErrCodeEmailNotVerified)
+	ErrExternalEmailConflict           = NewApiError("email is used by an account that is not verified, verify it or log in with password", ErrCodeAlreadyExists)
+	ErrAvatarTooLarge                  = NewApiError("avatar image is too large", ErrCodeRequestValidationError)
+	ErrEmailChangeThrottled            = NewApiError("email change was requested recently, try again later", ErrCodeTooManyRequests)
+	ErrInvalidEmailChangeToken         = NewApiError("email change token is invalid or expired", ErrCodeBadRequest)
+	ErrUserDeletionInProgress          = NewApiError("user deletion is already in progress", ErrCodeAlreadyExists)
+	ErrDataExportThrottled             = NewApiError("data export was requested recently, try again later", ErrCodeTooManyRequests)
+	ErrInvalidDataExportToken          = NewApiError("data export link is invalid or expired", ErrCodeBadRequest)
+	ErrErasureThrottled                = NewApiError("account deletion was requested recently, try again later", ErrCodeTooManyRequests)
+	ErrInvalidErasureToken             = NewApiError("account deletion token is invalid or expired", ErrCodeBadRequest)
 )
 
 func (e *ApiError) IsRequestValidationError() bool {
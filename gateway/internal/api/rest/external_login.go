@@ -0,0 +1,136 @@
+package rest
+
+import (
+	"errors"
+	appErrors "gateway/internal/app_errors"
+	"gateway/internal/models"
+	"gateway/pkg/ctxutil"
+	"github.com/opentracing/opentracing-go"
+	"net/http"
+	"strings"
+)
+
+const (
+	// oidcPath - префикс маршрутов входа через провайдера, исключенный из проверки токена пользователя
+	oidcPath = "/api/v1/users/oidc"
+	// oidcFlowCookie - cookie с подписанным состоянием входа, живет только между редиректами
+	oidcFlowCookie = "oidc_flow"
+)
+
+// ExternalLoginHandler отправляет пользователя на страницу входа провайдера OpenID Connect.
+// State, nonce и PKCE verifier запоминаются в cookie этого браузера до возврата от провайдера
+func (h *GatewayHandler) ExternalLoginHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.ExternalLoginHandler")
+	defer span.Finish()
+
+	start, err := h.gatewayService.StartExternalLogin(ctx)
+	if err != nil {
+		if h.respondExternalLoginError(w, err) {
+			h.logger.Warn().
+				Str("requestId", requestId).
+				Msgf("[ExternalLoginHandler] start: %s", err)
+			return
+		}
+
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[ExternalLoginHandler] start: %s", err)
+		h.ErrorInternalApi(w)
+		return
+	}
+
+	http.SetCookie(w, h.oidcFlowCookie(start.FlowToken, 0))
+	http.Redirect(w, r, start.AuthURL, http.StatusFound)
+}
+
+// ExternalLoginCallbackHandler принимает пользователя, вернувшегося от провайдера, и выдает пару токенов
+// так же, как /login. Если у пользователя включен второй фактор, вместо токенов возвращается challenge_token
+func (h *GatewayHandler) ExternalLoginCallbackHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.ExternalLoginCallbackHandler")
+	defer span.Finish()
+
+	// состояние входа одноразовое, cookie удаляется при любом исходе
+	cookie, cookieErr := r.Cookie(oidcFlowCookie)
+	http.SetCookie(w, h.oidcFlowCookie("", -1))
+
+	query := r.URL.Query()
+	if providerErr := query.Get("error"); providerErr != "" {
+		h.logger.Warn().
+			Str("requestId", requestId).
+			Msgf("[ExternalLoginCallbackHandler] provider error: %s %s", providerErr, query.Get("error_description"))
+		h.ErrorExternalLoginFailed(w)
+		return
+	}
+
+	if cookieErr != nil || query.Get("code") == "" || query.Get("state") == "" {
+		h.logger.Warn().
+			Str("requestId", requestId).
+			Msg("[ExternalLoginCallbackHandler] no flow cookie, code or state")
+		h.ErrorExternalLoginFailed(w)
+		return
+	}
+
+	response, err := h.gatewayService.CompleteExternalLogin(ctx, &models.ExternalLoginCallbackDTO{
+		Code:      query.Get("code"),
+		State:     query.Get("state"),
+		FlowToken: cookie.Value,
+	})
+	if err != nil {
+		if h.respondExternalLoginError(w, err) {
+			h.logger.Warn().
+				Str("requestId", requestId).
+				Msgf("[ExternalLoginCallbackHandler] login: %s", err)
+			return
+		}
+		if errors.Is(err, appErrors.ErrLoginThrottled) || errors.Is(err, appErrors.ErrAccountLocked) {
+			h.ErrorLoginThrottled(w, err)
+			return
+		}
+
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[ExternalLoginCallbackHandler] login: %s", err)
+		h.ErrorInternalApi(w)
+		return
+	}
+
+	h.JSONSuccessRespond(w, response)
+}
+
+// respondExternalLoginError отвечает на ошибки входа через провайдера, которые показываются пользователю
+func (h *GatewayHandler) respondExternalLoginError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, appErrors.ErrExternalLoginDisabled):
+		h.ErrorNotFound(w)
+	case errors.Is(err, appErrors.ErrExternalLoginFailed):
+		h.ErrorExternalLoginFailed(w)
+	case errors.Is(err, appErrors.ErrExternalEmailNotVerified):
+		h.ErrorExternalEmailNotVerified(w)
+	case errors.Is(err, appErrors.ErrExternalEmailConflict):
+		h.ErrorExternalEmailConflict(w)
+	// одновременный первый вход с тем же email упирается в уникальный индекс, повторный вход пройдет
+	case errors.Is(err, appErrors.ErrUsernameOrEmailIsUsed):
+		h.ErrorUsernameOrEmailAlreadyUsed(w)
+	default:
+		return false
+	}
+
+	return true
+}
+
+func (h *GatewayHandler) oidcFlowCookie(value string, maxAge int) *http.Cookie {
+	return &http.Cookie{
+		Name:   oidcFlowCookie,
+		Value:  value,
+		Path:   oidcPath,
+		MaxAge: maxAge,
+		// Lax, чтобы cookie пришла с редиректом от провайдера, но не с чужими POST запросами
+		SameSite: http.SameSiteLaxMode,
+		HttpOnly: true,
+		Secure:   strings.HasPrefix(h.publicURL, "https://"),
+	}
+}
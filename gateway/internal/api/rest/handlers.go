@@ -3,19 +3,23 @@ package rest
 import (
 	rest "gateway/internal/api"
 	"github.com/rs/zerolog"
+	"strings"
 )
 
 type GatewayHandler struct {
 	logger         *zerolog.Logger
 	gatewayService rest.GatewayService
+	publicURL      string
 }
 
 func NewGatewayHandler(
 	logger *zerolog.Logger,
 	gatewayService rest.GatewayService,
+	publicURL string,
 ) *GatewayHandler {
 	return &GatewayHandler{
 		logger:         logger,
 		gatewayService: gatewayService,
+		publicURL:      strings.TrimRight(publicURL, "/"),
 	}
 }
@@ -1,14 +1,24 @@
 package rest
 
 import (
+	"context"
+	"errors"
+	"gateway/internal/app_errors"
+	"gateway/internal/models"
 	"gateway/pkg/ctxutil"
 	"gateway/pkg/jwtutil"
 	"github.com/google/uuid"
+	"net"
 	"net/http"
 	"strings"
 )
 
-func ValidateTokenMiddleware(jwtUtil *jwtutil.JWTUtil, excludedPaths []string) func(http.Handler) http.Handler {
+// ApiTokenAuthenticator проверяет персональные токены, которые предъявляются вместо access токена
+type ApiTokenAuthenticator interface {
+	AuthenticateApiToken(ctx context.Context, token string) (*models.ApiTokenAuthDTO, error)
+}
+
+func ValidateTokenMiddleware(jwtUtil *jwtutil.JWTUtil, apiTokens ApiTokenAuthenticator, excludedPaths []string) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			// Generate a UUID for each request
@@ -31,17 +41,80 @@ func ValidateTokenMiddleware(jwtUtil *jwtutil.JWTUtil, excludedPaths []string) f
 
 			// Validate the token
 			token := strings.TrimPrefix(authHeader, "Bearer ")
-			userID, err := jwtUtil.VerifyToken(token)
+
+			// Personal access tokens are checked by the users service and limited by their scopes
+			if strings.HasPrefix(token, models.ApiTokenPrefix) {
+				auth, err := apiTokens.AuthenticateApiToken(ctx, token)
+				if err != nil {
+					if errors.Is(err, app_errors.ErrInvalidApiToken) {
+						http.Error(w, "Invalid or expired token", http.StatusUnauthorized)
+						return
+					}
+					http.Error(w, "Could not validate token", http.StatusInternalServerError)
+					return
+				}
+
+				if !apiTokenAllows(auth, r) {
+					http.Error(w, "Token scopes do not allow this request", http.StatusForbidden)
+					return
+				}
+
+				ctx = ctxutil.SetUserIDToContext(ctx, auth.UserID)
+				ctx = ctxutil.SetPermissionsToContext(ctx, auth.Permissions)
+				ctx = ctxutil.SetTimezoneToContext(ctx, auth.Timezone)
+
+				next.ServeHTTP(w, r.WithContext(ctx))
+				return
+			}
+
+			claims, err := jwtUtil.VerifyAccessToken(token)
 			if err != nil {
 				http.Error(w, "Invalid or expired token", http.StatusUnauthorized)
 				return
 			}
 
-			// Add the user ID to the context
-			ctx = ctxutil.SetUserIDToContext(ctx, userID)
+			// Add the user ID, the session ID, the permissions, the memberships and the timezone to the context
+			ctx = ctxutil.SetUserIDToContext(ctx, claims.UserID)
+			ctx = ctxutil.SetSessionIDToContext(ctx, claims.SessionID)
+			ctx = ctxutil.SetPermissionsToContext(ctx, claims.Permissions)
+			ctx = ctxutil.SetOrganizationIDsToContext(ctx, claims.OrganizationIDs)
+			ctx = ctxutil.SetTeamIDsToContext(ctx, claims.TeamIDs)
+			ctx = ctxutil.SetTimezoneToContext(ctx, claims.Timezone)
 
 			// Token is valid, proceed with the request
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
+
+// ClientIPMiddleware кладет в контекст адрес клиента, по которому users ограничивает попытки входа
+func ClientIPMiddleware(trustProxyHeaders bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := ctxutil.SetClientIPToContext(r.Context(), clientIP(r, trustProxyHeaders))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func clientIP(r *http.Request, trustProxyHeaders bool) string {
+	if trustProxyHeaders {
+		// первый адрес в X-Forwarded-For - исходный клиент, остальные добавлены прокси по пути
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			first, _, _ := strings.Cut(forwarded, ",")
+			if ip := strings.TrimSpace(first); ip != "" {
+				return ip
+			}
+		}
+		if realIP := strings.TrimSpace(r.Header.Get("X-Real-IP")); realIP != "" {
+			return realIP
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
@@ -0,0 +1,74 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"gateway/internal/app_errors"
+	"gateway/internal/models"
+	"gateway/pkg/ctxutil"
+	"github.com/opentracing/opentracing-go"
+	"net/http"
+)
+
+// GetNotificationPreferences возвращает каналы уведомлений пользователя по всем событиям
+func (h *GatewayHandler) GetNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.GetNotificationPreferences")
+	defer span.Finish()
+
+	preferences, err := h.gatewayService.GetNotificationPreferences(ctx)
+	if err != nil {
+		h.notificationPreferencesErrorRespond(w, requestId, "[GetNotificationPreferences] get", err)
+		return
+	}
+
+	h.JSONSuccessRespond(w, preferences)
+}
+
+// UpdateNotificationPreferences меняет каналы уведомлений для событий из запроса
+func (h *GatewayHandler) UpdateNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.UpdateNotificationPreferences")
+	defer span.Finish()
+
+	request := new(models.NotificationPreferencesDTO)
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[UpdateNotificationPreferences] unmarshal: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	preferences, err := h.gatewayService.UpdateNotificationPreferences(ctx, request)
+	if err != nil {
+		h.notificationPreferencesErrorRespond(w, requestId, "[UpdateNotificationPreferences] update", err)
+		return
+	}
+
+	h.JSONSuccessRespond(w, preferences)
+}
+
+// notificationPreferencesErrorRespond отвечает клиенту на ошибку чтения или изменения настроек уведомлений
+func (h *GatewayHandler) notificationPreferencesErrorRespond(w http.ResponseWriter, requestId, operation string, err error) {
+	switch {
+	case errors.Is(err, app_errors.ErrUnknownNotificationEvent):
+		h.ErrorRequestValidation(w, app_errors.ErrUnknownNotificationEvent.Error())
+		return
+	case errors.Is(err, app_errors.ErrUnknownNotificationChannel):
+		h.ErrorRequestValidation(w, app_errors.ErrUnknownNotificationChannel.Error())
+		return
+	case errors.Is(err, app_errors.ErrNotFound):
+		h.ErrorNotFound(w)
+		return
+	}
+
+	h.logger.Error().
+		Str("requestId", requestId).
+		Msgf("%s: %s", operation, err)
+	h.ErrorInternalApi(w)
+}
@@ -0,0 +1,374 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"gateway/internal/app_errors"
+	"gateway/internal/models"
+	"gateway/pkg/ctxutil"
+	"github.com/gorilla/mux"
+	"github.com/opentracing/opentracing-go"
+	"net/http"
+	"strconv"
+)
+
+// CreateOrganizationHandler создает организацию, текущий пользователь становится ее владельцем
+func (h *GatewayHandler) CreateOrganizationHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.CreateOrganization")
+	defer span.Finish()
+
+	request := new(models.CreateOrganizationDTO)
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[CreateOrganizationHandler] unmarshal: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	organization, err := h.gatewayService.CreateOrganization(ctx, request)
+	if err != nil {
+		h.organizationErrorRespond(w, requestId, "[CreateOrganizationHandler] create organization", err)
+		return
+	}
+
+	h.JSONSuccessRespond(w, organization)
+}
+
+// GetOrganizationsHandler возвращает организации текущего пользователя
+func (h *GatewayHandler) GetOrganizationsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.GetOrganizations")
+	defer span.Finish()
+
+	organizations, err := h.gatewayService.GetOrganizations(ctx)
+	if err != nil {
+		h.organizationErrorRespond(w, requestId, "[GetOrganizationsHandler] get organizations", err)
+		return
+	}
+
+	h.JSONSuccessRespond(w, organizations)
+}
+
+func (h *GatewayHandler) ListOrganizationMembersHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.ListOrganizationMembers")
+	defer span.Finish()
+
+	ids, ok := h.pathIDs(w, r, requestId, "[ListOrganizationMembersHandler]", "id")
+	if !ok {
+		return
+	}
+
+	members, err := h.gatewayService.ListOrganizationMembers(ctx, ids[0])
+	if err != nil {
+		h.organizationErrorRespond(w, requestId, "[ListOrganizationMembersHandler] get members", err)
+		return
+	}
+
+	h.JSONSuccessRespond(w, members)
+}
+
+// InviteMemberHandler отправляет приглашение в организацию на email
+func (h *GatewayHandler) InviteMemberHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.InviteMember")
+	defer span.Finish()
+
+	ids, ok := h.pathIDs(w, r, requestId, "[InviteMemberHandler]", "id")
+	if !ok {
+		return
+	}
+
+	request := new(models.InviteMemberDTO)
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[InviteMemberHandler] unmarshal: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	invitation, err := h.gatewayService.InviteMember(ctx, ids[0], request)
+	if err != nil {
+		h.organizationErrorRespond(w, requestId, "[InviteMemberHandler] invite member", err)
+		return
+	}
+
+	h.JSONSuccessRespond(w, invitation)
+}
+
+// AcceptInvitationHandler принимает приглашение по токену из ссылки в письме. Принять его может
+// только пользователь с тем же email, на который ушло приглашение
+func (h *GatewayHandler) AcceptInvitationHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.AcceptInvitation")
+	defer span.Finish()
+
+	request := new(models.AcceptInvitationDTO)
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[AcceptInvitationHandler] unmarshal: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	organization, err := h.gatewayService.AcceptInvitation(ctx, request)
+	if err != nil {
+		h.organizationErrorRespond(w, requestId, "[AcceptInvitationHandler] accept invitation", err)
+		return
+	}
+
+	h.JSONSuccessRespond(w, organization)
+}
+
+func (h *GatewayHandler) SetMemberRoleHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.SetMemberRole")
+	defer span.Finish()
+
+	ids, ok := h.pathIDs(w, r, requestId, "[SetMemberRoleHandler]", "id", "memberId")
+	if !ok {
+		return
+	}
+
+	request := new(models.SetMemberRoleDTO)
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[SetMemberRoleHandler] unmarshal: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	err := h.gatewayService.SetMemberRole(ctx, ids[0], ids[1], request)
+	if err != nil {
+		h.organizationErrorRespond(w, requestId, "[SetMemberRoleHandler] set role", err)
+		return
+	}
+
+	h.JSONSuccessRespond(w, nil)
+}
+
+// RemoveMemberHandler исключает участника из организации. Свой id в пути означает выход из организации
+func (h *GatewayHandler) RemoveMemberHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.RemoveMember")
+	defer span.Finish()
+
+	ids, ok := h.pathIDs(w, r, requestId, "[RemoveMemberHandler]", "id", "memberId")
+	if !ok {
+		return
+	}
+
+	err := h.gatewayService.RemoveMember(ctx, ids[0], ids[1])
+	if err != nil {
+		h.organizationErrorRespond(w, requestId, "[RemoveMemberHandler] remove member", err)
+		return
+	}
+
+	h.JSONSuccessRespond(w, nil)
+}
+
+func (h *GatewayHandler) CreateTeamHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.CreateTeam")
+	defer span.Finish()
+
+	ids, ok := h.pathIDs(w, r, requestId, "[CreateTeamHandler]", "id")
+	if !ok {
+		return
+	}
+
+	request := new(models.CreateTeamDTO)
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[CreateTeamHandler] unmarshal: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	team, err := h.gatewayService.CreateTeam(ctx, ids[0], request)
+	if err != nil {
+		h.organizationErrorRespond(w, requestId, "[CreateTeamHandler] create team", err)
+		return
+	}
+
+	h.JSONSuccessRespond(w, team)
+}
+
+func (h *GatewayHandler) GetTeamsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.GetTeams")
+	defer span.Finish()
+
+	ids, ok := h.pathIDs(w, r, requestId, "[GetTeamsHandler]", "id")
+	if !ok {
+		return
+	}
+
+	teams, err := h.gatewayService.GetTeams(ctx, ids[0])
+	if err != nil {
+		h.organizationErrorRespond(w, requestId, "[GetTeamsHandler] get teams", err)
+		return
+	}
+
+	h.JSONSuccessRespond(w, teams)
+}
+
+func (h *GatewayHandler) AddTeamMemberHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.AddTeamMember")
+	defer span.Finish()
+
+	ids, ok := h.pathIDs(w, r, requestId, "[AddTeamMemberHandler]", "id", "teamId")
+	if !ok {
+		return
+	}
+
+	request := new(models.TeamMemberDTO)
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[AddTeamMemberHandler] unmarshal: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	err := h.gatewayService.AddTeamMember(ctx, ids[0], ids[1], request)
+	if err != nil {
+		h.organizationErrorRespond(w, requestId, "[AddTeamMemberHandler] add member", err)
+		return
+	}
+
+	h.JSONSuccessRespond(w, nil)
+}
+
+func (h *GatewayHandler) RemoveTeamMemberHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.RemoveTeamMember")
+	defer span.Finish()
+
+	ids, ok := h.pathIDs(w, r, requestId, "[RemoveTeamMemberHandler]", "id", "teamId", "memberId")
+	if !ok {
+		return
+	}
+
+	err := h.gatewayService.RemoveTeamMember(ctx, ids[0], ids[1], ids[2])
+	if err != nil {
+		h.organizationErrorRespond(w, requestId, "[RemoveTeamMemberHandler] remove member", err)
+		return
+	}
+
+	h.JSONSuccessRespond(w, nil)
+}
+
+func (h *GatewayHandler) ListTeamMembersHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.ListTeamMembers")
+	defer span.Finish()
+
+	ids, ok := h.pathIDs(w, r, requestId, "[ListTeamMembersHandler]", "id", "teamId")
+	if !ok {
+		return
+	}
+
+	members, err := h.gatewayService.ListTeamMembers(ctx, ids[0], ids[1])
+	if err != nil {
+		h.organizationErrorRespond(w, requestId, "[ListTeamMembersHandler] get members", err)
+		return
+	}
+
+	h.JSONSuccessRespond(w, members)
+}
+
+// pathIDs читает числовые параметры пути в порядке names. При ошибке клиенту уже отправлен ответ
+func (h *GatewayHandler) pathIDs(w http.ResponseWriter, r *http.Request, requestId, operation string, names ...string) ([]int, bool) {
+	vars := mux.Vars(r)
+
+	ids := make([]int, len(names))
+	for i, name := range names {
+		id, err := strconv.Atoi(vars[name])
+		if err != nil {
+			h.logger.Error().
+				Str("requestId", requestId).
+				Msgf("%s parse %s from url: %s", operation, name, err)
+			h.ErrorBadRequest(w)
+			return nil, false
+		}
+		ids[i] = id
+	}
+
+	return ids, true
+}
+
+// organizationErrorRespond отвечает клиенту на ошибку операции с организацией или командой
+func (h *GatewayHandler) organizationErrorRespond(w http.ResponseWriter, requestId, operation string, err error) {
+	for _, validationErr := range []error{
+		app_errors.ErrOrganizationNameInvalid,
+		app_errors.ErrUnknownOrganizationRole,
+		app_errors.ErrLastOrganizationOwner,
+		app_errors.ErrInvalidEmail,
+		app_errors.ErrInvalidInvitation,
+		app_errors.ErrTeamNameInvalid,
+	} {
+		if errors.Is(err, validationErr) {
+			h.ErrorRequestValidation(w, validationErr.Error())
+			return
+		}
+	}
+
+	for _, conflictErr := range []error{
+		app_errors.ErrAlreadyMember,
+		app_errors.ErrTeamNameIsUsed,
+	} {
+		if errors.Is(err, conflictErr) {
+			h.ErrorAlreadyExists(w, conflictErr.Error())
+			return
+		}
+	}
+
+	switch {
+	case errors.Is(err, app_errors.ErrInvitationEmailMismatch):
+		h.JSONErrorRespond(w, http.StatusForbidden, NewApiError(app_errors.ErrInvitationEmailMismatch.Error(), ErrCodeForbidden))
+		return
+	case errors.Is(err, app_errors.ErrForbidden):
+		h.ErrorForbidden(w)
+		return
+	case errors.Is(err, app_errors.ErrNotFound):
+		h.ErrorNotFound(w)
+		return
+	}
+
+	h.logger.Error().
+		Str("requestId", requestId).
+		Msgf("%s: %s", operation, err)
+	h.ErrorInternalApi(w)
+}
@@ -0,0 +1,186 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	appErrors "gateway/internal/app_errors"
+	"gateway/internal/models"
+	"gateway/pkg/ctxutil"
+	"github.com/opentracing/opentracing-go"
+	"net/http"
+	"strconv"
+)
+
+// RequestDataExport начинает выгрузку данных пользователя из токена: профиля и задач, которые он создал
+// или исполняет. Архив собирается асинхронно, ссылка на него уходит на email
+func (h *GatewayHandler) RequestDataExport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.RequestDataExport")
+	defer span.Finish()
+
+	export, err := h.gatewayService.RequestDataExport(ctx)
+	if err != nil {
+		switch {
+		case errors.Is(err, appErrors.ErrDataExportThrottled):
+			h.ErrorDataExportThrottled(w)
+			return
+		case errors.Is(err, appErrors.ErrNotFound):
+			h.ErrorNotFound(w)
+			return
+		}
+
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[RequestDataExport] request export: %s", err)
+		h.ErrorInternalApi(w)
+		return
+	}
+
+	h.JSONSuccessRespond(w, export)
+}
+
+// GetDataExport возвращает состояние последней выгрузки данных пользователя из токена
+func (h *GatewayHandler) GetDataExport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.GetDataExport")
+	defer span.Finish()
+
+	export, err := h.gatewayService.GetDataExport(ctx)
+	if err != nil {
+		if errors.Is(err, appErrors.ErrNotFound) {
+			h.ErrorNotFound(w)
+			return
+		}
+
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[GetDataExport] get export: %s", err)
+		h.ErrorInternalApi(w)
+		return
+	}
+
+	h.JSONSuccessRespond(w, export)
+}
+
+// DownloadDataExport отдает zip архив выгрузки. Ссылка из письма открывается GET запросом с токеном
+// в параметре token, поэтому маршрут доступен без токена пользователя
+func (h *GatewayHandler) DownloadDataExport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.DownloadDataExport")
+	defer span.Finish()
+
+	archive, err := h.gatewayService.DownloadDataExport(ctx, r.URL.Query().Get("token"))
+	if err != nil {
+		if errors.Is(err, appErrors.ErrInvalidDataExportToken) {
+			h.ErrorInvalidDataExportToken(w)
+			return
+		}
+
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[DownloadDataExport] download: %s", err)
+		h.ErrorInternalApi(w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", archive.FileName))
+	w.Header().Set("Content-Length", strconv.Itoa(len(archive.Content)))
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := w.Write(archive.Content); err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[DownloadDataExport] write response: %s", err)
+	}
+}
+
+// RequestErasure отправляет на email пользователя из токена ссылку для подтверждения удаления аккаунта.
+// Аккаунт удаляется только после перехода по ссылке
+func (h *GatewayHandler) RequestErasure(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.RequestErasure")
+	defer span.Finish()
+
+	var request = new(models.RequestErasureDTO)
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[RequestErasure] unmarshall: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	// передаем данные слою бизнес-логики
+	err := h.gatewayService.RequestErasure(ctx, request)
+	if err != nil {
+		switch {
+		case errors.Is(err, appErrors.ErrWrongCredentials):
+			h.ErrorWrongCredentials(w)
+			return
+		case errors.Is(err, appErrors.ErrErasureThrottled):
+			h.ErrorErasureThrottled(w)
+			return
+		case errors.Is(err, appErrors.ErrNotFound):
+			h.ErrorNotFound(w)
+			return
+		}
+
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[RequestErasure] request erasure: %s", err)
+		h.ErrorInternalApi(w)
+		return
+	}
+
+	// возвращаем пользователю ответ - в данном случе просто status 200
+	h.JSONSuccessRespond(w, nil)
+}
+
+// ConfirmErasure подтверждает удаление аккаунта по токену из письма и возвращает состояние удаления.
+// Ссылка из письма открывается GET запросом с токеном в параметре token, клиенты приложения могут
+// передать токен в теле POST запроса
+func (h *GatewayHandler) ConfirmErasure(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.ConfirmErasure")
+	defer span.Finish()
+
+	var request = new(models.ConfirmErasureDTO)
+	if r.Method == http.MethodGet {
+		request.Token = r.URL.Query().Get("token")
+	} else if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[ConfirmErasure] unmarshall: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	// передаем данные слою бизнес-логики
+	deletion, err := h.gatewayService.ConfirmErasure(ctx, request.Token)
+	if err != nil {
+		switch {
+		case errors.Is(err, appErrors.ErrInvalidErasureToken):
+			h.ErrorInvalidErasureToken(w)
+			return
+		case errors.Is(err, appErrors.ErrUserDeletionInProgress):
+			h.ErrorUserDeletionInProgress(w)
+			return
+		}
+
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[ConfirmErasure] confirm: %s", err)
+		h.ErrorInternalApi(w)
+		return
+	}
+
+	h.JSONSuccessRespond(w, deletion)
+}
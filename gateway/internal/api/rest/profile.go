@@ -0,0 +1,109 @@
+package rest
+
+import (
+	"errors"
+	"gateway/internal/app_errors"
+	"gateway/pkg/ctxutil"
+	"github.com/opentracing/opentracing-go"
+	"io"
+	"net/http"
+)
+
+// maxAvatarUploadBytes - предельный размер запроса с аватаром. Точный предел для изображения задает сервис users,
+// а сообщение gRPC не может быть больше 4 МБ
+const maxAvatarUploadBytes = 4 << 20
+
+// avatarFormField - поле формы multipart/form-data с файлом аватара
+const avatarFormField = "avatar"
+
+// UploadAvatar заменяет аватар пользователя из токена. Файл передается полем avatar формы multipart/form-data
+func (h *GatewayHandler) UploadAvatar(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.UploadAvatar")
+	defer span.Finish()
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxAvatarUploadBytes)
+	file, _, err := r.FormFile(avatarFormField)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			h.ErrorAvatarTooLarge(w)
+			return
+		}
+
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[UploadAvatar] read form: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+	defer file.Close()
+
+	image, err := io.ReadAll(file)
+	if err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[UploadAvatar] read file: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	response, err := h.gatewayService.UploadAvatar(ctx, image)
+	if err != nil {
+		h.profileErrorRespond(w, requestId, "[UploadAvatar] upload", err)
+		return
+	}
+
+	h.JSONSuccessRespond(w, response)
+}
+
+// DeleteAvatar удаляет аватар пользователя из токена
+func (h *GatewayHandler) DeleteAvatar(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.DeleteAvatar")
+	defer span.Finish()
+
+	response, err := h.gatewayService.DeleteAvatar(ctx)
+	if err != nil {
+		h.profileErrorRespond(w, requestId, "[DeleteAvatar] delete", err)
+		return
+	}
+
+	h.JSONSuccessRespond(w, response)
+}
+
+// profileErrorRespond отвечает клиенту на ошибку изменения профиля
+func (h *GatewayHandler) profileErrorRespond(w http.ResponseWriter, requestId, operation string, err error) {
+	for _, validationErr := range []error{
+		app_errors.ErrUsernameInvalid,
+		app_errors.ErrDisplayNameInvalid,
+		app_errors.ErrUnknownTimezone,
+		app_errors.ErrUnknownLocale,
+		app_errors.ErrInvalidAvatar,
+		app_errors.ErrEmailChangeRequired,
+	} {
+		if errors.Is(err, validationErr) {
+			h.ErrorRequestValidation(w, validationErr.Error())
+			return
+		}
+	}
+
+	switch {
+	case errors.Is(err, app_errors.ErrAvatarTooLarge):
+		h.ErrorAvatarTooLarge(w)
+		return
+	case errors.Is(err, app_errors.ErrUsernameOrEmailIsUsed):
+		h.ErrorUsernameOrEmailAlreadyUsed(w)
+		return
+	case errors.Is(err, app_errors.ErrNotFound):
+		h.ErrorNotFound(w)
+		return
+	}
+
+	h.logger.Error().
+		Str("requestId", requestId).
+		Msgf("%s: %s", operation, err)
+	h.ErrorInternalApi(w)
+}
@@ -2,12 +2,17 @@ package rest
 
 import (
 	"encoding/json"
+	"errors"
+	"gateway/internal/app_errors"
+	"math"
 	"net/http"
+	"strconv"
 )
 
 type ErrorResponse struct {
-	ErrorMessage string `json:"errorMessage"`
-	ErrorCode    string `json:"errorCode"`
+	ErrorMessage string      `json:"errorMessage"`
+	ErrorCode    string      `json:"errorCode"`
+	Details      interface{} `json:"details,omitempty"`
 }
 
 func (h *GatewayHandler) ErrorBadRequest(w http.ResponseWriter) {
@@ -26,10 +31,151 @@ func (h *GatewayHandler) ErrorNotFound(w http.ResponseWriter) {
 	h.JSONErrorRespond(w, http.StatusNotFound, ErrNotFound)
 }
 
+func (h *GatewayHandler) ErrorForbidden(w http.ResponseWriter) {
+	h.JSONErrorRespond(w, http.StatusForbidden, ErrForbidden)
+}
+
+func (h *GatewayHandler) ErrorEmailNotVerified(w http.ResponseWriter) {
+	h.JSONErrorRespond(w, http.StatusForbidden, ErrEmailNotVerified)
+}
+
+func (h *GatewayHandler) ErrorInvalidVerificationToken(w http.ResponseWriter) {
+	h.JSONErrorRespond(w, http.StatusBadRequest, ErrInvalidVerificationToken)
+}
+
+func (h *GatewayHandler) ErrorInvalidPasswordResetToken(w http.ResponseWriter) {
+	h.JSONErrorRespond(w, http.StatusBadRequest, ErrInvalidPasswordResetToken)
+}
+
+func (h *GatewayHandler) ErrorPassAndConfirmationDoesNotMatch(w http.ResponseWriter) {
+	h.JSONErrorRespond(w, http.StatusBadRequest, ErrPassAndConfirmationDoesNotMatch)
+}
+
+func (h *GatewayHandler) ErrorIncorrectOldPassword(w http.ResponseWriter) {
+	h.JSONErrorRespond(w, http.StatusBadRequest, ErrIncorrectOldPassword)
+}
+
+func (h *GatewayHandler) ErrorInvalidRefreshToken(w http.ResponseWriter) {
+	h.JSONErrorRespond(w, http.StatusUnauthorized, ErrInvalidRefreshToken)
+}
+
+func (h *GatewayHandler) ErrorTwoFactorNotEnabled(w http.ResponseWriter) {
+	h.JSONErrorRespond(w, http.StatusBadRequest, ErrTwoFactorNotEnabled)
+}
+
+func (h *GatewayHandler) ErrorTwoFactorAlreadyEnabled(w http.ResponseWriter) {
+	h.JSONErrorRespond(w, http.StatusConflict, ErrTwoFactorAlreadyEnabled)
+}
+
+func (h *GatewayHandler) ErrorInvalidTwoFactorCode(w http.ResponseWriter) {
+	h.JSONErrorRespond(w, http.StatusBadRequest, ErrInvalidTwoFactorCode)
+}
+
+func (h *GatewayHandler) ErrorTwoFactorRequiredByRole(w http.ResponseWriter) {
+	h.JSONErrorRespond(w, http.StatusForbidden, ErrTwoFactorRequiredByRole)
+}
+
+func (h *GatewayHandler) ErrorInvalidTwoFactorChallenge(w http.ResponseWriter) {
+	h.JSONErrorRespond(w, http.StatusUnauthorized, ErrInvalidTwoFactorChallenge)
+}
+
+func (h *GatewayHandler) ErrorAvatarTooLarge(w http.ResponseWriter) {
+	h.JSONErrorRespond(w, http.StatusRequestEntityTooLarge, ErrAvatarTooLarge)
+}
+
+func (h *GatewayHandler) ErrorEmailChangeThrottled(w http.ResponseWriter) {
+	h.JSONErrorRespond(w, http.StatusTooManyRequests, ErrEmailChangeThrottled)
+}
+
+func (h *GatewayHandler) ErrorInvalidEmailChangeToken(w http.ResponseWriter) {
+	h.JSONErrorRespond(w, http.StatusBadRequest, ErrInvalidEmailChangeToken)
+}
+
+func (h *GatewayHandler) ErrorUserDeletionInProgress(w http.ResponseWriter) {
+	h.JSONErrorRespond(w, http.StatusConflict, ErrUserDeletionInProgress)
+}
+
+func (h *GatewayHandler) ErrorDataExportThrottled(w http.ResponseWriter) {
+	h.JSONErrorRespond(w, http.StatusTooManyRequests, ErrDataExportThrottled)
+}
+
+func (h *GatewayHandler) ErrorInvalidDataExportToken(w http.ResponseWriter) {
+	h.JSONErrorRespond(w, http.StatusBadRequest, ErrInvalidDataExportToken)
+}
+
+func (h *GatewayHandler) ErrorErasureThrottled(w http.ResponseWriter) {
+	h.JSONErrorRespond(w, http.StatusTooManyRequests, ErrErasureThrottled)
+}
+
+func (h *GatewayHandler) ErrorInvalidErasureToken(w http.ResponseWriter) {
+	h.JSONErrorRespond(w, http.StatusBadRequest, ErrInvalidErasureToken)
+}
+
+func (h *GatewayHandler) ErrorExternalLoginFailed(w http.ResponseWriter) {
+	h.JSONErrorRespond(w, http.StatusUnauthorized, ErrExternalLoginFailed)
+}
+
+func (h *GatewayHandler) ErrorExternalEmailNotVerified(w http.ResponseWriter) {
+	h.JSONErrorRespond(w, http.StatusForbidden, ErrExternalEmailNotVerified)
+}
+
+func (h *GatewayHandler) ErrorExternalEmailConflict(w http.ResponseWriter) {
+	h.JSONErrorRespond(w, http.StatusConflict, ErrExternalEmailConflict)
+}
+
+// ErrorLoginThrottled отвечает 429 и сообщает в Retry-After, через сколько секунд можно попробовать войти снова
+func (h *GatewayHandler) ErrorLoginThrottled(w http.ResponseWriter, err error) {
+	apiErr := ErrLoginThrottled
+	if errors.Is(err, app_errors.ErrAccountLocked) {
+		apiErr = ErrAccountLocked
+	}
+
+	var retryErr *app_errors.RetryAfterError
+	if errors.As(err, &retryErr) {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryErr.RetryAfter.Seconds()))))
+	}
+
+	h.JSONErrorRespond(w, http.StatusTooManyRequests, apiErr)
+}
+
+func (h *GatewayHandler) ErrorAlreadyExists(w http.ResponseWriter, message string) {
+	h.JSONErrorRespond(w, http.StatusConflict, NewApiError(message, ErrCodeAlreadyExists))
+}
+
+func (h *GatewayHandler) ErrorRequestValidation(w http.ResponseWriter, message string) {
+	h.JSONErrorRespond(w, http.StatusBadRequest, NewApiError(message, ErrCodeRequestValidationError))
+}
+
+// ErrorFieldsValidation отвечает на ValidationError, ошибки по полям уходят клиенту в details
+func (h *GatewayHandler) ErrorFieldsValidation(w http.ResponseWriter, validationErr *app_errors.ValidationError) {
+	apiErr := NewApiError(validationErr.Message, ErrCodeRequestValidationError)
+	if len(validationErr.Fields) > 0 {
+		apiErr = apiErr.WithDetails(struct {
+			Fields []app_errors.FieldViolation `json:"fields"`
+		}{
+			Fields: validationErr.Fields,
+		})
+	}
+
+	h.JSONErrorRespond(w, http.StatusBadRequest, apiErr)
+}
+
 func (h *GatewayHandler) ErrorInternalApi(w http.ResponseWriter) {
 	h.JSONErrorRespond(w, http.StatusInternalServerError, ErrInternalApi)
 }
 
+func (h *GatewayHandler) ErrorInvalidQuery(w http.ResponseWriter, queryErr *app_errors.QueryError) {
+	h.JSONErrorRespond(w, http.StatusBadRequest, NewApiError(queryErr.Error(), ErrCodeInvalidQuery).WithDetails(
+		struct {
+			Position int    `json:"position"`
+			Message  string `json:"message"`
+		}{
+			Position: queryErr.Position,
+			Message:  queryErr.Message,
+		},
+	))
+}
+
 func (h *GatewayHandler) JSONErrorRespond(w http.ResponseWriter, httpCode int, err *ApiError) {
 	// установка хэдера ответа
 	w.Header().Set("Content-Type", "application/json")
@@ -42,6 +188,7 @@ func (h *GatewayHandler) JSONErrorRespond(w http.ResponseWriter, httpCode int, e
 	data := ErrorResponse{
 		ErrorCode:    string(err.ErrCode),
 		ErrorMessage: err.Error(),
+		Details:      err.Details,
 	}
 
 	rawData, marshalErr := json.Marshal(data)
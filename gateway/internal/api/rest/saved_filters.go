@@ -0,0 +1,168 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"gateway/internal/app_errors"
+	"gateway/internal/models"
+	"gateway/pkg/ctxutil"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/opentracing/opentracing-go"
+	"net/http"
+)
+
+func (h *GatewayHandler) CreateSavedFilterHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.CreateSavedFilter")
+	defer span.Finish()
+
+	newFilter := models.NewEmptySavedFilterDTO()
+	if err := json.NewDecoder(r.Body).Decode(newFilter); err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[CreateSavedFilterHandler] unmarshal: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	createdFilter, err := h.gatewayService.CreateSavedFilter(ctx, newFilter)
+	if err != nil {
+		h.savedFilterErrorRespond(w, requestId, "[CreateSavedFilterHandler] create filter", err)
+		return
+	}
+
+	h.JSONSuccessRespond(w, createdFilter)
+}
+
+func (h *GatewayHandler) GetSavedFiltersHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.GetSavedFilters")
+	defer span.Finish()
+
+	filters, err := h.gatewayService.GetSavedFilters(ctx)
+	if err != nil {
+		h.savedFilterErrorRespond(w, requestId, "[GetSavedFiltersHandler] get filters", err)
+		return
+	}
+
+	h.JSONSuccessRespond(w, filters)
+}
+
+func (h *GatewayHandler) UpdateSavedFilterHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.UpdateSavedFilter")
+	defer span.Finish()
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[UpdateSavedFilterHandler] parse id from url: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	filter := models.NewEmptySavedFilterDTO()
+	if err := json.NewDecoder(r.Body).Decode(filter); err != nil {
+		h.ErrorBadRequest(w)
+		return
+	}
+	filter.ID = id
+
+	updatedFilter, err := h.gatewayService.UpdateSavedFilter(ctx, filter)
+	if err != nil {
+		h.savedFilterErrorRespond(w, requestId, "[UpdateSavedFilterHandler] update filter", err)
+		return
+	}
+
+	h.JSONSuccessRespond(w, updatedFilter)
+}
+
+func (h *GatewayHandler) DeleteSavedFilterHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.DeleteSavedFilter")
+	defer span.Finish()
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[DeleteSavedFilterHandler] parse id from url: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	err = h.gatewayService.DeleteSavedFilter(ctx, id)
+	if err != nil {
+		h.savedFilterErrorRespond(w, requestId, "[DeleteSavedFilterHandler] delete filter", err)
+		return
+	}
+
+	h.JSONSuccessRespond(w, nil)
+}
+
+func (h *GatewayHandler) RunSavedFilterHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.RunSavedFilter")
+	defer span.Finish()
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[RunSavedFilterHandler] parse id from url: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	response, err := h.gatewayService.RunSavedFilter(ctx, id)
+	if err != nil {
+		h.savedFilterErrorRespond(w, requestId, "[RunSavedFilterHandler] run filter", err)
+		return
+	}
+
+	h.JSONSuccessRespond(w, response)
+}
+
+// savedFilterErrorRespond отвечает клиенту на ошибку операции с сохраненным фильтром
+func (h *GatewayHandler) savedFilterErrorRespond(w http.ResponseWriter, requestId, operation string, err error) {
+	var queryErr *app_errors.QueryError
+	if errors.As(err, &queryErr) {
+		h.ErrorInvalidQuery(w, queryErr)
+		return
+	}
+
+	var validationErr *app_errors.ValidationError
+	if errors.As(err, &validationErr) {
+		h.ErrorRequestValidation(w, validationErr.Message)
+		return
+	}
+
+	switch {
+	case errors.Is(err, app_errors.ErrNotFound):
+		h.ErrorNotFound(w)
+		return
+	case errors.Is(err, app_errors.ErrForbidden):
+		h.ErrorForbidden(w)
+		return
+	case errors.Is(err, app_errors.ErrAlreadyExists):
+		h.ErrorAlreadyExists(w, "saved filter with this name already exists")
+		return
+	}
+
+	h.logger.Error().
+		Str("requestId", requestId).
+		Msgf("%s: %s", operation, err)
+	h.ErrorInternalApi(w)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"gateway/config"
 	rest "gateway/internal/api"
+	"gateway/internal/models"
 	"github.com/gorilla/mux"
 	"github.com/rs/zerolog"
 	"net/http"
@@ -14,34 +15,111 @@ func RunREST(
 	logger *zerolog.Logger,
 	gatewayService rest.GatewayService,
 ) error {
-	gatewayHandler := NewGatewayHandler(logger, gatewayService)
+	gatewayHandler := NewGatewayHandler(logger, gatewayService, cfg.App.PublicURL)
 
 	router := mux.NewRouter()
 	router.Use(
+		ClientIPMiddleware(cfg.App.TrustProxyHeaders),
 		ValidateTokenMiddleware(
 			&cfg.JWT,
+			gatewayService,
 			[]string{
 				"/api/v1/users/login",
 				"/api/v1/users/register",
+				"/api/v1/users/refresh",
+				"/api/v1/users/verify",
+				"/api/v1/users/password-reset",
+				"/api/v1/users/email/confirm",
+				"/api/v1/users/export/download",
+				"/api/v1/users/erasure/confirm",
+				oidcPath,
+				sharedTodoPath,
 			},
 		),
 	)
 
 	usersV1Router := router.PathPrefix("/api/v1/users").Subrouter()
+	usersV1Router.HandleFunc("", gatewayHandler.ListUsersHandler).Methods(http.MethodGet)
 	usersV1Router.HandleFunc("/register", gatewayHandler.RegisterUser).Methods(http.MethodPost)
 	usersV1Router.HandleFunc("/{id:[0-9]+}", gatewayHandler.GetGetUserById).Methods(http.MethodGet)
 	usersV1Router.HandleFunc("/update", gatewayHandler.UpdateUser).Methods(http.MethodPut)
 	usersV1Router.HandleFunc("/update-password", gatewayHandler.UpdatePassword).Methods(http.MethodPut)
-	usersV1Router.HandleFunc("/delete/{id:[0-9]+}", gatewayHandler.DeleteUser).Methods(http.MethodDelete)
+	usersV1Router.HandleFunc("/avatar", gatewayHandler.UploadAvatar).Methods(http.MethodPut)
+	usersV1Router.HandleFunc("/avatar", gatewayHandler.DeleteAvatar).Methods(http.MethodDelete)
+	usersV1Router.HandleFunc("/notifications", gatewayHandler.GetNotificationPreferences).Methods(http.MethodGet)
+	usersV1Router.HandleFunc("/notifications", gatewayHandler.UpdateNotificationPreferences).Methods(http.MethodPut)
+
+	// управлять чужими аккаунтами может только роль с разрешением users:manage
+	manageUser := SelfOrPermission("id", models.PermissionUsersManage)
+	usersV1Router.HandleFunc("/{id:[0-9]+}", gatewayHandler.Authorize(manageUser, gatewayHandler.UpdateUser)).Methods(http.MethodPut)
+	usersV1Router.HandleFunc("/delete/{id:[0-9]+}", gatewayHandler.Authorize(manageUser, gatewayHandler.DeleteUser)).Methods(http.MethodDelete)
+	usersV1Router.HandleFunc("/delete/{id:[0-9]+}", gatewayHandler.Authorize(manageUser, gatewayHandler.GetUserDeletion)).Methods(http.MethodGet)
+	usersV1Router.HandleFunc("/{id:[0-9]+}/role", gatewayHandler.Authorize(RequirePermission(models.PermissionUsersManage), gatewayHandler.SetUserRole)).Methods(http.MethodPut)
+	usersV1Router.HandleFunc("/{id:[0-9]+}/unlock", gatewayHandler.Authorize(RequirePermission(models.PermissionUsersManage), gatewayHandler.UnlockUser)).Methods(http.MethodPost)
 	usersV1Router.HandleFunc("/login", gatewayHandler.UserLogin).Methods(http.MethodPost)
+	usersV1Router.HandleFunc("/login/2fa", gatewayHandler.LoginTwoFactorHandler).Methods(http.MethodPost)
+	usersV1Router.HandleFunc("/oidc/login", gatewayHandler.ExternalLoginHandler).Methods(http.MethodGet)
+	usersV1Router.HandleFunc("/oidc/callback", gatewayHandler.ExternalLoginCallbackHandler).Methods(http.MethodGet)
 	usersV1Router.HandleFunc("/refresh", gatewayHandler.Refresh).Methods(http.MethodPost)
+	usersV1Router.HandleFunc("/logout", gatewayHandler.Logout).Methods(http.MethodPost)
+	usersV1Router.HandleFunc("/logout-all", gatewayHandler.LogoutAll).Methods(http.MethodPost)
+	usersV1Router.HandleFunc("/verify", gatewayHandler.VerifyEmail).Methods(http.MethodGet, http.MethodPost)
+	usersV1Router.HandleFunc("/verify/resend", gatewayHandler.ResendVerificationEmail).Methods(http.MethodPost)
+	usersV1Router.HandleFunc("/password-reset", gatewayHandler.RequestPasswordReset).Methods(http.MethodPost)
+	usersV1Router.HandleFunc("/password-reset/confirm", gatewayHandler.ResetPassword).Methods(http.MethodPost)
+	usersV1Router.HandleFunc("/email", gatewayHandler.RequestEmailChange).Methods(http.MethodPost)
+	usersV1Router.HandleFunc("/email/confirm", gatewayHandler.ConfirmEmailChange).Methods(http.MethodGet, http.MethodPost)
+	usersV1Router.HandleFunc("/export", gatewayHandler.RequestDataExport).Methods(http.MethodPost)
+	usersV1Router.HandleFunc("/export", gatewayHandler.GetDataExport).Methods(http.MethodGet)
+	usersV1Router.HandleFunc("/export/download", gatewayHandler.DownloadDataExport).Methods(http.MethodGet)
+	usersV1Router.HandleFunc("/erasure", gatewayHandler.RequestErasure).Methods(http.MethodPost)
+	usersV1Router.HandleFunc("/erasure/confirm", gatewayHandler.ConfirmErasure).Methods(http.MethodGet, http.MethodPost)
+	usersV1Router.HandleFunc("/2fa/enroll", gatewayHandler.EnrollTwoFactorHandler).Methods(http.MethodPost)
+	usersV1Router.HandleFunc("/2fa/confirm", gatewayHandler.ConfirmTwoFactorHandler).Methods(http.MethodPost)
+	usersV1Router.HandleFunc("/2fa/disable", gatewayHandler.DisableTwoFactorHandler).Methods(http.MethodPost)
+	usersV1Router.HandleFunc("/2fa/recovery-codes", gatewayHandler.RegenerateRecoveryCodesHandler).Methods(http.MethodPost)
+	usersV1Router.HandleFunc("/tokens", gatewayHandler.CreateApiTokenHandler).Methods(http.MethodPost)
+	usersV1Router.HandleFunc("/tokens", gatewayHandler.GetApiTokensHandler).Methods(http.MethodGet)
+	usersV1Router.HandleFunc("/tokens/{id}", gatewayHandler.RevokeApiTokenHandler).Methods(http.MethodDelete)
+
+	// права внутри организации проверяет сервис users по роли участника
+	orgsV1Router := router.PathPrefix("/api/v1/orgs").Subrouter()
+	orgsV1Router.HandleFunc("", gatewayHandler.CreateOrganizationHandler).Methods(http.MethodPost)
+	orgsV1Router.HandleFunc("", gatewayHandler.GetOrganizationsHandler).Methods(http.MethodGet)
+	orgsV1Router.HandleFunc("/invitations/accept", gatewayHandler.AcceptInvitationHandler).Methods(http.MethodPost)
+	orgsV1Router.HandleFunc("/{id:[0-9]+}/invitations", gatewayHandler.InviteMemberHandler).Methods(http.MethodPost)
+	orgsV1Router.HandleFunc("/{id:[0-9]+}/members", gatewayHandler.ListOrganizationMembersHandler).Methods(http.MethodGet)
+	orgsV1Router.HandleFunc("/{id:[0-9]+}/members/{memberId:[0-9]+}", gatewayHandler.RemoveMemberHandler).Methods(http.MethodDelete)
+	orgsV1Router.HandleFunc("/{id:[0-9]+}/members/{memberId:[0-9]+}/role", gatewayHandler.SetMemberRoleHandler).Methods(http.MethodPut)
+	orgsV1Router.HandleFunc("/{id:[0-9]+}/teams", gatewayHandler.CreateTeamHandler).Methods(http.MethodPost)
+	orgsV1Router.HandleFunc("/{id:[0-9]+}/teams", gatewayHandler.GetTeamsHandler).Methods(http.MethodGet)
+	orgsV1Router.HandleFunc("/{id:[0-9]+}/teams/{teamId:[0-9]+}/members", gatewayHandler.ListTeamMembersHandler).Methods(http.MethodGet)
+	orgsV1Router.HandleFunc("/{id:[0-9]+}/teams/{teamId:[0-9]+}/members", gatewayHandler.AddTeamMemberHandler).Methods(http.MethodPost)
+	orgsV1Router.HandleFunc("/{id:[0-9]+}/teams/{teamId:[0-9]+}/members/{memberId:[0-9]+}", gatewayHandler.RemoveTeamMemberHandler).Methods(http.MethodDelete)
 
 	todosV1Router := router.PathPrefix("/api/v1/todos").Subrouter()
 	todosV1Router.HandleFunc("/", gatewayHandler.CreateToDoHandler).Methods(http.MethodPost)
+	todosV1Router.HandleFunc("/", gatewayHandler.SearchToDosHandler).Methods(http.MethodGet)
 	todosV1Router.HandleFunc("/batch", gatewayHandler.GetToDosHandler).Methods(http.MethodPost)
 	todosV1Router.HandleFunc("/{id}", gatewayHandler.GetToDoHandler).Methods(http.MethodGet)
 	todosV1Router.HandleFunc("/{id}", gatewayHandler.UpdateToDoHandler).Methods(http.MethodPut)
 	todosV1Router.HandleFunc("/{id}", gatewayHandler.DeleteToDoHandler).Methods(http.MethodDelete)
+	todosV1Router.HandleFunc("/{id}/share", gatewayHandler.CreateShareLinkHandler).Methods(http.MethodPost)
+	todosV1Router.HandleFunc("/{id}/share", gatewayHandler.GetShareLinksHandler).Methods(http.MethodGet)
+	todosV1Router.HandleFunc("/{id}/share/{linkId}", gatewayHandler.RevokeShareLinkHandler).Methods(http.MethodDelete)
+
+	meV1Router := router.PathPrefix("/api/v1/me").Subrouter()
+	meV1Router.HandleFunc("/todos", gatewayHandler.MyToDosHandler).Methods(http.MethodGet)
+
+	// публичные ссылки на задачи открываются без токена пользователя
+	router.HandleFunc(sharedTodoPath+"{token}", gatewayHandler.SharedTodoHandler).Methods(http.MethodGet)
+
+	filtersV1Router := router.PathPrefix("/api/v1/filters").Subrouter()
+	filtersV1Router.HandleFunc("/", gatewayHandler.CreateSavedFilterHandler).Methods(http.MethodPost)
+	filtersV1Router.HandleFunc("/", gatewayHandler.GetSavedFiltersHandler).Methods(http.MethodGet)
+	filtersV1Router.HandleFunc("/{id}", gatewayHandler.UpdateSavedFilterHandler).Methods(http.MethodPut)
+	filtersV1Router.HandleFunc("/{id}", gatewayHandler.DeleteSavedFilterHandler).Methods(http.MethodDelete)
+	filtersV1Router.HandleFunc("/{id}/todos", gatewayHandler.RunSavedFilterHandler).Methods(http.MethodGet)
 
 	// запустить вебсервер по адресу, передать в него роутер
 	appAddr := fmt.Sprintf("%s:%s", cfg.App.AppHost, cfg.App.AppPort)
@@ -0,0 +1,204 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"gateway/internal/app_errors"
+	"gateway/internal/models"
+	"gateway/pkg/ctxutil"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/opentracing/opentracing-go"
+	"html/template"
+	"net/http"
+	"strings"
+)
+
+// sharedTodoPath - префикс публичных ссылок, исключенный из проверки токена пользователя
+const sharedTodoPath = "/api/v1/shared/"
+
+// sharedTodoPage - страница задачи для получателя публичной ссылки. HTML описания уже очищен сервисом todo.
+var sharedTodoPage = template.Must(template.New("shared_todo").Parse(`<!DOCTYPE html>
+<html>
+<head>
+	<meta charset="utf-8">
+	<meta name="robots" content="noindex, nofollow">
+	<title>{{.Title}}</title>
+</head>
+<body>
+	<h1>{{.Title}}</h1>
+	<div>{{.Description}}</div>
+	<p><small>Updated {{.UpdatedAt.Format "2006-01-02 15:04 MST"}}. This link expires {{.ExpiresAt.Format "2006-01-02 15:04 MST"}}.</small></p>
+</body>
+</html>
+`))
+
+func (h *GatewayHandler) CreateShareLinkHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.CreateShareLink")
+	defer span.Finish()
+
+	todoID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[CreateShareLinkHandler] parse id from url: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	// тело запроса необязательно: без него ссылка выпускается на срок по умолчанию
+	request := new(models.CreateShareLinkDTO)
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+			h.logger.Error().
+				Str("requestId", requestId).
+				Msgf("[CreateShareLinkHandler] unmarshal: %s", err)
+			h.ErrorBadRequest(w)
+			return
+		}
+	}
+
+	link, err := h.gatewayService.CreateShareLink(ctx, todoID, request)
+	if err != nil {
+		h.shareLinkErrorRespond(w, requestId, "[CreateShareLinkHandler] create link", err)
+		return
+	}
+	link.URL = h.sharedTodoURL(link.Token)
+
+	h.JSONSuccessRespond(w, link)
+}
+
+func (h *GatewayHandler) GetShareLinksHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.GetShareLinks")
+	defer span.Finish()
+
+	todoID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[GetShareLinksHandler] parse id from url: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	links, err := h.gatewayService.GetShareLinks(ctx, todoID)
+	if err != nil {
+		h.shareLinkErrorRespond(w, requestId, "[GetShareLinksHandler] get links", err)
+		return
+	}
+
+	for i := range links {
+		links[i].URL = h.sharedTodoURL(links[i].Token)
+	}
+
+	h.JSONSuccessRespond(w, links)
+}
+
+func (h *GatewayHandler) RevokeShareLinkHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.RevokeShareLink")
+	defer span.Finish()
+
+	linkID, err := uuid.Parse(mux.Vars(r)["linkId"])
+	if err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[RevokeShareLinkHandler] parse link id from url: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	err = h.gatewayService.RevokeShareLink(ctx, linkID)
+	if err != nil {
+		h.shareLinkErrorRespond(w, requestId, "[RevokeShareLinkHandler] revoke link", err)
+		return
+	}
+
+	h.JSONSuccessRespond(w, nil)
+}
+
+// SharedTodoHandler отдает задачу по публичной ссылке: HTML страницу для браузера или JSON,
+// если клиент запросил application/json
+func (h *GatewayHandler) SharedTodoHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.SharedTodo")
+	defer span.Finish()
+
+	// ссылка не должна попадать в кэши, поисковики и заголовок Referer при переходах со страницы
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Robots-Tag", "noindex, nofollow")
+	w.Header().Set("Referrer-Policy", "no-referrer")
+
+	sharedTodo, err := h.gatewayService.GetSharedTodo(ctx, mux.Vars(r)["token"])
+	if err != nil {
+		if errors.Is(err, app_errors.ErrNotFound) {
+			h.ErrorNotFound(w)
+			return
+		}
+
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[SharedTodoHandler] get todo: %s", err)
+		h.ErrorInternalApi(w)
+		return
+	}
+
+	if strings.Contains(r.Header.Get("Accept"), "application/json") {
+		h.JSONSuccessRespond(w, sharedTodo)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Content-Security-Policy", "default-src 'none'; img-src https: data:; style-src 'unsafe-inline'")
+	w.WriteHeader(http.StatusOK)
+
+	err = sharedTodoPage.Execute(w, struct {
+		*models.SharedTodoDTO
+		Description template.HTML
+	}{
+		SharedTodoDTO: sharedTodo,
+		Description:   template.HTML(sharedTodo.DescriptionHTML),
+	})
+	if err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[SharedTodoHandler] render page: %s", err)
+	}
+}
+
+func (h *GatewayHandler) sharedTodoURL(token string) string {
+	return h.publicURL + sharedTodoPath + token
+}
+
+// shareLinkErrorRespond отвечает клиенту на ошибку операции с публичной ссылкой
+func (h *GatewayHandler) shareLinkErrorRespond(w http.ResponseWriter, requestId, operation string, err error) {
+	var validationErr *app_errors.ValidationError
+	if errors.As(err, &validationErr) {
+		h.ErrorRequestValidation(w, validationErr.Message)
+		return
+	}
+
+	switch {
+	case errors.Is(err, app_errors.ErrNotFound):
+		h.ErrorNotFound(w)
+		return
+	case errors.Is(err, app_errors.ErrForbidden):
+		h.ErrorForbidden(w)
+		return
+	}
+
+	h.logger.Error().
+		Str("requestId", requestId).
+		Msgf("%s: %s", operation, err)
+	h.ErrorInternalApi(w)
+}
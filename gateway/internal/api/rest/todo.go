@@ -30,6 +30,12 @@ func (h *GatewayHandler) CreateToDoHandler(w http.ResponseWriter, r *http.Reques
 
 	createdTodo, err := h.gatewayService.CreateToDo(ctx, newTodo)
 	if err != nil {
+		var validationErr *app_errors.ValidationError
+		if errors.As(err, &validationErr) {
+			h.ErrorRequestValidation(w, validationErr.Message)
+			return
+		}
+
 		h.logger.Error().
 			Str("requestId", requestId).
 			Msgf("[CreateToDoHandler] create todo: %s", err)
@@ -91,6 +97,12 @@ func (h *GatewayHandler) GetToDosHandler(w http.ResponseWriter, r *http.Request)
 
 	response, err := h.gatewayService.GetToDos(ctx, newTodos)
 	if err != nil {
+		var queryErr *app_errors.QueryError
+		if errors.As(err, &queryErr) {
+			h.ErrorInvalidQuery(w, queryErr)
+			return
+		}
+
 		h.logger.Error().
 			Str("requestId", requestId).
 			Msgf("[GetToDos] getting:%s", err)
@@ -102,6 +114,65 @@ func (h *GatewayHandler) GetToDosHandler(w http.ResponseWriter, r *http.Request)
 
 }
 
+func (h *GatewayHandler) SearchToDosHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.SearchToDos")
+	defer span.Finish()
+
+	// выражение фильтра передается в параметре q, например ?q=assignee:me created>2024-01-01
+	request := models.NewEmptyGetTodosDTO()
+	request.Query = r.URL.Query().Get("q")
+
+	response, err := h.gatewayService.GetToDos(ctx, request)
+	if err != nil {
+		var queryErr *app_errors.QueryError
+		if errors.As(err, &queryErr) {
+			h.ErrorInvalidQuery(w, queryErr)
+			return
+		}
+
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[SearchToDos] getting:%s", err)
+		h.ErrorInternalApi(w)
+		return
+	}
+
+	h.JSONSuccessRespond(w, response)
+}
+
+// MyToDosHandler ищет задачи, которые пользователь создал или исполняет, даже если его роль видит все задачи
+func (h *GatewayHandler) MyToDosHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.MyToDos")
+	defer span.Finish()
+
+	// выражение фильтра передается в параметре q так же, как при поиске по всем задачам
+	request := models.NewEmptyGetTodosDTO()
+	request.Query = r.URL.Query().Get("q")
+
+	response, err := h.gatewayService.GetMyToDos(ctx, request)
+	if err != nil {
+		var queryErr *app_errors.QueryError
+		if errors.As(err, &queryErr) {
+			h.ErrorInvalidQuery(w, queryErr)
+			return
+		}
+
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[MyToDos] getting:%s", err)
+		h.ErrorInternalApi(w)
+		return
+	}
+
+	h.JSONSuccessRespond(w, response)
+}
+
 func (h *GatewayHandler) UpdateToDoHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
@@ -131,6 +202,10 @@ func (h *GatewayHandler) UpdateToDoHandler(w http.ResponseWriter, r *http.Reques
 			h.ErrorNotFound(w)
 			return
 		}
+		if errors.Is(err, app_errors.ErrForbidden) {
+			h.ErrorForbidden(w)
+			return
+		}
 
 		h.logger.Error().
 			Str("requestId", requestId).
@@ -164,6 +239,10 @@ func (h *GatewayHandler) DeleteToDoHandler(w http.ResponseWriter, r *http.Reques
 			h.ErrorNotFound(w)
 			return
 		}
+		if errors.Is(err, app_errors.ErrForbidden) {
+			h.ErrorForbidden(w)
+			return
+		}
 
 		h.logger.Error().
 			Str("requestId", requestId).
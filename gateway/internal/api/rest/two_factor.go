@@ -0,0 +1,182 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	appErrors "gateway/internal/app_errors"
+	"gateway/internal/models"
+	"gateway/pkg/ctxutil"
+	"github.com/opentracing/opentracing-go"
+	"net/http"
+)
+
+// LoginTwoFactorHandler - второй шаг входа: обменивает токен из ответа на /login и код на пару токенов
+func (h *GatewayHandler) LoginTwoFactorHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.LoginTwoFactorHandler")
+	defer span.Finish()
+
+	var request = new(models.TwoFactorLoginDTO)
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[LoginTwoFactorHandler] unmarshall: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	response, err := h.gatewayService.LoginTwoFactor(ctx, request)
+	if err != nil {
+		if errors.Is(err, appErrors.ErrInvalidTwoFactorChallenge) {
+			h.ErrorInvalidTwoFactorChallenge(w)
+			return
+		}
+		if h.respondTwoFactorError(w, err) {
+			return
+		}
+
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[LoginTwoFactorHandler] login: %s", err)
+		h.ErrorInternalApi(w)
+		return
+	}
+
+	h.JSONSuccessRespond(w, response)
+}
+
+// EnrollTwoFactorHandler выдает секрет для приложения-аутентификатора. Второй фактор включается
+// только после подтверждения кодом из приложения в ConfirmTwoFactorHandler
+func (h *GatewayHandler) EnrollTwoFactorHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.EnrollTwoFactorHandler")
+	defer span.Finish()
+
+	response, err := h.gatewayService.EnrollTwoFactor(ctx)
+	if err != nil {
+		if h.respondTwoFactorError(w, err) {
+			return
+		}
+
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[EnrollTwoFactorHandler] enroll: %s", err)
+		h.ErrorInternalApi(w)
+		return
+	}
+
+	h.JSONSuccessRespond(w, response)
+}
+
+func (h *GatewayHandler) ConfirmTwoFactorHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.ConfirmTwoFactorHandler")
+	defer span.Finish()
+
+	var request = new(models.TwoFactorCodeDTO)
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[ConfirmTwoFactorHandler] unmarshall: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	response, err := h.gatewayService.ConfirmTwoFactor(ctx, request)
+	if err != nil {
+		if h.respondTwoFactorError(w, err) {
+			return
+		}
+
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[ConfirmTwoFactorHandler] confirm: %s", err)
+		h.ErrorInternalApi(w)
+		return
+	}
+
+	h.JSONSuccessRespond(w, response)
+}
+
+func (h *GatewayHandler) DisableTwoFactorHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.DisableTwoFactorHandler")
+	defer span.Finish()
+
+	var request = new(models.TwoFactorCodeDTO)
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[DisableTwoFactorHandler] unmarshall: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	err := h.gatewayService.DisableTwoFactor(ctx, request)
+	if err != nil {
+		if h.respondTwoFactorError(w, err) {
+			return
+		}
+
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[DisableTwoFactorHandler] disable: %s", err)
+		h.ErrorInternalApi(w)
+		return
+	}
+
+	h.JSONSuccessRespond(w, nil)
+}
+
+func (h *GatewayHandler) RegenerateRecoveryCodesHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.RegenerateRecoveryCodesHandler")
+	defer span.Finish()
+
+	var request = new(models.TwoFactorCodeDTO)
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[RegenerateRecoveryCodesHandler] unmarshall: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	response, err := h.gatewayService.RegenerateRecoveryCodes(ctx, request)
+	if err != nil {
+		if h.respondTwoFactorError(w, err) {
+			return
+		}
+
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[RegenerateRecoveryCodesHandler] regenerate: %s", err)
+		h.ErrorInternalApi(w)
+		return
+	}
+
+	h.JSONSuccessRespond(w, response)
+}
+
+// respondTwoFactorError отвечает на ошибки второго фактора, которые пользователь может исправить сам
+func (h *GatewayHandler) respondTwoFactorError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, appErrors.ErrInvalidTwoFactorCode):
+		h.ErrorInvalidTwoFactorCode(w)
+	case errors.Is(err, appErrors.ErrTwoFactorNotEnabled):
+		h.ErrorTwoFactorNotEnabled(w)
+	case errors.Is(err, appErrors.ErrTwoFactorAlreadyEnabled):
+		h.ErrorTwoFactorAlreadyEnabled(w)
+	case errors.Is(err, appErrors.ErrTwoFactorRequiredByRole):
+		h.ErrorTwoFactorRequiredByRole(w)
+	default:
+		return false
+	}
+
+	return true
+}
@@ -31,15 +31,20 @@ func (h *GatewayHandler) RegisterUser(w http.ResponseWriter, r *http.Request) {
 	// передаем данные в слой сервиса
 	userID, err := h.gatewayService.RegisterUser(ctx, newUser)
 	if err != nil {
-		if errors.As(err, &appErrors.ErrUsernameOrEmailIsUsed) {
+		var validationErr *appErrors.ValidationError
+		switch {
+		case errors.As(err, &validationErr):
+			h.ErrorFieldsValidation(w, validationErr)
+		case errors.Is(err, appErrors.ErrUsernameOrEmailIsUsed):
 			h.ErrorUsernameOrEmailAlreadyUsed(w)
-			return
+		case errors.Is(err, appErrors.ErrPassAndConfirmationDoesNotMatch):
+			h.ErrorPassAndConfirmationDoesNotMatch(w)
+		default:
+			h.logger.Error().
+				Str("requestId", requestId).
+				Msgf("[RegisterUser] register:%s", err)
+			h.ErrorInternalApi(w)
 		}
-
-		h.logger.Error().
-			Str("requestId", requestId).
-			Msgf("[RegisterUser] register:%s", err)
-		h.ErrorInternalApi(w)
 		return
 	}
 
@@ -86,6 +91,50 @@ func (h *GatewayHandler) GetGetUserById(w http.ResponseWriter, r *http.Request)
 	h.JSONSuccessRespond(w, user)
 }
 
+// ListUsersHandler ищет пользователей для выбора исполнителя задачи.
+// Параметры: q - начало username или email, limit, offset и sort (id, username, email, с минусом - по убыванию)
+func (h *GatewayHandler) ListUsersHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.ListUsersHandler")
+	defer span.Finish()
+
+	query := r.URL.Query()
+	filter := &models.ListUsersDTO{
+		Search: query.Get("q"),
+		Sort:   query.Get("sort"),
+	}
+
+	for param, value := range map[string]*int{"limit": &filter.Limit, "offset": &filter.Offset} {
+		if query.Get(param) == "" {
+			continue
+		}
+
+		number, err := strconv.Atoi(query.Get(param))
+		if err != nil || number < 0 {
+			h.ErrorRequestValidation(w, param+" must be a non-negative integer")
+			return
+		}
+		*value = number
+	}
+
+	page, err := h.gatewayService.ListUsers(ctx, filter)
+	if err != nil {
+		if errors.Is(err, appErrors.ErrInvalidSort) {
+			h.ErrorRequestValidation(w, "sort must be one of id, username, email, optionally prefixed with -")
+			return
+		}
+
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[ListUsersHandler] list users:%s", err)
+		h.ErrorInternalApi(w)
+		return
+	}
+
+	h.JSONSuccessRespond(w, page)
+}
+
 func (h *GatewayHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
@@ -102,13 +151,21 @@ func (h *GatewayHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// передаем данные в слой сервиса
-	response, err := h.gatewayService.UpdateUser(ctx, updatedUser)
+	// на /users/{id} изменяется пользователь из адреса, на /users/update - сам пользователь из токена
+	userID, err := h.targetUserID(r)
 	if err != nil {
 		h.logger.Error().
 			Str("requestId", requestId).
-			Msgf("[UpdateUser] update user: %s", err)
-		h.ErrorInternalApi(w)
+			Msgf("[UpdateUser] get user id: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+	updatedUser.ID = userID
+
+	// передаем данные в слой сервиса
+	response, err := h.gatewayService.UpdateUser(ctx, updatedUser)
+	if err != nil {
+		h.profileErrorRespond(w, requestId, "[UpdateUser] update user", err)
 		return
 	}
 
@@ -132,12 +189,30 @@ func (h *GatewayHandler) UpdatePassword(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	// пароль меняется только себе
+	userID, ok := ctxutil.GetUserIDFromContext(ctx)
+	if !ok {
+		h.ErrorBadRequest(w)
+		return
+	}
+	passwordRequest.ID = userID
+
 	// передаем данные в слой сервиса
 	if err := h.gatewayService.UpdatePassword(ctx, passwordRequest); err != nil {
-		h.logger.Error().
-			Str("requestId", requestId).
-			Msgf("[UpdatePassword] update password: %s", err)
-		h.ErrorInternalApi(w)
+		var validationErr *appErrors.ValidationError
+		switch {
+		case errors.As(err, &validationErr):
+			h.ErrorFieldsValidation(w, validationErr)
+		case errors.Is(err, appErrors.ErrIncorrectOldPassword):
+			h.ErrorIncorrectOldPassword(w)
+		case errors.Is(err, appErrors.ErrPassAndConfirmationDoesNotMatch):
+			h.ErrorPassAndConfirmationDoesNotMatch(w)
+		default:
+			h.logger.Error().
+				Str("requestId", requestId).
+				Msgf("[UpdatePassword] update password: %s", err)
+			h.ErrorInternalApi(w)
+		}
 		return
 	}
 
@@ -161,12 +236,20 @@ func (h *GatewayHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// передаем данные в слой сервиса
+	// передаем данные в слой сервиса. Пользователь удаляется окончательно, когда удаление подтвердят
+	// все сервисы, за состоянием удаления можно следить через GetUserDeletion
 	if err := h.gatewayService.DeleteUser(ctx, userID); err != nil {
 		h.logger.Error().
 			Str("requestId", requestId).
 			Msgf("[DeleteUser] delete user: %s", err)
-		h.ErrorInternalApi(w)
+		switch {
+		case errors.Is(err, appErrors.ErrNotFound):
+			h.ErrorNotFound(w)
+		case errors.Is(err, appErrors.ErrUserDeletionInProgress):
+			h.ErrorUserDeletionInProgress(w)
+		default:
+			h.ErrorInternalApi(w)
+		}
 		return
 	}
 
@@ -174,6 +257,38 @@ func (h *GatewayHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 	h.JSONSuccessRespond(w, nil)
 }
 
+// GetUserDeletion возвращает состояние последнего удаления пользователя
+func (h *GatewayHandler) GetUserDeletion(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.GetUserDeletion")
+	defer span.Finish()
+
+	userID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[GetUserDeletion] get id from query: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	deletion, err := h.gatewayService.GetUserDeletion(ctx, userID)
+	if err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[GetUserDeletion] get deletion: %s", err)
+		if errors.Is(err, appErrors.ErrNotFound) {
+			h.ErrorNotFound(w)
+			return
+		}
+		h.ErrorInternalApi(w)
+		return
+	}
+
+	h.JSONSuccessRespond(w, deletion)
+}
+
 func (h *GatewayHandler) UserLogin(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
@@ -193,6 +308,16 @@ func (h *GatewayHandler) UserLogin(w http.ResponseWriter, r *http.Request) {
 
 	response, err := h.gatewayService.Login(ctx, request)
 	if err != nil {
+		if errors.Is(err, appErrors.ErrLoginThrottled) || errors.Is(err, appErrors.ErrAccountLocked) {
+			h.ErrorLoginThrottled(w, err)
+			return
+		}
+
+		if errors.Is(err, appErrors.ErrEmailNotVerified) {
+			h.ErrorEmailNotVerified(w)
+			return
+		}
+
 		if errors.As(err, &appErrors.ErrWrongCredentials) {
 			h.ErrorWrongCredentials(w)
 			return
@@ -229,6 +354,16 @@ func (h *GatewayHandler) Refresh(w http.ResponseWriter, r *http.Request) {
 	// передаем данные слою бизнес-логики
 	response, err := h.gatewayService.Refresh(ctx, request.RefreshToken)
 	if err != nil {
+		if errors.Is(err, appErrors.ErrInvalidRefreshToken) ||
+			errors.Is(err, appErrors.ErrSessionNotFound) ||
+			errors.Is(err, appErrors.ErrRefreshTokenReused) {
+			h.logger.Warn().
+				Str("requestId", requestId).
+				Msgf("[Refresh] refresh: %s", err)
+			h.ErrorInvalidRefreshToken(w)
+			return
+		}
+
 		h.logger.Error().
 			Str("requestId", requestId).
 			Msgf("[Refresh] refresh: %s", err)
@@ -239,3 +374,366 @@ func (h *GatewayHandler) Refresh(w http.ResponseWriter, r *http.Request) {
 	// возвращаем пользователю ответ
 	h.JSONSuccessRespond(w, response)
 }
+
+// VerifyEmail подтверждает email по токену из письма. Ссылка из письма открывается GET запросом
+// с токеном в параметре token, клиенты приложения могут передать токен в теле POST запроса.
+func (h *GatewayHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.VerifyEmail")
+	defer span.Finish()
+
+	var request = new(models.VerifyEmailDTO)
+	if r.Method == http.MethodGet {
+		request.Token = r.URL.Query().Get("token")
+	} else if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[VerifyEmail] unmarshall: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	// передаем данные слою бизнес-логики
+	err := h.gatewayService.VerifyEmail(ctx, request.Token)
+	if err != nil {
+		if errors.Is(err, appErrors.ErrInvalidVerificationToken) {
+			h.ErrorInvalidVerificationToken(w)
+			return
+		}
+
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[VerifyEmail] verify: %s", err)
+		h.ErrorInternalApi(w)
+		return
+	}
+
+	// возвращаем пользователю ответ - в данном случе просто status 200
+	h.JSONSuccessRespond(w, nil)
+}
+
+func (h *GatewayHandler) ResendVerificationEmail(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.ResendVerificationEmail")
+	defer span.Finish()
+
+	var request = new(models.ResendVerificationEmailDTO)
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[ResendVerificationEmail] unmarshall: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	// передаем данные слою бизнес-логики
+	err := h.gatewayService.ResendVerificationEmail(ctx, request.Email)
+	if err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[ResendVerificationEmail] resend: %s", err)
+		h.ErrorInternalApi(w)
+		return
+	}
+
+	// ответ одинаковый для любого адреса, чтобы по нему нельзя было узнать, зарегистрирован ли он
+	h.JSONSuccessRespond(w, nil)
+}
+
+func (h *GatewayHandler) RequestPasswordReset(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.RequestPasswordReset")
+	defer span.Finish()
+
+	var request = new(models.RequestPasswordResetDTO)
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[RequestPasswordReset] unmarshall: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	// передаем данные слою бизнес-логики
+	err := h.gatewayService.RequestPasswordReset(ctx, request.Email)
+	if err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[RequestPasswordReset] request reset: %s", err)
+		h.ErrorInternalApi(w)
+		return
+	}
+
+	// ответ одинаковый для любого адреса, чтобы по нему нельзя было узнать, зарегистрирован ли он
+	h.JSONSuccessRespond(w, nil)
+}
+
+func (h *GatewayHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.ResetPassword")
+	defer span.Finish()
+
+	var request = new(models.ResetPasswordDTO)
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[ResetPassword] unmarshall: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	// передаем данные слою бизнес-логики
+	err := h.gatewayService.ResetPassword(ctx, request)
+	if err != nil {
+		var validationErr *appErrors.ValidationError
+		if errors.As(err, &validationErr) {
+			h.ErrorFieldsValidation(w, validationErr)
+			return
+		}
+		if errors.Is(err, appErrors.ErrInvalidPasswordResetToken) {
+			h.ErrorInvalidPasswordResetToken(w)
+			return
+		}
+		if errors.Is(err, appErrors.ErrPassAndConfirmationDoesNotMatch) {
+			h.ErrorPassAndConfirmationDoesNotMatch(w)
+			return
+		}
+
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[ResetPassword] reset: %s", err)
+		h.ErrorInternalApi(w)
+		return
+	}
+
+	// возвращаем пользователю ответ - в данном случе просто status 200
+	h.JSONSuccessRespond(w, nil)
+}
+
+// RequestEmailChange отправляет ссылку для подтверждения на новый email и уведомление на текущий.
+// Email пользователя меняется только после перехода по ссылке
+func (h *GatewayHandler) RequestEmailChange(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.RequestEmailChange")
+	defer span.Finish()
+
+	var request = new(models.RequestEmailChangeDTO)
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[RequestEmailChange] unmarshall: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	// передаем данные слою бизнес-логики
+	err := h.gatewayService.RequestEmailChange(ctx, request)
+	if err != nil {
+		switch {
+		case errors.Is(err, appErrors.ErrInvalidEmail):
+			h.ErrorRequestValidation(w, appErrors.ErrInvalidEmail.Error())
+			return
+		case errors.Is(err, appErrors.ErrEmailUnchanged):
+			h.ErrorRequestValidation(w, appErrors.ErrEmailUnchanged.Error())
+			return
+		case errors.Is(err, appErrors.ErrWrongCredentials):
+			h.ErrorWrongCredentials(w)
+			return
+		case errors.Is(err, appErrors.ErrUsernameOrEmailIsUsed):
+			h.ErrorUsernameOrEmailAlreadyUsed(w)
+			return
+		case errors.Is(err, appErrors.ErrEmailChangeThrottled):
+			h.ErrorEmailChangeThrottled(w)
+			return
+		}
+
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[RequestEmailChange] request change: %s", err)
+		h.ErrorInternalApi(w)
+		return
+	}
+
+	// возвращаем пользователю ответ - в данном случе просто status 200
+	h.JSONSuccessRespond(w, nil)
+}
+
+// ConfirmEmailChange подтверждает новый email по токену из письма. Ссылка из письма открывается GET запросом
+// с токеном в параметре token, клиенты приложения могут передать токен в теле POST запроса.
+func (h *GatewayHandler) ConfirmEmailChange(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.ConfirmEmailChange")
+	defer span.Finish()
+
+	var request = new(models.ConfirmEmailChangeDTO)
+	if r.Method == http.MethodGet {
+		request.Token = r.URL.Query().Get("token")
+	} else if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[ConfirmEmailChange] unmarshall: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	// передаем данные слою бизнес-логики
+	err := h.gatewayService.ConfirmEmailChange(ctx, request.Token)
+	if err != nil {
+		switch {
+		case errors.Is(err, appErrors.ErrInvalidEmailChangeToken):
+			h.ErrorInvalidEmailChangeToken(w)
+			return
+		case errors.Is(err, appErrors.ErrUsernameOrEmailIsUsed):
+			h.ErrorUsernameOrEmailAlreadyUsed(w)
+			return
+		}
+
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[ConfirmEmailChange] confirm: %s", err)
+		h.ErrorInternalApi(w)
+		return
+	}
+
+	// возвращаем пользователю ответ - в данном случе просто status 200
+	h.JSONSuccessRespond(w, nil)
+}
+
+func (h *GatewayHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.Logout")
+	defer span.Finish()
+
+	// передаем данные слою бизнес-логики
+	if err := h.gatewayService.Logout(ctx); err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[Logout] logout: %s", err)
+		h.ErrorInternalApi(w)
+		return
+	}
+
+	// возвращаем пользователю ответ - в данном случе просто status 200
+	h.JSONSuccessRespond(w, nil)
+}
+
+func (h *GatewayHandler) LogoutAll(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.LogoutAll")
+	defer span.Finish()
+
+	// передаем данные слою бизнес-логики
+	if err := h.gatewayService.LogoutAll(ctx); err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[LogoutAll] logout: %s", err)
+		h.ErrorInternalApi(w)
+		return
+	}
+
+	// возвращаем пользователю ответ - в данном случе просто status 200
+	h.JSONSuccessRespond(w, nil)
+}
+
+func (h *GatewayHandler) SetUserRole(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.SetUserRole")
+	defer span.Finish()
+
+	userID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[SetUserRole] get id from query: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	var request = new(models.SetUserRoleDTO)
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[SetUserRole] unmarshall: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	// передаем данные слою бизнес-логики
+	err = h.gatewayService.SetUserRole(ctx, userID, request)
+	if err != nil {
+		if errors.Is(err, appErrors.ErrUnknownRole) {
+			h.ErrorRequestValidation(w, appErrors.ErrUnknownRole.Error())
+			return
+		}
+		if errors.Is(err, appErrors.ErrNotFound) {
+			h.ErrorNotFound(w)
+			return
+		}
+
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[SetUserRole] set role: %s", err)
+		h.ErrorInternalApi(w)
+		return
+	}
+
+	// возвращаем пользователю ответ - в данном случе просто status 200
+	h.JSONSuccessRespond(w, nil)
+}
+
+func (h *GatewayHandler) UnlockUser(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "gateway.UnlockUser")
+	defer span.Finish()
+
+	userID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[UnlockUser] get id from query: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	// передаем данные слою бизнес-логики
+	err = h.gatewayService.UnlockUser(ctx, userID)
+	if err != nil {
+		if errors.Is(err, appErrors.ErrNotFound) {
+			h.ErrorNotFound(w)
+			return
+		}
+
+		h.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[UnlockUser] unlock: %s", err)
+		h.ErrorInternalApi(w)
+		return
+	}
+
+	// возвращаем пользователю ответ - в данном случе просто status 200
+	h.JSONSuccessRespond(w, nil)
+}
+
+// targetUserID возвращает ID пользователя из адреса маршрута, а если его там нет - ID пользователя из токена
+func (h *GatewayHandler) targetUserID(r *http.Request) (int, error) {
+	if id, ok := mux.Vars(r)["id"]; ok {
+		return strconv.Atoi(id)
+	}
+
+	userID, ok := ctxutil.GetUserIDFromContext(r.Context())
+	if !ok {
+		return 0, appErrors.ErrNoUserInContext
+	}
+
+	return userID, nil
+}
@@ -3,6 +3,7 @@ package app_errors
 import (
 	"errors"
 	"fmt"
+	"time"
 )
 
 var (
@@ -12,27 +13,97 @@ var (
 	ErrIncorrectOldPassword            = errors.New("incorrect old password")
 	ErrPassAndConfirmationDoesNotMatch = errors.New("password and confirmation does not match")
 	ErrNoUserInContext                 = errors.New("no user in context")
+	ErrForbidden                       = errors.New("forbidden")
+	ErrAlreadyExists                   = errors.New("already exists")
+	ErrInvalidVerificationToken        = errors.New("verification token is invalid or expired")
+	ErrEmailNotVerified                = errors.New("email is not verified")
+	ErrInvalidPasswordResetToken       = errors.New("password reset token is invalid or expired")
+	ErrInvalidRefreshToken             = errors.New("invalid or expired refresh token")
+	ErrSessionNotFound                 = errors.New("session not found or revoked")
+	ErrRefreshTokenReused              = errors.New("refresh token reused, session revoked")
+	ErrTwoFactorNotEnabled             = errors.New("two-factor authentication is not enabled")
+	ErrTwoFactorAlreadyEnabled         = errors.New("two-factor authentication is already enabled")
+	ErrInvalidTwoFactorCode            = errors.New("invalid two-factor code")
+	ErrInvalidTwoFactorChallenge       = errors.New("two-factor challenge is invalid or expired")
+	ErrTwoFactorRequiredByRole         = errors.New("two-factor authentication is required for the user's role")
+	ErrLoginThrottled                  = errors.New("too many failed login attempts, try again later")
+	ErrAccountLocked                   = errors.New("account is temporarily locked after too many failed login attempts")
+	ErrUnknownRole                     = errors.New("unknown role")
+	ErrExternalLoginDisabled           = errors.New("external login is disabled")
+	ErrExternalLoginFailed             = errors.New("external login failed")
+	ErrExternalEmailNotVerified        = errors.New("identity provider did not confirm the email")
+	ErrExternalEmailConflict           = errors.New("email is used by an unverified account")
+	ErrInvalidSort                     = errors.New("unknown sort field")
+	ErrInvalidApiToken                 = errors.New("api token is invalid, expired or revoked")
+	ErrApiTokenNameInvalid             = errors.New("api token name must be from 1 to 64 characters")
+	ErrUnknownApiTokenScope            = errors.New("unknown api token scope")
+	ErrApiTokenTTLInvalid              = errors.New("api token lifetime is out of the allowed range")
+	ErrTooManyApiTokens                = errors.New("too many active api tokens, revoke unused ones first")
+	ErrOrganizationNameInvalid         = errors.New("organization name must be from 1 to 128 characters")
+	ErrUnknownOrganizationRole         = errors.New("unknown organization role")
+	ErrLastOrganizationOwner           = errors.New("organization must keep at least one owner")
+	ErrAlreadyMember                   = errors.New("user is already a member of the organization")
+	ErrInvalidEmail                    = errors.New("invalid email")
+	ErrInvalidInvitation               = errors.New("invitation is invalid, expired or revoked")
+	ErrInvitationEmailMismatch         = errors.New("invitation was sent to another email")
+	ErrTeamNameInvalid                 = errors.New("team name must be from 1 to 128 characters")
+	ErrTeamNameIsUsed                  = errors.New("team with this name already exists in the organization")
+	ErrUsernameInvalid                 = errors.New("username must be from 1 to 255 characters")
+	ErrDisplayNameInvalid              = errors.New("display name must be at most 64 characters")
+	ErrUnknownTimezone                 = errors.New("unknown timezone, use an IANA name like Europe/Moscow")
+	ErrUnknownLocale                   = errors.New("unknown locale, use a BCP 47 tag like en or ru-RU")
+	ErrInvalidAvatar                   = errors.New("avatar must be a PNG, JPEG or GIF image")
+	ErrAvatarTooLarge                  = errors.New("avatar image is too large")
+	ErrUnknownNotificationEvent        = errors.New("unknown notification event")
+	ErrUnknownNotificationChannel      = errors.New("unknown notification channel")
+	ErrEmailChangeThrottled            = errors.New("email change was requested recently, try again later")
+	ErrInvalidEmailChangeToken         = errors.New("email change token is invalid or expired")
+	ErrEmailUnchanged                  = errors.New("new email is the same as the current one")
+	ErrEmailChangeRequired             = errors.New("email is changed only with confirmation, request an email change")
+	ErrUserDeletionInProgress          = errors.New("user deletion is already in progress")
+	ErrDataExportThrottled             = errors.New("data export was requested recently, try again later")
+	ErrInvalidDataExportToken          = errors.New("data export link is invalid or expired")
+	ErrErasureThrottled                = errors.New("account deletion was requested recently, try again later")
+	ErrInvalidErasureToken             = errors.New("account deletion token is invalid or expired")
 )
 
-type UserIDMismatchError struct {
-	Operation string
-	ContextID int
-	UserDTOID int
+// RetryAfterError - запрос отклонен временно, повторить его можно через RetryAfter
+type RetryAfterError struct {
+	Err        error
+	RetryAfter time.Duration
 }
 
-// Error implements the error interface for UserIDMismatchError
-func (e *UserIDMismatchError) Error() string {
-	return fmt.Sprintf("%s context ID [%d], DTO ID [%d]", e.Operation, e.ContextID, e.UserDTOID)
+func (e *RetryAfterError) Error() string {
+	return fmt.Sprintf("%s (retry after %s)", e.Err, e.RetryAfter)
 }
 
-func NewUserIDMismatchError(
-	Operation string,
-	ContextID int,
-	UserDTOID int,
-) *UserIDMismatchError {
-	return &UserIDMismatchError{
-		Operation: Operation,
-		ContextID: ContextID,
-		UserDTOID: UserDTOID,
-	}
+func (e *RetryAfterError) Unwrap() error {
+	return e.Err
+}
+
+// QueryError - ошибка в выражении фильтра задач, Position указывает на место ошибки (с единицы, в символах)
+type QueryError struct {
+	Position int
+	Message  string
+}
+
+func (e *QueryError) Error() string {
+	return fmt.Sprintf("invalid query at position %d: %s", e.Position, e.Message)
+}
+
+// ValidationError - сервис отклонил данные запроса, Message объясняет причину.
+// Fields - ошибки по отдельным полям запроса, если сервис их передал
+type ValidationError struct {
+	Message string
+	Fields  []FieldViolation
+}
+
+// FieldViolation - ошибка в одном поле запроса
+type FieldViolation struct {
+	Field   string `json:"field" example:"password"`
+	Message string `json:"message" example:"password must contain a digit"`
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
 }
@@ -0,0 +1,51 @@
+package todos
+
+import (
+	"fmt"
+	"strconv"
+
+	"gateway/internal/app_errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorReasonInvalidQuery - причина, с которой сервис todo отклоняет некорректное выражение фильтра
+const errorReasonInvalidQuery = "INVALID_QUERY"
+
+// fromGRPCError восстанавливает из деталей gRPC статуса ошибки, которые gateway умеет показывать пользователю
+func fromGRPCError(err error) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+
+	for _, detail := range st.Details() {
+		info, ok := detail.(*errdetails.ErrorInfo)
+		if !ok {
+			continue
+		}
+
+		switch info.Reason {
+		case errorReasonInvalidQuery:
+			position, _ := strconv.Atoi(info.Metadata["position"])
+			return &app_errors.QueryError{
+				Position: position,
+				Message:  info.Metadata["message"],
+			}
+		}
+	}
+
+	switch st.Code() {
+	case codes.InvalidArgument:
+		return &app_errors.ValidationError{Message: st.Message()}
+	case codes.NotFound:
+		return fmt.Errorf("%w: %s", app_errors.ErrNotFound, st.Message())
+	case codes.PermissionDenied:
+		return fmt.Errorf("%w: %s", app_errors.ErrForbidden, st.Message())
+	case codes.AlreadyExists:
+		return fmt.Errorf("%w: %s", app_errors.ErrAlreadyExists, st.Message())
+	}
+
+	return err
+}
@@ -36,14 +36,18 @@ func NewTodosClient(cfg *config.Config, logger *zerolog.Logger) (*TodosClient, e
 	}, nil
 }
 
-func (c *TodosClient) CreateToDo(ctx context.Context, newTodo *models.CreateTodoDTO) (*models.TodoDTO, error) {
+func (c *TodosClient) CreateToDo(ctx context.Context, newTodo *models.CreateTodoDTO, caller *models.Caller) (*models.TodoDTO, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "client.CreateToDo")
 	defer span.Finish()
 
+	request := newTodo.ToGRPCShort()
+	request.CurrentUserId = int32(caller.UserID)
+	request.CurrentUserPermissions = caller.Permissions
+
 	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
-	todoItem, err := c.client.CreateToDo(ctx, newTodo.ToGRPCShort())
+	todoItem, err := c.client.CreateToDo(ctx, request)
 	if err != nil {
-		return nil, fmt.Errorf("[CreateToDo] create todo: %w", err)
+		return nil, fmt.Errorf("[CreateToDo] create todo: %w", fromGRPCError(err))
 	}
 
 	response, err := models.NewEmptyTodoDTO().FromGRPCFull(todoItem)
@@ -54,14 +58,18 @@ func (c *TodosClient) CreateToDo(ctx context.Context, newTodo *models.CreateTodo
 	return response, nil
 }
 
-func (c *TodosClient) UpdateToDo(ctx context.Context, newTodo *models.TodoDTO) (*models.TodoDTO, error) {
+func (c *TodosClient) UpdateToDo(ctx context.Context, newTodo *models.TodoDTO, caller *models.Caller) (*models.TodoDTO, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "client.UpdateToDo")
 	defer span.Finish()
 
+	request := newTodo.ToGRPCShort()
+	request.CurrentUserId = int32(caller.UserID)
+	request.CurrentUserPermissions = caller.Permissions
+
 	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
-	todoItem, err := c.client.UpdateToDo(ctx, newTodo.ToGRPCShort())
+	todoItem, err := c.client.UpdateToDo(ctx, request)
 	if err != nil {
-		return nil, fmt.Errorf("[CreateToDo] updating: %w", err)
+		return nil, fmt.Errorf("[CreateToDo] updating: %w", fromGRPCError(err))
 	}
 
 	response, err := models.NewEmptyTodoDTO().FromGRPCFull(todoItem)
@@ -79,7 +87,7 @@ func (c *TodosClient) GetToDos(ctx context.Context, todos *models.GetTodosDTO) (
 	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
 	storedTodos, err := c.client.GetToDos(ctx, todos.ToGRPCRequest())
 	if err != nil {
-		return nil, fmt.Errorf("[GetToDos] get: %w", err)
+		return nil, fmt.Errorf("[GetToDos] get: %w", fromGRPCError(err))
 	}
 
 	response, err := models.SliceFromGRPCResponse(storedTodos)
@@ -90,16 +98,18 @@ func (c *TodosClient) GetToDos(ctx context.Context, todos *models.GetTodosDTO) (
 	return response, nil
 }
 
-func (c *TodosClient) GetToDo(ctx context.Context, todoID uuid.UUID) (*models.TodoDTO, error) {
+func (c *TodosClient) GetToDo(ctx context.Context, todoID uuid.UUID, caller *models.Caller) (*models.TodoDTO, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "client.GetToDo")
 	defer span.Finish()
 
 	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
 	todoItem, err := c.client.GetTodoById(ctx, &todo.TodoID{
-		Id: todoID.String(),
+		Id:                     todoID.String(),
+		CurrentUserId:          int32(caller.UserID),
+		CurrentUserPermissions: caller.Permissions,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("[GetToDo] get: %w", err)
+		return nil, fmt.Errorf("[GetToDo] get: %w", fromGRPCError(err))
 	}
 
 	response, err := models.NewEmptyTodoDTO().FromGRPCFull(todoItem)
@@ -110,17 +120,190 @@ func (c *TodosClient) GetToDo(ctx context.Context, todoID uuid.UUID) (*models.To
 	return response, nil
 }
 
-func (c *TodosClient) DeleteToDo(ctx context.Context, todoID uuid.UUID) error {
+func (c *TodosClient) DeleteToDo(ctx context.Context, todoID uuid.UUID, caller *models.Caller) error {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "client.DeleteToDo")
 	defer span.Finish()
 
 	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
 	_, err := c.client.DeleteTodo(ctx, &todo.TodoID{
-		Id: todoID.String(),
+		Id:                     todoID.String(),
+		CurrentUserId:          int32(caller.UserID),
+		CurrentUserPermissions: caller.Permissions,
+	})
+	if err != nil {
+		return fmt.Errorf("[DeleteToDo] delete: %w", fromGRPCError(err))
+	}
+
+	return nil
+}
+
+func (c *TodosClient) CreateSavedFilter(ctx context.Context, newFilter *models.SavedFilterDTO) (*models.SavedFilterDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.CreateSavedFilter")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	filter, err := c.client.CreateSavedFilter(ctx, newFilter.ToGRPC())
+	if err != nil {
+		return nil, fmt.Errorf("[CreateSavedFilter] create: %w", fromGRPCError(err))
+	}
+
+	response, err := models.NewEmptySavedFilterDTO().FromGRPC(filter)
+	if err != nil {
+		return nil, fmt.Errorf("[CreateSavedFilter] get dto from grpc: %w", err)
+	}
+
+	return response, nil
+}
+
+func (c *TodosClient) UpdateSavedFilter(ctx context.Context, newFilter *models.SavedFilterDTO) (*models.SavedFilterDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.UpdateSavedFilter")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	filter, err := c.client.UpdateSavedFilter(ctx, newFilter.ToGRPC())
+	if err != nil {
+		return nil, fmt.Errorf("[UpdateSavedFilter] update: %w", fromGRPCError(err))
+	}
+
+	response, err := models.NewEmptySavedFilterDTO().FromGRPC(filter)
+	if err != nil {
+		return nil, fmt.Errorf("[UpdateSavedFilter] get dto from grpc: %w", err)
+	}
+
+	return response, nil
+}
+
+func (c *TodosClient) DeleteSavedFilter(ctx context.Context, filterID uuid.UUID, currentUserID int) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.DeleteSavedFilter")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	_, err := c.client.DeleteSavedFilter(ctx, &todo.SavedFilterRequest{
+		Id:            filterID.String(),
+		CurrentUserId: int32(currentUserID),
+	})
+	if err != nil {
+		return fmt.Errorf("[DeleteSavedFilter] delete: %w", fromGRPCError(err))
+	}
+
+	return nil
+}
+
+func (c *TodosClient) GetSavedFilters(ctx context.Context, caller *models.Caller, timezone string) ([]models.SavedFilterDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.GetSavedFilters")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	filters, err := c.client.GetSavedFilters(ctx, &todo.GetSavedFiltersRequest{
+		CurrentUserId:          int32(caller.UserID),
+		Timezone:               timezone,
+		CurrentUserPermissions: caller.Permissions,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("[GetSavedFilters] get: %w", fromGRPCError(err))
+	}
+
+	response, err := models.SavedFiltersFromGRPCResponse(filters)
+	if err != nil {
+		return nil, fmt.Errorf("[GetSavedFilters] get dto from grpc: %w", err)
+	}
+
+	return response, nil
+}
+
+func (c *TodosClient) RunSavedFilter(ctx context.Context, filterID uuid.UUID, caller *models.Caller, timezone string) ([]models.TodoDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.RunSavedFilter")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	storedTodos, err := c.client.RunSavedFilter(ctx, &todo.SavedFilterRequest{
+		Id:                     filterID.String(),
+		CurrentUserId:          int32(caller.UserID),
+		Timezone:               timezone,
+		CurrentUserPermissions: caller.Permissions,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("[RunSavedFilter] run: %w", fromGRPCError(err))
+	}
+
+	response, err := models.SliceFromGRPCResponse(storedTodos)
+	if err != nil {
+		return nil, fmt.Errorf("[RunSavedFilter] get dto from grpc: %w", err)
+	}
+
+	return response, nil
+}
+
+func (c *TodosClient) CreateShareLink(ctx context.Context, todoID uuid.UUID, currentUserID int, request *models.CreateShareLinkDTO) (*models.ShareLinkDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.CreateShareLink")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	link, err := c.client.CreateShareLink(ctx, &todo.CreateShareLinkRequest{
+		TodoId:           todoID.String(),
+		CurrentUserId:    int32(currentUserID),
+		ExpiresInSeconds: request.ExpiresIn,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("[CreateShareLink] create: %w", fromGRPCError(err))
+	}
+
+	response, err := models.NewEmptyShareLinkDTO().FromGRPC(link)
+	if err != nil {
+		return nil, fmt.Errorf("[CreateShareLink] get dto from grpc: %w", err)
+	}
+
+	return response, nil
+}
+
+func (c *TodosClient) GetShareLinks(ctx context.Context, todoID uuid.UUID, currentUserID int) ([]models.ShareLinkDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.GetShareLinks")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	links, err := c.client.GetShareLinks(ctx, &todo.ShareLinksRequest{
+		TodoId:        todoID.String(),
+		CurrentUserId: int32(currentUserID),
 	})
 	if err != nil {
-		return fmt.Errorf("[DeleteToDo] delete: %w", err)
+		return nil, fmt.Errorf("[GetShareLinks] get: %w", fromGRPCError(err))
+	}
+
+	response, err := models.ShareLinksFromGRPCResponse(links)
+	if err != nil {
+		return nil, fmt.Errorf("[GetShareLinks] get dto from grpc: %w", err)
+	}
+
+	return response, nil
+}
+
+func (c *TodosClient) RevokeShareLink(ctx context.Context, linkID uuid.UUID, currentUserID int) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.RevokeShareLink")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	_, err := c.client.RevokeShareLink(ctx, &todo.ShareLinkRequest{
+		Id:            linkID.String(),
+		CurrentUserId: int32(currentUserID),
+	})
+	if err != nil {
+		return fmt.Errorf("[RevokeShareLink] revoke: %w", fromGRPCError(err))
 	}
 
 	return nil
 }
+
+func (c *TodosClient) GetSharedTodo(ctx context.Context, token string) (*models.SharedTodoDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.GetSharedTodo")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	sharedTodo, err := c.client.GetSharedTodo(ctx, &todo.SharedTodoRequest{
+		Token: token,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("[GetSharedTodo] get: %w", fromGRPCError(err))
+	}
+
+	return models.SharedTodoFromGRPC(sharedTodo), nil
+}
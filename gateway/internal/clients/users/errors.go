@@ -0,0 +1,120 @@
+package users
+
+import (
+	"fmt"
+	"time"
+
+	"gateway/internal/app_errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
+)
+
+// errorReasons - причины из деталей gRPC статуса сервиса users и соответствующие им ошибки gateway
+var errorReasons = map[string]error{
+	"NOT_FOUND":                      app_errors.ErrNotFound,
+	"WRONG_CREDENTIALS":              app_errors.ErrWrongCredentials,
+	"INVALID_VERIFICATION_TOKEN":     app_errors.ErrInvalidVerificationToken,
+	"EMAIL_NOT_VERIFIED":             app_errors.ErrEmailNotVerified,
+	"INVALID_PASSWORD_RESET_TOKEN":   app_errors.ErrInvalidPasswordResetToken,
+	"PASSWORD_CONFIRMATION_MISMATCH": app_errors.ErrPassAndConfirmationDoesNotMatch,
+	"SESSION_NOT_FOUND":              app_errors.ErrSessionNotFound,
+	"REFRESH_TOKEN_REUSED":           app_errors.ErrRefreshTokenReused,
+	"TWO_FACTOR_NOT_ENABLED":         app_errors.ErrTwoFactorNotEnabled,
+	"TWO_FACTOR_ALREADY_ENABLED":     app_errors.ErrTwoFactorAlreadyEnabled,
+	"INVALID_TWO_FACTOR_CODE":        app_errors.ErrInvalidTwoFactorCode,
+	"INVALID_TWO_FACTOR_CHALLENGE":   app_errors.ErrInvalidTwoFactorChallenge,
+	"TWO_FACTOR_REQUIRED_BY_ROLE":    app_errors.ErrTwoFactorRequiredByRole,
+	"LOGIN_THROTTLED":                app_errors.ErrLoginThrottled,
+	"ACCOUNT_LOCKED":                 app_errors.ErrAccountLocked,
+	"UNKNOWN_ROLE":                   app_errors.ErrUnknownRole,
+	"EXTERNAL_EMAIL_NOT_VERIFIED":    app_errors.ErrExternalEmailNotVerified,
+	"EXTERNAL_EMAIL_CONFLICT":        app_errors.ErrExternalEmailConflict,
+	"INVALID_SORT":                   app_errors.ErrInvalidSort,
+	"API_TOKEN_NOT_FOUND":            app_errors.ErrNotFound,
+	"INVALID_API_TOKEN":              app_errors.ErrInvalidApiToken,
+	"API_TOKEN_NAME_INVALID":         app_errors.ErrApiTokenNameInvalid,
+	"UNKNOWN_API_TOKEN_SCOPE":        app_errors.ErrUnknownApiTokenScope,
+	"API_TOKEN_TTL_INVALID":          app_errors.ErrApiTokenTTLInvalid,
+	"TOO_MANY_API_TOKENS":            app_errors.ErrTooManyApiTokens,
+	"ORGANIZATION_NOT_FOUND":         app_errors.ErrNotFound,
+	"ORGANIZATION_NAME_INVALID":      app_errors.ErrOrganizationNameInvalid,
+	"ORGANIZATION_FORBIDDEN":         app_errors.ErrForbidden,
+	"UNKNOWN_ORGANIZATION_ROLE":      app_errors.ErrUnknownOrganizationRole,
+	"LAST_ORGANIZATION_OWNER":        app_errors.ErrLastOrganizationOwner,
+	"MEMBER_NOT_FOUND":               app_errors.ErrNotFound,
+	"ALREADY_MEMBER":                 app_errors.ErrAlreadyMember,
+	"INVALID_EMAIL":                  app_errors.ErrInvalidEmail,
+	"INVALID_INVITATION":             app_errors.ErrInvalidInvitation,
+	"INVITATION_EMAIL_MISMATCH":      app_errors.ErrInvitationEmailMismatch,
+	"TEAM_NOT_FOUND":                 app_errors.ErrNotFound,
+	"TEAM_NAME_INVALID":              app_errors.ErrTeamNameInvalid,
+	"TEAM_NAME_IS_USED":              app_errors.ErrTeamNameIsUsed,
+	"USERNAME_OR_EMAIL_IS_USED":      app_errors.ErrUsernameOrEmailIsUsed,
+	"USERNAME_INVALID":               app_errors.ErrUsernameInvalid,
+	"DISPLAY_NAME_INVALID":           app_errors.ErrDisplayNameInvalid,
+	"UNKNOWN_TIMEZONE":               app_errors.ErrUnknownTimezone,
+	"UNKNOWN_LOCALE":                 app_errors.ErrUnknownLocale,
+	"INVALID_AVATAR":                 app_errors.ErrInvalidAvatar,
+	"AVATAR_TOO_LARGE":               app_errors.ErrAvatarTooLarge,
+	"UNKNOWN_NOTIFICATION_EVENT":     app_errors.ErrUnknownNotificationEvent,
+	"UNKNOWN_NOTIFICATION_CHANNEL":   app_errors.ErrUnknownNotificationChannel,
+	"EMAIL_CHANGE_THROTTLED":         app_errors.ErrEmailChangeThrottled,
+	"INVALID_EMAIL_CHANGE_TOKEN":     app_errors.ErrInvalidEmailChangeToken,
+	"EMAIL_UNCHANGED":                app_errors.ErrEmailUnchanged,
+	"EMAIL_CHANGE_REQUIRED":          app_errors.ErrEmailChangeRequired,
+	"USER_DELETION_IN_PROGRESS":      app_errors.ErrUserDeletionInProgress,
+	"USER_DELETION_NOT_FOUND":        app_errors.ErrNotFound,
+	"DATA_EXPORT_THROTTLED":          app_errors.ErrDataExportThrottled,
+	"DATA_EXPORT_NOT_FOUND":          app_errors.ErrNotFound,
+	"INVALID_DATA_EXPORT_TOKEN":      app_errors.ErrInvalidDataExportToken,
+	"ERASURE_THROTTLED":              app_errors.ErrErasureThrottled,
+	"INVALID_ERASURE_TOKEN":          app_errors.ErrInvalidErasureToken,
+	"INCORRECT_OLD_PASSWORD":         app_errors.ErrIncorrectOldPassword,
+}
+
+// fromGRPCError восстанавливает из деталей gRPC статуса ошибки, которые gateway умеет показывать пользователю.
+// Если в статусе есть RetryInfo, ошибка оборачивается в RetryAfterError, а ошибки по полям из BadRequest
+// возвращаются как ValidationError
+func fromGRPCError(err error) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+
+	var (
+		target     error
+		retryAfter time.Duration
+		fields     []app_errors.FieldViolation
+	)
+	for _, detail := range st.Details() {
+		switch detail := detail.(type) {
+		case *errdetails.ErrorInfo:
+			if reasonErr, ok := errorReasons[detail.Reason]; ok {
+				target = reasonErr
+			}
+		case *errdetails.RetryInfo:
+			retryAfter = detail.GetRetryDelay().AsDuration()
+		case *errdetails.BadRequest:
+			for _, violation := range detail.GetFieldViolations() {
+				fields = append(fields, app_errors.FieldViolation{
+					Field:   violation.GetField(),
+					Message: violation.GetDescription(),
+				})
+			}
+		}
+	}
+
+	if len(fields) > 0 {
+		return &app_errors.ValidationError{Message: st.Message(), Fields: fields}
+	}
+
+	if target == nil {
+		return err
+	}
+
+	if retryAfter > 0 {
+		return fmt.Errorf("%w: %s", &app_errors.RetryAfterError{Err: target, RetryAfter: retryAfter}, st.Message())
+	}
+
+	return fmt.Errorf("%w: %s", target, st.Message())
+}
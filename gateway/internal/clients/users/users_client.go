@@ -7,11 +7,16 @@ import (
 	"gateway/internal/models"
 	"gateway/pkg/ctxutil"
 	"gateway/pkg/grpc_stubs/users"
+	"github.com/google/uuid"
 	"github.com/opentracing/opentracing-go"
 	"github.com/rs/zerolog"
 	"google.golang.org/grpc"
+	"time"
 )
 
+// maxDataExportArchiveSize - архив выгрузки может не поместиться в лимит gRPC ответа по умолчанию в 4MB
+const maxDataExportArchiveSize = 64 << 20
+
 type UsersClient struct {
 	client users.UserServiceClient
 }
@@ -42,27 +47,30 @@ func (c *UsersClient) CreateUser(ctx context.Context, user *models.CreateUserDTO
 	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
 	res, err := c.client.RegisterUser(ctx, user.ToGRPC())
 	if err != nil {
-		return 0, err
+		return 0, fromGRPCError(err)
 	}
 
 	return int(res.Id), nil
 }
 
-func (c *UsersClient) UpdateUser(ctx context.Context, user *models.UserDTO) error {
+func (c *UsersClient) UpdateUser(ctx context.Context, user *models.UserDTO) (*models.UserDTO, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "client.UpdateUser")
 	defer span.Finish()
 
 	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
-	_, err := c.client.UpdateUser(ctx, &users.UserDTO{
-		Id:       int32(user.ID),
-		Username: user.Username,
-		Email:    user.Email,
+	updatedUser, err := c.client.UpdateUser(ctx, &users.UserDTO{
+		Id:          int32(user.ID),
+		Username:    user.Username,
+		Email:       user.Email,
+		DisplayName: user.DisplayName,
+		Timezone:    user.Timezone,
+		Locale:      user.Locale,
 	})
 	if err != nil {
-		return err
+		return nil, fromGRPCError(err)
 	}
 
-	return nil
+	return models.NewEmptyUserDTO().FromGRPC(updatedUser), nil
 }
 
 func (c *UsersClient) UpdatePassword(ctx context.Context, data *models.UpdateUserPasswordDTO) error {
@@ -72,7 +80,7 @@ func (c *UsersClient) UpdatePassword(ctx context.Context, data *models.UpdateUse
 	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
 	_, err := c.client.UpdatePassword(ctx, data.ToGRPC())
 	if err != nil {
-		return err
+		return fromGRPCError(err)
 	}
 
 	return nil
@@ -87,12 +95,27 @@ func (c *UsersClient) DeleteUser(ctx context.Context, userID int) error {
 		Id: int32(userID),
 	})
 	if err != nil {
-		return err
+		return fromGRPCError(err)
 	}
 
 	return nil
 }
 
+func (c *UsersClient) GetUserDeletion(ctx context.Context, userID int) (*models.UserDeletionDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.GetUserDeletion")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	deletion, err := c.client.GetUserDeletion(ctx, &users.UserID{
+		Id: int32(userID),
+	})
+	if err != nil {
+		return nil, fromGRPCError(err)
+	}
+
+	return models.NewEmptyUserDeletionDTO().FromGRPC(deletion)
+}
+
 func (c *UsersClient) GetUserByID(ctx context.Context, userID int) (*models.UserDTO, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "client.GetUserByID")
 	defer span.Finish()
@@ -102,7 +125,7 @@ func (c *UsersClient) GetUserByID(ctx context.Context, userID int) (*models.User
 		Id: int32(userID),
 	})
 	if err != nil {
-		return nil, err
+		return nil, fromGRPCError(err)
 	}
 
 	return models.NewEmptyUserDTO().FromGRPC(user), nil
@@ -118,12 +141,26 @@ func (c *UsersClient) GetUserByUsernameOrEmail(ctx context.Context, username, em
 		Email:    email,
 	})
 	if err != nil {
-		return nil, err
+		return nil, fromGRPCError(err)
 	}
 
 	return models.NewEmptyUserDTO().FromGRPC(user), nil
 }
 
+// ListUsers ищет пользователей постранично по началу username или email
+func (c *UsersClient) ListUsers(ctx context.Context, filter *models.ListUsersDTO) (*models.UsersPageDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.ListUsers")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	response, err := c.client.ListUsers(ctx, filter.ToGRPC())
+	if err != nil {
+		return nil, fromGRPCError(err)
+	}
+
+	return models.UsersPageFromGRPC(response), nil
+}
+
 func (c *UsersClient) GetUserByUsername(ctx context.Context, username string) (*models.UserDTO, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "client.GetUserByUsername")
 	defer span.Finish()
@@ -133,7 +170,7 @@ func (c *UsersClient) GetUserByUsername(ctx context.Context, username string) (*
 		Username: username,
 	})
 	if err != nil {
-		return nil, err
+		return nil, fromGRPCError(err)
 	}
 
 	return models.NewEmptyUserDTO().FromGRPC(user), nil
@@ -146,8 +183,688 @@ func (c *UsersClient) UserLogin(ctx context.Context, data *models.UserLoginDTO)
 	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
 	user, err := c.client.Login(ctx, data.ToGRPC())
 	if err != nil {
-		return nil, err
+		return nil, fromGRPCError(err)
+	}
+
+	return models.NewEmptyUserDTO().FromGRPC(user), nil
+}
+
+func (c *UsersClient) VerifyEmail(ctx context.Context, token string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.VerifyEmail")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	_, err := c.client.VerifyEmail(ctx, &users.VerifyEmailRequest{
+		Token: token,
+	})
+	if err != nil {
+		return fromGRPCError(err)
+	}
+
+	return nil
+}
+
+func (c *UsersClient) RequestEmailChange(ctx context.Context, userID int, request *models.RequestEmailChangeDTO) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.RequestEmailChange")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	_, err := c.client.RequestEmailChange(ctx, request.ToGRPC(userID))
+	if err != nil {
+		return fromGRPCError(err)
+	}
+
+	return nil
+}
+
+func (c *UsersClient) ConfirmEmailChange(ctx context.Context, token string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.ConfirmEmailChange")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	_, err := c.client.ConfirmEmailChange(ctx, &users.ConfirmEmailChangeRequest{
+		Token: token,
+	})
+	if err != nil {
+		return fromGRPCError(err)
+	}
+
+	return nil
+}
+
+func (c *UsersClient) RequestDataExport(ctx context.Context, userID int) (*models.DataExportDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.RequestDataExport")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	export, err := c.client.RequestDataExport(ctx, &users.UserID{
+		Id: int32(userID),
+	})
+	if err != nil {
+		return nil, fromGRPCError(err)
+	}
+
+	return models.NewEmptyDataExportDTO().FromGRPC(export)
+}
+
+func (c *UsersClient) GetDataExport(ctx context.Context, userID int) (*models.DataExportDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.GetDataExport")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	export, err := c.client.GetDataExport(ctx, &users.UserID{
+		Id: int32(userID),
+	})
+	if err != nil {
+		return nil, fromGRPCError(err)
+	}
+
+	return models.NewEmptyDataExportDTO().FromGRPC(export)
+}
+
+func (c *UsersClient) DownloadDataExport(ctx context.Context, token string) (*models.DataExportArchive, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.DownloadDataExport")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	archive, err := c.client.DownloadDataExport(ctx, &users.DataExportDownloadRequest{
+		Token: token,
+	}, grpc.MaxCallRecvMsgSize(maxDataExportArchiveSize))
+	if err != nil {
+		return nil, fromGRPCError(err)
+	}
+
+	return &models.DataExportArchive{
+		FileName: archive.FileName,
+		Content:  archive.Content,
+	}, nil
+}
+
+func (c *UsersClient) RequestErasure(ctx context.Context, userID int, request *models.RequestErasureDTO) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.RequestErasure")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	_, err := c.client.RequestErasure(ctx, request.ToGRPC(userID))
+	if err != nil {
+		return fromGRPCError(err)
+	}
+
+	return nil
+}
+
+func (c *UsersClient) ConfirmErasure(ctx context.Context, token string) (*models.UserDeletionDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.ConfirmErasure")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	deletion, err := c.client.ConfirmErasure(ctx, &users.ConfirmErasureRequest{
+		Token: token,
+	})
+	if err != nil {
+		return nil, fromGRPCError(err)
+	}
+
+	return models.NewEmptyUserDeletionDTO().FromGRPC(deletion)
+}
+
+func (c *UsersClient) ResendVerificationEmail(ctx context.Context, email string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.ResendVerificationEmail")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	_, err := c.client.ResendVerificationEmail(ctx, &users.ResendVerificationEmailRequest{
+		Email: email,
+	})
+	if err != nil {
+		return fromGRPCError(err)
+	}
+
+	return nil
+}
+
+func (c *UsersClient) RequestPasswordReset(ctx context.Context, email string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.RequestPasswordReset")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	_, err := c.client.RequestPasswordReset(ctx, &users.RequestPasswordResetRequest{
+		Email: email,
+	})
+	if err != nil {
+		return fromGRPCError(err)
+	}
+
+	return nil
+}
+
+func (c *UsersClient) ResetPassword(ctx context.Context, request *models.ResetPasswordDTO) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.ResetPassword")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	_, err := c.client.ResetPassword(ctx, request.ToGRPC())
+	if err != nil {
+		return fromGRPCError(err)
+	}
+
+	return nil
+}
+
+// CreateSession заводит сессию пользователя с первым refresh токеном и возвращает ее идентификатор
+func (c *UsersClient) CreateSession(ctx context.Context, userID int, refreshTokenID uuid.UUID, ttl time.Duration) (uuid.UUID, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.CreateSession")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	session, err := c.client.CreateSession(ctx, &users.SessionDTO{
+		UserId:           int32(userID),
+		RefreshTokenId:   refreshTokenID.String(),
+		ExpiresInSeconds: int64(ttl.Seconds()),
+	})
+	if err != nil {
+		return uuid.Nil, fromGRPCError(err)
+	}
+
+	sessionID, err := uuid.Parse(session.Id)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("[CreateSession] wrong session uuid: %w", err)
+	}
+
+	return sessionID, nil
+}
+
+// RotateSession заменяет refresh токен сессии на новый
+func (c *UsersClient) RotateSession(ctx context.Context, sessionID, refreshTokenID, newRefreshTokenID uuid.UUID, ttl time.Duration) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.RotateSession")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	_, err := c.client.RotateSession(ctx, &users.RotateSessionRequest{
+		Id:                sessionID.String(),
+		RefreshTokenId:    refreshTokenID.String(),
+		NewRefreshTokenId: newRefreshTokenID.String(),
+		ExpiresInSeconds:  int64(ttl.Seconds()),
+	})
+	if err != nil {
+		return fromGRPCError(err)
+	}
+
+	return nil
+}
+
+func (c *UsersClient) RevokeSession(ctx context.Context, sessionID uuid.UUID, userID int) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.RevokeSession")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	_, err := c.client.RevokeSession(ctx, &users.SessionRequest{
+		Id:     sessionID.String(),
+		UserId: int32(userID),
+	})
+	if err != nil {
+		return fromGRPCError(err)
+	}
+
+	return nil
+}
+
+func (c *UsersClient) RevokeUserSessions(ctx context.Context, userID int) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.RevokeUserSessions")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	_, err := c.client.RevokeUserSessions(ctx, &users.UserID{
+		Id: int32(userID),
+	})
+	if err != nil {
+		return fromGRPCError(err)
+	}
+
+	return nil
+}
+
+// LoginTwoFactor завершает вход кодом второго фактора по токену, выданному UserLogin
+func (c *UsersClient) LoginTwoFactor(ctx context.Context, request *models.TwoFactorLoginDTO) (*models.UserDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.LoginTwoFactor")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	user, err := c.client.LoginTwoFactor(ctx, request.ToGRPC())
+	if err != nil {
+		return nil, fromGRPCError(err)
+	}
+
+	return models.NewEmptyUserDTO().FromGRPC(user), nil
+}
+
+// LoginExternal входит пользователем, которого подтвердил провайдер OpenID Connect, при необходимости создавая его
+func (c *UsersClient) LoginExternal(ctx context.Context, login *models.ExternalLoginDTO) (*models.UserDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.LoginExternal")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	user, err := c.client.LoginExternal(ctx, login.ToGRPC())
+	if err != nil {
+		return nil, fromGRPCError(err)
+	}
+
+	return models.NewEmptyUserDTO().FromGRPC(user), nil
+}
+
+func (c *UsersClient) EnrollTwoFactor(ctx context.Context, userID int) (*models.TwoFactorEnrollmentDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.EnrollTwoFactor")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	enrollment, err := c.client.EnrollTwoFactor(ctx, &users.UserID{
+		Id: int32(userID),
+	})
+	if err != nil {
+		return nil, fromGRPCError(err)
+	}
+
+	return new(models.TwoFactorEnrollmentDTO).FromGRPC(enrollment), nil
+}
+
+func (c *UsersClient) ConfirmTwoFactor(ctx context.Context, userID int, code string) (*models.RecoveryCodesDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.ConfirmTwoFactor")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	codes, err := c.client.ConfirmTwoFactor(ctx, &users.TwoFactorCodeRequest{
+		UserId: int32(userID),
+		Code:   code,
+	})
+	if err != nil {
+		return nil, fromGRPCError(err)
+	}
+
+	return &models.RecoveryCodesDTO{Codes: codes.Codes}, nil
+}
+
+func (c *UsersClient) DisableTwoFactor(ctx context.Context, userID int, code string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.DisableTwoFactor")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	_, err := c.client.DisableTwoFactor(ctx, &users.TwoFactorCodeRequest{
+		UserId: int32(userID),
+		Code:   code,
+	})
+	if err != nil {
+		return fromGRPCError(err)
+	}
+
+	return nil
+}
+
+func (c *UsersClient) RegenerateRecoveryCodes(ctx context.Context, userID int, code string) (*models.RecoveryCodesDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.RegenerateRecoveryCodes")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	codes, err := c.client.RegenerateRecoveryCodes(ctx, &users.TwoFactorCodeRequest{
+		UserId: int32(userID),
+		Code:   code,
+	})
+	if err != nil {
+		return nil, fromGRPCError(err)
+	}
+
+	return &models.RecoveryCodesDTO{Codes: codes.Codes}, nil
+}
+
+func (c *UsersClient) UnlockUser(ctx context.Context, userID int) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.UnlockUser")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	_, err := c.client.UnlockUser(ctx, &users.UserID{
+		Id: int32(userID),
+	})
+	if err != nil {
+		return fromGRPCError(err)
+	}
+
+	return nil
+}
+
+func (c *UsersClient) SetUserRole(ctx context.Context, userID int, role string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.SetUserRole")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	_, err := c.client.SetUserRole(ctx, &users.SetUserRoleRequest{
+		UserId: int32(userID),
+		Role:   role,
+	})
+	if err != nil {
+		return fromGRPCError(err)
+	}
+
+	return nil
+}
+
+// CreateApiToken выпускает персональный токен доступа пользователю
+func (c *UsersClient) CreateApiToken(ctx context.Context, userID int, request *models.CreateApiTokenDTO) (*models.ApiTokenDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.CreateApiToken")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	token, err := c.client.CreateApiToken(ctx, request.ToGRPC(userID))
+	if err != nil {
+		return nil, fromGRPCError(err)
+	}
+
+	return models.NewEmptyApiTokenDTO().FromGRPC(token)
+}
+
+func (c *UsersClient) GetApiTokens(ctx context.Context, userID int) ([]models.ApiTokenDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.GetApiTokens")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	tokens, err := c.client.GetApiTokens(ctx, &users.UserID{
+		Id: int32(userID),
+	})
+	if err != nil {
+		return nil, fromGRPCError(err)
+	}
+
+	return models.ApiTokensFromGRPCResponse(tokens)
+}
+
+func (c *UsersClient) RevokeApiToken(ctx context.Context, tokenID uuid.UUID, userID int) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.RevokeApiToken")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	_, err := c.client.RevokeApiToken(ctx, &users.ApiTokenRequest{
+		Id:     tokenID.String(),
+		UserId: int32(userID),
+	})
+	if err != nil {
+		return fromGRPCError(err)
+	}
+
+	return nil
+}
+
+// AuthenticateApiToken проверяет персональный токен из заголовка Authorization
+func (c *UsersClient) AuthenticateApiToken(ctx context.Context, token string) (*models.ApiTokenAuthDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.AuthenticateApiToken")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	auth, err := c.client.AuthenticateApiToken(ctx, &users.AuthenticateApiTokenRequest{
+		Token: token,
+	})
+	if err != nil {
+		return nil, fromGRPCError(err)
+	}
+
+	return new(models.ApiTokenAuthDTO).FromGRPC(auth)
+}
+
+func (c *UsersClient) CreateOrganization(ctx context.Context, userID int, request *models.CreateOrganizationDTO) (*models.OrganizationDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.CreateOrganization")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	organization, err := c.client.CreateOrganization(ctx, &users.CreateOrganizationRequest{
+		UserId: int32(userID),
+		Name:   request.Name,
+	})
+	if err != nil {
+		return nil, fromGRPCError(err)
+	}
+
+	return models.NewEmptyOrganizationDTO().FromGRPC(organization), nil
+}
+
+func (c *UsersClient) GetUserOrganizations(ctx context.Context, userID int) ([]models.OrganizationDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.GetUserOrganizations")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	organizations, err := c.client.GetUserOrganizations(ctx, &users.UserID{
+		Id: int32(userID),
+	})
+	if err != nil {
+		return nil, fromGRPCError(err)
+	}
+
+	return models.OrganizationsFromGRPCResponse(organizations), nil
+}
+
+func (c *UsersClient) ListOrganizationMembers(ctx context.Context, organizationID, userID int) ([]models.OrganizationMemberDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.ListOrganizationMembers")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	members, err := c.client.ListOrganizationMembers(ctx, &users.OrganizationRequest{
+		OrganizationId: int32(organizationID),
+		UserId:         int32(userID),
+	})
+	if err != nil {
+		return nil, fromGRPCError(err)
+	}
+
+	return models.OrganizationMembersFromGRPCResponse(members), nil
+}
+
+// InviteMember отправляет приглашение в организацию на email
+func (c *UsersClient) InviteMember(ctx context.Context, organizationID, userID int, request *models.InviteMemberDTO) (*models.InvitationDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.InviteMember")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	invitation, err := c.client.InviteMember(ctx, &users.InviteMemberRequest{
+		OrganizationId: int32(organizationID),
+		UserId:         int32(userID),
+		Email:          request.Email,
+		Role:           request.Role,
+	})
+	if err != nil {
+		return nil, fromGRPCError(err)
+	}
+
+	return new(models.InvitationDTO).FromGRPC(invitation)
+}
+
+func (c *UsersClient) AcceptInvitation(ctx context.Context, token string, userID int) (*models.OrganizationDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.AcceptInvitation")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	organization, err := c.client.AcceptInvitation(ctx, &users.AcceptInvitationRequest{
+		Token:  token,
+		UserId: int32(userID),
+	})
+	if err != nil {
+		return nil, fromGRPCError(err)
+	}
+
+	return models.NewEmptyOrganizationDTO().FromGRPC(organization), nil
+}
+
+func (c *UsersClient) SetMemberRole(ctx context.Context, organizationID, userID, memberID int, role string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.SetMemberRole")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	_, err := c.client.SetMemberRole(ctx, &users.MemberRequest{
+		OrganizationId: int32(organizationID),
+		UserId:         int32(userID),
+		MemberId:       int32(memberID),
+		Role:           role,
+	})
+	if err != nil {
+		return fromGRPCError(err)
+	}
+
+	return nil
+}
+
+func (c *UsersClient) RemoveMember(ctx context.Context, organizationID, userID, memberID int) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.RemoveMember")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	_, err := c.client.RemoveMember(ctx, &users.MemberRequest{
+		OrganizationId: int32(organizationID),
+		UserId:         int32(userID),
+		MemberId:       int32(memberID),
+	})
+	if err != nil {
+		return fromGRPCError(err)
+	}
+
+	return nil
+}
+
+func (c *UsersClient) CreateTeam(ctx context.Context, organizationID, userID int, request *models.CreateTeamDTO) (*models.TeamDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.CreateTeam")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	team, err := c.client.CreateTeam(ctx, &users.CreateTeamRequest{
+		OrganizationId: int32(organizationID),
+		UserId:         int32(userID),
+		Name:           request.Name,
+	})
+	if err != nil {
+		return nil, fromGRPCError(err)
+	}
+
+	return models.NewEmptyTeamDTO().FromGRPC(team), nil
+}
+
+func (c *UsersClient) GetTeams(ctx context.Context, organizationID, userID int) ([]models.TeamDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.GetTeams")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	teams, err := c.client.GetTeams(ctx, &users.OrganizationRequest{
+		OrganizationId: int32(organizationID),
+		UserId:         int32(userID),
+	})
+	if err != nil {
+		return nil, fromGRPCError(err)
+	}
+
+	return models.TeamsFromGRPCResponse(teams), nil
+}
+
+func (c *UsersClient) AddTeamMember(ctx context.Context, organizationID, teamID, userID, memberID int) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.AddTeamMember")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	_, err := c.client.AddTeamMember(ctx, &users.TeamMemberRequest{
+		OrganizationId: int32(organizationID),
+		TeamId:         int32(teamID),
+		UserId:         int32(userID),
+		MemberId:       int32(memberID),
+	})
+	if err != nil {
+		return fromGRPCError(err)
+	}
+
+	return nil
+}
+
+func (c *UsersClient) RemoveTeamMember(ctx context.Context, organizationID, teamID, userID, memberID int) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.RemoveTeamMember")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	_, err := c.client.RemoveTeamMember(ctx, &users.TeamMemberRequest{
+		OrganizationId: int32(organizationID),
+		TeamId:         int32(teamID),
+		UserId:         int32(userID),
+		MemberId:       int32(memberID),
+	})
+	if err != nil {
+		return fromGRPCError(err)
+	}
+
+	return nil
+}
+
+func (c *UsersClient) ListTeamMembers(ctx context.Context, organizationID, teamID, userID int) ([]models.OrganizationMemberDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.ListTeamMembers")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	members, err := c.client.ListTeamMembers(ctx, &users.TeamMemberRequest{
+		OrganizationId: int32(organizationID),
+		TeamId:         int32(teamID),
+		UserId:         int32(userID),
+	})
+	if err != nil {
+		return nil, fromGRPCError(err)
+	}
+
+	return models.OrganizationMembersFromGRPCResponse(members), nil
+}
+
+func (c *UsersClient) UploadAvatar(ctx context.Context, userID int, image []byte) (*models.UserDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.UploadAvatar")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	user, err := c.client.UploadAvatar(ctx, &users.UploadAvatarRequest{
+		UserId: int32(userID),
+		Image:  image,
+	})
+	if err != nil {
+		return nil, fromGRPCError(err)
+	}
+
+	return models.NewEmptyUserDTO().FromGRPC(user), nil
+}
+
+func (c *UsersClient) DeleteAvatar(ctx context.Context, userID int) (*models.UserDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.DeleteAvatar")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	user, err := c.client.DeleteAvatar(ctx, &users.UserID{
+		Id: int32(userID),
+	})
+	if err != nil {
+		return nil, fromGRPCError(err)
 	}
 
 	return models.NewEmptyUserDTO().FromGRPC(user), nil
 }
+
+func (c *UsersClient) GetNotificationPreferences(ctx context.Context, userID int) (*models.NotificationPreferencesDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.GetNotificationPreferences")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	preferences, err := c.client.GetNotificationPreferences(ctx, &users.UserID{
+		Id: int32(userID),
+	})
+	if err != nil {
+		return nil, fromGRPCError(err)
+	}
+
+	return models.NewEmptyNotificationPreferencesDTO().FromGRPC(preferences), nil
+}
+
+func (c *UsersClient) UpdateNotificationPreferences(ctx context.Context, userID int, request *models.NotificationPreferencesDTO) (*models.NotificationPreferencesDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.UpdateNotificationPreferences")
+	defer span.Finish()
+
+	ctx = ctxutil.SetRequestIdFromContextToGrpc(ctx)
+	preferences, err := c.client.UpdateNotificationPreferences(ctx, request.ToGRPC(userID))
+	if err != nil {
+		return nil, fromGRPCError(err)
+	}
+
+	return models.NewEmptyNotificationPreferencesDTO().FromGRPC(preferences), nil
+}
@@ -0,0 +1,128 @@
+package models
+
+import (
+	"fmt"
+	"gateway/pkg/grpc_stubs/users"
+	"github.com/google/uuid"
+	"time"
+)
+
+// ApiTokenPrefix - начало каждого персонального токена, по нему токен отличается от JWT
+const ApiTokenPrefix = "todo_pat_"
+
+// Области действия персонального токена. Список областей и их проверка при выпуске - в сервисе users,
+// здесь они нужны, чтобы решить, какие маршруты доступны с токеном
+const (
+	// ApiTokenScopeReadTodos - читать свои задачи и фильтры
+	ApiTokenScopeReadTodos = "read:todos"
+	// ApiTokenScopeWriteTodos - создавать, изменять и удалять свои задачи, фильтры и публичные ссылки
+	ApiTokenScopeWriteTodos = "write:todos"
+	// ApiTokenScopeReadProfile - читать профили пользователей
+	ApiTokenScopeReadProfile = "read:profile"
+	// ApiTokenScopeWriteProfile - изменять свой профиль. Пароль, второй фактор и токены так не изменить
+	ApiTokenScopeWriteProfile = "write:profile"
+	// ApiTokenScopeAdmin - токен получает разрешения роли владельца
+	ApiTokenScopeAdmin = "admin"
+)
+
+// CreateApiTokenDTO - струтктура запроса на выпуск персонального токена
+type CreateApiTokenDTO struct {
+	Name   string   `json:"name" example:"ci"`
+	Scopes []string `json:"scopes" example:"read:todos"`
+	// ExpiresIn - срок действия токена в секундах, 0 - срок по умолчанию
+	ExpiresIn int64 `json:"expires_in" example:"2592000"`
+}
+
+// ApiTokenDTO - персональный токен. Token заполнен только в ответ на создание
+type ApiTokenDTO struct {
+	ID         uuid.UUID  `json:"id" example:"c0e708fa-a7df-4d9f-a1b8-a3bfe63c433c"`
+	Name       string     `json:"name" example:"ci"`
+	Scopes     []string   `json:"scopes" example:"read:todos"`
+	Token      string     `json:"token,omitempty" example:"todo_pat_kq3v0XxkV4m2QH2nV3bq3z4S4Xk2t0tY5tEx5m7YzWc"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// ApiTokenAuthDTO - владелец предъявленного токена и то, что токен ему разрешает
+type ApiTokenAuthDTO struct {
+	UserID      int
+	TokenID     uuid.UUID
+	Scopes      []string
+	Permissions []string
+	// Timezone - часовой пояс владельца токена
+	Timezone string
+}
+
+func (d *CreateApiTokenDTO) ToGRPC(userID int) *users.CreateApiTokenRequest {
+	return &users.CreateApiTokenRequest{
+		UserId:           int32(userID),
+		Name:             d.Name,
+		Scopes:           d.Scopes,
+		ExpiresInSeconds: d.ExpiresIn,
+	}
+}
+
+func NewEmptyApiTokenDTO() *ApiTokenDTO {
+	return &ApiTokenDTO{}
+}
+
+func (d *ApiTokenDTO) FromGRPC(in *users.ApiTokenDTO) (*ApiTokenDTO, error) {
+	id, err := uuid.Parse(in.Id)
+	if err != nil {
+		return nil, fmt.Errorf("[FromGRPC] wrong uuid: %w", err)
+	}
+
+	d.ID = id
+	d.Name = in.Name
+	d.Scopes = in.Scopes
+	d.Token = in.Token
+	d.ExpiresAt = in.ExpiresAt.AsTime()
+	d.CreatedAt = in.CreatedAt.AsTime()
+
+	if in.LastUsedAt != nil {
+		lastUsedAt := in.LastUsedAt.AsTime()
+		d.LastUsedAt = &lastUsedAt
+	}
+
+	return d, nil
+}
+
+func ApiTokensFromGRPCResponse(response *users.ApiTokens) ([]ApiTokenDTO, error) {
+	var dtoSlice = make([]ApiTokenDTO, len(response.Items))
+
+	for i := range response.Items {
+		token, err := NewEmptyApiTokenDTO().FromGRPC(response.Items[i])
+		if err != nil {
+			return nil, fmt.Errorf("[ApiTokensFromGRPCResponse] %w", err)
+		}
+		dtoSlice[i] = *token
+	}
+
+	return dtoSlice, nil
+}
+
+func (d *ApiTokenAuthDTO) FromGRPC(in *users.ApiTokenAuth) (*ApiTokenAuthDTO, error) {
+	tokenID, err := uuid.Parse(in.TokenId)
+	if err != nil {
+		return nil, fmt.Errorf("[FromGRPC] wrong token uuid: %w", err)
+	}
+
+	d.UserID = int(in.UserId)
+	d.TokenID = tokenID
+	d.Scopes = in.Scopes
+	d.Permissions = in.Permissions
+	d.Timezone = in.Timezone
+	return d, nil
+}
+
+// HasScope сообщает, выдана ли токену область
+func (d *ApiTokenAuthDTO) HasScope(scope string) bool {
+	for _, item := range d.Scopes {
+		if item == scope {
+			return true
+		}
+	}
+
+	return false
+}
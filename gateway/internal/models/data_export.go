@@ -0,0 +1,85 @@
+package models
+
+import (
+	"fmt"
+	"gateway/pkg/grpc_stubs/users"
+	"github.com/google/uuid"
+	"time"
+)
+
+// DataExportPartDTO - часть выгрузки от одного из сервисов
+type DataExportPartDTO struct {
+	Service   string    `json:"service" example:"todo"`
+	Status    string    `json:"status" example:"received"`
+	Error     string    `json:"error,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// DataExportDTO - состояние выгрузки данных пользователя: pending, пока не ответили все сервисы,
+// ready, когда архив собран и ссылка отправлена на email, или failed
+type DataExportDTO struct {
+	ID        uuid.UUID           `json:"id" example:"c0e708fa-a7df-4d9f-a1b8-a3bfe63c433c"`
+	UserID    int                 `json:"user_id" example:"1"`
+	Status    string              `json:"status" example:"pending"`
+	Error     string              `json:"error,omitempty"`
+	Parts     []DataExportPartDTO `json:"parts"`
+	CreatedAt time.Time           `json:"created_at"`
+	UpdatedAt time.Time           `json:"updated_at"`
+	ExpiresAt *time.Time          `json:"expires_at,omitempty"`
+}
+
+func NewEmptyDataExportDTO() *DataExportDTO {
+	return &DataExportDTO{}
+}
+
+func (d *DataExportDTO) FromGRPC(in *users.DataExport) (*DataExportDTO, error) {
+	id, err := uuid.Parse(in.Id)
+	if err != nil {
+		return nil, fmt.Errorf("[FromGRPC] wrong uuid: %w", err)
+	}
+
+	d.ID = id
+	d.UserID = int(in.UserId)
+	d.Status = in.Status
+	d.Error = in.Error
+	d.CreatedAt = in.CreatedAt.AsTime()
+	d.UpdatedAt = in.UpdatedAt.AsTime()
+	if in.ExpiresAt != nil {
+		expiresAt := in.ExpiresAt.AsTime()
+		d.ExpiresAt = &expiresAt
+	}
+	d.Parts = make([]DataExportPartDTO, len(in.Parts))
+	for i, part := range in.Parts {
+		d.Parts[i] = DataExportPartDTO{
+			Service:   part.Service,
+			Status:    part.Status,
+			Error:     part.Error,
+			UpdatedAt: part.UpdatedAt.AsTime(),
+		}
+	}
+
+	return d, nil
+}
+
+// DataExportArchive - zip архив выгрузки для скачивания
+type DataExportArchive struct {
+	FileName string
+	Content  []byte
+}
+
+// RequestErasureDTO - струтктура запроса на удаление своего аккаунта. Удаление подтверждается текущим паролем
+type RequestErasureDTO struct {
+	Password string `json:"password" example:"password"`
+}
+
+func (d *RequestErasureDTO) ToGRPC(userID int) *users.RequestErasureRequest {
+	return &users.RequestErasureRequest{
+		UserId:   int32(userID),
+		Password: d.Password,
+	}
+}
+
+// ConfirmErasureDTO - струтктура запроса на подтверждение удаления аккаунта
+type ConfirmErasureDTO struct {
+	Token string `json:"token" example:"kq3v0XxkV4m2QH2nV3bq3z4S4Xk2t0tY5tEx5m7YzWc"`
+}
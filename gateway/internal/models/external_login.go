@@ -0,0 +1,36 @@
+package models
+
+import "gateway/pkg/grpc_stubs/users"
+
+// ExternalLoginDTO - data transfer object - пользователь, которого подтвердил провайдер OpenID Connect
+type ExternalLoginDTO struct {
+	Issuer            string
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	PreferredUsername string
+}
+
+func (d *ExternalLoginDTO) ToGRPC() *users.ExternalLoginDTO {
+	return &users.ExternalLoginDTO{
+		Issuer:            d.Issuer,
+		Subject:           d.Subject,
+		Email:             d.Email,
+		EmailVerified:     d.EmailVerified,
+		PreferredUsername: d.PreferredUsername,
+	}
+}
+
+// ExternalLoginStartDTO - начало входа через провайдера: куда отправить пользователя
+// и токен с секретами входа, который gateway кладет в cookie до возврата пользователя
+type ExternalLoginStartDTO struct {
+	AuthURL   string
+	FlowToken string
+}
+
+// ExternalLoginCallbackDTO - параметры, с которыми провайдер вернул пользователя в gateway
+type ExternalLoginCallbackDTO struct {
+	Code      string
+	State     string
+	FlowToken string
+}
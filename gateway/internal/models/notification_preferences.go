@@ -0,0 +1,53 @@
+package models
+
+import (
+	"gateway/pkg/grpc_stubs/users"
+)
+
+// NotificationPreferenceDTO - каналы, по которым приходят уведомления о событии: create_todo, update_todo,
+// delete_todo, mention_todo или reminder_todo. Пустой список каналов отключает уведомления о событии
+type NotificationPreferenceDTO struct {
+	Event    string   `json:"event" example:"update_todo"`
+	Channels []string `json:"channels" example:"email"`
+}
+
+// NotificationPreferencesDTO - настройки уведомлений пользователя. При изменении события,
+// которых нет в запросе, остаются прежними
+type NotificationPreferencesDTO struct {
+	Items []NotificationPreferenceDTO `json:"items"`
+}
+
+func NewEmptyNotificationPreferencesDTO() *NotificationPreferencesDTO {
+	return &NotificationPreferencesDTO{}
+}
+
+func (d *NotificationPreferencesDTO) FromGRPC(in *users.NotificationPreferences) *NotificationPreferencesDTO {
+	d.Items = make([]NotificationPreferenceDTO, len(in.Items))
+	for i, item := range in.Items {
+		channels := item.Channels
+		if channels == nil {
+			channels = make([]string, 0)
+		}
+		d.Items[i] = NotificationPreferenceDTO{
+			Event:    item.Event,
+			Channels: channels,
+		}
+	}
+	return d
+}
+
+func (d *NotificationPreferencesDTO) ToGRPC(userID int) *users.NotificationPreferences {
+	request := &users.NotificationPreferences{
+		UserId: int32(userID),
+		Items:  make([]*users.NotificationPreferenceDTO, len(d.Items)),
+	}
+
+	for i := range d.Items {
+		request.Items[i] = &users.NotificationPreferenceDTO{
+			Event:    d.Items[i].Event,
+			Channels: d.Items[i].Channels,
+		}
+	}
+
+	return request
+}
@@ -0,0 +1,158 @@
+package models
+
+import (
+	"fmt"
+	"gateway/pkg/grpc_stubs/users"
+	"github.com/google/uuid"
+	"time"
+)
+
+// OrganizationDTO - организация и роль в ней текущего пользователя: owner, admin или member
+type OrganizationDTO struct {
+	ID        int       `json:"id" example:"1"`
+	Name      string    `json:"name" example:"Acme"`
+	Role      string    `json:"role" example:"owner"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CreateOrganizationDTO - струтктура запроса на создание организации
+type CreateOrganizationDTO struct {
+	Name string `json:"name" example:"Acme"`
+}
+
+// OrganizationMemberDTO - участник организации и команды, в которых он состоит
+type OrganizationMemberDTO struct {
+	UserID   int       `json:"user_id" example:"2"`
+	Username string    `json:"username" example:"username"`
+	Role     string    `json:"role" example:"member"`
+	TeamIDs  []int     `json:"team_ids" example:"1"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+// SetMemberRoleDTO - струтктура запроса на изменение роли участника
+type SetMemberRoleDTO struct {
+	Role string `json:"role" example:"admin"`
+}
+
+// InviteMemberDTO - струтктура запроса на приглашение в организацию, роль по умолчанию - member
+type InviteMemberDTO struct {
+	Email string `json:"email" example:"user@example.com"`
+	Role  string `json:"role,omitempty" example:"member"`
+}
+
+// InvitationDTO - отправленное приглашение
+type InvitationDTO struct {
+	ID             uuid.UUID `json:"id" example:"c0e708fa-a7df-4d9f-a1b8-a3bfe63c433c"`
+	OrganizationID int       `json:"organization_id" example:"1"`
+	Email          string    `json:"email" example:"user@example.com"`
+	Role           string    `json:"role" example:"member"`
+	ExpiresAt      time.Time `json:"expires_at"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// AcceptInvitationDTO - струтктура запроса на принятие приглашения с токеном из ссылки в письме
+type AcceptInvitationDTO struct {
+	Token string `json:"token"`
+}
+
+// TeamDTO - команда внутри организации
+type TeamDTO struct {
+	ID             int       `json:"id" example:"1"`
+	OrganizationID int       `json:"organization_id" example:"1"`
+	Name           string    `json:"name" example:"backend"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// CreateTeamDTO - струтктура запроса на создание команды
+type CreateTeamDTO struct {
+	Name string `json:"name" example:"backend"`
+}
+
+// TeamMemberDTO - струтктура запроса на добавление участника организации в команду
+type TeamMemberDTO struct {
+	UserID int `json:"user_id" example:"2"`
+}
+
+func NewEmptyOrganizationDTO() *OrganizationDTO {
+	return &OrganizationDTO{}
+}
+
+func (d *OrganizationDTO) FromGRPC(in *users.OrganizationDTO) *OrganizationDTO {
+	d.ID = int(in.Id)
+	d.Name = in.Name
+	d.Role = in.Role
+	d.CreatedAt = in.CreatedAt.AsTime()
+	return d
+}
+
+func OrganizationsFromGRPCResponse(response *users.Organizations) []OrganizationDTO {
+	var dtoSlice = make([]OrganizationDTO, len(response.Items))
+
+	for i := range response.Items {
+		dtoSlice[i] = *NewEmptyOrganizationDTO().FromGRPC(response.Items[i])
+	}
+
+	return dtoSlice
+}
+
+func OrganizationMembersFromGRPCResponse(response *users.OrganizationMembers) []OrganizationMemberDTO {
+	var dtoSlice = make([]OrganizationMemberDTO, len(response.Items))
+
+	for i, member := range response.Items {
+		dtoSlice[i] = OrganizationMemberDTO{
+			UserID:   int(member.UserId),
+			Username: member.Username,
+			Role:     member.Role,
+			TeamIDs:  intsFromGRPC(member.TeamIds),
+			JoinedAt: member.JoinedAt.AsTime(),
+		}
+	}
+
+	return dtoSlice
+}
+
+func (d *InvitationDTO) FromGRPC(in *users.InvitationDTO) (*InvitationDTO, error) {
+	id, err := uuid.Parse(in.Id)
+	if err != nil {
+		return nil, fmt.Errorf("[FromGRPC] wrong uuid: %w", err)
+	}
+
+	d.ID = id
+	d.OrganizationID = int(in.OrganizationId)
+	d.Email = in.Email
+	d.Role = in.Role
+	d.ExpiresAt = in.ExpiresAt.AsTime()
+	d.CreatedAt = in.CreatedAt.AsTime()
+	return d, nil
+}
+
+func NewEmptyTeamDTO() *TeamDTO {
+	return &TeamDTO{}
+}
+
+func (d *TeamDTO) FromGRPC(in *users.TeamDTO) *TeamDTO {
+	d.ID = int(in.Id)
+	d.OrganizationID = int(in.OrganizationId)
+	d.Name = in.Name
+	d.CreatedAt = in.CreatedAt.AsTime()
+	return d
+}
+
+func TeamsFromGRPCResponse(response *users.Teams) []TeamDTO {
+	var dtoSlice = make([]TeamDTO, len(response.Items))
+
+	for i := range response.Items {
+		dtoSlice[i] = *NewEmptyTeamDTO().FromGRPC(response.Items[i])
+	}
+
+	return dtoSlice
+}
+
+func intsFromGRPC(values []int32) []int {
+	result := make([]int, len(values))
+	for i, value := range values {
+		result[i] = int(value)
+	}
+
+	return result
+}
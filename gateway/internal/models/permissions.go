@@ -0,0 +1,31 @@
+package models
+
+// Роли пользователей. Набор разрешений каждой роли хранится в сервисе users и приходит в access токене
+const (
+	RoleUser    = "user"
+	RoleAuditor = "auditor"
+	RoleAdmin   = "admin"
+)
+
+// Разрешения, которые проверяются на маршрутах gateway
+const (
+	// PermissionUsersRead - просматривать любых пользователей
+	PermissionUsersRead = "users:read"
+	// PermissionUsersManage - изменять, удалять, разблокировать любых пользователей и назначать им роли
+	PermissionUsersManage = "users:manage"
+	// PermissionTodosRead - просматривать любые задачи
+	PermissionTodosRead = "todos:read"
+	// PermissionTodosManage - изменять и удалять любые задачи
+	PermissionTodosManage = "todos:manage"
+)
+
+// Caller - пользователь из токена и разрешения его роли, от имени которого gateway обращается к сервисам
+type Caller struct {
+	UserID      int
+	Permissions []string
+}
+
+// SetUserRoleDTO - струтктура запроса на назначение роли
+type SetUserRoleDTO struct {
+	Role string `json:"role" example:"auditor"`
+}
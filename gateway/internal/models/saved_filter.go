@@ -0,0 +1,92 @@
+package models
+
+import (
+	"fmt"
+	todo "gateway/pkg/grpc_stubs/todos"
+	"github.com/google/uuid"
+	"time"
+)
+
+type SavedFilterDTO struct {
+	ID        uuid.UUID   `json:"id,omitempty" example:"c0e708fa-a7df-4d9f-a1b8-a3bfe63c433c"`
+	OwnerID   int         `json:"owner_id" example:"1"`
+	Name      string      `json:"name" example:"My backend tasks"`
+	Shared    bool        `json:"shared" example:"false"`
+	Criteria  GetTodosDTO `json:"criteria"`
+	Count     int         `json:"count" example:"3"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+
+	// CurrentUserID - пользователь из токена, выполняющий операцию над фильтром
+	CurrentUserID int `json:"-"`
+	// Timezone - часовой пояс пользователя из токена, в нем считаются задачи фильтра
+	Timezone string `json:"-"`
+	// CurrentUserPermissions - разрешения роли пользователя из токена, по ним считаются видимые ему задачи
+	CurrentUserPermissions []string `json:"-"`
+}
+
+func NewEmptySavedFilterDTO() *SavedFilterDTO {
+	return &SavedFilterDTO{}
+}
+
+func (d *SavedFilterDTO) ToGRPC() *todo.SavedFilterDTO {
+	var id string
+	if d.ID != uuid.Nil {
+		id = d.ID.String()
+	}
+
+	return &todo.SavedFilterDTO{
+		Id:            id,
+		OwnerId:       int32(d.OwnerID),
+		Name:          d.Name,
+		Shared:        d.Shared,
+		Criteria:      d.Criteria.ToGRPCRequest(),
+		CurrentUserId: int32(d.CurrentUserID),
+		Timezone:      d.Timezone,
+
+		CurrentUserPermissions: d.CurrentUserPermissions,
+	}
+}
+
+func (d *SavedFilterDTO) FromGRPC(dto *todo.SavedFilterDTO) (*SavedFilterDTO, error) {
+	id, err := uuid.Parse(dto.Id)
+	if err != nil {
+		return nil, fmt.Errorf("[FromGRPC] wrong uuid: %w", err)
+	}
+
+	filter := &SavedFilterDTO{
+		ID:        id,
+		OwnerID:   int(dto.OwnerId),
+		Name:      dto.Name,
+		Shared:    dto.Shared,
+		Count:     int(dto.Count),
+		CreatedAt: dto.CreatedAt.AsTime(),
+		UpdatedAt: dto.UpdatedAt.AsTime(),
+	}
+
+	if dto.Criteria != nil {
+		filter.Criteria = GetTodosDTO{
+			CreatedBy: int(dto.Criteria.CreatedBy),
+			Assignee:  int(dto.Criteria.Assignee),
+			DateFrom:  dto.Criteria.DateFrom.AsTime(),
+			DateTo:    dto.Criteria.DateTo.AsTime(),
+			Query:     dto.Criteria.Query,
+		}
+	}
+
+	return filter, nil
+}
+
+func SavedFiltersFromGRPCResponse(response *todo.GetSavedFiltersResponse) ([]SavedFilterDTO, error) {
+	var dtoSlice = make([]SavedFilterDTO, len(response.Items))
+
+	for i := range response.Items {
+		filter, err := NewEmptySavedFilterDTO().FromGRPC(response.Items[i])
+		if err != nil {
+			return nil, fmt.Errorf("[SavedFiltersFromGRPCResponse] %w", err)
+		}
+		dtoSlice[i] = *filter
+	}
+
+	return dtoSlice, nil
+}
@@ -0,0 +1,89 @@
+package models
+
+import (
+	"fmt"
+	todo "gateway/pkg/grpc_stubs/todos"
+	"github.com/google/uuid"
+	"time"
+)
+
+type CreateShareLinkDTO struct {
+	// ExpiresIn - срок действия ссылки в секундах, 0 - срок по умолчанию
+	ExpiresIn int64 `json:"expires_in" example:"86400"`
+}
+
+type ShareLinkDTO struct {
+	ID        uuid.UUID  `json:"id" example:"c0e708fa-a7df-4d9f-a1b8-a3bfe63c433c"`
+	TodoID    uuid.UUID  `json:"todo_id" example:"c0e708fa-a7df-4d9f-a1b8-a3bfe63c433c"`
+	CreatedBy int        `json:"created_by" example:"1"`
+	Token     string     `json:"token"`
+	URL       string     `json:"url" example:"http://localhost:3009/api/v1/shared/token"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// SharedTodoDTO - задача в том виде, в котором ее видит получатель публичной ссылки
+type SharedTodoDTO struct {
+	Title           string    `json:"title" example:"todo title"`
+	DescriptionHTML string    `json:"description_html" example:"<p>todo <strong>description</strong></p>"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+	ExpiresAt       time.Time `json:"expires_at"`
+}
+
+func NewEmptyShareLinkDTO() *ShareLinkDTO {
+	return &ShareLinkDTO{}
+}
+
+func (d *ShareLinkDTO) FromGRPC(dto *todo.ShareLinkDTO) (*ShareLinkDTO, error) {
+	id, err := uuid.Parse(dto.Id)
+	if err != nil {
+		return nil, fmt.Errorf("[FromGRPC] wrong uuid: %w", err)
+	}
+
+	todoID, err := uuid.Parse(dto.TodoId)
+	if err != nil {
+		return nil, fmt.Errorf("[FromGRPC] wrong todo uuid: %w", err)
+	}
+
+	link := &ShareLinkDTO{
+		ID:        id,
+		TodoID:    todoID,
+		CreatedBy: int(dto.CreatedBy),
+		Token:     dto.Token,
+		ExpiresAt: dto.ExpiresAt.AsTime(),
+		CreatedAt: dto.CreatedAt.AsTime(),
+	}
+
+	if dto.RevokedAt != nil {
+		revokedAt := dto.RevokedAt.AsTime()
+		link.RevokedAt = &revokedAt
+	}
+
+	return link, nil
+}
+
+func ShareLinksFromGRPCResponse(response *todo.GetShareLinksResponse) ([]ShareLinkDTO, error) {
+	var dtoSlice = make([]ShareLinkDTO, len(response.Items))
+
+	for i := range response.Items {
+		link, err := NewEmptyShareLinkDTO().FromGRPC(response.Items[i])
+		if err != nil {
+			return nil, fmt.Errorf("[ShareLinksFromGRPCResponse] %w", err)
+		}
+		dtoSlice[i] = *link
+	}
+
+	return dtoSlice, nil
+}
+
+func SharedTodoFromGRPC(dto *todo.SharedTodoDTO) *SharedTodoDTO {
+	return &SharedTodoDTO{
+		Title:           dto.Title,
+		DescriptionHTML: dto.DescriptionHtml,
+		CreatedAt:       dto.CreatedAt.AsTime(),
+		UpdatedAt:       dto.UpdatedAt.AsTime(),
+		ExpiresAt:       dto.ExpiresAt.AsTime(),
+	}
+}
@@ -9,9 +9,11 @@ import (
 )
 
 type CreateTodoDTO struct {
-	CreatedBy   int    `json:"created_by" example:"1"`
+	// CreatedBy - всегда пользователь из токена, из тела запроса не читается
+	CreatedBy   int    `json:"-"`
 	Assignee    int    `json:"assignee" example:"2"`
-	Description string `json:"description" example:"todo description"`
+	Title       string `json:"title" example:"todo title"`
+	Description string `json:"description" example:"todo **description**"`
 }
 
 func NewEmptyCreateTodoDTOO() *CreateTodoDTO {
@@ -23,17 +25,25 @@ func (d *CreateTodoDTO) ToGRPCShort() *todo.ShortTodoDTO {
 		Id:          "",
 		CreatedBy:   int32(d.CreatedBy),
 		Assignee:    int32(d.Assignee),
+		Title:       d.Title,
 		Description: d.Description,
 	}
 }
 
 type TodoDTO struct {
-	ID          uuid.UUID `json:"id,omitempty" example:"c0e708fa-a7df-4d9f-a1b8-a3bfe63c433c"`
-	CreatedBy   int       `json:"created_by" example:"1"`
-	Assignee    int       `json:"assignee" example:"2"`
-	Description string    `json:"description" example:"todo description"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID              uuid.UUID `json:"id,omitempty" example:"c0e708fa-a7df-4d9f-a1b8-a3bfe63c433c"`
+	CreatedBy       int       `json:"created_by" example:"1"`
+	Assignee        int       `json:"assignee" example:"2"`
+	Title           string    `json:"title" example:"todo title"`
+	Description     string    `json:"description" example:"todo **description**"`
+	DescriptionHTML string    `json:"description_html" example:"<p>todo <strong>description</strong></p>"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+
+	// Mentions - пользователи, упомянутые в описании через @username
+	Mentions []string `json:"mentions" example:"alice"`
+	// UnknownMentions - упоминания из описания, для которых не нашлось пользователя
+	UnknownMentions []string `json:"unknown_mentions,omitempty" example:"bob"`
 }
 
 func NewEmptyTodoDTO() *TodoDTO {
@@ -45,18 +55,23 @@ func (d *TodoDTO) ToGRPCShort() *todo.ShortTodoDTO {
 		Id:          d.ID.String(),
 		CreatedBy:   int32(d.CreatedBy),
 		Assignee:    int32(d.Assignee),
+		Title:       d.Title,
 		Description: d.Description,
 	}
 }
 
 func (d *TodoDTO) ToGRPCFull() *todo.FullTodoDTO {
 	return &todo.FullTodoDTO{
-		Id:          d.ID.String(),
-		CreatedBy:   int32(d.CreatedBy),
-		Assignee:    int32(d.Assignee),
-		Description: d.Description,
-		CreatedAt:   ts.New(d.CreatedAt),
-		UpdatedAt:   ts.New(d.UpdatedAt),
+		Id:              d.ID.String(),
+		CreatedBy:       int32(d.CreatedBy),
+		Assignee:        int32(d.Assignee),
+		Title:           d.Title,
+		Description:     d.Description,
+		DescriptionHtml: d.DescriptionHTML,
+		CreatedAt:       ts.New(d.CreatedAt),
+		UpdatedAt:       ts.New(d.UpdatedAt),
+		Mentions:        d.Mentions,
+		UnknownMentions: d.UnknownMentions,
 	}
 }
 
@@ -70,6 +85,7 @@ func (d *TodoDTO) FromGRPCShort(dto *todo.ShortTodoDTO) (*TodoDTO, error) {
 		ID:          id,
 		CreatedBy:   int(dto.CreatedBy),
 		Assignee:    int(dto.Assignee),
+		Title:       dto.Title,
 		Description: dto.Description,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
@@ -83,12 +99,16 @@ func (d *TodoDTO) FromGRPCFull(dto *todo.FullTodoDTO) (*TodoDTO, error) {
 	}
 
 	return &TodoDTO{
-		ID:          id,
-		CreatedBy:   int(dto.CreatedBy),
-		Assignee:    int(dto.Assignee),
-		Description: dto.Description,
-		CreatedAt:   dto.CreatedAt.AsTime(),
-		UpdatedAt:   dto.UpdatedAt.AsTime(),
+		ID:              id,
+		CreatedBy:       int(dto.CreatedBy),
+		Assignee:        int(dto.Assignee),
+		Title:           dto.Title,
+		Description:     dto.Description,
+		DescriptionHTML: dto.DescriptionHtml,
+		CreatedAt:       dto.CreatedAt.AsTime(),
+		UpdatedAt:       dto.UpdatedAt.AsTime(),
+		Mentions:        dto.Mentions,
+		UnknownMentions: dto.UnknownMentions,
 	}, nil
 }
 
@@ -97,6 +117,14 @@ type GetTodosDTO struct {
 	Assignee  int       `json:"assignee" example:"2"`
 	DateFrom  time.Time `json:"date_from"`
 	DateTo    time.Time `json:"date_to"`
+	Query     string    `json:"query" example:"assignee:me created>2024-01-01 -creator:5 text:\"deploy\""`
+
+	// CurrentUserID - пользователь из токена, подставляется в Query вместо "me"
+	CurrentUserID int `json:"-"`
+	// CurrentUserPermissions - разрешения роли пользователя. Без todos:read видны только его задачи
+	CurrentUserPermissions []string `json:"-"`
+	// Timezone - часовой пояс пользователя из токена, в нем понимаются даты в Query
+	Timezone string `json:"-"`
 }
 
 func NewEmptyGetTodosDTO() *GetTodosDTO {
@@ -105,10 +133,15 @@ func NewEmptyGetTodosDTO() *GetTodosDTO {
 
 func (d *GetTodosDTO) ToGRPCRequest() *todo.GetTodosRequest {
 	return &todo.GetTodosRequest{
-		CreatedBy: int32(d.CreatedBy),
-		Assignee:  int32(d.Assignee),
-		DateFrom:  ts.New(d.DateFrom),
-		DateTo:    ts.New(d.DateTo),
+		CreatedBy:     int32(d.CreatedBy),
+		Assignee:      int32(d.Assignee),
+		DateFrom:      ts.New(d.DateFrom),
+		DateTo:        ts.New(d.DateTo),
+		Query:         d.Query,
+		CurrentUserId: int32(d.CurrentUserID),
+		Timezone:      d.Timezone,
+
+		CurrentUserPermissions: d.CurrentUserPermissions,
 	}
 }
 
@@ -122,12 +155,16 @@ func SliceFromGRPCResponse(response *todo.GetTodosResponse) ([]TodoDTO, error) {
 		}
 
 		var newDto = TodoDTO{
-			ID:          id,
-			CreatedBy:   int(response.Items[i].CreatedBy),
-			Assignee:    int(response.Items[i].Assignee),
-			Description: response.Items[i].Description,
-			CreatedAt:   response.Items[i].CreatedAt.AsTime(),
-			UpdatedAt:   response.Items[i].UpdatedAt.AsTime(),
+			ID:              id,
+			CreatedBy:       int(response.Items[i].CreatedBy),
+			Assignee:        int(response.Items[i].Assignee),
+			Title:           response.Items[i].Title,
+			Description:     response.Items[i].Description,
+			DescriptionHTML: response.Items[i].DescriptionHtml,
+			CreatedAt:       response.Items[i].CreatedAt.AsTime(),
+			UpdatedAt:       response.Items[i].UpdatedAt.AsTime(),
+			Mentions:        response.Items[i].Mentions,
+			UnknownMentions: response.Items[i].UnknownMentions,
 		}
 		dtoSlice[i] = newDto
 	}
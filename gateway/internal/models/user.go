@@ -8,6 +8,30 @@ type UserDTO struct {
 	Username string `json:"username" example:"username"`
 	Password string `json:"password,omitempty" example:"password"`
 	Email    string `json:"email,omitempty" example:"user@example.com"`
+
+	EmailVerified bool `json:"email_verified" example:"false"`
+
+	Role        string   `json:"role,omitempty" example:"user"`
+	Permissions []string `json:"permissions,omitempty" example:"users:read"`
+
+	// DisplayName, Timezone и Locale при обновлении меняются, только если переданы.
+	// Timezone - часовой пояс IANA, Locale - язык в формате BCP 47
+	DisplayName string `json:"display_name,omitempty" example:"John Doe"`
+	Timezone    string `json:"timezone,omitempty" example:"Europe/Moscow"`
+	Locale      string `json:"locale,omitempty" example:"ru"`
+
+	AvatarURL          string `json:"avatar_url,omitempty"`
+	AvatarThumbnailURL string `json:"avatar_thumbnail_url,omitempty"`
+
+	// OrganizationIDs и TeamIDs нужны только для access токена
+	OrganizationIDs []int `json:"-"`
+	TeamIDs         []int `json:"-"`
+
+	// TwoFactorRequired и ChallengeToken приходят только в ответ на вход с включенным вторым фактором
+	TwoFactorRequired bool   `json:"-"`
+	ChallengeToken    string `json:"-"`
+	// TwoFactorSetupRequired - роль требует второй фактор, а он не включен, поэтому разрешений роли нет
+	TwoFactorSetupRequired bool `json:"two_factor_setup_required,omitempty"`
 }
 
 func NewEmptyUserDTO() *UserDTO {
@@ -20,6 +44,10 @@ func (d *UserDTO) ToGRPC() *users.UserDTO {
 		Username: d.Username,
 		Password: d.Password,
 		Email:    d.Email,
+
+		DisplayName: d.DisplayName,
+		Timezone:    d.Timezone,
+		Locale:      d.Locale,
 	}
 }
 
@@ -28,6 +56,19 @@ func (d *UserDTO) FromGRPC(in *users.UserDTO) *UserDTO {
 	d.Email = in.Email
 	d.Username = in.Username
 	d.Password = in.Password
+	d.EmailVerified = in.EmailVerified
+	d.Role = in.Role
+	d.Permissions = in.Permissions
+	d.DisplayName = in.DisplayName
+	d.Timezone = in.Timezone
+	d.Locale = in.Locale
+	d.AvatarURL = in.AvatarUrl
+	d.AvatarThumbnailURL = in.AvatarThumbnailUrl
+	d.OrganizationIDs = intsFromGRPC(in.OrganizationIds)
+	d.TeamIDs = intsFromGRPC(in.TeamIds)
+	d.TwoFactorRequired = in.TwoFactorRequired
+	d.ChallengeToken = in.ChallengeToken
+	d.TwoFactorSetupRequired = in.TwoFactorSetupRequired
 	return d
 }
 
@@ -97,6 +138,8 @@ type UserLoginDTO struct {
 	Username string `db:"username,omitempty"`
 	Password string `db:"password"`
 	Email    string `db:"email,omitempty"`
+	// ClientIP - адрес, с которого пришел запрос на вход, заполняется gateway
+	ClientIP string `json:"-"`
 }
 
 func NewEmptyUserLoginDTO() *UserLoginDTO {
@@ -108,6 +151,7 @@ func (d *UserLoginDTO) ToGRPC() *users.UserLoginDTO {
 		Email:    d.Email,
 		Username: d.Username,
 		Password: d.Password,
+		ClientIp: d.ClientIP,
 	}
 }
 
@@ -115,11 +159,103 @@ func (d *UserLoginDTO) FromGRPC(in *users.UserLoginDTO) *UserLoginDTO {
 	d.Email = in.Email
 	d.Username = in.Username
 	d.Password = in.Password
+	d.ClientIP = in.ClientIp
 	return d
 }
 
-// UserTokens - струтктура для передачи токенов пользователя
+// RequestEmailChangeDTO - струтктура запроса на смену email. Смена подтверждается текущим паролем
+type RequestEmailChangeDTO struct {
+	Email    string `json:"email" example:"new@example.com"`
+	Password string `json:"password" example:"password"`
+}
+
+func (d *RequestEmailChangeDTO) ToGRPC(userID int) *users.RequestEmailChangeRequest {
+	return &users.RequestEmailChangeRequest{
+		UserId:   int32(userID),
+		Email:    d.Email,
+		Password: d.Password,
+	}
+}
+
+// ConfirmEmailChangeDTO - струтктура запроса на подтверждение нового email
+type ConfirmEmailChangeDTO struct {
+	Token string `json:"token" example:"kq3v0XxkV4m2QH2nV3bq3z4S4Xk2t0tY5tEx5m7YzWc"`
+}
+
+// UserTokens - струтктура для передачи токенов пользователя. Если у пользователя включен второй фактор,
+// вместо токенов возвращается ChallengeToken для второго шага входа
 type UserTokens struct {
 	AccessToken  string `json:"access_token,omitempty"`
 	RefreshToken string `json:"refresh_token,omitempty"`
+
+	TwoFactorRequired bool   `json:"two_factor_required,omitempty"`
+	ChallengeToken    string `json:"challenge_token,omitempty"`
+
+	// TwoFactorSetupRequired - роль требует второй фактор: пока он не подключен, в токене нет разрешений роли
+	TwoFactorSetupRequired bool `json:"two_factor_setup_required,omitempty"`
+}
+
+// VerifyEmailDTO - струтктура запроса на подтверждение email
+type VerifyEmailDTO struct {
+	Token string `json:"token" example:"kq3v0XxkV4m2QH2nV3bq3z4S4Xk2t0tY5tEx5m7YzWc"`
+}
+
+// ResendVerificationEmailDTO - струтктура запроса на повторную отправку письма с подтверждением email
+type ResendVerificationEmailDTO struct {
+	Email string `json:"email" example:"user@example.com"`
+}
+
+// RequestPasswordResetDTO - струтктура запроса на отправку письма со сбросом пароля
+type RequestPasswordResetDTO struct {
+	Email string `json:"email" example:"user@example.com"`
+}
+
+// ResetPasswordDTO - data transfer object - струтктура для установки нового пароля по токену из письма
+type ResetPasswordDTO struct {
+	Token                string `json:"token" example:"kq3v0XxkV4m2QH2nV3bq3z4S4Xk2t0tY5tEx5m7YzWc"`
+	Password             string `json:"password" example:"password"`
+	PasswordConfirmation string `json:"password_confirmation" example:"password"`
+}
+
+func (d *ResetPasswordDTO) ToGRPC() *users.ResetPasswordRequest {
+	return &users.ResetPasswordRequest{
+		Token:                d.Token,
+		Password:             d.Password,
+		PasswordConfirmation: d.PasswordConfirmation,
+	}
+}
+
+// TwoFactorEnrollmentDTO - секрет для приложения-аутентификатора и ссылка для QR кода
+type TwoFactorEnrollmentDTO struct {
+	Secret     string `json:"secret" example:"JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"`
+	OtpauthURI string `json:"otpauth_uri" example:"otpauth://totp/Todo:user@example.com?secret=JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP&issuer=Todo"`
+}
+
+func (d *TwoFactorEnrollmentDTO) FromGRPC(in *users.TwoFactorEnrollment) *TwoFactorEnrollmentDTO {
+	d.Secret = in.Secret
+	d.OtpauthURI = in.OtpauthUri
+	return d
+}
+
+// TwoFactorCodeDTO - струтктура запроса с кодом из приложения-аутентификатора или кодом восстановления
+type TwoFactorCodeDTO struct {
+	Code string `json:"code" example:"123456"`
+}
+
+// TwoFactorLoginDTO - струтктура запроса второго шага входа
+type TwoFactorLoginDTO struct {
+	ChallengeToken string `json:"challenge_token" example:"kq3v0XxkV4m2QH2nV3bq3z4S4Xk2t0tY5tEx5m7YzWc"`
+	Code           string `json:"code" example:"123456"`
+}
+
+func (d *TwoFactorLoginDTO) ToGRPC() *users.TwoFactorLoginRequest {
+	return &users.TwoFactorLoginRequest{
+		ChallengeToken: d.ChallengeToken,
+		Code:           d.Code,
+	}
+}
+
+// RecoveryCodesDTO - коды восстановления, которые показываются пользователю один раз
+type RecoveryCodesDTO struct {
+	Codes []string `json:"recovery_codes" example:"k3fq-8dmx"`
 }
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"fmt"
+	"gateway/pkg/grpc_stubs/users"
+	"github.com/google/uuid"
+	"time"
+)
+
+// UserDeletionStepDTO - шаг удаления пользователя в одном из сервисов
+type UserDeletionStepDTO struct {
+	Service   string    `json:"service" example:"todo"`
+	Status    string    `json:"status" example:"confirmed"`
+	Error     string    `json:"error,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// UserDeletionDTO - состояние удаления пользователя: pending, пока не ответили все сервисы,
+// completed после окончательного удаления или failed, если удаление отменено
+type UserDeletionDTO struct {
+	ID        uuid.UUID             `json:"id" example:"c0e708fa-a7df-4d9f-a1b8-a3bfe63c433c"`
+	UserID    int                   `json:"user_id" example:"1"`
+	Status    string                `json:"status" example:"pending"`
+	Error     string                `json:"error,omitempty"`
+	Steps     []UserDeletionStepDTO `json:"steps"`
+	CreatedAt time.Time             `json:"created_at"`
+	UpdatedAt time.Time             `json:"updated_at"`
+}
+
+func NewEmptyUserDeletionDTO() *UserDeletionDTO {
+	return &UserDeletionDTO{}
+}
+
+func (d *UserDeletionDTO) FromGRPC(in *users.UserDeletion) (*UserDeletionDTO, error) {
+	id, err := uuid.Parse(in.Id)
+	if err != nil {
+		return nil, fmt.Errorf("[FromGRPC] wrong uuid: %w", err)
+	}
+
+	d.ID = id
+	d.UserID = int(in.UserId)
+	d.Status = in.Status
+	d.Error = in.Error
+	d.CreatedAt = in.CreatedAt.AsTime()
+	d.UpdatedAt = in.UpdatedAt.AsTime()
+	d.Steps = make([]UserDeletionStepDTO, len(in.Steps))
+	for i, step := range in.Steps {
+		d.Steps[i] = UserDeletionStepDTO{
+			Service:   step.Service,
+			Status:    step.Status,
+			Error:     step.Error,
+			UpdatedAt: step.UpdatedAt.AsTime(),
+		}
+	}
+
+	return d, nil
+}
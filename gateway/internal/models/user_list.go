@@ -0,0 +1,48 @@
+package models
+
+import "gateway/pkg/grpc_stubs/users"
+
+// ListUsersDTO - параметры постраничного поиска пользователей из строки запроса
+type ListUsersDTO struct {
+	// Search - начало username или email, регистр не учитывается
+	Search string
+	Limit  int
+	Offset int
+	// Sort - id, username или email, с минусом в начале - по убыванию
+	Sort string
+	// IncludeEmail - искать по email и показывать его, выставляется gateway для пользователей с users:read
+	IncludeEmail bool
+}
+
+// UsersPageDTO - страница найденных пользователей
+type UsersPageDTO struct {
+	Items  []UserDTO `json:"items"`
+	Total  int       `json:"total" example:"42"`
+	Limit  int       `json:"limit" example:"20"`
+	Offset int       `json:"offset" example:"0"`
+}
+
+func (d *ListUsersDTO) ToGRPC() *users.ListUsersRequest {
+	return &users.ListUsersRequest{
+		Search:       d.Search,
+		Limit:        int32(d.Limit),
+		Offset:       int32(d.Offset),
+		Sort:         d.Sort,
+		IncludeEmail: d.IncludeEmail,
+	}
+}
+
+func UsersPageFromGRPC(response *users.ListUsersResponse) *UsersPageDTO {
+	page := &UsersPageDTO{
+		Items:  make([]UserDTO, len(response.Items)),
+		Total:  int(response.Total),
+		Limit:  int(response.Limit),
+		Offset: int(response.Offset),
+	}
+
+	for i := range response.Items {
+		page.Items[i] = *NewEmptyUserDTO().FromGRPC(response.Items[i])
+	}
+
+	return page
+}
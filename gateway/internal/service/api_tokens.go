@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"gateway/internal/app_errors"
+	"gateway/internal/models"
+	"gateway/pkg/ctxutil"
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go"
+)
+
+// CreateApiToken выпускает персональный токен текущему пользователю. Токен показывается только в этом ответе.
+func (s *GatewayService) CreateApiToken(ctx context.Context, request *models.CreateApiTokenDTO) (*models.ApiTokenDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.CreateApiToken")
+	defer span.Finish()
+
+	userID, ok := ctxutil.GetUserIDFromContext(ctx)
+	if !ok {
+		return nil, app_errors.ErrNoUserInContext
+	}
+
+	token, err := s.usersServiceClient.CreateApiToken(ctx, userID, request)
+	if err != nil {
+		return nil, fmt.Errorf("[CreateApiToken] create token:%w", err)
+	}
+
+	return token, nil
+}
+
+func (s *GatewayService) GetApiTokens(ctx context.Context) ([]models.ApiTokenDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.GetApiTokens")
+	defer span.Finish()
+
+	userID, ok := ctxutil.GetUserIDFromContext(ctx)
+	if !ok {
+		return nil, app_errors.ErrNoUserInContext
+	}
+
+	tokens, err := s.usersServiceClient.GetApiTokens(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("[GetApiTokens] get tokens:%w", err)
+	}
+
+	return tokens, nil
+}
+
+func (s *GatewayService) RevokeApiToken(ctx context.Context, tokenID uuid.UUID) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.RevokeApiToken")
+	defer span.Finish()
+
+	userID, ok := ctxutil.GetUserIDFromContext(ctx)
+	if !ok {
+		return app_errors.ErrNoUserInContext
+	}
+
+	err := s.usersServiceClient.RevokeApiToken(ctx, tokenID, userID)
+	if err != nil {
+		return fmt.Errorf("[RevokeApiToken] revoke token:%w", err)
+	}
+
+	return nil
+}
+
+// AuthenticateApiToken проверяет персональный токен, предъявленный вместо access токена
+func (s *GatewayService) AuthenticateApiToken(ctx context.Context, token string) (*models.ApiTokenAuthDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.AuthenticateApiToken")
+	defer span.Finish()
+
+	auth, err := s.usersServiceClient.AuthenticateApiToken(ctx, token)
+	if err != nil {
+		return nil, fmt.Errorf("[AuthenticateApiToken] authenticate:%w", err)
+	}
+
+	return auth, nil
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"gateway/internal/app_errors"
+	"gateway/internal/models"
+	"gateway/pkg/jwtutil"
+	"gateway/pkg/oidc"
+	"github.com/opentracing/opentracing-go"
+)
+
+// StartExternalLogin готовит вход через провайдера OpenID Connect: state, nonce и PKCE verifier
+// уходят в подписанный токен, а пользователь отправляется на страницу входа провайдера
+func (s *GatewayService) StartExternalLogin(ctx context.Context) (*models.ExternalLoginStartDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.StartExternalLogin")
+	defer span.Finish()
+
+	if s.identityProvider == nil {
+		return nil, app_errors.ErrExternalLoginDisabled
+	}
+
+	flow := &jwtutil.OIDCFlow{}
+	for _, value := range []*string{&flow.State, &flow.Nonce, &flow.CodeVerifier} {
+		random, err := oidc.RandomString()
+		if err != nil {
+			return nil, fmt.Errorf("[StartExternalLogin] generate flow: %w", err)
+		}
+		*value = random
+	}
+
+	authURL, err := s.identityProvider.AuthCodeURL(ctx, flow.State, flow.Nonce, oidc.CodeChallenge(flow.CodeVerifier))
+	if err != nil {
+		return nil, fmt.Errorf("[StartExternalLogin] %w: %s", app_errors.ErrExternalLoginFailed, err)
+	}
+
+	flowToken, err := s.jwtUtil.GenerateOIDCFlowToken(flow)
+	if err != nil {
+		return nil, fmt.Errorf("[StartExternalLogin] generate flow token: %w", err)
+	}
+
+	return &models.ExternalLoginStartDTO{
+		AuthURL:   authURL,
+		FlowToken: flowToken,
+	}, nil
+}
+
+// CompleteExternalLogin завершает вход через провайдера: проверяет, что пользователь вернулся из того же
+// браузера, обменивает код на ID токен и входит связанным с ним пользователем
+func (s *GatewayService) CompleteExternalLogin(ctx context.Context, callback *models.ExternalLoginCallbackDTO) (*models.UserTokens, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.CompleteExternalLogin")
+	defer span.Finish()
+
+	if s.identityProvider == nil {
+		return nil, app_errors.ErrExternalLoginDisabled
+	}
+
+	flow, err := s.jwtUtil.VerifyOIDCFlowToken(callback.FlowToken)
+	if err != nil {
+		return nil, fmt.Errorf("[CompleteExternalLogin] verify flow token: %w: %s", app_errors.ErrExternalLoginFailed, err)
+	}
+
+	// state защищает от подстановки чужого кода авторизации в браузер пользователя
+	if subtle.ConstantTimeCompare([]byte(callback.State), []byte(flow.State)) != 1 {
+		return nil, fmt.Errorf("[CompleteExternalLogin] %w: state mismatch", app_errors.ErrExternalLoginFailed)
+	}
+
+	identity, err := s.identityProvider.Exchange(ctx, callback.Code, flow.CodeVerifier, flow.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("[CompleteExternalLogin] exchange code: %w: %s", app_errors.ErrExternalLoginFailed, err)
+	}
+
+	existingUser, err := s.usersServiceClient.LoginExternal(ctx, &models.ExternalLoginDTO{
+		Issuer:            identity.Issuer,
+		Subject:           identity.Subject,
+		Email:             identity.Email,
+		EmailVerified:     identity.EmailVerified,
+		PreferredUsername: identity.PreferredUsername,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("[CompleteExternalLogin] log in: %w", err)
+	}
+
+	// второй фактор, включенный в аккаунте, требуется и при входе через провайдера
+	if existingUser.TwoFactorRequired {
+		return &models.UserTokens{
+			TwoFactorRequired: true,
+			ChallengeToken:    existingUser.ChallengeToken,
+		}, nil
+	}
+
+	tokens, err := s.startSession(ctx, existingUser)
+	if err != nil {
+		return nil, fmt.Errorf("[CompleteExternalLogin] %w", err)
+	}
+
+	return tokens, nil
+}
@@ -8,16 +8,20 @@ type GatewayService struct {
 	jwtUtil            *jwtutil.JWTUtil
 	todoServiceClient  TodoServiceClient
 	usersServiceClient UsersServiceClient
+	// identityProvider - провайдер для входа через OpenID Connect, nil если вход выключен
+	identityProvider IdentityProvider
 }
 
 func NewGatewayService(
 	jwtUtil *jwtutil.JWTUtil,
 	todoServiceClient TodoServiceClient,
 	usersServiceClient UsersServiceClient,
+	identityProvider IdentityProvider,
 ) *GatewayService {
 	return &GatewayService{
 		jwtUtil:            jwtUtil,
 		todoServiceClient:  todoServiceClient,
 		usersServiceClient: usersServiceClient,
+		identityProvider:   identityProvider,
 	}
 }
@@ -3,24 +3,90 @@ package service
 import (
 	"context"
 	"gateway/internal/models"
+	"gateway/pkg/oidc"
 	"github.com/google/uuid"
+	"time"
 )
 
 type TodoServiceClient interface {
-	CreateToDo(ctx context.Context, newTodo *models.CreateTodoDTO) (*models.TodoDTO, error)
-	UpdateToDo(ctx context.Context, newTodo *models.TodoDTO) (*models.TodoDTO, error)
+	CreateToDo(ctx context.Context, newTodo *models.CreateTodoDTO, caller *models.Caller) (*models.TodoDTO, error)
+	UpdateToDo(ctx context.Context, newTodo *models.TodoDTO, caller *models.Caller) (*models.TodoDTO, error)
 	GetToDos(ctx context.Context, todos *models.GetTodosDTO) ([]models.TodoDTO, error)
-	GetToDo(ctx context.Context, todoID uuid.UUID) (*models.TodoDTO, error)
-	DeleteToDo(ctx context.Context, todoID uuid.UUID) error
+	GetToDo(ctx context.Context, todoID uuid.UUID, caller *models.Caller) (*models.TodoDTO, error)
+	DeleteToDo(ctx context.Context, todoID uuid.UUID, caller *models.Caller) error
+
+	CreateSavedFilter(ctx context.Context, newFilter *models.SavedFilterDTO) (*models.SavedFilterDTO, error)
+	UpdateSavedFilter(ctx context.Context, newFilter *models.SavedFilterDTO) (*models.SavedFilterDTO, error)
+	DeleteSavedFilter(ctx context.Context, filterID uuid.UUID, currentUserID int) error
+	GetSavedFilters(ctx context.Context, caller *models.Caller, timezone string) ([]models.SavedFilterDTO, error)
+	RunSavedFilter(ctx context.Context, filterID uuid.UUID, caller *models.Caller, timezone string) ([]models.TodoDTO, error)
+
+	CreateShareLink(ctx context.Context, todoID uuid.UUID, currentUserID int, request *models.CreateShareLinkDTO) (*models.ShareLinkDTO, error)
+	GetShareLinks(ctx context.Context, todoID uuid.UUID, currentUserID int) ([]models.ShareLinkDTO, error)
+	RevokeShareLink(ctx context.Context, linkID uuid.UUID, currentUserID int) error
+	GetSharedTodo(ctx context.Context, token string) (*models.SharedTodoDTO, error)
 }
 
 type UsersServiceClient interface {
 	CreateUser(ctx context.Context, user *models.CreateUserDTO) (int, error)
-	UpdateUser(ctx context.Context, user *models.UserDTO) error
+	UpdateUser(ctx context.Context, user *models.UserDTO) (*models.UserDTO, error)
 	UpdatePassword(ctx context.Context, data *models.UpdateUserPasswordDTO) error
 	DeleteUser(ctx context.Context, userID int) error
+	GetUserDeletion(ctx context.Context, userID int) (*models.UserDeletionDTO, error)
 	GetUserByID(ctx context.Context, userID int) (*models.UserDTO, error)
 	GetUserByUsernameOrEmail(ctx context.Context, username, email string) (*models.UserDTO, error)
 	GetUserByUsername(ctx context.Context, username string) (*models.UserDTO, error)
+	ListUsers(ctx context.Context, filter *models.ListUsersDTO) (*models.UsersPageDTO, error)
 	UserLogin(ctx context.Context, user *models.UserLoginDTO) (*models.UserDTO, error)
+	VerifyEmail(ctx context.Context, token string) error
+	ResendVerificationEmail(ctx context.Context, email string) error
+	RequestPasswordReset(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, request *models.ResetPasswordDTO) error
+	RequestEmailChange(ctx context.Context, userID int, request *models.RequestEmailChangeDTO) error
+	ConfirmEmailChange(ctx context.Context, token string) error
+	RequestDataExport(ctx context.Context, userID int) (*models.DataExportDTO, error)
+	GetDataExport(ctx context.Context, userID int) (*models.DataExportDTO, error)
+	DownloadDataExport(ctx context.Context, token string) (*models.DataExportArchive, error)
+	RequestErasure(ctx context.Context, userID int, request *models.RequestErasureDTO) error
+	ConfirmErasure(ctx context.Context, token string) (*models.UserDeletionDTO, error)
+	CreateSession(ctx context.Context, userID int, refreshTokenID uuid.UUID, ttl time.Duration) (uuid.UUID, error)
+	RotateSession(ctx context.Context, sessionID, refreshTokenID, newRefreshTokenID uuid.UUID, ttl time.Duration) error
+	RevokeSession(ctx context.Context, sessionID uuid.UUID, userID int) error
+	RevokeUserSessions(ctx context.Context, userID int) error
+	LoginTwoFactor(ctx context.Context, request *models.TwoFactorLoginDTO) (*models.UserDTO, error)
+	LoginExternal(ctx context.Context, login *models.ExternalLoginDTO) (*models.UserDTO, error)
+	CreateApiToken(ctx context.Context, userID int, request *models.CreateApiTokenDTO) (*models.ApiTokenDTO, error)
+	GetApiTokens(ctx context.Context, userID int) ([]models.ApiTokenDTO, error)
+	RevokeApiToken(ctx context.Context, tokenID uuid.UUID, userID int) error
+	AuthenticateApiToken(ctx context.Context, token string) (*models.ApiTokenAuthDTO, error)
+	EnrollTwoFactor(ctx context.Context, userID int) (*models.TwoFactorEnrollmentDTO, error)
+	ConfirmTwoFactor(ctx context.Context, userID int, code string) (*models.RecoveryCodesDTO, error)
+	DisableTwoFactor(ctx context.Context, userID int, code string) error
+	RegenerateRecoveryCodes(ctx context.Context, userID int, code string) (*models.RecoveryCodesDTO, error)
+	UnlockUser(ctx context.Context, userID int) error
+	SetUserRole(ctx context.Context, userID int, role string) error
+	CreateOrganization(ctx context.Context, userID int, request *models.CreateOrganizationDTO) (*models.OrganizationDTO, error)
+	GetUserOrganizations(ctx context.Context, userID int) ([]models.OrganizationDTO, error)
+	ListOrganizationMembers(ctx context.Context, organizationID, userID int) ([]models.OrganizationMemberDTO, error)
+	InviteMember(ctx context.Context, organizationID, userID int, request *models.InviteMemberDTO) (*models.InvitationDTO, error)
+	AcceptInvitation(ctx context.Context, token string, userID int) (*models.OrganizationDTO, error)
+	SetMemberRole(ctx context.Context, organizationID, userID, memberID int, role string) error
+	RemoveMember(ctx context.Context, organizationID, userID, memberID int) error
+	CreateTeam(ctx context.Context, organizationID, userID int, request *models.CreateTeamDTO) (*models.TeamDTO, error)
+	GetTeams(ctx context.Context, organizationID, userID int) ([]models.TeamDTO, error)
+	AddTeamMember(ctx context.Context, organizationID, teamID, userID, memberID int) error
+	RemoveTeamMember(ctx context.Context, organizationID, teamID, userID, memberID int) error
+	ListTeamMembers(ctx context.Context, organizationID, teamID, userID int) ([]models.OrganizationMemberDTO, error)
+
+	UploadAvatar(ctx context.Context, userID int, image []byte) (*models.UserDTO, error)
+	DeleteAvatar(ctx context.Context, userID int) (*models.UserDTO, error)
+
+	GetNotificationPreferences(ctx context.Context, userID int) (*models.NotificationPreferencesDTO, error)
+	UpdateNotificationPreferences(ctx context.Context, userID int, request *models.NotificationPreferencesDTO) (*models.NotificationPreferencesDTO, error)
+}
+
+// IdentityProvider - внешний провайдер входа (OpenID Connect)
+type IdentityProvider interface {
+	AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error)
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (*oidc.Identity, error)
 }
@@ -0,0 +1,219 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"gateway/internal/app_errors"
+	"gateway/internal/models"
+	"gateway/pkg/ctxutil"
+	"github.com/opentracing/opentracing-go"
+)
+
+// CreateOrganization создает организацию, текущий пользователь становится ее владельцем.
+// Организация попадет в access токен при следующем обновлении токенов
+func (s *GatewayService) CreateOrganization(ctx context.Context, request *models.CreateOrganizationDTO) (*models.OrganizationDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.CreateOrganization")
+	defer span.Finish()
+
+	userID, ok := ctxutil.GetUserIDFromContext(ctx)
+	if !ok {
+		return nil, app_errors.ErrNoUserInContext
+	}
+
+	organization, err := s.usersServiceClient.CreateOrganization(ctx, userID, request)
+	if err != nil {
+		return nil, fmt.Errorf("[CreateOrganization] create organization:%w", err)
+	}
+
+	return organization, nil
+}
+
+func (s *GatewayService) GetOrganizations(ctx context.Context) ([]models.OrganizationDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.GetOrganizations")
+	defer span.Finish()
+
+	userID, ok := ctxutil.GetUserIDFromContext(ctx)
+	if !ok {
+		return nil, app_errors.ErrNoUserInContext
+	}
+
+	organizations, err := s.usersServiceClient.GetUserOrganizations(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("[GetOrganizations] get organizations:%w", err)
+	}
+
+	return organizations, nil
+}
+
+func (s *GatewayService) ListOrganizationMembers(ctx context.Context, organizationID int) ([]models.OrganizationMemberDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.ListOrganizationMembers")
+	defer span.Finish()
+
+	userID, ok := ctxutil.GetUserIDFromContext(ctx)
+	if !ok {
+		return nil, app_errors.ErrNoUserInContext
+	}
+
+	members, err := s.usersServiceClient.ListOrganizationMembers(ctx, organizationID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("[ListOrganizationMembers] get members:%w", err)
+	}
+
+	return members, nil
+}
+
+// InviteMember отправляет приглашение в организацию со ссылкой на email
+func (s *GatewayService) InviteMember(ctx context.Context, organizationID int, request *models.InviteMemberDTO) (*models.InvitationDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.InviteMember")
+	defer span.Finish()
+
+	userID, ok := ctxutil.GetUserIDFromContext(ctx)
+	if !ok {
+		return nil, app_errors.ErrNoUserInContext
+	}
+
+	invitation, err := s.usersServiceClient.InviteMember(ctx, organizationID, userID, request)
+	if err != nil {
+		return nil, fmt.Errorf("[InviteMember] invite member:%w", err)
+	}
+
+	return invitation, nil
+}
+
+// AcceptInvitation добавляет текущего пользователя в организацию по токену из письма
+func (s *GatewayService) AcceptInvitation(ctx context.Context, request *models.AcceptInvitationDTO) (*models.OrganizationDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.AcceptInvitation")
+	defer span.Finish()
+
+	userID, ok := ctxutil.GetUserIDFromContext(ctx)
+	if !ok {
+		return nil, app_errors.ErrNoUserInContext
+	}
+
+	organization, err := s.usersServiceClient.AcceptInvitation(ctx, request.Token, userID)
+	if err != nil {
+		return nil, fmt.Errorf("[AcceptInvitation] accept invitation:%w", err)
+	}
+
+	return organization, nil
+}
+
+func (s *GatewayService) SetMemberRole(ctx context.Context, organizationID, memberID int, request *models.SetMemberRoleDTO) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.SetMemberRole")
+	defer span.Finish()
+
+	userID, ok := ctxutil.GetUserIDFromContext(ctx)
+	if !ok {
+		return app_errors.ErrNoUserInContext
+	}
+
+	err := s.usersServiceClient.SetMemberRole(ctx, organizationID, userID, memberID, request.Role)
+	if err != nil {
+		return fmt.Errorf("[SetMemberRole] set role:%w", err)
+	}
+
+	return nil
+}
+
+// RemoveMember исключает участника из организации, а если участник - сам пользователь, выводит его из нее
+func (s *GatewayService) RemoveMember(ctx context.Context, organizationID, memberID int) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.RemoveMember")
+	defer span.Finish()
+
+	userID, ok := ctxutil.GetUserIDFromContext(ctx)
+	if !ok {
+		return app_errors.ErrNoUserInContext
+	}
+
+	err := s.usersServiceClient.RemoveMember(ctx, organizationID, userID, memberID)
+	if err != nil {
+		return fmt.Errorf("[RemoveMember] remove member:%w", err)
+	}
+
+	return nil
+}
+
+func (s *GatewayService) CreateTeam(ctx context.Context, organizationID int, request *models.CreateTeamDTO) (*models.TeamDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.CreateTeam")
+	defer span.Finish()
+
+	userID, ok := ctxutil.GetUserIDFromContext(ctx)
+	if !ok {
+		return nil, app_errors.ErrNoUserInContext
+	}
+
+	team, err := s.usersServiceClient.CreateTeam(ctx, organizationID, userID, request)
+	if err != nil {
+		return nil, fmt.Errorf("[CreateTeam] create team:%w", err)
+	}
+
+	return team, nil
+}
+
+func (s *GatewayService) GetTeams(ctx context.Context, organizationID int) ([]models.TeamDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.GetTeams")
+	defer span.Finish()
+
+	userID, ok := ctxutil.GetUserIDFromContext(ctx)
+	if !ok {
+		return nil, app_errors.ErrNoUserInContext
+	}
+
+	teams, err := s.usersServiceClient.GetTeams(ctx, organizationID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("[GetTeams] get teams:%w", err)
+	}
+
+	return teams, nil
+}
+
+func (s *GatewayService) AddTeamMember(ctx context.Context, organizationID, teamID int, request *models.TeamMemberDTO) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.AddTeamMember")
+	defer span.Finish()
+
+	userID, ok := ctxutil.GetUserIDFromContext(ctx)
+	if !ok {
+		return app_errors.ErrNoUserInContext
+	}
+
+	err := s.usersServiceClient.AddTeamMember(ctx, organizationID, teamID, userID, request.UserID)
+	if err != nil {
+		return fmt.Errorf("[AddTeamMember] add member:%w", err)
+	}
+
+	return nil
+}
+
+func (s *GatewayService) RemoveTeamMember(ctx context.Context, organizationID, teamID, memberID int) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.RemoveTeamMember")
+	defer span.Finish()
+
+	userID, ok := ctxutil.GetUserIDFromContext(ctx)
+	if !ok {
+		return app_errors.ErrNoUserInContext
+	}
+
+	err := s.usersServiceClient.RemoveTeamMember(ctx, organizationID, teamID, userID, memberID)
+	if err != nil {
+		return fmt.Errorf("[RemoveTeamMember] remove member:%w", err)
+	}
+
+	return nil
+}
+
+func (s *GatewayService) ListTeamMembers(ctx context.Context, organizationID, teamID int) ([]models.OrganizationMemberDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.ListTeamMembers")
+	defer span.Finish()
+
+	userID, ok := ctxutil.GetUserIDFromContext(ctx)
+	if !ok {
+		return nil, app_errors.ErrNoUserInContext
+	}
+
+	members, err := s.usersServiceClient.ListTeamMembers(ctx, organizationID, teamID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("[ListTeamMembers] get members:%w", err)
+	}
+
+	return members, nil
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"gateway/internal/app_errors"
+	"gateway/internal/models"
+	"gateway/pkg/ctxutil"
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go"
+)
+
+func (s *GatewayService) CreateSavedFilter(ctx context.Context, newFilter *models.SavedFilterDTO) (*models.SavedFilterDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.CreateSavedFilter")
+	defer span.Finish()
+
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("[CreateSavedFilter] %w", err)
+	}
+
+	// владельцем фильтра всегда становится отправитель запроса
+	newFilter.OwnerID = caller.UserID
+	newFilter.CurrentUserID = caller.UserID
+	newFilter.CurrentUserPermissions = caller.Permissions
+	newFilter.Timezone, _ = ctxutil.GetTimezoneFromContext(ctx)
+
+	filter, err := s.todoServiceClient.CreateSavedFilter(ctx, newFilter)
+	if err != nil {
+		return nil, fmt.Errorf("[CreateSavedFilter] create filter:%w", err)
+	}
+
+	// возвращаем данные в слой хэндлера
+	return filter, nil
+}
+
+func (s *GatewayService) UpdateSavedFilter(ctx context.Context, newFilter *models.SavedFilterDTO) (*models.SavedFilterDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.UpdateSavedFilter")
+	defer span.Finish()
+
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("[UpdateSavedFilter] %w", err)
+	}
+	newFilter.CurrentUserID = caller.UserID
+	newFilter.CurrentUserPermissions = caller.Permissions
+	newFilter.Timezone, _ = ctxutil.GetTimezoneFromContext(ctx)
+
+	filter, err := s.todoServiceClient.UpdateSavedFilter(ctx, newFilter)
+	if err != nil {
+		return nil, fmt.Errorf("[UpdateSavedFilter] update filter:%w", err)
+	}
+
+	// возвращаем данные в слой хэндлера
+	return filter, nil
+}
+
+func (s *GatewayService) DeleteSavedFilter(ctx context.Context, filterID uuid.UUID) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.DeleteSavedFilter")
+	defer span.Finish()
+
+	senderID, ok := ctxutil.GetUserIDFromContext(ctx)
+	if !ok {
+		return app_errors.ErrNoUserInContext
+	}
+
+	err := s.todoServiceClient.DeleteSavedFilter(ctx, filterID, senderID)
+	if err != nil {
+		return fmt.Errorf("[DeleteSavedFilter] delete filter:%w", err)
+	}
+
+	// возвращаем данные в слой хэндлера
+	return nil
+}
+
+func (s *GatewayService) GetSavedFilters(ctx context.Context) ([]models.SavedFilterDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.GetSavedFilters")
+	defer span.Finish()
+
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("[GetSavedFilters] %w", err)
+	}
+
+	timezone, _ := ctxutil.GetTimezoneFromContext(ctx)
+
+	filters, err := s.todoServiceClient.GetSavedFilters(ctx, caller, timezone)
+	if err != nil {
+		return nil, fmt.Errorf("[GetSavedFilters] get filters:%w", err)
+	}
+
+	// возвращаем данные в слой хэндлера
+	return filters, nil
+}
+
+func (s *GatewayService) RunSavedFilter(ctx context.Context, filterID uuid.UUID) ([]models.TodoDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.RunSavedFilter")
+	defer span.Finish()
+
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("[RunSavedFilter] %w", err)
+	}
+
+	timezone, _ := ctxutil.GetTimezoneFromContext(ctx)
+
+	storedTodos, err := s.todoServiceClient.RunSavedFilter(ctx, filterID, caller, timezone)
+	if err != nil {
+		return nil, fmt.Errorf("[RunSavedFilter] run filter:%w", err)
+	}
+
+	// возвращаем данные в слой хэндлера
+	return storedTodos, nil
+}
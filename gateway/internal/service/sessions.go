@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gateway/internal/app_errors"
+	"gateway/internal/models"
+	"gateway/pkg/ctxutil"
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go"
+)
+
+// Logout завершает сессию, которой принадлежит токен запроса. Refresh токены этой сессии
+// больше не принимаются, access токен остается действительным до истечения своего короткого срока.
+func (s *GatewayService) Logout(ctx context.Context) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.Logout")
+	defer span.Finish()
+
+	userID, ok := ctxutil.GetUserIDFromContext(ctx)
+	if !ok {
+		return app_errors.ErrNoUserInContext
+	}
+
+	sessionID, ok := ctxutil.GetSessionIDFromContext(ctx)
+	if !ok {
+		return app_errors.ErrNoUserInContext
+	}
+
+	// повторный выход из уже завершенной сессии не считается ошибкой
+	err := s.usersServiceClient.RevokeSession(ctx, sessionID, userID)
+	if err != nil && !errors.Is(err, app_errors.ErrSessionNotFound) {
+		return fmt.Errorf("[Logout] revoke session:%w", err)
+	}
+
+	return nil
+}
+
+// LogoutAll завершает все сессии пользователя на всех устройствах
+func (s *GatewayService) LogoutAll(ctx context.Context) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.LogoutAll")
+	defer span.Finish()
+
+	userID, ok := ctxutil.GetUserIDFromContext(ctx)
+	if !ok {
+		return app_errors.ErrNoUserInContext
+	}
+
+	err := s.usersServiceClient.RevokeUserSessions(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("[LogoutAll] revoke sessions:%w", err)
+	}
+
+	return nil
+}
+
+// startSession заводит сессию после успешного входа и выдает ее первую пару токенов
+func (s *GatewayService) startSession(ctx context.Context, user *models.UserDTO) (*models.UserTokens, error) {
+	refreshTokenID := uuid.New()
+	sessionID, err := s.usersServiceClient.CreateSession(ctx, user.ID, refreshTokenID, s.jwtUtil.RefreshTokenExp)
+	if err != nil {
+		return nil, fmt.Errorf("create session:%w", err)
+	}
+
+	return s.generateTokens(user, sessionID, refreshTokenID)
+}
+
+// generateTokens подписывает пару токенов сессии. Роль, разрешения, организации, команды
+// и часовой пояс пользователя попадают в access токен
+func (s *GatewayService) generateTokens(user *models.UserDTO, sessionID, refreshTokenID uuid.UUID) (*models.UserTokens, error) {
+	accessToken, err := s.jwtUtil.GenerateAccessToken(user.ID, sessionID, user.Role, user.Permissions, user.OrganizationIDs, user.TeamIDs, user.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("generate access token:%w", err)
+	}
+
+	refreshToken, err := s.jwtUtil.GenerateRefreshToken(user.ID, sessionID, refreshTokenID)
+	if err != nil {
+		return nil, fmt.Errorf("generate refresh token:%w", err)
+	}
+
+	return &models.UserTokens{
+		AccessToken:            accessToken,
+		RefreshToken:           refreshToken,
+		TwoFactorSetupRequired: user.TwoFactorSetupRequired,
+	}, nil
+}
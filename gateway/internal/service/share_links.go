@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"gateway/internal/app_errors"
+	"gateway/internal/models"
+	"gateway/pkg/ctxutil"
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go"
+)
+
+func (s *GatewayService) CreateShareLink(ctx context.Context, todoID uuid.UUID, request *models.CreateShareLinkDTO) (*models.ShareLinkDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.CreateShareLink")
+	defer span.Finish()
+
+	senderID, ok := ctxutil.GetUserIDFromContext(ctx)
+	if !ok {
+		return nil, app_errors.ErrNoUserInContext
+	}
+
+	link, err := s.todoServiceClient.CreateShareLink(ctx, todoID, senderID, request)
+	if err != nil {
+		return nil, fmt.Errorf("[CreateShareLink] create link:%w", err)
+	}
+
+	// возвращаем данные в слой хэндлера
+	return link, nil
+}
+
+func (s *GatewayService) GetShareLinks(ctx context.Context, todoID uuid.UUID) ([]models.ShareLinkDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.GetShareLinks")
+	defer span.Finish()
+
+	senderID, ok := ctxutil.GetUserIDFromContext(ctx)
+	if !ok {
+		return nil, app_errors.ErrNoUserInContext
+	}
+
+	links, err := s.todoServiceClient.GetShareLinks(ctx, todoID, senderID)
+	if err != nil {
+		return nil, fmt.Errorf("[GetShareLinks] get links:%w", err)
+	}
+
+	// возвращаем данные в слой хэндлера
+	return links, nil
+}
+
+func (s *GatewayService) RevokeShareLink(ctx context.Context, linkID uuid.UUID) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.RevokeShareLink")
+	defer span.Finish()
+
+	senderID, ok := ctxutil.GetUserIDFromContext(ctx)
+	if !ok {
+		return app_errors.ErrNoUserInContext
+	}
+
+	err := s.todoServiceClient.RevokeShareLink(ctx, linkID, senderID)
+	if err != nil {
+		return fmt.Errorf("[RevokeShareLink] revoke link:%w", err)
+	}
+
+	// возвращаем данные в слой хэндлера
+	return nil
+}
+
+// GetSharedTodo вызывается без пользователя в контексте: доступ определяется только токеном ссылки
+func (s *GatewayService) GetSharedTodo(ctx context.Context, token string) (*models.SharedTodoDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.GetSharedTodo")
+	defer span.Finish()
+
+	sharedTodo, err := s.todoServiceClient.GetSharedTodo(ctx, token)
+	if err != nil {
+		return nil, fmt.Errorf("[GetSharedTodo] get todo:%w", err)
+	}
+
+	// возвращаем данные в слой хэндлера
+	return sharedTodo, nil
+}
@@ -3,7 +3,9 @@ package service
 import (
 	"context"
 	"fmt"
+	"gateway/internal/app_errors"
 	"gateway/internal/models"
+	"gateway/pkg/ctxutil"
 	"github.com/google/uuid"
 	"github.com/opentracing/opentracing-go"
 )
@@ -12,7 +14,15 @@ func (s *GatewayService) CreateToDo(ctx context.Context, newTodo *models.CreateT
 	span, ctx := opentracing.StartSpanFromContext(ctx, "service.CreateToDo")
 	defer span.Finish()
 
-	todo, err := s.todoServiceClient.CreateToDo(ctx, newTodo)
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("[CreateToDo] %w", err)
+	}
+
+	// создателем задачи всегда становится отправитель запроса
+	newTodo.CreatedBy = caller.UserID
+
+	todo, err := s.todoServiceClient.CreateToDo(ctx, newTodo, caller)
 	if err != nil {
 		return nil, fmt.Errorf("[CreateToDo] create todo:%w", err)
 	}
@@ -25,7 +35,13 @@ func (s *GatewayService) UpdateToDo(ctx context.Context, newTodo *models.TodoDTO
 	span, ctx := opentracing.StartSpanFromContext(ctx, "service.UpdateToDo")
 	defer span.Finish()
 
-	todo, err := s.todoServiceClient.UpdateToDo(ctx, newTodo)
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("[UpdateToDo] %w", err)
+	}
+
+	// право изменять задачу проверяет сервис todo
+	todo, err := s.todoServiceClient.UpdateToDo(ctx, newTodo, caller)
 	if err != nil {
 		return nil, fmt.Errorf("[UpdateToDo] update todo:%w", err)
 	}
@@ -34,10 +50,21 @@ func (s *GatewayService) UpdateToDo(ctx context.Context, newTodo *models.TodoDTO
 	return todo, nil
 }
 
+// GetToDos ищет задачи, которые видит отправитель запроса: свои, а при разрешении todos:read - любые
 func (s *GatewayService) GetToDos(ctx context.Context, todos *models.GetTodosDTO) ([]models.TodoDTO, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "service.GetToDos")
 	defer span.Finish()
 
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("[GetToDos] %w", err)
+	}
+
+	// "me" в выражении фильтра означает отправителя запроса
+	todos.CurrentUserID = caller.UserID
+	todos.CurrentUserPermissions = caller.Permissions
+	todos.Timezone, _ = ctxutil.GetTimezoneFromContext(ctx)
+
 	storedTodos, err := s.todoServiceClient.GetToDos(ctx, todos)
 	if err != nil {
 		return nil, fmt.Errorf("[GetToDos] get todos:%w", err)
@@ -47,11 +74,40 @@ func (s *GatewayService) GetToDos(ctx context.Context, todos *models.GetTodosDTO
 	return storedTodos, nil
 }
 
+// GetMyToDos ищет только задачи, которые отправитель запроса создал или исполняет, независимо от его роли
+func (s *GatewayService) GetMyToDos(ctx context.Context, todos *models.GetTodosDTO) ([]models.TodoDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.GetMyToDos")
+	defer span.Finish()
+
+	senderID, ok := ctxutil.GetUserIDFromContext(ctx)
+	if !ok {
+		return nil, app_errors.ErrNoUserInContext
+	}
+
+	// без разрешений сервис todo ограничивает выборку задачами пользователя
+	todos.CurrentUserID = senderID
+	todos.CurrentUserPermissions = nil
+	todos.Timezone, _ = ctxutil.GetTimezoneFromContext(ctx)
+
+	storedTodos, err := s.todoServiceClient.GetToDos(ctx, todos)
+	if err != nil {
+		return nil, fmt.Errorf("[GetMyToDos] get todos:%w", err)
+	}
+
+	// возвращаем данные в слой хэндлера
+	return storedTodos, nil
+}
+
 func (s *GatewayService) GetToDo(ctx context.Context, todoID uuid.UUID) (*models.TodoDTO, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "service.GetToDo")
 	defer span.Finish()
 
-	todo, err := s.todoServiceClient.GetToDo(ctx, todoID)
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("[GetToDo] %w", err)
+	}
+
+	todo, err := s.todoServiceClient.GetToDo(ctx, todoID, caller)
 	if err != nil {
 		return nil, fmt.Errorf("[GetToDo] get todo:%w", err)
 	}
@@ -64,7 +120,13 @@ func (s *GatewayService) DeleteToDo(ctx context.Context, todoID uuid.UUID) error
 	span, ctx := opentracing.StartSpanFromContext(ctx, "service.DeleteToDo")
 	defer span.Finish()
 
-	err := s.todoServiceClient.DeleteToDo(ctx, todoID)
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return fmt.Errorf("[DeleteToDo] %w", err)
+	}
+
+	// право удалять задачу проверяет сервис todo
+	err = s.todoServiceClient.DeleteToDo(ctx, todoID, caller)
 	if err != nil {
 		return fmt.Errorf("[DeleteToDo] delete todo:%w", err)
 	}
@@ -72,3 +134,18 @@ func (s *GatewayService) DeleteToDo(ctx context.Context, todoID uuid.UUID) error
 	// возвращаем данные в слой хэндлера
 	return nil
 }
+
+// callerFromContext возвращает пользователя из токена вместе с разрешениями его роли
+func callerFromContext(ctx context.Context) (*models.Caller, error) {
+	senderID, ok := ctxutil.GetUserIDFromContext(ctx)
+	if !ok {
+		return nil, app_errors.ErrNoUserInContext
+	}
+
+	permissions, _ := ctxutil.GetPermissionsFromContext(ctx)
+
+	return &models.Caller{
+		UserID:      senderID,
+		Permissions: permissions,
+	}, nil
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"gateway/internal/app_errors"
+	"gateway/internal/models"
+	"gateway/pkg/ctxutil"
+	"github.com/opentracing/opentracing-go"
+)
+
+// LoginTwoFactor - второй шаг входа. Сессия заводится и токены выдаются только после проверки кода.
+func (s *GatewayService) LoginTwoFactor(ctx context.Context, request *models.TwoFactorLoginDTO) (*models.UserTokens, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.LoginTwoFactor")
+	defer span.Finish()
+
+	existingUser, err := s.usersServiceClient.LoginTwoFactor(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("[LoginTwoFactor] log in: %w", err)
+	}
+
+	tokens, err := s.startSession(ctx, existingUser)
+	if err != nil {
+		return nil, fmt.Errorf("[LoginTwoFactor] %w", err)
+	}
+
+	return tokens, nil
+}
+
+func (s *GatewayService) EnrollTwoFactor(ctx context.Context) (*models.TwoFactorEnrollmentDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.EnrollTwoFactor")
+	defer span.Finish()
+
+	userID, ok := ctxutil.GetUserIDFromContext(ctx)
+	if !ok {
+		return nil, app_errors.ErrNoUserInContext
+	}
+
+	enrollment, err := s.usersServiceClient.EnrollTwoFactor(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("[EnrollTwoFactor] enroll:%w", err)
+	}
+
+	return enrollment, nil
+}
+
+func (s *GatewayService) ConfirmTwoFactor(ctx context.Context, request *models.TwoFactorCodeDTO) (*models.RecoveryCodesDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.ConfirmTwoFactor")
+	defer span.Finish()
+
+	userID, ok := ctxutil.GetUserIDFromContext(ctx)
+	if !ok {
+		return nil, app_errors.ErrNoUserInContext
+	}
+
+	codes, err := s.usersServiceClient.ConfirmTwoFactor(ctx, userID, request.Code)
+	if err != nil {
+		return nil, fmt.Errorf("[ConfirmTwoFactor] confirm:%w", err)
+	}
+
+	return codes, nil
+}
+
+func (s *GatewayService) DisableTwoFactor(ctx context.Context, request *models.TwoFactorCodeDTO) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.DisableTwoFactor")
+	defer span.Finish()
+
+	userID, ok := ctxutil.GetUserIDFromContext(ctx)
+	if !ok {
+		return app_errors.ErrNoUserInContext
+	}
+
+	err := s.usersServiceClient.DisableTwoFactor(ctx, userID, request.Code)
+	if err != nil {
+		return fmt.Errorf("[DisableTwoFactor] disable:%w", err)
+	}
+
+	return nil
+}
+
+func (s *GatewayService) RegenerateRecoveryCodes(ctx context.Context, request *models.TwoFactorCodeDTO) (*models.RecoveryCodesDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.RegenerateRecoveryCodes")
+	defer span.Finish()
+
+	userID, ok := ctxutil.GetUserIDFromContext(ctx)
+	if !ok {
+		return nil, app_errors.ErrNoUserInContext
+	}
+
+	codes, err := s.usersServiceClient.RegenerateRecoveryCodes(ctx, userID, request.Code)
+	if err != nil {
+		return nil, fmt.Errorf("[RegenerateRecoveryCodes] regenerate:%w", err)
+	}
+
+	return codes, nil
+}
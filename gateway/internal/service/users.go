@@ -6,6 +6,7 @@ import (
 	"gateway/internal/app_errors"
 	"gateway/internal/models"
 	"gateway/pkg/ctxutil"
+	"github.com/google/uuid"
 	"github.com/opentracing/opentracing-go"
 )
 
@@ -27,39 +28,21 @@ func (s *GatewayService) UpdateUser(ctx context.Context, updatedUser *models.Use
 	span, ctx := opentracing.StartSpanFromContext(ctx, "service.UpdateUser")
 	defer span.Finish()
 
-	senderID, ok := ctxutil.GetUserIDFromContext(ctx)
-	if !ok {
-		return nil, app_errors.ErrNoUserInContext
-	}
-
-	if senderID != updatedUser.ID {
-		return nil, app_errors.NewUserIDMismatchError("UpdateUser", senderID, updatedUser.ID)
-	}
-
-	// Передаем данные в слой репозитория
-	err := s.usersServiceClient.UpdateUser(ctx, updatedUser)
+	// Передаем данные в слой репозитория. Право изменять пользователя проверено на маршруте
+	user, err := s.usersServiceClient.UpdateUser(ctx, updatedUser)
 	if err != nil {
 		return nil, fmt.Errorf("[UpdateUser] update user:%w", err)
 	}
 
 	// возвращаем данные в слой хэндлера
-	return updatedUser, nil
+	return user, nil
 }
 
 func (s *GatewayService) UpdatePassword(ctx context.Context, request *models.UpdateUserPasswordDTO) error {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "service.UpdatePassword")
 	defer span.Finish()
 
-	senderID, ok := ctxutil.GetUserIDFromContext(ctx)
-	if !ok {
-		return app_errors.ErrNoUserInContext
-	}
-
-	if senderID != request.ID {
-		return app_errors.NewUserIDMismatchError("UpdatePassword", senderID, request.ID)
-	}
-
-	// Обновление пароля в базе данных
+	// Обновление пароля в базе данных. Пароль меняется только себе, ID берется из токена
 	err := s.usersServiceClient.UpdatePassword(ctx, request)
 	if err != nil {
 		return fmt.Errorf("[UpdatePassword] verify pass:%w", err)
@@ -73,16 +56,7 @@ func (s *GatewayService) DeleteUser(ctx context.Context, userID int) error {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "service.DeleteUser")
 	defer span.Finish()
 
-	senderID, ok := ctxutil.GetUserIDFromContext(ctx)
-	if !ok {
-		return app_errors.ErrNoUserInContext
-	}
-
-	if senderID != userID {
-		return app_errors.NewUserIDMismatchError("DeleteUser", senderID, userID)
-	}
-
-	// Удаление пользователя.
+	// Удаление пользователя. Право удалять пользователя проверено на маршруте
 	err := s.usersServiceClient.DeleteUser(ctx, userID)
 	if err != nil {
 		return fmt.Errorf("[DeleteUser] delete user:%w", err)
@@ -91,6 +65,19 @@ func (s *GatewayService) DeleteUser(ctx context.Context, userID int) error {
 	return nil
 }
 
+// GetUserDeletion возвращает состояние последнего удаления пользователя. Право удалять пользователя проверено на маршруте
+func (s *GatewayService) GetUserDeletion(ctx context.Context, userID int) (*models.UserDeletionDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.GetUserDeletion")
+	defer span.Finish()
+
+	deletion, err := s.usersServiceClient.GetUserDeletion(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("[GetUserDeletion] get deletion:%w", err)
+	}
+
+	return deletion, nil
+}
+
 func (s *GatewayService) GetUserByID(ctx context.Context, userID int) (*models.UserDTO, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "service.GetUserByID")
 	defer span.Finish()
@@ -105,55 +92,333 @@ func (s *GatewayService) GetUserByID(ctx context.Context, userID int) (*models.U
 	return storedUser, nil
 }
 
+// ListUsers ищет пользователей для выбора исполнителя. Email виден и участвует в поиске
+// только у пользователей с разрешением users:read, остальным достаются id и username
+func (s *GatewayService) ListUsers(ctx context.Context, filter *models.ListUsersDTO) (*models.UsersPageDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.ListUsers")
+	defer span.Finish()
+
+	filter.IncludeEmail = ctxutil.HasPermission(ctx, models.PermissionUsersRead)
+
+	page, err := s.usersServiceClient.ListUsers(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("[ListUsers] list users:%w", err)
+	}
+
+	return page, nil
+}
+
 func (s *GatewayService) Login(ctx context.Context, login *models.UserLoginDTO) (*models.UserTokens, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "service.Login")
 	defer span.Finish()
 
+	// users ограничивает число неудачных попыток входа с одного адреса
+	login.ClientIP, _ = ctxutil.GetClientIPFromContext(ctx)
+
 	// Проверка наличия пользователя.
 	existingUser, err := s.usersServiceClient.UserLogin(ctx, login)
 	if err != nil {
 		return nil, fmt.Errorf("[Login] log in: %w", err)
 	}
 
-	// Генерируем токены и возвращаем
-	accessToken, err := s.jwtUtil.GenerateAccessToken(existingUser.ID)
-	if err != nil {
-		return nil, fmt.Errorf("[Login] generate access token:%w", err)
+	// С включенным вторым фактором токены выдаются только после проверки кода в LoginTwoFactor
+	if existingUser.TwoFactorRequired {
+		return &models.UserTokens{
+			TwoFactorRequired: true,
+			ChallengeToken:    existingUser.ChallengeToken,
+		}, nil
 	}
 
-	refreshToken, err := s.jwtUtil.GenerateRefreshToken(existingUser.ID)
+	tokens, err := s.startSession(ctx, existingUser)
 	if err != nil {
-		return nil, fmt.Errorf("[Login] generate refresh token:%w", err)
+		return nil, fmt.Errorf("[Login] %w", err)
 	}
 
-	return &models.UserTokens{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-	}, nil
+	return tokens, nil
 }
 
 func (s *GatewayService) Refresh(ctx context.Context, refresh string) (*models.UserTokens, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "service.Refresh")
 	defer span.Finish()
 
-	userID, err := s.jwtUtil.VerifyToken(refresh)
+	claims, err := s.jwtUtil.VerifyRefreshToken(refresh)
+	if err != nil {
+		return nil, fmt.Errorf("[Refresh] verify token: %w: %s", app_errors.ErrInvalidRefreshToken, err)
+	}
+
+	// Каждый refresh токен одноразовый: сессия запоминает новый, а повторное предъявление старого ее завершает
+	refreshTokenID := uuid.New()
+	err = s.usersServiceClient.RotateSession(ctx, claims.SessionID, claims.TokenID, refreshTokenID, s.jwtUtil.RefreshTokenExp)
+	if err != nil {
+		return nil, fmt.Errorf("[Refresh] rotate session:%w", err)
+	}
+
+	// роль могла смениться с прошлого входа, поэтому разрешения для нового access токена берутся заново
+	existingUser, err := s.usersServiceClient.GetUserByID(ctx, claims.UserID)
 	if err != nil {
-		return nil, fmt.Errorf("[Refresh] verify token:%w", err)
+		return nil, fmt.Errorf("[Refresh] get user:%w", err)
 	}
 
-	// Генерируем токены и возвращаем
-	accessToken, err := s.jwtUtil.GenerateAccessToken(userID)
+	tokens, err := s.generateTokens(existingUser, claims.SessionID, refreshTokenID)
 	if err != nil {
-		return nil, fmt.Errorf("[Refresh] generate access token:%w", err)
+		return nil, fmt.Errorf("[Refresh] %w", err)
+	}
+
+	return tokens, nil
+}
+
+func (s *GatewayService) VerifyEmail(ctx context.Context, token string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.VerifyEmail")
+	defer span.Finish()
+
+	err := s.usersServiceClient.VerifyEmail(ctx, token)
+	if err != nil {
+		return fmt.Errorf("[VerifyEmail] verify:%w", err)
+	}
+
+	return nil
+}
+
+func (s *GatewayService) ResendVerificationEmail(ctx context.Context, email string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.ResendVerificationEmail")
+	defer span.Finish()
+
+	err := s.usersServiceClient.ResendVerificationEmail(ctx, email)
+	if err != nil {
+		return fmt.Errorf("[ResendVerificationEmail] resend:%w", err)
+	}
+
+	return nil
+}
+
+func (s *GatewayService) RequestPasswordReset(ctx context.Context, email string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.RequestPasswordReset")
+	defer span.Finish()
+
+	err := s.usersServiceClient.RequestPasswordReset(ctx, email)
+	if err != nil {
+		return fmt.Errorf("[RequestPasswordReset] request reset:%w", err)
+	}
+
+	return nil
+}
+
+func (s *GatewayService) ResetPassword(ctx context.Context, request *models.ResetPasswordDTO) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.ResetPassword")
+	defer span.Finish()
+
+	err := s.usersServiceClient.ResetPassword(ctx, request)
+	if err != nil {
+		return fmt.Errorf("[ResetPassword] reset:%w", err)
+	}
+
+	return nil
+}
+
+// RequestEmailChange начинает смену email отправителя запроса. Адрес меняется после подтверждения по ссылке из письма
+func (s *GatewayService) RequestEmailChange(ctx context.Context, request *models.RequestEmailChangeDTO) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.RequestEmailChange")
+	defer span.Finish()
+
+	senderID, ok := ctxutil.GetUserIDFromContext(ctx)
+	if !ok {
+		return app_errors.ErrNoUserInContext
+	}
+
+	err := s.usersServiceClient.RequestEmailChange(ctx, senderID, request)
+	if err != nil {
+		return fmt.Errorf("[RequestEmailChange] request:%w", err)
+	}
+
+	return nil
+}
+
+func (s *GatewayService) ConfirmEmailChange(ctx context.Context, token string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.ConfirmEmailChange")
+	defer span.Finish()
+
+	err := s.usersServiceClient.ConfirmEmailChange(ctx, token)
+	if err != nil {
+		return fmt.Errorf("[ConfirmEmailChange] confirm:%w", err)
+	}
+
+	return nil
+}
+
+// RequestDataExport начинает выгрузку данных отправителя запроса. Архив собирается асинхронно,
+// ссылка на него уходит на email, а за состоянием можно следить через GetDataExport
+func (s *GatewayService) RequestDataExport(ctx context.Context) (*models.DataExportDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.RequestDataExport")
+	defer span.Finish()
+
+	senderID, ok := ctxutil.GetUserIDFromContext(ctx)
+	if !ok {
+		return nil, app_errors.ErrNoUserInContext
+	}
+
+	export, err := s.usersServiceClient.RequestDataExport(ctx, senderID)
+	if err != nil {
+		return nil, fmt.Errorf("[RequestDataExport] request:%w", err)
+	}
+
+	return export, nil
+}
+
+// GetDataExport возвращает состояние последней выгрузки данных отправителя запроса
+func (s *GatewayService) GetDataExport(ctx context.Context) (*models.DataExportDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.GetDataExport")
+	defer span.Finish()
+
+	senderID, ok := ctxutil.GetUserIDFromContext(ctx)
+	if !ok {
+		return nil, app_errors.ErrNoUserInContext
+	}
+
+	export, err := s.usersServiceClient.GetDataExport(ctx, senderID)
+	if err != nil {
+		return nil, fmt.Errorf("[GetDataExport] get export:%w", err)
+	}
+
+	return export, nil
+}
+
+// DownloadDataExport возвращает архив выгрузки по токену из письма, токен заменяет авторизацию
+func (s *GatewayService) DownloadDataExport(ctx context.Context, token string) (*models.DataExportArchive, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.DownloadDataExport")
+	defer span.Finish()
+
+	archive, err := s.usersServiceClient.DownloadDataExport(ctx, token)
+	if err != nil {
+		return nil, fmt.Errorf("[DownloadDataExport] download:%w", err)
+	}
+
+	return archive, nil
+}
+
+// RequestErasure начинает удаление аккаунта отправителя запроса. Аккаунт удаляется после подтверждения по ссылке из письма
+func (s *GatewayService) RequestErasure(ctx context.Context, request *models.RequestErasureDTO) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.RequestErasure")
+	defer span.Finish()
+
+	senderID, ok := ctxutil.GetUserIDFromContext(ctx)
+	if !ok {
+		return app_errors.ErrNoUserInContext
+	}
+
+	err := s.usersServiceClient.RequestErasure(ctx, senderID, request)
+	if err != nil {
+		return fmt.Errorf("[RequestErasure] request:%w", err)
+	}
+
+	return nil
+}
+
+func (s *GatewayService) ConfirmErasure(ctx context.Context, token string) (*models.UserDeletionDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.ConfirmErasure")
+	defer span.Finish()
+
+	deletion, err := s.usersServiceClient.ConfirmErasure(ctx, token)
+	if err != nil {
+		return nil, fmt.Errorf("[ConfirmErasure] confirm:%w", err)
+	}
+
+	return deletion, nil
+}
+
+// SetUserRole назначает пользователю роль. Новая роль действует с ближайшего обновления токенов пользователя
+func (s *GatewayService) SetUserRole(ctx context.Context, userID int, request *models.SetUserRoleDTO) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.SetUserRole")
+	defer span.Finish()
+
+	err := s.usersServiceClient.SetUserRole(ctx, userID, request.Role)
+	if err != nil {
+		return fmt.Errorf("[SetUserRole] set role:%w", err)
+	}
+
+	return nil
+}
+
+// UnlockUser снимает блокировку входа, наложенную после серии неудачных попыток
+func (s *GatewayService) UnlockUser(ctx context.Context, userID int) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.UnlockUser")
+	defer span.Finish()
+
+	err := s.usersServiceClient.UnlockUser(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("[UnlockUser] unlock:%w", err)
+	}
+
+	return nil
+}
+
+// UploadAvatar заменяет аватар отправителя запроса. Изображение проверяет и уменьшает сервис users
+func (s *GatewayService) UploadAvatar(ctx context.Context, image []byte) (*models.UserDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.UploadAvatar")
+	defer span.Finish()
+
+	senderID, ok := ctxutil.GetUserIDFromContext(ctx)
+	if !ok {
+		return nil, app_errors.ErrNoUserInContext
+	}
+
+	user, err := s.usersServiceClient.UploadAvatar(ctx, senderID, image)
+	if err != nil {
+		return nil, fmt.Errorf("[UploadAvatar] upload:%w", err)
+	}
+
+	return user, nil
+}
+
+// DeleteAvatar удаляет аватар отправителя запроса
+func (s *GatewayService) DeleteAvatar(ctx context.Context) (*models.UserDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.DeleteAvatar")
+	defer span.Finish()
+
+	senderID, ok := ctxutil.GetUserIDFromContext(ctx)
+	if !ok {
+		return nil, app_errors.ErrNoUserInContext
+	}
+
+	user, err := s.usersServiceClient.DeleteAvatar(ctx, senderID)
+	if err != nil {
+		return nil, fmt.Errorf("[DeleteAvatar] delete:%w", err)
+	}
+
+	return user, nil
+}
+
+// GetNotificationPreferences возвращает настройки уведомлений отправителя запроса
+func (s *GatewayService) GetNotificationPreferences(ctx context.Context) (*models.NotificationPreferencesDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.GetNotificationPreferences")
+	defer span.Finish()
+
+	senderID, ok := ctxutil.GetUserIDFromContext(ctx)
+	if !ok {
+		return nil, app_errors.ErrNoUserInContext
+	}
+
+	preferences, err := s.usersServiceClient.GetNotificationPreferences(ctx, senderID)
+	if err != nil {
+		return nil, fmt.Errorf("[GetNotificationPreferences] get:%w", err)
+	}
+
+	return preferences, nil
+}
+
+// UpdateNotificationPreferences меняет настройки уведомлений отправителя запроса
+func (s *GatewayService) UpdateNotificationPreferences(ctx context.Context, request *models.NotificationPreferencesDTO) (*models.NotificationPreferencesDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.UpdateNotificationPreferences")
+	defer span.Finish()
+
+	senderID, ok := ctxutil.GetUserIDFromContext(ctx)
+	if !ok {
+		return nil, app_errors.ErrNoUserInContext
 	}
 
-	refreshToken, err := s.jwtUtil.GenerateRefreshToken(userID)
+	preferences, err := s.usersServiceClient.UpdateNotificationPreferences(ctx, senderID, request)
 	if err != nil {
-		return nil, fmt.Errorf("[Refresh] generate refresh token:%w", err)
+		return nil, fmt.Errorf("[UpdateNotificationPreferences] update:%w", err)
 	}
 
-	return &models.UserTokens{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-	}, nil
+	return preferences, nil
 }
@@ -2,6 +2,7 @@ package ctxutil
 
 import (
 	"context"
+	"github.com/google/uuid"
 	"google.golang.org/grpc/metadata"
 )
 
@@ -14,6 +15,75 @@ func SetUserIDToContext(ctx context.Context, userID int) context.Context {
 	return context.WithValue(ctx, "UserID", userID)
 }
 
+func GetSessionIDFromContext(ctx context.Context) (uuid.UUID, bool) {
+	sessionID, ok := ctx.Value("SessionID").(uuid.UUID)
+	return sessionID, ok
+}
+
+func SetSessionIDToContext(ctx context.Context, sessionID uuid.UUID) context.Context {
+	return context.WithValue(ctx, "SessionID", sessionID)
+}
+
+func GetPermissionsFromContext(ctx context.Context) ([]string, bool) {
+	permissions, ok := ctx.Value("Permissions").([]string)
+	return permissions, ok
+}
+
+func SetPermissionsToContext(ctx context.Context, permissions []string) context.Context {
+	return context.WithValue(ctx, "Permissions", permissions)
+}
+
+// HasPermission сообщает, есть ли разрешение у пользователя, от имени которого выполняется запрос
+func HasPermission(ctx context.Context, permission string) bool {
+	permissions, _ := GetPermissionsFromContext(ctx)
+	for _, p := range permissions {
+		if p == permission {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetOrganizationIDsFromContext возвращает организации пользователя из access токена
+func GetOrganizationIDsFromContext(ctx context.Context) ([]int, bool) {
+	organizationIDs, ok := ctx.Value("OrganizationIDs").([]int)
+	return organizationIDs, ok
+}
+
+func SetOrganizationIDsToContext(ctx context.Context, organizationIDs []int) context.Context {
+	return context.WithValue(ctx, "OrganizationIDs", organizationIDs)
+}
+
+// GetTeamIDsFromContext возвращает команды пользователя из access токена
+func GetTeamIDsFromContext(ctx context.Context) ([]int, bool) {
+	teamIDs, ok := ctx.Value("TeamIDs").([]int)
+	return teamIDs, ok
+}
+
+func SetTeamIDsToContext(ctx context.Context, teamIDs []int) context.Context {
+	return context.WithValue(ctx, "TeamIDs", teamIDs)
+}
+
+// GetTimezoneFromContext возвращает часовой пояс пользователя из access токена
+func GetTimezoneFromContext(ctx context.Context) (string, bool) {
+	timezone, ok := ctx.Value("Timezone").(string)
+	return timezone, ok
+}
+
+func SetTimezoneToContext(ctx context.Context, timezone string) context.Context {
+	return context.WithValue(ctx, "Timezone", timezone)
+}
+
+func GetClientIPFromContext(ctx context.Context) (string, bool) {
+	clientIP, ok := ctx.Value("ClientIP").(string)
+	return clientIP, ok
+}
+
+func SetClientIPToContext(ctx context.Context, clientIP string) context.Context {
+	return context.WithValue(ctx, "ClientIP", clientIP)
+}
+
 func GetRequestIDFromContext(ctx context.Context) (string, bool) {
 	requestID, ok := ctx.Value("RequestID").(string)
 	return requestID, ok
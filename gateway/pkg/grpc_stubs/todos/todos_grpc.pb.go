@@ -24,6 +24,20 @@ type TodoServiceClient interface {
 	GetTodoById(ctx context.Context, in *TodoID, opts ...grpc.CallOption) (*FullTodoDTO, error)
 	GetToDos(ctx context.Context, in *GetTodosRequest, opts ...grpc.CallOption) (*GetTodosResponse, error)
 	DeleteTodo(ctx context.Context, in *TodoID, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Сохраненные фильтры (умные списки) пользователя
+	CreateSavedFilter(ctx context.Context, in *SavedFilterDTO, opts ...grpc.CallOption) (*SavedFilterDTO, error)
+	UpdateSavedFilter(ctx context.Context, in *SavedFilterDTO, opts ...grpc.CallOption) (*SavedFilterDTO, error)
+	DeleteSavedFilter(ctx context.Context, in *SavedFilterRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Возвращает свои и общие фильтры пользователя вместе с количеством подходящих задач
+	GetSavedFilters(ctx context.Context, in *GetSavedFiltersRequest, opts ...grpc.CallOption) (*GetSavedFiltersResponse, error)
+	// Возвращает задачи, подходящие под сохраненный фильтр
+	RunSavedFilter(ctx context.Context, in *SavedFilterRequest, opts ...grpc.CallOption) (*GetTodosResponse, error)
+	// Публичные ссылки только для чтения на отдельную задачу
+	CreateShareLink(ctx context.Context, in *CreateShareLinkRequest, opts ...grpc.CallOption) (*ShareLinkDTO, error)
+	GetShareLinks(ctx context.Context, in *ShareLinksRequest, opts ...grpc.CallOption) (*GetShareLinksResponse, error)
+	RevokeShareLink(ctx context.Context, in *ShareLinkRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Возвращает задачу по токену публичной ссылки, проверка пользователя не требуется
+	GetSharedTodo(ctx context.Context, in *SharedTodoRequest, opts ...grpc.CallOption) (*SharedTodoDTO, error)
 }
 
 type todoServiceClient struct {
@@ -79,6 +93,87 @@ func (c *todoServiceClient) DeleteTodo(ctx context.Context, in *TodoID, opts ...
 	return out, nil
 }
 
+func (c *todoServiceClient) CreateSavedFilter(ctx context.Context, in *SavedFilterDTO, opts ...grpc.CallOption) (*SavedFilterDTO, error) {
+	out := new(SavedFilterDTO)
+	err := c.cc.Invoke(ctx, "/todoservice.TodoService/CreateSavedFilter", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) UpdateSavedFilter(ctx context.Context, in *SavedFilterDTO, opts ...grpc.CallOption) (*SavedFilterDTO, error) {
+	out := new(SavedFilterDTO)
+	err := c.cc.Invoke(ctx, "/todoservice.TodoService/UpdateSavedFilter", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) DeleteSavedFilter(ctx context.Context, in *SavedFilterRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/todoservice.TodoService/DeleteSavedFilter", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) GetSavedFilters(ctx context.Context, in *GetSavedFiltersRequest, opts ...grpc.CallOption) (*GetSavedFiltersResponse, error) {
+	out := new(GetSavedFiltersResponse)
+	err := c.cc.Invoke(ctx, "/todoservice.TodoService/GetSavedFilters", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) RunSavedFilter(ctx context.Context, in *SavedFilterRequest, opts ...grpc.CallOption) (*GetTodosResponse, error) {
+	out := new(GetTodosResponse)
+	err := c.cc.Invoke(ctx, "/todoservice.TodoService/RunSavedFilter", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) CreateShareLink(ctx context.Context, in *CreateShareLinkRequest, opts ...grpc.CallOption) (*ShareLinkDTO, error) {
+	out := new(ShareLinkDTO)
+	err := c.cc.Invoke(ctx, "/todoservice.TodoService/CreateShareLink", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) GetShareLinks(ctx context.Context, in *ShareLinksRequest, opts ...grpc.CallOption) (*GetShareLinksResponse, error) {
+	out := new(GetShareLinksResponse)
+	err := c.cc.Invoke(ctx, "/todoservice.TodoService/GetShareLinks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) RevokeShareLink(ctx context.Context, in *ShareLinkRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/todoservice.TodoService/RevokeShareLink", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) GetSharedTodo(ctx context.Context, in *SharedTodoRequest, opts ...grpc.CallOption) (*SharedTodoDTO, error) {
+	out := new(SharedTodoDTO)
+	err := c.cc.Invoke(ctx, "/todoservice.TodoService/GetSharedTodo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // TodoServiceServer is the server API for TodoService service.
 // All implementations must embed UnimplementedTodoServiceServer
 // for forward compatibility
@@ -88,6 +183,20 @@ type TodoServiceServer interface {
 	GetTodoById(context.Context, *TodoID) (*FullTodoDTO, error)
 	GetToDos(context.Context, *GetTodosRequest) (*GetTodosResponse, error)
 	DeleteTodo(context.Context, *TodoID) (*emptypb.Empty, error)
+	// Сохраненные фильтры (умные списки) пользователя
+	CreateSavedFilter(context.Context, *SavedFilterDTO) (*SavedFilterDTO, error)
+	UpdateSavedFilter(context.Context, *SavedFilterDTO) (*SavedFilterDTO, error)
+	DeleteSavedFilter(context.Context, *SavedFilterRequest) (*emptypb.Empty, error)
+	// Возвращает свои и общие фильтры пользователя вместе с количеством подходящих задач
+	GetSavedFilters(context.Context, *GetSavedFiltersRequest) (*GetSavedFiltersResponse, error)
+	// Возвращает задачи, подходящие под сохраненный фильтр
+	RunSavedFilter(context.Context, *SavedFilterRequest) (*GetTodosResponse, error)
+	// Публичные ссылки только для чтения на отдельную задачу
+	CreateShareLink(context.Context, *CreateShareLinkRequest) (*ShareLinkDTO, error)
+	GetShareLinks(context.Context, *ShareLinksRequest) (*GetShareLinksResponse, error)
+	RevokeShareLink(context.Context, *ShareLinkRequest) (*emptypb.Empty, error)
+	// Возвращает задачу по токену публичной ссылки, проверка пользователя не требуется
+	GetSharedTodo(context.Context, *SharedTodoRequest) (*SharedTodoDTO, error)
 	mustEmbedUnimplementedTodoServiceServer()
 }
 
@@ -110,6 +219,33 @@ func (UnimplementedTodoServiceServer) GetToDos(context.Context, *GetTodosRequest
 func (UnimplementedTodoServiceServer) DeleteTodo(context.Context, *TodoID) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteTodo not implemented")
 }
+func (UnimplementedTodoServiceServer) CreateSavedFilter(context.Context, *SavedFilterDTO) (*SavedFilterDTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateSavedFilter not implemented")
+}
+func (UnimplementedTodoServiceServer) UpdateSavedFilter(context.Context, *SavedFilterDTO) (*SavedFilterDTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateSavedFilter not implemented")
+}
+func (UnimplementedTodoServiceServer) DeleteSavedFilter(context.Context, *SavedFilterRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteSavedFilter not implemented")
+}
+func (UnimplementedTodoServiceServer) GetSavedFilters(context.Context, *GetSavedFiltersRequest) (*GetSavedFiltersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSavedFilters not implemented")
+}
+func (UnimplementedTodoServiceServer) RunSavedFilter(context.Context, *SavedFilterRequest) (*GetTodosResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RunSavedFilter not implemented")
+}
+func (UnimplementedTodoServiceServer) CreateShareLink(context.Context, *CreateShareLinkRequest) (*ShareLinkDTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateShareLink not implemented")
+}
+func (UnimplementedTodoServiceServer) GetShareLinks(context.Context, *ShareLinksRequest) (*GetShareLinksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetShareLinks not implemented")
+}
+func (UnimplementedTodoServiceServer) RevokeShareLink(context.Context, *ShareLinkRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeShareLink not implemented")
+}
+func (UnimplementedTodoServiceServer) GetSharedTodo(context.Context, *SharedTodoRequest) (*SharedTodoDTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSharedTodo not implemented")
+}
 func (UnimplementedTodoServiceServer) mustEmbedUnimplementedTodoServiceServer() {}
 
 // UnsafeTodoServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -213,6 +349,168 @@ func _TodoService_DeleteTodo_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TodoService_CreateSavedFilter_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SavedFilterDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).CreateSavedFilter(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todoservice.TodoService/CreateSavedFilter",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).CreateSavedFilter(ctx, req.(*SavedFilterDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_UpdateSavedFilter_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SavedFilterDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).UpdateSavedFilter(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todoservice.TodoService/UpdateSavedFilter",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).UpdateSavedFilter(ctx, req.(*SavedFilterDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_DeleteSavedFilter_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SavedFilterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).DeleteSavedFilter(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todoservice.TodoService/DeleteSavedFilter",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).DeleteSavedFilter(ctx, req.(*SavedFilterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_GetSavedFilters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSavedFiltersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).GetSavedFilters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todoservice.TodoService/GetSavedFilters",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).GetSavedFilters(ctx, req.(*GetSavedFiltersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_RunSavedFilter_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SavedFilterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).RunSavedFilter(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todoservice.TodoService/RunSavedFilter",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).RunSavedFilter(ctx, req.(*SavedFilterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_CreateShareLink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateShareLinkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).CreateShareLink(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todoservice.TodoService/CreateShareLink",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).CreateShareLink(ctx, req.(*CreateShareLinkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_GetShareLinks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShareLinksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).GetShareLinks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todoservice.TodoService/GetShareLinks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).GetShareLinks(ctx, req.(*ShareLinksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_RevokeShareLink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShareLinkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).RevokeShareLink(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todoservice.TodoService/RevokeShareLink",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).RevokeShareLink(ctx, req.(*ShareLinkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_GetSharedTodo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SharedTodoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).GetSharedTodo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todoservice.TodoService/GetSharedTodo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).GetSharedTodo(ctx, req.(*SharedTodoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // TodoService_ServiceDesc is the grpc.ServiceDesc for TodoService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -240,6 +538,42 @@ var TodoService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteTodo",
 			Handler:    _TodoService_DeleteTodo_Handler,
 		},
+		{
+			MethodName: "CreateSavedFilter",
+			Handler:    _TodoService_CreateSavedFilter_Handler,
+		},
+		{
+			MethodName: "UpdateSavedFilter",
+			Handler:    _TodoService_UpdateSavedFilter_Handler,
+		},
+		{
+			MethodName: "DeleteSavedFilter",
+			Handler:    _TodoService_DeleteSavedFilter_Handler,
+		},
+		{
+			MethodName: "GetSavedFilters",
+			Handler:    _TodoService_GetSavedFilters_Handler,
+		},
+		{
+			MethodName: "RunSavedFilter",
+			Handler:    _TodoService_RunSavedFilter_Handler,
+		},
+		{
+			MethodName: "CreateShareLink",
+			Handler:    _TodoService_CreateShareLink_Handler,
+		},
+		{
+			MethodName: "GetShareLinks",
+			Handler:    _TodoService_GetShareLinks_Handler,
+		},
+		{
+			MethodName: "RevokeShareLink",
+			Handler:    _TodoService_RevokeShareLink_Handler,
+		},
+		{
+			MethodName: "GetSharedTodo",
+			Handler:    _TodoService_GetSharedTodo_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "todos.proto",
@@ -6,31 +6,117 @@ import (
 	"time"
 
 	"github.com/dgrijalva/jwt-go"
+	"github.com/google/uuid"
 )
 
+// Типы токенов в claim "type". Токен одного типа нельзя использовать вместо другого.
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
+	// TokenTypeOIDCFlow - состояние входа через провайдера OpenID Connect между редиректами
+	TokenTypeOIDCFlow = "oidc_flow"
+)
+
+var ErrWrongTokenType = errors.New("wrong token type")
+
 type JWTUtil struct {
 	SecretKey       string        `envconfig:"SECRET_KEY" required:"true" default:"superSecretKey"`
 	AccessTokenExp  time.Duration `envconfig:"ACCESS_TOKEN_EXP" required:"true" default:"15m"`
 	RefreshTokenExp time.Duration `envconfig:"REFRESH_TOKEN_EXP" required:"true" default:"24h"`
+	// OIDCFlowExp - сколько у пользователя времени, чтобы войти у провайдера и вернуться в gateway
+	OIDCFlowExp time.Duration `envconfig:"OIDC_FLOW_EXP" required:"true" default:"10m"`
+}
+
+// OIDCFlow - секреты одного входа через провайдера. Хранятся в подписанном токене
+// в cookie браузера, поэтому gateway не держит состояние между редиректами
+type OIDCFlow struct {
+	State        string
+	Nonce        string
+	CodeVerifier string
+}
+
+// Claims - данные из проверенного токена
+type Claims struct {
+	UserID    int
+	Type      string
+	SessionID uuid.UUID
+	// TokenID - идентификатор refresh токена, у access токена пустой
+	TokenID uuid.UUID
+	// Role и Permissions есть только в access токене. Refresh токен их не несет,
+	// чтобы при обновлении подтягивались текущие права пользователя
+	Role        string
+	Permissions []string
+	// OrganizationIDs и TeamIDs - организации и команды пользователя, тоже только в access токене.
+	// По ним сервисы ограничивают данные, доступные пользователю
+	OrganizationIDs []int
+	TeamIDs         []int
+	// Timezone - часовой пояс пользователя, в нем сервисы понимают даты из запросов
+	Timezone string
+}
+
+func (ju *JWTUtil) GenerateAccessToken(userID int, sessionID uuid.UUID, role string, permissions []string, organizationIDs, teamIDs []int, timezone string) (string, error) {
+	return ju.generateToken(jwt.MapClaims{
+		"user_id":     userID,
+		"type":        TokenTypeAccess,
+		"sid":         sessionID.String(),
+		"role":        role,
+		"permissions": permissions,
+		"org_ids":     organizationIDs,
+		"team_ids":    teamIDs,
+		"tz":          timezone,
+	}, ju.SecretKey, ju.AccessTokenExp)
+}
+
+func (ju *JWTUtil) GenerateRefreshToken(userID int, sessionID, tokenID uuid.UUID) (string, error) {
+	return ju.generateToken(jwt.MapClaims{
+		"user_id": userID,
+		"type":    TokenTypeRefresh,
+		"sid":     sessionID.String(),
+		"jti":     tokenID.String(),
+	}, ju.SecretKey, ju.RefreshTokenExp)
+}
+
+// VerifyAccessToken проверяет токен, которым подписаны запросы пользователя
+func (ju *JWTUtil) VerifyAccessToken(token string) (*Claims, error) {
+	return ju.verifyToken(token, TokenTypeAccess)
 }
 
-func (ju *JWTUtil) GenerateAccessToken(userID int) (string, error) {
-	return ju.generateToken(userID, ju.SecretKey, ju.AccessTokenExp)
+// VerifyRefreshToken проверяет токен, по которому выдается новая пара токенов
+func (ju *JWTUtil) VerifyRefreshToken(token string) (*Claims, error) {
+	return ju.verifyToken(token, TokenTypeRefresh)
 }
 
-func (ju *JWTUtil) GenerateRefreshToken(userID int) (string, error) {
-	return ju.generateToken(userID, ju.SecretKey, ju.RefreshTokenExp)
+func (ju *JWTUtil) GenerateOIDCFlowToken(flow *OIDCFlow) (string, error) {
+	return ju.generateToken(jwt.MapClaims{
+		"type":          TokenTypeOIDCFlow,
+		"state":         flow.State,
+		"nonce":         flow.Nonce,
+		"code_verifier": flow.CodeVerifier,
+	}, ju.SecretKey, ju.OIDCFlowExp)
 }
 
-func (ju *JWTUtil) VerifyToken(token string) (int, error) {
-	return ju.verifyToken(token)
+// VerifyOIDCFlowToken проверяет токен из cookie, выданный при старте входа через провайдера
+func (ju *JWTUtil) VerifyOIDCFlowToken(tokenString string) (*OIDCFlow, error) {
+	claims, err := ju.parseToken(tokenString, TokenTypeOIDCFlow)
+	if err != nil {
+		return nil, err
+	}
+
+	flow := &OIDCFlow{
+		State:        stringClaim(claims, "state"),
+		Nonce:        stringClaim(claims, "nonce"),
+		CodeVerifier: stringClaim(claims, "code_verifier"),
+	}
+	if flow.State == "" || flow.Nonce == "" || flow.CodeVerifier == "" {
+		return nil, fmt.Errorf("token has no flow")
+	}
+
+	return flow, nil
 }
 
-func (ju *JWTUtil) generateToken(userID int, secret string, duration time.Duration) (string, error) {
-	token := jwt.New(jwt.SigningMethodHS256)
-	claims := token.Claims.(jwt.MapClaims)
-	claims["user_id"] = userID
+func (ju *JWTUtil) generateToken(claims jwt.MapClaims, secret string, duration time.Duration) (string, error) {
 	claims["exp"] = time.Now().Add(duration).Unix()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
 	tokenString, err := token.SignedString([]byte(secret))
 	if err != nil {
@@ -40,7 +126,48 @@ func (ju *JWTUtil) generateToken(userID int, secret string, duration time.Durati
 	return tokenString, nil
 }
 
-func (ju *JWTUtil) verifyToken(tokenString string) (int, error) {
+func (ju *JWTUtil) verifyToken(tokenString, tokenType string) (*Claims, error) {
+	claims, err := ju.parseToken(tokenString, tokenType)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, ok := claims["user_id"].(float64)
+	if !ok {
+		return nil, fmt.Errorf("token has no user")
+	}
+
+	sessionID, err := uuid.Parse(stringClaim(claims, "sid"))
+	if err != nil {
+		return nil, fmt.Errorf("token has no session: %w", err)
+	}
+
+	result := &Claims{
+		UserID:    int(userID),
+		Type:      tokenType,
+		SessionID: sessionID,
+	}
+
+	if tokenType == TokenTypeRefresh {
+		result.TokenID, err = uuid.Parse(stringClaim(claims, "jti"))
+		if err != nil {
+			return nil, fmt.Errorf("token has no id: %w", err)
+		}
+	}
+
+	if tokenType == TokenTypeAccess {
+		result.Role = stringClaim(claims, "role")
+		result.Permissions = stringsClaim(claims, "permissions")
+		result.OrganizationIDs = intsClaim(claims, "org_ids")
+		result.TeamIDs = intsClaim(claims, "team_ids")
+		result.Timezone = stringClaim(claims, "tz")
+	}
+
+	return result, nil
+}
+
+// parseToken проверяет подпись, срок и тип токена
+func (ju *JWTUtil) parseToken(tokenString, tokenType string) (jwt.MapClaims, error) {
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
 		if token.Method != jwt.SigningMethodHS256 {
 			return nil, fmt.Errorf("Unexpected signing method")
@@ -49,20 +176,54 @@ func (ju *JWTUtil) verifyToken(tokenString string) (int, error) {
 	})
 
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
 	claims, ok := token.Claims.(jwt.MapClaims)
 	if !ok || !token.Valid {
-		return 0, fmt.Errorf("token is not valid")
+		return nil, fmt.Errorf("token is not valid")
 	}
 
-	exp := int64(claims["exp"].(float64))
-	if time.Unix(exp, 0).Before(time.Now()) {
-		return 0, errors.New("token has expired")
+	exp, ok := claims["exp"].(float64)
+	if !ok || time.Unix(int64(exp), 0).Before(time.Now()) {
+		return nil, errors.New("token has expired")
 	}
 
-	userID := int(claims["user_id"].(float64))
+	// токены, выпущенные до появления claim "type", не принимаются ни в каком качестве
+	if claimType, _ := claims["type"].(string); claimType != tokenType {
+		return nil, ErrWrongTokenType
+	}
+
+	return claims, nil
+}
+
+func stringClaim(claims jwt.MapClaims, name string) string {
+	value, _ := claims[name].(string)
+	return value
+}
+
+func stringsClaim(claims jwt.MapClaims, name string) []string {
+	values, _ := claims[name].([]interface{})
+
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if value, ok := value.(string); ok {
+			result = append(result, value)
+		}
+	}
+
+	return result
+}
+
+func intsClaim(claims jwt.MapClaims, name string) []int {
+	values, _ := claims[name].([]interface{})
+
+	result := make([]int, 0, len(values))
+	for _, value := range values {
+		if value, ok := value.(float64); ok {
+			result = append(result, int(value))
+		}
+	}
 
-	return userID, nil
+	return result
 }
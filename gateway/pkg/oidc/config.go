@@ -0,0 +1,26 @@
+package oidc
+
+import "time"
+
+// Config - настройки входа через провайдера OpenID Connect
+type Config struct {
+	// Enabled - включить вход через провайдера. Без него маршруты входа через провайдера отвечают 404
+	Enabled bool `envconfig:"OIDC_ENABLED" default:"false"`
+	// IssuerURL - адрес издателя, по нему ищется /.well-known/openid-configuration. Должен совпадать
+	// с issuer из документа обнаружения и с claim iss в ID токенах
+	IssuerURL    string `envconfig:"OIDC_ISSUER_URL" default:"http://localhost:9000"`
+	ClientID     string `envconfig:"OIDC_CLIENT_ID" default:"todo-gateway"`
+	ClientSecret string `envconfig:"OIDC_CLIENT_SECRET"`
+	// RedirectURL - адрес обратного вызова gateway, зарегистрированный у провайдера
+	RedirectURL string   `envconfig:"OIDC_REDIRECT_URL" default:"http://localhost:3009/api/v1/users/oidc/callback"`
+	Scopes      []string `envconfig:"OIDC_SCOPES" default:"openid,email,profile"`
+
+	// DiscoveryCacheTTL и JWKSCacheTTL - сколько хранятся документ обнаружения и ключи провайдера.
+	// Токен, подписанный неизвестным ключом, вызывает внеочередное обновление ключей
+	DiscoveryCacheTTL time.Duration `envconfig:"OIDC_DISCOVERY_CACHE_TTL" default:"24h"`
+	JWKSCacheTTL      time.Duration `envconfig:"OIDC_JWKS_CACHE_TTL" default:"1h"`
+	// HTTPTimeout - таймаут запросов к провайдеру
+	HTTPTimeout time.Duration `envconfig:"OIDC_HTTP_TIMEOUT" default:"10s"`
+	// ClockSkew - допустимое расхождение часов с провайдером при проверке сроков ID токена
+	ClockSkew time.Duration `envconfig:"OIDC_CLOCK_SKEW" default:"1m"`
+}
@@ -0,0 +1,109 @@
+package oidc
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// allowedAlgs - асимметричные алгоритмы подписи ID токена. HS* и none не принимаются:
+// с ними токен мог бы подписать любой, кто знает client secret, или вообще никто
+var allowedAlgs = []string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}
+
+func (p *Provider) verifyIDToken(ctx context.Context, doc *discovery, rawToken, nonce string) (*Identity, error) {
+	parser := &jwt.Parser{
+		ValidMethods: allowedAlgs,
+		// сроки проверяются ниже с учетом расхождения часов
+		SkipClaimsValidation: true,
+	}
+
+	token, err := parser.Parse(rawToken, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.getKey(ctx, doc, kid)
+	})
+	if err != nil {
+		var validationErr *jwt.ValidationError
+		if errors.As(err, &validationErr) && validationErr.Inner != nil {
+			return nil, validationErr.Inner
+		}
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return nil, errors.New("token is not valid")
+	}
+
+	if iss, _ := claims["iss"].(string); iss != doc.Issuer {
+		return nil, fmt.Errorf("unexpected issuer %q", iss)
+	}
+
+	audience := audienceClaim(claims["aud"])
+	if !contains(audience, p.cfg.ClientID) {
+		return nil, errors.New("token is not issued for this client")
+	}
+	// при нескольких получателях токен должен быть выдан именно нам
+	azp, _ := claims["azp"].(string)
+	if (len(audience) > 1 || azp != "") && azp != p.cfg.ClientID {
+		return nil, errors.New("token has unexpected authorized party")
+	}
+
+	now := time.Now()
+	exp, ok := claims["exp"].(float64)
+	if !ok || now.After(time.Unix(int64(exp), 0).Add(p.cfg.ClockSkew)) {
+		return nil, errors.New("token has expired")
+	}
+	if iat, ok := claims["iat"].(float64); ok && now.Add(p.cfg.ClockSkew).Before(time.Unix(int64(iat), 0)) {
+		return nil, errors.New("token is issued in the future")
+	}
+
+	tokenNonce, _ := claims["nonce"].(string)
+	if subtle.ConstantTimeCompare([]byte(tokenNonce), []byte(nonce)) != 1 {
+		return nil, errors.New("nonce mismatch")
+	}
+
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		return nil, errors.New("token has no subject")
+	}
+
+	identity := &Identity{
+		Issuer:        doc.Issuer,
+		Subject:       subject,
+		EmailVerified: boolClaim(claims["email_verified"]),
+	}
+	identity.Email, _ = claims["email"].(string)
+	identity.PreferredUsername, _ = claims["preferred_username"].(string)
+
+	return identity, nil
+}
+
+func audienceClaim(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		result := make([]string, 0, len(v))
+		for _, item := range v {
+			if item, ok := item.(string); ok {
+				result = append(result, item)
+			}
+		}
+		return result
+	default:
+		return nil
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, item := range values {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
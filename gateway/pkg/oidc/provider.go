@@ -0,0 +1,377 @@
+package oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// jwksRefreshInterval - как часто можно обновлять ключи вне очереди, если пришел токен
+// с неизвестным kid. Без ограничения поддельные токены превращались бы в запросы к провайдеру
+const jwksRefreshInterval = 30 * time.Second
+
+var (
+	ErrProviderUnavailable = errors.New("identity provider unavailable")
+	ErrUnknownSigningKey   = errors.New("unknown signing key")
+)
+
+// Identity - пользователь, которого подтвердил провайдер
+type Identity struct {
+	Issuer            string
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	PreferredUsername string
+}
+
+// discovery - нужная часть документа /.well-known/openid-configuration
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type jsonWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// Provider - клиент провайдера OpenID Connect. Документ обнаружения и ключи подписи
+// кешируются, поэтому один Provider используется на все приложение
+type Provider struct {
+	cfg    Config
+	client *http.Client
+
+	mu                sync.Mutex
+	discovery         *discovery
+	discoveryLoadedAt time.Time
+	keys              map[string]interface{}
+	keysLoadedAt      time.Time
+}
+
+func NewProvider(cfg Config) *Provider {
+	return &Provider{
+		cfg:    cfg,
+		client: &http.Client{Timeout: cfg.HTTPTimeout},
+	}
+}
+
+// AuthCodeURL возвращает адрес страницы входа провайдера для authorization code flow с PKCE
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error) {
+	doc, err := p.getDiscovery(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	authURL, err := url.Parse(doc.AuthorizationEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("%w: bad authorization endpoint: %v", ErrProviderUnavailable, err)
+	}
+
+	query := authURL.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", p.cfg.ClientID)
+	query.Set("redirect_uri", p.cfg.RedirectURL)
+	query.Set("scope", strings.Join(p.cfg.Scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", codeChallenge)
+	query.Set("code_challenge_method", "S256")
+	authURL.RawQuery = query.Encode()
+
+	return authURL.String(), nil
+}
+
+// Exchange обменивает код авторизации на токены и возвращает пользователя из проверенного ID токена
+func (p *Provider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*Identity, error) {
+	doc, err := p.getDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.cfg.RedirectURL)
+	form.Set("code_verifier", codeVerifier)
+	if p.cfg.ClientSecret == "" {
+		// публичный клиент: секрета нет, код защищен только PKCE
+		form.Set("client_id", p.cfg.ClientID)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, doc.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("build token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.cfg.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.cfg.ClientID), url.QueryEscape(p.cfg.ClientSecret))
+	}
+
+	var tokens struct {
+		AccessToken string `json:"access_token"`
+		IDToken     string `json:"id_token"`
+	}
+	if err := p.doJSON(req, &tokens); err != nil {
+		return nil, fmt.Errorf("token request: %w", err)
+	}
+
+	if tokens.IDToken == "" {
+		return nil, errors.New("token response has no id_token")
+	}
+
+	identity, err := p.verifyIDToken(ctx, doc, tokens.IDToken, nonce)
+	if err != nil {
+		return nil, fmt.Errorf("verify id_token: %w", err)
+	}
+
+	// часть провайдеров кладет почту только в userinfo
+	if identity.Email == "" && doc.UserinfoEndpoint != "" && tokens.AccessToken != "" {
+		if err := p.fillFromUserinfo(ctx, doc, tokens.AccessToken, identity); err != nil {
+			return nil, fmt.Errorf("userinfo: %w", err)
+		}
+	}
+
+	return identity, nil
+}
+
+func (p *Provider) fillFromUserinfo(ctx context.Context, doc *discovery, accessToken string, identity *Identity) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, doc.UserinfoEndpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Accept", "application/json")
+
+	var info map[string]interface{}
+	if err := p.doJSON(req, &info); err != nil {
+		return err
+	}
+
+	// ответ userinfo не подписан, верим ему только про того же пользователя, что и в ID токене
+	if sub, _ := info["sub"].(string); sub != identity.Subject {
+		return errors.New("userinfo subject mismatch")
+	}
+
+	identity.Email, _ = info["email"].(string)
+	identity.EmailVerified = boolClaim(info["email_verified"])
+	if identity.PreferredUsername == "" {
+		identity.PreferredUsername, _ = info["preferred_username"].(string)
+	}
+
+	return nil
+}
+
+func (p *Provider) getDiscovery(ctx context.Context) (*discovery, error) {
+	p.mu.Lock()
+	if p.discovery != nil && time.Since(p.discoveryLoadedAt) < p.cfg.DiscoveryCacheTTL {
+		doc := p.discovery
+		p.mu.Unlock()
+		return doc, nil
+	}
+	p.mu.Unlock()
+
+	wellKnown := strings.TrimSuffix(p.cfg.IssuerURL, "/") + "/.well-known/openid-configuration"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, wellKnown, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrProviderUnavailable, err)
+	}
+
+	doc := &discovery{}
+	if err := p.doJSON(req, doc); err != nil {
+		return nil, fmt.Errorf("%w: discovery: %v", ErrProviderUnavailable, err)
+	}
+
+	// подмена issuer в документе позволила бы принимать токены чужого издателя
+	if doc.Issuer != p.cfg.IssuerURL {
+		return nil, fmt.Errorf("%w: discovery issuer %q does not match %q", ErrProviderUnavailable, doc.Issuer, p.cfg.IssuerURL)
+	}
+	if doc.AuthorizationEndpoint == "" || doc.TokenEndpoint == "" || doc.JWKSURI == "" {
+		return nil, fmt.Errorf("%w: discovery document is incomplete", ErrProviderUnavailable)
+	}
+
+	p.mu.Lock()
+	p.discovery = doc
+	p.discoveryLoadedAt = time.Now()
+	p.mu.Unlock()
+
+	return doc, nil
+}
+
+// getKey возвращает ключ подписи по kid. Если ключа нет в кеше, ключи перечитываются,
+// но не чаще jwksRefreshInterval: так подхватывается ротация ключей у провайдера
+func (p *Provider) getKey(ctx context.Context, doc *discovery, kid string) (interface{}, error) {
+	p.mu.Lock()
+	key, ok := p.keys[kid]
+	fresh := p.keys != nil && time.Since(p.keysLoadedAt) < p.cfg.JWKSCacheTTL
+	canRefresh := time.Since(p.keysLoadedAt) >= jwksRefreshInterval
+	p.mu.Unlock()
+
+	if ok && fresh {
+		return key, nil
+	}
+	if !canRefresh {
+		if ok {
+			return key, nil
+		}
+		return nil, ErrUnknownSigningKey
+	}
+
+	keys, err := p.fetchKeys(ctx, doc)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	p.keys = keys
+	p.keysLoadedAt = time.Now()
+	p.mu.Unlock()
+
+	key, ok = keys[kid]
+	if !ok {
+		return nil, ErrUnknownSigningKey
+	}
+
+	return key, nil
+}
+
+func (p *Provider) fetchKeys(ctx context.Context, doc *discovery) (map[string]interface{}, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, doc.JWKSURI, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrProviderUnavailable, err)
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := p.doJSON(req, &set); err != nil {
+		return nil, fmt.Errorf("%w: jwks: %v", ErrProviderUnavailable, err)
+	}
+
+	keys := make(map[string]interface{}, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		key, err := jwk.publicKey()
+		if err != nil {
+			// неподдерживаемые ключи пропускаются, токены ими все равно не примутся
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+
+	return keys, nil
+}
+
+func (p *Provider) doJSON(req *http.Request, out interface{}) error {
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var oauthErr struct {
+			Error       string `json:"error"`
+			Description string `json:"error_description"`
+		}
+		if json.Unmarshal(body, &oauthErr) == nil && oauthErr.Error != "" {
+			return fmt.Errorf("status %d: %s %s", resp.StatusCode, oauthErr.Error, oauthErr.Description)
+		}
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+
+	return json.Unmarshal(body, out)
+}
+
+func (k jsonWebKey) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() {
+			return nil, errors.New("rsa exponent is too large")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("ec point is not on curve")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(raw), nil
+}
+
+// boolClaim читает email_verified: часть провайдеров отдает его строкой
+func boolClaim(value interface{}) bool {
+	switch v := value.(type) {
+	case bool:
+		return v
+	case string:
+		return v == "true"
+	default:
+		return false
+	}
+}
@@ -11,12 +11,19 @@ type Config struct {
 	Logging       logging.LoggerConfig      `envconfig:"LOG"`
 	SmtpConfig    smtp_client.SmtpConfig    `envconfig:"SMTP"`
 	RabbitConfig  rabbitConfig.RabbitConfig `envconfig:"RABBITMQ"`
+	UsersClient   UsersClient               `envconfig:"USERS"`
 	UsersExchange string                    `envconfig:"RABBITMQ_USERS_EXCHANGE" default:"users.exchange"`
 	UsersQueue    string                    `envconfig:"RABBITMQ_USERS_QUEUE" default:"users.queue"`
 	TodoExchange  string                    `envconfig:"RABBITMQ_TODO_EXCHANGE" default:"todo.exchange"`
 	TodoQueue     string                    `envconfig:"RABBITMQ_TODO_QUEUE" default:"todo.queue"`
 }
 
+// UsersClient - адрес сервиса users, у которого notifications узнает настройки уведомлений
+type UsersClient struct {
+	AppHost     string `envconfig:"USERS_HOST" required:"true" default:"0.0.0.0"`
+	AppGrpcPort string `envconfig:"USERS_GRPC_PORT" required:"true" default:"50000"`
+}
+
 func NewFromEnv() *Config {
 	c := Config{}
 	envconfig.MustProcess("", &c)
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"notifications/internal/models"
 )
 
@@ -9,5 +10,5 @@ type UserService interface {
 }
 
 type TodoService interface {
-	SendTodoMessage(item *models.TodoMailItem) error
+	SendTodoMessage(ctx context.Context, item *models.TodoMailItem) error
 }
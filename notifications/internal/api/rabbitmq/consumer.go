@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/rs/zerolog"
 	"notifications/config"
+	usersClient "notifications/internal/clients/users"
 	"notifications/internal/service"
 	rabbitConsumer "notifications/pkg/rabbitmq/consumer"
 	"notifications/pkg/smtp_client"
@@ -34,7 +35,12 @@ func ConsumeRabbitMessages(
 		return fmt.Errorf("[ConsumeRabbitMessages] create users rabbitmq consumer: %w", err)
 	}
 
-	todoService := service.NewTodoService(logger, smtpClient)
+	usersServiceClient, err := usersClient.NewUsersClient(cfg, logger)
+	if err != nil {
+		return fmt.Errorf("[ConsumeRabbitMessages] create users client: %w", err)
+	}
+
+	todoService := service.NewTodoService(logger, smtpClient, usersServiceClient)
 	todoMessagesHandler := NewTodoMessagesHandler(logger, todoService)
 	rabbitMQ.SetHandler(
 		cfg.TodoQueue,
@@ -1,6 +1,7 @@
 package rabbitmq
 
 import (
+	"context"
 	"encoding/json"
 	amqp "github.com/rabbitmq/amqp091-go"
 	"github.com/rs/zerolog"
@@ -37,7 +38,7 @@ func (m *TodoMessagesHandler) Handle(d amqp.Delivery) {
 		return
 	}
 
-	err = m.todoService.SendTodoMessage(&item)
+	err = m.todoService.SendTodoMessage(context.Background(), &item)
 	if err != nil {
 		m.logger.Error().Msgf("[TodoMessagesHandler] sending todo mail: %s", err)
 		return
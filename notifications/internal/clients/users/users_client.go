@@ -0,0 +1,57 @@
+package users
+
+import (
+	"context"
+	"fmt"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"notifications/config"
+	"notifications/pkg/grpc_stubs/users"
+)
+
+type UsersClient struct {
+	client users.UserServiceClient
+}
+
+func NewUsersClient(cfg *config.Config, logger *zerolog.Logger) (*UsersClient, error) {
+	appAddr := fmt.Sprintf("%s:%s", cfg.UsersClient.AppHost, cfg.UsersClient.AppGrpcPort)
+
+	logger.Info().Msgf("[NewUsersClient] connecting via GRPC to users at %s", appAddr)
+
+	conn, err := grpc.Dial(
+		appAddr,
+		grpc.WithInsecure(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("[NewUsersClient] connect to users GRPC service: %w", err)
+	}
+
+	c := users.NewUserServiceClient(conn)
+	return &UsersClient{
+		client: c,
+	}, nil
+}
+
+// FilterNotificationRecipients оставляет пользователей, которые хотят получать уведомление о событии по каналу
+func (c *UsersClient) FilterNotificationRecipients(ctx context.Context, event, channel string, userIDs []int) ([]int, error) {
+	request := &users.NotificationRecipientsRequest{
+		Event:   event,
+		Channel: channel,
+		UserIds: make([]int32, len(userIDs)),
+	}
+	for i, userID := range userIDs {
+		request.UserIds[i] = int32(userID)
+	}
+
+	response, err := c.client.FilterNotificationRecipients(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	recipients := make([]int, len(response.UserIds))
+	for i, userID := range response.UserIds {
+		recipients[i] = int(userID)
+	}
+
+	return recipients, nil
+}
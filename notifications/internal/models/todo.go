@@ -1,15 +1,17 @@
 package models
 
 const (
-	TodoEventTypeCreateTodo = "create_todo"
-	TodoEventTypeUpdateTodo = "update_todo"
-	TodoEventTypeDeleteTodo = "delete_todo"
+	TodoEventTypeCreateTodo  = "create_todo"
+	TodoEventTypeUpdateTodo  = "update_todo"
+	TodoEventTypeDeleteTodo  = "delete_todo"
+	TodoEventTypeMentionTodo = "mention_todo"
 )
 
 const (
-	EmailSubjectCreateTodo = "A new TODO has been created"
-	EmailSubjectUpdateTodo = "Your TODO has been changed"
-	EmailSubjectDeleteTodo = "Your TODO has been deleted"
+	EmailSubjectCreateTodo  = "A new TODO has been created"
+	EmailSubjectUpdateTodo  = "Your TODO has been changed"
+	EmailSubjectDeleteTodo  = "Your TODO has been deleted"
+	EmailSubjectMentionTodo = "You have been mentioned in a TODO"
 )
 
 const (
@@ -21,8 +23,8 @@ const (
 			<h2>A new TODO has been created!</h2> 
 
 			<div> 
-			  <h3>Description:</h3> 
-			  <p>%s</p> 
+			  <h3>%s</h3> 
+			  %s 
 			</div> 
     
 			<div> 
@@ -41,8 +43,8 @@ const (
 			<h2>Your TODO has been changed!</h2> 
 
 			<div> 
-			  <h3>Description:</h3> 
-			  <p>%s</p> 
+			  <h3>%s</h3> 
+			  %s 
 			</div> 
     
 			<div> 
@@ -62,18 +64,67 @@ const (
 			<h2>Your TODO has been deleted!</h2> 
 
 			<div> 
-			  <h3>Description:</h3> 
-			  <p>%s</p> 
+			  <h3>%s</h3> 
+			  %s 
 			</div>
 
 		</body>
 		</html>
 	`
+
+	EmailBodyMentionTodo = `
+		<!DOCTYPE html>
+		<html>
+		<body>
+		
+			<h2>You have been mentioned in a TODO!</h2> 
+
+			<div> 
+			  <h3>%s</h3> 
+			  %s 
+			</div> 
+    
+			<div> 
+			  <h3>Assignee:</h3> 
+			  <p>%s</p> 
+			</div> 
+
+		</body>
+		</html>
+	`
+
+	EmailBodyMentionTodoNotice = `
+		<!DOCTYPE html>
+		<html>
+		<body>
+		
+			<h2>You have been mentioned in a TODO!</h2> 
+
+			<p>You do not have access to this TODO. Ask its creator or assignee to share it with you.</p> 
+
+		</body>
+		</html>
+	`
 )
 
+// NotificationChannelEmail - канал уведомлений, по которому уходят письма
+const NotificationChannelEmail = "email"
+
+// TodoMailRecipient - получатель письма. По UserID проверяются его настройки уведомлений
+type TodoMailRecipient struct {
+	UserID int    `json:"user_id"`
+	Email  string `json:"email"`
+}
+
 type TodoMailItem struct {
-	TodoEventType string   `json:"todo_event_type"`
-	Receivers     []string `json:"receivers"`
-	AssigneeName  string   `json:"assignee_name"`
-	Description   string   `json:"description"`
+	TodoEventType string              `json:"todo_event_type"`
+	Recipients    []TodoMailRecipient `json:"recipients"`
+	// Receivers - адреса получателей в сообщениях, отправленных до появления Recipients
+	Receivers       []string `json:"receivers,omitempty"`
+	AssigneeName    string   `json:"assignee_name"`
+	Title           string   `json:"title"`
+	Description     string   `json:"description"`
+	DescriptionHTML string   `json:"description_html"`
+	// ContentHidden - получатели не могут читать задачу, письмо только сообщает об упоминании
+	ContentHidden bool `json:"content_hidden,omitempty"`
 }
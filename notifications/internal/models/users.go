@@ -2,10 +2,24 @@ package models
 
 const (
 	UserEventTypeEmailVerification = "user_verify_email"
+	UserEventTypePasswordReset     = "user_reset_password"
+	UserEventTypeAccountLocked     = "user_account_locked"
+	UserEventTypeOrgInvitation     = "user_org_invitation"
+	UserEventTypeEmailChange       = "user_change_email"
+	UserEventTypeEmailChangeNotice = "user_email_change_notice"
+	UserEventTypeDataExportReady   = "user_data_export_ready"
+	UserEventTypeErasure           = "user_confirm_erasure"
 )
 
 const (
 	EmailSubjectEmailVerification = "Verify email"
+	EmailSubjectPasswordReset     = "Reset password"
+	EmailSubjectAccountLocked     = "Your account has been temporarily locked"
+	EmailSubjectOrgInvitation     = "Invitation to join an organization"
+	EmailSubjectEmailChange       = "Confirm your new email"
+	EmailSubjectEmailChangeNotice = "Your email is being changed"
+	EmailSubjectDataExportReady   = "Your data export is ready"
+	EmailSubjectErasure           = "Confirm deletion of your account"
 )
 
 const (
@@ -39,10 +53,206 @@ const (
 		</body>
 		</html>
 	`
+
+	EmailBodyPasswordReset = `
+		<!DOCTYPE html>
+		<html>
+		<head>
+			<style>
+				.button {
+					background-color: #007bff; /* Blue background */
+					border: none;
+					color: white;
+					padding: 15px 32px;
+					text-align: center;
+					text-decoration: none;
+					display: inline-block;
+					font-size: 16px;
+					margin: 4px 2px;
+					cursor: pointer;
+					border-radius: 5px;
+				}
+			</style>
+		</head>
+		<body>
+		
+			<p>We received a request to reset the password for your account.</p>
+			<p>Click the button below to choose a new password. The link can be used only once and expires soon.</p>
+		
+			<a href="%s" class="button">Reset password</a>
+
+			<p>If you did not request a password reset, you can ignore this email - your password will not change.</p>
+
+		</body>
+		</html>
+	`
+
+	EmailBodyAccountLocked = `
+		<!DOCTYPE html>
+		<html>
+		<body>
+		
+			<p>We noticed too many failed sign-in attempts to your account, so we have temporarily locked it.</p>
+			<p>The lock will be lifted automatically after a while, or you can ask an administrator to unlock the account.</p>
+
+			<p>If these attempts were not made by you, someone may be trying to guess your password - we recommend resetting it.</p>
+
+		</body>
+		</html>
+	`
+
+	EmailBodyOrgInvitation = `
+		<!DOCTYPE html>
+		<html>
+		<head>
+			<style>
+				.button {
+					background-color: #007bff; /* Blue background */
+					border: none;
+					color: white;
+					padding: 15px 32px;
+					text-align: center;
+					text-decoration: none;
+					display: inline-block;
+					font-size: 16px;
+					margin: 4px 2px;
+					cursor: pointer;
+					border-radius: 5px;
+				}
+			</style>
+		</head>
+		<body>
+		
+			<p>You have been invited to join the organization <b>%s</b>.</p>
+			<p>Sign in with the account registered to this email address and click the button below to accept the invitation.</p>
+		
+			<a href="%s" class="button">Accept invitation</a>
+
+			<p>If you do not want to join, you can ignore this email - the invitation will expire.</p>
+
+		</body>
+		</html>
+	`
+
+	EmailBodyEmailChange = `
+		<!DOCTYPE html>
+		<html>
+		<head>
+			<style>
+				.button {
+					background-color: #007bff; /* Blue background */
+					border: none;
+					color: white;
+					padding: 15px 32px;
+					text-align: center;
+					text-decoration: none;
+					display: inline-block;
+					font-size: 16px;
+					margin: 4px 2px;
+					cursor: pointer;
+					border-radius: 5px;
+				}
+			</style>
+		</head>
+		<body>
+		
+			<p>You have asked to use this address for your account.</p>
+			<p>Please confirm it by clicking the button below - until then the account keeps the previous email.</p>
+		
+			<a href="%s" class="button">Confirm email</a>
+
+			<p>If you did not ask for this, you can ignore this email.</p>
+
+		</body>
+		</html>
+	`
+
+	EmailBodyEmailChangeNotice = `
+		<!DOCTYPE html>
+		<html>
+		<body>
+		
+			<p>Someone has asked to change the email of your account to <b>%s</b>.</p>
+			<p>The email will be changed only after the new address is confirmed.</p>
+
+			<p>If this was not you, someone may have access to your account - we recommend changing your password and signing out of all sessions.</p>
+
+		</body>
+		</html>
+	`
+	EmailBodyDataExportReady = `
+		<!DOCTYPE html>
+		<html>
+		<head>
+			<style>
+				.button {
+					background-color: #007bff; /* Blue background */
+					border: none;
+					color: white;
+					padding: 15px 32px;
+					text-align: center;
+					text-decoration: none;
+					display: inline-block;
+					font-size: 16px;
+					margin: 4px 2px;
+					cursor: pointer;
+					border-radius: 5px;
+				}
+			</style>
+		</head>
+		<body>
+		
+			<p>The export of your data you requested is ready.</p>
+			<p>Click the button below to download the archive with your profile and todos. The link expires in a few days.</p>
+		
+			<a href="%s" class="button">Download</a>
+
+			<p>If you did not request an export, someone may have access to your account - we recommend changing your password.</p>
+
+		</body>
+		</html>
+	`
+
+	EmailBodyErasure = `
+		<!DOCTYPE html>
+		<html>
+		<head>
+			<style>
+				.button {
+					background-color: #007bff; /* Blue background */
+					border: none;
+					color: white;
+					padding: 15px 32px;
+					text-align: center;
+					text-decoration: none;
+					display: inline-block;
+					font-size: 16px;
+					margin: 4px 2px;
+					cursor: pointer;
+					border-radius: 5px;
+				}
+			</style>
+		</head>
+		<body>
+		
+			<p>We received a request to delete your account.</p>
+			<p>Click the button below to confirm. Your account will be deleted and your todos will no longer be linked to you. This cannot be undone.</p>
+		
+			<a href="%s" class="button">Delete account</a>
+
+			<p>If you did not request this, you can ignore this email - your account will not be deleted. We recommend changing your password.</p>
+
+		</body>
+		</html>
+	`
 )
 
 type UserMailItem struct {
 	UserEventType string   `json:"user_event_type"`
 	Receivers     []string `json:"receivers"`
 	Link          string   `json:"link"`
+	// Organization - название организации, только в приглашениях
+	Organization string `json:"organization,omitempty"`
+	// NewEmail - адрес, на который пользователь меняет email, только в уведомлении на старый адрес
+	NewEmail string `json:"new_email,omitempty"`
 }
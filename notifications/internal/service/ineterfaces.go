@@ -1,5 +1,12 @@
 package service
 
+import "context"
+
 type SmtpClient interface {
 	Send(receivers []string, subject, body string) error
 }
+
+// UsersClient - сервис users, в нем хранятся настройки уведомлений пользователей
+type UsersClient interface {
+	FilterNotificationRecipients(ctx context.Context, event, channel string, userIDs []int) ([]int, error)
+}
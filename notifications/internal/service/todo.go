@@ -1,53 +1,118 @@
 package service
 
 import (
+	"context"
 	"fmt"
 	"github.com/rs/zerolog"
+	"html"
 	"notifications/internal/app_errors"
 	"notifications/internal/models"
 )
 
 type TodoService struct {
-	logger     *zerolog.Logger
-	smtpClient SmtpClient
+	logger      *zerolog.Logger
+	smtpClient  SmtpClient
+	usersClient UsersClient
 }
 
 func NewTodoService(
 	logger *zerolog.Logger,
 	smtpClient SmtpClient,
+	usersClient UsersClient,
 ) *TodoService {
 	return &TodoService{
-		logger:     logger,
-		smtpClient: smtpClient,
+		logger:      logger,
+		smtpClient:  smtpClient,
+		usersClient: usersClient,
 	}
 }
 
-func (s *TodoService) SendTodoMessage(item *models.TodoMailItem) error {
+// SendTodoMessage отправляет письмо о событии задачи тем получателям, которые не отключили
+// уведомления о нем в настройках
+func (s *TodoService) SendTodoMessage(ctx context.Context, item *models.TodoMailItem) error {
 
 	var messageBody, subject string
 
+	// HTML описания уже очищен сервисом todo, остальные поля экранируем сами
+	title := html.EscapeString(item.Title)
+	assigneeName := html.EscapeString(item.AssigneeName)
+	description := item.DescriptionHTML
+	if description == "" {
+		// сообщения, отправленные до появления description_html, содержат только сырой текст
+		description = fmt.Sprintf("<p>%s</p>", html.EscapeString(item.Description))
+	}
+
 	switch item.TodoEventType {
 	case models.TodoEventTypeCreateTodo:
-		messageBody = fmt.Sprintf(models.EmailBodyCreateTodo, item.Description, item.AssigneeName)
+		messageBody = fmt.Sprintf(models.EmailBodyCreateTodo, title, description, assigneeName)
 		subject = models.EmailSubjectCreateTodo
 
 	case models.TodoEventTypeUpdateTodo:
-		messageBody = fmt.Sprintf(models.EmailBodyUpdateTodo, item.Description, item.AssigneeName)
+		messageBody = fmt.Sprintf(models.EmailBodyUpdateTodo, title, description, assigneeName)
 		subject = models.EmailSubjectUpdateTodo
 
 	case models.TodoEventTypeDeleteTodo:
-		messageBody = fmt.Sprintf(models.EmailBodyDeleteTodo, item.Description)
+		messageBody = fmt.Sprintf(models.EmailBodyDeleteTodo, title, description)
 		subject = models.EmailSubjectDeleteTodo
 
+	case models.TodoEventTypeMentionTodo:
+		messageBody = fmt.Sprintf(models.EmailBodyMentionTodo, title, description, assigneeName)
+		if item.ContentHidden {
+			messageBody = models.EmailBodyMentionTodoNotice
+		}
+		subject = models.EmailSubjectMentionTodo
+
 	default:
 		return app_errors.ErrIncorrectTodoEventType
 	}
 
-	s.logger.Info().Msgf("[SendUserMessage] sending %s message", subject)
-	err := s.smtpClient.Send(item.Receivers, subject, messageBody)
+	receivers, err := s.emailReceivers(ctx, item)
+	if err != nil {
+		return fmt.Errorf("[SendTodoMessage]: filter receivers: %w", err)
+	}
+
+	if len(receivers) == 0 {
+		s.logger.Info().Msgf("[SendTodoMessage] %s message is disabled by all receivers", item.TodoEventType)
+		return nil
+	}
+
+	s.logger.Info().Msgf("[SendTodoMessage] sending %s message", subject)
+	err = s.smtpClient.Send(receivers, subject, messageBody)
 	if err != nil {
 		return fmt.Errorf("[SendTodoMessage]: send mssg: %w", err)
 	}
 
 	return nil
 }
+
+// emailReceivers возвращает адреса получателей, которые хотят получать письма о событии.
+// В старых сообщениях нет ID получателей, поэтому настройки для них не проверяются
+func (s *TodoService) emailReceivers(ctx context.Context, item *models.TodoMailItem) ([]string, error) {
+	if len(item.Recipients) == 0 {
+		return item.Receivers, nil
+	}
+
+	userIDs := make([]int, len(item.Recipients))
+	for i, recipient := range item.Recipients {
+		userIDs[i] = recipient.UserID
+	}
+
+	allowedIDs, err := s.usersClient.FilterNotificationRecipients(ctx, item.TodoEventType, models.NotificationChannelEmail, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed := make(map[int]bool, len(allowedIDs))
+	for _, userID := range allowedIDs {
+		allowed[userID] = true
+	}
+
+	receivers := make([]string, 0, len(item.Recipients))
+	for _, recipient := range item.Recipients {
+		if allowed[recipient.UserID] {
+			receivers = append(receivers, recipient.Email)
+		}
+	}
+
+	return receivers, nil
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"fmt"
 	"github.com/rs/zerolog"
+	"html"
 	"notifications/internal/app_errors"
 	"notifications/internal/models"
 )
@@ -31,6 +32,36 @@ func (s *UsersService) SendUserMessage(item *models.UserMailItem) error {
 		messageBody = fmt.Sprintf(models.EmailBodyEmailVerification, item.Link)
 		subject = models.EmailSubjectEmailVerification
 
+	case models.UserEventTypePasswordReset:
+		messageBody = fmt.Sprintf(models.EmailBodyPasswordReset, item.Link)
+		subject = models.EmailSubjectPasswordReset
+
+	case models.UserEventTypeAccountLocked:
+		messageBody = models.EmailBodyAccountLocked
+		subject = models.EmailSubjectAccountLocked
+
+	case models.UserEventTypeOrgInvitation:
+		// название организации задают пользователи, поэтому оно попадает только в тело письма и экранируется
+		messageBody = fmt.Sprintf(models.EmailBodyOrgInvitation, html.EscapeString(item.Organization), item.Link)
+		subject = models.EmailSubjectOrgInvitation
+
+	case models.UserEventTypeEmailChange:
+		messageBody = fmt.Sprintf(models.EmailBodyEmailChange, item.Link)
+		subject = models.EmailSubjectEmailChange
+
+	case models.UserEventTypeEmailChangeNotice:
+		// адрес вводит пользователь, поэтому он экранируется
+		messageBody = fmt.Sprintf(models.EmailBodyEmailChangeNotice, html.EscapeString(item.NewEmail))
+		subject = models.EmailSubjectEmailChangeNotice
+
+	case models.UserEventTypeDataExportReady:
+		messageBody = fmt.Sprintf(models.EmailBodyDataExportReady, item.Link)
+		subject = models.EmailSubjectDataExportReady
+
+	case models.UserEventTypeErasure:
+		messageBody = fmt.Sprintf(models.EmailBodyErasure, item.Link)
+		subject = models.EmailSubjectErasure
+
 	default:
 		return app_errors.ErrIncorrectUserEventType
 	}
@@ -0,0 +1,2162 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package users
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// UserServiceClient is the client API for UserService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type UserServiceClient interface {
+	// Метод RPC для регистрации пользователя. Принимает CreateUserDTO и возвращает UserID
+	RegisterUser(ctx context.Context, in *CreateUserDTO, opts ...grpc.CallOption) (*UserID, error)
+	// Метод RPC для обновления данных пользователя. Принимает и возвращает UserDTO
+	UpdateUser(ctx context.Context, in *UserDTO, opts ...grpc.CallOption) (*UserDTO, error)
+	// Метод RPC для обновления пароля пользователя. Принимает UpdateUserPasswordDTO и возвращает пустой ответ
+	UpdatePassword(ctx context.Context, in *UpdateUserPasswordDTO, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Метод RPC для удаления пользователя. Принимает UserID и возвращает пустой ответ
+	DeleteUser(ctx context.Context, in *UserID, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Метод RPC для получения состояния последнего удаления пользователя. Принимает UserID и возвращает UserDeletion
+	GetUserDeletion(ctx context.Context, in *UserID, opts ...grpc.CallOption) (*UserDeletion, error)
+	// Метод RPC для получения данных пользователя по ID. Принимает UserID и возвращает UserDTO
+	GetUserByID(ctx context.Context, in *UserID, opts ...grpc.CallOption) (*UserDTO, error)
+	// Метод RPC для получения данных нескольких пользователей одним запросом. Принимает UserIDs и возвращает Users,
+	// пользователи, которых нет или которые удаляются, в ответ не попадают
+	GetUsersByIDs(ctx context.Context, in *UserIDs, opts ...grpc.CallOption) (*Users, error)
+	// Метод RPC для получения данных нескольких пользователей по именам одним запросом. Принимает Usernames и возвращает
+	// Users вместе с разрешениями ролей, имена сравниваются без учета регистра. Пользователи, которых нет или которые
+	// удаляются, в ответ не попадают
+	GetUsersByUsernames(ctx context.Context, in *Usernames, opts ...grpc.CallOption) (*Users, error)
+	// Метод RPC для получения данных пользователя по имени или email. Принимает UserDTO и возвращает UserDTO
+	GetUserByUsernameOrEmail(ctx context.Context, in *UserDTO, opts ...grpc.CallOption) (*UserDTO, error)
+	// Метод RPC для постраничного поиска пользователей по началу username или email. Принимает ListUsersRequest и возвращает ListUsersResponse
+	ListUsers(ctx context.Context, in *ListUsersRequest, opts ...grpc.CallOption) (*ListUsersResponse, error)
+	// Метод RPC для входа пользователя. Принимает UserLoginDTO и возвращает UserDTO
+	Login(ctx context.Context, in *UserLoginDTO, opts ...grpc.CallOption) (*UserDTO, error)
+	// Метод RPC для подтверждения email по токену из письма. Принимает VerifyEmailRequest и возвращает пустой ответ
+	VerifyEmail(ctx context.Context, in *VerifyEmailRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Метод RPC для повторной отправки письма с подтверждением email. Принимает ResendVerificationEmailRequest и возвращает пустой ответ
+	ResendVerificationEmail(ctx context.Context, in *ResendVerificationEmailRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Метод RPC для отправки письма со ссылкой на сброс пароля. Принимает RequestPasswordResetRequest и возвращает пустой ответ
+	RequestPasswordReset(ctx context.Context, in *RequestPasswordResetRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Метод RPC для установки нового пароля по токену из письма. Принимает ResetPasswordRequest и возвращает пустой ответ
+	ResetPassword(ctx context.Context, in *ResetPasswordRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Метод RPC для смены email, на новый адрес уходит ссылка для подтверждения, на старый - уведомление. Принимает RequestEmailChangeRequest и возвращает пустой ответ
+	RequestEmailChange(ctx context.Context, in *RequestEmailChangeRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Метод RPC для подтверждения нового email по токену из письма. Принимает ConfirmEmailChangeRequest и возвращает пустой ответ
+	ConfirmEmailChange(ctx context.Context, in *ConfirmEmailChangeRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Метод RPC для создания сессии после входа. Принимает и возвращает SessionDTO
+	CreateSession(ctx context.Context, in *SessionDTO, opts ...grpc.CallOption) (*SessionDTO, error)
+	// Метод RPC для замены refresh токена сессии. Повторное предъявление старого токена завершает сессию. Принимает RotateSessionRequest и возвращает SessionDTO
+	RotateSession(ctx context.Context, in *RotateSessionRequest, opts ...grpc.CallOption) (*SessionDTO, error)
+	// Метод RPC для завершения сессии. Принимает SessionRequest и возвращает пустой ответ
+	RevokeSession(ctx context.Context, in *SessionRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Метод RPC для завершения всех сессий пользователя. Принимает UserID и возвращает пустой ответ
+	RevokeUserSessions(ctx context.Context, in *UserID, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Метод RPC для выдачи секрета двухфакторной аутентификации. Принимает UserID и возвращает TwoFactorEnrollment
+	EnrollTwoFactor(ctx context.Context, in *UserID, opts ...grpc.CallOption) (*TwoFactorEnrollment, error)
+	// Метод RPC для подтверждения подключения второго фактора кодом из приложения. Принимает TwoFactorCodeRequest и возвращает RecoveryCodes
+	ConfirmTwoFactor(ctx context.Context, in *TwoFactorCodeRequest, opts ...grpc.CallOption) (*RecoveryCodes, error)
+	// Метод RPC для отключения второго фактора. Принимает TwoFactorCodeRequest и возвращает пустой ответ
+	DisableTwoFactor(ctx context.Context, in *TwoFactorCodeRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Метод RPC для выпуска новых кодов восстановления взамен старых. Принимает TwoFactorCodeRequest и возвращает RecoveryCodes
+	RegenerateRecoveryCodes(ctx context.Context, in *TwoFactorCodeRequest, opts ...grpc.CallOption) (*RecoveryCodes, error)
+	// Метод RPC для второго шага входа. Принимает TwoFactorLoginRequest и возвращает UserDTO
+	LoginTwoFactor(ctx context.Context, in *TwoFactorLoginRequest, opts ...grpc.CallOption) (*UserDTO, error)
+	// Метод RPC для снятия блокировки входа после серии неудачных попыток. Принимает UserID и возвращает пустой ответ
+	UnlockUser(ctx context.Context, in *UserID, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Метод RPC для назначения пользователю роли. Принимает SetUserRoleRequest и возвращает пустой ответ
+	SetUserRole(ctx context.Context, in *SetUserRoleRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Метод RPC для входа через внешнего провайдера OpenID Connect. При первом входе пользователь создается или привязывается к аккаунту с тем же подтвержденным email. Принимает ExternalLoginDTO и возвращает UserDTO
+	LoginExternal(ctx context.Context, in *ExternalLoginDTO, opts ...grpc.CallOption) (*UserDTO, error)
+	// Метод RPC для выпуска персонального токена доступа. Принимает CreateApiTokenRequest и возвращает ApiTokenDTO с самим токеном
+	CreateApiToken(ctx context.Context, in *CreateApiTokenRequest, opts ...grpc.CallOption) (*ApiTokenDTO, error)
+	// Метод RPC для получения действующих токенов пользователя. Принимает UserID и возвращает ApiTokens
+	GetApiTokens(ctx context.Context, in *UserID, opts ...grpc.CallOption) (*ApiTokens, error)
+	// Метод RPC для отзыва токена. Принимает ApiTokenRequest и возвращает пустой ответ
+	RevokeApiToken(ctx context.Context, in *ApiTokenRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Метод RPC для проверки токена из запроса, отмечает время его использования. Принимает AuthenticateApiTokenRequest и возвращает ApiTokenAuth
+	AuthenticateApiToken(ctx context.Context, in *AuthenticateApiTokenRequest, opts ...grpc.CallOption) (*ApiTokenAuth, error)
+	// Метод RPC для создания организации, создатель становится ее владельцем. Принимает CreateOrganizationRequest и возвращает OrganizationDTO
+	CreateOrganization(ctx context.Context, in *CreateOrganizationRequest, opts ...grpc.CallOption) (*OrganizationDTO, error)
+	// Метод RPC для получения организаций пользователя. Принимает UserID и возвращает Organizations
+	GetUserOrganizations(ctx context.Context, in *UserID, opts ...grpc.CallOption) (*Organizations, error)
+	// Метод RPC для получения участников организации. Принимает OrganizationRequest и возвращает OrganizationMembers
+	ListOrganizationMembers(ctx context.Context, in *OrganizationRequest, opts ...grpc.CallOption) (*OrganizationMembers, error)
+	// Метод RPC для приглашения в организацию, на email уходит письмо со ссылкой. Принимает InviteMemberRequest и возвращает InvitationDTO
+	InviteMember(ctx context.Context, in *InviteMemberRequest, opts ...grpc.CallOption) (*InvitationDTO, error)
+	// Метод RPC для принятия приглашения по токену из письма. Принимает AcceptInvitationRequest и возвращает OrganizationDTO
+	AcceptInvitation(ctx context.Context, in *AcceptInvitationRequest, opts ...grpc.CallOption) (*OrganizationDTO, error)
+	// Метод RPC для изменения роли участника организации. Принимает MemberRequest и возвращает пустой ответ
+	SetMemberRole(ctx context.Context, in *MemberRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Метод RPC для исключения участника из организации или выхода из нее. Принимает MemberRequest и возвращает пустой ответ
+	RemoveMember(ctx context.Context, in *MemberRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Метод RPC для создания команды в организации. Принимает CreateTeamRequest и возвращает TeamDTO
+	CreateTeam(ctx context.Context, in *CreateTeamRequest, opts ...grpc.CallOption) (*TeamDTO, error)
+	// Метод RPC для получения команд организации. Принимает OrganizationRequest и возвращает Teams
+	GetTeams(ctx context.Context, in *OrganizationRequest, opts ...grpc.CallOption) (*Teams, error)
+	// Метод RPC для добавления участника организации в команду. Принимает TeamMemberRequest и возвращает пустой ответ
+	AddTeamMember(ctx context.Context, in *TeamMemberRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Метод RPC для удаления участника из команды. Принимает TeamMemberRequest и возвращает пустой ответ
+	RemoveTeamMember(ctx context.Context, in *TeamMemberRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Метод RPC для получения участников команды. Принимает TeamMemberRequest и возвращает OrganizationMembers
+	ListTeamMembers(ctx context.Context, in *TeamMemberRequest, opts ...grpc.CallOption) (*OrganizationMembers, error)
+	// Метод RPC для загрузки аватара. Принимает UploadAvatarRequest и возвращает UserDTO
+	UploadAvatar(ctx context.Context, in *UploadAvatarRequest, opts ...grpc.CallOption) (*UserDTO, error)
+	// Метод RPC для удаления аватара. Принимает UserID и возвращает UserDTO
+	DeleteAvatar(ctx context.Context, in *UserID, opts ...grpc.CallOption) (*UserDTO, error)
+	// Метод RPC для получения настроек уведомлений. Принимает UserID и возвращает NotificationPreferences
+	GetNotificationPreferences(ctx context.Context, in *UserID, opts ...grpc.CallOption) (*NotificationPreferences, error)
+	// Метод RPC для изменения настроек уведомлений, события вне запроса не меняются. Принимает NotificationPreferences и возвращает NotificationPreferences
+	UpdateNotificationPreferences(ctx context.Context, in *NotificationPreferences, opts ...grpc.CallOption) (*NotificationPreferences, error)
+	// Метод RPC для отбора пользователей, которые хотят получить уведомление о событии по каналу. Принимает NotificationRecipientsRequest и возвращает NotificationRecipients
+	FilterNotificationRecipients(ctx context.Context, in *NotificationRecipientsRequest, opts ...grpc.CallOption) (*NotificationRecipients, error)
+	// Метод RPC для запроса выгрузки данных пользователя. Архив собирается асинхронно, ссылка на него приходит письмом. Принимает UserID и возвращает DataExport
+	RequestDataExport(ctx context.Context, in *UserID, opts ...grpc.CallOption) (*DataExport, error)
+	// Метод RPC для получения состояния последней выгрузки данных пользователя. Принимает UserID и возвращает DataExport
+	GetDataExport(ctx context.Context, in *UserID, opts ...grpc.CallOption) (*DataExport, error)
+	// Метод RPC для скачивания архива выгрузки по токену из письма. Принимает DataExportDownloadRequest и возвращает DataExportArchive
+	DownloadDataExport(ctx context.Context, in *DataExportDownloadRequest, opts ...grpc.CallOption) (*DataExportArchive, error)
+	// Метод RPC для запроса удаления аккаунта с обезличиванием данных во всех сервисах. Принимает RequestErasureRequest и возвращает google.protobuf.Empty
+	RequestErasure(ctx context.Context, in *RequestErasureRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Метод RPC для подтверждения удаления аккаунта по токену из письма. Принимает ConfirmErasureRequest и возвращает UserDeletion
+	ConfirmErasure(ctx context.Context, in *ConfirmErasureRequest, opts ...grpc.CallOption) (*UserDeletion, error)
+}
+
+type userServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewUserServiceClient(cc grpc.ClientConnInterface) UserServiceClient {
+	return &userServiceClient{cc}
+}
+
+func (c *userServiceClient) RegisterUser(ctx context.Context, in *CreateUserDTO, opts ...grpc.CallOption) (*UserID, error) {
+	out := new(UserID)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/RegisterUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) UpdateUser(ctx context.Context, in *UserDTO, opts ...grpc.CallOption) (*UserDTO, error) {
+	out := new(UserDTO)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/UpdateUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) UpdatePassword(ctx context.Context, in *UpdateUserPasswordDTO, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/UpdatePassword", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) DeleteUser(ctx context.Context, in *UserID, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/DeleteUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) GetUserDeletion(ctx context.Context, in *UserID, opts ...grpc.CallOption) (*UserDeletion, error) {
+	out := new(UserDeletion)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/GetUserDeletion", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) GetUserByID(ctx context.Context, in *UserID, opts ...grpc.CallOption) (*UserDTO, error) {
+	out := new(UserDTO)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/GetUserByID", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) GetUsersByIDs(ctx context.Context, in *UserIDs, opts ...grpc.CallOption) (*Users, error) {
+	out := new(Users)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/GetUsersByIDs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) GetUsersByUsernames(ctx context.Context, in *Usernames, opts ...grpc.CallOption) (*Users, error) {
+	out := new(Users)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/GetUsersByUsernames", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) GetUserByUsernameOrEmail(ctx context.Context, in *UserDTO, opts ...grpc.CallOption) (*UserDTO, error) {
+	out := new(UserDTO)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/GetUserByUsernameOrEmail", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) ListUsers(ctx context.Context, in *ListUsersRequest, opts ...grpc.CallOption) (*ListUsersResponse, error) {
+	out := new(ListUsersResponse)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/ListUsers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) Login(ctx context.Context, in *UserLoginDTO, opts ...grpc.CallOption) (*UserDTO, error) {
+	out := new(UserDTO)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/Login", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) VerifyEmail(ctx context.Context, in *VerifyEmailRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/VerifyEmail", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) ResendVerificationEmail(ctx context.Context, in *ResendVerificationEmailRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/ResendVerificationEmail", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) RequestPasswordReset(ctx context.Context, in *RequestPasswordResetRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/RequestPasswordReset", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) ResetPassword(ctx context.Context, in *ResetPasswordRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/ResetPassword", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) RequestEmailChange(ctx context.Context, in *RequestEmailChangeRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/RequestEmailChange", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) ConfirmEmailChange(ctx context.Context, in *ConfirmEmailChangeRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/ConfirmEmailChange", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) CreateSession(ctx context.Context, in *SessionDTO, opts ...grpc.CallOption) (*SessionDTO, error) {
+	out := new(SessionDTO)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/CreateSession", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) RotateSession(ctx context.Context, in *RotateSessionRequest, opts ...grpc.CallOption) (*SessionDTO, error) {
+	out := new(SessionDTO)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/RotateSession", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) RevokeSession(ctx context.Context, in *SessionRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/RevokeSession", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) RevokeUserSessions(ctx context.Context, in *UserID, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/RevokeUserSessions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) EnrollTwoFactor(ctx context.Context, in *UserID, opts ...grpc.CallOption) (*TwoFactorEnrollment, error) {
+	out := new(TwoFactorEnrollment)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/EnrollTwoFactor", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) ConfirmTwoFactor(ctx context.Context, in *TwoFactorCodeRequest, opts ...grpc.CallOption) (*RecoveryCodes, error) {
+	out := new(RecoveryCodes)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/ConfirmTwoFactor", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) DisableTwoFactor(ctx context.Context, in *TwoFactorCodeRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/DisableTwoFactor", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) RegenerateRecoveryCodes(ctx context.Context, in *TwoFactorCodeRequest, opts ...grpc.CallOption) (*RecoveryCodes, error) {
+	out := new(RecoveryCodes)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/RegenerateRecoveryCodes", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) LoginTwoFactor(ctx context.Context, in *TwoFactorLoginRequest, opts ...grpc.CallOption) (*UserDTO, error) {
+	out := new(UserDTO)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/LoginTwoFactor", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) UnlockUser(ctx context.Context, in *UserID, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/UnlockUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) SetUserRole(ctx context.Context, in *SetUserRoleRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/SetUserRole", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) LoginExternal(ctx context.Context, in *ExternalLoginDTO, opts ...grpc.CallOption) (*UserDTO, error) {
+	out := new(UserDTO)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/LoginExternal", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) CreateApiToken(ctx context.Context, in *CreateApiTokenRequest, opts ...grpc.CallOption) (*ApiTokenDTO, error) {
+	out := new(ApiTokenDTO)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/CreateApiToken", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) GetApiTokens(ctx context.Context, in *UserID, opts ...grpc.CallOption) (*ApiTokens, error) {
+	out := new(ApiTokens)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/GetApiTokens", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) RevokeApiToken(ctx context.Context, in *ApiTokenRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/RevokeApiToken", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) AuthenticateApiToken(ctx context.Context, in *AuthenticateApiTokenRequest, opts ...grpc.CallOption) (*ApiTokenAuth, error) {
+	out := new(ApiTokenAuth)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/AuthenticateApiToken", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) CreateOrganization(ctx context.Context, in *CreateOrganizationRequest, opts ...grpc.CallOption) (*OrganizationDTO, error) {
+	out := new(OrganizationDTO)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/CreateOrganization", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) GetUserOrganizations(ctx context.Context, in *UserID, opts ...grpc.CallOption) (*Organizations, error) {
+	out := new(Organizations)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/GetUserOrganizations", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) ListOrganizationMembers(ctx context.Context, in *OrganizationRequest, opts ...grpc.CallOption) (*OrganizationMembers, error) {
+	out := new(OrganizationMembers)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/ListOrganizationMembers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) InviteMember(ctx context.Context, in *InviteMemberRequest, opts ...grpc.CallOption) (*InvitationDTO, error) {
+	out := new(InvitationDTO)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/InviteMember", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) AcceptInvitation(ctx context.Context, in *AcceptInvitationRequest, opts ...grpc.CallOption) (*OrganizationDTO, error) {
+	out := new(OrganizationDTO)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/AcceptInvitation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) SetMemberRole(ctx context.Context, in *MemberRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/SetMemberRole", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) RemoveMember(ctx context.Context, in *MemberRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/RemoveMember", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) CreateTeam(ctx context.Context, in *CreateTeamRequest, opts ...grpc.CallOption) (*TeamDTO, error) {
+	out := new(TeamDTO)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/CreateTeam", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) GetTeams(ctx context.Context, in *OrganizationRequest, opts ...grpc.CallOption) (*Teams, error) {
+	out := new(Teams)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/GetTeams", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) AddTeamMember(ctx context.Context, in *TeamMemberRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/AddTeamMember", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) RemoveTeamMember(ctx context.Context, in *TeamMemberRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/RemoveTeamMember", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) ListTeamMembers(ctx context.Context, in *TeamMemberRequest, opts ...grpc.CallOption) (*OrganizationMembers, error) {
+	out := new(OrganizationMembers)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/ListTeamMembers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) UploadAvatar(ctx context.Context, in *UploadAvatarRequest, opts ...grpc.CallOption) (*UserDTO, error) {
+	out := new(UserDTO)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/UploadAvatar", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) DeleteAvatar(ctx context.Context, in *UserID, opts ...grpc.CallOption) (*UserDTO, error) {
+	out := new(UserDTO)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/DeleteAvatar", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) GetNotificationPreferences(ctx context.Context, in *UserID, opts ...grpc.CallOption) (*NotificationPreferences, error) {
+	out := new(NotificationPreferences)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/GetNotificationPreferences", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) UpdateNotificationPreferences(ctx context.Context, in *NotificationPreferences, opts ...grpc.CallOption) (*NotificationPreferences, error) {
+	out := new(NotificationPreferences)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/UpdateNotificationPreferences", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) FilterNotificationRecipients(ctx context.Context, in *NotificationRecipientsRequest, opts ...grpc.CallOption) (*NotificationRecipients, error) {
+	out := new(NotificationRecipients)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/FilterNotificationRecipients", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) RequestDataExport(ctx context.Context, in *UserID, opts ...grpc.CallOption) (*DataExport, error) {
+	out := new(DataExport)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/RequestDataExport", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) GetDataExport(ctx context.Context, in *UserID, opts ...grpc.CallOption) (*DataExport, error) {
+	out := new(DataExport)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/GetDataExport", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) DownloadDataExport(ctx context.Context, in *DataExportDownloadRequest, opts ...grpc.CallOption) (*DataExportArchive, error) {
+	out := new(DataExportArchive)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/DownloadDataExport", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) RequestErasure(ctx context.Context, in *RequestErasureRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/RequestErasure", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) ConfirmErasure(ctx context.Context, in *ConfirmErasureRequest, opts ...grpc.CallOption) (*UserDeletion, error) {
+	out := new(UserDeletion)
+	err := c.cc.Invoke(ctx, "/userservice.UserService/ConfirmErasure", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UserServiceServer is the server API for UserService service.
+// All implementations must embed UnimplementedUserServiceServer
+// for forward compatibility
+type UserServiceServer interface {
+	// Метод RPC для регистрации пользователя. Принимает CreateUserDTO и возвращает UserID
+	RegisterUser(context.Context, *CreateUserDTO) (*UserID, error)
+	// Метод RPC для обновления данных пользователя. Принимает и возвращает UserDTO
+	UpdateUser(context.Context, *UserDTO) (*UserDTO, error)
+	// Метод RPC для обновления пароля пользователя. Принимает UpdateUserPasswordDTO и возвращает пустой ответ
+	UpdatePassword(context.Context, *UpdateUserPasswordDTO) (*emptypb.Empty, error)
+	// Метод RPC для удаления пользователя. Принимает UserID и возвращает пустой ответ
+	DeleteUser(context.Context, *UserID) (*emptypb.Empty, error)
+	// Метод RPC для получения состояния последнего удаления пользователя. Принимает UserID и возвращает UserDeletion
+	GetUserDeletion(context.Context, *UserID) (*UserDeletion, error)
+	// Метод RPC для получения данных пользователя по ID. Принимает UserID и возвращает UserDTO
+	GetUserByID(context.Context, *UserID) (*UserDTO, error)
+	// Метод RPC для получения данных нескольких пользователей одним запросом. Принимает UserIDs и возвращает Users,
+	// пользователи, которых нет или которые удаляются, в ответ не попадают
+	GetUsersByIDs(context.Context, *UserIDs) (*Users, error)
+	// Метод RPC для получения данных нескольких пользователей по именам одним запросом. Принимает Usernames и возвращает
+	// Users вместе с разрешениями ролей, имена сравниваются без учета регистра. Пользователи, которых нет или которые
+	// удаляются, в ответ не попадают
+	GetUsersByUsernames(context.Context, *Usernames) (*Users, error)
+	// Метод RPC для получения данных пользователя по имени или email. Принимает UserDTO и возвращает UserDTO
+	GetUserByUsernameOrEmail(context.Context, *UserDTO) (*UserDTO, error)
+	// Метод RPC для постраничного поиска пользователей по началу username или email. Принимает ListUsersRequest и возвращает ListUsersResponse
+	ListUsers(context.Context, *ListUsersRequest) (*ListUsersResponse, error)
+	// Метод RPC для входа пользователя. Принимает UserLoginDTO и возвращает UserDTO
+	Login(context.Context, *UserLoginDTO) (*UserDTO, error)
+	// Метод RPC для подтверждения email по токену из письма. Принимает VerifyEmailRequest и возвращает пустой ответ
+	VerifyEmail(context.Context, *VerifyEmailRequest) (*emptypb.Empty, error)
+	// Метод RPC для повторной отправки письма с подтверждением email. Принимает ResendVerificationEmailRequest и возвращает пустой ответ
+	ResendVerificationEmail(context.Context, *ResendVerificationEmailRequest) (*emptypb.Empty, error)
+	// Метод RPC для отправки письма со ссылкой на сброс пароля. Принимает RequestPasswordResetRequest и возвращает пустой ответ
+	RequestPasswordReset(context.Context, *RequestPasswordResetRequest) (*emptypb.Empty, error)
+	// Метод RPC для установки нового пароля по токену из письма. Принимает ResetPasswordRequest и возвращает пустой ответ
+	ResetPassword(context.Context, *ResetPasswordRequest) (*emptypb.Empty, error)
+	// Метод RPC для смены email, на новый адрес уходит ссылка для подтверждения, на старый - уведомление. Принимает RequestEmailChangeRequest и возвращает пустой ответ
+	RequestEmailChange(context.Context, *RequestEmailChangeRequest) (*emptypb.Empty, error)
+	// Метод RPC для подтверждения нового email по токену из письма. Принимает ConfirmEmailChangeRequest и возвращает пустой ответ
+	ConfirmEmailChange(context.Context, *ConfirmEmailChangeRequest) (*emptypb.Empty, error)
+	// Метод RPC для создания сессии после входа. Принимает и возвращает SessionDTO
+	CreateSession(context.Context, *SessionDTO) (*SessionDTO, error)
+	// Метод RPC для замены refresh токена сессии. Повторное предъявление старого токена завершает сессию. Принимает RotateSessionRequest и возвращает SessionDTO
+	RotateSession(context.Context, *RotateSessionRequest) (*SessionDTO, error)
+	// Метод RPC для завершения сессии. Принимает SessionRequest и возвращает пустой ответ
+	RevokeSession(context.Context, *SessionRequest) (*emptypb.Empty, error)
+	// Метод RPC для завершения всех сессий пользователя. Принимает UserID и возвращает пустой ответ
+	RevokeUserSessions(context.Context, *UserID) (*emptypb.Empty, error)
+	// Метод RPC для выдачи секрета двухфакторной аутентификации. Принимает UserID и возвращает TwoFactorEnrollment
+	EnrollTwoFactor(context.Context, *UserID) (*TwoFactorEnrollment, error)
+	// Метод RPC для подтверждения подключения второго фактора кодом из приложения. Принимает TwoFactorCodeRequest и возвращает RecoveryCodes
+	ConfirmTwoFactor(context.Context, *TwoFactorCodeRequest) (*RecoveryCodes, error)
+	// Метод RPC для отключения второго фактора. Принимает TwoFactorCodeRequest и возвращает пустой ответ
+	DisableTwoFactor(context.Context, *TwoFactorCodeRequest) (*emptypb.Empty, error)
+	// Метод RPC для выпуска новых кодов восстановления взамен старых. Принимает TwoFactorCodeRequest и возвращает RecoveryCodes
+	RegenerateRecoveryCodes(context.Context, *TwoFactorCodeRequest) (*RecoveryCodes, error)
+	// Метод RPC для второго шага входа. Принимает TwoFactorLoginRequest и возвращает UserDTO
+	LoginTwoFactor(context.Context, *TwoFactorLoginRequest) (*UserDTO, error)
+	// Метод RPC для снятия блокировки входа после серии неудачных попыток. Принимает UserID и возвращает пустой ответ
+	UnlockUser(context.Context, *UserID) (*emptypb.Empty, error)
+	// Метод RPC для назначения пользователю роли. Принимает SetUserRoleRequest и возвращает пустой ответ
+	SetUserRole(context.Context, *SetUserRoleRequest) (*emptypb.Empty, error)
+	// Метод RPC для входа через внешнего провайдера OpenID Connect. При первом входе пользователь создается или привязывается к аккаунту с тем же подтвержденным email. Принимает ExternalLoginDTO и возвращает UserDTO
+	LoginExternal(context.Context, *ExternalLoginDTO) (*UserDTO, error)
+	// Метод RPC для выпуска персонального токена доступа. Принимает CreateApiTokenRequest и возвращает ApiTokenDTO с самим токеном
+	CreateApiToken(context.Context, *CreateApiTokenRequest) (*ApiTokenDTO, error)
+	// Метод RPC для получения действующих токенов пользователя. Принимает UserID и возвращает ApiTokens
+	GetApiTokens(context.Context, *UserID) (*ApiTokens, error)
+	// Метод RPC для отзыва токена. Принимает ApiTokenRequest и возвращает пустой ответ
+	RevokeApiToken(context.Context, *ApiTokenRequest) (*emptypb.Empty, error)
+	// Метод RPC для проверки токена из запроса, отмечает время его использования. Принимает AuthenticateApiTokenRequest и возвращает ApiTokenAuth
+	AuthenticateApiToken(context.Context, *AuthenticateApiTokenRequest) (*ApiTokenAuth, error)
+	// Метод RPC для создания организации, создатель становится ее владельцем. Принимает CreateOrganizationRequest и возвращает OrganizationDTO
+	CreateOrganization(context.Context, *CreateOrganizationRequest) (*OrganizationDTO, error)
+	// Метод RPC для получения организаций пользователя. Принимает UserID и возвращает Organizations
+	GetUserOrganizations(context.Context, *UserID) (*Organizations, error)
+	// Метод RPC для получения участников организации. Принимает OrganizationRequest и возвращает OrganizationMembers
+	ListOrganizationMembers(context.Context, *OrganizationRequest) (*OrganizationMembers, error)
+	// Метод RPC для приглашения в организацию, на email уходит письмо со ссылкой. Принимает InviteMemberRequest и возвращает InvitationDTO
+	InviteMember(context.Context, *InviteMemberRequest) (*InvitationDTO, error)
+	// Метод RPC для принятия приглашения по токену из письма. Принимает AcceptInvitationRequest и возвращает OrganizationDTO
+	AcceptInvitation(context.Context, *AcceptInvitationRequest) (*OrganizationDTO, error)
+	// Метод RPC для изменения роли участника организации. Принимает MemberRequest и возвращает пустой ответ
+	SetMemberRole(context.Context, *MemberRequest) (*emptypb.Empty, error)
+	// Метод RPC для исключения участника из организации или выхода из нее. Принимает MemberRequest и возвращает пустой ответ
+	RemoveMember(context.Context, *MemberRequest) (*emptypb.Empty, error)
+	// Метод RPC для создания команды в организации. Принимает CreateTeamRequest и возвращает TeamDTO
+	CreateTeam(context.Context, *CreateTeamRequest) (*TeamDTO, error)
+	// Метод RPC для получения команд организации. Принимает OrganizationRequest и возвращает Teams
+	GetTeams(context.Context, *OrganizationRequest) (*Teams, error)
+	// Метод RPC для добавления участника организации в команду. Принимает TeamMemberRequest и возвращает пустой ответ
+	AddTeamMember(context.Context, *TeamMemberRequest) (*emptypb.Empty, error)
+	// Метод RPC для удаления участника из команды. Принимает TeamMemberRequest и возвращает пустой ответ
+	RemoveTeamMember(context.Context, *TeamMemberRequest) (*emptypb.Empty, error)
+	// Метод RPC для получения участников команды. Принимает TeamMemberRequest и возвращает OrganizationMembers
+	ListTeamMembers(context.Context, *TeamMemberRequest) (*OrganizationMembers, error)
+	// Метод RPC для загрузки аватара. Принимает UploadAvatarRequest и возвращает UserDTO
+	UploadAvatar(context.Context, *UploadAvatarRequest) (*UserDTO, error)
+	// Метод RPC для удаления аватара. Принимает UserID и возвращает UserDTO
+	DeleteAvatar(context.Context, *UserID) (*UserDTO, error)
+	// Метод RPC для получения настроек уведомлений. Принимает UserID и возвращает NotificationPreferences
+	GetNotificationPreferences(context.Context, *UserID) (*NotificationPreferences, error)
+	// Метод RPC для изменения настроек уведомлений, события вне запроса не меняются. Принимает NotificationPreferences и возвращает NotificationPreferences
+	UpdateNotificationPreferences(context.Context, *NotificationPreferences) (*NotificationPreferences, error)
+	// Метод RPC для отбора пользователей, которые хотят получить уведомление о событии по каналу. Принимает NotificationRecipientsRequest и возвращает NotificationRecipients
+	FilterNotificationRecipients(context.Context, *NotificationRecipientsRequest) (*NotificationRecipients, error)
+	// Метод RPC для запроса выгрузки данных пользователя. Архив собирается асинхронно, ссылка на него приходит письмом. Принимает UserID и возвращает DataExport
+	RequestDataExport(context.Context, *UserID) (*DataExport, error)
+	// Метод RPC для получения состояния последней выгрузки данных пользователя. Принимает UserID и возвращает DataExport
+	GetDataExport(context.Context, *UserID) (*DataExport, error)
+	// Метод RPC для скачивания архива выгрузки по токену из письма. Принимает DataExportDownloadRequest и возвращает DataExportArchive
+	DownloadDataExport(context.Context, *DataExportDownloadRequest) (*DataExportArchive, error)
+	// Метод RPC для запроса удаления аккаунта с обезличиванием данных во всех сервисах. Принимает RequestErasureRequest и возвращает google.protobuf.Empty
+	RequestErasure(context.Context, *RequestErasureRequest) (*emptypb.Empty, error)
+	// Метод RPC для подтверждения удаления аккаунта по токену из письма. Принимает ConfirmErasureRequest и возвращает UserDeletion
+	ConfirmErasure(context.Context, *ConfirmErasureRequest) (*UserDeletion, error)
+	mustEmbedUnimplementedUserServiceServer()
+}
+
+// UnimplementedUserServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedUserServiceServer struct {
+}
+
+func (UnimplementedUserServiceServer) RegisterUser(context.Context, *CreateUserDTO) (*UserID, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterUser not implemented")
+}
+func (UnimplementedUserServiceServer) UpdateUser(context.Context, *UserDTO) (*UserDTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateUser not implemented")
+}
+func (UnimplementedUserServiceServer) UpdatePassword(context.Context, *UpdateUserPasswordDTO) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdatePassword not implemented")
+}
+func (UnimplementedUserServiceServer) DeleteUser(context.Context, *UserID) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteUser not implemented")
+}
+func (UnimplementedUserServiceServer) GetUserDeletion(context.Context, *UserID) (*UserDeletion, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUserDeletion not implemented")
+}
+func (UnimplementedUserServiceServer) GetUserByID(context.Context, *UserID) (*UserDTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUserByID not implemented")
+}
+func (UnimplementedUserServiceServer) GetUsersByIDs(context.Context, *UserIDs) (*Users, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUsersByIDs not implemented")
+}
+func (UnimplementedUserServiceServer) GetUsersByUsernames(context.Context, *Usernames) (*Users, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUsersByUsernames not implemented")
+}
+func (UnimplementedUserServiceServer) GetUserByUsernameOrEmail(context.Context, *UserDTO) (*UserDTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUserByUsernameOrEmail not implemented")
+}
+func (UnimplementedUserServiceServer) ListUsers(context.Context, *ListUsersRequest) (*ListUsersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListUsers not implemented")
+}
+func (UnimplementedUserServiceServer) Login(context.Context, *UserLoginDTO) (*UserDTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Login not implemented")
+}
+func (UnimplementedUserServiceServer) VerifyEmail(context.Context, *VerifyEmailRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyEmail not implemented")
+}
+func (UnimplementedUserServiceServer) ResendVerificationEmail(context.Context, *ResendVerificationEmailRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResendVerificationEmail not implemented")
+}
+func (UnimplementedUserServiceServer) RequestPasswordReset(context.Context, *RequestPasswordResetRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequestPasswordReset not implemented")
+}
+func (UnimplementedUserServiceServer) ResetPassword(context.Context, *ResetPasswordRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResetPassword not implemented")
+}
+func (UnimplementedUserServiceServer) RequestEmailChange(context.Context, *RequestEmailChangeRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequestEmailChange not implemented")
+}
+func (UnimplementedUserServiceServer) ConfirmEmailChange(context.Context, *ConfirmEmailChangeRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConfirmEmailChange not implemented")
+}
+func (UnimplementedUserServiceServer) CreateSession(context.Context, *SessionDTO) (*SessionDTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateSession not implemented")
+}
+func (UnimplementedUserServiceServer) RotateSession(context.Context, *RotateSessionRequest) (*SessionDTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RotateSession not implemented")
+}
+func (UnimplementedUserServiceServer) RevokeSession(context.Context, *SessionRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeSession not implemented")
+}
+func (UnimplementedUserServiceServer) RevokeUserSessions(context.Context, *UserID) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeUserSessions not implemented")
+}
+func (UnimplementedUserServiceServer) EnrollTwoFactor(context.Context, *UserID) (*TwoFactorEnrollment, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EnrollTwoFactor not implemented")
+}
+func (UnimplementedUserServiceServer) ConfirmTwoFactor(context.Context, *TwoFactorCodeRequest) (*RecoveryCodes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConfirmTwoFactor not implemented")
+}
+func (UnimplementedUserServiceServer) DisableTwoFactor(context.Context, *TwoFactorCodeRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DisableTwoFactor not implemented")
+}
+func (UnimplementedUserServiceServer) RegenerateRecoveryCodes(context.Context, *TwoFactorCodeRequest) (*RecoveryCodes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegenerateRecoveryCodes not implemented")
+}
+func (UnimplementedUserServiceServer) LoginTwoFactor(context.Context, *TwoFactorLoginRequest) (*UserDTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LoginTwoFactor not implemented")
+}
+func (UnimplementedUserServiceServer) UnlockUser(context.Context, *UserID) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnlockUser not implemented")
+}
+func (UnimplementedUserServiceServer) SetUserRole(context.Context, *SetUserRoleRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetUserRole not implemented")
+}
+func (UnimplementedUserServiceServer) LoginExternal(context.Context, *ExternalLoginDTO) (*UserDTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LoginExternal not implemented")
+}
+func (UnimplementedUserServiceServer) CreateApiToken(context.Context, *CreateApiTokenRequest) (*ApiTokenDTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateApiToken not implemented")
+}
+func (UnimplementedUserServiceServer) GetApiTokens(context.Context, *UserID) (*ApiTokens, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetApiTokens not implemented")
+}
+func (UnimplementedUserServiceServer) RevokeApiToken(context.Context, *ApiTokenRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeApiToken not implemented")
+}
+func (UnimplementedUserServiceServer) AuthenticateApiToken(context.Context, *AuthenticateApiTokenRequest) (*ApiTokenAuth, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AuthenticateApiToken not implemented")
+}
+func (UnimplementedUserServiceServer) CreateOrganization(context.Context, *CreateOrganizationRequest) (*OrganizationDTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateOrganization not implemented")
+}
+func (UnimplementedUserServiceServer) GetUserOrganizations(context.Context, *UserID) (*Organizations, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUserOrganizations not implemented")
+}
+func (UnimplementedUserServiceServer) ListOrganizationMembers(context.Context, *OrganizationRequest) (*OrganizationMembers, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListOrganizationMembers not implemented")
+}
+func (UnimplementedUserServiceServer) InviteMember(context.Context, *InviteMemberRequest) (*InvitationDTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InviteMember not implemented")
+}
+func (UnimplementedUserServiceServer) AcceptInvitation(context.Context, *AcceptInvitationRequest) (*OrganizationDTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AcceptInvitation not implemented")
+}
+func (UnimplementedUserServiceServer) SetMemberRole(context.Context, *MemberRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetMemberRole not implemented")
+}
+func (UnimplementedUserServiceServer) RemoveMember(context.Context, *MemberRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveMember not implemented")
+}
+func (UnimplementedUserServiceServer) CreateTeam(context.Context, *CreateTeamRequest) (*TeamDTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateTeam not implemented")
+}
+func (UnimplementedUserServiceServer) GetTeams(context.Context, *OrganizationRequest) (*Teams, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTeams not implemented")
+}
+func (UnimplementedUserServiceServer) AddTeamMember(context.Context, *TeamMemberRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddTeamMember not implemented")
+}
+func (UnimplementedUserServiceServer) RemoveTeamMember(context.Context, *TeamMemberRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveTeamMember not implemented")
+}
+func (UnimplementedUserServiceServer) ListTeamMembers(context.Context, *TeamMemberRequest) (*OrganizationMembers, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTeamMembers not implemented")
+}
+func (UnimplementedUserServiceServer) UploadAvatar(context.Context, *UploadAvatarRequest) (*UserDTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UploadAvatar not implemented")
+}
+func (UnimplementedUserServiceServer) DeleteAvatar(context.Context, *UserID) (*UserDTO, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteAvatar not implemented")
+}
+func (UnimplementedUserServiceServer) GetNotificationPreferences(context.Context, *UserID) (*NotificationPreferences, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNotificationPreferences not implemented")
+}
+func (UnimplementedUserServiceServer) UpdateNotificationPreferences(context.Context, *NotificationPreferences) (*NotificationPreferences, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateNotificationPreferences not implemented")
+}
+func (UnimplementedUserServiceServer) FilterNotificationRecipients(context.Context, *NotificationRecipientsRequest) (*NotificationRecipients, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FilterNotificationRecipients not implemented")
+}
+func (UnimplementedUserServiceServer) RequestDataExport(context.Context, *UserID) (*DataExport, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequestDataExport not implemented")
+}
+func (UnimplementedUserServiceServer) GetDataExport(context.Context, *UserID) (*DataExport, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDataExport not implemented")
+}
+func (UnimplementedUserServiceServer) DownloadDataExport(context.Context, *DataExportDownloadRequest) (*DataExportArchive, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DownloadDataExport not implemented")
+}
+func (UnimplementedUserServiceServer) RequestErasure(context.Context, *RequestErasureRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequestErasure not implemented")
+}
+func (UnimplementedUserServiceServer) ConfirmErasure(context.Context, *ConfirmErasureRequest) (*UserDeletion, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConfirmErasure not implemented")
+}
+func (UnimplementedUserServiceServer) mustEmbedUnimplementedUserServiceServer() {}
+
+// UnsafeUserServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to UserServiceServer will
+// result in compilation errors.
+type UnsafeUserServiceServer interface {
+	mustEmbedUnimplementedUserServiceServer()
+}
+
+func RegisterUserServiceServer(s grpc.ServiceRegistrar, srv UserServiceServer) {
+	s.RegisterService(&UserService_ServiceDesc, srv)
+}
+
+func _UserService_RegisterUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateUserDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).RegisterUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/RegisterUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).RegisterUser(ctx, req.(*CreateUserDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_UpdateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).UpdateUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/UpdateUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).UpdateUser(ctx, req.(*UserDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_UpdatePassword_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateUserPasswordDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).UpdatePassword(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/UpdatePassword",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).UpdatePassword(ctx, req.(*UpdateUserPasswordDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_DeleteUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).DeleteUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/DeleteUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).DeleteUser(ctx, req.(*UserID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_GetUserDeletion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).GetUserDeletion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/GetUserDeletion",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).GetUserDeletion(ctx, req.(*UserID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_GetUserByID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).GetUserByID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/GetUserByID",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).GetUserByID(ctx, req.(*UserID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_GetUsersByIDs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserIDs)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).GetUsersByIDs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/GetUsersByIDs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).GetUsersByIDs(ctx, req.(*UserIDs))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_GetUsersByUsernames_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Usernames)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).GetUsersByUsernames(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/GetUsersByUsernames",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).GetUsersByUsernames(ctx, req.(*Usernames))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_GetUserByUsernameOrEmail_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).GetUserByUsernameOrEmail(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/GetUserByUsernameOrEmail",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).GetUserByUsernameOrEmail(ctx, req.(*UserDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_ListUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListUsersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).ListUsers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/ListUsers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).ListUsers(ctx, req.(*ListUsersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_Login_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserLoginDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).Login(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/Login",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).Login(ctx, req.(*UserLoginDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_VerifyEmail_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyEmailRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).VerifyEmail(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/VerifyEmail",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).VerifyEmail(ctx, req.(*VerifyEmailRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_ResendVerificationEmail_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResendVerificationEmailRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).ResendVerificationEmail(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/ResendVerificationEmail",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).ResendVerificationEmail(ctx, req.(*ResendVerificationEmailRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_RequestPasswordReset_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestPasswordResetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).RequestPasswordReset(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/RequestPasswordReset",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).RequestPasswordReset(ctx, req.(*RequestPasswordResetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_ResetPassword_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResetPasswordRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).ResetPassword(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/ResetPassword",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).ResetPassword(ctx, req.(*ResetPasswordRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_RequestEmailChange_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestEmailChangeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).RequestEmailChange(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/RequestEmailChange",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).RequestEmailChange(ctx, req.(*RequestEmailChangeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_ConfirmEmailChange_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConfirmEmailChangeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).ConfirmEmailChange(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/ConfirmEmailChange",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).ConfirmEmailChange(ctx, req.(*ConfirmEmailChangeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_CreateSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SessionDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).CreateSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/CreateSession",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).CreateSession(ctx, req.(*SessionDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_RotateSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RotateSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).RotateSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/RotateSession",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).RotateSession(ctx, req.(*RotateSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_RevokeSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).RevokeSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/RevokeSession",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).RevokeSession(ctx, req.(*SessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_RevokeUserSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).RevokeUserSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/RevokeUserSessions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).RevokeUserSessions(ctx, req.(*UserID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_EnrollTwoFactor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).EnrollTwoFactor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/EnrollTwoFactor",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).EnrollTwoFactor(ctx, req.(*UserID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_ConfirmTwoFactor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TwoFactorCodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).ConfirmTwoFactor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/ConfirmTwoFactor",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).ConfirmTwoFactor(ctx, req.(*TwoFactorCodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_DisableTwoFactor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TwoFactorCodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).DisableTwoFactor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/DisableTwoFactor",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).DisableTwoFactor(ctx, req.(*TwoFactorCodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_RegenerateRecoveryCodes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TwoFactorCodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).RegenerateRecoveryCodes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/RegenerateRecoveryCodes",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).RegenerateRecoveryCodes(ctx, req.(*TwoFactorCodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_LoginTwoFactor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TwoFactorLoginRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).LoginTwoFactor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/LoginTwoFactor",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).LoginTwoFactor(ctx, req.(*TwoFactorLoginRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_UnlockUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).UnlockUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/UnlockUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).UnlockUser(ctx, req.(*UserID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_SetUserRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetUserRoleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).SetUserRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/SetUserRole",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).SetUserRole(ctx, req.(*SetUserRoleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_LoginExternal_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExternalLoginDTO)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).LoginExternal(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/LoginExternal",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).LoginExternal(ctx, req.(*ExternalLoginDTO))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_CreateApiToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateApiTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).CreateApiToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/CreateApiToken",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).CreateApiToken(ctx, req.(*CreateApiTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_GetApiTokens_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).GetApiTokens(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/GetApiTokens",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).GetApiTokens(ctx, req.(*UserID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_RevokeApiToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApiTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).RevokeApiToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/RevokeApiToken",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).RevokeApiToken(ctx, req.(*ApiTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_AuthenticateApiToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuthenticateApiTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).AuthenticateApiToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/AuthenticateApiToken",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).AuthenticateApiToken(ctx, req.(*AuthenticateApiTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_CreateOrganization_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateOrganizationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).CreateOrganization(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/CreateOrganization",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).CreateOrganization(ctx, req.(*CreateOrganizationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_GetUserOrganizations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).GetUserOrganizations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/GetUserOrganizations",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).GetUserOrganizations(ctx, req.(*UserID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_ListOrganizationMembers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OrganizationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).ListOrganizationMembers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/ListOrganizationMembers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).ListOrganizationMembers(ctx, req.(*OrganizationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_InviteMember_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InviteMemberRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).InviteMember(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/InviteMember",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).InviteMember(ctx, req.(*InviteMemberRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_AcceptInvitation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AcceptInvitationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).AcceptInvitation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/AcceptInvitation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).AcceptInvitation(ctx, req.(*AcceptInvitationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_SetMemberRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MemberRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).SetMemberRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/SetMemberRole",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).SetMemberRole(ctx, req.(*MemberRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_RemoveMember_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MemberRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).RemoveMember(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/RemoveMember",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).RemoveMember(ctx, req.(*MemberRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_CreateTeam_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateTeamRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).CreateTeam(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/CreateTeam",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).CreateTeam(ctx, req.(*CreateTeamRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_GetTeams_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OrganizationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).GetTeams(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/GetTeams",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).GetTeams(ctx, req.(*OrganizationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_AddTeamMember_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TeamMemberRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).AddTeamMember(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/AddTeamMember",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).AddTeamMember(ctx, req.(*TeamMemberRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_RemoveTeamMember_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TeamMemberRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).RemoveTeamMember(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/RemoveTeamMember",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).RemoveTeamMember(ctx, req.(*TeamMemberRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_ListTeamMembers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TeamMemberRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).ListTeamMembers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/ListTeamMembers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).ListTeamMembers(ctx, req.(*TeamMemberRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_UploadAvatar_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UploadAvatarRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).UploadAvatar(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/UploadAvatar",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).UploadAvatar(ctx, req.(*UploadAvatarRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_DeleteAvatar_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).DeleteAvatar(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/DeleteAvatar",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).DeleteAvatar(ctx, req.(*UserID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_GetNotificationPreferences_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).GetNotificationPreferences(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/GetNotificationPreferences",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).GetNotificationPreferences(ctx, req.(*UserID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_UpdateNotificationPreferences_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NotificationPreferences)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).UpdateNotificationPreferences(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/UpdateNotificationPreferences",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).UpdateNotificationPreferences(ctx, req.(*NotificationPreferences))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_FilterNotificationRecipients_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NotificationRecipientsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).FilterNotificationRecipients(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/FilterNotificationRecipients",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).FilterNotificationRecipients(ctx, req.(*NotificationRecipientsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_RequestDataExport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).RequestDataExport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/RequestDataExport",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).RequestDataExport(ctx, req.(*UserID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_GetDataExport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).GetDataExport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/GetDataExport",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).GetDataExport(ctx, req.(*UserID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_DownloadDataExport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DataExportDownloadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).DownloadDataExport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/DownloadDataExport",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).DownloadDataExport(ctx, req.(*DataExportDownloadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_RequestErasure_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestErasureRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).RequestErasure(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/RequestErasure",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).RequestErasure(ctx, req.(*RequestErasureRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_ConfirmErasure_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConfirmErasureRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).ConfirmErasure(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/userservice.UserService/ConfirmErasure",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).ConfirmErasure(ctx, req.(*ConfirmErasureRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// UserService_ServiceDesc is the grpc.ServiceDesc for UserService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var UserService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "userservice.UserService",
+	HandlerType: (*UserServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "RegisterUser",
+			Handler:    _UserService_RegisterUser_Handler,
+		},
+		{
+			MethodName: "UpdateUser",
+			Handler:    _UserService_UpdateUser_Handler,
+		},
+		{
+			MethodName: "UpdatePassword",
+			Handler:    _UserService_UpdatePassword_Handler,
+		},
+		{
+			MethodName: "DeleteUser",
+			Handler:    _UserService_DeleteUser_Handler,
+		},
+		{
+			MethodName: "GetUserDeletion",
+			Handler:    _UserService_GetUserDeletion_Handler,
+		},
+		{
+			MethodName: "GetUserByID",
+			Handler:    _UserService_GetUserByID_Handler,
+		},
+		{
+			MethodName: "GetUsersByIDs",
+			Handler:    _UserService_GetUsersByIDs_Handler,
+		},
+		{
+			MethodName: "GetUsersByUsernames",
+			Handler:    _UserService_GetUsersByUsernames_Handler,
+		},
+		{
+			MethodName: "GetUserByUsernameOrEmail",
+			Handler:    _UserService_GetUserByUsernameOrEmail_Handler,
+		},
+		{
+			MethodName: "ListUsers",
+			Handler:    _UserService_ListUsers_Handler,
+		},
+		{
+			MethodName: "Login",
+			Handler:    _UserService_Login_Handler,
+		},
+		{
+			MethodName: "VerifyEmail",
+			Handler:    _UserService_VerifyEmail_Handler,
+		},
+		{
+			MethodName: "ResendVerificationEmail",
+			Handler:    _UserService_ResendVerificationEmail_Handler,
+		},
+		{
+			MethodName: "RequestPasswordReset",
+			Handler:    _UserService_RequestPasswordReset_Handler,
+		},
+		{
+			MethodName: "ResetPassword",
+			Handler:    _UserService_ResetPassword_Handler,
+		},
+		{
+			MethodName: "RequestEmailChange",
+			Handler:    _UserService_RequestEmailChange_Handler,
+		},
+		{
+			MethodName: "ConfirmEmailChange",
+			Handler:    _UserService_ConfirmEmailChange_Handler,
+		},
+		{
+			MethodName: "CreateSession",
+			Handler:    _UserService_CreateSession_Handler,
+		},
+		{
+			MethodName: "RotateSession",
+			Handler:    _UserService_RotateSession_Handler,
+		},
+		{
+			MethodName: "RevokeSession",
+			Handler:    _UserService_RevokeSession_Handler,
+		},
+		{
+			MethodName: "RevokeUserSessions",
+			Handler:    _UserService_RevokeUserSessions_Handler,
+		},
+		{
+			MethodName: "EnrollTwoFactor",
+			Handler:    _UserService_EnrollTwoFactor_Handler,
+		},
+		{
+			MethodName: "ConfirmTwoFactor",
+			Handler:    _UserService_ConfirmTwoFactor_Handler,
+		},
+		{
+			MethodName: "DisableTwoFactor",
+			Handler:    _UserService_DisableTwoFactor_Handler,
+		},
+		{
+			MethodName: "RegenerateRecoveryCodes",
+			Handler:    _UserService_RegenerateRecoveryCodes_Handler,
+		},
+		{
+			MethodName: "LoginTwoFactor",
+			Handler:    _UserService_LoginTwoFactor_Handler,
+		},
+		{
+			MethodName: "UnlockUser",
+			Handler:    _UserService_UnlockUser_Handler,
+		},
+		{
+			MethodName: "SetUserRole",
+			Handler:    _UserService_SetUserRole_Handler,
+		},
+		{
+			MethodName: "LoginExternal",
+			Handler:    _UserService_LoginExternal_Handler,
+		},
+		{
+			MethodName: "CreateApiToken",
+			Handler:    _UserService_CreateApiToken_Handler,
+		},
+		{
+			MethodName: "GetApiTokens",
+			Handler:    _UserService_GetApiTokens_Handler,
+		},
+		{
+			MethodName: "RevokeApiToken",
+			Handler:    _UserService_RevokeApiToken_Handler,
+		},
+		{
+			MethodName: "AuthenticateApiToken",
+			Handler:    _UserService_AuthenticateApiToken_Handler,
+		},
+		{
+			MethodName: "CreateOrganization",
+			Handler:    _UserService_CreateOrganization_Handler,
+		},
+		{
+			MethodName: "GetUserOrganizations",
+			Handler:    _UserService_GetUserOrganizations_Handler,
+		},
+		{
+			MethodName: "ListOrganizationMembers",
+			Handler:    _UserService_ListOrganizationMembers_Handler,
+		},
+		{
+			MethodName: "InviteMember",
+			Handler:    _UserService_InviteMember_Handler,
+		},
+		{
+			MethodName: "AcceptInvitation",
+			Handler:    _UserService_AcceptInvitation_Handler,
+		},
+		{
+			MethodName: "SetMemberRole",
+			Handler:    _UserService_SetMemberRole_Handler,
+		},
+		{
+			MethodName: "RemoveMember",
+			Handler:    _UserService_RemoveMember_Handler,
+		},
+		{
+			MethodName: "CreateTeam",
+			Handler:    _UserService_CreateTeam_Handler,
+		},
+		{
+			MethodName: "GetTeams",
+			Handler:    _UserService_GetTeams_Handler,
+		},
+		{
+			MethodName: "AddTeamMember",
+			Handler:    _UserService_AddTeamMember_Handler,
+		},
+		{
+			MethodName: "RemoveTeamMember",
+			Handler:    _UserService_RemoveTeamMember_Handler,
+		},
+		{
+			MethodName: "ListTeamMembers",
+			Handler:    _UserService_ListTeamMembers_Handler,
+		},
+		{
+			MethodName: "UploadAvatar",
+			Handler:    _UserService_UploadAvatar_Handler,
+		},
+		{
+			MethodName: "DeleteAvatar",
+			Handler:    _UserService_DeleteAvatar_Handler,
+		},
+		{
+			MethodName: "GetNotificationPreferences",
+			Handler:    _UserService_GetNotificationPreferences_Handler,
+		},
+		{
+			MethodName: "UpdateNotificationPreferences",
+			Handler:    _UserService_UpdateNotificationPreferences_Handler,
+		},
+		{
+			MethodName: "FilterNotificationRecipients",
+			Handler:    _UserService_FilterNotificationRecipients_Handler,
+		},
+		{
+			MethodName: "RequestDataExport",
+			Handler:    _UserService_RequestDataExport_Handler,
+		},
+		{
+			MethodName: "GetDataExport",
+			Handler:    _UserService_GetDataExport_Handler,
+		},
+		{
+			MethodName: "DownloadDataExport",
+			Handler:    _UserService_DownloadDataExport_Handler,
+		},
+		{
+			MethodName: "RequestErasure",
+			Handler:    _UserService_RequestErasure_Handler,
+		},
+		{
+			MethodName: "ConfirmErasure",
+			Handler:    _UserService_ConfirmErasure_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "users.proto",
+}
@@ -1,6 +1,7 @@
 package app
 
 import (
+	"context"
 	"fmt"
 	"github.com/gorilla/mux"
 	"github.com/opentracing/opentracing-go"
@@ -9,11 +10,16 @@ import (
 	"todo/config"
 	"todo/internal/api"
 	"todo/internal/api/grpc"
+	"todo/internal/api/rabbitmq"
 	"todo/internal/api/rest"
 	"todo/pkg/jaeger"
+	"todo/pkg/markdown"
 	"todo/pkg/rabbitmq/producer"
+	"todo/pkg/sharetoken"
 
+	"todo/internal/app_errors"
 	"todo/internal/clients/users"
+	"todo/internal/models"
 	"todo/internal/repository"
 	"todo/internal/service"
 	"todo/pkg/logging"
@@ -21,16 +27,23 @@ import (
 )
 
 type App struct {
-	cfg         *config.Config
-	logger      *zerolog.Logger
-	router      *mux.Router
-	todoService api.TodoService
+	cfg             *config.Config
+	logger          *zerolog.Logger
+	router          *mux.Router
+	todoService     api.TodoService
+	deletionService api.UserDeletionService
+	updatesService  api.UserUpdatesService
+	exportService   api.UserExportService
 	//rabbitmqProducer *producer.Producer
 }
 
 func NewApp(cfg *config.Config) (*App, error) {
 	logger := logging.NewLogger(cfg.Logging)
 
+	if !models.IsUserDeletionPolicy(cfg.UserDeletion.Policy) {
+		return nil, fmt.Errorf("[NewApp] %w: %q", app_errors.ErrUnknownUserDeletionPolicy, cfg.UserDeletion.Policy)
+	}
+
 	// подключимся к базе данных
 	databaseConn, err := postgresql.NewPgxConn(&cfg.Postgres)
 	if err != nil {
@@ -55,12 +68,47 @@ func NewApp(cfg *config.Config) (*App, error) {
 		return nil, fmt.Errorf("start rabbit producer: %w", err)
 	}
 
-	todoService := service.NewTodoService(cfg, todoRepo, logger, usersClient, todoProducer)
+	// ответы сервису users на удаление пользователей
+	userDeletionProducer, err := producer.New(
+		&cfg.RabbitConfig,
+		cfg.UserDeletionRepliesExchange,
+		cfg.UserDeletionRepliesQueue,
+		logger,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("start rabbit user deletion producer: %w", err)
+	}
+
+	// данные пользователей для выгрузки в сервис users
+	userExportProducer, err := producer.New(
+		&cfg.RabbitConfig,
+		cfg.UserExportRepliesExchange,
+		cfg.UserExportRepliesQueue,
+		logger,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("start rabbit user export producer: %w", err)
+	}
+
+	todoService := service.NewTodoService(
+		cfg,
+		todoRepo,
+		logger,
+		usersClient,
+		todoProducer,
+		userDeletionProducer,
+		userExportProducer,
+		markdown.NewRenderer(),
+		sharetoken.NewSigner(cfg.ShareLinks.Secret),
+	)
 
 	return &App{
-		cfg:         cfg,
-		logger:      logger,
-		todoService: todoService,
+		cfg:             cfg,
+		logger:          logger,
+		todoService:     todoService,
+		deletionService: todoService,
+		updatesService:  todoService,
+		exportService:   todoService,
 	}, nil
 }
 
@@ -86,6 +134,11 @@ func (a *App) RunAPI() error {
 		return fmt.Errorf("[RunApp] run GRPC: %w", err)
 	})
 
+	group.Go(func() error {
+		err := rabbitmq.ConsumeRabbitMessages(context.Background(), a.cfg, a.logger, a.deletionService, a.updatesService, a.exportService)
+		return fmt.Errorf("[RunApp] consume rabbit messages: %w", err)
+	})
+
 	if err := group.Wait(); err != nil {
 		return fmt.Errorf("[RunApp] run: %w", err)
 	}
@@ -1,6 +1,8 @@
 package main
 
 import (
+	// база часовых поясов встроена в бинарник, чтобы не зависеть от tzdata в образе
+	_ "time/tzdata"
 	"todo/app"
 	"todo/config"
 )
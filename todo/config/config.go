@@ -2,6 +2,7 @@ package config
 
 import (
 	"github.com/kelseyhightower/envconfig"
+	"time"
 	"todo/pkg/jaeger"
 	"todo/pkg/logging"
 	"todo/pkg/postgresql"
@@ -18,6 +19,34 @@ type Config struct {
 	RabbitConfig rabbitmq.RabbitConfig `envconfig:"RABBITMQ"`
 	TodoExchange string                `envconfig:"RABBITMQ_TODO_EXCHANGE" default:"todo.exchange"`
 	TodoQueue    string                `envconfig:"RABBITMQ_TODO_QUEUE" default:"todo.queue"`
+	ShareLinks   ShareLinks            `envconfig:"SHARE_LINKS"`
+	UserDeletion UserDeletion          `envconfig:"USER_DELETION"`
+	// UserEventsExchange и UserDeletionsQueue - откуда приходят события user_deleted сервиса users
+	UserEventsExchange string `envconfig:"RABBITMQ_USER_EVENTS_EXCHANGE" default:"users.events"`
+	UserDeletionsQueue string `envconfig:"RABBITMQ_USER_DELETIONS_QUEUE" default:"todo.user_deletions"`
+	// UserUpdatesQueue - откуда приходят события user_updated сервиса users
+	UserUpdatesQueue string `envconfig:"RABBITMQ_USER_UPDATES_QUEUE" default:"todo.user_updates"`
+	// UserDeletionRepliesExchange и UserDeletionRepliesQueue - куда отправляются ответы на user_deleted
+	UserDeletionRepliesExchange string `envconfig:"RABBITMQ_USER_DELETION_REPLIES_EXCHANGE" default:"todo.events"`
+	UserDeletionRepliesQueue    string `envconfig:"RABBITMQ_USER_DELETION_REPLIES_QUEUE" default:"users.user_deletion_replies"`
+	// UserExportsQueue - откуда приходят события user_export_requested сервиса users
+	UserExportsQueue string `envconfig:"RABBITMQ_USER_EXPORTS_QUEUE" default:"todo.user_exports"`
+	// UserExportRepliesExchange и UserExportRepliesQueue - куда отправляются задачи пользователя для выгрузки
+	UserExportRepliesExchange string `envconfig:"RABBITMQ_USER_EXPORT_REPLIES_EXCHANGE" default:"todo.events"`
+	UserExportRepliesQueue    string `envconfig:"RABBITMQ_USER_EXPORT_REPLIES_QUEUE" default:"users.user_export_replies"`
+}
+
+// UserDeletion - настройки обработки удаления пользователя
+type UserDeletion struct {
+	// Policy - что делать с задачами удаленного пользователя: reassign, delete или anonymize
+	Policy string `envconfig:"USER_DELETION_POLICY" required:"true" default:"reassign"`
+}
+
+// ShareLinks - настройки публичных ссылок на задачи
+type ShareLinks struct {
+	Secret     string        `envconfig:"SHARE_LINKS_SECRET" required:"true" default:"superSecretShareKey"`
+	DefaultTTL time.Duration `envconfig:"SHARE_LINKS_DEFAULT_TTL" required:"true" default:"72h"`
+	MaxTTL     time.Duration `envconfig:"SHARE_LINKS_MAX_TTL" required:"true" default:"720h"`
 }
 
 type Grpc struct {
@@ -34,6 +63,11 @@ type UsersClient struct {
 	AppHost     string `envconfig:"USERS_HOST" required:"true" default:"0.0.0.0"`
 	AppRestPort string `envconfig:"USERS_REST_PORT" required:"true" default:"3000"`
 	AppGrpcPort string `envconfig:"USERS_GRPC_PORT" required:"true" default:"50000"`
+	// CacheTTL - сколько пользователь, полученный из сервиса users, считается свежим. Изменения пользователя
+	// приходят событием user_updated и сбрасывают кеш раньше
+	CacheTTL time.Duration `envconfig:"USERS_CACHE_TTL" required:"true" default:"5m"`
+	// CacheStaleTTL - сколько еще после CacheTTL пользователь из кеша отдается, если сервис users недоступен
+	CacheStaleTTL time.Duration `envconfig:"USERS_CACHE_STALE_TTL" required:"true" default:"30m"`
 }
 
 func NewFromEnv() *Config {
@@ -0,0 +1,63 @@
+package grpc
+
+import (
+	"errors"
+	"strconv"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"todo/internal/app_errors"
+	"todo/internal/query"
+)
+
+// errorDomain - домен ошибок сервиса в деталях gRPC статуса
+const errorDomain = "todo"
+
+// errorCodes - ошибки приложения, которые передаются клиенту с собственным кодом
+var errorCodes = map[error]codes.Code{
+	app_errors.ErrTitleTooLong:           codes.InvalidArgument,
+	app_errors.ErrTodoNotFound:           codes.NotFound,
+	app_errors.ErrTodoForbidden:          codes.PermissionDenied,
+	app_errors.ErrSavedFilterNameEmpty:   codes.InvalidArgument,
+	app_errors.ErrSavedFilterNameTooLong: codes.InvalidArgument,
+	app_errors.ErrSavedFilterNameIsUsed:  codes.AlreadyExists,
+	app_errors.ErrSavedFilterNotFound:    codes.NotFound,
+	app_errors.ErrSavedFilterForbidden:   codes.PermissionDenied,
+	app_errors.ErrShareLinkNotFound:      codes.NotFound,
+	app_errors.ErrShareLinkTTLInvalid:    codes.InvalidArgument,
+	app_errors.ErrAssigneeNotFound:       codes.InvalidArgument,
+}
+
+// toGRPCError переводит ошибки, о которых должен узнать клиент, в gRPC статусы с деталями.
+// Остальные ошибки возвращаются как есть.
+func toGRPCError(err error) error {
+	var syntaxErr *query.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return withErrorInfo(codes.InvalidArgument, syntaxErr.Error(), &errdetails.ErrorInfo{
+			Reason: query.ErrorReasonInvalidQuery,
+			Domain: errorDomain,
+			Metadata: map[string]string{
+				"position": strconv.Itoa(syntaxErr.Position),
+				"message":  syntaxErr.Message,
+			},
+		})
+	}
+
+	for target, code := range errorCodes {
+		if errors.Is(err, target) {
+			return status.Error(code, target.Error())
+		}
+	}
+
+	return err
+}
+
+func withErrorInfo(code codes.Code, message string, info *errdetails.ErrorInfo) error {
+	st, detailsErr := status.New(code, message).WithDetails(info)
+	if detailsErr != nil {
+		return status.Error(code, message)
+	}
+
+	return st.Err()
+}
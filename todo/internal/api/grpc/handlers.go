@@ -26,9 +26,9 @@ func (s *server) CreateToDo(ctx context.Context, todo *todo.ShortTodoDTO) (*todo
 		return nil, err
 	}
 
-	response, err := s.todoService.CreateToDo(ctx, newTodo)
+	response, err := s.todoService.CreateToDo(ctx, newTodo, callerFromGRPC(todo.CurrentUserId, todo.CurrentUserPermissions))
 	if err != nil {
-		return nil, err
+		return nil, toGRPCError(err)
 	}
 
 	return response.ToGRPCFull(), nil
@@ -45,9 +45,9 @@ func (s *server) UpdateToDo(ctx context.Context, todo *todo.ShortTodoDTO) (*todo
 		return nil, err
 	}
 
-	response, err := s.todoService.UpdateToDo(ctx, newTodo)
+	response, err := s.todoService.UpdateToDo(ctx, newTodo, callerFromGRPC(todo.CurrentUserId, todo.CurrentUserPermissions))
 	if err != nil {
-		return nil, err
+		return nil, toGRPCError(err)
 	}
 
 	return response.ToGRPCFull(), nil
@@ -64,9 +64,9 @@ func (s *server) GetTodoById(ctx context.Context, todoId *todo.TodoID) (*todo.Fu
 		return nil, err
 	}
 
-	response, err := s.todoService.GetToDo(ctx, id)
+	response, err := s.todoService.GetToDo(ctx, id, callerFromGRPC(todoId.CurrentUserId, todoId.CurrentUserPermissions))
 	if err != nil {
-		return nil, err
+		return nil, toGRPCError(err)
 	}
 
 	return response.ToGRPCFull(), nil
@@ -81,7 +81,7 @@ func (s *server) GetToDos(ctx context.Context, todosRequest *todo.GetTodosReques
 	request := models.NewEmptyGetTodosDTO().FromGRPCRequest(todosRequest)
 	response, err := s.todoService.GetToDos(ctx, request)
 	if err != nil {
-		return nil, err
+		return nil, toGRPCError(err)
 	}
 
 	return models.SliceToGRPCResponse(response), nil
@@ -98,10 +98,18 @@ func (s *server) DeleteTodo(ctx context.Context, todoId *todo.TodoID) (*emptypb.
 		return nil, err
 	}
 
-	err = s.todoService.DeleteToDo(ctx, id)
+	err = s.todoService.DeleteToDo(ctx, id, callerFromGRPC(todoId.CurrentUserId, todoId.CurrentUserPermissions))
 	if err != nil {
-		return nil, err
+		return nil, toGRPCError(err)
 	}
 
 	return &emptypb.Empty{}, nil
 }
+
+// callerFromGRPC собирает пользователя, от имени которого gateway выполняет операцию
+func callerFromGRPC(userID int32, permissions []string) models.Caller {
+	return models.Caller{
+		UserID:      int(userID),
+		Permissions: permissions,
+	}
+}
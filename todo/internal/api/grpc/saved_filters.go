@@ -0,0 +1,100 @@
+package grpc
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"todo/internal/models"
+	"todo/pkg/ctxutil"
+	todo "todo/pkg/grpc_stubs/todos"
+)
+
+func (s *server) CreateSavedFilter(ctx context.Context, filter *todo.SavedFilterDTO) (*todo.SavedFilterDTO, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.CreateSavedFilter")
+	defer span.Finish()
+
+	newFilter := models.NewEmptySavedFilterDTO().FromGRPCWithNewId(filter)
+
+	response, err := s.todoService.CreateSavedFilter(ctx, newFilter)
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	return response.ToGRPC(), nil
+}
+
+func (s *server) UpdateSavedFilter(ctx context.Context, filter *todo.SavedFilterDTO) (*todo.SavedFilterDTO, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.UpdateSavedFilter")
+	defer span.Finish()
+
+	newFilter, err := models.NewEmptySavedFilterDTO().FromGRPC(filter)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	response, err := s.todoService.UpdateSavedFilter(ctx, newFilter)
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	return response.ToGRPC(), nil
+}
+
+func (s *server) DeleteSavedFilter(ctx context.Context, request *todo.SavedFilterRequest) (*emptypb.Empty, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.DeleteSavedFilter")
+	defer span.Finish()
+
+	id, err := uuid.Parse(request.Id)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	err = s.todoService.DeleteSavedFilter(ctx, id, int(request.CurrentUserId))
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *server) GetSavedFilters(ctx context.Context, request *todo.GetSavedFiltersRequest) (*todo.GetSavedFiltersResponse, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.GetSavedFilters")
+	defer span.Finish()
+
+	response, err := s.todoService.GetSavedFilters(ctx, callerFromGRPC(request.CurrentUserId, request.CurrentUserPermissions), request.Timezone)
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	return models.SavedFiltersToGRPCResponse(response), nil
+}
+
+func (s *server) RunSavedFilter(ctx context.Context, request *todo.SavedFilterRequest) (*todo.GetTodosResponse, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.RunSavedFilter")
+	defer span.Finish()
+
+	id, err := uuid.Parse(request.Id)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	response, err := s.todoService.RunSavedFilter(ctx, id, callerFromGRPC(request.CurrentUserId, request.CurrentUserPermissions), request.Timezone)
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	return models.SliceToGRPCResponse(response), nil
+}
@@ -0,0 +1,86 @@
+package grpc
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"time"
+	"todo/internal/models"
+	"todo/pkg/ctxutil"
+	todo "todo/pkg/grpc_stubs/todos"
+)
+
+func (s *server) CreateShareLink(ctx context.Context, request *todo.CreateShareLinkRequest) (*todo.ShareLinkDTO, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.CreateShareLink")
+	defer span.Finish()
+
+	todoID, err := uuid.Parse(request.TodoId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	expiresIn := time.Duration(request.ExpiresInSeconds) * time.Second
+	response, err := s.todoService.CreateShareLink(ctx, todoID, int(request.CurrentUserId), expiresIn)
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	return response.ToGRPC(), nil
+}
+
+func (s *server) GetShareLinks(ctx context.Context, request *todo.ShareLinksRequest) (*todo.GetShareLinksResponse, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.GetShareLinks")
+	defer span.Finish()
+
+	todoID, err := uuid.Parse(request.TodoId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	response, err := s.todoService.GetShareLinks(ctx, todoID, int(request.CurrentUserId))
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	return models.ShareLinksToGRPCResponse(response), nil
+}
+
+func (s *server) RevokeShareLink(ctx context.Context, request *todo.ShareLinkRequest) (*emptypb.Empty, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.RevokeShareLink")
+	defer span.Finish()
+
+	linkID, err := uuid.Parse(request.Id)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	err = s.todoService.RevokeShareLink(ctx, linkID, int(request.CurrentUserId))
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *server) GetSharedTodo(ctx context.Context, request *todo.SharedTodoRequest) (*todo.SharedTodoDTO, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.GetSharedTodo")
+	defer span.Finish()
+
+	response, err := s.todoService.GetSharedTodo(ctx, request.Token)
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	return response.ToGRPC(), nil
+}
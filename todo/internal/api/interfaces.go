@@ -3,13 +3,42 @@ package api
 import (
 	"context"
 	"github.com/google/uuid"
+	"time"
 	"todo/internal/models"
 )
 
 type TodoService interface {
-	CreateToDo(ctx context.Context, newTodo *models.TodoDTO) (*models.TodoDTO, error)
-	UpdateToDo(ctx context.Context, newTodo *models.TodoDTO) (*models.TodoDTO, error)
+	CreateToDo(ctx context.Context, newTodo *models.TodoDTO, caller models.Caller) (*models.TodoDTO, error)
+	UpdateToDo(ctx context.Context, newTodo *models.TodoDTO, caller models.Caller) (*models.TodoDTO, error)
 	GetToDos(ctx context.Context, todos *models.GetTodosDTO) ([]models.TodoDTO, error)
-	GetToDo(ctx context.Context, todoID uuid.UUID) (*models.TodoDTO, error)
-	DeleteToDo(ctx context.Context, todoID uuid.UUID) error
+	GetToDo(ctx context.Context, todoID uuid.UUID, caller models.Caller) (*models.TodoDTO, error)
+	DeleteToDo(ctx context.Context, todoID uuid.UUID, caller models.Caller) error
+
+	CreateSavedFilter(ctx context.Context, newFilter *models.SavedFilterDTO) (*models.SavedFilterDTO, error)
+	UpdateSavedFilter(ctx context.Context, newFilter *models.SavedFilterDTO) (*models.SavedFilterDTO, error)
+	DeleteSavedFilter(ctx context.Context, filterID uuid.UUID, currentUserID int) error
+	GetSavedFilters(ctx context.Context, caller models.Caller, timezone string) ([]models.SavedFilterDTO, error)
+	RunSavedFilter(ctx context.Context, filterID uuid.UUID, caller models.Caller, timezone string) ([]models.TodoDTO, error)
+
+	CreateShareLink(ctx context.Context, todoID uuid.UUID, currentUserID int, expiresIn time.Duration) (*models.ShareLinkDTO, error)
+	GetShareLinks(ctx context.Context, todoID uuid.UUID, currentUserID int) ([]models.ShareLinkDTO, error)
+	RevokeShareLink(ctx context.Context, linkID uuid.UUID, currentUserID int) error
+	GetSharedTodo(ctx context.Context, token string) (*models.SharedTodoDTO, error)
+}
+
+// UserDeletionService - обработка событий об удалении пользователей
+type UserDeletionService interface {
+	HandleUserDeleted(ctx context.Context, event *models.UserDeletedEvent) error
+	HandleUserDeletionCancelled(ctx context.Context, event *models.UserDeletedEvent) error
+	HandleUserDeletionCompleted(ctx context.Context, event *models.UserDeletedEvent) error
+}
+
+// UserUpdatesService - обработка событий об изменении пользователей
+type UserUpdatesService interface {
+	HandleUserUpdated(ctx context.Context, event *models.UserUpdatedEvent) error
+}
+
+// UserExportService - обработка запросов на выгрузку данных пользователей
+type UserExportService interface {
+	HandleUserExportRequested(ctx context.Context, event *models.UserExportRequestedEvent) error
 }
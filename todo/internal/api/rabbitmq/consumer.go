@@ -0,0 +1,48 @@
+package rabbitmq
+
+import (
+	"context"
+	"fmt"
+	"github.com/rs/zerolog"
+	"todo/config"
+	"todo/internal/api"
+	rabbitConsumer "todo/pkg/rabbitmq/consumer"
+)
+
+// ConsumeRabbitMessages принимает события сервиса users об удалении и изменении пользователей и запросы на выгрузку их данных
+func ConsumeRabbitMessages(
+	ctx context.Context,
+	cfg *config.Config,
+	logger *zerolog.Logger,
+	deletionService api.UserDeletionService,
+	updatesService api.UserUpdatesService,
+	exportService api.UserExportService,
+) error {
+	rabbitMQ, err := rabbitConsumer.New(&cfg.RabbitConfig, logger)
+	if err != nil {
+		return fmt.Errorf("[ConsumeRabbitMessages] connection to rabbitmq %w", err)
+	}
+
+	rabbitMQ.SetHandler(
+		cfg.UserDeletionsQueue,
+		cfg.UserEventsExchange,
+		NewUserDeletionsHandler(logger, deletionService),
+	)
+	rabbitMQ.SetHandler(
+		cfg.UserUpdatesQueue,
+		cfg.UserEventsExchange,
+		NewUserUpdatesHandler(logger, updatesService),
+	)
+	rabbitMQ.SetHandler(
+		cfg.UserExportsQueue,
+		cfg.UserEventsExchange,
+		NewUserExportsHandler(logger, exportService),
+	)
+
+	rabbitMQ.Run()
+
+	<-ctx.Done()
+	logger.Info().Msg("[ConsumeRabbitMessages] shutting down message consumption")
+
+	return ctx.Err()
+}
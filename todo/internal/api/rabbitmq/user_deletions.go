@@ -0,0 +1,62 @@
+package rabbitmq
+
+import (
+	"context"
+	"encoding/json"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/rs/zerolog"
+	"todo/internal/api"
+	"todo/internal/models"
+	"todo/pkg/ctxutil"
+)
+
+type UserDeletionsHandler struct {
+	logger          *zerolog.Logger
+	deletionService api.UserDeletionService
+}
+
+func NewUserDeletionsHandler(
+	logger *zerolog.Logger,
+	deletionService api.UserDeletionService,
+) *UserDeletionsHandler {
+	return &UserDeletionsHandler{
+		logger:          logger,
+		deletionService: deletionService,
+	}
+}
+
+func (h *UserDeletionsHandler) Handle(d amqp.Delivery) {
+	requestID, _ := d.Headers["requestId"].(string)
+	ctx := ctxutil.SetRequestIDToContext(context.Background(), requestID)
+
+	var event models.UserDeletedEvent
+	err := json.Unmarshal(d.Body, &event)
+	handle, known := h.eventHandlers()[event.EventType]
+	if err != nil || !known {
+		// сообщение не станет корректным при повторной доставке
+		h.logger.Error().
+			Str("requestId", requestID).
+			Msgf("[UserDeletionsHandler] unexpected message %q: %v", d.Body, err)
+		d.Ack(false)
+		return
+	}
+
+	err = handle(ctx, &event)
+	if err != nil {
+		h.logger.Error().
+			Str("requestId", requestID).
+			Msgf("[UserDeletionsHandler] handling deletion %s of user %d: %s", event.DeletionID, event.UserID, err)
+		return
+	}
+
+	d.Ack(false)
+}
+
+// eventHandlers - обработчики событий об удалении по их типу
+func (h *UserDeletionsHandler) eventHandlers() map[string]func(ctx context.Context, event *models.UserDeletedEvent) error {
+	return map[string]func(ctx context.Context, event *models.UserDeletedEvent) error{
+		models.UserEventTypeUserDeleted:           h.deletionService.HandleUserDeleted,
+		models.UserEventTypeUserDeletionCancelled: h.deletionService.HandleUserDeletionCancelled,
+		models.UserEventTypeUserDeletionCompleted: h.deletionService.HandleUserDeletionCompleted,
+	}
+}
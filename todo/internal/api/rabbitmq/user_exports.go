@@ -0,0 +1,52 @@
+package rabbitmq
+
+import (
+	"context"
+	"encoding/json"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/rs/zerolog"
+	"todo/internal/api"
+	"todo/internal/models"
+	"todo/pkg/ctxutil"
+)
+
+type UserExportsHandler struct {
+	logger        *zerolog.Logger
+	exportService api.UserExportService
+}
+
+func NewUserExportsHandler(
+	logger *zerolog.Logger,
+	exportService api.UserExportService,
+) *UserExportsHandler {
+	return &UserExportsHandler{
+		logger:        logger,
+		exportService: exportService,
+	}
+}
+
+func (h *UserExportsHandler) Handle(d amqp.Delivery) {
+	requestID, _ := d.Headers["requestId"].(string)
+	ctx := ctxutil.SetRequestIDToContext(context.Background(), requestID)
+
+	var event models.UserExportRequestedEvent
+	err := json.Unmarshal(d.Body, &event)
+	if err != nil || event.EventType != models.UserEventTypeUserExportRequested {
+		// сообщение не станет корректным при повторной доставке
+		h.logger.Error().
+			Str("requestId", requestID).
+			Msgf("[UserExportsHandler] unexpected message %q: %v", d.Body, err)
+		d.Ack(false)
+		return
+	}
+
+	err = h.exportService.HandleUserExportRequested(ctx, &event)
+	if err != nil {
+		h.logger.Error().
+			Str("requestId", requestID).
+			Msgf("[UserExportsHandler] handling export %s of user %d: %s", event.ExportID, event.UserID, err)
+		return
+	}
+
+	d.Ack(false)
+}
@@ -0,0 +1,52 @@
+package rabbitmq
+
+import (
+	"context"
+	"encoding/json"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/rs/zerolog"
+	"todo/internal/api"
+	"todo/internal/models"
+	"todo/pkg/ctxutil"
+)
+
+type UserUpdatesHandler struct {
+	logger         *zerolog.Logger
+	updatesService api.UserUpdatesService
+}
+
+func NewUserUpdatesHandler(
+	logger *zerolog.Logger,
+	updatesService api.UserUpdatesService,
+) *UserUpdatesHandler {
+	return &UserUpdatesHandler{
+		logger:         logger,
+		updatesService: updatesService,
+	}
+}
+
+func (h *UserUpdatesHandler) Handle(d amqp.Delivery) {
+	requestID, _ := d.Headers["requestId"].(string)
+	ctx := ctxutil.SetRequestIDToContext(context.Background(), requestID)
+
+	var event models.UserUpdatedEvent
+	err := json.Unmarshal(d.Body, &event)
+	if err != nil || event.EventType != models.UserEventTypeUserUpdated {
+		// сообщение не станет корректным при повторной доставке
+		h.logger.Error().
+			Str("requestId", requestID).
+			Msgf("[UserUpdatesHandler] unexpected message %q: %v", d.Body, err)
+		d.Ack(false)
+		return
+	}
+
+	err = h.updatesService.HandleUserUpdated(ctx, &event)
+	if err != nil {
+		h.logger.Error().
+			Str("requestId", requestID).
+			Msgf("[UserUpdatesHandler] handling update of user %d: %s", event.UserID, err)
+		return
+	}
+
+	d.Ack(false)
+}
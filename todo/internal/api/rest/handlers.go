@@ -2,12 +2,14 @@ package rest
 
 import (
 	"encoding/json"
+	"errors"
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"github.com/rs/zerolog"
 	"net/http"
 	"todo/internal/api"
 	"todo/internal/models"
+	"todo/internal/query"
 )
 
 type TodoHandler struct {
@@ -35,7 +37,7 @@ func (h *TodoHandler) CreateToDo(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response, err := h.todoService.CreateToDo(ctx, newTodo)
+	response, err := h.todoService.CreateToDo(ctx, newTodo, models.InternalCaller)
 	if err != nil {
 		h.logger.Error().Msgf("[CreateToDo] creating:%s", err)
 		h.ErrorInternalError(w, "Can't create Todo")
@@ -54,7 +56,7 @@ func (h *TodoHandler) GetToDo(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response, err := h.todoService.GetToDo(ctx, todoId)
+	response, err := h.todoService.GetToDo(ctx, todoId, models.InternalCaller)
 	if err != nil {
 		h.logger.Error().Msgf("[GetToDo] getting:%s", err)
 		h.ErrorInternalError(w, "Can't get Todo")
@@ -74,8 +76,16 @@ func (h *TodoHandler) GetToDos(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	newTodos.CurrentUserPermissions = models.InternalCaller.Permissions
+
 	response, err := h.todoService.GetToDos(ctx, newTodos)
 	if err != nil {
+		var syntaxErr *query.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			h.ErrorBadRequest(w, syntaxErr.Error())
+			return
+		}
+
 		h.logger.Error().Msgf("[GetToDos] getting:%s", err)
 		h.ErrorInternalError(w, "Can't get Todos")
 		return
@@ -94,7 +104,7 @@ func (h *TodoHandler) UpdateToDo(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response, err := h.todoService.UpdateToDo(ctx, newTodo)
+	response, err := h.todoService.UpdateToDo(ctx, newTodo, models.InternalCaller)
 	if err != nil {
 		h.logger.Error().Msgf("[UpdateToDo] updating:%s", err)
 		h.ErrorInternalError(w, "Can't update Todo")
@@ -113,7 +123,7 @@ func (h *TodoHandler) DeleteToDo(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.todoService.DeleteToDo(ctx, todoId)
+	err = h.todoService.DeleteToDo(ctx, todoId, models.InternalCaller)
 	if err != nil {
 		h.logger.Error().Msgf("[DeleteToDo] deleting:%s", err)
 		h.ErrorInternalError(w, "Can't delete Todo")
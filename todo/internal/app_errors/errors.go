@@ -0,0 +1,21 @@
+package app_errors
+
+import "errors"
+
+var (
+	ErrTitleTooLong  = errors.New("todo title is too long")
+	ErrTodoNotFound  = errors.New("todo not found")
+	ErrTodoForbidden = errors.New("todo belongs to another user")
+
+	ErrSavedFilterNotFound    = errors.New("saved filter not found")
+	ErrSavedFilterForbidden   = errors.New("saved filter belongs to another user")
+	ErrSavedFilterNameEmpty   = errors.New("saved filter name is empty")
+	ErrSavedFilterNameTooLong = errors.New("saved filter name is too long")
+	ErrSavedFilterNameIsUsed  = errors.New("saved filter with this name already exists")
+
+	ErrShareLinkNotFound   = errors.New("share link not found")
+	ErrShareLinkTTLInvalid = errors.New("share link lifetime is out of range")
+
+	ErrAssigneeNotFound          = errors.New("assignee not found")
+	ErrUnknownUserDeletionPolicy = errors.New("unknown user deletion policy")
+)
@@ -0,0 +1,95 @@
+package users
+
+import (
+	"sync"
+	"time"
+	"todo/internal/models"
+)
+
+// userCache хранит пользователей, полученных из сервиса users. Пользователь считается свежим ttl,
+// после этого его перечитывают, но еще staleTTL он может отдаваться, если сервис users недоступен
+type userCache struct {
+	mu       sync.Mutex
+	ttl      time.Duration
+	staleTTL time.Duration
+	items    map[int]userCacheItem
+	// invalidated - когда пользователь последний раз убран из кеша. Ответ на запрос, начатый раньше,
+	// мог уже устареть, и такой пользователь в кеш не записывается
+	invalidated map[int]time.Time
+	lastSweep   time.Time
+}
+
+type userCacheItem struct {
+	user     models.UserDTO
+	cachedAt time.Time
+}
+
+func newUserCache(ttl, staleTTL time.Duration) *userCache {
+	return &userCache{
+		ttl:         ttl,
+		staleTTL:    staleTTL,
+		items:       make(map[int]userCacheItem),
+		invalidated: make(map[int]time.Time),
+	}
+}
+
+// get возвращает копию пользователя и признак того, что он еще свежий. Если пользователя нет
+// или он устарел дольше, чем на staleTTL, возвращает nil
+func (c *userCache) get(userID int, now time.Time) (*models.UserDTO, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	item, ok := c.items[userID]
+	if !ok {
+		return nil, false
+	}
+
+	age := now.Sub(item.cachedAt)
+	if age >= c.ttl+c.staleTTL {
+		return nil, false
+	}
+
+	user := item.user
+	return &user, age < c.ttl
+}
+
+// set запоминает пользователей, полученных запросом, который начался в now, и заодно удаляет тех,
+// кто устарел окончательно. Пользователи, убранные из кеша после начала запроса, не записываются
+func (c *userCache) set(now time.Time, users ...*models.UserDTO) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, user := range users {
+		if invalidatedAt, ok := c.invalidated[user.ID]; ok && !invalidatedAt.Before(now) {
+			continue
+		}
+		c.items[user.ID] = userCacheItem{user: *user, cachedAt: now}
+	}
+
+	// проходить по всему кешу на каждую запись незачем, достаточно раз в ttl
+	if now.Sub(c.lastSweep) < c.ttl {
+		return
+	}
+	c.lastSweep = now
+
+	for userID, item := range c.items {
+		if now.Sub(item.cachedAt) >= c.ttl+c.staleTTL {
+			delete(c.items, userID)
+		}
+	}
+	for userID, invalidatedAt := range c.invalidated {
+		if now.Sub(invalidatedAt) >= c.ttl+c.staleTTL {
+			delete(c.invalidated, userID)
+		}
+	}
+}
+
+// delete забывает пользователя, следующий запрос пойдет в сервис users. Ответы на запросы,
+// начатые до now, пользователя в кеш уже не вернут
+func (c *userCache) delete(userID int, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.items, userID)
+	c.invalidated[userID] = now
+}
@@ -0,0 +1,57 @@
+package users
+
+import (
+	"testing"
+	"time"
+	"todo/internal/models"
+)
+
+func TestUserCacheInvalidation(t *testing.T) {
+	start := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	oldUser := &models.UserDTO{ID: 1, Username: "alice", Email: "old@example.com"}
+	otherUser := &models.UserDTO{ID: 2, Username: "bob", Email: "bob@example.com"}
+
+	tests := []struct {
+		name          string
+		invalidatedAt time.Time
+		wantCached    bool
+	}{
+		{"invalidated while the request was in flight", start.Add(time.Millisecond), false},
+		{"invalidated at the moment the request started", start, false},
+		{"invalidated before the request started", start.Add(-time.Millisecond), true},
+		{"invalidated long ago", start.Add(-time.Hour), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := newUserCache(time.Minute, time.Minute)
+			cache.delete(oldUser.ID, tt.invalidatedAt)
+
+			// ответ на запрос, начатый в start, приходит уже после invalidatedAt
+			cache.set(start, oldUser, otherUser)
+
+			user, _ := cache.get(oldUser.ID, start.Add(time.Second))
+			if (user != nil) != tt.wantCached {
+				t.Errorf("get(%d) = %v, want cached %v", oldUser.ID, user, tt.wantCached)
+			}
+			if user, _ := cache.get(otherUser.ID, start.Add(time.Second)); user == nil {
+				t.Errorf("get(%d) = nil, other users must stay cached", otherUser.ID)
+			}
+		})
+	}
+
+	// следующий запрос после инвалидации снова заполняет кеш
+	cache := newUserCache(time.Minute, time.Minute)
+	cache.delete(oldUser.ID, start)
+	cache.set(start.Add(time.Second), &models.UserDTO{ID: 1, Username: "alice", Email: "new@example.com"})
+	user, fresh := cache.get(oldUser.ID, start.Add(2*time.Second))
+	if user == nil || !fresh || user.Email != "new@example.com" {
+		t.Errorf("get after refetch = %v (fresh %v), want new email", user, fresh)
+	}
+
+	// отметки об инвалидации забываются вместе с окончательно устаревшими пользователями
+	cache.set(start.Add(3*time.Minute), otherUser)
+	if _, ok := cache.invalidated[oldUser.ID]; ok {
+		t.Errorf("invalidation mark for user %d is kept after ttl+staleTTL", oldUser.ID)
+	}
+}
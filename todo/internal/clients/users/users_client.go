@@ -3,8 +3,11 @@ package users
 import (
 	"context"
 	"fmt"
+	"github.com/opentracing/opentracing-go"
 	"github.com/rs/zerolog"
 	"google.golang.org/grpc"
+	"strings"
+	"time"
 	"todo/config"
 	"todo/internal/models"
 	"todo/pkg/grpc_stubs/users"
@@ -12,6 +15,7 @@ import (
 
 type UsersClient struct {
 	client users.UserServiceClient
+	cache  *userCache
 }
 
 func NewUsersClient(cfg *config.Config, logger *zerolog.Logger) (*UsersClient, error) {
@@ -30,16 +34,96 @@ func NewUsersClient(cfg *config.Config, logger *zerolog.Logger) (*UsersClient, e
 	c := users.NewUserServiceClient(conn)
 	return &UsersClient{
 		client: c,
+		cache:  newUserCache(cfg.UsersClient.CacheTTL, cfg.UsersClient.CacheStaleTTL),
 	}, nil
 }
 
-func (c *UsersClient) GetUserByID(ctx context.Context, userID int) (*models.UserDTO, error) {
-	user, err := c.client.GetUserByID(ctx, &users.UserID{
-		Id: int32(userID),
+// GetUsersByIDs ищет пользователей по ID, сначала в кеше, а тех, кого там нет, одним запросом в сервис users.
+// Пользователей, которых нет или которые удаляются, в ответе нет. Если сервис users не ответил,
+// а все недостающие пользователи есть в кеше, хотя и устарели, возвращаются они
+func (c *UsersClient) GetUsersByIDs(ctx context.Context, userIDs []int) (map[int]*models.UserDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.GetUsersByIDs")
+	defer span.Finish()
+
+	now := time.Now()
+	found := make(map[int]*models.UserDTO, len(userIDs))
+	stale := make(map[int]*models.UserDTO)
+	missing := make([]int32, 0, len(userIDs))
+	for _, userID := range userIDs {
+		if _, ok := found[userID]; ok {
+			continue
+		}
+		if _, ok := stale[userID]; ok {
+			continue
+		}
+
+		user, fresh := c.cache.get(userID, now)
+		if fresh {
+			found[userID] = user
+			continue
+		}
+		if user != nil {
+			stale[userID] = user
+		}
+		missing = append(missing, int32(userID))
+	}
+
+	if len(missing) == 0 {
+		return found, nil
+	}
+
+	response, err := c.client.GetUsersByIDs(ctx, &users.UserIDs{
+		Ids: missing,
+	})
+	if err != nil {
+		if len(stale) < len(missing) {
+			return nil, err
+		}
+		for userID, user := range stale {
+			found[userID] = user
+		}
+		return found, nil
+	}
+
+	loaded := make([]*models.UserDTO, len(response.Items))
+	for i, item := range response.Items {
+		loaded[i] = models.NewEmptyUserDTO().FromGRPC(item)
+		found[loaded[i].ID] = loaded[i]
+	}
+	c.cache.set(now, loaded...)
+
+	return found, nil
+}
+
+// InvalidateUser убирает пользователя из кеша, когда его данные изменились. Запрос в сервис users,
+// который уже идет, прежние данные в кеш не запишет
+func (c *UsersClient) InvalidateUser(userID int) {
+	c.cache.delete(userID, time.Now())
+}
+
+// GetUsersByUsernames ищет пользователей по именам одним запросом в сервис users и возвращает их вместе
+// с разрешениями ролей по имени в нижнем регистре. Пользователей, которых нет или которые удаляются, в ответе нет.
+// Кеш не используется: в нем нет разрешений
+func (c *UsersClient) GetUsersByUsernames(ctx context.Context, usernames []string) (map[string]*models.UserDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "client.GetUsersByUsernames")
+	defer span.Finish()
+
+	found := make(map[string]*models.UserDTO, len(usernames))
+	if len(usernames) == 0 {
+		return found, nil
+	}
+
+	response, err := c.client.GetUsersByUsernames(ctx, &users.Usernames{
+		Usernames: usernames,
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	return models.NewEmptyUserDTO().FromGRPC(user), nil
+	for _, item := range response.Items {
+		user := models.NewEmptyUserDTO().FromGRPC(item)
+		found[strings.ToLower(user.Username)] = user
+	}
+
+	return found, nil
 }
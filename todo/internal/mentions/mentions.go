@@ -0,0 +1,79 @@
+package mentions
+
+import (
+	"strings"
+	"unicode"
+)
+
+// MaxMentions - сколько разных упоминаний из одного текста разрешается, остальные игнорируются
+const MaxMentions = 20
+
+// Parse находит в Markdown тексте упоминания вида @username и возвращает имена без @ в порядке появления,
+// без повторов (сравнение без учета регистра). Упоминания внутри блоков кода и `inline code` не учитываются,
+// как и @ внутри слова - например, в адресе почты.
+func Parse(text string) []string {
+	var (
+		usernames []string
+		seen      = make(map[string]bool)
+		fenced    bool
+	)
+
+	for _, line := range strings.Split(text, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			fenced = !fenced
+			continue
+		}
+		if fenced {
+			continue
+		}
+
+		for _, username := range parseLine(line) {
+			key := strings.ToLower(username)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			usernames = append(usernames, username)
+			if len(usernames) == MaxMentions {
+				return usernames
+			}
+		}
+	}
+
+	return usernames
+}
+
+func parseLine(line string) []string {
+	var (
+		usernames []string
+		runes     = []rune(line)
+		inCode    bool
+	)
+
+	for i := 0; i < len(runes); i++ {
+		switch {
+		case runes[i] == '`':
+			inCode = !inCode
+
+		case runes[i] == '@' && !inCode && (i == 0 || !isUsernameRune(runes[i-1])):
+			end := i + 1
+			for end < len(runes) && isUsernameRune(runes[end]) {
+				end++
+			}
+
+			// точка или дефис в конце скорее относятся к предложению, чем к имени
+			username := strings.TrimRight(string(runes[i+1:end]), ".-")
+			if username != "" {
+				usernames = append(usernames, username)
+			}
+			i = end - 1
+		}
+	}
+
+	return usernames
+}
+
+func isUsernameRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.' || r == '-'
+}
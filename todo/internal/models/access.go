@@ -0,0 +1,59 @@
+package models
+
+// Разрешения ролей пользователей, которые касаются задач. Роли и разрешения хранит сервис users,
+// gateway передает их вместе с ID пользователя.
+const (
+	// PermissionTodosRead - видеть любые задачи
+	PermissionTodosRead = "todos:read"
+	// PermissionTodosManage - видеть, изменять и удалять любые задачи
+	PermissionTodosManage = "todos:manage"
+)
+
+// Caller - пользователь, выполняющий операцию с задачами, и разрешения его роли
+type Caller struct {
+	UserID      int
+	Permissions []string
+}
+
+// InternalCaller - внутренний REST API не публикуется наружу и работает с задачами без ограничений
+var InternalCaller = Caller{Permissions: []string{PermissionTodosRead, PermissionTodosManage}}
+
+func (c Caller) HasPermission(permission string) bool {
+	for _, p := range c.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ReadsAll - пользователь видит любые задачи, а не только свои
+func (c Caller) ReadsAll() bool {
+	return c.HasPermission(PermissionTodosRead) || c.HasPermission(PermissionTodosManage)
+}
+
+// isParticipant - пользователь создал задачу или исполняет ее
+func (c Caller) isParticipant(todo *TodoDAO) bool {
+	return c.UserID != 0 && (todo.CreatedBy == c.UserID || todo.Assignee == c.UserID)
+}
+
+// CanRead - задачу видят ее создатель, исполнитель и роли с правом читать любые задачи
+func (c Caller) CanRead(todo *TodoDAO) bool {
+	return c.isParticipant(todo) || c.ReadsAll()
+}
+
+// CanEdit - изменять задачу могут ее создатель и исполнитель
+func (c Caller) CanEdit(todo *TodoDAO) bool {
+	return c.isParticipant(todo) || c.HasPermission(PermissionTodosManage)
+}
+
+// CanReassign - передать задачу другому исполнителю может только ее создатель
+func (c Caller) CanReassign(todo *TodoDAO) bool {
+	return (c.UserID != 0 && todo.CreatedBy == c.UserID) || c.HasPermission(PermissionTodosManage)
+}
+
+// CanDelete - удалить задачу может только ее создатель
+func (c Caller) CanDelete(todo *TodoDAO) bool {
+	return (c.UserID != 0 && todo.CreatedBy == c.UserID) || c.HasPermission(PermissionTodosManage)
+}
@@ -17,18 +17,23 @@ func (d *TodoDTO) ToGRPCShort() *todo.ShortTodoDTO {
 		Id:          d.ID.String(),
 		CreatedBy:   int32(d.CreatedBy),
 		Assignee:    int32(d.Assignee),
+		Title:       d.Title,
 		Description: d.Description,
 	}
 }
 
 func (d *TodoDTO) ToGRPCFull() *todo.FullTodoDTO {
 	return &todo.FullTodoDTO{
-		Id:          d.ID.String(),
-		CreatedBy:   int32(d.CreatedBy),
-		Assignee:    int32(d.Assignee),
-		Description: d.Description,
-		CreatedAt:   ts.New(d.CreatedAt),
-		UpdatedAt:   ts.New(d.UpdatedAt),
+		Id:              d.ID.String(),
+		CreatedBy:       int32(d.CreatedBy),
+		Assignee:        int32(d.Assignee),
+		Title:           d.Title,
+		Description:     d.Description,
+		DescriptionHtml: d.DescriptionHTML,
+		CreatedAt:       ts.New(d.CreatedAt),
+		UpdatedAt:       ts.New(d.UpdatedAt),
+		Mentions:        d.Mentions,
+		UnknownMentions: d.UnknownMentions,
 	}
 }
 
@@ -39,6 +44,7 @@ func (d *TodoDTO) FromGRPCShortWithNewId(dto *todo.ShortTodoDTO) (*TodoDTO, erro
 		ID:          newId,
 		CreatedBy:   int(dto.CreatedBy),
 		Assignee:    int(dto.Assignee),
+		Title:       dto.Title,
 		Description: dto.Description,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
@@ -55,6 +61,7 @@ func (d *TodoDTO) FromGRPCShort(dto *todo.ShortTodoDTO) (*TodoDTO, error) {
 		ID:          id,
 		CreatedBy:   int(dto.CreatedBy),
 		Assignee:    int(dto.Assignee),
+		Title:       dto.Title,
 		Description: dto.Description,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
@@ -68,12 +75,16 @@ func (d *TodoDTO) FromGRPCFull(dto *todo.FullTodoDTO) (*TodoDTO, error) {
 	}
 
 	return &TodoDTO{
-		ID:          id,
-		CreatedBy:   int(dto.CreatedBy),
-		Assignee:    int(dto.Assignee),
-		Description: dto.Description,
-		CreatedAt:   dto.CreatedAt.AsTime(),
-		UpdatedAt:   dto.UpdatedAt.AsTime(),
+		ID:              id,
+		CreatedBy:       int(dto.CreatedBy),
+		Assignee:        int(dto.Assignee),
+		Title:           dto.Title,
+		Description:     dto.Description,
+		DescriptionHTML: dto.DescriptionHtml,
+		CreatedAt:       dto.CreatedAt.AsTime(),
+		UpdatedAt:       dto.UpdatedAt.AsTime(),
+		Mentions:        dto.Mentions,
+		UnknownMentions: dto.UnknownMentions,
 	}, nil
 }
 
@@ -82,6 +93,7 @@ func (d *TodoDTO) ToDAO() *TodoDAO {
 		ID:          d.ID,
 		CreatedBy:   d.CreatedBy,
 		Assignee:    d.Assignee,
+		Title:       d.Title,
 		Description: d.Description,
 		CreatedAt:   d.CreatedAt,
 		UpdatedAt:   d.UpdatedAt,
@@ -93,6 +105,7 @@ func (d *TodoDAO) ToDTO() *TodoDTO {
 		ID:          d.ID,
 		CreatedBy:   d.CreatedBy,
 		Assignee:    d.Assignee,
+		Title:       d.Title,
 		Description: d.Description,
 		CreatedAt:   d.CreatedAt,
 		UpdatedAt:   d.UpdatedAt,
@@ -114,19 +127,29 @@ func NewEmptyGetTodosDTO() *GetTodosDTO {
 
 func (d *GetTodosDTO) ToGRPCRequest() *todo.GetTodosRequest {
 	return &todo.GetTodosRequest{
-		CreatedBy: int32(d.CreatedBy),
-		Assignee:  int32(d.Assignee),
-		DateFrom:  ts.New(d.DateFrom),
-		DateTo:    ts.New(d.DateTo),
+		CreatedBy:     int32(d.CreatedBy),
+		Assignee:      int32(d.Assignee),
+		DateFrom:      ts.New(d.DateFrom),
+		DateTo:        ts.New(d.DateTo),
+		Query:         d.Query,
+		CurrentUserId: int32(d.CurrentUserID),
+		Timezone:      d.Timezone,
+
+		CurrentUserPermissions: d.CurrentUserPermissions,
 	}
 }
 
 func (d *GetTodosDTO) FromGRPCRequest(req *todo.GetTodosRequest) *GetTodosDTO {
 	return &GetTodosDTO{
-		CreatedBy: int(req.CreatedBy),
-		Assignee:  int(req.Assignee),
-		DateFrom:  req.DateFrom.AsTime(),
-		DateTo:    req.DateTo.AsTime(),
+		CreatedBy:     int(req.CreatedBy),
+		Assignee:      int(req.Assignee),
+		DateFrom:      req.DateFrom.AsTime(),
+		DateTo:        req.DateTo.AsTime(),
+		Query:         req.Query,
+		CurrentUserID: int(req.CurrentUserId),
+		Timezone:      req.Timezone,
+
+		CurrentUserPermissions: req.CurrentUserPermissions,
 	}
 }
 
@@ -140,12 +163,16 @@ func SliceFromGRPCResponse(response *todo.GetTodosResponse) ([]TodoDTO, error) {
 		}
 
 		var newDto = TodoDTO{
-			ID:          id,
-			CreatedBy:   int(response.Items[i].CreatedBy),
-			Assignee:    int(response.Items[i].Assignee),
-			Description: response.Items[i].Description,
-			CreatedAt:   response.Items[i].CreatedAt.AsTime(),
-			UpdatedAt:   response.Items[i].UpdatedAt.AsTime(),
+			ID:              id,
+			CreatedBy:       int(response.Items[i].CreatedBy),
+			Assignee:        int(response.Items[i].Assignee),
+			Title:           response.Items[i].Title,
+			Description:     response.Items[i].Description,
+			DescriptionHTML: response.Items[i].DescriptionHtml,
+			CreatedAt:       response.Items[i].CreatedAt.AsTime(),
+			UpdatedAt:       response.Items[i].UpdatedAt.AsTime(),
+			Mentions:        response.Items[i].Mentions,
+			UnknownMentions: response.Items[i].UnknownMentions,
 		}
 		dtoSlice[i] = newDto
 	}
@@ -159,16 +186,8 @@ func SliceToGRPCResponse(slice []TodoDTO) *todo.GetTodosResponse {
 	}
 
 	for i, value := range slice {
-		var newDto = todo.FullTodoDTO{
-			Id:          value.ID.String(),
-			CreatedBy:   int32(value.CreatedBy),
-			Assignee:    int32(value.Assignee),
-			Description: value.Description,
-			CreatedAt:   ts.New(value.CreatedAt),
-			UpdatedAt:   ts.New(value.UpdatedAt),
-		}
-		dtoSlice.Items[i] = &newDto
+		dtoSlice.Items[i] = value.ToGRPCFull()
 	}
 
-	return &todo.GetTodosResponse{}
+	return &dtoSlice
 }
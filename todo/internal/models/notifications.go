@@ -1,14 +1,25 @@
 package models
 
 const (
-	TodoEventTypeCreateTodo = "create_todo"
-	TodoEventTypeUpdateTodo = "update_todo"
-	TodoEventTypeDeleteTodo = "delete_todo"
+	TodoEventTypeCreateTodo  = "create_todo"
+	TodoEventTypeUpdateTodo  = "update_todo"
+	TodoEventTypeDeleteTodo  = "delete_todo"
+	TodoEventTypeMentionTodo = "mention_todo"
 )
 
+// TodoMailRecipient - получатель письма. По UserID notifications проверяет его настройки уведомлений
+type TodoMailRecipient struct {
+	UserID int    `json:"user_id"`
+	Email  string `json:"email"`
+}
+
 type TodoMailItem struct {
-	TodoEventType string   `json:"todo_event_type"`
-	Receivers     []string `json:"receivers"`
-	AssigneeName  string   `json:"assignee_name"`
-	Description   string   `json:"description"`
+	TodoEventType   string              `json:"todo_event_type"`
+	Recipients      []TodoMailRecipient `json:"recipients"`
+	AssigneeName    string              `json:"assignee_name"`
+	Title           string              `json:"title"`
+	Description     string              `json:"description"`
+	DescriptionHTML string              `json:"description_html"`
+	// ContentHidden - получатели не могут читать задачу, поэтому в письме нет ее заголовка и описания
+	ContentHidden bool `json:"content_hidden,omitempty"`
 }
@@ -0,0 +1,141 @@
+package models
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	ts "google.golang.org/protobuf/types/known/timestamppb"
+	"time"
+	"todo/pkg/grpc_stubs/todos"
+)
+
+// SavedFilterNameMaxLength - максимальная длина названия сохраненного фильтра в символах
+const SavedFilterNameMaxLength = 255
+
+type SavedFilterDAO struct {
+	ID        uuid.UUID   `db:"id"`
+	OwnerID   int         `db:"owner_id"`
+	Name      string      `db:"name"`
+	Shared    bool        `db:"shared"`
+	Criteria  GetTodosDTO `db:"criteria"`
+	CreatedAt time.Time   `db:"created_at"`
+	UpdatedAt time.Time   `db:"updated_at"`
+}
+
+type SavedFilterDTO struct {
+	ID        uuid.UUID   `json:"id,omitempty" example:"c0e708fa-a7df-4d9f-a1b8-a3bfe63c433c"`
+	OwnerID   int         `json:"owner_id" example:"1"`
+	Name      string      `json:"name" example:"My backend tasks"`
+	Shared    bool        `json:"shared" example:"false"`
+	Criteria  GetTodosDTO `json:"criteria"`
+	Count     int         `json:"count" example:"3"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+
+	// CurrentUserID - пользователь, выполняющий операцию над фильтром
+	CurrentUserID int `json:"current_user_id,omitempty" example:"1"`
+	// Timezone - часовой пояс пользователя, выполняющего операцию, в нем считаются задачи фильтра
+	Timezone string `json:"-"`
+	// CurrentUserPermissions - разрешения роли пользователя, выполняющего операцию. В критериях фильтра
+	// они не хранятся, поэтому задачи считаются по правам того, кто фильтр запускает
+	CurrentUserPermissions []string `json:"-"`
+}
+
+// Caller возвращает пользователя, выполняющего операцию над фильтром
+func (d *SavedFilterDTO) Caller() Caller {
+	return Caller{
+		UserID:      d.CurrentUserID,
+		Permissions: d.CurrentUserPermissions,
+	}
+}
+
+func NewEmptySavedFilterDTO() *SavedFilterDTO {
+	return &SavedFilterDTO{}
+}
+
+func (d *SavedFilterDTO) ToGRPC() *todo.SavedFilterDTO {
+	return &todo.SavedFilterDTO{
+		Id:            d.ID.String(),
+		OwnerId:       int32(d.OwnerID),
+		Name:          d.Name,
+		Shared:        d.Shared,
+		Criteria:      d.Criteria.ToGRPCRequest(),
+		Count:         int32(d.Count),
+		CreatedAt:     ts.New(d.CreatedAt),
+		UpdatedAt:     ts.New(d.UpdatedAt),
+		CurrentUserId: int32(d.CurrentUserID),
+		Timezone:      d.Timezone,
+	}
+}
+
+func (d *SavedFilterDTO) FromGRPCWithNewId(dto *todo.SavedFilterDTO) *SavedFilterDTO {
+	filter := d.fromGRPC(dto)
+	filter.ID = uuid.New()
+
+	return filter
+}
+
+func (d *SavedFilterDTO) FromGRPC(dto *todo.SavedFilterDTO) (*SavedFilterDTO, error) {
+	id, err := uuid.Parse(dto.Id)
+	if err != nil {
+		return nil, fmt.Errorf("[FromGRPC] wrong uuid: %w", err)
+	}
+
+	filter := d.fromGRPC(dto)
+	filter.ID = id
+
+	return filter, nil
+}
+
+func (d *SavedFilterDTO) fromGRPC(dto *todo.SavedFilterDTO) *SavedFilterDTO {
+	filter := &SavedFilterDTO{
+		OwnerID:       int(dto.OwnerId),
+		Name:          dto.Name,
+		Shared:        dto.Shared,
+		CurrentUserID: int(dto.CurrentUserId),
+		Timezone:      dto.Timezone,
+
+		CurrentUserPermissions: dto.CurrentUserPermissions,
+	}
+
+	if dto.Criteria != nil {
+		filter.Criteria = *NewEmptyGetTodosDTO().FromGRPCRequest(dto.Criteria)
+	}
+
+	return filter
+}
+
+func (d *SavedFilterDTO) ToDAO() *SavedFilterDAO {
+	return &SavedFilterDAO{
+		ID:        d.ID,
+		OwnerID:   d.OwnerID,
+		Name:      d.Name,
+		Shared:    d.Shared,
+		Criteria:  d.Criteria,
+		CreatedAt: d.CreatedAt,
+		UpdatedAt: d.UpdatedAt,
+	}
+}
+
+func (d *SavedFilterDAO) ToDTO() *SavedFilterDTO {
+	return &SavedFilterDTO{
+		ID:        d.ID,
+		OwnerID:   d.OwnerID,
+		Name:      d.Name,
+		Shared:    d.Shared,
+		Criteria:  d.Criteria,
+		CreatedAt: d.CreatedAt,
+		UpdatedAt: d.UpdatedAt,
+	}
+}
+
+func SavedFiltersToGRPCResponse(slice []SavedFilterDTO) *todo.GetSavedFiltersResponse {
+	var response = todo.GetSavedFiltersResponse{
+		Items: make([]*todo.SavedFilterDTO, len(slice)),
+	}
+
+	for i, value := range slice {
+		response.Items[i] = value.ToGRPC()
+	}
+
+	return &response
+}
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	ts "google.golang.org/protobuf/types/known/timestamppb"
+	"time"
+	"todo/pkg/grpc_stubs/todos"
+)
+
+type ShareLinkDAO struct {
+	ID        uuid.UUID  `db:"id"`
+	TodoID    uuid.UUID  `db:"todo_id"`
+	CreatedBy int        `db:"created_by"`
+	ExpiresAt time.Time  `db:"expires_at"`
+	RevokedAt *time.Time `db:"revoked_at"`
+	CreatedAt time.Time  `db:"created_at"`
+}
+
+type ShareLinkDTO struct {
+	ID        uuid.UUID  `json:"id" example:"c0e708fa-a7df-4d9f-a1b8-a3bfe63c433c"`
+	TodoID    uuid.UUID  `json:"todo_id" example:"c0e708fa-a7df-4d9f-a1b8-a3bfe63c433c"`
+	CreatedBy int        `json:"created_by" example:"1"`
+	Token     string     `json:"token"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// SharedTodoDTO - задача в том виде, в котором ее видит получатель публичной ссылки
+type SharedTodoDTO struct {
+	Title           string    `json:"title" example:"todo title"`
+	DescriptionHTML string    `json:"description_html" example:"<p>todo <strong>description</strong></p>"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+	ExpiresAt       time.Time `json:"expires_at"`
+}
+
+func (d *ShareLinkDAO) ToDTO() *ShareLinkDTO {
+	return &ShareLinkDTO{
+		ID:        d.ID,
+		TodoID:    d.TodoID,
+		CreatedBy: d.CreatedBy,
+		ExpiresAt: d.ExpiresAt,
+		RevokedAt: d.RevokedAt,
+		CreatedAt: d.CreatedAt,
+	}
+}
+
+func (d *ShareLinkDTO) ToGRPC() *todo.ShareLinkDTO {
+	link := &todo.ShareLinkDTO{
+		Id:        d.ID.String(),
+		TodoId:    d.TodoID.String(),
+		CreatedBy: int32(d.CreatedBy),
+		Token:     d.Token,
+		ExpiresAt: ts.New(d.ExpiresAt),
+		CreatedAt: ts.New(d.CreatedAt),
+	}
+
+	if d.RevokedAt != nil {
+		link.RevokedAt = ts.New(*d.RevokedAt)
+	}
+
+	return link
+}
+
+func ShareLinksToGRPCResponse(slice []ShareLinkDTO) *todo.GetShareLinksResponse {
+	var response = todo.GetShareLinksResponse{
+		Items: make([]*todo.ShareLinkDTO, len(slice)),
+	}
+
+	for i, value := range slice {
+		response.Items[i] = value.ToGRPC()
+	}
+
+	return &response
+}
+
+func (d *SharedTodoDTO) ToGRPC() *todo.SharedTodoDTO {
+	return &todo.SharedTodoDTO{
+		Title:           d.Title,
+		DescriptionHtml: d.DescriptionHTML,
+		CreatedAt:       ts.New(d.CreatedAt),
+		UpdatedAt:       ts.New(d.UpdatedAt),
+		ExpiresAt:       ts.New(d.ExpiresAt),
+	}
+}
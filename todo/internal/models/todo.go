@@ -3,24 +3,43 @@ package models
 import (
 	"github.com/google/uuid"
 	"time"
+	"todo/internal/query"
 )
 
+// TodoTitleMaxLength - максимальная длина заголовка задачи в символах
+const TodoTitleMaxLength = 255
+
 type TodoDAO struct {
 	ID          uuid.UUID `db:"id"`
 	CreatedBy   int       `db:"created_by"`
 	Assignee    int       `db:"assignee"`
+	Title       string    `db:"title"`
 	Description string    `db:"description"`
 	CreatedAt   time.Time `db:"created_at"`
 	UpdatedAt   time.Time `db:"updated_at"`
 }
 
 type TodoDTO struct {
-	ID          uuid.UUID `json:"id,omitempty" example:"c0e708fa-a7df-4d9f-a1b8-a3bfe63c433c"`
-	CreatedBy   int       `json:"created_by" example:"1"`
-	Assignee    int       `json:"assignee" example:"2"`
-	Description string    `json:"description" example:"todo description"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID              uuid.UUID `json:"id,omitempty" example:"c0e708fa-a7df-4d9f-a1b8-a3bfe63c433c"`
+	CreatedBy       int       `json:"created_by" example:"1"`
+	Assignee        int       `json:"assignee" example:"2"`
+	Title           string    `json:"title" example:"todo title"`
+	Description     string    `json:"description" example:"todo **description**"`
+	DescriptionHTML string    `json:"description_html" example:"<p>todo <strong>description</strong></p>"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+
+	// Mentions - пользователи, упомянутые в описании через @username
+	Mentions []string `json:"mentions" example:"alice"`
+	// UnknownMentions - упоминания из описания, для которых не нашлось пользователя
+	UnknownMentions []string `json:"unknown_mentions,omitempty" example:"bob"`
+}
+
+type MentionDAO struct {
+	TodoID    uuid.UUID `db:"todo_id"`
+	UserID    int       `db:"user_id"`
+	Username  string    `db:"username"`
+	CreatedAt time.Time `db:"created_at"`
 }
 
 type GetTodosDTO struct {
@@ -28,4 +47,40 @@ type GetTodosDTO struct {
 	Assignee  int       `json:"assignee" example:"2"`
 	DateFrom  time.Time `json:"date_from"`
 	DateTo    time.Time `json:"date_to"`
+	Query     string    `json:"query" example:"assignee:me created>2024-01-01 -creator:5 text:\"deploy\""`
+
+	// CurrentUserID - пользователь, выполняющий запрос, подставляется в Query вместо "me"
+	CurrentUserID int `json:"current_user_id,omitempty" example:"1"`
+	// CurrentUserPermissions - разрешения роли пользователя, выполняющего запрос
+	CurrentUserPermissions []string `json:"-"`
+	// Timezone - часовой пояс пользователя, выполняющего запрос. В сохраненный фильтр не попадает:
+	// как и "me", он подставляется при каждом запуске
+	Timezone string `json:"-"`
+	// VisibleTo - если задан, выбираются только задачи, которые этот пользователь создал или исполняет
+	VisibleTo int `json:"-"`
+	// Filter - разобранное и проверенное сервисом выражение Query
+	Filter query.Node `json:"-"`
+}
+
+// Location возвращает часовой пояс, в котором понимаются даты выражения фильтра. Пустой
+// или неизвестный пояс заменяется на UTC: запрос не должен зависеть от настроек сервера
+func (d *GetTodosDTO) Location() *time.Location {
+	if d.Timezone == "" || d.Timezone == "Local" {
+		return time.UTC
+	}
+
+	location, err := time.LoadLocation(d.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+
+	return location
+}
+
+// Caller возвращает пользователя, выполняющего запрос
+func (d *GetTodosDTO) Caller() Caller {
+	return Caller{
+		UserID:      d.CurrentUserID,
+		Permissions: d.CurrentUserPermissions,
+	}
 }
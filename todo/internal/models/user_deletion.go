@@ -0,0 +1,60 @@
+package models
+
+import "github.com/google/uuid"
+
+// UserDeletionService - имя сервиса в ответах на удаление пользователя, под ним сервис записан
+// в участниках удаления в сервисе users
+const UserDeletionService = "todo"
+
+// DeletedUserID - создатель или исполнитель обезличенной задачи удаленного пользователя
+const DeletedUserID = 0
+
+// Что происходит с задачами удаленного пользователя
+const (
+	// UserDeletionPolicyReassign - задачи, назначенные пользователю, возвращаются создателям, а созданные им
+	// для других переходят к исполнителям. Задачи, которые пользователь создал для себя, удаляются
+	UserDeletionPolicyReassign = "reassign"
+	// UserDeletionPolicyDelete - удаляются все задачи, которые пользователь создал или исполняет
+	UserDeletionPolicyDelete = "delete"
+	// UserDeletionPolicyAnonymize - задачи остаются, вместо пользователя в них записывается DeletedUserID
+	UserDeletionPolicyAnonymize = "anonymize"
+)
+
+// IsUserDeletionPolicy проверяет, что политика из настроек известна сервису
+func IsUserDeletionPolicy(policy string) bool {
+	switch policy {
+	case UserDeletionPolicyReassign, UserDeletionPolicyDelete, UserDeletionPolicyAnonymize:
+		return true
+	}
+
+	return false
+}
+
+// Типы сообщений об удалении пользователя между сервисами
+const (
+	UserEventTypeUserDeleted           = "user_deleted"
+	UserEventTypeUserDeletionConfirmed = "user_deletion_confirmed"
+	UserEventTypeUserDeletionFailed    = "user_deletion_failed"
+	UserEventTypeUserDeletionCancelled = "user_deletion_cancelled"
+	UserEventTypeUserDeletionCompleted = "user_deletion_completed"
+)
+
+// UserDeletedEvent - событие сервиса users: пользователь удаляется. С тем же DeletionID users сообщает,
+// что удаление отменено (user_deletion_cancelled) или завершено (user_deletion_completed)
+type UserDeletedEvent struct {
+	EventType  string    `json:"event_type"`
+	DeletionID uuid.UUID `json:"deletion_id"`
+	UserID     int       `json:"user_id"`
+	// Policy - политика, которую требует users, например anonymize при удалении по запросу самого пользователя.
+	// Если пустая, используется политика из настроек
+	Policy string `json:"policy,omitempty"`
+}
+
+// UserDeletionReply - ответ сервису users: задачи пользователя обработаны или обработать их не удалось
+type UserDeletionReply struct {
+	EventType  string    `json:"event_type"`
+	DeletionID uuid.UUID `json:"deletion_id"`
+	UserID     int       `json:"user_id"`
+	Service    string    `json:"service"`
+	Error      string    `json:"error,omitempty"`
+}
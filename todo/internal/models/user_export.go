@@ -0,0 +1,39 @@
+package models
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+)
+
+// UserExportService - имя сервиса в ответах на выгрузку, под ним сервис записан в участниках выгрузки
+// в сервисе users, и так же называется файл с его данными в архиве
+const UserExportService = "todo"
+
+// Типы сообщений о выгрузке данных пользователя между сервисами
+const (
+	UserEventTypeUserExportRequested = "user_export_requested"
+	UserEventTypeUserExportData      = "user_export_data"
+	UserEventTypeUserExportFailed    = "user_export_failed"
+)
+
+// UserExportRequestedEvent - событие сервиса users: пользователь запросил выгрузку своих данных
+type UserExportRequestedEvent struct {
+	EventType string    `json:"event_type"`
+	ExportID  uuid.UUID `json:"export_id"`
+	UserID    int       `json:"user_id"`
+}
+
+// UserExportReply - ответ сервису users: задачи пользователя для выгрузки или причина, по которой их выгрузить не удалось
+type UserExportReply struct {
+	EventType string          `json:"event_type"`
+	ExportID  uuid.UUID       `json:"export_id"`
+	UserID    int             `json:"user_id"`
+	Service   string          `json:"service"`
+	Data      json.RawMessage `json:"data,omitempty"`
+	Error     string          `json:"error,omitempty"`
+}
+
+// UserExportData - данные пользователя в сервисе todo, попадают в архив выгрузки файлом todo.json
+type UserExportData struct {
+	Todos []TodoDTO `json:"todos"`
+}
@@ -7,6 +7,8 @@ type UserDTO struct {
 	Username string `json:"username" example:"username"`
 	Password string `json:"password,omitempty" example:"password"`
 	Email    string `json:"email,omitempty" example:"user@example.com"`
+	// Permissions - разрешения роли, приходят только из GetUsersByUsernames
+	Permissions []string `json:"-"`
 }
 
 func NewEmptyUserDTO() *UserDTO {
@@ -18,5 +20,15 @@ func (d *UserDTO) FromGRPC(in *users.UserDTO) *UserDTO {
 	d.Email = in.Email
 	d.Username = in.Username
 	d.Password = in.Password
+	d.Permissions = in.Permissions
 	return d
 }
+
+// UserEventTypeUserUpdated - тип события сервиса users об изменении данных пользователя
+const UserEventTypeUserUpdated = "user_updated"
+
+// UserUpdatedEvent - событие сервиса users: данные пользователя изменились
+type UserUpdatedEvent struct {
+	EventType string `json:"event_type"`
+	UserID    int    `json:"user_id"`
+}
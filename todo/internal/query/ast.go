@@ -0,0 +1,83 @@
+package query
+
+import "time"
+
+// Field - поле задачи, по которому можно фильтровать
+type Field string
+
+const (
+	FieldAssignee Field = "assignee"
+	FieldCreator  Field = "creator"
+	FieldCreated  Field = "created"
+	FieldUpdated  Field = "updated"
+	FieldTitle    Field = "title"
+	FieldText     Field = "text"
+)
+
+// Operator - оператор сравнения поля со значением
+type Operator string
+
+const (
+	OpEq  Operator = ":"
+	OpGt  Operator = ">"
+	OpGte Operator = ">="
+	OpLt  Operator = "<"
+	OpLte Operator = "<="
+)
+
+// ValueMe - значение, которое подставляется идентификатором текущего пользователя
+const ValueMe = "me"
+
+// Node - узел дерева разбора выражения
+type Node interface {
+	// Pos возвращает позицию начала узла в выражении (с единицы, в символах)
+	Pos() int
+}
+
+// And - все вложенные условия должны выполняться
+type And struct {
+	Position int
+	Nodes    []Node
+}
+
+// Or - должно выполняться хотя бы одно вложенное условие
+type Or struct {
+	Position int
+	Nodes    []Node
+}
+
+// Not - вложенное условие не должно выполняться
+type Not struct {
+	Position int
+	Node     Node
+}
+
+// Comparison - сравнение поля задачи со значением, например created>2024-01-01
+type Comparison struct {
+	Position int
+	Field    Field
+	Op       Operator
+	Value    Value
+}
+
+// Value - уже проверенное значение из сравнения
+type Value struct {
+	Position int
+	Raw      string
+
+	// заполняется для assignee и creator
+	Me     bool
+	UserID int
+
+	// заполняется для created и updated. DateOnly означает, что время не указано и сравнивать нужно весь день
+	Time     time.Time
+	DateOnly bool
+
+	// заполняется для title и text
+	Text string
+}
+
+func (n *And) Pos() int        { return n.Position }
+func (n *Or) Pos() int         { return n.Position }
+func (n *Not) Pos() int        { return n.Position }
+func (n *Comparison) Pos() int { return n.Position }
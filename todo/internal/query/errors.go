@@ -0,0 +1,24 @@
+package query
+
+import "fmt"
+
+// ErrorReasonInvalidQuery - причина ошибки, по которой другие сервисы узнают ошибку разбора выражения
+const ErrorReasonInvalidQuery = "INVALID_QUERY"
+
+// SyntaxError - ошибка разбора или проверки выражения с указанием места, где она найдена
+type SyntaxError struct {
+	// Position - позиция в выражении, считая с единицы, в символах
+	Position int
+	Message  string
+}
+
+func newSyntaxError(position int, format string, args ...interface{}) *SyntaxError {
+	return &SyntaxError{
+		Position: position,
+		Message:  fmt.Sprintf(format, args...),
+	}
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("invalid query at position %d: %s", e.Position, e.Message)
+}
@@ -0,0 +1,436 @@
+package query
+
+import (
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	// MaxLength - максимальная длина выражения в символах
+	MaxLength = 1024
+	// maxDepth - максимальная вложенность скобок и отрицаний
+	maxDepth = 32
+)
+
+const (
+	keywordAnd = "AND"
+	keywordOr  = "OR"
+)
+
+// Parse разбирает выражение вида `assignee:me created>2024-01-01 -creator:5 text:"deploy"`
+// в дерево и проверяет поля, операторы и значения. Условия, записанные через пробел, объединяются по И,
+// ключевое слово OR объединяет по ИЛИ, минус перед условием его отрицает, скобки задают группировку.
+// Для пустого выражения возвращается nil.
+func Parse(expression string) (Node, error) {
+	p := &parser{input: []rune(expression)}
+
+	if len(p.input) > MaxLength {
+		return nil, newSyntaxError(MaxLength+1, "query is longer than %d characters", MaxLength)
+	}
+
+	p.skipSpaces()
+	if p.eof() {
+		return nil, nil
+	}
+
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpaces()
+	if !p.eof() {
+		// единственный способ остановиться раньше конца - лишняя закрывающая скобка
+		return nil, newSyntaxError(p.position(), "unexpected %q", string(p.peek()))
+	}
+
+	return node, nil
+}
+
+type parser struct {
+	input []rune
+	pos   int
+	depth int
+}
+
+// position возвращает текущую позицию в том виде, в каком она попадает в ошибки
+func (p *parser) position() int {
+	return p.pos + 1
+}
+
+func (p *parser) eof() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *parser) peek() rune {
+	if p.eof() {
+		return 0
+	}
+	return p.input[p.pos]
+}
+
+func (p *parser) skipSpaces() {
+	for !p.eof() && unicode.IsSpace(p.peek()) {
+		p.pos++
+	}
+}
+
+// atTermEnd сообщает, что очередное условие закончилось: дальше конец выражения или закрывающая скобка
+func (p *parser) atTermEnd() bool {
+	return p.eof() || p.peek() == ')'
+}
+
+// parseOr: and (OR and)*
+func (p *parser) parseOr() (Node, error) {
+	start := p.position()
+
+	first, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := []Node{first}
+	for {
+		p.skipSpaces()
+		if !p.acceptKeyword(keywordOr) {
+			break
+		}
+
+		p.skipSpaces()
+		if p.atTermEnd() {
+			return nil, newSyntaxError(p.position(), "expected condition after %s", keywordOr)
+		}
+
+		next, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, next)
+	}
+
+	if len(nodes) == 1 {
+		return first, nil
+	}
+
+	return &Or{Position: start, Nodes: nodes}, nil
+}
+
+// parseAnd: unary ([AND] unary)*
+func (p *parser) parseAnd() (Node, error) {
+	start := p.position()
+
+	var nodes []Node
+	for {
+		p.skipSpaces()
+		if p.atTermEnd() || p.isKeyword(keywordOr) {
+			break
+		}
+
+		if p.acceptKeyword(keywordAnd) {
+			p.skipSpaces()
+			if p.atTermEnd() || p.isKeyword(keywordOr) {
+				return nil, newSyntaxError(p.position(), "expected condition after %s", keywordAnd)
+			}
+		}
+
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+
+	switch len(nodes) {
+	case 0:
+		if p.eof() {
+			return nil, newSyntaxError(p.position(), "expected condition")
+		}
+		return nil, newSyntaxError(p.position(), "expected condition, got %q", string(p.peek()))
+	case 1:
+		return nodes[0], nil
+	}
+
+	return &And{Position: start, Nodes: nodes}, nil
+}
+
+// parseUnary: '-' unary | '(' or ')' | term
+func (p *parser) parseUnary() (Node, error) {
+	start := p.position()
+
+	p.depth++
+	defer func() { p.depth-- }()
+	if p.depth > maxDepth {
+		return nil, newSyntaxError(start, "query is nested deeper than %d levels", maxDepth)
+	}
+
+	switch p.peek() {
+	case '-':
+		p.pos++
+		if p.eof() || unicode.IsSpace(p.peek()) || p.peek() == ')' {
+			return nil, newSyntaxError(p.position(), "expected condition right after '-'")
+		}
+
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &Not{Position: start, Node: node}, nil
+
+	case '(':
+		p.pos++
+		p.skipSpaces()
+		if p.peek() == ')' {
+			return nil, newSyntaxError(p.position(), "empty parentheses")
+		}
+
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		p.skipSpaces()
+		if p.peek() != ')' {
+			return nil, newSyntaxError(p.position(), "missing ')' for '(' at position %d", start)
+		}
+		p.pos++
+		return node, nil
+	}
+
+	return p.parseTerm()
+}
+
+// parseTerm: field op value | value
+func (p *parser) parseTerm() (Node, error) {
+	start := p.position()
+
+	if p.peek() == '"' {
+		text, err := p.parseQuoted()
+		if err != nil {
+			return nil, err
+		}
+		return textComparison(start, text)
+	}
+
+	word := p.readWhile(func(r rune) bool {
+		return !unicode.IsSpace(r) && !strings.ContainsRune(`:<>()"`, r)
+	})
+	if word == "" {
+		return nil, newSyntaxError(start, "unexpected %q", string(p.peek()))
+	}
+
+	opPosition := p.position()
+	op, ok := p.parseOperator()
+	if !ok {
+		// слово без оператора ищем в тексте задачи
+		return textComparison(start, word)
+	}
+
+	field := Field(strings.ToLower(word))
+	if !isKnownField(field) {
+		return nil, newSyntaxError(start, "unknown field %q, expected one of %s", word, knownFieldsList())
+	}
+
+	if !isOperatorAllowed(field, op) {
+		return nil, newSyntaxError(opPosition, "operator %q is not supported for field %q", string(op), field)
+	}
+
+	valuePosition := p.position()
+	var raw string
+	if p.peek() == '"' {
+		text, err := p.parseQuoted()
+		if err != nil {
+			return nil, err
+		}
+		raw = text
+	} else {
+		// значение может содержать ':' - например, время в RFC3339
+		raw = p.readWhile(func(r rune) bool {
+			return !unicode.IsSpace(r) && r != '(' && r != ')' && r != '"'
+		})
+	}
+
+	if raw == "" {
+		return nil, newSyntaxError(valuePosition, "expected value after %q", word+string(op))
+	}
+
+	value, err := parseValue(field, raw, valuePosition)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Comparison{
+		Position: start,
+		Field:    field,
+		Op:       op,
+		Value:    value,
+	}, nil
+}
+
+func (p *parser) parseOperator() (Operator, bool) {
+	switch p.peek() {
+	case ':':
+		p.pos++
+		return OpEq, true
+	case '>':
+		p.pos++
+		if p.peek() == '=' {
+			p.pos++
+			return OpGte, true
+		}
+		return OpGt, true
+	case '<':
+		p.pos++
+		if p.peek() == '=' {
+			p.pos++
+			return OpLte, true
+		}
+		return OpLt, true
+	}
+
+	return "", false
+}
+
+// parseQuoted читает строку в двойных кавычках, внутри которой можно экранировать \" и \\
+func (p *parser) parseQuoted() (string, error) {
+	start := p.position()
+	p.pos++
+
+	var sb strings.Builder
+	for !p.eof() {
+		r := p.peek()
+		p.pos++
+
+		switch r {
+		case '"':
+			return sb.String(), nil
+		case '\\':
+			if p.eof() {
+				return "", newSyntaxError(p.position(), "unfinished escape sequence")
+			}
+			sb.WriteRune(p.peek())
+			p.pos++
+		default:
+			sb.WriteRune(r)
+		}
+	}
+
+	return "", newSyntaxError(start, "unterminated quoted string")
+}
+
+func (p *parser) readWhile(accept func(r rune) bool) string {
+	start := p.pos
+	for !p.eof() && accept(p.peek()) {
+		p.pos++
+	}
+	return string(p.input[start:p.pos])
+}
+
+// isKeyword проверяет, что дальше стоит ключевое слово, отделенное от остального текста
+func (p *parser) isKeyword(keyword string) bool {
+	end := p.pos + len(keyword)
+	if end > len(p.input) || string(p.input[p.pos:end]) != keyword {
+		return false
+	}
+
+	return end == len(p.input) || unicode.IsSpace(p.input[end]) || p.input[end] == '(' || p.input[end] == '-'
+}
+
+func (p *parser) acceptKeyword(keyword string) bool {
+	if !p.isKeyword(keyword) {
+		return false
+	}
+
+	p.pos += len(keyword)
+	return true
+}
+
+func textComparison(position int, text string) (Node, error) {
+	value, err := parseValue(FieldText, text, position)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Comparison{
+		Position: position,
+		Field:    FieldText,
+		Op:       OpEq,
+		Value:    value,
+	}, nil
+}
+
+func parseValue(field Field, raw string, position int) (Value, error) {
+	value := Value{
+		Position: position,
+		Raw:      raw,
+	}
+
+	switch field {
+	case FieldAssignee, FieldCreator:
+		if strings.EqualFold(raw, ValueMe) {
+			value.Me = true
+			return value, nil
+		}
+
+		userID, err := strconv.Atoi(raw)
+		if err != nil || userID <= 0 {
+			return value, newSyntaxError(position, "%s expects a user id or %q, got %q", field, ValueMe, raw)
+		}
+		value.UserID = userID
+
+	case FieldCreated, FieldUpdated:
+		if t, err := time.Parse("2006-01-02", raw); err == nil {
+			value.Time = t
+			value.DateOnly = true
+			return value, nil
+		}
+
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return value, newSyntaxError(position, "%s expects a date like 2024-01-31 or 2024-01-31T15:04:05Z, got %q", field, raw)
+		}
+		value.Time = t
+
+	case FieldTitle, FieldText:
+		if strings.TrimSpace(raw) == "" {
+			return value, newSyntaxError(position, "%s expects non-empty text", field)
+		}
+		value.Text = raw
+	}
+
+	return value, nil
+}
+
+var fieldOperators = map[Field][]Operator{
+	FieldAssignee: {OpEq},
+	FieldCreator:  {OpEq},
+	FieldCreated:  {OpEq, OpGt, OpGte, OpLt, OpLte},
+	FieldUpdated:  {OpEq, OpGt, OpGte, OpLt, OpLte},
+	FieldTitle:    {OpEq},
+	FieldText:     {OpEq},
+}
+
+var knownFields = []Field{FieldAssignee, FieldCreator, FieldCreated, FieldUpdated, FieldTitle, FieldText}
+
+func isKnownField(field Field) bool {
+	_, ok := fieldOperators[field]
+	return ok
+}
+
+func isOperatorAllowed(field Field, op Operator) bool {
+	for _, allowed := range fieldOperators[field] {
+		if allowed == op {
+			return true
+		}
+	}
+	return false
+}
+
+func knownFieldsList() string {
+	names := make([]string, len(knownFields))
+	for i, field := range knownFields {
+		names[i] = string(field)
+	}
+	return strings.Join(names, ", ")
+}
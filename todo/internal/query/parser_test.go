@@ -0,0 +1,151 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// render записывает дерево в виде скобочного выражения, чтобы сравнивать группировку условий
+func render(node Node) string {
+	switch n := node.(type) {
+	case nil:
+		return "<nil>"
+	case *And:
+		return renderGroup("and", n.Nodes)
+	case *Or:
+		return renderGroup("or", n.Nodes)
+	case *Not:
+		return "(not " + render(n.Node) + ")"
+	case *Comparison:
+		return string(n.Field) + string(n.Op) + n.Value.Raw
+	}
+
+	return fmt.Sprintf("<unknown %T>", node)
+}
+
+func renderGroup(name string, nodes []Node) string {
+	parts := make([]string, len(nodes))
+	for i, node := range nodes {
+		parts[i] = render(node)
+	}
+	return "(" + name + " " + strings.Join(parts, " ") + ")"
+}
+
+func TestParsePrecedence(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		want       string
+	}{
+		{"empty", "   ", "<nil>"},
+		{"single comparison", "assignee:me", "assignee:me"},
+		{"bare word is text", "deploy", "text:deploy"},
+		{"spaces join with and", "assignee:me creator:5", "(and assignee:me creator:5)"},
+		{"explicit and", "assignee:me AND creator:5", "(and assignee:me creator:5)"},
+		{"and binds tighter than or on the left", "a b OR c", "(or (and text:a text:b) text:c)"},
+		{"and binds tighter than or on the right", "a OR b c", "(or text:a (and text:b text:c))"},
+		{"explicit and binds tighter than or", "a AND b OR c", "(or (and text:a text:b) text:c)"},
+		{"several ors are flattened", "a OR b OR c", "(or text:a text:b text:c)"},
+		{"parentheses group or", "(a OR b) c", "(and (or text:a text:b) text:c)"},
+		{"minus negates one condition", "-creator:5 a", "(and (not creator:5) text:a)"},
+		{"minus negates a group", "-(a OR b) c", "(and (not (or text:a text:b)) text:c)"},
+		{"double negation", "--a", "(not (not text:a))"},
+		{"lowercase or is text", "a or b", "(and text:a text:or text:b)"},
+		{"quoted text", `title:"release notes" "a \"b\""`, `(and title:release notes text:a "b")`},
+		{"date operators", "created>=2024-01-01 updated<2024-02-01T10:00:00Z", "(and created>=2024-01-01 updated<2024-02-01T10:00:00Z)"},
+		{"field names ignore case", "Assignee:ME", "assignee:ME"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node, err := Parse(tt.expression)
+			if err != nil {
+				t.Fatalf("Parse(%q) error: %v", tt.expression, err)
+			}
+			if got := render(node); got != tt.want {
+				t.Errorf("Parse(%q) = %s, want %s", tt.expression, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseValues(t *testing.T) {
+	node, err := Parse("assignee:me creator:7 created:2024-01-31 updated>2024-01-31T15:04:05Z")
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+
+	and, ok := node.(*And)
+	if !ok || len(and.Nodes) != 4 {
+		t.Fatalf("Parse = %s, want four conditions joined with and", render(node))
+	}
+
+	values := make([]Value, len(and.Nodes))
+	for i, n := range and.Nodes {
+		values[i] = n.(*Comparison).Value
+	}
+
+	if !values[0].Me {
+		t.Errorf("assignee:me Me = false, want true")
+	}
+	if values[1].UserID != 7 {
+		t.Errorf("creator:7 UserID = %d, want 7", values[1].UserID)
+	}
+	if !values[2].DateOnly || values[2].Time.Format("2006-01-02") != "2024-01-31" {
+		t.Errorf("created:2024-01-31 = %v (date only %v), want 2024-01-31 date only", values[2].Time, values[2].DateOnly)
+	}
+	if values[3].DateOnly || values[3].Time.Hour() != 15 {
+		t.Errorf("updated>2024-01-31T15:04:05Z = %v (date only %v), want 15:04:05 with time", values[3].Time, values[3].DateOnly)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		position   int
+		message    string
+	}{
+		{"too long", strings.Repeat("a", MaxLength+1), MaxLength + 1, "longer than"},
+		{"or without right side", "a OR", 5, "expected condition after OR"},
+		{"and without right side", "a AND", 6, "expected condition after AND"},
+		{"and before or", "a AND OR b", 7, "expected condition after AND"},
+		{"or at start", "OR a", 1, "expected condition"},
+		{"unclosed parenthesis", "(a b", 5, "missing ')' for '(' at position 1"},
+		{"extra closing parenthesis", "a)", 2, `unexpected ")"`},
+		{"empty parentheses", "a ( )", 5, "empty parentheses"},
+		{"minus followed by space", "a - b", 4, "expected condition right after '-'"},
+		{"unknown field", "a foo:bar", 3, `unknown field "foo"`},
+		{"operator not allowed", "assignee>5", 9, `operator ">" is not supported`},
+		{"missing value", "title:", 7, `expected value after "title:"`},
+		{"bad user id", "creator:abc", 9, "creator expects a user id"},
+		{"negative user id", "assignee:-1", 10, "assignee expects a user id"},
+		{"bad date", "created:2024-13-01", 9, "created expects a date"},
+		{"empty quoted text", `title:" "`, 7, "title expects non-empty text"},
+		{"unterminated quote", `a title:"abc`, 9, "unterminated quoted string"},
+		{"unfinished escape", `"abc\`, 6, "unfinished escape sequence"},
+		{"nested too deep", strings.Repeat("(", maxDepth+1) + "a" + strings.Repeat(")", maxDepth+1), maxDepth + 1, "nested deeper"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node, err := Parse(tt.expression)
+			if err == nil {
+				t.Fatalf("Parse(%q) = %s, want error", tt.expression, render(node))
+			}
+
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("Parse(%q) error %T, want *SyntaxError", tt.expression, err)
+			}
+			if syntaxErr.Position != tt.position {
+				t.Errorf("Parse(%q) error position = %d, want %d (%s)", tt.expression, syntaxErr.Position, tt.position, syntaxErr.Message)
+			}
+			if !strings.Contains(syntaxErr.Message, tt.message) {
+				t.Errorf("Parse(%q) error message = %q, want it to contain %q", tt.expression, syntaxErr.Message, tt.message)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"todo/internal/query"
+)
+
+// queryColumns - колонки таблицы todos, в которые превращаются поля выражения фильтра
+var queryColumns = map[query.Field]string{
+	query.FieldAssignee: "assignee",
+	query.FieldCreator:  "created_by",
+	query.FieldCreated:  "created_at",
+	query.FieldUpdated:  "updated_at",
+	query.FieldTitle:    "title",
+}
+
+// compileQuery превращает дерево выражения фильтра в условие для squirrel.
+// currentUserID подставляется вместо значения "me", location задает границы дней для дат без времени.
+func compileQuery(node query.Node, currentUserID int, location *time.Location) (squirrel.Sqlizer, error) {
+	switch n := node.(type) {
+	case *query.And:
+		conditions := make(squirrel.And, 0, len(n.Nodes))
+		for _, child := range n.Nodes {
+			condition, err := compileQuery(child, currentUserID, location)
+			if err != nil {
+				return nil, err
+			}
+			conditions = append(conditions, condition)
+		}
+		return conditions, nil
+
+	case *query.Or:
+		conditions := make(squirrel.Or, 0, len(n.Nodes))
+		for _, child := range n.Nodes {
+			condition, err := compileQuery(child, currentUserID, location)
+			if err != nil {
+				return nil, err
+			}
+			conditions = append(conditions, condition)
+		}
+		return conditions, nil
+
+	case *query.Not:
+		condition, err := compileQuery(n.Node, currentUserID, location)
+		if err != nil {
+			return nil, err
+		}
+		return notCondition{condition}, nil
+
+	case *query.Comparison:
+		return compileComparison(n, currentUserID, location)
+	}
+
+	return nil, fmt.Errorf("[compileQuery] unexpected node %T", node)
+}
+
+func compileComparison(c *query.Comparison, currentUserID int, location *time.Location) (squirrel.Sqlizer, error) {
+	switch c.Field {
+	case query.FieldAssignee, query.FieldCreator:
+		userID := c.Value.UserID
+		if c.Value.Me {
+			if currentUserID == 0 {
+				return nil, &query.SyntaxError{
+					Position: c.Value.Position,
+					Message:  fmt.Sprintf("%q can only be used by an authenticated user", query.ValueMe),
+				}
+			}
+			userID = currentUserID
+		}
+		return squirrel.Eq{queryColumns[c.Field]: userID}, nil
+
+	case query.FieldCreated, query.FieldUpdated:
+		return compileTimeComparison(queryColumns[c.Field], c.Op, c.Value, location), nil
+
+	case query.FieldTitle:
+		return squirrel.ILike{"title": likePattern(c.Value.Text)}, nil
+
+	case query.FieldText:
+		pattern := likePattern(c.Value.Text)
+		return squirrel.Or{
+			squirrel.ILike{"title": pattern},
+			squirrel.ILike{"description": pattern},
+		}, nil
+	}
+
+	return nil, fmt.Errorf("[compileComparison] unexpected field %q", c.Field)
+}
+
+func compileTimeComparison(column string, op query.Operator, value query.Value, location *time.Location) squirrel.Sqlizer {
+	// колонки имеют тип TIMESTAMP без зоны и хранят время в UTC
+	if !value.DateOnly {
+		moment := value.Time.UTC()
+		switch op {
+		case query.OpGt:
+			return squirrel.Gt{column: moment}
+		case query.OpGte:
+			return squirrel.GtOrEq{column: moment}
+		case query.OpLt:
+			return squirrel.Lt{column: moment}
+		case query.OpLte:
+			return squirrel.LtOrEq{column: moment}
+		default:
+			return squirrel.Eq{column: moment}
+		}
+	}
+
+	// дата без времени означает целый день в зоне пользователя: [dayStart, nextDayStart)
+	dayStart := time.Date(value.Time.Year(), value.Time.Month(), value.Time.Day(), 0, 0, 0, 0, location)
+	nextDayStart := dayStart.AddDate(0, 0, 1)
+
+	switch op {
+	case query.OpGt:
+		return squirrel.GtOrEq{column: nextDayStart.UTC()}
+	case query.OpGte:
+		return squirrel.GtOrEq{column: dayStart.UTC()}
+	case query.OpLt:
+		return squirrel.Lt{column: dayStart.UTC()}
+	case query.OpLte:
+		return squirrel.Lt{column: nextDayStart.UTC()}
+	default:
+		return squirrel.And{
+			squirrel.GtOrEq{column: dayStart.UTC()},
+			squirrel.Lt{column: nextDayStart.UTC()},
+		}
+	}
+}
+
+// likePattern экранирует спецсимволы LIKE, чтобы текст искался как есть
+func likePattern(text string) string {
+	escaper := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return "%" + escaper.Replace(text) + "%"
+}
+
+// notCondition - отрицание условия, которого нет среди готовых выражений squirrel
+type notCondition struct {
+	condition squirrel.Sqlizer
+}
+
+func (n notCondition) ToSql() (string, []interface{}, error) {
+	sql, args, err := n.condition.ToSql()
+	if err != nil {
+		return "", nil, err
+	}
+
+	return fmt.Sprintf("NOT (%s)", sql), args, nil
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go"
+	"todo/internal/models"
+	"todo/pkg/ctxutil"
+)
+
+// GetMentions возвращает сохраненные упоминания для каждой из задач
+func (r *TodoRepository) GetMentions(ctx context.Context, todoIDs []uuid.UUID) (map[uuid.UUID][]models.MentionDAO, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.GetMentions")
+	defer span.Finish()
+
+	var mentions = make(map[uuid.UUID][]models.MentionDAO, len(todoIDs))
+	if len(todoIDs) == 0 {
+		return mentions, nil
+	}
+
+	sql := `
+        SELECT
+            todo_id,
+			user_id,
+			username,
+			created_at
+        FROM
+            todo_mentions
+        WHERE
+            todo_id = ANY($1)
+        ORDER BY
+            created_at, username
+    `
+	rows, err := r.conn.Query(ctx, sql, todoIDs)
+	if err != nil {
+		return nil, fmt.Errorf("[GetMentions] query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var mention models.MentionDAO
+		err := rows.Scan(&mention.TodoID, &mention.UserID, &mention.Username, &mention.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("[GetMentions] scan: %w", err)
+		}
+		mentions[mention.TodoID] = append(mentions[mention.TodoID], mention)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("[GetMentions] rows: %w", err)
+	}
+
+	return mentions, nil
+}
+
+// SetMentions заменяет упоминания задачи на переданные. Упоминания, которые уже были, сохраняют дату создания.
+func (r *TodoRepository) SetMentions(ctx context.Context, todoID uuid.UUID, mentions []models.MentionDAO) error {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.SetMentions")
+	defer span.Finish()
+
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("[SetMentions] begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	userIDs := make([]int, len(mentions))
+	for i, mention := range mentions {
+		userIDs[i] = mention.UserID
+	}
+
+	_, err = tx.Exec(ctx, `
+        DELETE FROM
+		    todo_mentions
+        WHERE
+            todo_id = $1 AND NOT (user_id = ANY($2))
+    `, todoID, userIDs)
+	if err != nil {
+		return fmt.Errorf("[SetMentions] delete: %w", err)
+	}
+
+	for _, mention := range mentions {
+		_, err = tx.Exec(ctx, `
+            INSERT INTO
+				todo_mentions (todo_id, user_id, username, created_at)
+            VALUES
+				($1, $2, $3, now())
+            ON CONFLICT (todo_id, user_id) DO UPDATE SET username = EXCLUDED.username
+        `, todoID, mention.UserID, mention.Username)
+		if err != nil {
+			return fmt.Errorf("[SetMentions] insert: %w", err)
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("[SetMentions] commit: %w", err)
+	}
+
+	return nil
+}
@@ -2,11 +2,14 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/Masterminds/squirrel"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/opentracing/opentracing-go"
+	"todo/internal/app_errors"
 	"todo/internal/models"
 	"todo/pkg/ctxutil"
 )
@@ -33,15 +36,16 @@ func (r *TodoRepository) CreateToDo(ctx context.Context, newTodo *models.TodoDAO
 			    id,
 				created_by, 
 				assignee, 
+				title,
 				description,
 				created_at,
 				updated_at
 			)
         VALUES 
-			($1, $2, $3, $4, now(), now())
+			($1, $2, $3, $4, $5, now(), now())
         RETURNING id
     `
-	err := r.conn.QueryRow(ctx, sql, newTodo.ID, newTodo.CreatedBy, newTodo.Assignee, newTodo.Description).
+	err := r.conn.QueryRow(ctx, sql, newTodo.ID, newTodo.CreatedBy, newTodo.Assignee, newTodo.Title, newTodo.Description).
 		Scan(&todoId)
 	if err != nil {
 		return nil, err
@@ -63,13 +67,14 @@ func (r *TodoRepository) UpdateToDo(ctx context.Context, newTodo *models.TodoDAO
 		todos
 	SET
 	    assignee = $2,
-	    description = $3,
+	    title = $3,
+	    description = $4,
 	    updated_at = now()
 	WHERE 
 	    id = $1
 	`
 
-	_, err := r.conn.Exec(ctx, sql, newTodo.ID, newTodo.Assignee, newTodo.Description)
+	_, err := r.conn.Exec(ctx, sql, newTodo.ID, newTodo.Assignee, newTodo.Title, newTodo.Description)
 	if err != nil {
 		return nil, err
 	}
@@ -89,6 +94,7 @@ func (r *TodoRepository) GetToDos(ctx context.Context, todos *models.GetTodosDTO
 		"id",
 		"created_by",
 		"assignee",
+		"title",
 		"description",
 		"created_at",
 		"updated_at",
@@ -96,20 +102,9 @@ func (r *TodoRepository) GetToDos(ctx context.Context, todos *models.GetTodosDTO
 		From("todos").
 		PlaceholderFormat(squirrel.Dollar)
 
-	if todos.CreatedBy != 0 {
-		builder = builder.Where(squirrel.Eq{"created_by": todos.CreatedBy})
-	}
-
-	if todos.Assignee != 0 {
-		builder = builder.Where(squirrel.Eq{"assignee": todos.Assignee})
-	}
-
-	if !todos.DateFrom.IsZero() && !todos.DateTo.IsZero() {
-		builder = builder.Where("created_at BETWEEN ? AND ?", todos.DateFrom, todos.DateTo)
-	} else if !todos.DateFrom.IsZero() {
-		builder = builder.Where("created_at >= ?", todos.DateFrom)
-	} else if !todos.DateTo.IsZero() {
-		builder = builder.Where("created_at <= ?", todos.DateTo)
+	builder, err := applyTodosFilter(builder, todos)
+	if err != nil {
+		return nil, fmt.Errorf("[GetToDos] %w", err)
 	}
 
 	query, args, err := builder.ToSql()
@@ -133,6 +128,7 @@ func (r *TodoRepository) GetToDos(ctx context.Context, todos *models.GetTodosDTO
 			&todo.ID,
 			&todo.CreatedBy,
 			&todo.Assignee,
+			&todo.Title,
 			&todo.Description,
 			&todo.CreatedAt,
 			&todo.UpdatedAt,
@@ -150,6 +146,72 @@ func (r *TodoRepository) GetToDos(ctx context.Context, todos *models.GetTodosDTO
 	return todosFromDb, nil
 }
 
+// CountToDos возвращает количество задач, подходящих под критерии
+func (r *TodoRepository) CountToDos(ctx context.Context, todos *models.GetTodosDTO) (int, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.CountToDos")
+	defer span.Finish()
+
+	builder := squirrel.Select("COUNT(*)").
+		From("todos").
+		PlaceholderFormat(squirrel.Dollar)
+
+	builder, err := applyTodosFilter(builder, todos)
+	if err != nil {
+		return 0, fmt.Errorf("[CountToDos] %w", err)
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("[CountToDos] build query: %w", err)
+	}
+
+	var count int
+	err = r.conn.QueryRow(ctx, query, args...).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("[CountToDos] query: %w", err)
+	}
+
+	return count, nil
+}
+
+// applyTodosFilter добавляет к запросу условия из критериев выборки задач
+func applyTodosFilter(builder squirrel.SelectBuilder, todos *models.GetTodosDTO) (squirrel.SelectBuilder, error) {
+	if todos.VisibleTo != 0 {
+		builder = builder.Where(squirrel.Or{
+			squirrel.Eq{"created_by": todos.VisibleTo},
+			squirrel.Eq{"assignee": todos.VisibleTo},
+		})
+	}
+
+	if todos.CreatedBy != 0 {
+		builder = builder.Where(squirrel.Eq{"created_by": todos.CreatedBy})
+	}
+
+	if todos.Assignee != 0 {
+		builder = builder.Where(squirrel.Eq{"assignee": todos.Assignee})
+	}
+
+	if !todos.DateFrom.IsZero() && !todos.DateTo.IsZero() {
+		builder = builder.Where("created_at BETWEEN ? AND ?", todos.DateFrom, todos.DateTo)
+	} else if !todos.DateFrom.IsZero() {
+		builder = builder.Where("created_at >= ?", todos.DateFrom)
+	} else if !todos.DateTo.IsZero() {
+		builder = builder.Where("created_at <= ?", todos.DateTo)
+	}
+
+	if todos.Filter != nil {
+		condition, err := compileQuery(todos.Filter, todos.CurrentUserID, todos.Location())
+		if err != nil {
+			return builder, fmt.Errorf("compile query: %w", err)
+		}
+		builder = builder.Where(condition)
+	}
+
+	return builder, nil
+}
+
 func (r *TodoRepository) GetToDo(ctx context.Context, todoID uuid.UUID) (*models.TodoDAO, error) {
 	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
 
@@ -162,6 +224,7 @@ func (r *TodoRepository) GetToDo(ctx context.Context, todoID uuid.UUID) (*models
             id, 
 			created_by, 
 			assignee, 
+			title,
 			description,
 			created_at,
 			updated_at
@@ -171,8 +234,11 @@ func (r *TodoRepository) GetToDo(ctx context.Context, todoID uuid.UUID) (*models
             id = $1
     `
 	err := r.conn.QueryRow(ctx, sql, todoID).
-		Scan(&todo.ID, &todo.CreatedBy, &todo.Assignee, &todo.Description, &todo.CreatedAt, &todo.UpdatedAt)
+		Scan(&todo.ID, &todo.CreatedBy, &todo.Assignee, &todo.Title, &todo.Description, &todo.CreatedAt, &todo.UpdatedAt)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, app_errors.ErrTodoNotFound
+		}
 		return nil, err
 	}
 	return &todo, nil
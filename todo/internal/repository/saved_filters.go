@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/opentracing/opentracing-go"
+	"todo/internal/app_errors"
+	"todo/internal/models"
+	"todo/pkg/ctxutil"
+)
+
+// uniqueViolationCode - код ошибки postgres при нарушении уникального индекса
+const uniqueViolationCode = "23505"
+
+func (r *TodoRepository) CreateSavedFilter(ctx context.Context, filter *models.SavedFilterDAO) (*models.SavedFilterDAO, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.CreateSavedFilter")
+	defer span.Finish()
+
+	sql := `
+        INSERT INTO
+			saved_filters (
+			    id,
+				owner_id,
+				name,
+				shared,
+				criteria,
+				created_at,
+				updated_at
+			)
+        VALUES
+			($1, $2, $3, $4, $5, now(), now())
+        RETURNING created_at, updated_at
+    `
+	err := r.conn.QueryRow(ctx, sql, filter.ID, filter.OwnerID, filter.Name, filter.Shared, filter.Criteria).
+		Scan(&filter.CreatedAt, &filter.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("[CreateSavedFilter] insert: %w", savedFilterError(err))
+	}
+
+	return filter, nil
+}
+
+func (r *TodoRepository) UpdateSavedFilter(ctx context.Context, filter *models.SavedFilterDAO) (*models.SavedFilterDAO, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.UpdateSavedFilter")
+	defer span.Finish()
+
+	sql := `
+	UPDATE
+		saved_filters
+	SET
+	    name = $2,
+	    shared = $3,
+	    criteria = $4,
+	    updated_at = now()
+	WHERE
+	    id = $1
+	RETURNING updated_at
+	`
+
+	err := r.conn.QueryRow(ctx, sql, filter.ID, filter.Name, filter.Shared, filter.Criteria).
+		Scan(&filter.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("[UpdateSavedFilter] update: %w", savedFilterError(err))
+	}
+
+	return filter, nil
+}
+
+func (r *TodoRepository) GetSavedFilter(ctx context.Context, filterID uuid.UUID) (*models.SavedFilterDAO, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.GetSavedFilter")
+	defer span.Finish()
+
+	var filter models.SavedFilterDAO
+	sql := `
+        SELECT
+            id,
+			owner_id,
+			name,
+			shared,
+			criteria,
+			created_at,
+			updated_at
+        FROM
+            saved_filters
+        WHERE
+            id = $1
+    `
+	err := r.conn.QueryRow(ctx, sql, filterID).
+		Scan(&filter.ID, &filter.OwnerID, &filter.Name, &filter.Shared, &filter.Criteria, &filter.CreatedAt, &filter.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("[GetSavedFilter] select: %w", savedFilterError(err))
+	}
+
+	return &filter, nil
+}
+
+// GetSavedFilters возвращает фильтры пользователя и общие фильтры остальных пользователей
+func (r *TodoRepository) GetSavedFilters(ctx context.Context, userID int) ([]models.SavedFilterDAO, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.GetSavedFilters")
+	defer span.Finish()
+
+	var filters = make([]models.SavedFilterDAO, 0)
+
+	sql := `
+        SELECT
+            id,
+			owner_id,
+			name,
+			shared,
+			criteria,
+			created_at,
+			updated_at
+        FROM
+            saved_filters
+        WHERE
+            owner_id = $1 OR shared
+        ORDER BY
+            owner_id <> $1, name
+    `
+	rows, err := r.conn.Query(ctx, sql, userID)
+	if err != nil {
+		return nil, fmt.Errorf("[GetSavedFilters] query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var filter models.SavedFilterDAO
+		err := rows.Scan(
+			&filter.ID,
+			&filter.OwnerID,
+			&filter.Name,
+			&filter.Shared,
+			&filter.Criteria,
+			&filter.CreatedAt,
+			&filter.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("[GetSavedFilters] scan: %w", err)
+		}
+		filters = append(filters, filter)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("[GetSavedFilters] rows: %w", err)
+	}
+
+	return filters, nil
+}
+
+func (r *TodoRepository) DeleteSavedFilter(ctx context.Context, filterID uuid.UUID) error {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.DeleteSavedFilter")
+	defer span.Finish()
+
+	sql := `
+        DELETE FROM
+		    saved_filters
+        WHERE
+            id = $1
+    `
+	tag, err := r.conn.Exec(ctx, sql, filterID)
+	if err != nil {
+		return fmt.Errorf("[DeleteSavedFilter] delete: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return app_errors.ErrSavedFilterNotFound
+	}
+
+	return nil
+}
+
+// savedFilterError переводит ошибки базы в ошибки приложения
+func savedFilterError(err error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return app_errors.ErrSavedFilterNotFound
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+		return app_errors.ErrSavedFilterNameIsUsed
+	}
+
+	return err
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/opentracing/opentracing-go"
+	"todo/internal/app_errors"
+	"todo/internal/models"
+	"todo/pkg/ctxutil"
+)
+
+func (r *TodoRepository) CreateShareLink(ctx context.Context, link *models.ShareLinkDAO) (*models.ShareLinkDAO, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.CreateShareLink")
+	defer span.Finish()
+
+	sql := `
+        INSERT INTO
+			share_links (
+			    id,
+				todo_id,
+				created_by,
+				expires_at,
+				created_at
+			)
+        VALUES
+			($1, $2, $3, $4, now())
+        RETURNING created_at
+    `
+	err := r.conn.QueryRow(ctx, sql, link.ID, link.TodoID, link.CreatedBy, link.ExpiresAt).
+		Scan(&link.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("[CreateShareLink] insert: %w", err)
+	}
+
+	return link, nil
+}
+
+func (r *TodoRepository) GetShareLink(ctx context.Context, linkID uuid.UUID) (*models.ShareLinkDAO, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.GetShareLink")
+	defer span.Finish()
+
+	var link models.ShareLinkDAO
+	sql := `
+        SELECT
+            id,
+			todo_id,
+			created_by,
+			expires_at,
+			revoked_at,
+			created_at
+        FROM
+            share_links
+        WHERE
+            id = $1
+    `
+	err := r.conn.QueryRow(ctx, sql, linkID).
+		Scan(&link.ID, &link.TodoID, &link.CreatedBy, &link.ExpiresAt, &link.RevokedAt, &link.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, app_errors.ErrShareLinkNotFound
+		}
+		return nil, fmt.Errorf("[GetShareLink] select: %w", err)
+	}
+
+	return &link, nil
+}
+
+func (r *TodoRepository) GetShareLinks(ctx context.Context, todoID uuid.UUID) ([]models.ShareLinkDAO, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.GetShareLinks")
+	defer span.Finish()
+
+	var links = make([]models.ShareLinkDAO, 0)
+
+	sql := `
+        SELECT
+            id,
+			todo_id,
+			created_by,
+			expires_at,
+			revoked_at,
+			created_at
+        FROM
+            share_links
+        WHERE
+            todo_id = $1
+        ORDER BY
+            created_at DESC
+    `
+	rows, err := r.conn.Query(ctx, sql, todoID)
+	if err != nil {
+		return nil, fmt.Errorf("[GetShareLinks] query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var link models.ShareLinkDAO
+		err := rows.Scan(&link.ID, &link.TodoID, &link.CreatedBy, &link.ExpiresAt, &link.RevokedAt, &link.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("[GetShareLinks] scan: %w", err)
+		}
+		links = append(links, link)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("[GetShareLinks] rows: %w", err)
+	}
+
+	return links, nil
+}
+
+// RevokeShareLink отзывает ссылку, повторный отзыв не меняет время первого
+func (r *TodoRepository) RevokeShareLink(ctx context.Context, linkID uuid.UUID) error {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.RevokeShareLink")
+	defer span.Finish()
+
+	sql := `
+	UPDATE
+		share_links
+	SET
+	    revoked_at = now()
+	WHERE
+	    id = $1 AND revoked_at IS NULL
+	`
+
+	_, err := r.conn.Exec(ctx, sql, linkID)
+	if err != nil {
+		return fmt.Errorf("[RevokeShareLink] update: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,213 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/opentracing/opentracing-go"
+	"time"
+	"todo/internal/app_errors"
+	"todo/internal/models"
+)
+
+// ApplyUserDeletion одной транзакцией обрабатывает задачи удаленного пользователя по policy, удаляет его
+// упоминания и сохраненные фильтры и отзывает выданные им ссылки. Строки до изменений сохраняются
+// в user_deletion_backups, чтобы RevertUserDeletion мог откатить удаление. Удаление, которое уже было
+// обработано или отменено, второй раз не применяется
+func (r *TodoRepository) ApplyUserDeletion(ctx context.Context, deletion *models.UserDeletedEvent, policy string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.ApplyUserDeletion")
+	defer span.Finish()
+
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("[ApplyUserDeletion] begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sql := `
+        INSERT INTO
+			user_deletions (deletion_id, user_id, policy)
+        VALUES
+			($1, $2, $3)
+        ON CONFLICT (deletion_id) DO NOTHING
+    `
+	tag, err := tx.Exec(ctx, sql, deletion.DeletionID, deletion.UserID, policy)
+	if err != nil {
+		return fmt.Errorf("[ApplyUserDeletion] insert deletion: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return nil
+	}
+
+	userID := deletion.UserID
+
+	// копии строк снимаются до изменений, удаленные по policy задачи уносят с собой упоминания и ссылки
+	statements := []userDeletionStatement{
+		{`INSERT INTO user_deletion_backups (deletion_id, table_name, data)
+            SELECT $1, 'todos', to_jsonb(t) FROM todos t
+            WHERE t.created_by = $2 OR t.assignee = $2`, []interface{}{deletion.DeletionID, userID}},
+		{`INSERT INTO user_deletion_backups (deletion_id, table_name, data)
+            SELECT $1, 'todo_mentions', to_jsonb(m) FROM todo_mentions m
+            WHERE m.user_id = $2 OR m.todo_id IN (SELECT id FROM todos WHERE created_by = $2 OR assignee = $2)`, []interface{}{deletion.DeletionID, userID}},
+		{`INSERT INTO user_deletion_backups (deletion_id, table_name, data)
+            SELECT $1, 'share_links', to_jsonb(l) FROM share_links l
+            WHERE l.created_by = $2 OR l.todo_id IN (SELECT id FROM todos WHERE created_by = $2 OR assignee = $2)`, []interface{}{deletion.DeletionID, userID}},
+		{`INSERT INTO user_deletion_backups (deletion_id, table_name, data)
+            SELECT $1, 'saved_filters', to_jsonb(f) FROM saved_filters f
+            WHERE f.owner_id = $2`, []interface{}{deletion.DeletionID, userID}},
+	}
+
+	switch policy {
+	case models.UserDeletionPolicyReassign:
+		statements = append(statements,
+			userDeletionStatement{`DELETE FROM todos WHERE created_by = $1 AND assignee = $1`, []interface{}{userID}},
+			userDeletionStatement{`UPDATE todos SET assignee = created_by, updated_at = now() WHERE assignee = $1`, []interface{}{userID}},
+			userDeletionStatement{`UPDATE todos SET created_by = assignee, updated_at = now() WHERE created_by = $1`, []interface{}{userID}},
+		)
+	case models.UserDeletionPolicyDelete:
+		statements = append(statements,
+			userDeletionStatement{`DELETE FROM todos WHERE created_by = $1 OR assignee = $1`, []interface{}{userID}},
+		)
+	case models.UserDeletionPolicyAnonymize:
+		statements = append(statements,
+			userDeletionStatement{`UPDATE todos SET created_by = $2, updated_at = now() WHERE created_by = $1`, []interface{}{userID, models.DeletedUserID}},
+			userDeletionStatement{`UPDATE todos SET assignee = $2, updated_at = now() WHERE assignee = $1`, []interface{}{userID, models.DeletedUserID}},
+		)
+	default:
+		return fmt.Errorf("[ApplyUserDeletion] %w: %q", app_errors.ErrUnknownUserDeletionPolicy, policy)
+	}
+
+	statements = append(statements,
+		userDeletionStatement{`DELETE FROM todo_mentions WHERE user_id = $1`, []interface{}{userID}},
+		userDeletionStatement{`DELETE FROM saved_filters WHERE owner_id = $1`, []interface{}{userID}},
+		userDeletionStatement{`UPDATE share_links SET revoked_at = now() WHERE created_by = $1 AND revoked_at IS NULL`, []interface{}{userID}},
+	)
+
+	for _, statement := range statements {
+		if _, err := tx.Exec(ctx, statement.sql, statement.args...); err != nil {
+			return fmt.Errorf("[ApplyUserDeletion] exec %q: %w", statement.sql, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("[ApplyUserDeletion] commit: %w", err)
+	}
+
+	return nil
+}
+
+// RevertUserDeletion одной транзакцией возвращает строки, измененные удалением пользователя, из user_deletion_backups
+// и отмечает удаление отмененным. Упоминания и ссылки задач, которые с тех пор удалили, не возвращаются.
+// Если событие об удалении еще не приходило, удаление сразу отмечается отмененным и потом не применяется
+func (r *TodoRepository) RevertUserDeletion(ctx context.Context, deletion *models.UserDeletedEvent) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.RevertUserDeletion")
+	defer span.Finish()
+
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("[RevertUserDeletion] begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var cancelledAt *time.Time
+	sql := `
+        SELECT
+            cancelled_at
+        FROM
+            user_deletions
+        WHERE
+            deletion_id = $1
+        FOR UPDATE
+    `
+	err = tx.QueryRow(ctx, sql, deletion.DeletionID).Scan(&cancelledAt)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		sql = `
+            INSERT INTO
+				user_deletions (deletion_id, user_id, policy, cancelled_at)
+            VALUES
+				($1, $2, '', now())
+            ON CONFLICT (deletion_id) DO NOTHING
+        `
+		if _, err := tx.Exec(ctx, sql, deletion.DeletionID, deletion.UserID); err != nil {
+			return fmt.Errorf("[RevertUserDeletion] insert deletion: %w", err)
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return fmt.Errorf("[RevertUserDeletion] commit: %w", err)
+		}
+		return nil
+	case err != nil:
+		return fmt.Errorf("[RevertUserDeletion] get deletion: %w", err)
+	case cancelledAt != nil:
+		return nil
+	}
+
+	// задачи возвращаются первыми, на них ссылаются упоминания и ссылки. Ссылки, которые с тех пор отозвал
+	// их автор, остаются отозванными
+	statements := []userDeletionStatement{
+		{`INSERT INTO todos
+            SELECT t.* FROM user_deletion_backups b, jsonb_populate_record(NULL::todos, b.data) t
+            WHERE b.deletion_id = $1 AND b.table_name = 'todos'
+            ON CONFLICT (id) DO UPDATE SET
+                created_by = EXCLUDED.created_by,
+                assignee = EXCLUDED.assignee,
+                updated_at = EXCLUDED.updated_at`, []interface{}{deletion.DeletionID}},
+		{`INSERT INTO todo_mentions
+            SELECT m.* FROM user_deletion_backups b, jsonb_populate_record(NULL::todo_mentions, b.data) m
+            WHERE b.deletion_id = $1 AND b.table_name = 'todo_mentions'
+                AND EXISTS (SELECT 1 FROM todos WHERE id = m.todo_id)
+            ON CONFLICT DO NOTHING`, []interface{}{deletion.DeletionID}},
+		{`INSERT INTO share_links
+            SELECT l.* FROM user_deletion_backups b, jsonb_populate_record(NULL::share_links, b.data) l
+            WHERE b.deletion_id = $1 AND b.table_name = 'share_links'
+                AND EXISTS (SELECT 1 FROM todos WHERE id = l.todo_id)
+            ON CONFLICT (id) DO UPDATE SET
+                revoked_at = EXCLUDED.revoked_at
+            WHERE share_links.created_by = $2`, []interface{}{deletion.DeletionID, deletion.UserID}},
+		{`INSERT INTO saved_filters
+            SELECT f.* FROM user_deletion_backups b, jsonb_populate_record(NULL::saved_filters, b.data) f
+            WHERE b.deletion_id = $1 AND b.table_name = 'saved_filters'
+            ON CONFLICT DO NOTHING`, []interface{}{deletion.DeletionID}},
+		{`DELETE FROM user_deletion_backups WHERE deletion_id = $1`, []interface{}{deletion.DeletionID}},
+		{`UPDATE user_deletions SET cancelled_at = now() WHERE deletion_id = $1`, []interface{}{deletion.DeletionID}},
+	}
+
+	for _, statement := range statements {
+		if _, err := tx.Exec(ctx, statement.sql, statement.args...); err != nil {
+			return fmt.Errorf("[RevertUserDeletion] exec %q: %w", statement.sql, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("[RevertUserDeletion] commit: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteUserDeletionBackup стирает копии строк, измененных удалением пользователя. Вызывается, когда users
+// завершил удаление и откатывать его больше не нужно
+func (r *TodoRepository) DeleteUserDeletionBackup(ctx context.Context, deletionID uuid.UUID) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.DeleteUserDeletionBackup")
+	defer span.Finish()
+
+	sql := `
+        DELETE FROM
+            user_deletion_backups
+        WHERE
+            deletion_id = $1
+    `
+	if _, err := r.conn.Exec(ctx, sql, deletionID); err != nil {
+		return fmt.Errorf("[DeleteUserDeletionBackup] delete backup: %w", err)
+	}
+
+	return nil
+}
+
+// userDeletionStatement - запрос, который выполняется при удалении пользователя, и его аргументы
+type userDeletionStatement struct {
+	sql  string
+	args []interface{}
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/opentracing/opentracing-go"
+	"todo/internal/models"
+)
+
+// GetUserTodos возвращает все задачи, которые пользователь создал или исполняет, для выгрузки его данных
+func (r *TodoRepository) GetUserTodos(ctx context.Context, userID int) ([]models.TodoDAO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.GetUserTodos")
+	defer span.Finish()
+
+	sql := `
+        SELECT
+            id,
+			created_by,
+			assignee,
+			title,
+			description,
+			created_at,
+			updated_at
+        FROM
+            todos
+        WHERE
+            created_by = $1 OR assignee = $1
+        ORDER BY
+            created_at
+    `
+	rows, err := r.conn.Query(ctx, sql, userID)
+	if err != nil {
+		return nil, fmt.Errorf("[GetUserTodos] query: %w", err)
+	}
+	defer rows.Close()
+
+	todos := make([]models.TodoDAO, 0)
+	for rows.Next() {
+		var todo models.TodoDAO
+		err := rows.Scan(&todo.ID, &todo.CreatedBy, &todo.Assignee, &todo.Title, &todo.Description, &todo.CreatedAt, &todo.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("[GetUserTodos] scan: %w", err)
+		}
+		todos = append(todos, todo)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("[GetUserTodos] rows: %w", err)
+	}
+
+	return todos, nil
+}
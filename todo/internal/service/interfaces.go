@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"github.com/google/uuid"
+	"time"
 	"todo/internal/models"
 )
 
@@ -12,6 +13,26 @@ type TodoRepository interface {
 	GetToDos(ctx context.Context, todos *models.GetTodosDTO) ([]models.TodoDAO, error)
 	GetToDo(ctx context.Context, todoID uuid.UUID) (*models.TodoDAO, error)
 	DeleteToDo(ctx context.Context, todoID uuid.UUID) error
+	CountToDos(ctx context.Context, todos *models.GetTodosDTO) (int, error)
+
+	GetMentions(ctx context.Context, todoIDs []uuid.UUID) (map[uuid.UUID][]models.MentionDAO, error)
+	SetMentions(ctx context.Context, todoID uuid.UUID, mentions []models.MentionDAO) error
+
+	CreateSavedFilter(ctx context.Context, filter *models.SavedFilterDAO) (*models.SavedFilterDAO, error)
+	UpdateSavedFilter(ctx context.Context, filter *models.SavedFilterDAO) (*models.SavedFilterDAO, error)
+	GetSavedFilter(ctx context.Context, filterID uuid.UUID) (*models.SavedFilterDAO, error)
+	GetSavedFilters(ctx context.Context, userID int) ([]models.SavedFilterDAO, error)
+	DeleteSavedFilter(ctx context.Context, filterID uuid.UUID) error
+
+	CreateShareLink(ctx context.Context, link *models.ShareLinkDAO) (*models.ShareLinkDAO, error)
+	GetShareLink(ctx context.Context, linkID uuid.UUID) (*models.ShareLinkDAO, error)
+	GetShareLinks(ctx context.Context, todoID uuid.UUID) ([]models.ShareLinkDAO, error)
+	RevokeShareLink(ctx context.Context, linkID uuid.UUID) error
+
+	ApplyUserDeletion(ctx context.Context, deletion *models.UserDeletedEvent, policy string) error
+	RevertUserDeletion(ctx context.Context, deletion *models.UserDeletedEvent) error
+	DeleteUserDeletionBackup(ctx context.Context, deletionID uuid.UUID) error
+	GetUserTodos(ctx context.Context, userID int) ([]models.TodoDAO, error)
 }
 
 type RabbitProducer interface {
@@ -19,5 +40,16 @@ type RabbitProducer interface {
 }
 
 type UsersServiceClient interface {
-	GetUserByID(ctx context.Context, userID int) (*models.UserDTO, error)
+	GetUsersByIDs(ctx context.Context, userIDs []int) (map[int]*models.UserDTO, error)
+	InvalidateUser(userID int)
+	GetUsersByUsernames(ctx context.Context, usernames []string) (map[string]*models.UserDTO, error)
+}
+
+type MarkdownRenderer interface {
+	Render(source string) (string, error)
+}
+
+type ShareTokenSigner interface {
+	Sign(id uuid.UUID, expiresAt time.Time) string
+	Verify(token string, now time.Time) (uuid.UUID, error)
 }
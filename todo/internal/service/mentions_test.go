@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"todo/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// mentionsRepo хранит упоминания одной задачи. Остальные методы не вызываются
+type mentionsRepo struct {
+	TodoRepository
+	mentions []models.MentionDAO
+}
+
+func (r *mentionsRepo) GetMentions(_ context.Context, todoIDs []uuid.UUID) (map[uuid.UUID][]models.MentionDAO, error) {
+	return map[uuid.UUID][]models.MentionDAO{todoIDs[0]: r.mentions}, nil
+}
+
+func (r *mentionsRepo) SetMentions(_ context.Context, _ uuid.UUID, mentions []models.MentionDAO) error {
+	r.mentions = mentions
+	return nil
+}
+
+type mentionsUsersClient struct {
+	UsersServiceClient
+	users []*models.UserDTO
+}
+
+func (c *mentionsUsersClient) GetUsersByUsernames(_ context.Context, usernames []string) (map[string]*models.UserDTO, error) {
+	usersByName := make(map[string]*models.UserDTO)
+	for _, username := range usernames {
+		for _, user := range c.users {
+			if strings.EqualFold(user.Username, username) {
+				usersByName[strings.ToLower(username)] = user
+			}
+		}
+	}
+	return usersByName, nil
+}
+
+type recordingProducer struct {
+	items []models.TodoMailItem
+}
+
+func (p *recordingProducer) Publish(data []byte, _ string) error {
+	var item models.TodoMailItem
+	if err := json.Unmarshal(data, &item); err != nil {
+		return err
+	}
+	p.items = append(p.items, item)
+	return nil
+}
+
+func TestUpdateMentionsRecipients(t *testing.T) {
+	const (
+		creatorID  = 1
+		assigneeID = 2
+		readerID   = 3
+		otherID    = 4
+	)
+	users := []*models.UserDTO{
+		{ID: creatorID, Username: "creator", Email: "creator@example.com"},
+		{ID: assigneeID, Username: "assignee", Email: "assignee@example.com"},
+		{ID: readerID, Username: "auditor", Email: "auditor@example.com", Permissions: []string{models.PermissionTodosRead}},
+		{ID: otherID, Username: "bob", Email: "bob@example.com"},
+	}
+
+	// адресаты письма: полный текст и уведомление без содержимого
+	type mail struct {
+		readers []int
+		others  []int
+	}
+
+	tests := []struct {
+		name        string
+		description string
+		existing    []int
+		want        mail
+	}{
+		{"non-participant gets a notice", "ping @bob", nil, mail{others: []int{otherID}}},
+		{"reader gets the todo", "ping @auditor", nil, mail{readers: []int{readerID}}},
+		{"participants get no mention mail", "@creator @assignee", nil, mail{}},
+		{"everyone at once", "@creator @bob @auditor @assignee", nil, mail{readers: []int{readerID}, others: []int{otherID}}},
+		{"repeated mention is not mailed again", "@bob @auditor", []int{otherID}, mail{readers: []int{readerID}}},
+		{"unknown user", "@nobody", nil, mail{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mentionsRepo{}
+			for _, userID := range tt.existing {
+				repo.mentions = append(repo.mentions, models.MentionDAO{UserID: userID})
+			}
+			producer := &recordingProducer{}
+			s := &TodoService{
+				todoRepo:           repo,
+				todoRabbitProducer: producer,
+				userServiceClient:  &mentionsUsersClient{users: users},
+			}
+
+			todo := &models.TodoDTO{
+				ID:              uuid.New(),
+				CreatedBy:       creatorID,
+				Assignee:        assigneeID,
+				Title:           "secret title",
+				Description:     tt.description,
+				DescriptionHTML: "<p>" + tt.description + "</p>",
+			}
+			if err := s.updateMentions(context.Background(), todo, "assignee"); err != nil {
+				t.Fatalf("updateMentions error: %v", err)
+			}
+
+			var got mail
+			for _, item := range producer.items {
+				if item.TodoEventType != models.TodoEventTypeMentionTodo {
+					t.Errorf("event type = %q, want %q", item.TodoEventType, models.TodoEventTypeMentionTodo)
+				}
+
+				ids := make([]int, len(item.Recipients))
+				for i, recipient := range item.Recipients {
+					ids[i] = recipient.UserID
+				}
+
+				if !item.ContentHidden {
+					got.readers = append(got.readers, ids...)
+					continue
+				}
+				got.others = append(got.others, ids...)
+				if item.Title != "" || item.Description != "" || item.DescriptionHTML != "" || item.AssigneeName != "" {
+					t.Errorf("notice for %v contains the todo: %+v", ids, item)
+				}
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mention mail = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
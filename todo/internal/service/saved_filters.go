@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go"
+	"strings"
+	"time"
+	"todo/internal/app_errors"
+	"todo/internal/models"
+	"todo/internal/query"
+	"todo/pkg/ctxutil"
+	"unicode/utf8"
+)
+
+func (s *TodoService) CreateSavedFilter(ctx context.Context, newFilter *models.SavedFilterDTO) (*models.SavedFilterDTO, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.CreateSavedFilter")
+	defer span.Finish()
+
+	if newFilter.CurrentUserID != 0 {
+		newFilter.OwnerID = newFilter.CurrentUserID
+	}
+
+	if err := prepareSavedFilter(newFilter); err != nil {
+		return nil, fmt.Errorf("[CreateSavedFilter] check filter: %w", err)
+	}
+
+	createdFilter, err := s.todoRepo.CreateSavedFilter(ctx, newFilter.ToDAO())
+	if err != nil {
+		return nil, fmt.Errorf("[CreateSavedFilter] create filter: %w", err)
+	}
+
+	caller := newFilter.Caller()
+	caller.UserID = newFilter.OwnerID
+
+	response, err := s.countSavedFilter(ctx, createdFilter, caller, newFilter.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("[CreateSavedFilter] count todos: %w", err)
+	}
+
+	return response, nil
+}
+
+func (s *TodoService) UpdateSavedFilter(ctx context.Context, newFilter *models.SavedFilterDTO) (*models.SavedFilterDTO, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.UpdateSavedFilter")
+	defer span.Finish()
+
+	existedFilter, err := s.todoRepo.GetSavedFilter(ctx, newFilter.ID)
+	if err != nil {
+		return nil, fmt.Errorf("[UpdateSavedFilter] get filter: %w", err)
+	}
+
+	// менять фильтр может только его владелец, даже если фильтр общий
+	if existedFilter.OwnerID != newFilter.CurrentUserID {
+		return nil, fmt.Errorf("[UpdateSavedFilter] %w", app_errors.ErrSavedFilterForbidden)
+	}
+
+	if err := prepareSavedFilter(newFilter); err != nil {
+		return nil, fmt.Errorf("[UpdateSavedFilter] check filter: %w", err)
+	}
+
+	existedFilter.Name = newFilter.Name
+	existedFilter.Shared = newFilter.Shared
+	existedFilter.Criteria = newFilter.Criteria
+	existedFilter.UpdatedAt = time.Now()
+
+	updatedFilter, err := s.todoRepo.UpdateSavedFilter(ctx, existedFilter)
+	if err != nil {
+		return nil, fmt.Errorf("[UpdateSavedFilter] update filter: %w", err)
+	}
+
+	response, err := s.countSavedFilter(ctx, updatedFilter, newFilter.Caller(), newFilter.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("[UpdateSavedFilter] count todos: %w", err)
+	}
+
+	return response, nil
+}
+
+func (s *TodoService) DeleteSavedFilter(ctx context.Context, filterID uuid.UUID, currentUserID int) error {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.DeleteSavedFilter")
+	defer span.Finish()
+
+	existedFilter, err := s.todoRepo.GetSavedFilter(ctx, filterID)
+	if err != nil {
+		return fmt.Errorf("[DeleteSavedFilter] get filter: %w", err)
+	}
+
+	if existedFilter.OwnerID != currentUserID {
+		return fmt.Errorf("[DeleteSavedFilter] %w", app_errors.ErrSavedFilterForbidden)
+	}
+
+	err = s.todoRepo.DeleteSavedFilter(ctx, filterID)
+	if err != nil {
+		return fmt.Errorf("[DeleteSavedFilter] delete filter: %w", err)
+	}
+
+	return nil
+}
+
+// GetSavedFilters возвращает фильтры пользователя и общие фильтры вместе с текущим количеством задач в каждом
+func (s *TodoService) GetSavedFilters(ctx context.Context, caller models.Caller, timezone string) ([]models.SavedFilterDTO, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.GetSavedFilters")
+	defer span.Finish()
+
+	existedFilters, err := s.todoRepo.GetSavedFilters(ctx, caller.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("[GetSavedFilters] get filters: %w", err)
+	}
+
+	response := make([]models.SavedFilterDTO, len(existedFilters))
+	for i := range existedFilters {
+		filter, err := s.countSavedFilter(ctx, &existedFilters[i], caller, timezone)
+		if err != nil {
+			return nil, fmt.Errorf("[GetSavedFilters] count todos: %w", err)
+		}
+		response[i] = *filter
+	}
+
+	return response, nil
+}
+
+// RunSavedFilter возвращает задачи, подходящие под сохраненный фильтр. "me" в выражении фильтра
+// означает пользователя, который его запускает, поэтому общий фильтр у каждого показывает свои задачи.
+// Даты выражения и видимость задач тоже определяются по запускающему.
+func (s *TodoService) RunSavedFilter(ctx context.Context, filterID uuid.UUID, caller models.Caller, timezone string) ([]models.TodoDTO, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.RunSavedFilter")
+	defer span.Finish()
+
+	existedFilter, err := s.todoRepo.GetSavedFilter(ctx, filterID)
+	if err != nil {
+		return nil, fmt.Errorf("[RunSavedFilter] get filter: %w", err)
+	}
+
+	if !existedFilter.Shared && existedFilter.OwnerID != caller.UserID {
+		return nil, fmt.Errorf("[RunSavedFilter] %w", app_errors.ErrSavedFilterForbidden)
+	}
+
+	criteria := existedFilter.Criteria
+	criteria.CurrentUserID = caller.UserID
+	criteria.CurrentUserPermissions = caller.Permissions
+	criteria.Timezone = timezone
+
+	response, err := s.GetToDos(ctx, &criteria)
+	if err != nil {
+		return nil, fmt.Errorf("[RunSavedFilter] get todos: %w", err)
+	}
+
+	return response, nil
+}
+
+// countSavedFilter переводит фильтр в DTO и считает подходящие под него задачи, которые видны caller
+func (s *TodoService) countSavedFilter(ctx context.Context, filter *models.SavedFilterDAO, caller models.Caller, timezone string) (*models.SavedFilterDTO, error) {
+	criteria := filter.Criteria
+	criteria.CurrentUserID = caller.UserID
+	criteria.CurrentUserPermissions = caller.Permissions
+	criteria.Timezone = timezone
+
+	node, err := query.Parse(criteria.Query)
+	if err != nil {
+		return nil, err
+	}
+	criteria.Filter = node
+
+	if err := restrictToVisible(&criteria); err != nil {
+		return nil, err
+	}
+
+	count, err := s.todoRepo.CountToDos(ctx, &criteria)
+	if err != nil {
+		return nil, err
+	}
+
+	response := filter.ToDTO()
+	response.Count = count
+
+	return response, nil
+}
+
+// prepareSavedFilter проверяет название и выражение фильтра перед сохранением
+func prepareSavedFilter(filter *models.SavedFilterDTO) error {
+	filter.Name = strings.TrimSpace(filter.Name)
+	if filter.Name == "" {
+		return app_errors.ErrSavedFilterNameEmpty
+	}
+
+	if utf8.RuneCountInString(filter.Name) > models.SavedFilterNameMaxLength {
+		return app_errors.ErrSavedFilterNameTooLong
+	}
+
+	// выражение проверяется при сохранении, чтобы ошибка с позицией пришла сразу, а не при запуске
+	if _, err := query.Parse(filter.Criteria.Query); err != nil {
+		return err
+	}
+
+	// "me" сохраняется как есть и подставляется при каждом запуске
+	filter.Criteria.CurrentUserID = 0
+	filter.Criteria.Filter = nil
+
+	return nil
+}
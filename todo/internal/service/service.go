@@ -7,18 +7,27 @@ import (
 	"github.com/google/uuid"
 	"github.com/opentracing/opentracing-go"
 	"github.com/rs/zerolog"
+	"strings"
 	"time"
 	"todo/config"
+	"todo/internal/app_errors"
+	"todo/internal/mentions"
 	"todo/internal/models"
+	"todo/internal/query"
 	"todo/pkg/ctxutil"
+	"unicode/utf8"
 )
 
 type TodoService struct {
-	todoRepo           TodoRepository
-	cfg                *config.Config
-	logger             *zerolog.Logger
-	todoRabbitProducer RabbitProducer
-	userServiceClient  UsersServiceClient
+	todoRepo             TodoRepository
+	cfg                  *config.Config
+	logger               *zerolog.Logger
+	todoRabbitProducer   RabbitProducer
+	userDeletionProducer RabbitProducer
+	userExportProducer   RabbitProducer
+	userServiceClient    UsersServiceClient
+	markdownRenderer     MarkdownRenderer
+	shareTokenSigner     ShareTokenSigner
 }
 
 func NewTodoService(
@@ -27,48 +36,81 @@ func NewTodoService(
 	logger *zerolog.Logger,
 	userServiceClient UsersServiceClient,
 	todoRabbitProducer RabbitProducer,
+	userDeletionProducer RabbitProducer,
+	userExportProducer RabbitProducer,
+	markdownRenderer MarkdownRenderer,
+	shareTokenSigner ShareTokenSigner,
 ) *TodoService {
 	return &TodoService{
-		todoRepo:           todoRepo,
-		cfg:                cfg,
-		userServiceClient:  userServiceClient,
-		todoRabbitProducer: todoRabbitProducer,
-		logger:             logger,
+		todoRepo:             todoRepo,
+		cfg:                  cfg,
+		userServiceClient:    userServiceClient,
+		todoRabbitProducer:   todoRabbitProducer,
+		userDeletionProducer: userDeletionProducer,
+		userExportProducer:   userExportProducer,
+		logger:               logger,
+		markdownRenderer:     markdownRenderer,
+		shareTokenSigner:     shareTokenSigner,
 	}
 }
 
-func (s *TodoService) CreateToDo(ctx context.Context, newTodo *models.TodoDTO) (*models.TodoDTO, error) {
+// CreateToDo создает задачу от имени caller. Создателем задачи всегда записывается caller,
+// а если исполнитель не указан, задача назначается создателю.
+func (s *TodoService) CreateToDo(ctx context.Context, newTodo *models.TodoDTO, caller models.Caller) (*models.TodoDTO, error) {
 	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
 
 	span, ctx := opentracing.StartSpanFromContext(ctx, "service.CreateToDo")
 	defer span.Finish()
 
+	// у внутреннего API нет пользователя, создатель берется из запроса
+	if caller.UserID != 0 {
+		newTodo.CreatedBy = caller.UserID
+	}
+	if newTodo.Assignee == 0 {
+		newTodo.Assignee = newTodo.CreatedBy
+	}
+
+	usersByID, err := s.findUsers(ctx, newTodo.Assignee, newTodo.CreatedBy)
+	if err != nil {
+		return nil, fmt.Errorf("[CreateToDo] get users by ids:%w", err)
+	}
+	user := usersByID[newTodo.Assignee]
+	if user == nil {
+		return nil, fmt.Errorf("[CreateToDo] %w", app_errors.ErrAssigneeNotFound)
+	}
+
+	title, err := normalizeTitle(newTodo.Title, newTodo.Description)
+	if err != nil {
+		return nil, fmt.Errorf("[CreateToDo] check title: %w", err)
+	}
+	newTodo.Title = title
+
 	createdTodo, err := s.todoRepo.CreateToDo(ctx, newTodo.ToDAO())
 	if err != nil {
 		return nil, fmt.Errorf("[CreateToDo] create todo: %w", err)
 	}
 
-	var receivers = make([]string, 0, 2)
-
-	user, err := s.userServiceClient.GetUserByID(ctx, createdTodo.Assignee)
+	response, err := s.renderTodo(createdTodo)
 	if err != nil {
-		return nil, fmt.Errorf("[CreateToDo] get user by id:%w", err)
+		return nil, fmt.Errorf("[CreateToDo] render todo: %w", err)
 	}
-	receivers = append(receivers, user.Email)
+
+	var recipients = make([]models.TodoMailRecipient, 0, 2)
+	recipients = append(recipients, models.TodoMailRecipient{UserID: user.ID, Email: user.Email})
 
 	if createdTodo.Assignee != createdTodo.CreatedBy {
-		userCreatedBy, err := s.userServiceClient.GetUserByID(ctx, createdTodo.CreatedBy)
-		if err != nil {
-			return nil, fmt.Errorf("[CreateToDo] get user by id:%w", err)
+		if userCreatedBy := usersByID[createdTodo.CreatedBy]; userCreatedBy != nil {
+			recipients = append(recipients, models.TodoMailRecipient{UserID: userCreatedBy.ID, Email: userCreatedBy.Email})
 		}
-		receivers = append(receivers, userCreatedBy.Email)
 	}
 
 	data, err := json.Marshal(models.TodoMailItem{
-		TodoEventType: models.TodoEventTypeCreateTodo,
-		Receivers:     receivers,
-		AssigneeName:  user.Username,
-		Description:   createdTodo.Description,
+		TodoEventType:   models.TodoEventTypeCreateTodo,
+		Recipients:      recipients,
+		AssigneeName:    user.Username,
+		Title:           response.Title,
+		Description:     response.Description,
+		DescriptionHTML: response.DescriptionHTML,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("[CreateToDo] marshal new todo mssg:%w", err)
@@ -80,51 +122,99 @@ func (s *TodoService) CreateToDo(ctx context.Context, newTodo *models.TodoDTO) (
 		return nil, fmt.Errorf("[CreateToDo] publish new todo letter mssg:%w", err)
 	}
 
-	return createdTodo.ToDTO(), nil
+	err = s.updateMentions(ctx, response, user.Username)
+	if err != nil {
+		return nil, fmt.Errorf("[CreateToDo] update mentions: %w", err)
+	}
+
+	return response, nil
 }
 
-func (s *TodoService) UpdateToDo(ctx context.Context, newTodo *models.TodoDTO) (*models.TodoDTO, error) {
+// UpdateToDo изменяет задачу. Изменять задачу могут ее создатель и исполнитель, а передать ее
+// другому исполнителю - только создатель. Исполнитель, равный нулю, остается прежним.
+func (s *TodoService) UpdateToDo(ctx context.Context, newTodo *models.TodoDTO, caller models.Caller) (*models.TodoDTO, error) {
 	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
 
 	span, ctx := opentracing.StartSpanFromContext(ctx, "service.UpdateToDo")
 	defer span.Finish()
 
-	existedTodo, err := s.todoRepo.GetToDo(ctx, newTodo.ID)
+	existedTodo, err := s.getVisibleToDo(ctx, newTodo.ID, caller)
 	if err != nil {
 		return nil, fmt.Errorf("[UpdateToDo] get todo: %w", err)
 	}
 
+	if !caller.CanEdit(existedTodo) {
+		return nil, fmt.Errorf("[UpdateToDo] %w", app_errors.ErrTodoForbidden)
+	}
+
+	if newTodo.Assignee == 0 {
+		newTodo.Assignee = existedTodo.Assignee
+	}
+	reassigned := newTodo.Assignee != existedTodo.Assignee
+	if reassigned && !caller.CanReassign(existedTodo) {
+		return nil, fmt.Errorf("[UpdateToDo] reassign: %w", app_errors.ErrTodoForbidden)
+	}
+
+	// исполнитель нужен и для проверки при передаче задачи, и для письма об изменении
+	usersByID, err := s.findUsers(ctx, newTodo.Assignee)
+	if err != nil {
+		return nil, fmt.Errorf("[UpdateToDo] get users by ids:%w", err)
+	}
+	user := usersByID[newTodo.Assignee]
+	if reassigned && user == nil {
+		return nil, fmt.Errorf("[UpdateToDo] %w", app_errors.ErrAssigneeNotFound)
+	}
+
+	title, err := normalizeTitle(newTodo.Title, newTodo.Description)
+	if err != nil {
+		return nil, fmt.Errorf("[UpdateToDo] check title: %w", err)
+	}
+
+	existedTodo.Title = title
 	existedTodo.Description = newTodo.Description
 	existedTodo.Assignee = newTodo.Assignee
 	existedTodo.UpdatedAt = time.Now()
 
-	response, err := s.todoRepo.UpdateToDo(ctx, existedTodo)
+	updatedTodo, err := s.todoRepo.UpdateToDo(ctx, existedTodo)
 	if err != nil {
 		return nil, fmt.Errorf("[UpdateToDo] update todo: %w", err)
 	}
 
-	user, err := s.userServiceClient.GetUserByID(ctx, existedTodo.Assignee)
+	response, err := s.renderTodo(updatedTodo)
 	if err != nil {
-		return nil, fmt.Errorf("[UpdateToDo] get user by id:%w", err)
+		return nil, fmt.Errorf("[UpdateToDo] render todo: %w", err)
 	}
 
-	data, err := json.Marshal(models.TodoMailItem{
-		TodoEventType: models.TodoEventTypeUpdateTodo,
-		Receivers:     []string{user.Email},
-		AssigneeName:  user.Username,
-		Description:   existedTodo.Description,
-	})
-	if err != nil {
-		return nil, fmt.Errorf("[UpdateToDo] marshal update todo mssg:%w", err)
+	// у обезличенной задачи исполнителя нет, письмо об изменении отправлять некому
+	var assigneeName string
+	if user != nil {
+		assigneeName = user.Username
+
+		data, err := json.Marshal(models.TodoMailItem{
+			TodoEventType:   models.TodoEventTypeUpdateTodo,
+			Recipients:      []models.TodoMailRecipient{{UserID: user.ID, Email: user.Email}},
+			AssigneeName:    user.Username,
+			Title:           response.Title,
+			Description:     response.Description,
+			DescriptionHTML: response.DescriptionHTML,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("[UpdateToDo] marshal update todo mssg:%w", err)
+		}
+
+		requestID, _ := ctxutil.GetRequestIDFromContext(ctx)
+		err = s.todoRabbitProducer.Publish(data, requestID)
+		if err != nil {
+			return nil, fmt.Errorf("[UpdateToDo] publish update todo letter mssg:%w", err)
+		}
 	}
 
-	requestID, _ := ctxutil.GetRequestIDFromContext(ctx)
-	err = s.todoRabbitProducer.Publish(data, requestID)
+	err = s.updateMentions(ctx, response, assigneeName)
 	if err != nil {
-		return nil, fmt.Errorf("[UpdateToDo] publish update todo letter mssg:%w", err)
+		return nil, fmt.Errorf("[UpdateToDo] update mentions: %w", err)
 	}
 
-	return response.ToDTO(), nil
+	return response, nil
 }
 
 func (s *TodoService) GetToDos(ctx context.Context, todos *models.GetTodosDTO) ([]models.TodoDTO, error) {
@@ -133,56 +223,108 @@ func (s *TodoService) GetToDos(ctx context.Context, todos *models.GetTodosDTO) (
 	span, ctx := opentracing.StartSpanFromContext(ctx, "service.GetToDos")
 	defer span.Finish()
 
+	filter, err := query.Parse(todos.Query)
+	if err != nil {
+		return nil, fmt.Errorf("[GetToDos] parse query: %w", err)
+	}
+	todos.Filter = filter
+
+	if err := restrictToVisible(todos); err != nil {
+		return nil, fmt.Errorf("[GetToDos] %w", err)
+	}
+
 	existedTodos, err := s.todoRepo.GetToDos(ctx, todos)
 	if err != nil {
 		return nil, fmt.Errorf("[GetToDos] get todos: %w", err)
 	}
 
-	response := models.SliceDAOToDTO(&existedTodos)
+	response := make([]models.TodoDTO, len(existedTodos))
+	for i := range existedTodos {
+		todo, err := s.renderTodo(&existedTodos[i])
+		if err != nil {
+			return nil, fmt.Errorf("[GetToDos] render todo: %w", err)
+		}
+		response[i] = *todo
+	}
+
+	err = s.attachMentions(ctx, response)
+	if err != nil {
+		return nil, fmt.Errorf("[GetToDos] get mentions: %w", err)
+	}
 
-	return *response, nil
+	return response, nil
 }
 
-func (s *TodoService) GetToDo(ctx context.Context, todoID uuid.UUID) (*models.TodoDTO, error) {
+func (s *TodoService) GetToDo(ctx context.Context, todoID uuid.UUID, caller models.Caller) (*models.TodoDTO, error) {
 	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
 
 	span, ctx := opentracing.StartSpanFromContext(ctx, "service.GetToDo")
 	defer span.Finish()
 
-	response, err := s.todoRepo.GetToDo(ctx, todoID)
+	existedTodo, err := s.getVisibleToDo(ctx, todoID, caller)
 	if err != nil {
 		return nil, fmt.Errorf("[GetToDo] get todo: %w", err)
 	}
 
-	return response.ToDTO(), nil
+	response, err := s.renderTodo(existedTodo)
+	if err != nil {
+		return nil, fmt.Errorf("[GetToDo] render todo: %w", err)
+	}
+
+	todos := []models.TodoDTO{*response}
+	err = s.attachMentions(ctx, todos)
+	if err != nil {
+		return nil, fmt.Errorf("[GetToDo] get mentions: %w", err)
+	}
+
+	return &todos[0], nil
 }
 
-func (s *TodoService) DeleteToDo(ctx context.Context, todoID uuid.UUID) error {
+// DeleteToDo удаляет задачу. Удалить задачу может только ее создатель.
+func (s *TodoService) DeleteToDo(ctx context.Context, todoID uuid.UUID, caller models.Caller) error {
 	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
 
 	span, ctx := opentracing.StartSpanFromContext(ctx, "service.DeleteToDo")
 	defer span.Finish()
 
-	existedTodo, err := s.todoRepo.GetToDo(ctx, todoID)
+	existedTodo, err := s.getVisibleToDo(ctx, todoID, caller)
 	if err != nil {
 		return fmt.Errorf("[DeleteToDo] get todo: %w", err)
 	}
 
+	if !caller.CanDelete(existedTodo) {
+		return fmt.Errorf("[DeleteToDo] %w", app_errors.ErrTodoForbidden)
+	}
+
+	// исполнитель и текст письма готовятся до удаления: если они не найдутся, задача остается на месте,
+	// и повторный запрос не упрется в NotFound
+	usersByID, err := s.findUsers(ctx, existedTodo.Assignee)
+	if err != nil {
+		return fmt.Errorf("[DeleteToDo] get users by ids:%w", err)
+	}
+
+	deletedTodo, err := s.renderTodo(existedTodo)
+	if err != nil {
+		return fmt.Errorf("[DeleteToDo] render todo: %w", err)
+	}
+
 	err = s.todoRepo.DeleteToDo(ctx, todoID)
 	if err != nil {
 		return fmt.Errorf("[DeleteToDo] delete todo: %w", err)
 	}
 
-	user, err := s.userServiceClient.GetUserByID(ctx, existedTodo.Assignee)
-	if err != nil {
-		return fmt.Errorf("[DeleteToDo] get user by id:%w", err)
+	user := usersByID[existedTodo.Assignee]
+	if user == nil {
+		return nil
 	}
 
 	data, err := json.Marshal(models.TodoMailItem{
-		TodoEventType: models.TodoEventTypeDeleteTodo,
-		Receivers:     []string{user.Email},
-		AssigneeName:  user.Username,
-		Description:   existedTodo.Description,
+		TodoEventType:   models.TodoEventTypeDeleteTodo,
+		Recipients:      []models.TodoMailRecipient{{UserID: user.ID, Email: user.Email}},
+		AssigneeName:    user.Username,
+		Title:           deletedTodo.Title,
+		Description:     deletedTodo.Description,
+		DescriptionHTML: deletedTodo.DescriptionHTML,
 	})
 	if err != nil {
 		return fmt.Errorf("[DeleteToDo] marshal delete todo mssg:%w", err)
@@ -196,3 +338,219 @@ func (s *TodoService) DeleteToDo(ctx context.Context, todoID uuid.UUID) error {
 
 	return nil
 }
+
+// findUsers возвращает найденных пользователей по ID одним запросом. Тех, кого нет, в ответе нет:
+// пользователь мог быть удален, а в обезличенных задачах вместо него записан models.DeletedUserID
+func (s *TodoService) findUsers(ctx context.Context, userIDs ...int) (map[int]*models.UserDTO, error) {
+	lookupIDs := make([]int, 0, len(userIDs))
+	for _, userID := range userIDs {
+		if userID != models.DeletedUserID {
+			lookupIDs = append(lookupIDs, userID)
+		}
+	}
+	if len(lookupIDs) == 0 {
+		return map[int]*models.UserDTO{}, nil
+	}
+
+	return s.userServiceClient.GetUsersByIDs(ctx, lookupIDs)
+}
+
+// getVisibleToDo возвращает задачу, если caller может ее видеть. Чужая задача неотличима
+// от несуществующей, чтобы по ответу нельзя было перебирать ID задач других пользователей.
+func (s *TodoService) getVisibleToDo(ctx context.Context, todoID uuid.UUID, caller models.Caller) (*models.TodoDAO, error) {
+	existedTodo, err := s.todoRepo.GetToDo(ctx, todoID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !caller.CanRead(existedTodo) {
+		return nil, app_errors.ErrTodoNotFound
+	}
+
+	return existedTodo, nil
+}
+
+// restrictToVisible ограничивает выборку задачами, которые пользователь создал или исполняет,
+// если его роль не позволяет видеть все задачи
+func restrictToVisible(todos *models.GetTodosDTO) error {
+	caller := todos.Caller()
+	if caller.ReadsAll() {
+		return nil
+	}
+
+	if caller.UserID == 0 {
+		return app_errors.ErrTodoForbidden
+	}
+	todos.VisibleTo = caller.UserID
+
+	return nil
+}
+
+// renderTodo переводит задачу из DAO в DTO и добавляет отрендеренный из Markdown HTML описания
+func (s *TodoService) renderTodo(todo *models.TodoDAO) (*models.TodoDTO, error) {
+	response := todo.ToDTO()
+
+	descriptionHTML, err := s.markdownRenderer.Render(todo.Description)
+	if err != nil {
+		return nil, err
+	}
+	response.DescriptionHTML = descriptionHTML
+	response.Mentions = make([]string, 0)
+
+	return response, nil
+}
+
+// updateMentions находит в описании задачи упоминания @username, сохраняет тех, кого удалось найти,
+// и отправляет письмо только пользователям, упомянутым впервые. Создатель и исполнитель узнают о задаче
+// из своих писем, поэтому письмо об упоминании им не отправляется. Текст задачи получают только те,
+// кто может ее читать, остальным приходит уведомление без содержимого: упомянуть можно кого угодно,
+// но задачу это им не открывает. Ненайденные имена попадают в UnknownMentions.
+func (s *TodoService) updateMentions(ctx context.Context, todo *models.TodoDTO, assigneeName string) error {
+	existedMentions, err := s.todoRepo.GetMentions(ctx, []uuid.UUID{todo.ID})
+	if err != nil {
+		return fmt.Errorf("[updateMentions] get mentions: %w", err)
+	}
+
+	alreadyMentioned := make(map[int]bool, len(existedMentions[todo.ID]))
+	for _, mention := range existedMentions[todo.ID] {
+		alreadyMentioned[mention.UserID] = true
+	}
+
+	var (
+		resolved = make([]models.MentionDAO, 0)
+		readers  = make([]models.TodoMailRecipient, 0)
+		others   = make([]models.TodoMailRecipient, 0)
+		seen     = make(map[int]bool)
+	)
+
+	usernames := mentions.Parse(todo.Description)
+	usersByName, err := s.userServiceClient.GetUsersByUsernames(ctx, usernames)
+	if err != nil {
+		return fmt.Errorf("[updateMentions] get users by usernames: %w", err)
+	}
+
+	mentionedTodo := &models.TodoDAO{CreatedBy: todo.CreatedBy, Assignee: todo.Assignee}
+
+	todo.Mentions = make([]string, 0)
+	for _, username := range usernames {
+		user := usersByName[strings.ToLower(username)]
+		if user == nil {
+			todo.UnknownMentions = append(todo.UnknownMentions, username)
+			continue
+		}
+
+		// одно и то же имя в разном регистре может указывать на одного пользователя
+		if seen[user.ID] {
+			continue
+		}
+		seen[user.ID] = true
+
+		resolved = append(resolved, models.MentionDAO{
+			TodoID:   todo.ID,
+			UserID:   user.ID,
+			Username: user.Username,
+		})
+		todo.Mentions = append(todo.Mentions, user.Username)
+
+		if alreadyMentioned[user.ID] || user.ID == todo.CreatedBy || user.ID == todo.Assignee {
+			continue
+		}
+
+		recipient := models.TodoMailRecipient{UserID: user.ID, Email: user.Email}
+		if reader := (models.Caller{UserID: user.ID, Permissions: user.Permissions}); reader.CanRead(mentionedTodo) {
+			readers = append(readers, recipient)
+		} else {
+			others = append(others, recipient)
+		}
+	}
+
+	err = s.todoRepo.SetMentions(ctx, todo.ID, resolved)
+	if err != nil {
+		return fmt.Errorf("[updateMentions] set mentions: %w", err)
+	}
+
+	if len(readers) > 0 {
+		err = s.publishMention(ctx, models.TodoMailItem{
+			TodoEventType:   models.TodoEventTypeMentionTodo,
+			Recipients:      readers,
+			AssigneeName:    assigneeName,
+			Title:           todo.Title,
+			Description:     todo.Description,
+			DescriptionHTML: todo.DescriptionHTML,
+		})
+		if err != nil {
+			return fmt.Errorf("[updateMentions] %w", err)
+		}
+	}
+
+	if len(others) > 0 {
+		err = s.publishMention(ctx, models.TodoMailItem{
+			TodoEventType: models.TodoEventTypeMentionTodo,
+			Recipients:    others,
+			ContentHidden: true,
+		})
+		if err != nil {
+			return fmt.Errorf("[updateMentions] %w", err)
+		}
+	}
+
+	return nil
+}
+
+// publishMention отправляет в очередь письмо об упоминании
+func (s *TodoService) publishMention(ctx context.Context, item models.TodoMailItem) error {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return fmt.Errorf("marshal mention todo mssg:%w", err)
+	}
+
+	requestID, _ := ctxutil.GetRequestIDFromContext(ctx)
+	err = s.todoRabbitProducer.Publish(data, requestID)
+	if err != nil {
+		return fmt.Errorf("publish mention todo letter mssg:%w", err)
+	}
+
+	return nil
+}
+
+// attachMentions дополняет задачи сохраненными упоминаниями
+func (s *TodoService) attachMentions(ctx context.Context, todos []models.TodoDTO) error {
+	todoIDs := make([]uuid.UUID, len(todos))
+	for i := range todos {
+		todoIDs[i] = todos[i].ID
+	}
+
+	storedMentions, err := s.todoRepo.GetMentions(ctx, todoIDs)
+	if err != nil {
+		return err
+	}
+
+	for i := range todos {
+		for _, mention := range storedMentions[todos[i].ID] {
+			todos[i].Mentions = append(todos[i].Mentions, mention.Username)
+		}
+	}
+
+	return nil
+}
+
+// normalizeTitle проверяет длину заголовка, а если он не передан - берет первую строку описания
+func normalizeTitle(title, description string) (string, error) {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		title, _, _ = strings.Cut(strings.TrimSpace(description), "\n")
+		title = strings.TrimSpace(strings.TrimLeft(title, "# "))
+
+		if utf8.RuneCountInString(title) > models.TodoTitleMaxLength {
+			title = string([]rune(title)[:models.TodoTitleMaxLength])
+		}
+
+		return title, nil
+	}
+
+	if utf8.RuneCountInString(title) > models.TodoTitleMaxLength {
+		return "", app_errors.ErrTitleTooLong
+	}
+
+	return title, nil
+}
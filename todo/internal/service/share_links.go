@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go"
+	"time"
+	"todo/internal/app_errors"
+	"todo/internal/models"
+	"todo/pkg/ctxutil"
+)
+
+// CreateShareLink выпускает публичную ссылку на задачу. expiresIn равный нулю означает срок по умолчанию.
+func (s *TodoService) CreateShareLink(ctx context.Context, todoID uuid.UUID, currentUserID int, expiresIn time.Duration) (*models.ShareLinkDTO, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.CreateShareLink")
+	defer span.Finish()
+
+	if expiresIn == 0 {
+		expiresIn = s.cfg.ShareLinks.DefaultTTL
+	}
+	if expiresIn < time.Minute || expiresIn > s.cfg.ShareLinks.MaxTTL {
+		return nil, fmt.Errorf("[CreateShareLink] %w", app_errors.ErrShareLinkTTLInvalid)
+	}
+
+	if err := s.checkShareAccess(ctx, todoID, currentUserID); err != nil {
+		return nil, fmt.Errorf("[CreateShareLink] check access: %w", err)
+	}
+
+	// в токене время хранится с точностью до секунды
+	createdLink, err := s.todoRepo.CreateShareLink(ctx, &models.ShareLinkDAO{
+		ID:        uuid.New(),
+		TodoID:    todoID,
+		CreatedBy: currentUserID,
+		ExpiresAt: time.Now().Add(expiresIn).Truncate(time.Second),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("[CreateShareLink] create link: %w", err)
+	}
+
+	return s.signShareLink(createdLink), nil
+}
+
+func (s *TodoService) GetShareLinks(ctx context.Context, todoID uuid.UUID, currentUserID int) ([]models.ShareLinkDTO, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.GetShareLinks")
+	defer span.Finish()
+
+	if err := s.checkShareAccess(ctx, todoID, currentUserID); err != nil {
+		return nil, fmt.Errorf("[GetShareLinks] check access: %w", err)
+	}
+
+	existedLinks, err := s.todoRepo.GetShareLinks(ctx, todoID)
+	if err != nil {
+		return nil, fmt.Errorf("[GetShareLinks] get links: %w", err)
+	}
+
+	response := make([]models.ShareLinkDTO, len(existedLinks))
+	for i := range existedLinks {
+		response[i] = *s.signShareLink(&existedLinks[i])
+	}
+
+	return response, nil
+}
+
+func (s *TodoService) RevokeShareLink(ctx context.Context, linkID uuid.UUID, currentUserID int) error {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.RevokeShareLink")
+	defer span.Finish()
+
+	existedLink, err := s.todoRepo.GetShareLink(ctx, linkID)
+	if err != nil {
+		return fmt.Errorf("[RevokeShareLink] get link: %w", err)
+	}
+
+	// отозвать ссылку может ее автор, а также создатель или исполнитель задачи
+	if existedLink.CreatedBy != currentUserID {
+		if err := s.checkShareAccess(ctx, existedLink.TodoID, currentUserID); err != nil {
+			return fmt.Errorf("[RevokeShareLink] check access: %w", err)
+		}
+	}
+
+	err = s.todoRepo.RevokeShareLink(ctx, linkID)
+	if err != nil {
+		return fmt.Errorf("[RevokeShareLink] revoke link: %w", err)
+	}
+
+	return nil
+}
+
+// GetSharedTodo возвращает задачу по токену публичной ссылки. Поддельный, просроченный и отозванный токены
+// неотличимы для клиента и приводят к ErrShareLinkNotFound.
+func (s *TodoService) GetSharedTodo(ctx context.Context, token string) (*models.SharedTodoDTO, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.GetSharedTodo")
+	defer span.Finish()
+
+	now := time.Now()
+
+	linkID, err := s.shareTokenSigner.Verify(token, now)
+	if err != nil {
+		return nil, fmt.Errorf("[GetSharedTodo] verify token: %w: %s", app_errors.ErrShareLinkNotFound, err)
+	}
+
+	existedLink, err := s.todoRepo.GetShareLink(ctx, linkID)
+	if err != nil {
+		return nil, fmt.Errorf("[GetSharedTodo] get link: %w", err)
+	}
+
+	if existedLink.RevokedAt != nil || !now.Before(existedLink.ExpiresAt) {
+		return nil, fmt.Errorf("[GetSharedTodo] link is no longer active: %w", app_errors.ErrShareLinkNotFound)
+	}
+
+	existedTodo, err := s.todoRepo.GetToDo(ctx, existedLink.TodoID)
+	if err != nil {
+		if errors.Is(err, app_errors.ErrTodoNotFound) {
+			return nil, fmt.Errorf("[GetSharedTodo] get todo: %w", app_errors.ErrShareLinkNotFound)
+		}
+		return nil, fmt.Errorf("[GetSharedTodo] get todo: %w", err)
+	}
+
+	rendered, err := s.renderTodo(existedTodo)
+	if err != nil {
+		return nil, fmt.Errorf("[GetSharedTodo] render todo: %w", err)
+	}
+
+	return &models.SharedTodoDTO{
+		Title:           rendered.Title,
+		DescriptionHTML: rendered.DescriptionHTML,
+		CreatedAt:       rendered.CreatedAt,
+		UpdatedAt:       rendered.UpdatedAt,
+		ExpiresAt:       existedLink.ExpiresAt,
+	}, nil
+}
+
+// checkShareAccess разрешает управлять публичными ссылками только создателю и исполнителю задачи
+func (s *TodoService) checkShareAccess(ctx context.Context, todoID uuid.UUID, currentUserID int) error {
+	existedTodo, err := s.todoRepo.GetToDo(ctx, todoID)
+	if err != nil {
+		return err
+	}
+
+	if currentUserID == 0 || (existedTodo.CreatedBy != currentUserID && existedTodo.Assignee != currentUserID) {
+		return app_errors.ErrTodoForbidden
+	}
+
+	return nil
+}
+
+func (s *TodoService) signShareLink(link *models.ShareLinkDAO) *models.ShareLinkDTO {
+	response := link.ToDTO()
+	response.Token = s.shareTokenSigner.Sign(link.ID, link.ExpiresAt)
+
+	return response
+}
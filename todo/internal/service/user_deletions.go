@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/opentracing/opentracing-go"
+	"todo/internal/models"
+	"todo/pkg/ctxutil"
+)
+
+// HandleUserDeleted обрабатывает задачи удаленного пользователя по политике из события или, если ее нет, из настроек
+// и отвечает сервису users.
+// Если обработать задачи не удалось, users получает отказ и отменяет удаление, поэтому ошибка возвращается,
+// только когда не удалось отправить сам ответ
+func (s *TodoService) HandleUserDeleted(ctx context.Context, event *models.UserDeletedEvent) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.HandleUserDeleted")
+	defer span.Finish()
+
+	reply := models.UserDeletionReply{
+		EventType:  models.UserEventTypeUserDeletionConfirmed,
+		DeletionID: event.DeletionID,
+		UserID:     event.UserID,
+		Service:    models.UserDeletionService,
+	}
+
+	// удаляемый пользователь больше не должен находиться, даже если он еще в кеше
+	s.userServiceClient.InvalidateUser(event.UserID)
+
+	policy := s.cfg.UserDeletion.Policy
+	if models.IsUserDeletionPolicy(event.Policy) {
+		policy = event.Policy
+	}
+
+	err := s.todoRepo.ApplyUserDeletion(ctx, event, policy)
+	if err != nil {
+		s.logger.Error().Msgf("[HandleUserDeleted] apply deletion %s of user %d: %s", event.DeletionID, event.UserID, err)
+
+		reply.EventType = models.UserEventTypeUserDeletionFailed
+		reply.Error = err.Error()
+	}
+
+	data, err := json.Marshal(reply)
+	if err != nil {
+		return fmt.Errorf("[HandleUserDeleted] marshal reply mssg: %w", err)
+	}
+
+	requestID, _ := ctxutil.GetRequestIDFromContext(ctx)
+	err = s.userDeletionProducer.Publish(data, requestID)
+	if err != nil {
+		return fmt.Errorf("[HandleUserDeleted] publish reply mssg: %w", err)
+	}
+
+	return nil
+}
+
+// HandleUserDeletionCancelled откатывает удаление, которое users отменил из-за отказа другого сервиса
+// или потому что ответы не пришли вовремя: задачи, упоминания, ссылки и фильтры пользователя возвращаются
+func (s *TodoService) HandleUserDeletionCancelled(ctx context.Context, event *models.UserDeletedEvent) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.HandleUserDeletionCancelled")
+	defer span.Finish()
+
+	if err := s.todoRepo.RevertUserDeletion(ctx, event); err != nil {
+		return fmt.Errorf("[HandleUserDeletionCancelled] revert deletion: %w", err)
+	}
+
+	return nil
+}
+
+// HandleUserDeletionCompleted стирает копии данных пользователя, сохраненные для отката: удаление завершено
+// и откатываться уже не будет
+func (s *TodoService) HandleUserDeletionCompleted(ctx context.Context, event *models.UserDeletedEvent) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.HandleUserDeletionCompleted")
+	defer span.Finish()
+
+	if err := s.todoRepo.DeleteUserDeletionBackup(ctx, event.DeletionID); err != nil {
+		return fmt.Errorf("[HandleUserDeletionCompleted] delete backup: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/opentracing/opentracing-go"
+	"todo/internal/models"
+	"todo/pkg/ctxutil"
+)
+
+// HandleUserExportRequested собирает задачи, которые пользователь создал или исполняет, и отправляет их сервису users
+// для архива выгрузки. Если собрать задачи не удалось, users получает отказ, поэтому ошибка возвращается,
+// только когда не удалось отправить сам ответ
+func (s *TodoService) HandleUserExportRequested(ctx context.Context, event *models.UserExportRequestedEvent) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.HandleUserExportRequested")
+	defer span.Finish()
+
+	reply := models.UserExportReply{
+		EventType: models.UserEventTypeUserExportData,
+		ExportID:  event.ExportID,
+		UserID:    event.UserID,
+		Service:   models.UserExportService,
+	}
+
+	data, err := s.collectUserTodos(ctx, event.UserID)
+	if err != nil {
+		s.logger.Error().Msgf("[HandleUserExportRequested] collect todos for export %s of user %d: %s", event.ExportID, event.UserID, err)
+
+		reply.EventType = models.UserEventTypeUserExportFailed
+		reply.Error = err.Error()
+	}
+	reply.Data = data
+
+	mssg, err := json.Marshal(reply)
+	if err != nil {
+		return fmt.Errorf("[HandleUserExportRequested] marshal reply mssg: %w", err)
+	}
+
+	requestID, _ := ctxutil.GetRequestIDFromContext(ctx)
+	err = s.userExportProducer.Publish(mssg, requestID)
+	if err != nil {
+		return fmt.Errorf("[HandleUserExportRequested] publish reply mssg: %w", err)
+	}
+
+	return nil
+}
+
+// collectUserTodos возвращает задачи пользователя в том виде, в каком их отдает API, вместе с упоминаниями
+func (s *TodoService) collectUserTodos(ctx context.Context, userID int) (json.RawMessage, error) {
+	existedTodos, err := s.todoRepo.GetUserTodos(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get todos: %w", err)
+	}
+
+	export := models.UserExportData{Todos: make([]models.TodoDTO, len(existedTodos))}
+	for i := range existedTodos {
+		todo, err := s.renderTodo(&existedTodos[i])
+		if err != nil {
+			return nil, fmt.Errorf("render todo: %w", err)
+		}
+		export.Todos[i] = *todo
+	}
+
+	if err := s.attachMentions(ctx, export.Todos); err != nil {
+		return nil, fmt.Errorf("get mentions: %w", err)
+	}
+
+	data, err := json.Marshal(export)
+	if err != nil {
+		return nil, fmt.Errorf("marshal todos: %w", err)
+	}
+
+	return data, nil
+}
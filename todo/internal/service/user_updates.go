@@ -0,0 +1,17 @@
+package service
+
+import (
+	"context"
+	"github.com/opentracing/opentracing-go"
+	"todo/internal/models"
+)
+
+// HandleUserUpdated сбрасывает закешированного пользователя, чтобы письма о задачах уходили на актуальные имя и email
+func (s *TodoService) HandleUserUpdated(ctx context.Context, event *models.UserUpdatedEvent) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "service.HandleUserUpdated")
+	defer span.Finish()
+
+	s.userServiceClient.InvalidateUser(event.UserID)
+
+	return nil
+}
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE todos
+    ADD COLUMN IF NOT EXISTS title VARCHAR(255) NOT NULL DEFAULT '',
+    ALTER COLUMN description TYPE TEXT;
+
+-- заголовком существующих задач станет первая строка описания
+UPDATE todos
+SET title = LEFT(SPLIT_PART(COALESCE(description, ''), E'\n', 1), 255)
+WHERE title = '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE todos
+    DROP COLUMN IF EXISTS title,
+    ALTER COLUMN description TYPE VARCHAR(255) USING LEFT(description, 255);
+-- +goose StatementEnd
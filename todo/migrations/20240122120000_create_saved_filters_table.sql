@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS saved_filters (
+    id          UUID         PRIMARY KEY,
+    owner_id    INTEGER      NOT NULL,
+    name        VARCHAR(255) NOT NULL,
+    shared      BOOLEAN      NOT NULL DEFAULT false,
+    criteria    JSONB        NOT NULL DEFAULT '{}',
+    created_at  TIMESTAMP    NOT NULL DEFAULT now(),
+    updated_at  TIMESTAMP    NOT NULL DEFAULT now(),
+    CONSTRAINT saved_filters_owner_name_key UNIQUE (owner_id, name)
+);
+
+CREATE INDEX IF NOT EXISTS saved_filters_shared_idx ON saved_filters (shared) WHERE shared;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS saved_filters;
+-- +goose StatementEnd
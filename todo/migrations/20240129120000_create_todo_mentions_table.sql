@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS todo_mentions (
+    todo_id     UUID         NOT NULL REFERENCES todos (id) ON DELETE CASCADE,
+    user_id     INTEGER      NOT NULL,
+    username    VARCHAR(255) NOT NULL,
+    created_at  TIMESTAMP    NOT NULL DEFAULT now(),
+    PRIMARY KEY (todo_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS todo_mentions_user_id_idx ON todo_mentions (user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS todo_mentions;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS share_links (
+    id          UUID      PRIMARY KEY,
+    todo_id     UUID      NOT NULL REFERENCES todos (id) ON DELETE CASCADE,
+    created_by  INTEGER   NOT NULL,
+    expires_at  TIMESTAMP NOT NULL,
+    revoked_at  TIMESTAMP,
+    created_at  TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS share_links_todo_id_idx ON share_links (todo_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS share_links;
+-- +goose StatementEnd
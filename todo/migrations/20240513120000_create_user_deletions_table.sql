@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- удаления пользователей, которые сервис уже обработал. Повторно доставленное событие не применяется второй раз
+CREATE TABLE IF NOT EXISTS user_deletions (
+    deletion_id  UUID        PRIMARY KEY,
+    user_id      INTEGER     NOT NULL,
+    policy       VARCHAR(16) NOT NULL,
+    processed_at TIMESTAMP   NOT NULL DEFAULT now()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS user_deletions;
+-- +goose StatementEnd
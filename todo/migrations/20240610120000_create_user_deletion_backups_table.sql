@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+-- строки, которые изменило удаление пользователя, в том виде, какими они были до него. По ним удаление
+-- откатывается, если users его отменил, и они стираются, как только users его завершил
+CREATE TABLE IF NOT EXISTS user_deletion_backups (
+    deletion_id UUID        NOT NULL REFERENCES user_deletions (deletion_id) ON DELETE CASCADE,
+    table_name  VARCHAR(32) NOT NULL,
+    data        JSONB       NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS user_deletion_backups_deletion_id_idx ON user_deletion_backups (deletion_id);
+
+-- отмененное удаление не применяется, даже если событие о нем придет после отмены
+ALTER TABLE user_deletions
+    ADD COLUMN IF NOT EXISTS cancelled_at TIMESTAMP;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE user_deletions
+    DROP COLUMN IF EXISTS cancelled_at;
+
+DROP TABLE IF EXISTS user_deletion_backups;
+-- +goose StatementEnd
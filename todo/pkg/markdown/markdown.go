@@ -0,0 +1,42 @@
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+// Renderer превращает Markdown в HTML, безопасный для вставки в страницы и письма
+type Renderer struct {
+	md     goldmark.Markdown
+	policy *bluemonday.Policy
+}
+
+func NewRenderer() *Renderer {
+	// ссылки открываем в новой вкладке и не передаем referrer
+	policy := bluemonday.UGCPolicy()
+	policy.RequireNoFollowOnLinks(true)
+	policy.AddTargetBlankToFullyQualifiedLinks(true)
+
+	return &Renderer{
+		md: goldmark.New(
+			goldmark.WithExtensions(
+				extension.GFM,
+			),
+		),
+		policy: policy,
+	}
+}
+
+// Render рендерит Markdown и вычищает из результата все, что не разрешено политикой
+func (r *Renderer) Render(source string) (string, error) {
+	var buf bytes.Buffer
+	if err := r.md.Convert([]byte(source), &buf); err != nil {
+		return "", fmt.Errorf("[Render] convert markdown: %w", err)
+	}
+
+	return r.policy.Sanitize(buf.String()), nil
+}
@@ -0,0 +1,76 @@
+package sharetoken
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid share token")
+	ErrExpiredToken = errors.New("share token has expired")
+)
+
+// payloadLength - идентификатор ссылки (16 байт) и время истечения в unix-секундах (8 байт)
+const payloadLength = 16 + 8
+
+// Signer выпускает и проверяет токены публичных ссылок. Токен содержит идентификатор ссылки и срок действия,
+// подписанные HMAC-SHA256, поэтому поддельный или просроченный токен отклоняется без обращения к базе.
+type Signer struct {
+	secret []byte
+}
+
+func NewSigner(secret string) *Signer {
+	return &Signer{secret: []byte(secret)}
+}
+
+// Sign возвращает токен вида <payload>.<signature> в base64url без паддинга
+func (s *Signer) Sign(id uuid.UUID, expiresAt time.Time) string {
+	payload := make([]byte, payloadLength)
+	copy(payload, id[:])
+	binary.BigEndian.PutUint64(payload[16:], uint64(expiresAt.Unix()))
+
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(s.sign(payload))
+}
+
+// Verify проверяет подпись и срок действия токена и возвращает идентификатор ссылки
+func (s *Signer) Verify(token string, now time.Time) (uuid.UUID, error) {
+	encodedPayload, encodedSignature, ok := strings.Cut(token, ".")
+	if !ok {
+		return uuid.Nil, ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil || len(payload) != payloadLength {
+		return uuid.Nil, ErrInvalidToken
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil || !hmac.Equal(signature, s.sign(payload)) {
+		return uuid.Nil, ErrInvalidToken
+	}
+
+	expiresAt := time.Unix(int64(binary.BigEndian.Uint64(payload[16:])), 0)
+	if !now.Before(expiresAt) {
+		return uuid.Nil, ErrExpiredToken
+	}
+
+	id, err := uuid.FromBytes(payload[:16])
+	if err != nil {
+		return uuid.Nil, ErrInvalidToken
+	}
+
+	return id, nil
+}
+
+func (s *Signer) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,105 @@
+package sharetoken
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestVerifyValidToken(t *testing.T) {
+	signer := NewSigner("secret")
+	id := uuid.New()
+	now := time.Date(2024, 2, 5, 12, 0, 0, 0, time.UTC)
+
+	token := signer.Sign(id, now.Add(time.Hour))
+
+	got, err := signer.Verify(token, now)
+	if err != nil {
+		t.Fatalf("Verify error: %v", err)
+	}
+	if got != id {
+		t.Errorf("Verify = %s, want %s", got, id)
+	}
+}
+
+func TestVerifyExpiry(t *testing.T) {
+	signer := NewSigner("secret")
+	expiresAt := time.Date(2024, 2, 5, 12, 0, 0, 0, time.UTC)
+	token := signer.Sign(uuid.New(), expiresAt)
+
+	tests := []struct {
+		name    string
+		now     time.Time
+		wantErr error
+	}{
+		{"second before expiry", expiresAt.Add(-time.Second), nil},
+		{"exactly at expiry", expiresAt, ErrExpiredToken},
+		{"after expiry", expiresAt.Add(time.Hour), ErrExpiredToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := signer.Verify(token, tt.now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Verify error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifyTamperedToken(t *testing.T) {
+	signer := NewSigner("secret")
+	now := time.Date(2024, 2, 5, 12, 0, 0, 0, time.UTC)
+	token := signer.Sign(uuid.New(), now.Add(time.Hour))
+	encodedPayload, encodedSignature, _ := strings.Cut(token, ".")
+
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+
+	// продленный срок действия с прежней подписью
+	extended := append([]byte(nil), payload...)
+	extended[len(extended)-1] ^= 0xff
+	// другая ссылка с прежней подписью
+	otherLink := append([]byte(nil), payload...)
+	otherLink[0] ^= 0xff
+
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil {
+		t.Fatalf("decode signature: %v", err)
+	}
+	flipped := append([]byte(nil), signature...)
+	flipped[0] ^= 0x01
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"empty", ""},
+		{"no separator", encodedPayload + encodedSignature},
+		{"changed expiry", base64.RawURLEncoding.EncodeToString(extended) + "." + encodedSignature},
+		{"changed link id", base64.RawURLEncoding.EncodeToString(otherLink) + "." + encodedSignature},
+		{"changed signature", encodedPayload + "." + base64.RawURLEncoding.EncodeToString(flipped)},
+		{"truncated signature", encodedPayload + "." + encodedSignature[:len(encodedSignature)-2]},
+		{"short payload", base64.RawURLEncoding.EncodeToString(payload[:16]) + "." + encodedSignature},
+		{"not base64", encodedPayload + ".***"},
+		{"signed with another secret", NewSigner("other").Sign(uuid.New(), now.Add(time.Hour))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := signer.Verify(tt.token, now)
+			if !errors.Is(err, ErrInvalidToken) {
+				t.Errorf("Verify error = %v, want %v", err, ErrInvalidToken)
+			}
+			if id != uuid.Nil {
+				t.Errorf("Verify = %s, want nil id", id)
+			}
+		})
+	}
+}
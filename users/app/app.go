@@ -1,19 +1,25 @@
 package app
 
 import (
+	"context"
 	"fmt"
 	"github.com/gorilla/mux"
 	"github.com/opentracing/opentracing-go"
 	"github.com/rs/zerolog"
 	"golang.org/x/sync/errgroup"
+	"os"
+	"time"
 	"users/config"
 	"users/internal/api"
 	"users/internal/api/grpc"
+	"users/internal/api/rabbitmq"
 	"users/internal/api/rest"
 	"users/internal/repository"
 	"users/internal/service"
+	"users/pkg/filestorage"
 	"users/pkg/jaeger"
 	"users/pkg/logging"
+	"users/pkg/passwordpolicy"
 	"users/pkg/postgresql"
 	"users/pkg/rabbitmq/producer"
 )
@@ -23,7 +29,10 @@ type App struct {
 	logger           *zerolog.Logger
 	router           *mux.Router
 	userService      api.UserService
+	deletionService  api.UserDeletionService
+	exportService    api.DataExportService
 	rabbitmqProducer *producer.Producer
+	fileStorage      *filestorage.Storage
 }
 
 func NewApp(
@@ -51,16 +60,90 @@ func NewApp(
 		return nil, fmt.Errorf("start rabbit producer: %w", err)
 	}
 
+	// продьюсер доменных событий пользователей для других сервисов
+	userEventsProducer, err := producer.New(
+		&cfg.RabbitConfig,
+		cfg.UserEventsExchange,
+		cfg.UserDeletionsQueue,
+		logger,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("start rabbit user events producer: %w", err)
+	}
+
+	// продьюсер событий об изменении пользователей
+	userUpdatesProducer, err := producer.New(
+		&cfg.RabbitConfig,
+		cfg.UserEventsExchange,
+		cfg.UserUpdatesQueue,
+		logger,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("start rabbit user updates producer: %w", err)
+	}
+
+	// продьюсер запросов выгрузки данных пользователя
+	userExportsProducer, err := producer.New(
+		&cfg.RabbitConfig,
+		cfg.UserEventsExchange,
+		cfg.UserExportsQueue,
+		logger,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("start rabbit user exports producer: %w", err)
+	}
+
+	// хранилище аватаров
+	fileStorage, err := filestorage.New(&cfg.FileStorage)
+	if err != nil {
+		return nil, fmt.Errorf("open file storage: %w", err)
+	}
+
+	// требования к новым паролям
+	passwordPolicy, err := newPasswordPolicy(&cfg.PasswordPolicy)
+	if err != nil {
+		return nil, fmt.Errorf("load password policy: %w", err)
+	}
+
 	// передадим реализацию репозитория конструктору сервиса
-	userService := service.NewUserService(&cfg.Password, userRepo, usersProducer)
+	userService := service.NewUserService(&cfg.Password, &cfg.PasswordPolicy, passwordPolicy, &cfg.Verification, &cfg.PasswordReset, &cfg.EmailChange, &cfg.TwoFactor, &cfg.LoginThrottle, &cfg.ApiTokens, &cfg.Organizations, &cfg.Profile, &cfg.UserDeletion, &cfg.DataExport, &cfg.Erasure, userRepo, usersProducer, userEventsProducer, userUpdatesProducer, userExportsProducer, fileStorage)
 
 	return &App{
-		cfg:         cfg,
-		logger:      logger,
-		userService: userService,
+		cfg:             cfg,
+		logger:          logger,
+		userService:     userService,
+		deletionService: userService,
+		exportService:   userService,
+		fileStorage:     fileStorage,
 	}, nil
 }
 
+// newPasswordPolicy собирает требования к паролю из настроек. Список запрещенных паролей из файла
+// дополняет встроенный
+func newPasswordPolicy(cfg *config.PasswordPolicyConfig) (*passwordpolicy.Policy, error) {
+	rules := passwordpolicy.Rules{
+		MinLength:        cfg.MinLength,
+		MaxLength:        cfg.MaxLength,
+		RequireLowercase: cfg.RequireLowercase,
+		RequireUppercase: cfg.RequireUppercase,
+		RequireDigit:     cfg.RequireDigit,
+		RequireSymbol:    cfg.RequireSymbol,
+		RejectUserInfo:   cfg.RejectUserInfo,
+	}
+
+	if cfg.BannedListFile == "" {
+		return passwordpolicy.New(rules)
+	}
+
+	file, err := os.Open(cfg.BannedListFile)
+	if err != nil {
+		return nil, fmt.Errorf("open banned list: %w", err)
+	}
+	defer file.Close()
+
+	return passwordpolicy.New(rules, file)
+}
+
 func (a *App) RunApp() error {
 	tracer, closer, err := jaeger.InitJaeger(&a.cfg.Jaeger, a.cfg.Logging.LogIndex)
 	if err != nil {
@@ -74,7 +157,7 @@ func (a *App) RunApp() error {
 
 	group := new(errgroup.Group)
 	group.Go(func() error {
-		err := rest.NewRestApi(a.cfg, a.logger, a.userService)
+		err := rest.NewRestApi(a.cfg, a.logger, a.userService, a.fileStorage.Handler())
 		return fmt.Errorf("[RunApp] run REST: %w", err)
 	})
 
@@ -83,9 +166,37 @@ func (a *App) RunApp() error {
 		return fmt.Errorf("[RunApp] run GRPC: %w", err)
 	})
 
+	group.Go(func() error {
+		err := rabbitmq.ConsumeRabbitMessages(context.Background(), a.cfg, a.logger, a.deletionService, a.exportService)
+		return fmt.Errorf("[RunApp] consume rabbit messages: %w", err)
+	})
+
+	group.Go(func() error {
+		err := a.cancelStaleUserDeletions(context.Background())
+		return fmt.Errorf("[RunApp] cancel stale user deletions: %w", err)
+	})
+
 	if err := group.Wait(); err != nil {
 		return fmt.Errorf("[RunApp] run: %w", err)
 	}
 
 	return nil
 }
+
+// cancelStaleUserDeletions раз в UserDeletion.SweepInterval отменяет удаления пользователей, на которые
+// сервисы-участники не ответили за UserDeletion.Timeout. Ошибка проверки только логируется, следующая повторит ее
+func (a *App) cancelStaleUserDeletions(ctx context.Context) error {
+	ticker := time.NewTicker(a.cfg.UserDeletion.SweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := a.deletionService.CancelStaleUserDeletions(ctx); err != nil {
+				a.logger.Error().Msgf("[cancelStaleUserDeletions] %s", err)
+			}
+		}
+	}
+}
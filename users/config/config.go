@@ -2,6 +2,8 @@ package config
 
 import (
 	"github.com/kelseyhightower/envconfig"
+	"time"
+	"users/pkg/filestorage"
 	"users/pkg/jaeger"
 	"users/pkg/logging"
 	"users/pkg/postgresql"
@@ -9,15 +11,41 @@ import (
 )
 
 type Config struct {
-	App           App                   `envconfig:"APP"`
-	Grpc          Grpc                  `envconfig:"GRPC"`
-	Password      PasswordConfig        `envconfig:"PASS"`
-	Logging       logging.LoggerConfig  `envconfig:"LOG"`
-	Jaeger        jaeger.JaegerConfig   `envconfig:"JAEGER"`
-	Postgres      postgresql.PostgreSQL `envconfig:"POSTGRES"`
-	RabbitConfig  rabbitmq.RabbitConfig `envconfig:"RABBITMQ"`
-	UsersExchange string                `envconfig:"RABBITMQ_USERS_EXCHANGE" default:"users.exchange"`
-	UsersQueue    string                `envconfig:"RABBITMQ_USERS_QUEUE" default:"users.queue"`
+	App            App                     `envconfig:"APP"`
+	Grpc           Grpc                    `envconfig:"GRPC"`
+	Password       PasswordConfig          `envconfig:"PASS"`
+	PasswordPolicy PasswordPolicyConfig    `envconfig:"PASS_POLICY"`
+	Verification   VerificationConfig      `envconfig:"VERIFY"`
+	PasswordReset  PasswordResetConfig     `envconfig:"PASS_RESET"`
+	EmailChange    EmailChangeConfig       `envconfig:"EMAIL_CHANGE"`
+	TwoFactor      TwoFactorConfig         `envconfig:"TWO_FACTOR"`
+	LoginThrottle  LoginThrottleConfig     `envconfig:"LOGIN_THROTTLE"`
+	ApiTokens      ApiTokenConfig          `envconfig:"API_TOKEN"`
+	Organizations  OrganizationConfig      `envconfig:"ORG"`
+	Profile        ProfileConfig           `envconfig:"PROFILE"`
+	UserDeletion   UserDeletionConfig      `envconfig:"USER_DELETION"`
+	DataExport     DataExportConfig        `envconfig:"DATA_EXPORT"`
+	Erasure        ErasureConfig           `envconfig:"ERASURE"`
+	FileStorage    filestorage.FileStorage `envconfig:"FILE_STORAGE"`
+	Logging        logging.LoggerConfig    `envconfig:"LOG"`
+	Jaeger         jaeger.JaegerConfig     `envconfig:"JAEGER"`
+	Postgres       postgresql.PostgreSQL   `envconfig:"POSTGRES"`
+	RabbitConfig   rabbitmq.RabbitConfig   `envconfig:"RABBITMQ"`
+	UsersExchange  string                  `envconfig:"RABBITMQ_USERS_EXCHANGE" default:"users.exchange"`
+	UsersQueue     string                  `envconfig:"RABBITMQ_USERS_QUEUE" default:"users.queue"`
+	// UserEventsExchange и UserDeletionsQueue - куда публикуется событие user_deleted для сервисов-участников
+	UserEventsExchange string `envconfig:"RABBITMQ_USER_EVENTS_EXCHANGE" default:"users.events"`
+	UserDeletionsQueue string `envconfig:"RABBITMQ_USER_DELETIONS_QUEUE" default:"todo.user_deletions"`
+	// UserUpdatesQueue - куда публикуется событие user_updated, по нему сервисы сбрасывают закешированных пользователей
+	UserUpdatesQueue string `envconfig:"RABBITMQ_USER_UPDATES_QUEUE" default:"todo.user_updates"`
+	// UserExportsQueue - куда публикуется событие user_export_requested для сервисов-участников выгрузки
+	UserExportsQueue string `envconfig:"RABBITMQ_USER_EXPORTS_QUEUE" default:"todo.user_exports"`
+	// UserExportRepliesExchange и UserExportRepliesQueue - откуда приходят данные сервисов для выгрузки
+	UserExportRepliesExchange string `envconfig:"RABBITMQ_USER_EXPORT_REPLIES_EXCHANGE" default:"todo.events"`
+	UserExportRepliesQueue    string `envconfig:"RABBITMQ_USER_EXPORT_REPLIES_QUEUE" default:"users.user_export_replies"`
+	// UserDeletionRepliesExchange и UserDeletionRepliesQueue - откуда приходят ответы сервисов на user_deleted
+	UserDeletionRepliesExchange string `envconfig:"RABBITMQ_USER_DELETION_REPLIES_EXCHANGE" default:"todo.events"`
+	UserDeletionRepliesQueue    string `envconfig:"RABBITMQ_USER_DELETION_REPLIES_QUEUE" default:"users.user_deletion_replies"`
 }
 
 type MigrationsConfig struct {
@@ -41,6 +69,136 @@ type PasswordConfig struct {
 	KeyLen  uint32 `envconfig:"PASS_KEY_LEN" required:"true" default:"32"`
 }
 
+// PasswordPolicyConfig - требования к новому паролю при регистрации, смене и сбросе пароля
+type PasswordPolicyConfig struct {
+	MinLength        int  `envconfig:"PASS_POLICY_MIN_LENGTH" required:"true" default:"8"`
+	MaxLength        int  `envconfig:"PASS_POLICY_MAX_LENGTH" required:"true" default:"128"`
+	RequireLowercase bool `envconfig:"PASS_POLICY_REQUIRE_LOWERCASE" default:"true"`
+	RequireUppercase bool `envconfig:"PASS_POLICY_REQUIRE_UPPERCASE" default:"true"`
+	RequireDigit     bool `envconfig:"PASS_POLICY_REQUIRE_DIGIT" default:"true"`
+	RequireSymbol    bool `envconfig:"PASS_POLICY_REQUIRE_SYMBOL" default:"false"`
+	// RejectUserInfo - пароль не должен содержать имя пользователя или email
+	RejectUserInfo bool `envconfig:"PASS_POLICY_REJECT_USER_INFO" default:"true"`
+	// BannedListFile - файл с запрещенными паролями по одному в строке, дополняет встроенный список
+	BannedListFile string `envconfig:"PASS_POLICY_BANNED_LIST_FILE"`
+	// HistorySize - сколько последних паролей, включая текущий, нельзя использовать снова, 0 - не проверять
+	HistorySize int `envconfig:"PASS_POLICY_HISTORY_SIZE" default:"5"`
+}
+
+// VerificationConfig - настройки подтверждения email
+type VerificationConfig struct {
+	// LinkURL - адрес страницы подтверждения, к нему добавляется параметр token
+	LinkURL        string        `envconfig:"VERIFY_LINK_URL" required:"true" default:"http://localhost:3009/api/v1/users/verify"`
+	TokenTTL       time.Duration `envconfig:"VERIFY_TOKEN_TTL" required:"true" default:"24h"`
+	ResendInterval time.Duration `envconfig:"VERIFY_RESEND_INTERVAL" required:"true" default:"1m"`
+	// RequireVerifiedLogin - запрещать вход, пока email не подтвержден
+	RequireVerifiedLogin bool `envconfig:"VERIFY_REQUIRE_VERIFIED_LOGIN" default:"false"`
+}
+
+// PasswordResetConfig - настройки сброса пароля
+type PasswordResetConfig struct {
+	// LinkURL - адрес страницы ввода нового пароля, к нему добавляется параметр token
+	LinkURL  string        `envconfig:"PASS_RESET_LINK_URL" required:"true" default:"http://localhost:3009/api/v1/users/password-reset/confirm"`
+	TokenTTL time.Duration `envconfig:"PASS_RESET_TOKEN_TTL" required:"true" default:"1h"`
+	// RequestInterval - как часто можно отправлять письмо со сбросом пароля на один адрес
+	RequestInterval time.Duration `envconfig:"PASS_RESET_REQUEST_INTERVAL" required:"true" default:"1m"`
+}
+
+// EmailChangeConfig - настройки смены email
+type EmailChangeConfig struct {
+	// LinkURL - адрес страницы подтверждения нового email, к нему добавляется параметр token
+	LinkURL  string        `envconfig:"EMAIL_CHANGE_LINK_URL" required:"true" default:"http://localhost:3009/api/v1/users/email/confirm"`
+	TokenTTL time.Duration `envconfig:"EMAIL_CHANGE_TOKEN_TTL" required:"true" default:"24h"`
+	// RequestInterval - как часто пользователь может запрашивать смену email
+	RequestInterval time.Duration `envconfig:"EMAIL_CHANGE_REQUEST_INTERVAL" required:"true" default:"1m"`
+}
+
+// TwoFactorConfig - настройки двухфакторной аутентификации
+type TwoFactorConfig struct {
+	// Issuer - название сервиса, под которым аккаунт появится в приложении-аутентификаторе
+	Issuer       string        `envconfig:"TWO_FACTOR_ISSUER" required:"true" default:"Todo"`
+	ChallengeTTL time.Duration `envconfig:"TWO_FACTOR_CHALLENGE_TTL" required:"true" default:"5m"`
+	// MaxAttempts - сколько неверных кодов можно ввести на одном втором шаге входа
+	MaxAttempts   int `envconfig:"TWO_FACTOR_MAX_ATTEMPTS" required:"true" default:"5"`
+	RecoveryCodes int `envconfig:"TWO_FACTOR_RECOVERY_CODES" required:"true" default:"10"`
+	// RequiredRoles - роли, которым нужен второй фактор. Пока он не включен, разрешения роли не выдаются,
+	// а отключить его нельзя
+	RequiredRoles []string `envconfig:"TWO_FACTOR_REQUIRED_ROLES" default:"admin"`
+}
+
+// LoginThrottleConfig - настройки защиты от подбора пароля. После FreeAttempts неудачных попыток подряд
+// каждая следующая попытка откладывается на BaseDelay, затем на вдвое больший срок, но не больше MaxDelay.
+// После LockoutThreshold неудач аккаунт блокируется на LockoutDuration, а владельцу уходит письмо
+type LoginThrottleConfig struct {
+	FreeAttempts int           `envconfig:"LOGIN_THROTTLE_FREE_ATTEMPTS" required:"true" default:"3"`
+	BaseDelay    time.Duration `envconfig:"LOGIN_THROTTLE_BASE_DELAY" required:"true" default:"1s"`
+	MaxDelay     time.Duration `envconfig:"LOGIN_THROTTLE_MAX_DELAY" required:"true" default:"5m"`
+	// Window - через сколько после последней неудачи счет попыток начинается заново
+	Window           time.Duration `envconfig:"LOGIN_THROTTLE_WINDOW" required:"true" default:"1h"`
+	LockoutThreshold int           `envconfig:"LOGIN_THROTTLE_LOCKOUT_THRESHOLD" required:"true" default:"10"`
+	LockoutDuration  time.Duration `envconfig:"LOGIN_THROTTLE_LOCKOUT_DURATION" required:"true" default:"30m"`
+	// IPFreeAttempts - сколько неудач подряд прощается одному адресу, за одним адресом бывает много пользователей
+	IPFreeAttempts int `envconfig:"LOGIN_THROTTLE_IP_FREE_ATTEMPTS" required:"true" default:"20"`
+	// AttemptTimeout - через сколько незавершенная попытка входа перестает учитываться
+	AttemptTimeout time.Duration `envconfig:"LOGIN_THROTTLE_ATTEMPT_TIMEOUT" required:"true" default:"1m"`
+}
+
+// ApiTokenConfig - настройки персональных токенов доступа
+type ApiTokenConfig struct {
+	DefaultTTL time.Duration `envconfig:"API_TOKEN_DEFAULT_TTL" required:"true" default:"720h"`
+	MaxTTL     time.Duration `envconfig:"API_TOKEN_MAX_TTL" required:"true" default:"8760h"`
+	// MaxActive - сколько действующих токенов может быть у одного пользователя
+	MaxActive int `envconfig:"API_TOKEN_MAX_ACTIVE" required:"true" default:"20"`
+	// LastUsedInterval - с какой точностью запоминается время последнего использования токена
+	LastUsedInterval time.Duration `envconfig:"API_TOKEN_LAST_USED_INTERVAL" required:"true" default:"1m"`
+}
+
+// OrganizationConfig - настройки организаций и приглашений в них
+type OrganizationConfig struct {
+	// InviteLinkURL - адрес страницы принятия приглашения, к нему добавляется параметр token
+	InviteLinkURL string        `envconfig:"ORG_INVITE_LINK_URL" required:"true" default:"http://localhost:3009/api/v1/orgs/invitations/accept"`
+	InviteTTL     time.Duration `envconfig:"ORG_INVITE_TTL" required:"true" default:"168h"`
+}
+
+// ProfileConfig - настройки профиля пользователя
+type ProfileConfig struct {
+	// AvatarMaxBytes - предельный размер загружаемого файла, запрос по gRPC не может быть больше 4 МБ
+	AvatarMaxBytes int `envconfig:"PROFILE_AVATAR_MAX_BYTES" required:"true" default:"2097152"`
+	// AvatarMaxPixels - предельное число пикселей, защищает от картинок, которые раздуваются в памяти при декодировании
+	AvatarMaxPixels int `envconfig:"PROFILE_AVATAR_MAX_PIXELS" required:"true" default:"16777216"`
+}
+
+// UserDeletionConfig - настройки удаления пользователя. Пользователь удаляется окончательно, когда удаление
+// подтвердят все сервисы из Participants, а если хотя бы один откажет или не ответит за Timeout - удаление отменяется
+type UserDeletionConfig struct {
+	Participants []string `envconfig:"USER_DELETION_PARTICIPANTS" required:"true" default:"todo"`
+	// Timeout - сколько удаление ждет ответов сервисов-участников
+	Timeout time.Duration `envconfig:"USER_DELETION_TIMEOUT" required:"true" default:"1h"`
+	// SweepInterval - как часто удаления без ответов проверяются на Timeout
+	SweepInterval time.Duration `envconfig:"USER_DELETION_SWEEP_INTERVAL" required:"true" default:"5m"`
+}
+
+// DataExportConfig - настройки выгрузки данных пользователя. Архив собирается, когда свои данные
+// пришлют все сервисы из Participants, и скачивается по ссылке из письма
+type DataExportConfig struct {
+	Participants []string `envconfig:"DATA_EXPORT_PARTICIPANTS" required:"true" default:"todo"`
+	// LinkURL - адрес скачивания архива, к нему добавляется параметр token
+	LinkURL string `envconfig:"DATA_EXPORT_LINK_URL" required:"true" default:"http://localhost:3009/api/v1/users/export/download"`
+	// TTL - сколько архив можно скачать после того, как он собран
+	TTL time.Duration `envconfig:"DATA_EXPORT_TTL" required:"true" default:"72h"`
+	// RequestInterval - как часто пользователь может запрашивать выгрузку
+	RequestInterval time.Duration `envconfig:"DATA_EXPORT_REQUEST_INTERVAL" required:"true" default:"1h"`
+}
+
+// ErasureConfig - настройки удаления аккаунта по запросу пользователя. Запрос подтверждается ссылкой из письма
+type ErasureConfig struct {
+	// LinkURL - адрес страницы подтверждения удаления, к нему добавляется параметр token
+	LinkURL  string        `envconfig:"ERASURE_LINK_URL" required:"true" default:"http://localhost:3009/api/v1/users/erasure/confirm"`
+	TokenTTL time.Duration `envconfig:"ERASURE_TOKEN_TTL" required:"true" default:"24h"`
+	// RequestInterval - как часто пользователь может запрашивать удаление
+	RequestInterval time.Duration `envconfig:"ERASURE_REQUEST_INTERVAL" required:"true" default:"1m"`
+}
+
 func NewFromEnv() *Config {
 	c := Config{}
 	envconfig.MustProcess("", &c)
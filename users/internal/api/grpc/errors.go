@@ -0,0 +1,153 @@
+package grpc
+
+import (
+	"errors"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	appErrors "users/internal/app_errors"
+)
+
+// errorDomain is the error domain of the service in gRPC status details.
+const errorDomain = "users"
+
+// errorStatus describes how an application error is reported to the caller.
+type errorStatus struct {
+	code   codes.Code
+	reason string
+}
+
+// errorStatuses lists the application errors the caller can react to.
+var errorStatuses = map[error]errorStatus{
+	appErrors.ErrNotFound:                        {codes.NotFound, "NOT_FOUND"},
+	appErrors.ErrWrongCredentials:                {codes.Unauthenticated, "WRONG_CREDENTIALS"},
+	appErrors.ErrInvalidVerificationToken:        {codes.InvalidArgument, "INVALID_VERIFICATION_TOKEN"},
+	appErrors.ErrEmailNotVerified:                {codes.FailedPrecondition, "EMAIL_NOT_VERIFIED"},
+	appErrors.ErrInvalidPasswordResetToken:       {codes.InvalidArgument, "INVALID_PASSWORD_RESET_TOKEN"},
+	appErrors.ErrPassAndConfirmationDoesNotMatch: {codes.InvalidArgument, "PASSWORD_CONFIRMATION_MISMATCH"},
+	appErrors.ErrSessionNotFound:                 {codes.Unauthenticated, "SESSION_NOT_FOUND"},
+	appErrors.ErrRefreshTokenReused:              {codes.Unauthenticated, "REFRESH_TOKEN_REUSED"},
+	appErrors.ErrTwoFactorNotEnabled:             {codes.FailedPrecondition, "TWO_FACTOR_NOT_ENABLED"},
+	appErrors.ErrTwoFactorAlreadyEnabled:         {codes.FailedPrecondition, "TWO_FACTOR_ALREADY_ENABLED"},
+	appErrors.ErrInvalidTwoFactorCode:            {codes.InvalidArgument, "INVALID_TWO_FACTOR_CODE"},
+	appErrors.ErrInvalidTwoFactorChallenge:       {codes.Unauthenticated, "INVALID_TWO_FACTOR_CHALLENGE"},
+	appErrors.ErrTwoFactorRequiredByRole:         {codes.FailedPrecondition, "TWO_FACTOR_REQUIRED_BY_ROLE"},
+	appErrors.ErrLoginThrottled:                  {codes.ResourceExhausted, "LOGIN_THROTTLED"},
+	appErrors.ErrAccountLocked:                   {codes.ResourceExhausted, "ACCOUNT_LOCKED"},
+	appErrors.ErrUnknownRole:                     {codes.InvalidArgument, "UNKNOWN_ROLE"},
+	appErrors.ErrExternalEmailNotVerified:        {codes.FailedPrecondition, "EXTERNAL_EMAIL_NOT_VERIFIED"},
+	appErrors.ErrExternalEmailConflict:           {codes.FailedPrecondition, "EXTERNAL_EMAIL_CONFLICT"},
+	appErrors.ErrInvalidSort:                     {codes.InvalidArgument, "INVALID_SORT"},
+	appErrors.ErrApiTokenNotFound:                {codes.NotFound, "API_TOKEN_NOT_FOUND"},
+	appErrors.ErrInvalidApiToken:                 {codes.Unauthenticated, "INVALID_API_TOKEN"},
+	appErrors.ErrApiTokenNameInvalid:             {codes.InvalidArgument, "API_TOKEN_NAME_INVALID"},
+	appErrors.ErrUnknownApiTokenScope:            {codes.InvalidArgument, "UNKNOWN_API_TOKEN_SCOPE"},
+	appErrors.ErrApiTokenTTLInvalid:              {codes.InvalidArgument, "API_TOKEN_TTL_INVALID"},
+	appErrors.ErrTooManyApiTokens:                {codes.FailedPrecondition, "TOO_MANY_API_TOKENS"},
+	appErrors.ErrOrganizationNotFound:            {codes.NotFound, "ORGANIZATION_NOT_FOUND"},
+	appErrors.ErrOrganizationNameInvalid:         {codes.InvalidArgument, "ORGANIZATION_NAME_INVALID"},
+	appErrors.ErrOrganizationForbidden:           {codes.PermissionDenied, "ORGANIZATION_FORBIDDEN"},
+	appErrors.ErrUnknownOrganizationRole:         {codes.InvalidArgument, "UNKNOWN_ORGANIZATION_ROLE"},
+	appErrors.ErrLastOrganizationOwner:           {codes.FailedPrecondition, "LAST_ORGANIZATION_OWNER"},
+	appErrors.ErrMemberNotFound:                  {codes.NotFound, "MEMBER_NOT_FOUND"},
+	appErrors.ErrAlreadyMember:                   {codes.AlreadyExists, "ALREADY_MEMBER"},
+	appErrors.ErrInvalidEmail:                    {codes.InvalidArgument, "INVALID_EMAIL"},
+	appErrors.ErrInvalidInvitation:               {codes.InvalidArgument, "INVALID_INVITATION"},
+	appErrors.ErrInvitationEmailMismatch:         {codes.PermissionDenied, "INVITATION_EMAIL_MISMATCH"},
+	appErrors.ErrTeamNotFound:                    {codes.NotFound, "TEAM_NOT_FOUND"},
+	appErrors.ErrTeamNameInvalid:                 {codes.InvalidArgument, "TEAM_NAME_INVALID"},
+	appErrors.ErrTeamNameIsUsed:                  {codes.AlreadyExists, "TEAM_NAME_IS_USED"},
+	appErrors.ErrUsernameOrEmailIsUsed:           {codes.AlreadyExists, "USERNAME_OR_EMAIL_IS_USED"},
+	appErrors.ErrUsernameInvalid:                 {codes.InvalidArgument, "USERNAME_INVALID"},
+	appErrors.ErrDisplayNameInvalid:              {codes.InvalidArgument, "DISPLAY_NAME_INVALID"},
+	appErrors.ErrUnknownTimezone:                 {codes.InvalidArgument, "UNKNOWN_TIMEZONE"},
+	appErrors.ErrUnknownLocale:                   {codes.InvalidArgument, "UNKNOWN_LOCALE"},
+	appErrors.ErrInvalidAvatar:                   {codes.InvalidArgument, "INVALID_AVATAR"},
+	appErrors.ErrAvatarTooLarge:                  {codes.InvalidArgument, "AVATAR_TOO_LARGE"},
+	appErrors.ErrUnknownNotificationEvent:        {codes.InvalidArgument, "UNKNOWN_NOTIFICATION_EVENT"},
+	appErrors.ErrUnknownNotificationChannel:      {codes.InvalidArgument, "UNKNOWN_NOTIFICATION_CHANNEL"},
+	appErrors.ErrEmailChangeThrottled:            {codes.ResourceExhausted, "EMAIL_CHANGE_THROTTLED"},
+	appErrors.ErrInvalidEmailChangeToken:         {codes.InvalidArgument, "INVALID_EMAIL_CHANGE_TOKEN"},
+	appErrors.ErrEmailUnchanged:                  {codes.InvalidArgument, "EMAIL_UNCHANGED"},
+	appErrors.ErrEmailChangeRequired:             {codes.FailedPrecondition, "EMAIL_CHANGE_REQUIRED"},
+	appErrors.ErrUserDeletionInProgress:          {codes.FailedPrecondition, "USER_DELETION_IN_PROGRESS"},
+	appErrors.ErrUserDeletionNotFound:            {codes.NotFound, "USER_DELETION_NOT_FOUND"},
+	appErrors.ErrIncorrectOldPassword:            {codes.InvalidArgument, "INCORRECT_OLD_PASSWORD"},
+	appErrors.ErrPasswordPolicy:                  {codes.InvalidArgument, "PASSWORD_POLICY_VIOLATION"},
+	appErrors.ErrTooManyUserIDs:                  {codes.InvalidArgument, "TOO_MANY_USER_IDS"},
+	appErrors.ErrDataExportThrottled:             {codes.ResourceExhausted, "DATA_EXPORT_THROTTLED"},
+	appErrors.ErrDataExportNotFound:              {codes.NotFound, "DATA_EXPORT_NOT_FOUND"},
+	appErrors.ErrInvalidDataExportToken:          {codes.InvalidArgument, "INVALID_DATA_EXPORT_TOKEN"},
+	appErrors.ErrErasureThrottled:                {codes.ResourceExhausted, "ERASURE_THROTTLED"},
+	appErrors.ErrInvalidErasureToken:             {codes.InvalidArgument, "INVALID_ERASURE_TOKEN"},
+}
+
+// toGRPCError converts errors the caller can react to into gRPC statuses with an ErrorInfo detail.
+// Throttled logins also carry a RetryInfo detail, password policy violations a BadRequest detail.
+// Other errors are returned as is.
+func toGRPCError(err error) error {
+	for target, st := range errorStatuses {
+		if errors.Is(err, target) {
+			info := &errdetails.ErrorInfo{
+				Reason: st.reason,
+				Domain: errorDomain,
+			}
+
+			var throttled *appErrors.LoginThrottledError
+			if errors.As(err, &throttled) {
+				return withRetryInfo(st.code, target.Error(), info, throttled.RetryAfter)
+			}
+
+			var policyErr *appErrors.PasswordPolicyError
+			if errors.As(err, &policyErr) {
+				return withFieldViolations(st.code, target.Error(), info, policyErr.Violations)
+			}
+
+			return withErrorInfo(st.code, target.Error(), info)
+		}
+	}
+
+	return err
+}
+
+func withErrorInfo(code codes.Code, message string, info *errdetails.ErrorInfo) error {
+	st, detailsErr := status.New(code, message).WithDetails(info)
+	if detailsErr != nil {
+		return status.Error(code, message)
+	}
+
+	return st.Err()
+}
+
+func withRetryInfo(code codes.Code, message string, info *errdetails.ErrorInfo, retryAfter time.Duration) error {
+	st, detailsErr := status.New(code, message).WithDetails(info, &errdetails.RetryInfo{
+		RetryDelay: durationpb.New(retryAfter),
+	})
+	if detailsErr != nil {
+		return withErrorInfo(code, message, info)
+	}
+
+	return st.Err()
+}
+
+func withFieldViolations(code codes.Code, message string, info *errdetails.ErrorInfo, violations []appErrors.FieldViolation) error {
+	badRequest := &errdetails.BadRequest{
+		FieldViolations: make([]*errdetails.BadRequest_FieldViolation, len(violations)),
+	}
+	for i, violation := range violations {
+		badRequest.FieldViolations[i] = &errdetails.BadRequest_FieldViolation{
+			Field:       violation.Field,
+			Description: violation.Message,
+		}
+	}
+
+	st, detailsErr := status.New(code, message).WithDetails(info, badRequest)
+	if detailsErr != nil {
+		return withErrorInfo(code, message, info)
+	}
+
+	return st.Err()
+}
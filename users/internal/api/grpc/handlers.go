@@ -2,7 +2,10 @@ package grpc
 
 import (
 	"context"
+	"github.com/google/uuid"
 	"github.com/opentracing/opentracing-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
 	"users/internal/models"
 	"users/pkg/ctxutil"
@@ -29,7 +32,7 @@ func (s *server) RegisterUser(ctx context.Context, req *users.CreateUserDTO) (*u
 		s.logger.Error().
 			Str("requestId", requestId).
 			Msgf("[RegisterUser]: %w", err)
-		return nil, err
+		return nil, toGRPCError(err)
 	}
 
 	return &users.UserID{Id: int32(id)}, nil
@@ -50,7 +53,7 @@ func (s *server) UpdateUser(ctx context.Context, req *users.UserDTO) (*users.Use
 			Str("requestId", requestId).
 			Msgf("[UpdateUser]: %w", err)
 
-		return nil, err
+		return nil, toGRPCError(err)
 	}
 
 	return result.ToGRPC(), nil
@@ -71,7 +74,7 @@ func (s *server) UpdatePassword(ctx context.Context, req *users.UpdateUserPasswo
 			Str("requestId", requestId).
 			Msgf("[UpdatePassword]: %w", err)
 
-		return nil, err
+		return nil, toGRPCError(err)
 	}
 
 	return &emptypb.Empty{}, nil
@@ -91,12 +94,132 @@ func (s *server) DeleteUser(ctx context.Context, req *users.UserID) (*emptypb.Em
 			Str("requestId", requestId).
 			Msgf("[DeleteUser]: %w", err)
 
-		return nil, err
+		return nil, toGRPCError(err)
 	}
 
 	return &emptypb.Empty{}, nil
 }
 
+// GetUserDeletion - Returns the state of the latest user deletion
+func (s *server) GetUserDeletion(ctx context.Context, req *users.UserID) (*users.UserDeletion, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.GetUserDeletion")
+	defer span.Finish()
+
+	deletion, err := s.userService.GetUserDeletion(ctx, int(req.Id))
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[GetUserDeletion]: %s", err)
+
+		return nil, toGRPCError(err)
+	}
+
+	return deletion.ToGRPC(), nil
+}
+
+// RequestErasure - Sends a link to confirm the erasure of the user account
+func (s *server) RequestErasure(ctx context.Context, req *users.RequestErasureRequest) (*emptypb.Empty, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.RequestErasure")
+	defer span.Finish()
+
+	err := s.userService.RequestErasure(ctx, models.NewEmptyRequestErasureDTO().FromGRPC(req))
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[RequestErasure]: %s", err)
+
+		return nil, toGRPCError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// ConfirmErasure - Confirms the erasure by token and starts anonymizing the user data
+func (s *server) ConfirmErasure(ctx context.Context, req *users.ConfirmErasureRequest) (*users.UserDeletion, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.ConfirmErasure")
+	defer span.Finish()
+
+	deletion, err := s.userService.ConfirmErasure(ctx, req.Token)
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[ConfirmErasure]: %s", err)
+
+		return nil, toGRPCError(err)
+	}
+
+	return deletion.ToGRPC(), nil
+}
+
+// RequestDataExport - Starts an asynchronous export of the user data
+func (s *server) RequestDataExport(ctx context.Context, req *users.UserID) (*users.DataExport, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.RequestDataExport")
+	defer span.Finish()
+
+	export, err := s.userService.RequestDataExport(ctx, int(req.Id))
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[RequestDataExport]: %s", err)
+
+		return nil, toGRPCError(err)
+	}
+
+	return export.ToGRPC(), nil
+}
+
+// GetDataExport - Returns the state of the latest user data export
+func (s *server) GetDataExport(ctx context.Context, req *users.UserID) (*users.DataExport, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.GetDataExport")
+	defer span.Finish()
+
+	export, err := s.userService.GetDataExport(ctx, int(req.Id))
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[GetDataExport]: %s", err)
+
+		return nil, toGRPCError(err)
+	}
+
+	return export.ToGRPC(), nil
+}
+
+// DownloadDataExport - Returns the export archive by the token from the email
+func (s *server) DownloadDataExport(ctx context.Context, req *users.DataExportDownloadRequest) (*users.DataExportArchive, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.DownloadDataExport")
+	defer span.Finish()
+
+	archive, err := s.userService.DownloadDataExport(ctx, req.Token)
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[DownloadDataExport]: %s", err)
+
+		return nil, toGRPCError(err)
+	}
+
+	return archive.ToGRPC(), nil
+}
+
 // GetUserByID - Retrieves a user by ID
 func (s *server) GetUserByID(ctx context.Context, req *users.UserID) (*users.UserDTO, error) {
 	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
@@ -111,12 +234,58 @@ func (s *server) GetUserByID(ctx context.Context, req *users.UserID) (*users.Use
 			Str("requestId", requestId).
 			Msgf("[GetUserByID]: %w", err)
 
-		return nil, err
+		return nil, toGRPCError(err)
 	}
 
 	return user.ToGRPC(), nil
 }
 
+// GetUsersByIDs - Retrieves several users by ID in one call, missing and deleted users are skipped
+func (s *server) GetUsersByIDs(ctx context.Context, req *users.UserIDs) (*users.Users, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.GetUsersByIDs")
+	defer span.Finish()
+
+	userIDs := make([]int, len(req.Ids))
+	for i, id := range req.Ids {
+		userIDs[i] = int(id)
+	}
+
+	foundUsers, err := s.userService.GetUsersByIDs(ctx, userIDs)
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[GetUsersByIDs]: %s", err)
+
+		return nil, toGRPCError(err)
+	}
+
+	return models.UsersToGRPC(foundUsers), nil
+}
+
+// GetUsersByUsernames - Retrieves several users with their role permissions by username in one call,
+// missing and deleted users are skipped
+func (s *server) GetUsersByUsernames(ctx context.Context, req *users.Usernames) (*users.Users, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.GetUsersByUsernames")
+	defer span.Finish()
+
+	foundUsers, err := s.userService.GetUsersByUsernames(ctx, req.Usernames)
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[GetUsersByUsernames]: %s", err)
+
+		return nil, toGRPCError(err)
+	}
+
+	return models.UsersToGRPC(foundUsers), nil
+}
+
 // GetUserByUsernameOrEmail - Retrieves a user by username or email
 func (s *server) GetUserByUsernameOrEmail(ctx context.Context, req *users.UserDTO) (*users.UserDTO, error) {
 	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
@@ -131,12 +300,33 @@ func (s *server) GetUserByUsernameOrEmail(ctx context.Context, req *users.UserDT
 			Str("requestId", requestId).
 			Msgf("[GetUserByUsernameOrEmail]: %w", err)
 
-		return nil, err
+		return nil, toGRPCError(err)
 	}
 
 	return user.ToGRPC(), nil
 }
 
+// ListUsers - Searches users by username or email prefix, one page at a time
+func (s *server) ListUsers(ctx context.Context, req *users.ListUsersRequest) (*users.ListUsersResponse, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.ListUsers")
+	defer span.Finish()
+
+	filter := models.NewEmptyListUsersDTO().FromGRPC(req)
+	page, err := s.userService.ListUsers(ctx, filter)
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[ListUsers]: %s", err)
+
+		return nil, toGRPCError(err)
+	}
+
+	return page.ToGRPC(), nil
+}
+
 // Login - Handles user login
 func (s *server) Login(ctx context.Context, req *users.UserLoginDTO) (*users.UserDTO, error) {
 	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
@@ -152,8 +342,818 @@ func (s *server) Login(ctx context.Context, req *users.UserLoginDTO) (*users.Use
 			Str("requestId", requestId).
 			Msgf("[Login]: %w", err)
 
-		return nil, err
+		return nil, toGRPCError(err)
 	}
 
 	return user.ToGRPC(), nil
 }
+
+// VerifyEmail - Confirms the user's email with the token from the verification letter
+func (s *server) VerifyEmail(ctx context.Context, req *users.VerifyEmailRequest) (*emptypb.Empty, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.VerifyEmail")
+	defer span.Finish()
+
+	err := s.userService.VerifyEmail(ctx, req.Token)
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[VerifyEmail]: %s", err)
+
+		return nil, toGRPCError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// ResendVerificationEmail - Sends the verification letter again
+func (s *server) ResendVerificationEmail(ctx context.Context, req *users.ResendVerificationEmailRequest) (*emptypb.Empty, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.ResendVerificationEmail")
+	defer span.Finish()
+
+	err := s.userService.ResendVerificationEmail(ctx, req.Email)
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[ResendVerificationEmail]: %s", err)
+
+		return nil, toGRPCError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// RequestPasswordReset - Sends a letter with the password reset link
+func (s *server) RequestPasswordReset(ctx context.Context, req *users.RequestPasswordResetRequest) (*emptypb.Empty, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.RequestPasswordReset")
+	defer span.Finish()
+
+	err := s.userService.RequestPasswordReset(ctx, req.Email)
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[RequestPasswordReset]: %s", err)
+
+		return nil, toGRPCError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// ResetPassword - Sets a new password with the token from the password reset letter
+func (s *server) ResetPassword(ctx context.Context, req *users.ResetPasswordRequest) (*emptypb.Empty, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.ResetPassword")
+	defer span.Finish()
+
+	request := models.NewEmptyResetPasswordDTO().FromGRPC(req)
+	err := s.userService.ResetPassword(ctx, request)
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[ResetPassword]: %s", err)
+
+		return nil, toGRPCError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// RequestEmailChange - Sends a confirmation link to the new email and a notice to the current one
+func (s *server) RequestEmailChange(ctx context.Context, req *users.RequestEmailChangeRequest) (*emptypb.Empty, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.RequestEmailChange")
+	defer span.Finish()
+
+	request := models.NewEmptyRequestEmailChangeDTO().FromGRPC(req)
+	err := s.userService.RequestEmailChange(ctx, request)
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[RequestEmailChange]: %s", err)
+
+		return nil, toGRPCError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// ConfirmEmailChange - Replaces the email of the user with the address confirmed by the token
+func (s *server) ConfirmEmailChange(ctx context.Context, req *users.ConfirmEmailChangeRequest) (*emptypb.Empty, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.ConfirmEmailChange")
+	defer span.Finish()
+
+	err := s.userService.ConfirmEmailChange(ctx, req.Token)
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[ConfirmEmailChange]: %s", err)
+
+		return nil, toGRPCError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// CreateSession - Starts a session after a successful login
+func (s *server) CreateSession(ctx context.Context, req *users.SessionDTO) (*users.SessionDTO, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.CreateSession")
+	defer span.Finish()
+
+	session, err := models.NewEmptySessionDTO().FromGRPC(req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	createdSession, err := s.userService.CreateSession(ctx, session)
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[CreateSession]: %s", err)
+
+		return nil, toGRPCError(err)
+	}
+
+	return createdSession.ToGRPC(), nil
+}
+
+// RotateSession - Replaces the refresh token of a session, revoking the session on token reuse
+func (s *server) RotateSession(ctx context.Context, req *users.RotateSessionRequest) (*users.SessionDTO, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.RotateSession")
+	defer span.Finish()
+
+	rotate, err := models.NewEmptyRotateSessionDTO().FromGRPC(req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	rotatedSession, err := s.userService.RotateSession(ctx, rotate)
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[RotateSession]: %s", err)
+
+		return nil, toGRPCError(err)
+	}
+
+	return rotatedSession.ToGRPC(), nil
+}
+
+// RevokeSession - Ends a session of the user
+func (s *server) RevokeSession(ctx context.Context, req *users.SessionRequest) (*emptypb.Empty, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.RevokeSession")
+	defer span.Finish()
+
+	sessionID, err := uuid.Parse(req.Id)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	err = s.userService.RevokeSession(ctx, sessionID, int(req.UserId))
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[RevokeSession]: %s", err)
+
+		return nil, toGRPCError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// RevokeUserSessions - Ends all sessions of the user
+func (s *server) RevokeUserSessions(ctx context.Context, req *users.UserID) (*emptypb.Empty, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.RevokeUserSessions")
+	defer span.Finish()
+
+	err := s.userService.RevokeUserSessions(ctx, int(req.Id))
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[RevokeUserSessions]: %s", err)
+
+		return nil, toGRPCError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// EnrollTwoFactor - Issues a new TOTP secret and otpauth URI for the authenticator app
+func (s *server) EnrollTwoFactor(ctx context.Context, req *users.UserID) (*users.TwoFactorEnrollment, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.EnrollTwoFactor")
+	defer span.Finish()
+
+	enrollment, err := s.userService.EnrollTwoFactor(ctx, int(req.Id))
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[EnrollTwoFactor]: %s", err)
+
+		return nil, toGRPCError(err)
+	}
+
+	return enrollment.ToGRPC(), nil
+}
+
+// ConfirmTwoFactor - Enables two-factor authentication with the first code and returns recovery codes
+func (s *server) ConfirmTwoFactor(ctx context.Context, req *users.TwoFactorCodeRequest) (*users.RecoveryCodes, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.ConfirmTwoFactor")
+	defer span.Finish()
+
+	request := models.NewEmptyTwoFactorCodeDTO().FromGRPC(req)
+	codes, err := s.userService.ConfirmTwoFactor(ctx, request)
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[ConfirmTwoFactor]: %s", err)
+
+		return nil, toGRPCError(err)
+	}
+
+	return codes.ToGRPC(), nil
+}
+
+// DisableTwoFactor - Disables two-factor authentication after checking a TOTP or recovery code
+func (s *server) DisableTwoFactor(ctx context.Context, req *users.TwoFactorCodeRequest) (*emptypb.Empty, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.DisableTwoFactor")
+	defer span.Finish()
+
+	request := models.NewEmptyTwoFactorCodeDTO().FromGRPC(req)
+	err := s.userService.DisableTwoFactor(ctx, request)
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[DisableTwoFactor]: %s", err)
+
+		return nil, toGRPCError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// RegenerateRecoveryCodes - Replaces the recovery codes after checking a TOTP code
+func (s *server) RegenerateRecoveryCodes(ctx context.Context, req *users.TwoFactorCodeRequest) (*users.RecoveryCodes, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.RegenerateRecoveryCodes")
+	defer span.Finish()
+
+	request := models.NewEmptyTwoFactorCodeDTO().FromGRPC(req)
+	codes, err := s.userService.RegenerateRecoveryCodes(ctx, request)
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[RegenerateRecoveryCodes]: %s", err)
+
+		return nil, toGRPCError(err)
+	}
+
+	return codes.ToGRPC(), nil
+}
+
+// LoginTwoFactor - Completes the login started by Login with a TOTP or recovery code
+func (s *server) LoginTwoFactor(ctx context.Context, req *users.TwoFactorLoginRequest) (*users.UserDTO, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.LoginTwoFactor")
+	defer span.Finish()
+
+	request := models.NewEmptyTwoFactorLoginDTO().FromGRPC(req)
+	user, err := s.userService.LoginTwoFactor(ctx, request)
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[LoginTwoFactor]: %s", err)
+
+		return nil, toGRPCError(err)
+	}
+
+	return user.ToGRPC(), nil
+}
+
+// UnlockUser - Lifts the login lockout of a user and forgets the failed attempts
+func (s *server) UnlockUser(ctx context.Context, req *users.UserID) (*emptypb.Empty, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.UnlockUser")
+	defer span.Finish()
+
+	err := s.userService.UnlockUser(ctx, int(req.Id))
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[UnlockUser]: %s", err)
+
+		return nil, toGRPCError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// SetUserRole - Assigns a role to the user
+func (s *server) SetUserRole(ctx context.Context, req *users.SetUserRoleRequest) (*emptypb.Empty, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.SetUserRole")
+	defer span.Finish()
+
+	err := s.userService.SetUserRole(ctx, int(req.UserId), req.Role)
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[SetUserRole]: %s", err)
+
+		return nil, toGRPCError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// LoginExternal - Signs in a user verified by an external OpenID Connect provider
+func (s *server) LoginExternal(ctx context.Context, req *users.ExternalLoginDTO) (*users.UserDTO, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.LoginExternal")
+	defer span.Finish()
+
+	login := models.NewEmptyExternalLoginDTO().FromGRPC(req)
+	user, err := s.userService.LoginExternal(ctx, login)
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[LoginExternal]: %s", err)
+
+		return nil, toGRPCError(err)
+	}
+
+	return user.ToGRPC(), nil
+}
+
+// CreateApiToken - Issues a personal access token, the token itself is returned only once
+func (s *server) CreateApiToken(ctx context.Context, req *users.CreateApiTokenRequest) (*users.ApiTokenDTO, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.CreateApiToken")
+	defer span.Finish()
+
+	request := models.NewEmptyCreateApiTokenDTO().FromGRPC(req)
+	token, err := s.userService.CreateApiToken(ctx, request)
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[CreateApiToken]: %s", err)
+
+		return nil, toGRPCError(err)
+	}
+
+	return token.ToGRPC(), nil
+}
+
+// GetApiTokens - Lists active personal access tokens of the user
+func (s *server) GetApiTokens(ctx context.Context, req *users.UserID) (*users.ApiTokens, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.GetApiTokens")
+	defer span.Finish()
+
+	tokens, err := s.userService.GetApiTokens(ctx, int(req.Id))
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[GetApiTokens]: %s", err)
+
+		return nil, toGRPCError(err)
+	}
+
+	return models.ApiTokensToGRPC(tokens), nil
+}
+
+// RevokeApiToken - Revokes a personal access token of the user
+func (s *server) RevokeApiToken(ctx context.Context, req *users.ApiTokenRequest) (*emptypb.Empty, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.RevokeApiToken")
+	defer span.Finish()
+
+	tokenID, err := uuid.Parse(req.Id)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	err = s.userService.RevokeApiToken(ctx, tokenID, int(req.UserId))
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[RevokeApiToken]: %s", err)
+
+		return nil, toGRPCError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// AuthenticateApiToken - Resolves the owner and the scopes of a presented personal access token
+func (s *server) AuthenticateApiToken(ctx context.Context, req *users.AuthenticateApiTokenRequest) (*users.ApiTokenAuth, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.AuthenticateApiToken")
+	defer span.Finish()
+
+	auth, err := s.userService.AuthenticateApiToken(ctx, req.Token)
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[AuthenticateApiToken]: %s", err)
+
+		return nil, toGRPCError(err)
+	}
+
+	return auth.ToGRPC(), nil
+}
+
+// CreateOrganization - Creates an organization owned by the user
+func (s *server) CreateOrganization(ctx context.Context, req *users.CreateOrganizationRequest) (*users.OrganizationDTO, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.CreateOrganization")
+	defer span.Finish()
+
+	organization, err := s.userService.CreateOrganization(ctx, new(models.CreateOrganizationDTO).FromGRPC(req))
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[CreateOrganization]: %s", err)
+
+		return nil, toGRPCError(err)
+	}
+
+	return organization.ToGRPC(), nil
+}
+
+// GetUserOrganizations - Lists organizations of the user with the user's role in each
+func (s *server) GetUserOrganizations(ctx context.Context, req *users.UserID) (*users.Organizations, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.GetUserOrganizations")
+	defer span.Finish()
+
+	organizations, err := s.userService.GetUserOrganizations(ctx, int(req.Id))
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[GetUserOrganizations]: %s", err)
+
+		return nil, toGRPCError(err)
+	}
+
+	return models.OrganizationsToGRPC(organizations), nil
+}
+
+// ListOrganizationMembers - Lists members of an organization, available to any member
+func (s *server) ListOrganizationMembers(ctx context.Context, req *users.OrganizationRequest) (*users.OrganizationMembers, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.ListOrganizationMembers")
+	defer span.Finish()
+
+	members, err := s.userService.ListOrganizationMembers(ctx, int(req.OrganizationId), int(req.UserId))
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[ListOrganizationMembers]: %s", err)
+
+		return nil, toGRPCError(err)
+	}
+
+	return models.OrganizationMembersToGRPC(members), nil
+}
+
+// InviteMember - Sends an invitation email with an accept link
+func (s *server) InviteMember(ctx context.Context, req *users.InviteMemberRequest) (*users.InvitationDTO, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.InviteMember")
+	defer span.Finish()
+
+	invitation, err := s.userService.InviteMember(ctx, new(models.InviteMemberDTO).FromGRPC(req))
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[InviteMember]: %s", err)
+
+		return nil, toGRPCError(err)
+	}
+
+	return invitation.ToGRPC(), nil
+}
+
+// AcceptInvitation - Adds the user to the organization by the token from the invitation email
+func (s *server) AcceptInvitation(ctx context.Context, req *users.AcceptInvitationRequest) (*users.OrganizationDTO, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.AcceptInvitation")
+	defer span.Finish()
+
+	organization, err := s.userService.AcceptInvitation(ctx, req.Token, int(req.UserId))
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[AcceptInvitation]: %s", err)
+
+		return nil, toGRPCError(err)
+	}
+
+	return organization.ToGRPC(), nil
+}
+
+// SetMemberRole - Changes the role of an organization member
+func (s *server) SetMemberRole(ctx context.Context, req *users.MemberRequest) (*emptypb.Empty, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.SetMemberRole")
+	defer span.Finish()
+
+	err := s.userService.SetMemberRole(ctx, new(models.MemberDTO).FromGRPC(req))
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[SetMemberRole]: %s", err)
+
+		return nil, toGRPCError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// RemoveMember - Removes a member from the organization or lets the user leave it
+func (s *server) RemoveMember(ctx context.Context, req *users.MemberRequest) (*emptypb.Empty, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.RemoveMember")
+	defer span.Finish()
+
+	err := s.userService.RemoveMember(ctx, new(models.MemberDTO).FromGRPC(req))
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[RemoveMember]: %s", err)
+
+		return nil, toGRPCError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// CreateTeam - Creates a team in the organization
+func (s *server) CreateTeam(ctx context.Context, req *users.CreateTeamRequest) (*users.TeamDTO, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.CreateTeam")
+	defer span.Finish()
+
+	team, err := s.userService.CreateTeam(ctx, new(models.CreateTeamDTO).FromGRPC(req))
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[CreateTeam]: %s", err)
+
+		return nil, toGRPCError(err)
+	}
+
+	return team.ToGRPC(), nil
+}
+
+// GetTeams - Lists teams of the organization
+func (s *server) GetTeams(ctx context.Context, req *users.OrganizationRequest) (*users.Teams, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.GetTeams")
+	defer span.Finish()
+
+	teams, err := s.userService.GetTeams(ctx, int(req.OrganizationId), int(req.UserId))
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[GetTeams]: %s", err)
+
+		return nil, toGRPCError(err)
+	}
+
+	return models.TeamsToGRPC(teams), nil
+}
+
+// AddTeamMember - Adds an organization member to a team
+func (s *server) AddTeamMember(ctx context.Context, req *users.TeamMemberRequest) (*emptypb.Empty, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.AddTeamMember")
+	defer span.Finish()
+
+	err := s.userService.AddTeamMember(ctx, new(models.TeamMemberDTO).FromGRPC(req))
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[AddTeamMember]: %s", err)
+
+		return nil, toGRPCError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// RemoveTeamMember - Removes a member from a team
+func (s *server) RemoveTeamMember(ctx context.Context, req *users.TeamMemberRequest) (*emptypb.Empty, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.RemoveTeamMember")
+	defer span.Finish()
+
+	err := s.userService.RemoveTeamMember(ctx, new(models.TeamMemberDTO).FromGRPC(req))
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[RemoveTeamMember]: %s", err)
+
+		return nil, toGRPCError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// ListTeamMembers - Lists members of a team, available to any organization member
+func (s *server) ListTeamMembers(ctx context.Context, req *users.TeamMemberRequest) (*users.OrganizationMembers, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.ListTeamMembers")
+	defer span.Finish()
+
+	members, err := s.userService.ListTeamMembers(ctx, new(models.TeamMemberDTO).FromGRPC(req))
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[ListTeamMembers]: %s", err)
+
+		return nil, toGRPCError(err)
+	}
+
+	return models.OrganizationMembersToGRPC(members), nil
+}
+
+// UploadAvatar - Stores a new avatar and returns the updated user
+func (s *server) UploadAvatar(ctx context.Context, req *users.UploadAvatarRequest) (*users.UserDTO, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.UploadAvatar")
+	defer span.Finish()
+
+	user, err := s.userService.UploadAvatar(ctx, int(req.UserId), req.Image)
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[UploadAvatar]: %s", err)
+
+		return nil, toGRPCError(err)
+	}
+
+	return user.ToGRPC(), nil
+}
+
+// DeleteAvatar - Removes the avatar and returns the updated user
+func (s *server) DeleteAvatar(ctx context.Context, req *users.UserID) (*users.UserDTO, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.DeleteAvatar")
+	defer span.Finish()
+
+	user, err := s.userService.DeleteAvatar(ctx, int(req.Id))
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[DeleteAvatar]: %s", err)
+
+		return nil, toGRPCError(err)
+	}
+
+	return user.ToGRPC(), nil
+}
+
+// GetNotificationPreferences - Returns notification channels of the user for every event
+func (s *server) GetNotificationPreferences(ctx context.Context, req *users.UserID) (*users.NotificationPreferences, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.GetNotificationPreferences")
+	defer span.Finish()
+
+	preferences, err := s.userService.GetNotificationPreferences(ctx, int(req.Id))
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[GetNotificationPreferences]: %s", err)
+
+		return nil, toGRPCError(err)
+	}
+
+	return preferences.ToGRPC(), nil
+}
+
+// UpdateNotificationPreferences - Changes notification channels of the listed events
+func (s *server) UpdateNotificationPreferences(ctx context.Context, req *users.NotificationPreferences) (*users.NotificationPreferences, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.UpdateNotificationPreferences")
+	defer span.Finish()
+
+	request := models.NewEmptyNotificationPreferencesDTO().FromGRPC(req)
+	preferences, err := s.userService.UpdateNotificationPreferences(ctx, request)
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[UpdateNotificationPreferences]: %s", err)
+
+		return nil, toGRPCError(err)
+	}
+
+	return preferences.ToGRPC(), nil
+}
+
+// FilterNotificationRecipients - Keeps the users who want to be notified about the event via the channel
+func (s *server) FilterNotificationRecipients(ctx context.Context, req *users.NotificationRecipientsRequest) (*users.NotificationRecipients, error) {
+	ctx = ctxutil.SetRequestIdFromGrpcToContext(ctx)
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "grpc_handler.FilterNotificationRecipients")
+	defer span.Finish()
+
+	request := models.NewEmptyNotificationRecipientsDTO().FromGRPC(req)
+	recipients, err := s.userService.FilterNotificationRecipients(ctx, request)
+	if err != nil {
+		requestId, _ := ctxutil.GetRequestIDFromContext(ctx)
+		s.logger.Error().
+			Str("requestId", requestId).
+			Msgf("[FilterNotificationRecipients]: %s", err)
+
+		return nil, toGRPCError(err)
+	}
+
+	return models.NotificationRecipientsToGRPC(recipients), nil
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"github.com/google/uuid"
 	"users/internal/models"
 )
 
@@ -10,7 +11,69 @@ type UserService interface {
 	UpdateUser(ctx context.Context, updatedUser *models.UserDTO) (*models.UserDTO, error)
 	UpdatePassword(ctx context.Context, updatePassword *models.UpdateUserPasswordDTO) error
 	DeleteUser(ctx context.Context, userID int) error
+	GetUserDeletion(ctx context.Context, userID int) (*models.UserDeletionDTO, error)
+	RequestErasure(ctx context.Context, request *models.RequestErasureDTO) error
+	ConfirmErasure(ctx context.Context, token string) (*models.UserDeletionDTO, error)
+	RequestDataExport(ctx context.Context, userID int) (*models.DataExportDTO, error)
+	GetDataExport(ctx context.Context, userID int) (*models.DataExportDTO, error)
+	DownloadDataExport(ctx context.Context, token string) (*models.DataExportArchiveDTO, error)
 	GetUserByID(ctx context.Context, userID int) (*models.UserDTO, error)
+	GetUsersByIDs(ctx context.Context, userIDs []int) ([]models.UserDTO, error)
+	GetUsersByUsernames(ctx context.Context, usernames []string) ([]models.UserDTO, error)
 	GetUserByUsernameOrEmail(ctx context.Context, name, email string) (*models.UserDTO, error)
+	ListUsers(ctx context.Context, filter *models.ListUsersDTO) (*models.UsersPageDTO, error)
 	Login(ctx context.Context, login *models.UserLoginDTO) (*models.UserDTO, error)
+	VerifyEmail(ctx context.Context, token string) error
+	ResendVerificationEmail(ctx context.Context, email string) error
+	RequestPasswordReset(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, request *models.ResetPasswordDTO) error
+	RequestEmailChange(ctx context.Context, request *models.RequestEmailChangeDTO) error
+	ConfirmEmailChange(ctx context.Context, token string) error
+	CreateSession(ctx context.Context, session *models.SessionDTO) (*models.SessionDTO, error)
+	RotateSession(ctx context.Context, rotate *models.RotateSessionDTO) (*models.SessionDTO, error)
+	RevokeSession(ctx context.Context, sessionID uuid.UUID, userID int) error
+	RevokeUserSessions(ctx context.Context, userID int) error
+	EnrollTwoFactor(ctx context.Context, userID int) (*models.TwoFactorEnrollmentDTO, error)
+	ConfirmTwoFactor(ctx context.Context, request *models.TwoFactorCodeDTO) (*models.RecoveryCodesDTO, error)
+	DisableTwoFactor(ctx context.Context, request *models.TwoFactorCodeDTO) error
+	RegenerateRecoveryCodes(ctx context.Context, request *models.TwoFactorCodeDTO) (*models.RecoveryCodesDTO, error)
+	LoginTwoFactor(ctx context.Context, request *models.TwoFactorLoginDTO) (*models.UserDTO, error)
+	UnlockUser(ctx context.Context, userID int) error
+	SetUserRole(ctx context.Context, userID int, role string) error
+	LoginExternal(ctx context.Context, login *models.ExternalLoginDTO) (*models.UserDTO, error)
+	CreateApiToken(ctx context.Context, request *models.CreateApiTokenDTO) (*models.ApiTokenDTO, error)
+	GetApiTokens(ctx context.Context, userID int) ([]models.ApiTokenDTO, error)
+	RevokeApiToken(ctx context.Context, tokenID uuid.UUID, userID int) error
+	AuthenticateApiToken(ctx context.Context, token string) (*models.ApiTokenAuthDTO, error)
+	CreateOrganization(ctx context.Context, request *models.CreateOrganizationDTO) (*models.OrganizationDAO, error)
+	GetUserOrganizations(ctx context.Context, userID int) ([]models.OrganizationDAO, error)
+	ListOrganizationMembers(ctx context.Context, organizationID, userID int) ([]models.OrganizationMemberDAO, error)
+	InviteMember(ctx context.Context, request *models.InviteMemberDTO) (*models.InvitationDAO, error)
+	AcceptInvitation(ctx context.Context, token string, userID int) (*models.OrganizationDAO, error)
+	SetMemberRole(ctx context.Context, request *models.MemberDTO) error
+	RemoveMember(ctx context.Context, request *models.MemberDTO) error
+	CreateTeam(ctx context.Context, request *models.CreateTeamDTO) (*models.TeamDAO, error)
+	GetTeams(ctx context.Context, organizationID, userID int) ([]models.TeamDAO, error)
+	AddTeamMember(ctx context.Context, request *models.TeamMemberDTO) error
+	RemoveTeamMember(ctx context.Context, request *models.TeamMemberDTO) error
+	ListTeamMembers(ctx context.Context, request *models.TeamMemberDTO) ([]models.OrganizationMemberDAO, error)
+
+	UploadAvatar(ctx context.Context, userID int, data []byte) (*models.UserDTO, error)
+	DeleteAvatar(ctx context.Context, userID int) (*models.UserDTO, error)
+
+	GetNotificationPreferences(ctx context.Context, userID int) (*models.NotificationPreferencesDTO, error)
+	UpdateNotificationPreferences(ctx context.Context, request *models.NotificationPreferencesDTO) (*models.NotificationPreferencesDTO, error)
+	FilterNotificationRecipients(ctx context.Context, request *models.NotificationRecipientsDTO) ([]int, error)
+}
+
+// UserDeletionService - обработка ответов сервисов-участников на удаление пользователя и отмена удалений,
+// на которые они не ответили
+type UserDeletionService interface {
+	HandleUserDeletionReply(ctx context.Context, reply *models.UserDeletionReply) error
+	CancelStaleUserDeletions(ctx context.Context) error
+}
+
+// DataExportService - обработка ответов сервисов-участников на выгрузку данных пользователя
+type DataExportService interface {
+	HandleDataExportReply(ctx context.Context, reply *models.UserExportReply) error
 }
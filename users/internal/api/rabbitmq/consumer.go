@@ -0,0 +1,42 @@
+package rabbitmq
+
+import (
+	"context"
+	"fmt"
+	"github.com/rs/zerolog"
+	"users/config"
+	"users/internal/api"
+	rabbitConsumer "users/pkg/rabbitmq/consumer"
+)
+
+// ConsumeRabbitMessages принимает ответы сервисов-участников на удаление пользователя и выгрузку его данных
+func ConsumeRabbitMessages(
+	ctx context.Context,
+	cfg *config.Config,
+	logger *zerolog.Logger,
+	deletionService api.UserDeletionService,
+	exportService api.DataExportService,
+) error {
+	rabbitMQ, err := rabbitConsumer.New(&cfg.RabbitConfig, logger)
+	if err != nil {
+		return fmt.Errorf("[ConsumeRabbitMessages] connection to rabbitmq %w", err)
+	}
+
+	rabbitMQ.SetHandler(
+		cfg.UserDeletionRepliesQueue,
+		cfg.UserDeletionRepliesExchange,
+		NewUserDeletionRepliesHandler(logger, deletionService),
+	)
+	rabbitMQ.SetHandler(
+		cfg.UserExportRepliesQueue,
+		cfg.UserExportRepliesExchange,
+		NewDataExportRepliesHandler(logger, exportService),
+	)
+
+	rabbitMQ.Run()
+
+	<-ctx.Done()
+	logger.Info().Msg("[ConsumeRabbitMessages] shutting down message consumption")
+
+	return ctx.Err()
+}
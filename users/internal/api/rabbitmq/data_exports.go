@@ -0,0 +1,63 @@
+package rabbitmq
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/rs/zerolog"
+	"users/internal/api"
+	appErrors "users/internal/app_errors"
+	"users/internal/models"
+	"users/pkg/ctxutil"
+)
+
+type DataExportRepliesHandler struct {
+	logger        *zerolog.Logger
+	exportService api.DataExportService
+}
+
+func NewDataExportRepliesHandler(
+	logger *zerolog.Logger,
+	exportService api.DataExportService,
+) *DataExportRepliesHandler {
+	return &DataExportRepliesHandler{
+		logger:        logger,
+		exportService: exportService,
+	}
+}
+
+func (h *DataExportRepliesHandler) Handle(d amqp.Delivery) {
+	requestID, _ := d.Headers["requestId"].(string)
+	ctx := ctxutil.SetRequestIDToContext(context.Background(), requestID)
+
+	var reply models.UserExportReply
+	err := json.Unmarshal(d.Body, &reply)
+	if err != nil {
+		// сообщение не станет корректным при повторной доставке
+		h.logger.Error().
+			Str("requestId", requestID).
+			Msgf("[DataExportRepliesHandler] unmarshalling message: %s", err)
+		d.Ack(false)
+		return
+	}
+
+	err = h.exportService.HandleDataExportReply(ctx, &reply)
+	if err != nil {
+		h.logger.Error().
+			Str("requestId", requestID).
+			Msgf("[DataExportRepliesHandler] handling reply of %s to export %s: %s", reply.Service, reply.ExportID, err)
+
+		// ответ на неизвестную выгрузку или неизвестного типа обработать нельзя, остальные ошибки временные
+		if errors.Is(err, appErrors.ErrDataExportNotFound) || errors.Is(err, appErrors.ErrUnknownDataExportReply) {
+			d.Ack(false)
+		}
+		return
+	}
+
+	h.logger.Info().
+		Str("requestId", requestID).
+		Msgf("[DataExportRepliesHandler] %s replied %s to export %s", reply.Service, reply.EventType, reply.ExportID)
+
+	d.Ack(false)
+}
@@ -0,0 +1,63 @@
+package rabbitmq
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/rs/zerolog"
+	"users/internal/api"
+	appErrors "users/internal/app_errors"
+	"users/internal/models"
+	"users/pkg/ctxutil"
+)
+
+type UserDeletionRepliesHandler struct {
+	logger          *zerolog.Logger
+	deletionService api.UserDeletionService
+}
+
+func NewUserDeletionRepliesHandler(
+	logger *zerolog.Logger,
+	deletionService api.UserDeletionService,
+) *UserDeletionRepliesHandler {
+	return &UserDeletionRepliesHandler{
+		logger:          logger,
+		deletionService: deletionService,
+	}
+}
+
+func (h *UserDeletionRepliesHandler) Handle(d amqp.Delivery) {
+	requestID, _ := d.Headers["requestId"].(string)
+	ctx := ctxutil.SetRequestIDToContext(context.Background(), requestID)
+
+	var reply models.UserDeletionReply
+	err := json.Unmarshal(d.Body, &reply)
+	if err != nil {
+		// сообщение не станет корректным при повторной доставке
+		h.logger.Error().
+			Str("requestId", requestID).
+			Msgf("[UserDeletionRepliesHandler] unmarshalling message: %s", err)
+		d.Ack(false)
+		return
+	}
+
+	err = h.deletionService.HandleUserDeletionReply(ctx, &reply)
+	if err != nil {
+		h.logger.Error().
+			Str("requestId", requestID).
+			Msgf("[UserDeletionRepliesHandler] handling reply of %s to deletion %s: %s", reply.Service, reply.DeletionID, err)
+
+		// ответ на неизвестное удаление или неизвестного типа обработать нельзя, остальные ошибки временные
+		if errors.Is(err, appErrors.ErrUserDeletionNotFound) || errors.Is(err, appErrors.ErrUnknownUserDeletionReply) {
+			d.Ack(false)
+		}
+		return
+	}
+
+	h.logger.Info().
+		Str("requestId", requestID).
+		Msgf("[UserDeletionRepliesHandler] %s replied %s to deletion %s", reply.Service, reply.EventType, reply.DeletionID)
+
+	d.Ack(false)
+}
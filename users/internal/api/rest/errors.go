@@ -8,6 +8,7 @@ const (
 	ErrCodeBadRequest             ErrorCode = "BAD_REQUEST"
 	ErrCodeNotFound               ErrorCode = "NOT_FOUND"
 	ErrCodeInvalidJsonFormat      ErrorCode = "JSON_FORMAT_ERROR"
+	ErrCodeTooManyRequests        ErrorCode = "TOO_MANY_REQUESTS"
 )
 
 type ApiError struct {
@@ -38,11 +39,14 @@ func (e *ApiError) As(target interface{}) bool {
 }
 
 var (
-	ErrInternalApi                = NewApiError("an error occurred while processing the request", ErrCodeInternalAPIError)
-	ErrBadRequest                 = NewApiError("bad request", ErrCodeBadRequest)
-	ErrUsernameOrEmailAlreadyUsed = NewApiError("username or email already used", ErrCodeBadRequest)
-	ErrWrongCredentials           = NewApiError("wrong credentials", ErrCodeBadRequest)
-	ErrNotFound                   = NewApiError("not found", ErrCodeNotFound)
+	ErrInternalApi                     = NewApiError("an error occurred while processing the request", ErrCodeInternalAPIError)
+	ErrBadRequest                      = NewApiError("bad request", ErrCodeBadRequest)
+	ErrUsernameOrEmailAlreadyUsed      = NewApiError("username or email already used", ErrCodeBadRequest)
+	ErrWrongCredentials                = NewApiError("wrong credentials", ErrCodeBadRequest)
+	ErrNotFound                        = NewApiError("not found", ErrCodeNotFound)
+	ErrInvalidVerificationToken        = NewApiError("verification token is invalid or expired", ErrCodeBadRequest)
+	ErrInvalidPasswordResetToken       = NewApiError("password reset token is invalid or expired", ErrCodeBadRequest)
+	ErrPassAndConfirmationDoesNotMatch = NewApiError("password and confirmation does not match", ErrCodeBadRequest)
 )
 
 func (e *ApiError) IsRequestValidationError() bool {
@@ -40,7 +40,12 @@ func (h *UserHandler) RegisterUser(w http.ResponseWriter, r *http.Request) {
 	// передаем данные в слой сервиса
 	userID, err := h.userService.RegisterUser(ctx, newUser)
 	if err != nil {
-		if errors.As(err, &appErrors.ErrUsernameOrEmailIsUsed) {
+		var policyErr *appErrors.PasswordPolicyError
+		if errors.As(err, &policyErr) {
+			h.ErrorPasswordPolicy(w, policyErr)
+			return
+		}
+		if errors.Is(err, appErrors.ErrUsernameOrEmailIsUsed) {
 			h.ErrorUsernameOrEmailAlreadyUsed(w)
 			return
 		}
@@ -72,7 +77,7 @@ func (h *UserHandler) GetGetUserById(w http.ResponseWriter, r *http.Request) {
 	// передаем данные в слой сервиса
 	user, err := h.userService.GetUserByID(ctx, userID)
 	if err != nil {
-		if errors.As(err, &appErrors.ErrNotFound) {
+		if errors.Is(err, appErrors.ErrNotFound) {
 			h.ErrorNotFound(w)
 			return
 		}
@@ -122,6 +127,12 @@ func (h *UserHandler) UpdatePassword(w http.ResponseWriter, r *http.Request) {
 
 	// передаем данные в слой сервиса
 	if err := h.userService.UpdatePassword(ctx, passwordRequest); err != nil {
+		var policyErr *appErrors.PasswordPolicyError
+		if errors.As(err, &policyErr) {
+			h.ErrorPasswordPolicy(w, policyErr)
+			return
+		}
+
 		h.logger.Error().Msgf("[UpdatePassword] update password: %s", err)
 		h.ErrorInternalApi(w)
 		return
@@ -152,3 +163,137 @@ func (h *UserHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 	// возвращаем пользователю ответ - в данном случе просто status 200
 	h.JSONSuccessRespond(w, nil)
 }
+
+func (h *UserHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// Обработка запроса на подтверждение email по токену из письма.
+	var request = new(models.VerifyEmailDTO)
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.logger.Error().Msgf("[VerifyEmail] unmarshall: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	// передаем данные в слой сервиса
+	if err := h.userService.VerifyEmail(ctx, request.Token); err != nil {
+		if errors.Is(err, appErrors.ErrInvalidVerificationToken) {
+			h.ErrorInvalidVerificationToken(w)
+			return
+		}
+
+		h.logger.Error().Msgf("[VerifyEmail] verify: %s", err)
+		h.ErrorInternalApi(w)
+		return
+	}
+
+	// возвращаем пользователю ответ - в данном случе просто status 200
+	h.JSONSuccessRespond(w, nil)
+}
+
+func (h *UserHandler) ResendVerificationEmail(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// Обработка запроса на повторную отправку письма с подтверждением email.
+	var request = new(models.ResendVerificationEmailDTO)
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.logger.Error().Msgf("[ResendVerificationEmail] unmarshall: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	// передаем данные в слой сервиса
+	if err := h.userService.ResendVerificationEmail(ctx, request.Email); err != nil {
+		h.logger.Error().Msgf("[ResendVerificationEmail] resend: %s", err)
+		h.ErrorInternalApi(w)
+		return
+	}
+
+	// возвращаем пользователю ответ - в данном случе просто status 200
+	h.JSONSuccessRespond(w, nil)
+}
+
+func (h *UserHandler) RequestPasswordReset(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// Обработка запроса на отправку письма со сбросом пароля.
+	var request = new(models.RequestPasswordResetDTO)
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.logger.Error().Msgf("[RequestPasswordReset] unmarshall: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	// передаем данные в слой сервиса
+	if err := h.userService.RequestPasswordReset(ctx, request.Email); err != nil {
+		h.logger.Error().Msgf("[RequestPasswordReset] request reset: %s", err)
+		h.ErrorInternalApi(w)
+		return
+	}
+
+	// возвращаем пользователю ответ - в данном случе просто status 200
+	h.JSONSuccessRespond(w, nil)
+}
+
+func (h *UserHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// Обработка запроса на установку нового пароля по токену из письма.
+	var request = new(models.ResetPasswordDTO)
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.logger.Error().Msgf("[ResetPassword] unmarshall: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	// передаем данные в слой сервиса
+	if err := h.userService.ResetPassword(ctx, request); err != nil {
+		var policyErr *appErrors.PasswordPolicyError
+		if errors.As(err, &policyErr) {
+			h.ErrorPasswordPolicy(w, policyErr)
+			return
+		}
+		if errors.Is(err, appErrors.ErrInvalidPasswordResetToken) {
+			h.ErrorInvalidPasswordResetToken(w)
+			return
+		}
+		if errors.Is(err, appErrors.ErrPassAndConfirmationDoesNotMatch) {
+			h.ErrorPassAndConfirmationDoesNotMatch(w)
+			return
+		}
+
+		h.logger.Error().Msgf("[ResetPassword] reset: %s", err)
+		h.ErrorInternalApi(w)
+		return
+	}
+
+	// возвращаем пользователю ответ - в данном случе просто status 200
+	h.JSONSuccessRespond(w, nil)
+}
+
+func (h *UserHandler) UnlockUser(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// Обработка запроса на снятие блокировки входа.
+	userID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.logger.Error().Msgf("[UnlockUser] get id from query: %s", err)
+		h.ErrorBadRequest(w)
+		return
+	}
+
+	// передаем данные в слой сервиса
+	if err := h.userService.UnlockUser(ctx, userID); err != nil {
+		if errors.Is(err, appErrors.ErrNotFound) {
+			h.ErrorNotFound(w)
+			return
+		}
+
+		h.logger.Error().Msgf("[UnlockUser] unlock: %s", err)
+		h.ErrorInternalApi(w)
+		return
+	}
+
+	// возвращаем пользователю ответ - в данном случе просто status 200
+	h.JSONSuccessRespond(w, nil)
+}
@@ -3,6 +3,7 @@ package rest
 import (
 	"encoding/json"
 	"net/http"
+	appErrors "users/internal/app_errors"
 )
 
 type ErrorResponse struct {
@@ -26,6 +27,23 @@ func (h *UserHandler) ErrorNotFound(w http.ResponseWriter) {
 	h.JSONErrorRespond(w, http.StatusNotFound, ErrNotFound)
 }
 
+func (h *UserHandler) ErrorInvalidVerificationToken(w http.ResponseWriter) {
+	h.JSONErrorRespond(w, http.StatusBadRequest, ErrInvalidVerificationToken)
+}
+
+func (h *UserHandler) ErrorInvalidPasswordResetToken(w http.ResponseWriter) {
+	h.JSONErrorRespond(w, http.StatusBadRequest, ErrInvalidPasswordResetToken)
+}
+
+func (h *UserHandler) ErrorPassAndConfirmationDoesNotMatch(w http.ResponseWriter) {
+	h.JSONErrorRespond(w, http.StatusBadRequest, ErrPassAndConfirmationDoesNotMatch)
+}
+
+// ErrorPasswordPolicy отвечает на пароль, не прошедший требования, в сообщении перечислены все нарушения
+func (h *UserHandler) ErrorPasswordPolicy(w http.ResponseWriter, policyErr *appErrors.PasswordPolicyError) {
+	h.JSONErrorRespond(w, http.StatusBadRequest, NewApiError(policyErr.Error(), ErrCodeRequestValidationError))
+}
+
 func (h *UserHandler) ErrorInternalApi(w http.ResponseWriter) {
 	h.JSONErrorRespond(w, http.StatusInternalServerError, ErrInternalApi)
 }
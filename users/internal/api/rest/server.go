@@ -9,7 +9,7 @@ import (
 	"users/internal/api"
 )
 
-func NewRestApi(cfg *config.Config, logger *zerolog.Logger, userService api.UserService) error {
+func NewRestApi(cfg *config.Config, logger *zerolog.Logger, userService api.UserService, files http.Handler) error {
 	// инициализируем хэндлер
 	userRestHandler := NewUserHandler(logger, userService)
 
@@ -23,8 +23,20 @@ func NewRestApi(cfg *config.Config, logger *zerolog.Logger, userService api.User
 	router.HandleFunc("/users/update", userRestHandler.UpdateUser).Methods(http.MethodPut)
 	// обновить пароль
 	router.HandleFunc("/users/update-password", userRestHandler.UpdatePassword).Methods(http.MethodPut)
+	// подтвердить email по токену из письма
+	router.HandleFunc("/users/verify", userRestHandler.VerifyEmail).Methods(http.MethodPost)
+	// повторно отправить письмо с подтверждением email
+	router.HandleFunc("/users/verify/resend", userRestHandler.ResendVerificationEmail).Methods(http.MethodPost)
+	// отправить письмо со ссылкой на сброс пароля
+	router.HandleFunc("/users/password-reset", userRestHandler.RequestPasswordReset).Methods(http.MethodPost)
+	// установить новый пароль по токену из письма
+	router.HandleFunc("/users/password-reset/confirm", userRestHandler.ResetPassword).Methods(http.MethodPost)
+	// снять блокировку входа после серии неудачных попыток
+	router.HandleFunc("/users/{id:[0-9]+}/unlock", userRestHandler.UnlockUser).Methods(http.MethodPost)
 	// удалить пользователя
 	router.HandleFunc("/users/delete/{id:[0-9]+}", userRestHandler.DeleteUser).Methods(http.MethodDelete)
+	// раздать загруженные файлы, например аватары
+	router.PathPrefix("/files/").Handler(http.StripPrefix("/files", files)).Methods(http.MethodGet, http.MethodHead)
 
 	appAddr := fmt.Sprintf("%s:%s", cfg.App.AppHost, cfg.App.AppPort)
 	logger.Info().Msgf("running REST server at '%s'", appAddr)
@@ -1,6 +1,11 @@
 package app_errors
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
 
 var (
 	ErrUsernameOrEmailIsUsed           = errors.New("username or email already used")
@@ -8,4 +13,103 @@ var (
 	ErrWrongCredentials                = errors.New("wrong credentials")
 	ErrIncorrectOldPassword            = errors.New("incorrect old password")
 	ErrPassAndConfirmationDoesNotMatch = errors.New("password and confirmation does not match")
+	ErrInvalidVerificationToken        = errors.New("verification token is invalid or expired")
+	ErrEmailNotVerified                = errors.New("email is not verified")
+	ErrInvalidPasswordResetToken       = errors.New("password reset token is invalid or expired")
+	ErrSessionNotFound                 = errors.New("session not found or revoked")
+	ErrRefreshTokenReused              = errors.New("refresh token reused, session revoked")
+	ErrTwoFactorNotEnabled             = errors.New("two-factor authentication is not enabled")
+	ErrTwoFactorAlreadyEnabled         = errors.New("two-factor authentication is already enabled")
+	ErrInvalidTwoFactorCode            = errors.New("invalid two-factor code")
+	ErrInvalidTwoFactorChallenge       = errors.New("two-factor challenge is invalid or expired")
+	ErrTwoFactorRequiredByRole         = errors.New("two-factor authentication is required for the user's role")
+	ErrLoginThrottled                  = errors.New("too many failed login attempts, try again later")
+	ErrAccountLocked                   = errors.New("account is temporarily locked after too many failed login attempts")
+	ErrUnknownRole                     = errors.New("unknown role")
+	ErrIdentityNotFound                = errors.New("external identity is not linked")
+	ErrExternalEmailNotVerified        = errors.New("identity provider did not confirm the email")
+	ErrExternalEmailConflict           = errors.New("email belongs to an account that is not verified, sign in with password and verify it first")
+	ErrInvalidSort                     = errors.New("unknown sort field")
+	ErrApiTokenNotFound                = errors.New("api token not found or revoked")
+	ErrInvalidApiToken                 = errors.New("api token is invalid, expired or revoked")
+	ErrApiTokenNameInvalid             = errors.New("api token name must be from 1 to 64 characters")
+	ErrUnknownApiTokenScope            = errors.New("unknown api token scope")
+	ErrApiTokenTTLInvalid              = errors.New("api token lifetime is out of the allowed range")
+	ErrTooManyApiTokens                = errors.New("too many active api tokens, revoke unused ones first")
+	ErrOrganizationNotFound            = errors.New("organization not found")
+	ErrOrganizationNameInvalid         = errors.New("organization name must be from 1 to 128 characters")
+	ErrOrganizationForbidden           = errors.New("not enough rights in the organization")
+	ErrUnknownOrganizationRole         = errors.New("unknown organization role")
+	ErrLastOrganizationOwner           = errors.New("organization must keep at least one owner")
+	ErrMemberNotFound                  = errors.New("organization member not found")
+	ErrAlreadyMember                   = errors.New("user is already a member of the organization")
+	ErrInvalidEmail                    = errors.New("invalid email")
+	ErrInvalidInvitation               = errors.New("invitation is invalid, expired or revoked")
+	ErrInvitationEmailMismatch         = errors.New("invitation was sent to another email")
+	ErrTeamNotFound                    = errors.New("team not found")
+	ErrTeamNameInvalid                 = errors.New("team name must be from 1 to 128 characters")
+	ErrTeamNameIsUsed                  = errors.New("team with this name already exists in the organization")
+	ErrUsernameInvalid                 = errors.New("username must be from 1 to 255 characters")
+	ErrDisplayNameInvalid              = errors.New("display name must be at most 64 characters")
+	ErrUnknownTimezone                 = errors.New("unknown timezone, use an IANA name like Europe/Moscow")
+	ErrUnknownLocale                   = errors.New("unknown locale, use a BCP 47 tag like en or ru-RU")
+	ErrInvalidAvatar                   = errors.New("avatar must be a PNG, JPEG or GIF image")
+	ErrAvatarTooLarge                  = errors.New("avatar image is too large")
+	ErrUnknownNotificationEvent        = errors.New("unknown notification event")
+	ErrUnknownNotificationChannel      = errors.New("unknown notification channel")
+	ErrEmailChangeThrottled            = errors.New("email change was requested recently, try again later")
+	ErrInvalidEmailChangeToken         = errors.New("email change token is invalid or expired")
+	ErrEmailUnchanged                  = errors.New("new email is the same as the current one")
+	ErrEmailChangeRequired             = errors.New("email is changed only with confirmation, request an email change")
+	ErrUserDeletionInProgress          = errors.New("user deletion is already in progress")
+	ErrUserDeletionNotFound            = errors.New("user deletion not found")
+	ErrUnknownUserDeletionReply        = errors.New("unknown user deletion reply")
+	ErrMalformedPasswordHash           = errors.New("stored password hash is malformed")
+	ErrPasswordPolicy                  = errors.New("password does not meet the requirements")
+	ErrTooManyUserIDs                  = errors.New("too many user ids in one request")
+	ErrDataExportThrottled             = errors.New("data export was requested recently, try again later")
+	ErrDataExportNotFound              = errors.New("data export not found")
+	ErrInvalidDataExportToken          = errors.New("data export link is invalid or expired")
+	ErrUnknownDataExportReply          = errors.New("unknown data export reply")
+	ErrErasureThrottled                = errors.New("account erasure was requested recently, try again later")
+	ErrInvalidErasureToken             = errors.New("account erasure token is invalid or expired")
 )
+
+// LoginThrottledError - вход временно запрещен, Err - ErrLoginThrottled или ErrAccountLocked,
+// RetryAfter - через сколько можно попробовать снова
+type LoginThrottledError struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+func (e *LoginThrottledError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *LoginThrottledError) Unwrap() error {
+	return e.Err
+}
+
+// FieldViolation - ошибка в одном поле запроса, Field - имя поля, как оно называется в запросе
+type FieldViolation struct {
+	Field   string
+	Message string
+}
+
+// PasswordPolicyError - пароль не соответствует требованиям, Violations - все нарушенные требования
+type PasswordPolicyError struct {
+	Violations []FieldViolation
+}
+
+func (e *PasswordPolicyError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, violation := range e.Violations {
+		messages[i] = violation.Message
+	}
+
+	return fmt.Sprintf("%s: %s", ErrPasswordPolicy, strings.Join(messages, "; "))
+}
+
+func (e *PasswordPolicyError) Unwrap() error {
+	return ErrPasswordPolicy
+}
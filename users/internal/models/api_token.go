@@ -0,0 +1,139 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	ts "google.golang.org/protobuf/types/known/timestamppb"
+	"time"
+	"users/pkg/grpc_stubs/users"
+)
+
+// ApiTokenPrefix - начало каждого персонального токена. По нему gateway отличает токен от JWT,
+// а сканеры секретов находят токены, случайно попавшие в код
+const ApiTokenPrefix = "todo_pat_"
+
+// Области действия персонального токена. Токен без области admin не получает разрешений роли владельца
+const (
+	ApiTokenScopeReadTodos    = "read:todos"
+	ApiTokenScopeWriteTodos   = "write:todos"
+	ApiTokenScopeReadProfile  = "read:profile"
+	ApiTokenScopeWriteProfile = "write:profile"
+	ApiTokenScopeAdmin        = "admin"
+)
+
+// ApiTokenScopes - все области, которые можно выдать токену
+var ApiTokenScopes = []string{
+	ApiTokenScopeReadTodos,
+	ApiTokenScopeWriteTodos,
+	ApiTokenScopeReadProfile,
+	ApiTokenScopeWriteProfile,
+	ApiTokenScopeAdmin,
+}
+
+// ApiTokenDAO - персональный токен доступа. В базе хранится только хэш токена
+type ApiTokenDAO struct {
+	ID         uuid.UUID  `db:"id"`
+	UserID     int        `db:"user_id"`
+	Name       string     `db:"name"`
+	TokenHash  string     `db:"token_hash"`
+	Scopes     []string   `db:"scopes"`
+	ExpiresAt  time.Time  `db:"expires_at"`
+	LastUsedAt *time.Time `db:"last_used_at"`
+	RevokedAt  *time.Time `db:"revoked_at"`
+	CreatedAt  time.Time  `db:"created_at"`
+}
+
+// ApiTokenDTO - data transfer object - токен в том виде, в котором его видит владелец.
+// Token заполнен только в ответ на создание, потом узнать токен нельзя
+type ApiTokenDTO struct {
+	ID         uuid.UUID  `json:"id" example:"c0e708fa-a7df-4d9f-a1b8-a3bfe63c433c"`
+	UserID     int        `json:"user_id" example:"1"`
+	Name       string     `json:"name" example:"ci"`
+	Scopes     []string   `json:"scopes" example:"read:todos"`
+	Token      string     `json:"token,omitempty"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreateApiTokenDTO - data transfer object - струтктура запроса на выпуск токена
+type CreateApiTokenDTO struct {
+	UserID int      `json:"user_id" example:"1"`
+	Name   string   `json:"name" example:"ci"`
+	Scopes []string `json:"scopes" example:"read:todos"`
+	// ExpiresIn - срок действия токена, 0 - срок по умолчанию
+	ExpiresIn time.Duration `json:"-"`
+}
+
+// ApiTokenAuthDTO - владелец предъявленного токена и то, что токен ему разрешает
+type ApiTokenAuthDTO struct {
+	UserID      int
+	TokenID     uuid.UUID
+	Scopes      []string
+	Permissions []string
+	// Timezone - часовой пояс владельца, в нем понимаются даты в фильтрах задач
+	Timezone string
+}
+
+func (d *ApiTokenDAO) ToDTO() *ApiTokenDTO {
+	return &ApiTokenDTO{
+		ID:         d.ID,
+		UserID:     d.UserID,
+		Name:       d.Name,
+		Scopes:     d.Scopes,
+		ExpiresAt:  d.ExpiresAt,
+		LastUsedAt: d.LastUsedAt,
+		CreatedAt:  d.CreatedAt,
+	}
+}
+
+func (d *ApiTokenDTO) ToGRPC() *users.ApiTokenDTO {
+	token := &users.ApiTokenDTO{
+		Id:        d.ID.String(),
+		UserId:    int32(d.UserID),
+		Name:      d.Name,
+		Scopes:    d.Scopes,
+		Token:     d.Token,
+		ExpiresAt: ts.New(d.ExpiresAt),
+		CreatedAt: ts.New(d.CreatedAt),
+	}
+
+	if d.LastUsedAt != nil {
+		token.LastUsedAt = ts.New(*d.LastUsedAt)
+	}
+
+	return token
+}
+
+func ApiTokensToGRPC(tokens []ApiTokenDTO) *users.ApiTokens {
+	response := &users.ApiTokens{
+		Items: make([]*users.ApiTokenDTO, len(tokens)),
+	}
+
+	for i := range tokens {
+		response.Items[i] = tokens[i].ToGRPC()
+	}
+
+	return response
+}
+
+func NewEmptyCreateApiTokenDTO() *CreateApiTokenDTO {
+	return &CreateApiTokenDTO{}
+}
+
+func (d *CreateApiTokenDTO) FromGRPC(in *users.CreateApiTokenRequest) *CreateApiTokenDTO {
+	d.UserID = int(in.UserId)
+	d.Name = in.Name
+	d.Scopes = in.Scopes
+	d.ExpiresIn = time.Duration(in.ExpiresInSeconds) * time.Second
+	return d
+}
+
+func (d *ApiTokenAuthDTO) ToGRPC() *users.ApiTokenAuth {
+	return &users.ApiTokenAuth{
+		UserId:      int32(d.UserID),
+		TokenId:     d.TokenID.String(),
+		Scopes:      d.Scopes,
+		Permissions: d.Permissions,
+		Timezone:    d.Timezone,
+	}
+}
@@ -0,0 +1,202 @@
+package models
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	ts "google.golang.org/protobuf/types/known/timestamppb"
+	"time"
+	"users/pkg/grpc_stubs/users"
+)
+
+// Состояния выгрузки данных пользователя
+const (
+	DataExportStatusPending = "pending"
+	DataExportStatusReady   = "ready"
+	DataExportStatusFailed  = "failed"
+)
+
+// Состояния части выгрузки от сервиса-участника
+const (
+	DataExportPartPending  = "pending"
+	DataExportPartReceived = "received"
+	DataExportPartFailed   = "failed"
+)
+
+// Типы сообщений о выгрузке данных пользователя между сервисами
+const (
+	UserEventTypeUserExportRequested = "user_export_requested"
+	UserEventTypeUserExportData      = "user_export_data"
+	UserEventTypeUserExportFailed    = "user_export_failed"
+)
+
+// UserExportRequestedEvent - доменное событие: пользователь запросил выгрузку, сервисы должны прислать
+// его данные в UserExportReply с тем же ExportID
+type UserExportRequestedEvent struct {
+	EventType string    `json:"event_type"`
+	ExportID  uuid.UUID `json:"export_id"`
+	UserID    int       `json:"user_id"`
+}
+
+// UserExportReply - ответ сервиса-участника на UserExportRequestedEvent
+type UserExportReply struct {
+	EventType string    `json:"event_type"`
+	ExportID  uuid.UUID `json:"export_id"`
+	UserID    int       `json:"user_id"`
+	Service   string    `json:"service"`
+	// Data - данные пользователя в сервисе, только в user_export_data. В архив попадают как есть
+	Data json.RawMessage `json:"data,omitempty"`
+	// Error - почему сервис не смог выгрузить данные, только в user_export_failed
+	Error string `json:"error,omitempty"`
+}
+
+// DataExportPartDAO - часть выгрузки от одного сервиса
+type DataExportPartDAO struct {
+	Service   string    `db:"service"`
+	Status    string    `db:"status"`
+	Data      []byte    `db:"data"`
+	Error     string    `db:"error"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// DataExportDAO - выгрузка данных пользователя и ее части от сервисов-участников
+type DataExportDAO struct {
+	ID        uuid.UUID  `db:"id"`
+	UserID    int        `db:"user_id"`
+	Status    string     `db:"status"`
+	Error     string     `db:"error"`
+	ExpiresAt *time.Time `db:"expires_at"`
+	CreatedAt time.Time  `db:"created_at"`
+	UpdatedAt time.Time  `db:"updated_at"`
+	Parts     []DataExportPartDAO
+}
+
+// PartsStatus сводит части к одному состоянию: failed, если хотя бы один сервис не смог выгрузить данные,
+// received, если прислали все, иначе pending
+func (d *DataExportDAO) PartsStatus() string {
+	status := DataExportPartReceived
+	for _, part := range d.Parts {
+		switch part.Status {
+		case DataExportPartFailed:
+			return DataExportPartFailed
+		case DataExportPartPending:
+			status = DataExportPartPending
+		}
+	}
+
+	return status
+}
+
+// FailedPartsError собирает причины отказа сервисов в одну строку
+func (d *DataExportDAO) FailedPartsError() string {
+	var reason string
+	for _, part := range d.Parts {
+		if part.Status != DataExportPartFailed {
+			continue
+		}
+		if reason != "" {
+			reason += "; "
+		}
+		reason += part.Service + ": " + part.Error
+	}
+
+	return reason
+}
+
+// DataExportPartDTO - data transfer object - часть выгрузки от одного сервиса
+type DataExportPartDTO struct {
+	Service   string    `json:"service" example:"todo"`
+	Status    string    `json:"status" example:"received"`
+	Error     string    `json:"error,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// DataExportDTO - data transfer object - состояние выгрузки данных пользователя
+type DataExportDTO struct {
+	ID        uuid.UUID           `json:"id" example:"c0e708fa-a7df-4d9f-a1b8-a3bfe63c433c"`
+	UserID    int                 `json:"user_id" example:"1"`
+	Status    string              `json:"status" example:"pending"`
+	Error     string              `json:"error,omitempty"`
+	Parts     []DataExportPartDTO `json:"parts"`
+	CreatedAt time.Time           `json:"created_at"`
+	UpdatedAt time.Time           `json:"updated_at"`
+	ExpiresAt *time.Time          `json:"expires_at,omitempty"`
+}
+
+func (d *DataExportDAO) ToDTO() *DataExportDTO {
+	response := &DataExportDTO{
+		ID:        d.ID,
+		UserID:    d.UserID,
+		Status:    d.Status,
+		Error:     d.Error,
+		Parts:     make([]DataExportPartDTO, len(d.Parts)),
+		CreatedAt: d.CreatedAt,
+		UpdatedAt: d.UpdatedAt,
+		ExpiresAt: d.ExpiresAt,
+	}
+
+	for i, part := range d.Parts {
+		response.Parts[i] = DataExportPartDTO{
+			Service:   part.Service,
+			Status:    part.Status,
+			Error:     part.Error,
+			UpdatedAt: part.UpdatedAt,
+		}
+	}
+
+	return response
+}
+
+func (d *DataExportDTO) ToGRPC() *users.DataExport {
+	response := &users.DataExport{
+		Id:        d.ID.String(),
+		UserId:    int32(d.UserID),
+		Status:    d.Status,
+		Error:     d.Error,
+		Parts:     make([]*users.DataExportPart, len(d.Parts)),
+		CreatedAt: ts.New(d.CreatedAt),
+		UpdatedAt: ts.New(d.UpdatedAt),
+	}
+	if d.ExpiresAt != nil {
+		response.ExpiresAt = ts.New(*d.ExpiresAt)
+	}
+
+	for i, part := range d.Parts {
+		response.Parts[i] = &users.DataExportPart{
+			Service:   part.Service,
+			Status:    part.Status,
+			Error:     part.Error,
+			UpdatedAt: ts.New(part.UpdatedAt),
+		}
+	}
+
+	return response
+}
+
+// DataExportArchiveDTO - data transfer object - архив выгрузки для скачивания
+type DataExportArchiveDTO struct {
+	FileName string
+	Content  []byte
+}
+
+func (d *DataExportArchiveDTO) ToGRPC() *users.DataExportArchive {
+	return &users.DataExportArchive{
+		FileName: d.FileName,
+		Content:  d.Content,
+	}
+}
+
+// RequestErasureDTO - data transfer object - запрос на удаление аккаунта
+type RequestErasureDTO struct {
+	UserID   int    `json:"-"`
+	Password string `json:"password" example:"password"`
+}
+
+func NewEmptyRequestErasureDTO() *RequestErasureDTO {
+	return &RequestErasureDTO{}
+}
+
+func (d *RequestErasureDTO) FromGRPC(in *users.RequestErasureRequest) *RequestErasureDTO {
+	d.UserID = int(in.UserId)
+	d.Password = in.Password
+	return d
+}
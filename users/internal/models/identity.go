@@ -0,0 +1,34 @@
+package models
+
+import "users/pkg/grpc_stubs/users"
+
+// UserIdentityDAO - привязка аккаунта к пользователю внешнего провайдера OpenID Connect.
+// Пара Issuer и Subject однозначно определяет пользователя у провайдера
+type UserIdentityDAO struct {
+	Issuer  string `db:"issuer"`
+	Subject string `db:"subject"`
+	UserID  int    `db:"user_id"`
+	Email   string `db:"email"`
+}
+
+// ExternalLoginDTO - данные пользователя из ID токена, который gateway уже проверил
+type ExternalLoginDTO struct {
+	Issuer            string `json:"issuer" example:"https://sso.example.com"`
+	Subject           string `json:"subject" example:"248289761001"`
+	Email             string `json:"email" example:"user@example.com"`
+	EmailVerified     bool   `json:"email_verified" example:"true"`
+	PreferredUsername string `json:"preferred_username" example:"username"`
+}
+
+func NewEmptyExternalLoginDTO() *ExternalLoginDTO {
+	return &ExternalLoginDTO{}
+}
+
+func (d *ExternalLoginDTO) FromGRPC(in *users.ExternalLoginDTO) *ExternalLoginDTO {
+	d.Issuer = in.Issuer
+	d.Subject = in.Subject
+	d.Email = in.Email
+	d.EmailVerified = in.EmailVerified
+	d.PreferredUsername = in.PreferredUsername
+	return d
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// Виды счетчиков неудачных входов: по аккаунту и по адресу, с которого приходят запросы
+const (
+	LoginThrottleKindUser = "user"
+	LoginThrottleKindIP   = "ip"
+)
+
+// LoginThrottleDAO - неудачные попытки входа по аккаунту или по адресу. Пока RetryAfter больше нуля,
+// новые попытки не принимаются; Locked означает, что аккаунт заблокирован после слишком многих попыток
+type LoginThrottleDAO struct {
+	Kind       string        `db:"kind"`
+	Key        string        `db:"key"`
+	Failures   int           `db:"failures"`
+	Locked     bool          `db:"locked"`
+	RetryAfter time.Duration `db:"-"`
+}
@@ -0,0 +1,119 @@
+package models
+
+import (
+	"users/pkg/grpc_stubs/users"
+)
+
+// События, о которых пользователь может получать уведомления. Значения совпадают с типами
+// сообщений todo, чтобы notifications мог проверять настройки без сопоставления имен
+const (
+	NotificationEventTodoCreated   = "create_todo"
+	NotificationEventTodoUpdated   = "update_todo"
+	NotificationEventTodoDeleted   = "delete_todo"
+	NotificationEventTodoMentioned = "mention_todo"
+	NotificationEventTodoReminder  = "reminder_todo"
+)
+
+// NotificationEvents - все события, которые можно настроить, в порядке выдачи клиенту
+var NotificationEvents = []string{
+	NotificationEventTodoCreated,
+	NotificationEventTodoUpdated,
+	NotificationEventTodoDeleted,
+	NotificationEventTodoMentioned,
+	NotificationEventTodoReminder,
+}
+
+// Каналы доставки уведомлений
+const (
+	NotificationChannelEmail = "email"
+)
+
+// NotificationChannels - все каналы. Без сохраненной настройки событие приходит по каждому из них
+var NotificationChannels = []string{
+	NotificationChannelEmail,
+}
+
+// NotificationPreferenceDAO - каналы, выбранные пользователем для события
+type NotificationPreferenceDAO struct {
+	UserID   int      `db:"user_id"`
+	Event    string   `db:"event"`
+	Channels []string `db:"channels"`
+}
+
+// NotificationPreferenceDTO - data transfer object - каналы, по которым приходит уведомление о событии.
+// Пустой список каналов означает, что уведомления о событии отключены
+type NotificationPreferenceDTO struct {
+	Event    string   `json:"event" example:"update_todo"`
+	Channels []string `json:"channels" example:"email"`
+}
+
+// NotificationPreferencesDTO - data transfer object - настройки уведомлений пользователя
+type NotificationPreferencesDTO struct {
+	UserID int                         `json:"-"`
+	Items  []NotificationPreferenceDTO `json:"items"`
+}
+
+// NotificationRecipientsDTO - data transfer object - запрос получателей события по каналу
+type NotificationRecipientsDTO struct {
+	Event   string
+	Channel string
+	UserIDs []int
+}
+
+func NewEmptyNotificationPreferencesDTO() *NotificationPreferencesDTO {
+	return &NotificationPreferencesDTO{}
+}
+
+func (d *NotificationPreferencesDTO) FromGRPC(in *users.NotificationPreferences) *NotificationPreferencesDTO {
+	d.UserID = int(in.UserId)
+	d.Items = make([]NotificationPreferenceDTO, len(in.Items))
+	for i, item := range in.Items {
+		d.Items[i] = NotificationPreferenceDTO{
+			Event:    item.Event,
+			Channels: item.Channels,
+		}
+	}
+	return d
+}
+
+func (d *NotificationPreferencesDTO) ToGRPC() *users.NotificationPreferences {
+	response := &users.NotificationPreferences{
+		UserId: int32(d.UserID),
+		Items:  make([]*users.NotificationPreferenceDTO, len(d.Items)),
+	}
+
+	for i := range d.Items {
+		response.Items[i] = &users.NotificationPreferenceDTO{
+			Event:    d.Items[i].Event,
+			Channels: d.Items[i].Channels,
+		}
+	}
+
+	return response
+}
+
+func NewEmptyNotificationRecipientsDTO() *NotificationRecipientsDTO {
+	return &NotificationRecipientsDTO{}
+}
+
+func (d *NotificationRecipientsDTO) FromGRPC(in *users.NotificationRecipientsRequest) *NotificationRecipientsDTO {
+	d.Event = in.Event
+	d.Channel = in.Channel
+	d.UserIDs = make([]int, len(in.UserIds))
+	for i, userID := range in.UserIds {
+		d.UserIDs[i] = int(userID)
+	}
+	return d
+}
+
+func NotificationRecipientsToGRPC(userIDs []int) *users.NotificationRecipients {
+	response := &users.NotificationRecipients{
+		UserIds: make([]int32, len(userIDs)),
+	}
+
+	for i, userID := range userIDs {
+		response.UserIds[i] = int32(userID)
+	}
+
+	return response
+}
@@ -2,10 +2,21 @@ package models
 
 const (
 	UserEventTypeEmailVerification = "user_verify_email"
+	UserEventTypePasswordReset     = "user_reset_password"
+	UserEventTypeAccountLocked     = "user_account_locked"
+	UserEventTypeOrgInvitation     = "user_org_invitation"
+	UserEventTypeEmailChange       = "user_change_email"
+	UserEventTypeEmailChangeNotice = "user_email_change_notice"
+	UserEventTypeDataExportReady   = "user_data_export_ready"
+	UserEventTypeErasure           = "user_confirm_erasure"
 )
 
 type UserMailItem struct {
 	UserEventType string   `json:"user_event_type"`
 	Receivers     []string `json:"receivers"`
 	Link          string   `json:"link"`
+	// Organization - название организации, только в приглашениях
+	Organization string `json:"organization,omitempty"`
+	// NewEmail - адрес, на который пользователь меняет email, только в уведомлении на старый адрес
+	NewEmail string `json:"new_email,omitempty"`
 }
@@ -0,0 +1,207 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	ts "google.golang.org/protobuf/types/known/timestamppb"
+	"time"
+	"users/pkg/grpc_stubs/users"
+)
+
+// Роли участника организации. Владелец управляет всем, включая роли, администратор приглашает
+// и исключает участников и собирает команды, участник видит состав организации и ее команд
+const (
+	OrganizationRoleOwner  = "owner"
+	OrganizationRoleAdmin  = "admin"
+	OrganizationRoleMember = "member"
+)
+
+// OrganizationDAO - организация и роль в ней пользователя, от имени которого сделан запрос
+type OrganizationDAO struct {
+	ID        int       `db:"id"`
+	Name      string    `db:"name"`
+	Role      string    `db:"role"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// OrganizationMemberDAO - участник организации и команды организации, в которых он состоит
+type OrganizationMemberDAO struct {
+	UserID   int       `db:"user_id"`
+	Username string    `db:"username"`
+	Role     string    `db:"role"`
+	TeamIDs  []int32   `db:"team_ids"`
+	JoinedAt time.Time `db:"created_at"`
+}
+
+// InvitationDAO - приглашение в организацию. В базе хранится только хэш токена из письма
+type InvitationDAO struct {
+	ID             uuid.UUID  `db:"id"`
+	OrganizationID int        `db:"organization_id"`
+	Email          string     `db:"email"`
+	Role           string     `db:"role"`
+	TokenHash      string     `db:"token_hash"`
+	InvitedBy      int        `db:"invited_by"`
+	ExpiresAt      time.Time  `db:"expires_at"`
+	AcceptedAt     *time.Time `db:"accepted_at"`
+	RevokedAt      *time.Time `db:"revoked_at"`
+	CreatedAt      time.Time  `db:"created_at"`
+}
+
+// TeamDAO - команда внутри организации
+type TeamDAO struct {
+	ID             int       `db:"id"`
+	OrganizationID int       `db:"organization_id"`
+	Name           string    `db:"name"`
+	CreatedAt      time.Time `db:"created_at"`
+}
+
+// MembershipsDAO - организации и команды, в которых состоит пользователь. Попадают в access токен
+type MembershipsDAO struct {
+	OrganizationIDs []int32
+	TeamIDs         []int32
+}
+
+// CreateOrganizationDTO - data transfer object - струтктура запроса на создание организации
+type CreateOrganizationDTO struct {
+	UserID int    `json:"user_id" example:"1"`
+	Name   string `json:"name" example:"Acme"`
+}
+
+// MemberDTO - data transfer object - струтктура запроса на изменение роли участника или его исключение.
+// UserID - пользователь, от имени которого сделан запрос
+type MemberDTO struct {
+	OrganizationID int    `json:"organization_id" example:"1"`
+	UserID         int    `json:"user_id" example:"1"`
+	MemberID       int    `json:"member_id" example:"2"`
+	Role           string `json:"role,omitempty" example:"admin"`
+}
+
+// InviteMemberDTO - data transfer object - струтктура запроса на приглашение в организацию
+type InviteMemberDTO struct {
+	OrganizationID int    `json:"organization_id" example:"1"`
+	UserID         int    `json:"user_id" example:"1"`
+	Email          string `json:"email" example:"user@example.com"`
+	Role           string `json:"role" example:"member"`
+}
+
+// CreateTeamDTO - data transfer object - струтктура запроса на создание команды
+type CreateTeamDTO struct {
+	OrganizationID int    `json:"organization_id" example:"1"`
+	UserID         int    `json:"user_id" example:"1"`
+	Name           string `json:"name" example:"backend"`
+}
+
+// TeamMemberDTO - data transfer object - струтктура запроса к команде. MemberID не нужен для получения участников
+type TeamMemberDTO struct {
+	OrganizationID int `json:"organization_id" example:"1"`
+	TeamID         int `json:"team_id" example:"1"`
+	UserID         int `json:"user_id" example:"1"`
+	MemberID       int `json:"member_id,omitempty" example:"2"`
+}
+
+func (d *OrganizationDAO) ToGRPC() *users.OrganizationDTO {
+	return &users.OrganizationDTO{
+		Id:        int32(d.ID),
+		Name:      d.Name,
+		Role:      d.Role,
+		CreatedAt: ts.New(d.CreatedAt),
+	}
+}
+
+func OrganizationsToGRPC(organizations []OrganizationDAO) *users.Organizations {
+	response := &users.Organizations{
+		Items: make([]*users.OrganizationDTO, len(organizations)),
+	}
+
+	for i := range organizations {
+		response.Items[i] = organizations[i].ToGRPC()
+	}
+
+	return response
+}
+
+func OrganizationMembersToGRPC(members []OrganizationMemberDAO) *users.OrganizationMembers {
+	response := &users.OrganizationMembers{
+		Items: make([]*users.OrganizationMemberDTO, len(members)),
+	}
+
+	for i, member := range members {
+		response.Items[i] = &users.OrganizationMemberDTO{
+			UserId:   int32(member.UserID),
+			Username: member.Username,
+			Role:     member.Role,
+			TeamIds:  member.TeamIDs,
+			JoinedAt: ts.New(member.JoinedAt),
+		}
+	}
+
+	return response
+}
+
+func (d *InvitationDAO) ToGRPC() *users.InvitationDTO {
+	return &users.InvitationDTO{
+		Id:             d.ID.String(),
+		OrganizationId: int32(d.OrganizationID),
+		Email:          d.Email,
+		Role:           d.Role,
+		ExpiresAt:      ts.New(d.ExpiresAt),
+		CreatedAt:      ts.New(d.CreatedAt),
+	}
+}
+
+func (d *TeamDAO) ToGRPC() *users.TeamDTO {
+	return &users.TeamDTO{
+		Id:             int32(d.ID),
+		OrganizationId: int32(d.OrganizationID),
+		Name:           d.Name,
+		CreatedAt:      ts.New(d.CreatedAt),
+	}
+}
+
+func TeamsToGRPC(teams []TeamDAO) *users.Teams {
+	response := &users.Teams{
+		Items: make([]*users.TeamDTO, len(teams)),
+	}
+
+	for i := range teams {
+		response.Items[i] = teams[i].ToGRPC()
+	}
+
+	return response
+}
+
+func (d *CreateOrganizationDTO) FromGRPC(in *users.CreateOrganizationRequest) *CreateOrganizationDTO {
+	d.UserID = int(in.UserId)
+	d.Name = in.Name
+	return d
+}
+
+func (d *MemberDTO) FromGRPC(in *users.MemberRequest) *MemberDTO {
+	d.OrganizationID = int(in.OrganizationId)
+	d.UserID = int(in.UserId)
+	d.MemberID = int(in.MemberId)
+	d.Role = in.Role
+	return d
+}
+
+func (d *InviteMemberDTO) FromGRPC(in *users.InviteMemberRequest) *InviteMemberDTO {
+	d.OrganizationID = int(in.OrganizationId)
+	d.UserID = int(in.UserId)
+	d.Email = in.Email
+	d.Role = in.Role
+	return d
+}
+
+func (d *CreateTeamDTO) FromGRPC(in *users.CreateTeamRequest) *CreateTeamDTO {
+	d.OrganizationID = int(in.OrganizationId)
+	d.UserID = int(in.UserId)
+	d.Name = in.Name
+	return d
+}
+
+func (d *TeamMemberDTO) FromGRPC(in *users.TeamMemberRequest) *TeamMemberDTO {
+	d.OrganizationID = int(in.OrganizationId)
+	d.TeamID = int(in.TeamId)
+	d.UserID = int(in.UserId)
+	d.MemberID = int(in.MemberId)
+	return d
+}
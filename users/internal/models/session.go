@@ -0,0 +1,90 @@
+package models
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"time"
+	"users/pkg/grpc_stubs/users"
+)
+
+// SessionDAO - сессия пользователя. Сессия живет от входа до выхода и объединяет цепочку refresh токенов,
+// из которых действителен только последний выданный
+type SessionDAO struct {
+	ID             uuid.UUID  `db:"id"`
+	UserID         int        `db:"user_id"`
+	RefreshTokenID uuid.UUID  `db:"refresh_token_id"`
+	CreatedAt      time.Time  `db:"created_at"`
+	RefreshedAt    time.Time  `db:"refreshed_at"`
+	ExpiresAt      time.Time  `db:"expires_at"`
+	RevokedAt      *time.Time `db:"revoked_at"`
+}
+
+// SessionDTO - data transfer object - струтктура для создания сессии и ответа на ее обновление
+type SessionDTO struct {
+	ID             uuid.UUID
+	UserID         int
+	RefreshTokenID uuid.UUID
+	ExpiresIn      time.Duration
+}
+
+func NewEmptySessionDTO() *SessionDTO {
+	return &SessionDTO{}
+}
+
+func (d *SessionDTO) ToGRPC() *users.SessionDTO {
+	return &users.SessionDTO{
+		Id:               d.ID.String(),
+		UserId:           int32(d.UserID),
+		RefreshTokenId:   d.RefreshTokenID.String(),
+		ExpiresInSeconds: int64(d.ExpiresIn.Seconds()),
+	}
+}
+
+func (d *SessionDTO) FromGRPC(in *users.SessionDTO) (*SessionDTO, error) {
+	refreshTokenID, err := uuid.Parse(in.RefreshTokenId)
+	if err != nil {
+		return nil, fmt.Errorf("[FromGRPC] wrong refresh token uuid: %w", err)
+	}
+
+	d.UserID = int(in.UserId)
+	d.RefreshTokenID = refreshTokenID
+	d.ExpiresIn = time.Duration(in.ExpiresInSeconds) * time.Second
+	return d, nil
+}
+
+// RotateSessionDTO - data transfer object - струтктура для замены refresh токена сессии
+type RotateSessionDTO struct {
+	ID                uuid.UUID
+	RefreshTokenID    uuid.UUID
+	NewRefreshTokenID uuid.UUID
+	ExpiresIn         time.Duration
+}
+
+func (d *SessionDAO) ToDTO() *SessionDTO {
+	return &SessionDTO{
+		ID:             d.ID,
+		UserID:         d.UserID,
+		RefreshTokenID: d.RefreshTokenID,
+		ExpiresIn:      time.Until(d.ExpiresAt),
+	}
+}
+
+func NewEmptyRotateSessionDTO() *RotateSessionDTO {
+	return &RotateSessionDTO{}
+}
+
+func (d *RotateSessionDTO) FromGRPC(in *users.RotateSessionRequest) (*RotateSessionDTO, error) {
+	var err error
+	if d.ID, err = uuid.Parse(in.Id); err != nil {
+		return nil, fmt.Errorf("[FromGRPC] wrong session uuid: %w", err)
+	}
+	if d.RefreshTokenID, err = uuid.Parse(in.RefreshTokenId); err != nil {
+		return nil, fmt.Errorf("[FromGRPC] wrong refresh token uuid: %w", err)
+	}
+	if d.NewRefreshTokenID, err = uuid.Parse(in.NewRefreshTokenId); err != nil {
+		return nil, fmt.Errorf("[FromGRPC] wrong new refresh token uuid: %w", err)
+	}
+
+	d.ExpiresIn = time.Duration(in.ExpiresInSeconds) * time.Second
+	return d, nil
+}
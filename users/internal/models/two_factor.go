@@ -0,0 +1,76 @@
+package models
+
+import (
+	"time"
+	"users/pkg/grpc_stubs/users"
+)
+
+// TwoFactorDAO - настройки двухфакторной аутентификации пользователя. Пока ConfirmedAt пустой,
+// секрет только выдан и вход по нему не требуется
+type TwoFactorDAO struct {
+	UserID       int        `db:"user_id"`
+	Secret       string     `db:"secret"`
+	ConfirmedAt  *time.Time `db:"confirmed_at"`
+	LastUsedStep int64      `db:"last_used_step"`
+}
+
+// Enabled сообщает, подтвердил ли пользователь подключение второго фактора
+func (d *TwoFactorDAO) Enabled() bool {
+	return d.ConfirmedAt != nil
+}
+
+// TwoFactorEnrollmentDTO - секрет для приложения-аутентификатора и ссылка для QR кода
+type TwoFactorEnrollmentDTO struct {
+	Secret     string `json:"secret" example:"JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"`
+	OtpauthURI string `json:"otpauth_uri" example:"otpauth://totp/Todo:user@example.com?secret=JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP&issuer=Todo"`
+}
+
+func (d *TwoFactorEnrollmentDTO) ToGRPC() *users.TwoFactorEnrollment {
+	return &users.TwoFactorEnrollment{
+		Secret:     d.Secret,
+		OtpauthUri: d.OtpauthURI,
+	}
+}
+
+// TwoFactorCodeDTO - код из приложения-аутентификатора или код восстановления для действия над вторым фактором
+type TwoFactorCodeDTO struct {
+	UserID int    `json:"user_id,omitempty" example:"1"`
+	Code   string `json:"code" example:"123456"`
+}
+
+func NewEmptyTwoFactorCodeDTO() *TwoFactorCodeDTO {
+	return &TwoFactorCodeDTO{}
+}
+
+func (d *TwoFactorCodeDTO) FromGRPC(in *users.TwoFactorCodeRequest) *TwoFactorCodeDTO {
+	d.UserID = int(in.UserId)
+	d.Code = in.Code
+	return d
+}
+
+// TwoFactorLoginDTO - второй шаг входа: токен, выданный после проверки пароля, и код
+type TwoFactorLoginDTO struct {
+	ChallengeToken string `json:"challenge_token" example:"kq3v0XxkV4m2QH2nV3bq3z4S4Xk2t0tY5tEx5m7YzWc"`
+	Code           string `json:"code" example:"123456"`
+}
+
+func NewEmptyTwoFactorLoginDTO() *TwoFactorLoginDTO {
+	return &TwoFactorLoginDTO{}
+}
+
+func (d *TwoFactorLoginDTO) FromGRPC(in *users.TwoFactorLoginRequest) *TwoFactorLoginDTO {
+	d.ChallengeToken = in.ChallengeToken
+	d.Code = in.Code
+	return d
+}
+
+// RecoveryCodesDTO - коды восстановления, которые показываются пользователю один раз
+type RecoveryCodesDTO struct {
+	Codes []string `json:"recovery_codes" example:"k3fq-8dmx"`
+}
+
+func (d *RecoveryCodesDTO) ToGRPC() *users.RecoveryCodes {
+	return &users.RecoveryCodes{
+		Codes: d.Codes,
+	}
+}
@@ -8,6 +8,15 @@ type UserDAO struct {
 	Username string `db:"username"`
 	Password string `db:"password"`
 	Email    string `db:"email"`
+
+	EmailVerified bool   `db:"email_verified"`
+	Role          string `db:"role"`
+
+	DisplayName string `db:"display_name"`
+	Timezone    string `db:"timezone"`
+	Locale      string `db:"locale"`
+	// AvatarKey - ключ аватара в хранилище, пустой, если аватар не загружен
+	AvatarKey string `db:"avatar_key"`
 }
 
 // UserDTO - data transfer object - общая струтктура для передачи данных пользователя
@@ -16,6 +25,32 @@ type UserDTO struct {
 	Username string `json:"username" example:"username"`
 	Password string `json:"password,omitempty" example:"password"`
 	Email    string `json:"email,omitempty" example:"user@example.com"`
+
+	EmailVerified bool `json:"email_verified" example:"false"`
+
+	// Role - роль пользователя, Permissions - разрешения этой роли
+	Role        string   `json:"role,omitempty" example:"user"`
+	Permissions []string `json:"permissions,omitempty" example:"users:read"`
+
+	// OrganizationIDs и TeamIDs - организации и команды пользователя, по ним другие сервисы ограничивают данные
+	OrganizationIDs []int32 `json:"organization_ids,omitempty" example:"1"`
+	TeamIDs         []int32 `json:"team_ids,omitempty" example:"1"`
+
+	// DisplayName, Timezone и Locale при обновлении меняются, только если переданы.
+	// Timezone - часовой пояс IANA, Locale - язык в формате BCP 47
+	DisplayName string `json:"display_name,omitempty" example:"John Doe"`
+	Timezone    string `json:"timezone,omitempty" example:"Europe/Moscow"`
+	Locale      string `json:"locale,omitempty" example:"ru"`
+
+	// AvatarURL и AvatarThumbnailURL заполняет сервис, при обновлении пользователя они не учитываются
+	AvatarURL          string `json:"avatar_url,omitempty"`
+	AvatarThumbnailURL string `json:"avatar_thumbnail_url,omitempty"`
+
+	// TwoFactorRequired и ChallengeToken заполняются только в ответе на вход с включенным вторым фактором
+	TwoFactorRequired bool   `json:"two_factor_required,omitempty" example:"false"`
+	ChallengeToken    string `json:"challenge_token,omitempty"`
+	// TwoFactorSetupRequired - роль требует второй фактор, а он не включен, поэтому Permissions пустой
+	TwoFactorSetupRequired bool `json:"two_factor_setup_required,omitempty" example:"false"`
 }
 
 func NewEmptyUserDTO() *UserDTO {
@@ -28,6 +63,24 @@ func (d *UserDTO) ToGRPC() *users.UserDTO {
 		Username: d.Username,
 		Password: d.Password,
 		Email:    d.Email,
+
+		EmailVerified: d.EmailVerified,
+
+		Role:        d.Role,
+		Permissions: d.Permissions,
+
+		OrganizationIds: d.OrganizationIDs,
+		TeamIds:         d.TeamIDs,
+
+		DisplayName:        d.DisplayName,
+		Timezone:           d.Timezone,
+		Locale:             d.Locale,
+		AvatarUrl:          d.AvatarURL,
+		AvatarThumbnailUrl: d.AvatarThumbnailURL,
+
+		TwoFactorRequired:      d.TwoFactorRequired,
+		ChallengeToken:         d.ChallengeToken,
+		TwoFactorSetupRequired: d.TwoFactorSetupRequired,
 	}
 }
 
@@ -36,6 +89,19 @@ func (d *UserDTO) FromGRPC(in *users.UserDTO) *UserDTO {
 	d.Email = in.Email
 	d.Username = in.Username
 	d.Password = in.Password
+	d.EmailVerified = in.EmailVerified
+	d.Role = in.Role
+	d.Permissions = in.Permissions
+	d.OrganizationIDs = in.OrganizationIds
+	d.TeamIDs = in.TeamIds
+	d.DisplayName = in.DisplayName
+	d.Timezone = in.Timezone
+	d.Locale = in.Locale
+	d.AvatarURL = in.AvatarUrl
+	d.AvatarThumbnailURL = in.AvatarThumbnailUrl
+	d.TwoFactorRequired = in.TwoFactorRequired
+	d.ChallengeToken = in.ChallengeToken
+	d.TwoFactorSetupRequired = in.TwoFactorSetupRequired
 	return d
 }
 
@@ -105,6 +171,8 @@ type UserLoginDTO struct {
 	Username string `db:"username,omitempty"`
 	Password string `db:"password"`
 	Email    string `db:"email,omitempty"`
+	// ClientIP - адрес, с которого пришел запрос на вход
+	ClientIP string `json:"-"`
 }
 
 func NewEmptyUserLoginDTO() *UserLoginDTO {
@@ -116,6 +184,7 @@ func (d *UserLoginDTO) ToGRPC() *users.UserLoginDTO {
 		Email:    d.Email,
 		Username: d.Username,
 		Password: d.Password,
+		ClientIp: d.ClientIP,
 	}
 }
 
@@ -123,6 +192,66 @@ func (d *UserLoginDTO) FromGRPC(in *users.UserLoginDTO) *UserLoginDTO {
 	d.Email = in.Email
 	d.Username = in.Username
 	d.Password = in.Password
+	d.ClientIP = in.ClientIp
+	return d
+}
+
+// VerifyEmailDTO - струтктура запроса на подтверждение email
+type VerifyEmailDTO struct {
+	Token string `json:"token" example:"kq3v0XxkV4m2QH2nV3bq3z4S4Xk2t0tY5tEx5m7YzWc"`
+}
+
+// ResendVerificationEmailDTO - струтктура запроса на повторную отправку письма с подтверждением email
+type ResendVerificationEmailDTO struct {
+	Email string `json:"email" example:"user@example.com"`
+}
+
+// RequestPasswordResetDTO - струтктура запроса на отправку письма со сбросом пароля
+type RequestPasswordResetDTO struct {
+	Email string `json:"email" example:"user@example.com"`
+}
+
+// ResetPasswordDTO - data transfer object - струтктура для установки нового пароля по токену из письма
+type ResetPasswordDTO struct {
+	Token                string `json:"token" example:"kq3v0XxkV4m2QH2nV3bq3z4S4Xk2t0tY5tEx5m7YzWc"`
+	Password             string `json:"password" example:"password"`
+	PasswordConfirmation string `json:"password_confirmation" example:"password"`
+}
+
+func NewEmptyResetPasswordDTO() *ResetPasswordDTO {
+	return &ResetPasswordDTO{}
+}
+
+func (d *ResetPasswordDTO) ToGRPC() *users.ResetPasswordRequest {
+	return &users.ResetPasswordRequest{
+		Token:                d.Token,
+		Password:             d.Password,
+		PasswordConfirmation: d.PasswordConfirmation,
+	}
+}
+
+func (d *ResetPasswordDTO) FromGRPC(in *users.ResetPasswordRequest) *ResetPasswordDTO {
+	d.Token = in.Token
+	d.Password = in.Password
+	d.PasswordConfirmation = in.PasswordConfirmation
+	return d
+}
+
+// RequestEmailChangeDTO - data transfer object - струтктура запроса на смену email
+type RequestEmailChangeDTO struct {
+	UserID   int    `json:"-"`
+	Email    string `json:"email" example:"new@example.com"`
+	Password string `json:"password" example:"password"`
+}
+
+func NewEmptyRequestEmailChangeDTO() *RequestEmailChangeDTO {
+	return &RequestEmailChangeDTO{}
+}
+
+func (d *RequestEmailChangeDTO) FromGRPC(in *users.RequestEmailChangeRequest) *RequestEmailChangeDTO {
+	d.UserID = int(in.UserId)
+	d.Email = in.Email
+	d.Password = in.Password
 	return d
 }
 
@@ -0,0 +1,173 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	ts "google.golang.org/protobuf/types/known/timestamppb"
+	"time"
+	"users/pkg/grpc_stubs/users"
+)
+
+// Состояния удаления пользователя. Пока удаление в состоянии pending, пользователь скрыт и не может войти
+const (
+	UserDeletionStatusPending   = "pending"
+	UserDeletionStatusCompleted = "completed"
+	UserDeletionStatusFailed    = "failed"
+)
+
+// Состояния шага удаления в сервисе-участнике
+const (
+	UserDeletionStepPending   = "pending"
+	UserDeletionStepConfirmed = "confirmed"
+	UserDeletionStepFailed    = "failed"
+)
+
+// Типы сообщений об удалении пользователя между сервисами
+const (
+	UserEventTypeUserDeleted           = "user_deleted"
+	UserEventTypeUserDeletionConfirmed = "user_deletion_confirmed"
+	UserEventTypeUserDeletionFailed    = "user_deletion_failed"
+	UserEventTypeUserDeletionCancelled = "user_deletion_cancelled"
+	UserEventTypeUserDeletionCompleted = "user_deletion_completed"
+)
+
+// UserDeletionPolicyAnonymize - данные пользователя в сервисах не передаются другим, а обезличиваются.
+// Так удаляется аккаунт по запросу самого пользователя
+const UserDeletionPolicyAnonymize = "anonymize"
+
+// UserDeletedEvent - доменное событие: пользователь удаляется, сервисы должны убрать или передать его данные
+// и ответить UserDeletionReply с тем же DeletionID. Тем же событием с типом user_deletion_cancelled сервисы
+// узнают, что удаление отменено и его нужно откатить, а с типом user_deletion_completed - что удаление завершено
+// и сохраненные для отката данные больше не нужны
+type UserDeletedEvent struct {
+	EventType  string    `json:"event_type"`
+	DeletionID uuid.UUID `json:"deletion_id"`
+	UserID     int       `json:"user_id"`
+	// Policy - как обработать данные пользователя. Если не задана, сервис следует своим настройкам
+	Policy string `json:"policy,omitempty"`
+}
+
+// UserDeletionReply - ответ сервиса-участника на UserDeletedEvent
+type UserDeletionReply struct {
+	EventType  string    `json:"event_type"`
+	DeletionID uuid.UUID `json:"deletion_id"`
+	UserID     int       `json:"user_id"`
+	Service    string    `json:"service"`
+	// Error - почему сервис не смог обработать удаление, только в user_deletion_failed
+	Error string `json:"error,omitempty"`
+}
+
+// UserDeletionStepDAO - шаг удаления пользователя в одном сервисе
+type UserDeletionStepDAO struct {
+	Service   string    `db:"service"`
+	Status    string    `db:"status"`
+	Error     string    `db:"error"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// UserDeletionDAO - удаление пользователя и его шаги в сервисах-участниках
+type UserDeletionDAO struct {
+	ID        uuid.UUID `db:"id"`
+	UserID    int       `db:"user_id"`
+	Status    string    `db:"status"`
+	Error     string    `db:"error"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+	Steps     []UserDeletionStepDAO
+}
+
+// StepsStatus сводит шаги к одному состоянию: failed, если хотя бы один сервис не смог удалить данные,
+// confirmed, если подтвердили все, иначе pending
+func (d *UserDeletionDAO) StepsStatus() string {
+	status := UserDeletionStepConfirmed
+	for _, step := range d.Steps {
+		switch step.Status {
+		case UserDeletionStepFailed:
+			return UserDeletionStepFailed
+		case UserDeletionStepPending:
+			status = UserDeletionStepPending
+		}
+	}
+
+	return status
+}
+
+// FailedStepsError собирает причины отказа сервисов в одну строку
+func (d *UserDeletionDAO) FailedStepsError() string {
+	var reason string
+	for _, step := range d.Steps {
+		if step.Status != UserDeletionStepFailed {
+			continue
+		}
+		if reason != "" {
+			reason += "; "
+		}
+		reason += step.Service + ": " + step.Error
+	}
+
+	return reason
+}
+
+// UserDeletionStepDTO - data transfer object - шаг удаления пользователя в одном сервисе
+type UserDeletionStepDTO struct {
+	Service   string    `json:"service" example:"todo"`
+	Status    string    `json:"status" example:"confirmed"`
+	Error     string    `json:"error,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// UserDeletionDTO - data transfer object - состояние удаления пользователя
+type UserDeletionDTO struct {
+	ID        uuid.UUID             `json:"id" example:"c0e708fa-a7df-4d9f-a1b8-a3bfe63c433c"`
+	UserID    int                   `json:"user_id" example:"1"`
+	Status    string                `json:"status" example:"pending"`
+	Error     string                `json:"error,omitempty"`
+	Steps     []UserDeletionStepDTO `json:"steps"`
+	CreatedAt time.Time             `json:"created_at"`
+	UpdatedAt time.Time             `json:"updated_at"`
+}
+
+func (d *UserDeletionDAO) ToDTO() *UserDeletionDTO {
+	response := &UserDeletionDTO{
+		ID:        d.ID,
+		UserID:    d.UserID,
+		Status:    d.Status,
+		Error:     d.Error,
+		Steps:     make([]UserDeletionStepDTO, len(d.Steps)),
+		CreatedAt: d.CreatedAt,
+		UpdatedAt: d.UpdatedAt,
+	}
+
+	for i, step := range d.Steps {
+		response.Steps[i] = UserDeletionStepDTO{
+			Service:   step.Service,
+			Status:    step.Status,
+			Error:     step.Error,
+			UpdatedAt: step.UpdatedAt,
+		}
+	}
+
+	return response
+}
+
+func (d *UserDeletionDTO) ToGRPC() *users.UserDeletion {
+	response := &users.UserDeletion{
+		Id:        d.ID.String(),
+		UserId:    int32(d.UserID),
+		Status:    d.Status,
+		Error:     d.Error,
+		Steps:     make([]*users.UserDeletionStep, len(d.Steps)),
+		CreatedAt: ts.New(d.CreatedAt),
+		UpdatedAt: ts.New(d.UpdatedAt),
+	}
+
+	for i, step := range d.Steps {
+		response.Steps[i] = &users.UserDeletionStep{
+			Service:   step.Service,
+			Status:    step.Status,
+			Error:     step.Error,
+			UpdatedAt: ts.New(step.UpdatedAt),
+		}
+	}
+
+	return response
+}
@@ -0,0 +1,11 @@
+package models
+
+// UserEventTypeUserUpdated - тип события об изменении данных пользователя
+const UserEventTypeUserUpdated = "user_updated"
+
+// UserUpdatedEvent - доменное событие: данные пользователя изменились, сервисы, которые кешируют пользователя,
+// должны перечитать его через GetUserByID или GetUsersByIDs
+type UserUpdatedEvent struct {
+	EventType string `json:"event_type"`
+	UserID    int    `json:"user_id"`
+}
@@ -0,0 +1,63 @@
+package models
+
+import "users/pkg/grpc_stubs/users"
+
+// ListUsersDTO - data transfer object - параметры постраничного поиска пользователей
+type ListUsersDTO struct {
+	// Search - начало username или email, регистр не учитывается
+	Search string
+	Limit  int
+	Offset int
+	// Sort - поле сортировки: id, username или email, с минусом в начале - по убыванию
+	Sort string
+	// IncludeEmail - искать по email и возвращать его. Без этого в ответе только id и username
+	IncludeEmail bool
+}
+
+// UsersPageDTO - data transfer object - страница найденных пользователей
+type UsersPageDTO struct {
+	Items  []UserDTO
+	Total  int
+	Limit  int
+	Offset int
+}
+
+func NewEmptyListUsersDTO() *ListUsersDTO {
+	return &ListUsersDTO{}
+}
+
+func (d *ListUsersDTO) FromGRPC(in *users.ListUsersRequest) *ListUsersDTO {
+	d.Search = in.Search
+	d.Limit = int(in.Limit)
+	d.Offset = int(in.Offset)
+	d.Sort = in.Sort
+	d.IncludeEmail = in.IncludeEmail
+	return d
+}
+
+func (d *UsersPageDTO) ToGRPC() *users.ListUsersResponse {
+	response := &users.ListUsersResponse{
+		Items:  make([]*users.UserDTO, len(d.Items)),
+		Total:  int32(d.Total),
+		Limit:  int32(d.Limit),
+		Offset: int32(d.Offset),
+	}
+
+	for i := range d.Items {
+		response.Items[i] = d.Items[i].ToGRPC()
+	}
+
+	return response
+}
+
+func UsersToGRPC(items []UserDTO) *users.Users {
+	response := &users.Users{
+		Items: make([]*users.UserDTO, len(items)),
+	}
+
+	for i := range items {
+		response.Items[i] = items[i].ToGRPC()
+	}
+
+	return response
+}
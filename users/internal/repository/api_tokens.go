@@ -0,0 +1,209 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/opentracing/opentracing-go"
+	"time"
+	appErrors "users/internal/app_errors"
+	"users/internal/models"
+)
+
+// CreateApiToken сохраняет токен, если у пользователя меньше maxActive действующих токенов.
+// Подсчет и вставка идут под блокировкой строки пользователя, чтобы параллельные запросы не превысили лимит
+func (r *UserRepository) CreateApiToken(ctx context.Context, token *models.ApiTokenDAO, maxActive int) (*models.ApiTokenDAO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.CreateApiToken")
+	defer span.Finish()
+
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("[CreateApiToken] begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sql := `
+        SELECT
+            id
+        FROM
+            users
+        WHERE
+            id = $1
+        FOR UPDATE
+    `
+	if err := tx.QueryRow(ctx, sql, token.UserID).Scan(new(int)); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, appErrors.ErrNotFound
+		}
+		return nil, fmt.Errorf("[CreateApiToken] lock user: %w", err)
+	}
+
+	var active int
+	sql = `
+        SELECT
+            count(*)
+        FROM
+            api_tokens
+        WHERE
+            user_id = $1 AND revoked_at IS NULL AND expires_at > now()
+    `
+	if err := tx.QueryRow(ctx, sql, token.UserID).Scan(&active); err != nil {
+		return nil, fmt.Errorf("[CreateApiToken] count: %w", err)
+	}
+
+	if active >= maxActive {
+		return nil, appErrors.ErrTooManyApiTokens
+	}
+
+	stored := *token
+	sql = `
+        INSERT INTO
+			api_tokens (
+			   id,
+			   user_id,
+			   name,
+			   token_hash,
+			   scopes,
+			   expires_at
+			)
+        VALUES
+			($1, $2, $3, $4, $5, $6)
+        RETURNING created_at
+    `
+	err = tx.QueryRow(ctx, sql, token.ID, token.UserID, token.Name, token.TokenHash, token.Scopes, token.ExpiresAt).
+		Scan(&stored.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("[CreateApiToken] insert: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("[CreateApiToken] commit: %w", err)
+	}
+
+	return &stored, nil
+}
+
+// GetApiTokens возвращает действующие токены пользователя, новые первыми
+func (r *UserRepository) GetApiTokens(ctx context.Context, userID int) ([]models.ApiTokenDAO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.GetApiTokens")
+	defer span.Finish()
+
+	sql := `
+        SELECT
+            id,
+            user_id,
+            name,
+            scopes,
+            expires_at,
+            last_used_at,
+            created_at
+        FROM
+            api_tokens
+        WHERE
+            user_id = $1 AND revoked_at IS NULL AND expires_at > now()
+        ORDER BY
+            created_at DESC
+    `
+	rows, err := r.conn.Query(ctx, sql, userID)
+	if err != nil {
+		return nil, fmt.Errorf("[GetApiTokens] select: %w", err)
+	}
+	defer rows.Close()
+
+	tokens := make([]models.ApiTokenDAO, 0)
+	for rows.Next() {
+		var token models.ApiTokenDAO
+		err := rows.Scan(&token.ID, &token.UserID, &token.Name, &token.Scopes, &token.ExpiresAt, &token.LastUsedAt, &token.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("[GetApiTokens] scan: %w", err)
+		}
+		tokens = append(tokens, token)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("[GetApiTokens] rows: %w", err)
+	}
+
+	return tokens, nil
+}
+
+// GetApiTokenByHash ищет токен по хэшу вместе с отозванными и просроченными, их отсеивает сервис
+func (r *UserRepository) GetApiTokenByHash(ctx context.Context, tokenHash string) (*models.ApiTokenDAO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.GetApiTokenByHash")
+	defer span.Finish()
+
+	var token models.ApiTokenDAO
+	sql := `
+        SELECT
+            id,
+            user_id,
+            name,
+            scopes,
+            expires_at,
+            last_used_at,
+            revoked_at,
+            created_at
+        FROM
+            api_tokens
+        WHERE
+            token_hash = $1
+    `
+	err := r.conn.QueryRow(ctx, sql, tokenHash).
+		Scan(&token.ID, &token.UserID, &token.Name, &token.Scopes, &token.ExpiresAt, &token.LastUsedAt, &token.RevokedAt, &token.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, appErrors.ErrInvalidApiToken
+		}
+		return nil, fmt.Errorf("[GetApiTokenByHash] select: %w", err)
+	}
+
+	return &token, nil
+}
+
+// TouchApiToken отмечает время использования токена. Чтобы не писать в базу на каждый запрос,
+// время обновляется, только если с прошлой отметки прошло больше interval
+func (r *UserRepository) TouchApiToken(ctx context.Context, tokenID uuid.UUID, interval time.Duration) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.TouchApiToken")
+	defer span.Finish()
+
+	sql := `
+        UPDATE
+            api_tokens
+        SET
+            last_used_at = now()
+        WHERE
+            id = $1 AND (last_used_at IS NULL OR last_used_at < now() - make_interval(secs => $2))
+    `
+	if _, err := r.conn.Exec(ctx, sql, tokenID, interval.Seconds()); err != nil {
+		return fmt.Errorf("[TouchApiToken] update: %w", err)
+	}
+
+	return nil
+}
+
+// RevokeApiToken отзывает токен пользователя. Чужой или уже отозванный токен найти нельзя.
+func (r *UserRepository) RevokeApiToken(ctx context.Context, tokenID uuid.UUID, userID int) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.RevokeApiToken")
+	defer span.Finish()
+
+	sql := `
+        UPDATE
+            api_tokens
+        SET
+            revoked_at = now()
+        WHERE
+            id = $1 AND user_id = $2 AND revoked_at IS NULL
+    `
+	tag, err := r.conn.Exec(ctx, sql, tokenID, userID)
+	if err != nil {
+		return fmt.Errorf("[RevokeApiToken] update: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return appErrors.ErrApiTokenNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,375 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/opentracing/opentracing-go"
+	"time"
+	appErrors "users/internal/app_errors"
+	"users/internal/models"
+)
+
+// HasRecentDataExport проверяет, запрашивал ли пользователь выгрузку за последний interval
+func (r *UserRepository) HasRecentDataExport(ctx context.Context, userID int, interval time.Duration) (bool, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.HasRecentDataExport")
+	defer span.Finish()
+
+	var exists bool
+	sql := `
+        SELECT EXISTS (
+            SELECT 1
+            FROM
+                data_exports
+            WHERE
+                user_id = $1 AND created_at > now() - make_interval(secs => $2)
+        )
+    `
+	err := r.conn.QueryRow(ctx, sql, userID, interval.Seconds()).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("[HasRecentDataExport] select: %w", err)
+	}
+
+	return exists, nil
+}
+
+// StartDataExport одной транзакцией записывает выгрузку с частью pending для каждого сервиса-участника.
+// Прежние выгрузки пользователя удаляются вместе с архивами, скачать можно только последнюю
+func (r *UserRepository) StartDataExport(ctx context.Context, export *models.DataExportDAO) (*models.DataExportDAO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.StartDataExport")
+	defer span.Finish()
+
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("[StartDataExport] begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sql := `
+        DELETE FROM
+            data_exports
+        WHERE
+            user_id = $1
+    `
+	if _, err := tx.Exec(ctx, sql, export.UserID); err != nil {
+		return nil, fmt.Errorf("[StartDataExport] delete previous: %w", err)
+	}
+
+	sql = `
+        INSERT INTO
+			data_exports (
+			   id,
+			   user_id,
+			   status
+			)
+        VALUES
+			($1, $2, $3)
+        RETURNING created_at, updated_at
+    `
+	err = tx.QueryRow(ctx, sql, export.ID, export.UserID, models.DataExportStatusPending).
+		Scan(&export.CreatedAt, &export.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("[StartDataExport] insert export: %w", err)
+	}
+	export.Status = models.DataExportStatusPending
+
+	for i := range export.Parts {
+		sql = `
+            INSERT INTO
+				data_export_parts (export_id, service, status)
+            VALUES
+				($1, $2, $3)
+            RETURNING updated_at
+        `
+		err = tx.QueryRow(ctx, sql, export.ID, export.Parts[i].Service, models.DataExportPartPending).
+			Scan(&export.Parts[i].UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("[StartDataExport] insert part: %w", err)
+		}
+		export.Parts[i].Status = models.DataExportPartPending
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("[StartDataExport] commit: %w", err)
+	}
+
+	return export, nil
+}
+
+// GetDataExport возвращает последнюю выгрузку пользователя с частями, но без данных сервисов
+func (r *UserRepository) GetDataExport(ctx context.Context, userID int) (*models.DataExportDAO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.GetDataExport")
+	defer span.Finish()
+
+	var export models.DataExportDAO
+	sql := `
+        SELECT
+            id,
+            user_id,
+            status,
+            error,
+            expires_at,
+            created_at,
+            updated_at
+        FROM
+            data_exports
+        WHERE
+            user_id = $1
+        ORDER BY
+            created_at DESC
+        LIMIT 1
+    `
+	err := r.conn.QueryRow(ctx, sql, userID).
+		Scan(&export.ID, &export.UserID, &export.Status, &export.Error, &export.ExpiresAt, &export.CreatedAt, &export.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, appErrors.ErrDataExportNotFound
+		}
+		return nil, fmt.Errorf("[GetDataExport] select: %w", err)
+	}
+
+	export.Parts, err = queryDataExportParts(ctx, r.conn, export.ID, false)
+	if err != nil {
+		return nil, fmt.Errorf("[GetDataExport] %w", err)
+	}
+
+	return &export, nil
+}
+
+// SetDataExportPart записывает ответ сервиса и возвращает выгрузку со всеми частями и их данными. Выгрузка
+// блокируется до конца транзакции, поэтому одновременные ответы разных сервисов видят части друг друга.
+// Повторный ответ на уже записанную часть ничего не меняет
+func (r *UserRepository) SetDataExportPart(ctx context.Context, exportID uuid.UUID, part *models.DataExportPartDAO) (*models.DataExportDAO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.SetDataExportPart")
+	defer span.Finish()
+
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("[SetDataExportPart] begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var export models.DataExportDAO
+	sql := `
+        SELECT
+            id,
+            user_id,
+            status,
+            error,
+            expires_at,
+            created_at,
+            updated_at
+        FROM
+            data_exports
+        WHERE
+            id = $1
+        FOR UPDATE
+    `
+	err = tx.QueryRow(ctx, sql, exportID).
+		Scan(&export.ID, &export.UserID, &export.Status, &export.Error, &export.ExpiresAt, &export.CreatedAt, &export.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, appErrors.ErrDataExportNotFound
+		}
+		return nil, fmt.Errorf("[SetDataExportPart] select export: %w", err)
+	}
+
+	sql = `
+        UPDATE
+            data_export_parts
+        SET
+            status = $3,
+            data = $4,
+            error = $5,
+            updated_at = now()
+        WHERE
+            export_id = $1 AND service = $2 AND status = $6
+    `
+	_, err = tx.Exec(ctx, sql, exportID, part.Service, part.Status, part.Data, part.Error, models.DataExportPartPending)
+	if err != nil {
+		return nil, fmt.Errorf("[SetDataExportPart] update part: %w", err)
+	}
+
+	export.Parts, err = queryDataExportParts(ctx, tx, exportID, true)
+	if err != nil {
+		return nil, fmt.Errorf("[SetDataExportPart] %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("[SetDataExportPart] commit: %w", err)
+	}
+
+	return &export, nil
+}
+
+// CompleteDataExport сохраняет собранный архив и хэш токена для скачивания. Данные сервисов уже попали в архив
+// и удаляются. Если выгрузка уже завершена или не удалась, возвращает false
+func (r *UserRepository) CompleteDataExport(ctx context.Context, exportID uuid.UUID, archive []byte, tokenHash string, ttl time.Duration) (bool, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.CompleteDataExport")
+	defer span.Finish()
+
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("[CompleteDataExport] begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sql := `
+        UPDATE
+            data_exports
+        SET
+            status = $2,
+            archive = $3,
+            token_hash = $4,
+            expires_at = now() + make_interval(secs => $5),
+            updated_at = now()
+        WHERE
+            id = $1 AND status = $6
+    `
+	tag, err := tx.Exec(ctx, sql, exportID, models.DataExportStatusReady, archive, tokenHash, ttl.Seconds(), models.DataExportStatusPending)
+	if err != nil {
+		return false, fmt.Errorf("[CompleteDataExport] update export: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	sql = `
+        UPDATE
+            data_export_parts
+        SET
+            data = NULL
+        WHERE
+            export_id = $1
+    `
+	if _, err := tx.Exec(ctx, sql, exportID); err != nil {
+		return false, fmt.Errorf("[CompleteDataExport] clear parts: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("[CompleteDataExport] commit: %w", err)
+	}
+
+	return true, nil
+}
+
+// FailDataExport отмечает выгрузку failed с причиной и удаляет данные, которые успели прислать сервисы.
+// Если выгрузка уже завершена или не удалась, ничего не делает
+func (r *UserRepository) FailDataExport(ctx context.Context, exportID uuid.UUID, reason string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.FailDataExport")
+	defer span.Finish()
+
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("[FailDataExport] begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sql := `
+        UPDATE
+            data_exports
+        SET
+            status = $2,
+            error = $3,
+            updated_at = now()
+        WHERE
+            id = $1 AND status = $4
+    `
+	tag, err := tx.Exec(ctx, sql, exportID, models.DataExportStatusFailed, reason, models.DataExportStatusPending)
+	if err != nil {
+		return fmt.Errorf("[FailDataExport] update export: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return nil
+	}
+
+	sql = `
+        UPDATE
+            data_export_parts
+        SET
+            data = NULL
+        WHERE
+            export_id = $1
+    `
+	if _, err := tx.Exec(ctx, sql, exportID); err != nil {
+		return fmt.Errorf("[FailDataExport] clear parts: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("[FailDataExport] commit: %w", err)
+	}
+
+	return nil
+}
+
+// GetDataExportArchive возвращает архив готовой выгрузки по хэшу токена из письма. Если токен неизвестен
+// или срок скачивания прошел, возвращает ErrInvalidDataExportToken
+func (r *UserRepository) GetDataExportArchive(ctx context.Context, tokenHash string) ([]byte, time.Time, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.GetDataExportArchive")
+	defer span.Finish()
+
+	var (
+		archive   []byte
+		createdAt time.Time
+	)
+	sql := `
+        SELECT
+            e.archive,
+            e.created_at
+        FROM
+            data_exports e
+        JOIN
+            users u ON u.id = e.user_id
+        WHERE
+            e.token_hash = $1 AND e.status = $2 AND e.expires_at > now() AND u.deleted_at IS NULL
+    `
+	err := r.conn.QueryRow(ctx, sql, tokenHash, models.DataExportStatusReady).Scan(&archive, &createdAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, time.Time{}, appErrors.ErrInvalidDataExportToken
+		}
+		return nil, time.Time{}, fmt.Errorf("[GetDataExportArchive] select: %w", err)
+	}
+
+	return archive, createdAt, nil
+}
+
+// queryDataExportParts читает части выгрузки, данные сервисов - только если withData
+func queryDataExportParts(ctx context.Context, q stepsQuerier, exportID uuid.UUID, withData bool) ([]models.DataExportPartDAO, error) {
+	sql := `
+        SELECT
+            service,
+            status,
+            CASE WHEN $2 THEN data END,
+            error,
+            updated_at
+        FROM
+            data_export_parts
+        WHERE
+            export_id = $1
+        ORDER BY
+            service
+    `
+	rows, err := q.Query(ctx, sql, exportID, withData)
+	if err != nil {
+		return nil, fmt.Errorf("[queryDataExportParts] select: %w", err)
+	}
+	defer rows.Close()
+
+	parts := make([]models.DataExportPartDAO, 0)
+	for rows.Next() {
+		var part models.DataExportPartDAO
+		if err := rows.Scan(&part.Service, &part.Status, &part.Data, &part.Error, &part.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("[queryDataExportParts] scan: %w", err)
+		}
+		parts = append(parts, part)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("[queryDataExportParts] rows: %w", err)
+	}
+
+	return parts, nil
+}
@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/opentracing/opentracing-go"
+	"time"
+	appErrors "users/internal/app_errors"
+)
+
+// CreateEmailChangeToken сохраняет хэш токена подтверждения нового email. Прежние неподтвержденные
+// запросы пользователя удаляются, поэтому рабочей остается только ссылка из последнего письма.
+func (r *UserRepository) CreateEmailChangeToken(ctx context.Context, userID int, newEmail, tokenHash string, ttl time.Duration) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.CreateEmailChangeToken")
+	defer span.Finish()
+
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("[CreateEmailChangeToken] begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sql := `
+        DELETE FROM
+            email_change_requests
+        WHERE
+            user_id = $1 AND consumed_at IS NULL
+    `
+	if _, err := tx.Exec(ctx, sql, userID); err != nil {
+		return fmt.Errorf("[CreateEmailChangeToken] delete previous: %w", err)
+	}
+
+	sql = `
+        INSERT INTO
+			email_change_requests (
+			   user_id,
+			   new_email,
+			   token_hash,
+			   expires_at
+			)
+        VALUES
+			($1, $2, $3, now() + make_interval(secs => $4))
+    `
+	if _, err := tx.Exec(ctx, sql, userID, newEmail, tokenHash, ttl.Seconds()); err != nil {
+		return fmt.Errorf("[CreateEmailChangeToken] insert: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("[CreateEmailChangeToken] commit: %w", err)
+	}
+
+	return nil
+}
+
+// HasRecentEmailChangeToken проверяет, запрашивал ли пользователь смену email за последний interval
+func (r *UserRepository) HasRecentEmailChangeToken(ctx context.Context, userID int, interval time.Duration) (bool, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.HasRecentEmailChangeToken")
+	defer span.Finish()
+
+	var exists bool
+	sql := `
+        SELECT EXISTS (
+            SELECT 1
+            FROM
+                email_change_requests
+            WHERE
+                user_id = $1 AND created_at > now() - make_interval(secs => $2)
+        )
+    `
+	err := r.conn.QueryRow(ctx, sql, userID, interval.Seconds()).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("[HasRecentEmailChangeToken] select: %w", err)
+	}
+
+	return exists, nil
+}
+
+// ConsumeEmailChangeToken одной транзакцией гасит токен, записывает пользователю новый email подтвержденным
+// и удаляет его остальные запросы. Если адрес успел занять другой пользователь, возвращает ErrUsernameOrEmailIsUsed.
+func (r *UserRepository) ConsumeEmailChangeToken(ctx context.Context, tokenHash string) (int, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.ConsumeEmailChangeToken")
+	defer span.Finish()
+
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("[ConsumeEmailChangeToken] begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var (
+		userID   int
+		newEmail string
+	)
+	sql := `
+        UPDATE
+            email_change_requests
+        SET
+            consumed_at = now()
+        WHERE
+            token_hash = $1 AND consumed_at IS NULL AND expires_at > now()
+        RETURNING user_id, new_email
+    `
+	err = tx.QueryRow(ctx, sql, tokenHash).Scan(&userID, &newEmail)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, appErrors.ErrInvalidEmailChangeToken
+		}
+		return 0, fmt.Errorf("[ConsumeEmailChangeToken] consume: %w", err)
+	}
+
+	var taken bool
+	sql = `
+        SELECT EXISTS (
+            SELECT 1
+            FROM
+                users
+            WHERE
+                lower(email) = lower($2) AND id <> $1
+        )
+    `
+	if err := tx.QueryRow(ctx, sql, userID, newEmail).Scan(&taken); err != nil {
+		return 0, fmt.Errorf("[ConsumeEmailChangeToken] check email: %w", err)
+	}
+	if taken {
+		return 0, appErrors.ErrUsernameOrEmailIsUsed
+	}
+
+	sql = `
+        UPDATE
+            users
+        SET
+            email = $2,
+            email_verified = true
+        WHERE
+            id = $1
+    `
+	if _, err := tx.Exec(ctx, sql, userID, newEmail); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+			return 0, appErrors.ErrUsernameOrEmailIsUsed
+		}
+		return 0, fmt.Errorf("[ConsumeEmailChangeToken] update email: %w", err)
+	}
+
+	sql = `
+        DELETE FROM
+            email_change_requests
+        WHERE
+            user_id = $1 AND consumed_at IS NULL
+    `
+	if _, err := tx.Exec(ctx, sql, userID); err != nil {
+		return 0, fmt.Errorf("[ConsumeEmailChangeToken] delete other: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("[ConsumeEmailChangeToken] commit: %w", err)
+	}
+
+	return userID, nil
+}
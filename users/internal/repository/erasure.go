@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v4"
+	"github.com/opentracing/opentracing-go"
+	"time"
+	appErrors "users/internal/app_errors"
+)
+
+// CreateErasureToken сохраняет хэш токена подтверждения удаления аккаунта. Прежние неподтвержденные
+// запросы пользователя удаляются, поэтому рабочей остается только ссылка из последнего письма.
+func (r *UserRepository) CreateErasureToken(ctx context.Context, userID int, tokenHash string, ttl time.Duration) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.CreateErasureToken")
+	defer span.Finish()
+
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("[CreateErasureToken] begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sql := `
+        DELETE FROM
+            erasure_requests
+        WHERE
+            user_id = $1 AND consumed_at IS NULL
+    `
+	if _, err := tx.Exec(ctx, sql, userID); err != nil {
+		return fmt.Errorf("[CreateErasureToken] delete previous: %w", err)
+	}
+
+	sql = `
+        INSERT INTO
+			erasure_requests (
+			   user_id,
+			   token_hash,
+			   expires_at
+			)
+        VALUES
+			($1, $2, now() + make_interval(secs => $3))
+    `
+	if _, err := tx.Exec(ctx, sql, userID, tokenHash, ttl.Seconds()); err != nil {
+		return fmt.Errorf("[CreateErasureToken] insert: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("[CreateErasureToken] commit: %w", err)
+	}
+
+	return nil
+}
+
+// HasRecentErasureToken проверяет, запрашивал ли пользователь удаление аккаунта за последний interval
+func (r *UserRepository) HasRecentErasureToken(ctx context.Context, userID int, interval time.Duration) (bool, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.HasRecentErasureToken")
+	defer span.Finish()
+
+	var exists bool
+	sql := `
+        SELECT EXISTS (
+            SELECT 1
+            FROM
+                erasure_requests
+            WHERE
+                user_id = $1 AND created_at > now() - make_interval(secs => $2)
+        )
+    `
+	err := r.conn.QueryRow(ctx, sql, userID, interval.Seconds()).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("[HasRecentErasureToken] select: %w", err)
+	}
+
+	return exists, nil
+}
+
+// ConsumeErasureToken гасит токен и возвращает ID пользователя, который запросил удаление
+func (r *UserRepository) ConsumeErasureToken(ctx context.Context, tokenHash string) (int, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.ConsumeErasureToken")
+	defer span.Finish()
+
+	var userID int
+	sql := `
+        UPDATE
+            erasure_requests
+        SET
+            consumed_at = now()
+        WHERE
+            token_hash = $1 AND consumed_at IS NULL AND expires_at > now()
+        RETURNING user_id
+    `
+	err := r.conn.QueryRow(ctx, sql, tokenHash).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, appErrors.ErrInvalidErasureToken
+		}
+		return 0, fmt.Errorf("[ConsumeErasureToken] consume: %w", err)
+	}
+
+	return userID, nil
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/opentracing/opentracing-go"
+	appErrors "users/internal/app_errors"
+	"users/internal/models"
+)
+
+func (r *UserRepository) GetUserIdentity(ctx context.Context, issuer, subject string) (*models.UserIdentityDAO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.GetUserIdentity")
+	defer span.Finish()
+
+	var identity models.UserIdentityDAO
+	sql := `
+        SELECT
+            issuer,
+            subject,
+            user_id,
+            email
+        FROM
+            user_identities
+        WHERE
+            issuer = $1 AND subject = $2
+    `
+	err := r.conn.QueryRow(ctx, sql, issuer, subject).
+		Scan(&identity.Issuer, &identity.Subject, &identity.UserID, &identity.Email)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, appErrors.ErrIdentityNotFound
+		}
+		return nil, fmt.Errorf("[GetUserIdentity] select: %w", err)
+	}
+
+	return &identity, nil
+}
+
+// CreateUserIdentity привязывает пользователя провайдера к существующему аккаунту
+func (r *UserRepository) CreateUserIdentity(ctx context.Context, identity *models.UserIdentityDAO) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.CreateUserIdentity")
+	defer span.Finish()
+
+	if err := createUserIdentity(ctx, r.conn, identity); err != nil {
+		return fmt.Errorf("[CreateUserIdentity] %w", err)
+	}
+
+	return nil
+}
+
+// CreateExternalUser создает пользователя, впервые вошедшего через провайдера, вместе с привязкой.
+// Email такого пользователя подтвержден провайдером.
+func (r *UserRepository) CreateExternalUser(ctx context.Context, user *models.CreateUserDTO, identity *models.UserIdentityDAO) (int, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.CreateExternalUser")
+	defer span.Finish()
+
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("[CreateExternalUser] begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var userID int
+	sql := `
+        INSERT INTO
+			users (
+			   username,
+			   password,
+			   email,
+			   email_verified
+			)
+        VALUES
+			($1, $2, $3, true)
+        RETURNING id
+    `
+	err = tx.QueryRow(ctx, sql, user.Username, user.Password, user.Email).Scan(&userID)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+			return 0, appErrors.ErrUsernameOrEmailIsUsed
+		}
+		return 0, fmt.Errorf("[CreateExternalUser] insert user: %w", err)
+	}
+
+	identity.UserID = userID
+	if err := createUserIdentity(ctx, tx, identity); err != nil {
+		return 0, fmt.Errorf("[CreateExternalUser] %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("[CreateExternalUser] commit: %w", err)
+	}
+
+	return userID, nil
+}
+
+// TouchUserIdentity запоминает время входа и email, который провайдер вернул в последний раз
+func (r *UserRepository) TouchUserIdentity(ctx context.Context, identity *models.UserIdentityDAO) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.TouchUserIdentity")
+	defer span.Finish()
+
+	sql := `
+        UPDATE
+            user_identities
+        SET
+            email = $3,
+            last_login_at = now()
+        WHERE
+            issuer = $1 AND subject = $2
+    `
+	if _, err := r.conn.Exec(ctx, sql, identity.Issuer, identity.Subject, identity.Email); err != nil {
+		return fmt.Errorf("[TouchUserIdentity] update: %w", err)
+	}
+
+	return nil
+}
+
+// execer - общее у пула соединений и транзакции
+type execer interface {
+	Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error)
+}
+
+func createUserIdentity(ctx context.Context, conn execer, identity *models.UserIdentityDAO) error {
+	sql := `
+        INSERT INTO
+			user_identities (
+			   issuer,
+			   subject,
+			   user_id,
+			   email
+			)
+        VALUES
+			($1, $2, $3, $4)
+    `
+	_, err := conn.Exec(ctx, sql, identity.Issuer, identity.Subject, identity.UserID, identity.Email)
+	if err != nil {
+		return fmt.Errorf("insert identity: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v4"
+	"github.com/opentracing/opentracing-go"
+	"time"
+	"users/internal/models"
+)
+
+// GetLoginThrottle возвращает счетчик неудачных входов. Если неудачных попыток не было, возвращается пустой счетчик.
+func (r *UserRepository) GetLoginThrottle(ctx context.Context, kind, key string) (*models.LoginThrottleDAO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.GetLoginThrottle")
+	defer span.Finish()
+
+	throttle := models.LoginThrottleDAO{
+		Kind: kind,
+		Key:  key,
+	}
+	var retryAfterSeconds float64
+	sql := `
+        SELECT
+            failures,
+            locked,
+            COALESCE(EXTRACT(EPOCH FROM blocked_until - now()), 0)::float8
+        FROM
+            login_throttles
+        WHERE
+            kind = $1 AND key = $2
+    `
+	err := r.conn.QueryRow(ctx, sql, kind, key).Scan(&throttle.Failures, &throttle.Locked, &retryAfterSeconds)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return &throttle, nil
+		}
+		return nil, fmt.Errorf("[GetLoginThrottle] select: %w", err)
+	}
+
+	if retryAfterSeconds > 0 {
+		throttle.RetryAfter = time.Duration(retryAfterSeconds * float64(time.Second))
+	}
+
+	return &throttle, nil
+}
+
+// ReserveLoginAttempt занимает попытку входа до проверки пароля и возвращает false, если попытки сейчас
+// запрещены. Незавершенные попытки считаются вместе с неудачами: пока неудач меньше freeAttempts,
+// одновременно идет не больше оставшихся бесплатных попыток, дальше - только одна. Попытки, не освобожденные
+// за attemptTimeout, например из-за упавшего запроса, перестают учитываться.
+func (r *UserRepository) ReserveLoginAttempt(ctx context.Context, kind, key string, freeAttempts int, window, attemptTimeout time.Duration) (bool, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.ReserveLoginAttempt")
+	defer span.Finish()
+
+	var inFlight int
+	sql := `
+        INSERT INTO
+			login_throttles (
+			   kind,
+			   key,
+			   in_flight,
+			   attempt_started_at
+			)
+        VALUES
+			($1, $2, 1, now())
+        ON CONFLICT (kind, key) DO UPDATE SET
+            in_flight = CASE
+                WHEN login_throttles.attempt_started_at < now() - make_interval(secs => $5) THEN 1
+                ELSE login_throttles.in_flight + 1
+            END,
+            attempt_started_at = now()
+        WHERE
+            (login_throttles.blocked_until IS NULL OR login_throttles.blocked_until <= now())
+            AND CASE
+                WHEN login_throttles.attempt_started_at < now() - make_interval(secs => $5) THEN 0
+                ELSE login_throttles.in_flight
+            END < GREATEST($3 - CASE
+                WHEN login_throttles.locked OR login_throttles.last_failure_at < now() - make_interval(secs => $4) THEN 0
+                ELSE login_throttles.failures
+            END, 1)
+        RETURNING in_flight
+    `
+	err := r.conn.QueryRow(ctx, sql, kind, key, freeAttempts, window.Seconds(), attemptTimeout.Seconds()).Scan(&inFlight)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("[ReserveLoginAttempt] upsert: %w", err)
+	}
+
+	return true, nil
+}
+
+// ReleaseLoginAttempt освобождает попытку, занятую ReserveLoginAttempt, после того как ее результат учтен
+func (r *UserRepository) ReleaseLoginAttempt(ctx context.Context, kind, key string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.ReleaseLoginAttempt")
+	defer span.Finish()
+
+	sql := `
+        UPDATE
+            login_throttles
+        SET
+            in_flight = GREATEST(in_flight - 1, 0)
+        WHERE
+            kind = $1 AND key = $2
+    `
+	if _, err := r.conn.Exec(ctx, sql, kind, key); err != nil {
+		return fmt.Errorf("[ReleaseLoginAttempt] update: %w", err)
+	}
+
+	return nil
+}
+
+// RegisterLoginFailure учитывает неудачную попытку входа и возвращает число попыток подряд.
+// Счет начинается заново, если последняя неудача была раньше window или если закончилась блокировка.
+func (r *UserRepository) RegisterLoginFailure(ctx context.Context, kind, key string, window time.Duration) (int, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.RegisterLoginFailure")
+	defer span.Finish()
+
+	var failures int
+	sql := `
+        INSERT INTO
+			login_throttles (
+			   kind,
+			   key,
+			   failures
+			)
+        VALUES
+			($1, $2, 1)
+        ON CONFLICT (kind, key) DO UPDATE SET
+            failures = CASE
+                WHEN login_throttles.locked OR login_throttles.last_failure_at < now() - make_interval(secs => $3) THEN 1
+                ELSE login_throttles.failures + 1
+            END,
+            locked = false,
+            last_failure_at = now()
+        RETURNING failures
+    `
+	err := r.conn.QueryRow(ctx, sql, kind, key, window.Seconds()).Scan(&failures)
+	if err != nil {
+		return 0, fmt.Errorf("[RegisterLoginFailure] upsert: %w", err)
+	}
+
+	return failures, nil
+}
+
+// BlockLogin запрещает попытки входа на время duration. locked отмечает блокировку аккаунта, которую может снять администратор.
+func (r *UserRepository) BlockLogin(ctx context.Context, kind, key string, duration time.Duration, locked bool) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.BlockLogin")
+	defer span.Finish()
+
+	sql := `
+        UPDATE
+            login_throttles
+        SET
+            blocked_until = now() + make_interval(secs => $3),
+            locked = $4
+        WHERE
+            kind = $1 AND key = $2
+    `
+	if _, err := r.conn.Exec(ctx, sql, kind, key, duration.Seconds(), locked); err != nil {
+		return fmt.Errorf("[BlockLogin] update: %w", err)
+	}
+
+	return nil
+}
+
+// ResetLoginThrottle забывает неудачные попытки входа и снимает блокировку
+func (r *UserRepository) ResetLoginThrottle(ctx context.Context, kind, key string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.ResetLoginThrottle")
+	defer span.Finish()
+
+	sql := `
+        DELETE FROM
+            login_throttles
+        WHERE
+            kind = $1 AND key = $2
+    `
+	if _, err := r.conn.Exec(ctx, sql, kind, key); err != nil {
+		return fmt.Errorf("[ResetLoginThrottle] delete: %w", err)
+	}
+
+	return nil
+}
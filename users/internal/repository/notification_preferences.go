@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgconn"
+	"github.com/opentracing/opentracing-go"
+	appErrors "users/internal/app_errors"
+	"users/internal/models"
+)
+
+// GetNotificationPreferences возвращает сохраненные настройки уведомлений пользователя.
+// События без сохраненной настройки в ответ не попадают
+func (r *UserRepository) GetNotificationPreferences(ctx context.Context, userID int) ([]models.NotificationPreferenceDAO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.GetNotificationPreferences")
+	defer span.Finish()
+
+	sql := `
+        SELECT
+            user_id,
+            event,
+            channels
+        FROM
+            notification_preferences
+        WHERE
+            user_id = $1
+    `
+	return r.queryNotificationPreferences(ctx, sql, userID)
+}
+
+// GetEventNotificationPreferences возвращает сохраненные настройки события для нескольких пользователей
+func (r *UserRepository) GetEventNotificationPreferences(ctx context.Context, event string, userIDs []int) ([]models.NotificationPreferenceDAO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.GetEventNotificationPreferences")
+	defer span.Finish()
+
+	sql := `
+        SELECT
+            user_id,
+            event,
+            channels
+        FROM
+            notification_preferences
+        WHERE
+            event = $1 AND user_id = ANY($2)
+    `
+	return r.queryNotificationPreferences(ctx, sql, event, userIDs)
+}
+
+// SetNotificationPreferences сохраняет настройки пользователя по переданным событиям, остальные не меняются
+func (r *UserRepository) SetNotificationPreferences(ctx context.Context, userID int, preferences []models.NotificationPreferenceDAO) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.SetNotificationPreferences")
+	defer span.Finish()
+
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("[SetNotificationPreferences] begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	for _, preference := range preferences {
+		_, err = tx.Exec(ctx, `
+            INSERT INTO
+				notification_preferences (user_id, event, channels, updated_at)
+            VALUES
+				($1, $2, $3, now())
+            ON CONFLICT (user_id, event) DO UPDATE SET channels = EXCLUDED.channels, updated_at = now()
+        `, userID, preference.Event, preference.Channels)
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolationCode {
+				return appErrors.ErrNotFound
+			}
+			return fmt.Errorf("[SetNotificationPreferences] upsert: %w", err)
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("[SetNotificationPreferences] commit: %w", err)
+	}
+
+	return nil
+}
+
+func (r *UserRepository) queryNotificationPreferences(ctx context.Context, sql string, args ...interface{}) ([]models.NotificationPreferenceDAO, error) {
+	rows, err := r.conn.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("[queryNotificationPreferences] select: %w", err)
+	}
+	defer rows.Close()
+
+	preferences := make([]models.NotificationPreferenceDAO, 0)
+	for rows.Next() {
+		var preference models.NotificationPreferenceDAO
+		if err := rows.Scan(&preference.UserID, &preference.Event, &preference.Channels); err != nil {
+			return nil, fmt.Errorf("[queryNotificationPreferences] scan: %w", err)
+		}
+		preferences = append(preferences, preference)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("[queryNotificationPreferences] rows: %w", err)
+	}
+
+	return preferences, nil
+}
@@ -0,0 +1,646 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/opentracing/opentracing-go"
+	appErrors "users/internal/app_errors"
+	"users/internal/models"
+)
+
+// uniqueViolationCode - код ошибки postgres при нарушении уникальности
+const uniqueViolationCode = "23505"
+
+// CreateOrganization создает организацию и делает создателя ее владельцем
+func (r *UserRepository) CreateOrganization(ctx context.Context, name string, userID int) (*models.OrganizationDAO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.CreateOrganization")
+	defer span.Finish()
+
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("[CreateOrganization] begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	organization := models.OrganizationDAO{
+		Name: name,
+		Role: models.OrganizationRoleOwner,
+	}
+	sql := `
+        INSERT INTO
+			organizations (
+			   name,
+			   created_by
+			)
+        VALUES
+			($1, $2)
+        RETURNING id, created_at
+    `
+	if err := tx.QueryRow(ctx, sql, name, userID).Scan(&organization.ID, &organization.CreatedAt); err != nil {
+		return nil, fmt.Errorf("[CreateOrganization] insert organization: %w", err)
+	}
+
+	if err := addOrganizationMember(ctx, tx, organization.ID, userID, models.OrganizationRoleOwner); err != nil {
+		return nil, fmt.Errorf("[CreateOrganization] %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("[CreateOrganization] commit: %w", err)
+	}
+
+	return &organization, nil
+}
+
+// GetUserOrganizations возвращает организации пользователя вместе с его ролью в каждой
+func (r *UserRepository) GetUserOrganizations(ctx context.Context, userID int) ([]models.OrganizationDAO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.GetUserOrganizations")
+	defer span.Finish()
+
+	sql := `
+        SELECT
+            o.id,
+            o.name,
+            m.role,
+            o.created_at
+        FROM
+            organizations o
+            JOIN organization_members m ON m.organization_id = o.id
+        WHERE
+            m.user_id = $1
+        ORDER BY
+            o.name, o.id
+    `
+	rows, err := r.conn.Query(ctx, sql, userID)
+	if err != nil {
+		return nil, fmt.Errorf("[GetUserOrganizations] select: %w", err)
+	}
+	defer rows.Close()
+
+	organizations := make([]models.OrganizationDAO, 0)
+	for rows.Next() {
+		var organization models.OrganizationDAO
+		if err := rows.Scan(&organization.ID, &organization.Name, &organization.Role, &organization.CreatedAt); err != nil {
+			return nil, fmt.Errorf("[GetUserOrganizations] scan: %w", err)
+		}
+		organizations = append(organizations, organization)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("[GetUserOrganizations] rows: %w", err)
+	}
+
+	return organizations, nil
+}
+
+// GetUserOrganization возвращает организацию и роль в ней пользователя. Для того, кто в организации
+// не состоит, она не существует
+func (r *UserRepository) GetUserOrganization(ctx context.Context, organizationID, userID int) (*models.OrganizationDAO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.GetUserOrganization")
+	defer span.Finish()
+
+	var organization models.OrganizationDAO
+	sql := `
+        SELECT
+            o.id,
+            o.name,
+            m.role,
+            o.created_at
+        FROM
+            organizations o
+            JOIN organization_members m ON m.organization_id = o.id
+        WHERE
+            o.id = $1 AND m.user_id = $2
+    `
+	err := r.conn.QueryRow(ctx, sql, organizationID, userID).
+		Scan(&organization.ID, &organization.Name, &organization.Role, &organization.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, appErrors.ErrOrganizationNotFound
+		}
+		return nil, fmt.Errorf("[GetUserOrganization] select: %w", err)
+	}
+
+	return &organization, nil
+}
+
+// GetOrganizationMembers возвращает участников организации с командами, в которых они состоят
+func (r *UserRepository) GetOrganizationMembers(ctx context.Context, organizationID int) ([]models.OrganizationMemberDAO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.GetOrganizationMembers")
+	defer span.Finish()
+
+	sql := `
+        SELECT
+            m.user_id,
+            u.username,
+            m.role,
+            COALESCE(array_agg(tm.team_id ORDER BY tm.team_id) FILTER (WHERE tm.team_id IS NOT NULL), '{}'),
+            m.created_at
+        FROM
+            organization_members m
+            JOIN users u ON u.id = m.user_id
+            LEFT JOIN team_members tm ON tm.organization_id = m.organization_id AND tm.user_id = m.user_id
+        WHERE
+            m.organization_id = $1
+        GROUP BY
+            m.user_id, u.username, m.role, m.created_at
+        ORDER BY
+            u.username, m.user_id
+    `
+
+	return r.queryMembers(ctx, "GetOrganizationMembers", sql, organizationID)
+}
+
+// GetTeamMembers возвращает участников команды, у каждого - все его команды в организации
+func (r *UserRepository) GetTeamMembers(ctx context.Context, teamID int) ([]models.OrganizationMemberDAO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.GetTeamMembers")
+	defer span.Finish()
+
+	sql := `
+        SELECT
+            m.user_id,
+            u.username,
+            m.role,
+            array_agg(tm.team_id ORDER BY tm.team_id),
+            m.created_at
+        FROM
+            team_members t
+            JOIN organization_members m ON m.organization_id = t.organization_id AND m.user_id = t.user_id
+            JOIN users u ON u.id = m.user_id
+            JOIN team_members tm ON tm.organization_id = m.organization_id AND tm.user_id = m.user_id
+        WHERE
+            t.team_id = $1
+        GROUP BY
+            m.user_id, u.username, m.role, m.created_at
+        ORDER BY
+            u.username, m.user_id
+    `
+
+	return r.queryMembers(ctx, "GetTeamMembers", sql, teamID)
+}
+
+func (r *UserRepository) queryMembers(ctx context.Context, method, sql string, args ...interface{}) ([]models.OrganizationMemberDAO, error) {
+	rows, err := r.conn.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("[%s] select: %w", method, err)
+	}
+	defer rows.Close()
+
+	members := make([]models.OrganizationMemberDAO, 0)
+	for rows.Next() {
+		var member models.OrganizationMemberDAO
+		if err := rows.Scan(&member.UserID, &member.Username, &member.Role, &member.TeamIDs, &member.JoinedAt); err != nil {
+			return nil, fmt.Errorf("[%s] scan: %w", method, err)
+		}
+		members = append(members, member)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("[%s] rows: %w", method, err)
+	}
+
+	return members, nil
+}
+
+// SetMemberRole меняет роль участника. Проверка последнего владельца и изменение идут под блокировкой
+// организации, чтобы два владельца не могли одновременно разжаловать друг друга
+func (r *UserRepository) SetMemberRole(ctx context.Context, organizationID, memberID int, role string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.SetMemberRole")
+	defer span.Finish()
+
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("[SetMemberRole] begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := checkLastOwner(ctx, tx, organizationID, memberID, role); err != nil {
+		return fmt.Errorf("[SetMemberRole] %w", err)
+	}
+
+	sql := `
+        UPDATE
+            organization_members
+        SET
+            role = $3
+        WHERE
+            organization_id = $1 AND user_id = $2
+    `
+	if _, err := tx.Exec(ctx, sql, organizationID, memberID, role); err != nil {
+		return fmt.Errorf("[SetMemberRole] update: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("[SetMemberRole] commit: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveMember исключает участника из организации и из всех ее команд. Последнего владельца исключить нельзя
+func (r *UserRepository) RemoveMember(ctx context.Context, organizationID, memberID int) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.RemoveMember")
+	defer span.Finish()
+
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("[RemoveMember] begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := checkLastOwner(ctx, tx, organizationID, memberID, ""); err != nil {
+		return fmt.Errorf("[RemoveMember] %w", err)
+	}
+
+	sql := `
+        DELETE FROM
+            organization_members
+        WHERE
+            organization_id = $1 AND user_id = $2
+    `
+	if _, err := tx.Exec(ctx, sql, organizationID, memberID); err != nil {
+		return fmt.Errorf("[RemoveMember] delete: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("[RemoveMember] commit: %w", err)
+	}
+
+	return nil
+}
+
+// CreateInvitation сохраняет приглашение. Прежние неиспользованные приглашения на тот же адрес отзываются,
+// чтобы действовала только последняя ссылка
+func (r *UserRepository) CreateInvitation(ctx context.Context, invitation *models.InvitationDAO) (*models.InvitationDAO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.CreateInvitation")
+	defer span.Finish()
+
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("[CreateInvitation] begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sql := `
+        UPDATE
+            organization_invitations
+        SET
+            revoked_at = now()
+        WHERE
+            organization_id = $1 AND lower(email) = lower($2) AND accepted_at IS NULL AND revoked_at IS NULL
+    `
+	if _, err := tx.Exec(ctx, sql, invitation.OrganizationID, invitation.Email); err != nil {
+		return nil, fmt.Errorf("[CreateInvitation] revoke previous: %w", err)
+	}
+
+	stored := *invitation
+	sql = `
+        INSERT INTO
+			organization_invitations (
+			   id,
+			   organization_id,
+			   email,
+			   role,
+			   token_hash,
+			   invited_by,
+			   expires_at
+			)
+        VALUES
+			($1, $2, $3, $4, $5, $6, $7)
+        RETURNING created_at
+    `
+	err = tx.QueryRow(ctx, sql, invitation.ID, invitation.OrganizationID, invitation.Email, invitation.Role,
+		invitation.TokenHash, invitation.InvitedBy, invitation.ExpiresAt).Scan(&stored.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("[CreateInvitation] insert: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("[CreateInvitation] commit: %w", err)
+	}
+
+	return &stored, nil
+}
+
+// GetInvitationByHash ищет действующее приглашение по хэшу токена из письма
+func (r *UserRepository) GetInvitationByHash(ctx context.Context, tokenHash string) (*models.InvitationDAO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.GetInvitationByHash")
+	defer span.Finish()
+
+	var invitation models.InvitationDAO
+	sql := `
+        SELECT
+            id,
+            organization_id,
+            email,
+            role,
+            expires_at,
+            created_at
+        FROM
+            organization_invitations
+        WHERE
+            token_hash = $1 AND accepted_at IS NULL AND revoked_at IS NULL AND expires_at > now()
+    `
+	err := r.conn.QueryRow(ctx, sql, tokenHash).
+		Scan(&invitation.ID, &invitation.OrganizationID, &invitation.Email, &invitation.Role, &invitation.ExpiresAt, &invitation.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, appErrors.ErrInvalidInvitation
+		}
+		return nil, fmt.Errorf("[GetInvitationByHash] select: %w", err)
+	}
+
+	return &invitation, nil
+}
+
+// AcceptInvitation отмечает приглашение принятым и добавляет пользователя в организацию.
+// Приглашение принимается один раз, даже если по ссылке перешли одновременно
+func (r *UserRepository) AcceptInvitation(ctx context.Context, invitationID uuid.UUID, userID int) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.AcceptInvitation")
+	defer span.Finish()
+
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("[AcceptInvitation] begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var organizationID int
+	var role string
+	sql := `
+        UPDATE
+            organization_invitations
+        SET
+            accepted_at = now()
+        WHERE
+            id = $1 AND accepted_at IS NULL AND revoked_at IS NULL AND expires_at > now()
+        RETURNING organization_id, role
+    `
+	if err := tx.QueryRow(ctx, sql, invitationID).Scan(&organizationID, &role); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return appErrors.ErrInvalidInvitation
+		}
+		return fmt.Errorf("[AcceptInvitation] update: %w", err)
+	}
+
+	if err := addOrganizationMember(ctx, tx, organizationID, userID, role); err != nil {
+		return fmt.Errorf("[AcceptInvitation] %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("[AcceptInvitation] commit: %w", err)
+	}
+
+	return nil
+}
+
+// CreateTeam создает команду. Имена команд в одной организации не повторяются
+func (r *UserRepository) CreateTeam(ctx context.Context, organizationID int, name string) (*models.TeamDAO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.CreateTeam")
+	defer span.Finish()
+
+	team := models.TeamDAO{
+		OrganizationID: organizationID,
+		Name:           name,
+	}
+	sql := `
+        INSERT INTO
+			teams (
+			   organization_id,
+			   name
+			)
+        VALUES
+			($1, $2)
+        RETURNING id, created_at
+    `
+	err := r.conn.QueryRow(ctx, sql, organizationID, name).Scan(&team.ID, &team.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+			return nil, appErrors.ErrTeamNameIsUsed
+		}
+		return nil, fmt.Errorf("[CreateTeam] insert: %w", err)
+	}
+
+	return &team, nil
+}
+
+func (r *UserRepository) GetTeams(ctx context.Context, organizationID int) ([]models.TeamDAO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.GetTeams")
+	defer span.Finish()
+
+	sql := `
+        SELECT
+            id,
+            organization_id,
+            name,
+            created_at
+        FROM
+            teams
+        WHERE
+            organization_id = $1
+        ORDER BY
+            name
+    `
+	rows, err := r.conn.Query(ctx, sql, organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("[GetTeams] select: %w", err)
+	}
+	defer rows.Close()
+
+	teams := make([]models.TeamDAO, 0)
+	for rows.Next() {
+		var team models.TeamDAO
+		if err := rows.Scan(&team.ID, &team.OrganizationID, &team.Name, &team.CreatedAt); err != nil {
+			return nil, fmt.Errorf("[GetTeams] scan: %w", err)
+		}
+		teams = append(teams, team)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("[GetTeams] rows: %w", err)
+	}
+
+	return teams, nil
+}
+
+// GetTeam ищет команду внутри организации, команда другой организации не находится
+func (r *UserRepository) GetTeam(ctx context.Context, organizationID, teamID int) (*models.TeamDAO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.GetTeam")
+	defer span.Finish()
+
+	var team models.TeamDAO
+	sql := `
+        SELECT
+            id,
+            organization_id,
+            name,
+            created_at
+        FROM
+            teams
+        WHERE
+            id = $1 AND organization_id = $2
+    `
+	err := r.conn.QueryRow(ctx, sql, teamID, organizationID).Scan(&team.ID, &team.OrganizationID, &team.Name, &team.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, appErrors.ErrTeamNotFound
+		}
+		return nil, fmt.Errorf("[GetTeam] select: %w", err)
+	}
+
+	return &team, nil
+}
+
+// AddTeamMember добавляет участника организации в ее команду. Повторное добавление не считается ошибкой
+func (r *UserRepository) AddTeamMember(ctx context.Context, organizationID, teamID, memberID int) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.AddTeamMember")
+	defer span.Finish()
+
+	sql := `
+        INSERT INTO
+			team_members (
+			   team_id,
+			   organization_id,
+			   user_id
+			)
+        VALUES
+			($1, $2, $3)
+        ON CONFLICT (team_id, user_id) DO NOTHING
+    `
+	_, err := r.conn.Exec(ctx, sql, teamID, organizationID, memberID)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolationCode {
+			return appErrors.ErrMemberNotFound
+		}
+		return fmt.Errorf("[AddTeamMember] insert: %w", err)
+	}
+
+	return nil
+}
+
+func (r *UserRepository) RemoveTeamMember(ctx context.Context, teamID, memberID int) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.RemoveTeamMember")
+	defer span.Finish()
+
+	sql := `
+        DELETE FROM
+            team_members
+        WHERE
+            team_id = $1 AND user_id = $2
+    `
+	tag, err := r.conn.Exec(ctx, sql, teamID, memberID)
+	if err != nil {
+		return fmt.Errorf("[RemoveTeamMember] delete: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return appErrors.ErrMemberNotFound
+	}
+
+	return nil
+}
+
+// GetUserMemberships возвращает организации и команды, в которых состоит пользователь
+func (r *UserRepository) GetUserMemberships(ctx context.Context, userID int) (*models.MembershipsDAO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.GetUserMemberships")
+	defer span.Finish()
+
+	memberships := models.MembershipsDAO{}
+	sql := `
+        SELECT
+            COALESCE((SELECT array_agg(organization_id ORDER BY organization_id) FROM organization_members WHERE user_id = $1), '{}'),
+            COALESCE((SELECT array_agg(team_id ORDER BY team_id) FROM team_members WHERE user_id = $1), '{}')
+    `
+	if err := r.conn.QueryRow(ctx, sql, userID).Scan(&memberships.OrganizationIDs, &memberships.TeamIDs); err != nil {
+		return nil, fmt.Errorf("[GetUserMemberships] select: %w", err)
+	}
+
+	return &memberships, nil
+}
+
+func addOrganizationMember(ctx context.Context, tx pgx.Tx, organizationID, userID int, role string) error {
+	sql := `
+        INSERT INTO
+			organization_members (
+			   organization_id,
+			   user_id,
+			   role
+			)
+        VALUES
+			($1, $2, $3)
+    `
+	_, err := tx.Exec(ctx, sql, organizationID, userID, role)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+			return appErrors.ErrAlreadyMember
+		}
+		return fmt.Errorf("insert member: %w", err)
+	}
+
+	return nil
+}
+
+// checkLastOwner блокирует организацию и проверяет, что после смены роли участника на newRole
+// (пустая роль - исключение) у организации останется владелец
+func checkLastOwner(ctx context.Context, tx pgx.Tx, organizationID, memberID int, newRole string) error {
+	sql := `
+        SELECT
+            id
+        FROM
+            organizations
+        WHERE
+            id = $1
+        FOR UPDATE
+    `
+	if err := tx.QueryRow(ctx, sql, organizationID).Scan(new(int)); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return appErrors.ErrOrganizationNotFound
+		}
+		return fmt.Errorf("lock organization: %w", err)
+	}
+
+	var role string
+	sql = `
+        SELECT
+            role
+        FROM
+            organization_members
+        WHERE
+            organization_id = $1 AND user_id = $2
+    `
+	if err := tx.QueryRow(ctx, sql, organizationID, memberID).Scan(&role); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return appErrors.ErrMemberNotFound
+		}
+		return fmt.Errorf("get member: %w", err)
+	}
+
+	if role != models.OrganizationRoleOwner || newRole == models.OrganizationRoleOwner {
+		return nil
+	}
+
+	var owners int
+	sql = `
+        SELECT
+            count(*)
+        FROM
+            organization_members
+        WHERE
+            organization_id = $1 AND role = $2
+    `
+	if err := tx.QueryRow(ctx, sql, organizationID, models.OrganizationRoleOwner).Scan(&owners); err != nil {
+		return fmt.Errorf("count owners: %w", err)
+	}
+
+	if owners <= 1 {
+		return appErrors.ErrLastOrganizationOwner
+	}
+
+	return nil
+}
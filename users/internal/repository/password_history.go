@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/jackc/pgx/v4"
+	"github.com/opentracing/opentracing-go"
+)
+
+// GetPasswordHistory возвращает хэши последних limit паролей пользователя, начиная с текущего
+func (r *UserRepository) GetPasswordHistory(ctx context.Context, userID int, limit int) ([]string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.GetPasswordHistory")
+	defer span.Finish()
+
+	sql := `
+        SELECT
+            password
+        FROM (
+            SELECT
+                password,
+                0 AS position,
+                now() AS created_at
+            FROM
+                users
+            WHERE
+                id = $1
+            UNION ALL
+            SELECT
+                password,
+                1 AS position,
+                created_at
+            FROM
+                password_history
+            WHERE
+                user_id = $1
+        ) passwords
+        ORDER BY
+            position, created_at DESC
+        LIMIT $2
+    `
+	rows, err := r.conn.Query(ctx, sql, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("[GetPasswordHistory] select: %w", err)
+	}
+	defer rows.Close()
+
+	hashes := make([]string, 0, limit)
+	for rows.Next() {
+		var hash string
+		if err := rows.Scan(&hash); err != nil {
+			return nil, fmt.Errorf("[GetPasswordHistory] scan: %w", err)
+		}
+		hashes = append(hashes, hash)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("[GetPasswordHistory] rows: %w", err)
+	}
+
+	return hashes, nil
+}
+
+// PrunePasswordHistory оставляет в истории только keep последних прежних паролей пользователя
+func (r *UserRepository) PrunePasswordHistory(ctx context.Context, userID int, keep int) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.PrunePasswordHistory")
+	defer span.Finish()
+
+	sql := `
+        DELETE FROM
+            password_history
+        WHERE
+            user_id = $1 AND id NOT IN (
+                SELECT
+                    id
+                FROM
+                    password_history
+                WHERE
+                    user_id = $1
+                ORDER BY
+                    created_at DESC, id DESC
+                LIMIT $2
+            )
+    `
+	if _, err := r.conn.Exec(ctx, sql, userID, keep); err != nil {
+		return fmt.Errorf("[PrunePasswordHistory] delete: %w", err)
+	}
+
+	return nil
+}
+
+// savePasswordHistory переносит текущий хэш пароля в историю, вызывается в транзакции перед сменой пароля
+func savePasswordHistory(ctx context.Context, tx pgx.Tx, userID int) error {
+	sql := `
+        INSERT INTO
+            password_history (user_id, password)
+        SELECT
+            id,
+            password
+        FROM
+            users
+        WHERE
+            id = $1 AND password IS NOT NULL
+    `
+	if _, err := tx.Exec(ctx, sql, userID); err != nil {
+		return fmt.Errorf("[savePasswordHistory] insert: %w", err)
+	}
+
+	return nil
+}
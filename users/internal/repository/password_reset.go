@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v4"
+	"github.com/opentracing/opentracing-go"
+	"time"
+	appErrors "users/internal/app_errors"
+)
+
+// CreatePasswordResetToken сохраняет хэш нового токена сброса пароля. Выданные ранее неиспользованные
+// токены пользователя удаляются, поэтому рабочей остается только ссылка из последнего письма.
+func (r *UserRepository) CreatePasswordResetToken(ctx context.Context, userID int, tokenHash string, ttl time.Duration) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.CreatePasswordResetToken")
+	defer span.Finish()
+
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("[CreatePasswordResetToken] begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sql := `
+        DELETE FROM
+            password_reset_tokens
+        WHERE
+            user_id = $1 AND consumed_at IS NULL
+    `
+	if _, err := tx.Exec(ctx, sql, userID); err != nil {
+		return fmt.Errorf("[CreatePasswordResetToken] delete previous: %w", err)
+	}
+
+	sql = `
+        INSERT INTO
+			password_reset_tokens (
+			   user_id,
+			   token_hash,
+			   expires_at
+			)
+        VALUES
+			($1, $2, now() + make_interval(secs => $3))
+    `
+	if _, err := tx.Exec(ctx, sql, userID, tokenHash, ttl.Seconds()); err != nil {
+		return fmt.Errorf("[CreatePasswordResetToken] insert: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("[CreatePasswordResetToken] commit: %w", err)
+	}
+
+	return nil
+}
+
+// HasRecentPasswordResetToken проверяет, выдавался ли пользователю токен сброса пароля за последний interval
+func (r *UserRepository) HasRecentPasswordResetToken(ctx context.Context, userID int, interval time.Duration) (bool, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.HasRecentPasswordResetToken")
+	defer span.Finish()
+
+	var exists bool
+	sql := `
+        SELECT EXISTS (
+            SELECT 1
+            FROM
+                password_reset_tokens
+            WHERE
+                user_id = $1 AND created_at > now() - make_interval(secs => $2)
+        )
+    `
+	err := r.conn.QueryRow(ctx, sql, userID, interval.Seconds()).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("[HasRecentPasswordResetToken] select: %w", err)
+	}
+
+	return exists, nil
+}
+
+// GetPasswordResetTokenUser возвращает владельца действующего токена сброса пароля, не погашая токен
+func (r *UserRepository) GetPasswordResetTokenUser(ctx context.Context, tokenHash string) (int, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.GetPasswordResetTokenUser")
+	defer span.Finish()
+
+	var userID int
+	sql := `
+        SELECT
+            user_id
+        FROM
+            password_reset_tokens
+        WHERE
+            token_hash = $1 AND consumed_at IS NULL AND expires_at > now()
+    `
+	err := r.conn.QueryRow(ctx, sql, tokenHash).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, appErrors.ErrInvalidPasswordResetToken
+		}
+		return 0, fmt.Errorf("[GetPasswordResetTokenUser] select: %w", err)
+	}
+
+	return userID, nil
+}
+
+// ResetPassword одной транзакцией гасит токен сброса, сохраняет новый хэш пароля, удаляет
+// остальные токены пользователя и завершает его сессии. Просроченный, уже использованный или неизвестный токен не принимается.
+func (r *UserRepository) ResetPassword(ctx context.Context, tokenHash, newPassword string) (int, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.ResetPassword")
+	defer span.Finish()
+
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("[ResetPassword] begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var userID int
+	sql := `
+        UPDATE
+            password_reset_tokens
+        SET
+            consumed_at = now()
+        WHERE
+            token_hash = $1 AND consumed_at IS NULL AND expires_at > now()
+        RETURNING user_id
+    `
+	err = tx.QueryRow(ctx, sql, tokenHash).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, appErrors.ErrInvalidPasswordResetToken
+		}
+		return 0, fmt.Errorf("[ResetPassword] consume: %w", err)
+	}
+
+	if err := savePasswordHistory(ctx, tx, userID); err != nil {
+		return 0, fmt.Errorf("[ResetPassword] %w", err)
+	}
+
+	sql = `
+        UPDATE
+            users
+        SET
+            password = $2
+        WHERE
+            id = $1
+    `
+	if _, err := tx.Exec(ctx, sql, userID, newPassword); err != nil {
+		return 0, fmt.Errorf("[ResetPassword] update password: %w", err)
+	}
+
+	sql = `
+        DELETE FROM
+            password_reset_tokens
+        WHERE
+            user_id = $1 AND consumed_at IS NULL
+    `
+	if _, err := tx.Exec(ctx, sql, userID); err != nil {
+		return 0, fmt.Errorf("[ResetPassword] delete other: %w", err)
+	}
+
+	// после смены пароля все входы, сделанные со старым паролем, завершаются
+	sql = `
+        UPDATE
+            sessions
+        SET
+            revoked_at = now()
+        WHERE
+            user_id = $1 AND revoked_at IS NULL
+    `
+	if _, err := tx.Exec(ctx, sql, userID); err != nil {
+		return 0, fmt.Errorf("[ResetPassword] revoke sessions: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("[ResetPassword] commit: %w", err)
+	}
+
+	return userID, nil
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v4"
+	"github.com/opentracing/opentracing-go"
+	appErrors "users/internal/app_errors"
+)
+
+// UpdateAvatarKey сохраняет ключ нового аватара и возвращает ключ прежнего, чтобы удалить его файлы из хранилища
+func (r *UserRepository) UpdateAvatarKey(ctx context.Context, userID int, avatarKey string) (string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.UpdateAvatarKey")
+	defer span.Finish()
+
+	sql := `
+        UPDATE
+            users u
+        SET
+            avatar_key = $2
+        FROM
+            (SELECT avatar_key FROM users WHERE id = $1 FOR UPDATE) previous
+        WHERE
+            u.id = $1
+        RETURNING
+            previous.avatar_key
+    `
+	var previousKey string
+	err := r.conn.QueryRow(ctx, sql, userID, avatarKey).Scan(&previousKey)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", appErrors.ErrNotFound
+		}
+		return "", fmt.Errorf("[UpdateAvatarKey] update: %w", err)
+	}
+
+	return previousKey, nil
+}
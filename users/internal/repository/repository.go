@@ -2,9 +2,13 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgconn"
 	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/opentracing/opentracing-go"
+	appErrors "users/internal/app_errors"
 	"users/internal/models"
 )
 
@@ -34,6 +38,11 @@ func (r *UserRepository) CreateUser(ctx context.Context, user *models.CreateUser
 	err := r.conn.QueryRow(ctx, sql, user.Username, user.Password, user.Email).
 		Scan(&userID)
 	if err != nil {
+		// username и email уникальны без учета регистра, одновременная регистрация с тем же именем упирается в индекс
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+			return 0, appErrors.ErrUsernameOrEmailIsUsed
+		}
 		return 0, err
 	}
 	return userID, nil
@@ -48,18 +57,36 @@ func (r *UserRepository) UpdateUser(ctx context.Context, user *models.UserDAO) e
             users
         SET 
 			username = $2, 
-			email = $3
+			email = $3,
+			display_name = $4,
+			timezone = $5,
+			locale = $6
         WHERE 
             id = $1
     `
-	_, err := r.conn.Exec(ctx, sql, user.ID, user.Username, user.Email)
+	_, err := r.conn.Exec(ctx, sql, user.ID, user.Username, user.Email, user.DisplayName, user.Timezone, user.Locale)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+		return appErrors.ErrUsernameOrEmailIsUsed
+	}
 	return err
 }
 
+// UpdatePassword сохраняет новый хэш пароля, прежний хэш переносится в историю паролей
 func (r *UserRepository) UpdatePassword(ctx context.Context, userID int, newPassword string) error {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.UpdatePassword")
 	defer span.Finish()
 
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("[UpdatePassword] begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := savePasswordHistory(ctx, tx, userID); err != nil {
+		return fmt.Errorf("[UpdatePassword] %w", err)
+	}
+
 	sql := `
         UPDATE 
             users
@@ -68,21 +95,31 @@ func (r *UserRepository) UpdatePassword(ctx context.Context, userID int, newPass
         WHERE 
             id = $1
     `
-	_, err := r.conn.Exec(ctx, sql, userID, newPassword)
-	return err
+	if _, err := tx.Exec(ctx, sql, userID, newPassword); err != nil {
+		return fmt.Errorf("[UpdatePassword] update: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("[UpdatePassword] commit: %w", err)
+	}
+
+	return nil
 }
 
-func (r *UserRepository) DeleteUser(ctx context.Context, userID int) error {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.DeleteUser")
+// ReplacePasswordHash заменяет хэш пароля, только если в базе все еще oldHash
+func (r *UserRepository) ReplacePasswordHash(ctx context.Context, userID int, oldHash, newHash string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.ReplacePasswordHash")
 	defer span.Finish()
 
 	sql := `
-        DELETE FROM 
-		    users
-        WHERE 
-            id = $1
+        UPDATE
+            users
+        SET
+            password = $3
+        WHERE
+            id = $1 AND password = $2
     `
-	_, err := r.conn.Exec(ctx, sql, userID)
+	_, err := r.conn.Exec(ctx, sql, userID, oldHash, newHash)
 	return err
 }
 
@@ -96,20 +133,122 @@ func (r *UserRepository) GetUserByID(ctx context.Context, userID int) (*models.U
             id, 
             username, 
             password, 
-            email
+            email,
+            email_verified,
+            role,
+            display_name,
+            timezone,
+            locale,
+            avatar_key
         FROM 
             users
         WHERE 
-            id = $1
+            id = $1 AND deleted_at IS NULL
     `
 	err := r.conn.QueryRow(ctx, sql, userID).
-		Scan(&user.ID, &user.Username, &user.Password, &user.Email)
+		Scan(&user.ID, &user.Username, &user.Password, &user.Email, &user.EmailVerified, &user.Role,
+			&user.DisplayName, &user.Timezone, &user.Locale, &user.AvatarKey)
 	if err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
+// GetUsersByIDs возвращает пользователей с переданными ID. Пользователей, которых нет или которые удаляются, в ответе нет
+func (r *UserRepository) GetUsersByIDs(ctx context.Context, userIDs []int) ([]models.UserDAO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.GetUsersByIDs")
+	defer span.Finish()
+
+	sql := `
+        SELECT
+            id,
+            username,
+            email,
+            email_verified,
+            role,
+            display_name,
+            timezone,
+            locale,
+            avatar_key
+        FROM
+            users
+        WHERE
+            id = ANY($1) AND deleted_at IS NULL
+        ORDER BY
+            id
+    `
+	rows, err := r.conn.Query(ctx, sql, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("[GetUsersByIDs] select: %w", err)
+	}
+	defer rows.Close()
+
+	users := make([]models.UserDAO, 0, len(userIDs))
+	for rows.Next() {
+		var user models.UserDAO
+		err := rows.Scan(&user.ID, &user.Username, &user.Email, &user.EmailVerified, &user.Role,
+			&user.DisplayName, &user.Timezone, &user.Locale, &user.AvatarKey)
+		if err != nil {
+			return nil, fmt.Errorf("[GetUsersByIDs] scan: %w", err)
+		}
+		users = append(users, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("[GetUsersByIDs] rows: %w", err)
+	}
+
+	return users, nil
+}
+
+// GetUsersByUsernames возвращает пользователей с переданными именами. Имена передаются в нижнем регистре
+// и сравниваются без учета регистра. Пользователей, которых нет или которые удаляются, в ответе нет
+func (r *UserRepository) GetUsersByUsernames(ctx context.Context, usernames []string) ([]models.UserDAO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.GetUsersByUsernames")
+	defer span.Finish()
+
+	sql := `
+        SELECT
+            id,
+            username,
+            email,
+            email_verified,
+            role,
+            display_name,
+            timezone,
+            locale,
+            avatar_key
+        FROM
+            users
+        WHERE
+            lower(username) = ANY($1) AND deleted_at IS NULL
+        ORDER BY
+            id
+    `
+	rows, err := r.conn.Query(ctx, sql, usernames)
+	if err != nil {
+		return nil, fmt.Errorf("[GetUsersByUsernames] select: %w", err)
+	}
+	defer rows.Close()
+
+	users := make([]models.UserDAO, 0, len(usernames))
+	for rows.Next() {
+		var user models.UserDAO
+		err := rows.Scan(&user.ID, &user.Username, &user.Email, &user.EmailVerified, &user.Role,
+			&user.DisplayName, &user.Timezone, &user.Locale, &user.AvatarKey)
+		if err != nil {
+			return nil, fmt.Errorf("[GetUsersByUsernames] scan: %w", err)
+		}
+		users = append(users, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("[GetUsersByUsernames] rows: %w", err)
+	}
+
+	return users, nil
+}
+
 func (r *UserRepository) GetUserByUsernameOrEmail(ctx context.Context, username, email string) (*models.UserDAO, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.GetUserByUsernameOrEmail")
 	defer span.Finish()
@@ -117,19 +256,25 @@ func (r *UserRepository) GetUserByUsernameOrEmail(ctx context.Context, username,
 	var user models.UserDAO
 	// создадим конструктор квери. определим, что и откуда забрать
 	queryBuilder := sq.
-		Select("id", "username", "password", "email").
-		From("users")
+		Select("id", "username", "password", "email", "email_verified", "role",
+			"display_name", "timezone", "locale", "avatar_key").
+		From("users").
+		Where("deleted_at IS NULL")
+
+	// username и email сравниваются без учета регистра, как в уникальных индексах
+	usernameEq := sq.Expr("lower(username) = lower(?)", username)
+	emailEq := sq.Expr("lower(email) = lower(?)", email)
 
 	// убедимся, что username или email не являются пустыми строками перед добавлением их в запрос
 	if username != "" && email == "" {
-		queryBuilder = queryBuilder.Where(sq.Eq{"username": username})
+		queryBuilder = queryBuilder.Where(usernameEq)
 	}
 	if email != "" && username == "" {
-		queryBuilder = queryBuilder.Where(sq.Eq{"email": email})
+		queryBuilder = queryBuilder.Where(emailEq)
 	}
 
 	if email != "" && username != "" {
-		queryBuilder = queryBuilder.Where(sq.Or{sq.Eq{"username": username}, sq.Eq{"email": email}})
+		queryBuilder = queryBuilder.Where(sq.Or{usernameEq, emailEq})
 	}
 
 	// создадим квери и аргументы для нее, зададим формат плэйсхолдеров в виде доллара
@@ -140,7 +285,8 @@ func (r *UserRepository) GetUserByUsernameOrEmail(ctx context.Context, username,
 
 	// выполним запрос с созданной квери и подготовленными аргументами
 	err = r.conn.QueryRow(ctx, sql, args...).
-		Scan(&user.ID, &user.Username, &user.Password, &user.Email)
+		Scan(&user.ID, &user.Username, &user.Password, &user.Email, &user.EmailVerified, &user.Role,
+			&user.DisplayName, &user.Timezone, &user.Locale, &user.AvatarKey)
 	if err != nil {
 		return nil, err
 	}
@@ -148,6 +294,45 @@ func (r *UserRepository) GetUserByUsernameOrEmail(ctx context.Context, username,
 	return &user, nil
 }
 
+// IsUsernameOrEmailUsed проверяет без учета регистра, занят ли username или email. Пользователь, который ждет
+// удаления, занимает их до конца удаления: оно может быть отменено, и уникальные индексы тоже его учитывают
+func (r *UserRepository) IsUsernameOrEmailUsed(ctx context.Context, username, email string) (bool, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.IsUsernameOrEmailUsed")
+	defer span.Finish()
+
+	var conditions sq.Or
+	if username != "" {
+		conditions = append(conditions, sq.Expr("lower(username) = lower(?)", username))
+	}
+	if email != "" {
+		conditions = append(conditions, sq.Expr("lower(email) = lower(?)", email))
+	}
+	if len(conditions) == 0 {
+		return false, nil
+	}
+
+	sql, args, err := sq.
+		Select("1").
+		From("users").
+		Where(conditions).
+		Prefix("SELECT EXISTS (").
+		Suffix(")").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return false, fmt.Errorf("[IsUsernameOrEmailUsed] build query: %w", err)
+	}
+
+	var used bool
+	if err := r.conn.QueryRow(ctx, sql, args...).Scan(&used); err != nil {
+		return false, fmt.Errorf("[IsUsernameOrEmailUsed] select: %w", err)
+	}
+
+	return used, nil
+}
+
+// GetUserByUsername ищет пользователя, которому принадлежит username, в том числе ждущего удаления.
+// По нему проверяется, свободно ли имя, поэтому он учитывает те же строки, что и уникальный индекс
 func (r *UserRepository) GetUserByUsername(ctx context.Context, username string) (*models.UserDAO, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.GetUserByUsername")
 	defer span.Finish()
@@ -158,14 +343,21 @@ func (r *UserRepository) GetUserByUsername(ctx context.Context, username string)
             id, 
             username, 
             password, 
-            email
+            email,
+            email_verified,
+            role,
+            display_name,
+            timezone,
+            locale,
+            avatar_key
         FROM 
             users
         WHERE 
-            username = $1
+            lower(username) = lower($1)
     `
 	err := r.conn.QueryRow(ctx, sql, username).
-		Scan(&user.ID, &user.Username, &user.Password, &user.Email)
+		Scan(&user.ID, &user.Username, &user.Password, &user.Email, &user.EmailVerified, &user.Role,
+			&user.DisplayName, &user.Timezone, &user.Locale, &user.AvatarKey)
 	if err != nil {
 		return nil, err
 	}
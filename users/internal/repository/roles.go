@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgconn"
+	"github.com/opentracing/opentracing-go"
+	appErrors "users/internal/app_errors"
+)
+
+// foreignKeyViolationCode - код ошибки postgres при ссылке на несуществующую запись
+const foreignKeyViolationCode = "23503"
+
+// GetRolePermissions возвращает разрешения роли
+func (r *UserRepository) GetRolePermissions(ctx context.Context, role string) ([]string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.GetRolePermissions")
+	defer span.Finish()
+
+	sql := `
+        SELECT
+            permission
+        FROM
+            role_permissions
+        WHERE
+            role = $1
+        ORDER BY
+            permission
+    `
+	rows, err := r.conn.Query(ctx, sql, role)
+	if err != nil {
+		return nil, fmt.Errorf("[GetRolePermissions] select: %w", err)
+	}
+	defer rows.Close()
+
+	permissions := make([]string, 0)
+	for rows.Next() {
+		var permission string
+		if err := rows.Scan(&permission); err != nil {
+			return nil, fmt.Errorf("[GetRolePermissions] scan: %w", err)
+		}
+		permissions = append(permissions, permission)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("[GetRolePermissions] rows: %w", err)
+	}
+
+	return permissions, nil
+}
+
+// SetUserRole назначает пользователю роль. Роль должна быть заведена в таблице roles.
+func (r *UserRepository) SetUserRole(ctx context.Context, userID int, role string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.SetUserRole")
+	defer span.Finish()
+
+	sql := `
+        UPDATE
+            users
+        SET
+            role = $2
+        WHERE
+            id = $1
+    `
+	tag, err := r.conn.Exec(ctx, sql, userID, role)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolationCode {
+			return appErrors.ErrUnknownRole
+		}
+		return fmt.Errorf("[SetUserRole] update: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return appErrors.ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/opentracing/opentracing-go"
+	appErrors "users/internal/app_errors"
+	"users/internal/models"
+)
+
+func (r *UserRepository) CreateSession(ctx context.Context, session *models.SessionDTO) (*models.SessionDAO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.CreateSession")
+	defer span.Finish()
+
+	stored := models.SessionDAO{
+		ID:             session.ID,
+		UserID:         session.UserID,
+		RefreshTokenID: session.RefreshTokenID,
+	}
+	sql := `
+        INSERT INTO
+			sessions (
+			   id,
+			   user_id,
+			   refresh_token_id,
+			   expires_at
+			)
+        VALUES
+			($1, $2, $3, now() + make_interval(secs => $4))
+        RETURNING created_at, refreshed_at, expires_at
+    `
+	err := r.conn.QueryRow(ctx, sql, session.ID, session.UserID, session.RefreshTokenID, session.ExpiresIn.Seconds()).
+		Scan(&stored.CreatedAt, &stored.RefreshedAt, &stored.ExpiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("[CreateSession] insert: %w", err)
+	}
+
+	return &stored, nil
+}
+
+// RotateSession заменяет refresh токен сессии на новый. Если предъявлен не последний выданный токен,
+// значит, старый токен кто-то сохранил и использует повторно: сессия завершается целиком,
+// и ни владелец, ни тот, кто завладел токеном, не может ее продолжить.
+func (r *UserRepository) RotateSession(ctx context.Context, rotate *models.RotateSessionDTO) (*models.SessionDAO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.RotateSession")
+	defer span.Finish()
+
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("[RotateSession] begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var (
+		session models.SessionDAO
+		expired bool
+	)
+	sql := `
+        SELECT
+            id,
+            user_id,
+            refresh_token_id,
+            revoked_at,
+            expires_at <= now()
+        FROM
+            sessions
+        WHERE
+            id = $1
+        FOR UPDATE
+    `
+	err = tx.QueryRow(ctx, sql, rotate.ID).
+		Scan(&session.ID, &session.UserID, &session.RefreshTokenID, &session.RevokedAt, &expired)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, appErrors.ErrSessionNotFound
+		}
+		return nil, fmt.Errorf("[RotateSession] select: %w", err)
+	}
+
+	if session.RevokedAt != nil || expired {
+		return nil, appErrors.ErrSessionNotFound
+	}
+
+	if session.RefreshTokenID != rotate.RefreshTokenID {
+		sql = `
+            UPDATE
+                sessions
+            SET
+                revoked_at = now()
+            WHERE
+                id = $1
+        `
+		if _, err := tx.Exec(ctx, sql, session.ID); err != nil {
+			return nil, fmt.Errorf("[RotateSession] revoke reused: %w", err)
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return nil, fmt.Errorf("[RotateSession] commit revoke: %w", err)
+		}
+		return nil, appErrors.ErrRefreshTokenReused
+	}
+
+	sql = `
+        UPDATE
+            sessions
+        SET
+            refresh_token_id = $2,
+            refreshed_at = now(),
+            expires_at = now() + make_interval(secs => $3)
+        WHERE
+            id = $1
+        RETURNING refresh_token_id, created_at, refreshed_at, expires_at
+    `
+	err = tx.QueryRow(ctx, sql, rotate.ID, rotate.NewRefreshTokenID, rotate.ExpiresIn.Seconds()).
+		Scan(&session.RefreshTokenID, &session.CreatedAt, &session.RefreshedAt, &session.ExpiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("[RotateSession] update: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("[RotateSession] commit: %w", err)
+	}
+
+	return &session, nil
+}
+
+// RevokeSession завершает сессию пользователя. Чужую или уже завершенную сессию найти нельзя.
+func (r *UserRepository) RevokeSession(ctx context.Context, sessionID uuid.UUID, userID int) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.RevokeSession")
+	defer span.Finish()
+
+	sql := `
+        UPDATE
+            sessions
+        SET
+            revoked_at = now()
+        WHERE
+            id = $1 AND user_id = $2 AND revoked_at IS NULL
+    `
+	tag, err := r.conn.Exec(ctx, sql, sessionID, userID)
+	if err != nil {
+		return fmt.Errorf("[RevokeSession] update: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return appErrors.ErrSessionNotFound
+	}
+
+	return nil
+}
+
+// RevokeUserSessions завершает все сессии пользователя
+func (r *UserRepository) RevokeUserSessions(ctx context.Context, userID int) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.RevokeUserSessions")
+	defer span.Finish()
+
+	sql := `
+        UPDATE
+            sessions
+        SET
+            revoked_at = now()
+        WHERE
+            user_id = $1 AND revoked_at IS NULL
+    `
+	if _, err := r.conn.Exec(ctx, sql, userID); err != nil {
+		return fmt.Errorf("[RevokeUserSessions] update: %w", err)
+	}
+
+	return nil
+}
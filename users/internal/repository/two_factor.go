@@ -0,0 +1,316 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v4"
+	"github.com/opentracing/opentracing-go"
+	"time"
+	appErrors "users/internal/app_errors"
+	"users/internal/models"
+)
+
+func (r *UserRepository) GetTwoFactor(ctx context.Context, userID int) (*models.TwoFactorDAO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.GetTwoFactor")
+	defer span.Finish()
+
+	var twoFactor models.TwoFactorDAO
+	sql := `
+        SELECT
+            user_id,
+            secret,
+            confirmed_at,
+            last_used_step
+        FROM
+            two_factor
+        WHERE
+            user_id = $1
+    `
+	err := r.conn.QueryRow(ctx, sql, userID).
+		Scan(&twoFactor.UserID, &twoFactor.Secret, &twoFactor.ConfirmedAt, &twoFactor.LastUsedStep)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, appErrors.ErrTwoFactorNotEnabled
+		}
+		return nil, fmt.Errorf("[GetTwoFactor] select: %w", err)
+	}
+
+	return &twoFactor, nil
+}
+
+// SaveTwoFactorSecret сохраняет новый неподтвержденный секрет. Подтвержденный секрет не перезаписывается.
+func (r *UserRepository) SaveTwoFactorSecret(ctx context.Context, userID int, secret string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.SaveTwoFactorSecret")
+	defer span.Finish()
+
+	sql := `
+        INSERT INTO
+			two_factor (
+			   user_id,
+			   secret
+			)
+        VALUES
+			($1, $2)
+        ON CONFLICT (user_id) DO UPDATE SET
+            secret = EXCLUDED.secret,
+            last_used_step = 0,
+            created_at = now()
+        WHERE
+            two_factor.confirmed_at IS NULL
+    `
+	tag, err := r.conn.Exec(ctx, sql, userID, secret)
+	if err != nil {
+		return fmt.Errorf("[SaveTwoFactorSecret] upsert: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return appErrors.ErrTwoFactorAlreadyEnabled
+	}
+
+	return nil
+}
+
+// ConfirmTwoFactor включает второй фактор и сохраняет хэши кодов восстановления. step - шаг кода,
+// которым подтверждено подключение, он больше не принимается.
+func (r *UserRepository) ConfirmTwoFactor(ctx context.Context, userID int, step int64, codeHashes []string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.ConfirmTwoFactor")
+	defer span.Finish()
+
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("[ConfirmTwoFactor] begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sql := `
+        UPDATE
+            two_factor
+        SET
+            confirmed_at = now(),
+            last_used_step = $2
+        WHERE
+            user_id = $1 AND confirmed_at IS NULL AND last_used_step < $2
+    `
+	tag, err := tx.Exec(ctx, sql, userID, step)
+	if err != nil {
+		return fmt.Errorf("[ConfirmTwoFactor] update: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return appErrors.ErrInvalidTwoFactorCode
+	}
+
+	if err := replaceRecoveryCodes(ctx, tx, userID, codeHashes); err != nil {
+		return fmt.Errorf("[ConfirmTwoFactor] %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("[ConfirmTwoFactor] commit: %w", err)
+	}
+
+	return nil
+}
+
+// UseTwoFactorStep отмечает шаг кода использованным. Код того же или более раннего шага
+// после этого не принимается, поэтому подсмотренный код нельзя повторить.
+func (r *UserRepository) UseTwoFactorStep(ctx context.Context, userID int, step int64) (bool, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.UseTwoFactorStep")
+	defer span.Finish()
+
+	sql := `
+        UPDATE
+            two_factor
+        SET
+            last_used_step = $2
+        WHERE
+            user_id = $1 AND last_used_step < $2
+    `
+	tag, err := r.conn.Exec(ctx, sql, userID, step)
+	if err != nil {
+		return false, fmt.Errorf("[UseTwoFactorStep] update: %w", err)
+	}
+
+	return tag.RowsAffected() == 1, nil
+}
+
+// UseRecoveryCode гасит код восстановления, если он есть среди неиспользованных кодов пользователя
+func (r *UserRepository) UseRecoveryCode(ctx context.Context, userID int, codeHash string) (bool, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.UseRecoveryCode")
+	defer span.Finish()
+
+	sql := `
+        UPDATE
+            recovery_codes
+        SET
+            used_at = now()
+        WHERE
+            user_id = $1 AND code_hash = $2 AND used_at IS NULL
+    `
+	tag, err := r.conn.Exec(ctx, sql, userID, codeHash)
+	if err != nil {
+		return false, fmt.Errorf("[UseRecoveryCode] update: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// ReplaceRecoveryCodes заменяет все коды восстановления пользователя новыми
+func (r *UserRepository) ReplaceRecoveryCodes(ctx context.Context, userID int, codeHashes []string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.ReplaceRecoveryCodes")
+	defer span.Finish()
+
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("[ReplaceRecoveryCodes] begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := replaceRecoveryCodes(ctx, tx, userID, codeHashes); err != nil {
+		return fmt.Errorf("[ReplaceRecoveryCodes] %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("[ReplaceRecoveryCodes] commit: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteTwoFactor отключает второй фактор вместе с кодами восстановления
+func (r *UserRepository) DeleteTwoFactor(ctx context.Context, userID int) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.DeleteTwoFactor")
+	defer span.Finish()
+
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("[DeleteTwoFactor] begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("[DeleteTwoFactor] delete codes: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM two_factor WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("[DeleteTwoFactor] delete: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("[DeleteTwoFactor] commit: %w", err)
+	}
+
+	return nil
+}
+
+func replaceRecoveryCodes(ctx context.Context, tx pgx.Tx, userID int, codeHashes []string) error {
+	if _, err := tx.Exec(ctx, `DELETE FROM recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("delete recovery codes: %w", err)
+	}
+
+	sql := `
+        INSERT INTO
+			recovery_codes (
+			   user_id,
+			   code_hash
+			)
+        SELECT
+            $1, unnest($2::VARCHAR[])
+    `
+	if _, err := tx.Exec(ctx, sql, userID, codeHashes); err != nil {
+		return fmt.Errorf("insert recovery codes: %w", err)
+	}
+
+	return nil
+}
+
+func (r *UserRepository) CreateTwoFactorChallenge(ctx context.Context, userID int, tokenHash string, ttl time.Duration) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.CreateTwoFactorChallenge")
+	defer span.Finish()
+
+	sql := `
+        INSERT INTO
+			two_factor_challenges (
+			   user_id,
+			   token_hash,
+			   expires_at
+			)
+        VALUES
+			($1, $2, now() + make_interval(secs => $3))
+    `
+	if _, err := r.conn.Exec(ctx, sql, userID, tokenHash, ttl.Seconds()); err != nil {
+		return fmt.Errorf("[CreateTwoFactorChallenge] insert: %w", err)
+	}
+
+	return nil
+}
+
+// GetTwoFactorChallenge возвращает пользователя, который прошел проверку пароля и получил этот токен.
+// Истекший, использованный токен и токен с исчерпанными попытками не принимаются.
+func (r *UserRepository) GetTwoFactorChallenge(ctx context.Context, tokenHash string, maxAttempts int) (int, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.GetTwoFactorChallenge")
+	defer span.Finish()
+
+	var userID int
+	sql := `
+        SELECT
+            user_id
+        FROM
+            two_factor_challenges
+        WHERE
+            token_hash = $1 AND consumed_at IS NULL AND expires_at > now() AND attempts < $2
+    `
+	err := r.conn.QueryRow(ctx, sql, tokenHash, maxAttempts).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, appErrors.ErrInvalidTwoFactorChallenge
+		}
+		return 0, fmt.Errorf("[GetTwoFactorChallenge] select: %w", err)
+	}
+
+	return userID, nil
+}
+
+// FailTwoFactorChallenge учитывает неверный код, введенный для токена
+func (r *UserRepository) FailTwoFactorChallenge(ctx context.Context, tokenHash string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.FailTwoFactorChallenge")
+	defer span.Finish()
+
+	sql := `
+        UPDATE
+            two_factor_challenges
+        SET
+            attempts = attempts + 1
+        WHERE
+            token_hash = $1
+    `
+	if _, err := r.conn.Exec(ctx, sql, tokenHash); err != nil {
+		return fmt.Errorf("[FailTwoFactorChallenge] update: %w", err)
+	}
+
+	return nil
+}
+
+// ConsumeTwoFactorChallenge гасит токен после успешного второго шага
+func (r *UserRepository) ConsumeTwoFactorChallenge(ctx context.Context, tokenHash string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.ConsumeTwoFactorChallenge")
+	defer span.Finish()
+
+	sql := `
+        UPDATE
+            two_factor_challenges
+        SET
+            consumed_at = now()
+        WHERE
+            token_hash = $1 AND consumed_at IS NULL
+    `
+	tag, err := r.conn.Exec(ctx, sql, tokenHash)
+	if err != nil {
+		return fmt.Errorf("[ConsumeTwoFactorChallenge] update: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return appErrors.ErrInvalidTwoFactorChallenge
+	}
+
+	return nil
+}
@@ -0,0 +1,399 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/opentracing/opentracing-go"
+	"time"
+	appErrors "users/internal/app_errors"
+	"users/internal/models"
+)
+
+// StartUserDeletion одной транзакцией скрывает пользователя, завершает его сессии и записывает удаление
+// с шагом pending для каждого сервиса-участника. Если удаление пользователя уже идет, возвращает ErrUserDeletionInProgress
+func (r *UserRepository) StartUserDeletion(ctx context.Context, deletion *models.UserDeletionDAO) (*models.UserDeletionDAO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.StartUserDeletion")
+	defer span.Finish()
+
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("[StartUserDeletion] begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sql := `
+        UPDATE
+            users
+        SET
+            deleted_at = now()
+        WHERE
+            id = $1 AND deleted_at IS NULL
+    `
+	tag, err := tx.Exec(ctx, sql, deletion.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("[StartUserDeletion] hide user: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		var exists bool
+		sql = `
+            SELECT EXISTS (
+                SELECT 1
+                FROM
+                    users
+                WHERE
+                    id = $1
+            )
+        `
+		if err := tx.QueryRow(ctx, sql, deletion.UserID).Scan(&exists); err != nil {
+			return nil, fmt.Errorf("[StartUserDeletion] check user: %w", err)
+		}
+		if exists {
+			return nil, appErrors.ErrUserDeletionInProgress
+		}
+		return nil, appErrors.ErrNotFound
+	}
+
+	sql = `
+        UPDATE
+            sessions
+        SET
+            revoked_at = now()
+        WHERE
+            user_id = $1 AND revoked_at IS NULL
+    `
+	if _, err := tx.Exec(ctx, sql, deletion.UserID); err != nil {
+		return nil, fmt.Errorf("[StartUserDeletion] revoke sessions: %w", err)
+	}
+
+	sql = `
+        INSERT INTO
+			user_deletions (
+			   id,
+			   user_id,
+			   status
+			)
+        VALUES
+			($1, $2, $3)
+        RETURNING created_at, updated_at
+    `
+	err = tx.QueryRow(ctx, sql, deletion.ID, deletion.UserID, models.UserDeletionStatusPending).
+		Scan(&deletion.CreatedAt, &deletion.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("[StartUserDeletion] insert deletion: %w", err)
+	}
+	deletion.Status = models.UserDeletionStatusPending
+
+	for i := range deletion.Steps {
+		sql = `
+            INSERT INTO
+				user_deletion_steps (deletion_id, service, status)
+            VALUES
+				($1, $2, $3)
+            RETURNING updated_at
+        `
+		err = tx.QueryRow(ctx, sql, deletion.ID, deletion.Steps[i].Service, models.UserDeletionStepPending).
+			Scan(&deletion.Steps[i].UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("[StartUserDeletion] insert step: %w", err)
+		}
+		deletion.Steps[i].Status = models.UserDeletionStepPending
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("[StartUserDeletion] commit: %w", err)
+	}
+
+	return deletion, nil
+}
+
+// GetUserDeletion возвращает последнее удаление пользователя вместе с шагами
+func (r *UserRepository) GetUserDeletion(ctx context.Context, userID int) (*models.UserDeletionDAO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.GetUserDeletion")
+	defer span.Finish()
+
+	var deletion models.UserDeletionDAO
+	sql := `
+        SELECT
+            id,
+            user_id,
+            status,
+            error,
+            created_at,
+            updated_at
+        FROM
+            user_deletions
+        WHERE
+            user_id = $1
+        ORDER BY
+            created_at DESC
+        LIMIT 1
+    `
+	err := r.conn.QueryRow(ctx, sql, userID).
+		Scan(&deletion.ID, &deletion.UserID, &deletion.Status, &deletion.Error, &deletion.CreatedAt, &deletion.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, appErrors.ErrUserDeletionNotFound
+		}
+		return nil, fmt.Errorf("[GetUserDeletion] select: %w", err)
+	}
+
+	deletion.Steps, err = queryUserDeletionSteps(ctx, r.conn, deletion.ID)
+	if err != nil {
+		return nil, fmt.Errorf("[GetUserDeletion] %w", err)
+	}
+
+	return &deletion, nil
+}
+
+// SetUserDeletionStep записывает ответ сервиса и возвращает удаление со всеми шагами. Удаление блокируется
+// до конца транзакции, поэтому одновременные ответы разных сервисов видят шаги друг друга.
+// Повторный ответ на уже записанный шаг ничего не меняет
+func (r *UserRepository) SetUserDeletionStep(ctx context.Context, deletionID uuid.UUID, step *models.UserDeletionStepDAO) (*models.UserDeletionDAO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.SetUserDeletionStep")
+	defer span.Finish()
+
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("[SetUserDeletionStep] begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var deletion models.UserDeletionDAO
+	sql := `
+        SELECT
+            id,
+            user_id,
+            status,
+            error,
+            created_at,
+            updated_at
+        FROM
+            user_deletions
+        WHERE
+            id = $1
+        FOR UPDATE
+    `
+	err = tx.QueryRow(ctx, sql, deletionID).
+		Scan(&deletion.ID, &deletion.UserID, &deletion.Status, &deletion.Error, &deletion.CreatedAt, &deletion.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, appErrors.ErrUserDeletionNotFound
+		}
+		return nil, fmt.Errorf("[SetUserDeletionStep] select deletion: %w", err)
+	}
+
+	sql = `
+        UPDATE
+            user_deletion_steps
+        SET
+            status = $3,
+            error = $4,
+            updated_at = now()
+        WHERE
+            deletion_id = $1 AND service = $2 AND status = $5
+    `
+	_, err = tx.Exec(ctx, sql, deletionID, step.Service, step.Status, step.Error, models.UserDeletionStepPending)
+	if err != nil {
+		return nil, fmt.Errorf("[SetUserDeletionStep] update step: %w", err)
+	}
+
+	deletion.Steps, err = queryUserDeletionSteps(ctx, tx, deletionID)
+	if err != nil {
+		return nil, fmt.Errorf("[SetUserDeletionStep] %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("[SetUserDeletionStep] commit: %w", err)
+	}
+
+	return &deletion, nil
+}
+
+// CompleteUserDeletion удаляет пользователя окончательно и отмечает удаление завершенным. Возвращает ключ аватара,
+// чтобы сервис удалил файлы. Если удаление уже завершено или отменено, ничего не делает
+func (r *UserRepository) CompleteUserDeletion(ctx context.Context, deletionID uuid.UUID) (string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.CompleteUserDeletion")
+	defer span.Finish()
+
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return "", fmt.Errorf("[CompleteUserDeletion] begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var userID int
+	sql := `
+        UPDATE
+            user_deletions
+        SET
+            status = $2,
+            updated_at = now()
+        WHERE
+            id = $1 AND status = $3
+        RETURNING user_id
+    `
+	err = tx.QueryRow(ctx, sql, deletionID, models.UserDeletionStatusCompleted, models.UserDeletionStatusPending).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", nil
+		}
+		return "", fmt.Errorf("[CompleteUserDeletion] update deletion: %w", err)
+	}
+
+	// остальные данные пользователя удаляются каскадом
+	var avatarKey string
+	sql = `
+        DELETE FROM
+            users
+        WHERE
+            id = $1
+        RETURNING avatar_key
+    `
+	err = tx.QueryRow(ctx, sql, userID).Scan(&avatarKey)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return "", fmt.Errorf("[CompleteUserDeletion] delete user: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return "", fmt.Errorf("[CompleteUserDeletion] commit: %w", err)
+	}
+
+	return avatarKey, nil
+}
+
+// CancelUserDeletion отменяет удаление: пользователь снова виден и может войти, удаление отмечается failed
+// с причиной. Если удаление уже завершено или отменено, ничего не делает
+func (r *UserRepository) CancelUserDeletion(ctx context.Context, deletionID uuid.UUID, reason string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.CancelUserDeletion")
+	defer span.Finish()
+
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("[CancelUserDeletion] begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var userID int
+	sql := `
+        UPDATE
+            user_deletions
+        SET
+            status = $2,
+            error = $3,
+            updated_at = now()
+        WHERE
+            id = $1 AND status = $4
+        RETURNING user_id
+    `
+	err = tx.QueryRow(ctx, sql, deletionID, models.UserDeletionStatusFailed, reason, models.UserDeletionStatusPending).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return fmt.Errorf("[CancelUserDeletion] update deletion: %w", err)
+	}
+
+	sql = `
+        UPDATE
+            users
+        SET
+            deleted_at = NULL
+        WHERE
+            id = $1
+    `
+	if _, err := tx.Exec(ctx, sql, userID); err != nil {
+		return fmt.Errorf("[CancelUserDeletion] restore user: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("[CancelUserDeletion] commit: %w", err)
+	}
+
+	return nil
+}
+
+// GetStaleUserDeletions возвращает удаления, которые ждут ответов сервисов-участников дольше timeout
+func (r *UserRepository) GetStaleUserDeletions(ctx context.Context, timeout time.Duration) ([]models.UserDeletionDAO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.GetStaleUserDeletions")
+	defer span.Finish()
+
+	sql := `
+        SELECT
+            id,
+            user_id,
+            status,
+            error,
+            created_at,
+            updated_at
+        FROM
+            user_deletions
+        WHERE
+            status = $1 AND created_at < now() - make_interval(secs => $2)
+        ORDER BY
+            created_at
+    `
+	rows, err := r.conn.Query(ctx, sql, models.UserDeletionStatusPending, timeout.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("[GetStaleUserDeletions] select: %w", err)
+	}
+	defer rows.Close()
+
+	deletions := make([]models.UserDeletionDAO, 0)
+	for rows.Next() {
+		var deletion models.UserDeletionDAO
+		err := rows.Scan(&deletion.ID, &deletion.UserID, &deletion.Status, &deletion.Error, &deletion.CreatedAt, &deletion.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("[GetStaleUserDeletions] scan: %w", err)
+		}
+		deletions = append(deletions, deletion)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("[GetStaleUserDeletions] rows: %w", err)
+	}
+
+	return deletions, nil
+}
+
+// stepsQuerier - пул соединений или транзакция, шаги читаются и внутри транзакции, и вне ее
+type stepsQuerier interface {
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+}
+
+func queryUserDeletionSteps(ctx context.Context, q stepsQuerier, deletionID uuid.UUID) ([]models.UserDeletionStepDAO, error) {
+	sql := `
+        SELECT
+            service,
+            status,
+            error,
+            updated_at
+        FROM
+            user_deletion_steps
+        WHERE
+            deletion_id = $1
+        ORDER BY
+            service
+    `
+	rows, err := q.Query(ctx, sql, deletionID)
+	if err != nil {
+		return nil, fmt.Errorf("[queryUserDeletionSteps] select: %w", err)
+	}
+	defer rows.Close()
+
+	steps := make([]models.UserDeletionStepDAO, 0)
+	for rows.Next() {
+		var step models.UserDeletionStepDAO
+		if err := rows.Scan(&step.Service, &step.Status, &step.Error, &step.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("[queryUserDeletionSteps] scan: %w", err)
+		}
+		steps = append(steps, step)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("[queryUserDeletionSteps] rows: %w", err)
+	}
+
+	return steps, nil
+}
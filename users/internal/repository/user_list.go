@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/opentracing/opentracing-go"
+	"strings"
+	"users/internal/models"
+)
+
+// likeEscaper экранирует символы шаблона LIKE, чтобы строка поиска совпадала только буквально
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// ListUsers возвращает страницу пользователей и общее число подходящих пользователей.
+// Поле сортировки уже проверено сервисом, для одинаковых значений порядок задает id
+func (r *UserRepository) ListUsers(ctx context.Context, filter *models.ListUsersDTO, sortColumn string, descending bool) ([]models.UserDAO, int, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.ListUsers")
+	defer span.Finish()
+
+	// пользователи, которые ждут удаления, в список не попадают
+	where := sq.And{sq.Expr("deleted_at IS NULL")}
+	if filter.Search != "" {
+		// lower(...) LIKE 'prefix%' использует индексы text_pattern_ops по lower(username) и lower(email)
+		pattern := likeEscaper.Replace(strings.ToLower(filter.Search)) + "%"
+		search := sq.Or{sq.Like{"lower(username)": pattern}}
+		if filter.IncludeEmail {
+			search = append(search, sq.Like{"lower(email)": pattern})
+		}
+		where = append(where, search)
+	}
+
+	var total int
+	sql, args, err := sq.Select("count(*)").From("users").Where(where).PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("[ListUsers] build count: %w", err)
+	}
+	if err := r.conn.QueryRow(ctx, sql, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("[ListUsers] count: %w", err)
+	}
+
+	direction := "ASC"
+	if descending {
+		direction = "DESC"
+	}
+
+	sql, args, err = sq.
+		Select("id", "username", "email", "email_verified", "role", "display_name", "avatar_key").
+		From("users").
+		Where(where).
+		OrderBy(fmt.Sprintf("%s %s", sortColumn, direction), "id "+direction).
+		Limit(uint64(filter.Limit)).
+		Offset(uint64(filter.Offset)).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("[ListUsers] build select: %w", err)
+	}
+
+	rows, err := r.conn.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("[ListUsers] select: %w", err)
+	}
+	defer rows.Close()
+
+	users := make([]models.UserDAO, 0, filter.Limit)
+	for rows.Next() {
+		var user models.UserDAO
+		if err := rows.Scan(&user.ID, &user.Username, &user.Email, &user.EmailVerified, &user.Role, &user.DisplayName, &user.AvatarKey); err != nil {
+			return nil, 0, fmt.Errorf("[ListUsers] scan: %w", err)
+		}
+		users = append(users, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("[ListUsers] rows: %w", err)
+	}
+
+	return users, total, nil
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v4"
+	"github.com/opentracing/opentracing-go"
+	"time"
+	appErrors "users/internal/app_errors"
+)
+
+// CreateVerificationToken сохраняет хэш нового токена подтверждения email. Выданные ранее неиспользованные
+// токены пользователя удаляются, поэтому рабочей остается только ссылка из последнего письма.
+func (r *UserRepository) CreateVerificationToken(ctx context.Context, userID int, tokenHash string, ttl time.Duration) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.CreateVerificationToken")
+	defer span.Finish()
+
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("[CreateVerificationToken] begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sql := `
+        DELETE FROM
+            email_verification_tokens
+        WHERE
+            user_id = $1 AND consumed_at IS NULL
+    `
+	if _, err := tx.Exec(ctx, sql, userID); err != nil {
+		return fmt.Errorf("[CreateVerificationToken] delete previous: %w", err)
+	}
+
+	sql = `
+        INSERT INTO
+			email_verification_tokens (
+			   user_id,
+			   token_hash,
+			   expires_at
+			)
+        VALUES
+			($1, $2, now() + make_interval(secs => $3))
+    `
+	if _, err := tx.Exec(ctx, sql, userID, tokenHash, ttl.Seconds()); err != nil {
+		return fmt.Errorf("[CreateVerificationToken] insert: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("[CreateVerificationToken] commit: %w", err)
+	}
+
+	return nil
+}
+
+// HasRecentVerificationToken проверяет, выдавался ли пользователю токен подтверждения за последний interval
+func (r *UserRepository) HasRecentVerificationToken(ctx context.Context, userID int, interval time.Duration) (bool, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.HasRecentVerificationToken")
+	defer span.Finish()
+
+	var exists bool
+	sql := `
+        SELECT EXISTS (
+            SELECT 1
+            FROM
+                email_verification_tokens
+            WHERE
+                user_id = $1 AND created_at > now() - make_interval(secs => $2)
+        )
+    `
+	err := r.conn.QueryRow(ctx, sql, userID, interval.Seconds()).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("[HasRecentVerificationToken] select: %w", err)
+	}
+
+	return exists, nil
+}
+
+// ConsumeVerificationToken одной транзакцией гасит токен, отмечает email пользователя подтвержденным
+// и удаляет его остальные токены. Просроченный, уже использованный или неизвестный токен не принимается.
+func (r *UserRepository) ConsumeVerificationToken(ctx context.Context, tokenHash string) (int, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "repository.ConsumeVerificationToken")
+	defer span.Finish()
+
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("[ConsumeVerificationToken] begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var userID int
+	sql := `
+        UPDATE
+            email_verification_tokens
+        SET
+            consumed_at = now()
+        WHERE
+            token_hash = $1 AND consumed_at IS NULL AND expires_at > now()
+        RETURNING user_id
+    `
+	err = tx.QueryRow(ctx, sql, tokenHash).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, appErrors.ErrInvalidVerificationToken
+		}
+		return 0, fmt.Errorf("[ConsumeVerificationToken] consume: %w", err)
+	}
+
+	sql = `
+        UPDATE
+            users
+        SET
+            email_verified = true
+        WHERE
+            id = $1
+    `
+	if _, err := tx.Exec(ctx, sql, userID); err != nil {
+		return 0, fmt.Errorf("[ConsumeVerificationToken] verify user: %w", err)
+	}
+
+	sql = `
+        DELETE FROM
+            email_verification_tokens
+        WHERE
+            user_id = $1 AND consumed_at IS NULL
+    `
+	if _, err := tx.Exec(ctx, sql, userID); err != nil {
+		return 0, fmt.Errorf("[ConsumeVerificationToken] delete other: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("[ConsumeVerificationToken] commit: %w", err)
+	}
+
+	return userID, nil
+}
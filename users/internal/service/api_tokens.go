@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go"
+	"strings"
+	"time"
+	"unicode/utf8"
+	appErrors "users/internal/app_errors"
+	"users/internal/models"
+)
+
+const (
+	maxApiTokenNameLength = 64
+	minApiTokenTTL        = time.Hour
+)
+
+// CreateApiToken выпускает персональный токен доступа. Токен возвращается один раз, в базе остается только его хэш.
+func (s *UserService) CreateApiToken(ctx context.Context, request *models.CreateApiTokenDTO) (*models.ApiTokenDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.CreateApiToken")
+	defer span.Finish()
+
+	name := strings.TrimSpace(request.Name)
+	if name == "" || utf8.RuneCountInString(name) > maxApiTokenNameLength {
+		return nil, fmt.Errorf("[CreateApiToken] %w", appErrors.ErrApiTokenNameInvalid)
+	}
+
+	scopes, err := normalizeApiTokenScopes(request.Scopes)
+	if err != nil {
+		return nil, fmt.Errorf("[CreateApiToken] %w", err)
+	}
+
+	expiresIn := request.ExpiresIn
+	if expiresIn == 0 {
+		expiresIn = s.apiTokenConfig.DefaultTTL
+	}
+	if expiresIn < minApiTokenTTL || expiresIn > s.apiTokenConfig.MaxTTL {
+		return nil, fmt.Errorf("[CreateApiToken] %w", appErrors.ErrApiTokenTTLInvalid)
+	}
+
+	secret, err := newSecretToken()
+	if err != nil {
+		return nil, fmt.Errorf("[CreateApiToken] generate token: %w", err)
+	}
+	token := models.ApiTokenPrefix + secret
+
+	createdToken, err := s.userRepo.CreateApiToken(ctx, &models.ApiTokenDAO{
+		ID:        uuid.New(),
+		UserID:    request.UserID,
+		Name:      name,
+		TokenHash: hashSecretToken(token),
+		Scopes:    scopes,
+		ExpiresAt: time.Now().Add(expiresIn).Truncate(time.Second),
+	}, s.apiTokenConfig.MaxActive)
+	if err != nil {
+		return nil, fmt.Errorf("[CreateApiToken] create token: %w", err)
+	}
+
+	response := createdToken.ToDTO()
+	response.Token = token
+
+	return response, nil
+}
+
+func (s *UserService) GetApiTokens(ctx context.Context, userID int) ([]models.ApiTokenDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.GetApiTokens")
+	defer span.Finish()
+
+	storedTokens, err := s.userRepo.GetApiTokens(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("[GetApiTokens] get tokens: %w", err)
+	}
+
+	response := make([]models.ApiTokenDTO, len(storedTokens))
+	for i := range storedTokens {
+		response[i] = *storedTokens[i].ToDTO()
+	}
+
+	return response, nil
+}
+
+func (s *UserService) RevokeApiToken(ctx context.Context, tokenID uuid.UUID, userID int) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.RevokeApiToken")
+	defer span.Finish()
+
+	err := s.userRepo.RevokeApiToken(ctx, tokenID, userID)
+	if err != nil {
+		return fmt.Errorf("[RevokeApiToken] revoke token: %w", err)
+	}
+
+	return nil
+}
+
+// AuthenticateApiToken проверяет токен из запроса. Поддельный, просроченный и отозванный токены
+// неотличимы для клиента и приводят к ErrInvalidApiToken.
+func (s *UserService) AuthenticateApiToken(ctx context.Context, token string) (*models.ApiTokenAuthDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.AuthenticateApiToken")
+	defer span.Finish()
+
+	if !strings.HasPrefix(token, models.ApiTokenPrefix) {
+		return nil, fmt.Errorf("[AuthenticateApiToken] %w", appErrors.ErrInvalidApiToken)
+	}
+
+	storedToken, err := s.userRepo.GetApiTokenByHash(ctx, hashSecretToken(token))
+	if err != nil {
+		return nil, fmt.Errorf("[AuthenticateApiToken] get token: %w", err)
+	}
+
+	if storedToken.RevokedAt != nil || !time.Now().Before(storedToken.ExpiresAt) {
+		return nil, fmt.Errorf("[AuthenticateApiToken] token is no longer active: %w", appErrors.ErrInvalidApiToken)
+	}
+
+	if err := s.userRepo.TouchApiToken(ctx, storedToken.ID, s.apiTokenConfig.LastUsedInterval); err != nil {
+		return nil, fmt.Errorf("[AuthenticateApiToken] touch token: %w", err)
+	}
+
+	storedUser, err := s.userRepo.GetUserByID(ctx, storedToken.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("[AuthenticateApiToken] get user: %w", err)
+	}
+
+	response := &models.ApiTokenAuthDTO{
+		UserID:   storedToken.UserID,
+		TokenID:  storedToken.ID,
+		Scopes:   storedToken.Scopes,
+		Timezone: storedUser.Timezone,
+	}
+
+	// разрешения роли берутся на момент запроса: отобранная у владельца роль сразу перестает действовать и для токена.
+	// Роль, которой нужен второй фактор, без него разрешений не дает
+	if hasScope(storedToken.Scopes, models.ApiTokenScopeAdmin) {
+		setupRequired, err := s.missingRequiredTwoFactor(ctx, storedUser)
+		if err != nil {
+			return nil, fmt.Errorf("[AuthenticateApiToken] %w", err)
+		}
+		if !setupRequired {
+			response.Permissions, err = s.userRepo.GetRolePermissions(ctx, storedUser.Role)
+			if err != nil {
+				return nil, fmt.Errorf("[AuthenticateApiToken] get permissions: %w", err)
+			}
+		}
+	}
+
+	return response, nil
+}
+
+// normalizeApiTokenScopes проверяет области токена и убирает повторы
+func normalizeApiTokenScopes(scopes []string) ([]string, error) {
+	if len(scopes) == 0 {
+		return nil, fmt.Errorf("%w: at least one scope is required", appErrors.ErrUnknownApiTokenScope)
+	}
+
+	result := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.TrimSpace(scope)
+		if !hasScope(models.ApiTokenScopes, scope) {
+			return nil, fmt.Errorf("%w: %q", appErrors.ErrUnknownApiTokenScope, scope)
+		}
+		if !hasScope(result, scope) {
+			result = append(result, scope)
+		}
+	}
+
+	return result, nil
+}
+
+func hasScope(scopes []string, scope string) bool {
+	for _, item := range scopes {
+		if item == scope {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,266 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/opentracing/opentracing-go"
+	"strings"
+	appErrors "users/internal/app_errors"
+	"users/internal/models"
+	"users/pkg/ctxutil"
+)
+
+// profileFileName - файл архива с профилем пользователя из сервиса users
+const profileFileName = "profile.json"
+
+// RequestDataExport начинает выгрузку данных пользователя: профиль берется из сервиса users, а сервисам-участникам
+// уходит событие user_export_requested. Когда все участники пришлют данные, архив собирается и ссылка на него
+// уходит пользователю письмом
+func (s *UserService) RequestDataExport(ctx context.Context, userID int) (*models.DataExportDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.RequestDataExport")
+	defer span.Finish()
+
+	if _, err := s.userRepo.GetUserByID(ctx, userID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("[RequestDataExport] %w", appErrors.ErrNotFound)
+		}
+		return nil, fmt.Errorf("[RequestDataExport] get user: %w", err)
+	}
+
+	recentlyRequested, err := s.userRepo.HasRecentDataExport(ctx, userID, s.exportConfig.RequestInterval)
+	if err != nil {
+		return nil, fmt.Errorf("[RequestDataExport] check last export: %w", err)
+	}
+	if recentlyRequested {
+		return nil, fmt.Errorf("[RequestDataExport] %w", appErrors.ErrDataExportThrottled)
+	}
+
+	export := &models.DataExportDAO{
+		ID:     uuid.New(),
+		UserID: userID,
+		Parts:  make([]models.DataExportPartDAO, 0, len(s.exportConfig.Participants)),
+	}
+	for _, participant := range s.exportConfig.Participants {
+		if participant = strings.TrimSpace(participant); participant != "" {
+			export.Parts = append(export.Parts, models.DataExportPartDAO{Service: participant})
+		}
+	}
+
+	export, err = s.userRepo.StartDataExport(ctx, export)
+	if err != nil {
+		return nil, fmt.Errorf("[RequestDataExport] start export: %w", err)
+	}
+
+	// присылать данные некому, архив собирается сразу
+	if len(export.Parts) == 0 {
+		if err := s.completeDataExport(ctx, export); err != nil {
+			return nil, fmt.Errorf("[RequestDataExport] %w", err)
+		}
+		return s.GetDataExport(ctx, userID)
+	}
+
+	data, err := json.Marshal(models.UserExportRequestedEvent{
+		EventType: models.UserEventTypeUserExportRequested,
+		ExportID:  export.ID,
+		UserID:    userID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("[RequestDataExport] marshal mssg: %w", err)
+	}
+
+	requestID, _ := ctxutil.GetRequestIDFromContext(ctx)
+	if err := s.userExportsProducer.Publish(data, requestID); err != nil {
+		// без события участники не пришлют данные, и выгрузка никогда не завершится
+		if failErr := s.userRepo.FailDataExport(ctx, export.ID, "user_export_requested event was not published"); failErr != nil {
+			return nil, fmt.Errorf("[RequestDataExport] fail export: %w", failErr)
+		}
+		return nil, fmt.Errorf("[RequestDataExport] publish mssg: %w", err)
+	}
+
+	return export.ToDTO(), nil
+}
+
+// GetDataExport возвращает состояние последней выгрузки данных пользователя
+func (s *UserService) GetDataExport(ctx context.Context, userID int) (*models.DataExportDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.GetDataExport")
+	defer span.Finish()
+
+	export, err := s.userRepo.GetDataExport(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("[GetDataExport] get export: %w", err)
+	}
+
+	return export.ToDTO(), nil
+}
+
+// HandleDataExportReply записывает данные, присланные сервисом-участником. Когда прислали все участники,
+// архив собирается и ссылка на него уходит пользователю. Если хотя бы один отказал, выгрузка не удается
+func (s *UserService) HandleDataExportReply(ctx context.Context, reply *models.UserExportReply) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.HandleDataExportReply")
+	defer span.Finish()
+
+	part := &models.DataExportPartDAO{
+		Service: reply.Service,
+		Error:   reply.Error,
+	}
+	switch reply.EventType {
+	case models.UserEventTypeUserExportData:
+		part.Status = models.DataExportPartReceived
+		part.Data = reply.Data
+	case models.UserEventTypeUserExportFailed:
+		part.Status = models.DataExportPartFailed
+	default:
+		return fmt.Errorf("[HandleDataExportReply] %w: %q", appErrors.ErrUnknownDataExportReply, reply.EventType)
+	}
+
+	export, err := s.userRepo.SetDataExportPart(ctx, reply.ExportID, part)
+	if err != nil {
+		return fmt.Errorf("[HandleDataExportReply] set part: %w", err)
+	}
+
+	// ответ пришел после того, как выгрузка завершилась или не удалась
+	if export.Status != models.DataExportStatusPending {
+		return nil
+	}
+
+	switch export.PartsStatus() {
+	case models.DataExportPartFailed:
+		if err := s.userRepo.FailDataExport(ctx, export.ID, export.FailedPartsError()); err != nil {
+			return fmt.Errorf("[HandleDataExportReply] fail export: %w", err)
+		}
+	case models.DataExportPartReceived:
+		if err := s.completeDataExport(ctx, export); err != nil {
+			return fmt.Errorf("[HandleDataExportReply] %w", err)
+		}
+	}
+
+	return nil
+}
+
+// DownloadDataExport возвращает архив выгрузки по токену из письма. Токен действует, пока не истек срок
+// скачивания, и его можно использовать несколько раз
+func (s *UserService) DownloadDataExport(ctx context.Context, token string) (*models.DataExportArchiveDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.DownloadDataExport")
+	defer span.Finish()
+
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return nil, fmt.Errorf("[DownloadDataExport] %w", appErrors.ErrInvalidDataExportToken)
+	}
+
+	archive, createdAt, err := s.userRepo.GetDataExportArchive(ctx, hashSecretToken(token))
+	if err != nil {
+		return nil, fmt.Errorf("[DownloadDataExport] get archive: %w", err)
+	}
+
+	return &models.DataExportArchiveDTO{
+		FileName: fmt.Sprintf("user-data-%s.zip", createdAt.Format("2006-01-02")),
+		Content:  archive,
+	}, nil
+}
+
+// completeDataExport собирает архив из профиля пользователя и данных сервисов-участников, сохраняет его
+// и отправляет пользователю ссылку для скачивания
+func (s *UserService) completeDataExport(ctx context.Context, export *models.DataExportDAO) error {
+	storedUser, err := s.userRepo.GetUserByID(ctx, export.UserID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			// пользователь удаляется, выгружать его данные уже не нужно
+			if failErr := s.userRepo.FailDataExport(ctx, export.ID, "user is being deleted"); failErr != nil {
+				return fmt.Errorf("[completeDataExport] fail export: %w", failErr)
+			}
+			return nil
+		}
+		return fmt.Errorf("[completeDataExport] get user: %w", err)
+	}
+
+	profile, err := s.userResponse(ctx, storedUser)
+	if err != nil {
+		return fmt.Errorf("[completeDataExport] %w", err)
+	}
+
+	archive, err := buildDataExportArchive(profile, export.Parts)
+	if err != nil {
+		return fmt.Errorf("[completeDataExport] build archive: %w", err)
+	}
+
+	token, err := newSecretToken()
+	if err != nil {
+		return fmt.Errorf("[completeDataExport] generate token: %w", err)
+	}
+
+	completed, err := s.userRepo.CompleteDataExport(ctx, export.ID, archive, hashSecretToken(token), s.exportConfig.TTL)
+	if err != nil {
+		return fmt.Errorf("[completeDataExport] complete export: %w", err)
+	}
+	if !completed {
+		return nil
+	}
+
+	link, err := tokenLink(s.exportConfig.LinkURL, token)
+	if err != nil {
+		return fmt.Errorf("[completeDataExport] build link: %w", err)
+	}
+
+	err = s.publishUserMail(ctx, &models.UserMailItem{
+		UserEventType: models.UserEventTypeDataExportReady,
+		Receivers:     []string{storedUser.Email},
+		Link:          link,
+	})
+	if err != nil {
+		return fmt.Errorf("[completeDataExport] publish mssg: %w", err)
+	}
+
+	return nil
+}
+
+// buildDataExportArchive складывает в zip профиль пользователя и данные каждого сервиса отдельным файлом <service>.json
+func buildDataExportArchive(profile *models.UserDTO, parts []models.DataExportPartDAO) ([]byte, error) {
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+
+	profileData, err := json.MarshalIndent(profile, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshal profile: %w", err)
+	}
+
+	files := []struct {
+		name string
+		data []byte
+	}{{profileFileName, profileData}}
+	for _, part := range parts {
+		var data bytes.Buffer
+		if len(part.Data) == 0 {
+			part.Data = []byte("null")
+		}
+		if err := json.Indent(&data, part.Data, "", "  "); err != nil {
+			return nil, fmt.Errorf("format %s data: %w", part.Service, err)
+		}
+		files = append(files, struct {
+			name string
+			data []byte
+		}{part.Service + ".json", data.Bytes()})
+	}
+
+	for _, file := range files {
+		w, err := archive.Create(file.name)
+		if err != nil {
+			return nil, fmt.Errorf("create %s: %w", file.name, err)
+		}
+		if _, err := w.Write(file.data); err != nil {
+			return nil, fmt.Errorf("write %s: %w", file.name, err)
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return nil, fmt.Errorf("close archive: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v4"
+	"github.com/opentracing/opentracing-go"
+	"net/mail"
+	"strings"
+	appErrors "users/internal/app_errors"
+	"users/internal/models"
+)
+
+// RequestEmailChange начинает смену email. Смена подтверждается текущим паролем, на новый адрес уходит
+// ссылка для подтверждения, на старый - уведомление. Сам email меняется только после перехода по ссылке.
+func (s *UserService) RequestEmailChange(ctx context.Context, request *models.RequestEmailChangeDTO) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.RequestEmailChange")
+	defer span.Finish()
+
+	email := normalizeEmail(request.Email)
+	if address, err := mail.ParseAddress(email); err != nil || address.Address != email {
+		return fmt.Errorf("[RequestEmailChange] %w", appErrors.ErrInvalidEmail)
+	}
+
+	existingUser, err := s.userRepo.GetUserByID(ctx, request.UserID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("[RequestEmailChange] %w", appErrors.ErrNotFound)
+		}
+		return fmt.Errorf("[RequestEmailChange] get user: %w", err)
+	}
+
+	passMatch, err := ComparePassword(ctx, request.Password, existingUser.Password)
+	if err != nil {
+		return fmt.Errorf("[RequestEmailChange] verify pass: %w", err)
+	}
+	if !passMatch {
+		return fmt.Errorf("[RequestEmailChange] verify pass: %w", appErrors.ErrWrongCredentials)
+	}
+
+	if strings.EqualFold(email, existingUser.Email) {
+		return fmt.Errorf("[RequestEmailChange] %w", appErrors.ErrEmailUnchanged)
+	}
+
+	used, err := s.userRepo.IsUsernameOrEmailUsed(ctx, "", email)
+	if err != nil {
+		return fmt.Errorf("[RequestEmailChange] check email: %w", err)
+	}
+	if used {
+		return fmt.Errorf("[RequestEmailChange] %w", appErrors.ErrUsernameOrEmailIsUsed)
+	}
+
+	recentlySent, err := s.userRepo.HasRecentEmailChangeToken(ctx, existingUser.ID, s.emailChangeConfig.RequestInterval)
+	if err != nil {
+		return fmt.Errorf("[RequestEmailChange] check last token: %w", err)
+	}
+	if recentlySent {
+		return fmt.Errorf("[RequestEmailChange] %w", appErrors.ErrEmailChangeThrottled)
+	}
+
+	token, err := newSecretToken()
+	if err != nil {
+		return fmt.Errorf("[RequestEmailChange] generate token: %w", err)
+	}
+
+	err = s.userRepo.CreateEmailChangeToken(ctx, existingUser.ID, email, hashSecretToken(token), s.emailChangeConfig.TokenTTL)
+	if err != nil {
+		return fmt.Errorf("[RequestEmailChange] store token: %w", err)
+	}
+
+	link, err := tokenLink(s.emailChangeConfig.LinkURL, token)
+	if err != nil {
+		return fmt.Errorf("[RequestEmailChange] build link: %w", err)
+	}
+
+	err = s.publishUserMail(ctx, &models.UserMailItem{
+		UserEventType: models.UserEventTypeEmailChange,
+		Receivers:     []string{email},
+		Link:          link,
+	})
+	if err != nil {
+		return fmt.Errorf("[RequestEmailChange] publish confirmation mssg: %w", err)
+	}
+
+	// владелец старого адреса узнает о смене, даже если запрос сделал тот, кто украл его сессию
+	err = s.publishUserMail(ctx, &models.UserMailItem{
+		UserEventType: models.UserEventTypeEmailChangeNotice,
+		Receivers:     []string{existingUser.Email},
+		NewEmail:      email,
+	})
+	if err != nil {
+		return fmt.Errorf("[RequestEmailChange] publish notice mssg: %w", err)
+	}
+
+	return nil
+}
+
+// ConfirmEmailChange записывает новый email по токену из письма. Токен одноразовый.
+func (s *UserService) ConfirmEmailChange(ctx context.Context, token string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.ConfirmEmailChange")
+	defer span.Finish()
+
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return fmt.Errorf("[ConfirmEmailChange] %w", appErrors.ErrInvalidEmailChangeToken)
+	}
+
+	userID, err := s.userRepo.ConsumeEmailChangeToken(ctx, hashSecretToken(token))
+	if err != nil {
+		return fmt.Errorf("[ConfirmEmailChange] consume token: %w", err)
+	}
+	s.publishUserUpdated(ctx, userID)
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v4"
+	"github.com/opentracing/opentracing-go"
+	"strings"
+	appErrors "users/internal/app_errors"
+	"users/internal/models"
+)
+
+// RequestErasure начинает удаление аккаунта по запросу самого пользователя. Запрос подтверждается текущим паролем,
+// на email уходит ссылка для подтверждения, аккаунт удаляется только после перехода по ней
+func (s *UserService) RequestErasure(ctx context.Context, request *models.RequestErasureDTO) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.RequestErasure")
+	defer span.Finish()
+
+	existingUser, err := s.userRepo.GetUserByID(ctx, request.UserID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("[RequestErasure] %w", appErrors.ErrNotFound)
+		}
+		return fmt.Errorf("[RequestErasure] get user: %w", err)
+	}
+
+	passMatch, err := ComparePassword(ctx, request.Password, existingUser.Password)
+	if err != nil {
+		return fmt.Errorf("[RequestErasure] verify pass: %w", err)
+	}
+	if !passMatch {
+		return fmt.Errorf("[RequestErasure] verify pass: %w", appErrors.ErrWrongCredentials)
+	}
+
+	recentlySent, err := s.userRepo.HasRecentErasureToken(ctx, existingUser.ID, s.erasureConfig.RequestInterval)
+	if err != nil {
+		return fmt.Errorf("[RequestErasure] check last token: %w", err)
+	}
+	if recentlySent {
+		return fmt.Errorf("[RequestErasure] %w", appErrors.ErrErasureThrottled)
+	}
+
+	token, err := newSecretToken()
+	if err != nil {
+		return fmt.Errorf("[RequestErasure] generate token: %w", err)
+	}
+
+	err = s.userRepo.CreateErasureToken(ctx, existingUser.ID, hashSecretToken(token), s.erasureConfig.TokenTTL)
+	if err != nil {
+		return fmt.Errorf("[RequestErasure] store token: %w", err)
+	}
+
+	link, err := tokenLink(s.erasureConfig.LinkURL, token)
+	if err != nil {
+		return fmt.Errorf("[RequestErasure] build link: %w", err)
+	}
+
+	err = s.publishUserMail(ctx, &models.UserMailItem{
+		UserEventType: models.UserEventTypeErasure,
+		Receivers:     []string{existingUser.Email},
+		Link:          link,
+	})
+	if err != nil {
+		return fmt.Errorf("[RequestErasure] publish confirmation mssg: %w", err)
+	}
+
+	return nil
+}
+
+// ConfirmErasure удаляет аккаунт по токену из письма. Удаление идет так же, как удаление администратором,
+// но сервисы-участники обезличивают данные пользователя, а не передают их другим. Токен одноразовый
+func (s *UserService) ConfirmErasure(ctx context.Context, token string) (*models.UserDeletionDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.ConfirmErasure")
+	defer span.Finish()
+
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return nil, fmt.Errorf("[ConfirmErasure] %w", appErrors.ErrInvalidErasureToken)
+	}
+
+	userID, err := s.userRepo.ConsumeErasureToken(ctx, hashSecretToken(token))
+	if err != nil {
+		return nil, fmt.Errorf("[ConfirmErasure] consume token: %w", err)
+	}
+
+	deletion, err := s.startUserDeletion(ctx, userID, models.UserDeletionPolicyAnonymize)
+	if err != nil {
+		return nil, fmt.Errorf("[ConfirmErasure] %w", err)
+	}
+
+	return deletion.ToDTO(), nil
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v4"
+	"github.com/opentracing/opentracing-go"
+	"strconv"
+	"strings"
+	"unicode"
+	appErrors "users/internal/app_errors"
+	"users/internal/models"
+)
+
+const (
+	// externalUsernameMaxLength - максимальная длина имени, которое собирается из данных провайдера
+	externalUsernameMaxLength = 32
+	// externalUsernameAttempts - сколько числовых суффиксов перебирается, если имя уже занято
+	externalUsernameAttempts = 20
+)
+
+// LoginExternal выполняет вход пользователя, которого проверил внешний провайдер OpenID Connect.
+// Привязанный ранее пользователь провайдера входит в свой аккаунт. При первом входе он привязывается
+// к аккаунту с тем же подтвержденным email или для него создается новый аккаунт. Пароль в этом
+// случае не проверяется, но второй фактор, если он включен, все равно запрашивается.
+func (s *UserService) LoginExternal(ctx context.Context, login *models.ExternalLoginDTO) (*models.UserDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.LoginExternal")
+	defer span.Finish()
+
+	identity := &models.UserIdentityDAO{
+		Issuer:  login.Issuer,
+		Subject: login.Subject,
+		Email:   normalizeEmail(login.Email),
+	}
+	if identity.Issuer == "" || identity.Subject == "" {
+		return nil, fmt.Errorf("[LoginExternal] %w", appErrors.ErrWrongCredentials)
+	}
+
+	existingUser, err := s.externalUser(ctx, identity, login)
+	if err != nil {
+		return nil, fmt.Errorf("[LoginExternal] %w", err)
+	}
+
+	if err := s.userRepo.TouchUserIdentity(ctx, identity); err != nil {
+		return nil, fmt.Errorf("[LoginExternal] %w", err)
+	}
+
+	// при включенном втором факторе вместо данных пользователя возвращается токен второго шага
+	challenge, err := s.startTwoFactorLogin(ctx, existingUser.ID)
+	if err != nil {
+		return nil, fmt.Errorf("[LoginExternal] %w", err)
+	}
+	if challenge != nil {
+		return challenge, nil
+	}
+
+	userResponse, err := s.userResponse(ctx, existingUser)
+	if err != nil {
+		return nil, fmt.Errorf("[LoginExternal] %w", err)
+	}
+
+	return userResponse, nil
+}
+
+// externalUser находит аккаунт, привязанный к пользователю провайдера, а при первом входе привязывает или создает его
+func (s *UserService) externalUser(ctx context.Context, identity *models.UserIdentityDAO, login *models.ExternalLoginDTO) (*models.UserDAO, error) {
+	linked, err := s.userRepo.GetUserIdentity(ctx, identity.Issuer, identity.Subject)
+	if err == nil {
+		existingUser, err := s.userRepo.GetUserByID(ctx, linked.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("get linked user: %w", err)
+		}
+		return existingUser, nil
+	}
+	if !errors.Is(err, appErrors.ErrIdentityNotFound) {
+		return nil, fmt.Errorf("get identity: %w", err)
+	}
+
+	// без подтвержденного провайдером email нельзя ни найти аккаунт, ни создать его
+	if identity.Email == "" || !login.EmailVerified {
+		return nil, appErrors.ErrExternalEmailNotVerified
+	}
+
+	existingUser, err := s.userRepo.GetUserByUsernameOrEmail(ctx, "", identity.Email)
+	if err == nil {
+		// неподтвержденный адрес мог указать кто угодно: привязка отдала бы аккаунт с чужим паролем владельцу адреса
+		if !existingUser.EmailVerified {
+			return nil, appErrors.ErrExternalEmailConflict
+		}
+
+		identity.UserID = existingUser.ID
+		if err := s.userRepo.CreateUserIdentity(ctx, identity); err != nil {
+			return nil, fmt.Errorf("link identity: %w", err)
+		}
+		return existingUser, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("get user by email: %w", err)
+	}
+
+	return s.createExternalUser(ctx, identity, login)
+}
+
+// createExternalUser создает аккаунт для пользователя провайдера. Пароль аккаунта случайный и никому
+// не известен: войти по паролю можно будет только после сброса пароля через email.
+func (s *UserService) createExternalUser(ctx context.Context, identity *models.UserIdentityDAO, login *models.ExternalLoginDTO) (*models.UserDAO, error) {
+	username, err := s.freeUsername(ctx, externalUsername(login))
+	if err != nil {
+		return nil, err
+	}
+
+	randomPassword, err := newSecretToken()
+	if err != nil {
+		return nil, fmt.Errorf("generate password: %w", err)
+	}
+	hashedPassword, err := GeneratePassword(ctx, s.passConfig, randomPassword)
+	if err != nil {
+		return nil, fmt.Errorf("generate pass: %w", err)
+	}
+
+	newUser := &models.CreateUserDTO{
+		Username: username,
+		Password: hashedPassword,
+		Email:    identity.Email,
+	}
+	userID, err := s.userRepo.CreateExternalUser(ctx, newUser, identity)
+	if err != nil {
+		return nil, fmt.Errorf("store user: %w", err)
+	}
+
+	createdUser, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get created user: %w", err)
+	}
+
+	return createdUser, nil
+}
+
+// freeUsername возвращает base, а если имя занято - base с первым свободным числовым суффиксом
+func (s *UserService) freeUsername(ctx context.Context, base string) (string, error) {
+	for i := 1; i <= externalUsernameAttempts; i++ {
+		candidate := base
+		if i > 1 {
+			suffix := strconv.Itoa(i)
+			candidate = truncate(base, externalUsernameMaxLength-len(suffix)) + suffix
+		}
+
+		_, err := s.userRepo.GetUserByUsername(ctx, candidate)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return candidate, nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("check username: %w", err)
+		}
+	}
+
+	// все простые варианты заняты, добавляем случайный хвост
+	token, err := newSecretToken()
+	if err != nil {
+		return "", fmt.Errorf("generate username: %w", err)
+	}
+
+	return truncate(base, externalUsernameMaxLength-9) + "-" + strings.ToLower(token[:8]), nil
+}
+
+// externalUsername собирает имя пользователя из preferred_username, а если его нет - из email до @
+func externalUsername(login *models.ExternalLoginDTO) string {
+	name := sanitizeUsername(login.PreferredUsername)
+	if name == "" {
+		local, _, _ := strings.Cut(login.Email, "@")
+		name = sanitizeUsername(local)
+	}
+	if name == "" {
+		name = "user"
+	}
+
+	return truncate(name, externalUsernameMaxLength)
+}
+
+// sanitizeUsername оставляет в имени только латинские буквы, цифры, точку, дефис и подчеркивание
+func sanitizeUsername(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '.' || r == '-' || r == '_') {
+			return r
+		}
+		return -1
+	}, name)
+}
+
+func truncate(s string, length int) string {
+	if len(s) > length {
+		return s[:length]
+	}
+	return s
+}
@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"github.com/google/uuid"
+	"time"
 	"users/internal/models"
 )
 
@@ -9,12 +11,117 @@ type UserRepository interface {
 	CreateUser(ctx context.Context, user *models.CreateUserDTO) (int, error)
 	UpdateUser(ctx context.Context, user *models.UserDAO) error
 	UpdatePassword(ctx context.Context, userID int, newPassword string) error
-	DeleteUser(ctx context.Context, userID int) error
+	ReplacePasswordHash(ctx context.Context, userID int, oldHash, newHash string) error
+	GetPasswordHistory(ctx context.Context, userID int, limit int) ([]string, error)
+	PrunePasswordHistory(ctx context.Context, userID int, keep int) error
 	GetUserByID(ctx context.Context, userID int) (*models.UserDAO, error)
+	GetUsersByIDs(ctx context.Context, userIDs []int) ([]models.UserDAO, error)
+	GetUsersByUsernames(ctx context.Context, usernames []string) ([]models.UserDAO, error)
 	GetUserByUsernameOrEmail(ctx context.Context, username, email string) (*models.UserDAO, error)
 	GetUserByUsername(ctx context.Context, username string) (*models.UserDAO, error)
+	IsUsernameOrEmailUsed(ctx context.Context, username, email string) (bool, error)
+	ListUsers(ctx context.Context, filter *models.ListUsersDTO, sortColumn string, descending bool) ([]models.UserDAO, int, error)
+	UpdateAvatarKey(ctx context.Context, userID int, avatarKey string) (string, error)
+
+	StartUserDeletion(ctx context.Context, deletion *models.UserDeletionDAO) (*models.UserDeletionDAO, error)
+	GetUserDeletion(ctx context.Context, userID int) (*models.UserDeletionDAO, error)
+	SetUserDeletionStep(ctx context.Context, deletionID uuid.UUID, step *models.UserDeletionStepDAO) (*models.UserDeletionDAO, error)
+	CompleteUserDeletion(ctx context.Context, deletionID uuid.UUID) (string, error)
+	CancelUserDeletion(ctx context.Context, deletionID uuid.UUID, reason string) error
+	GetStaleUserDeletions(ctx context.Context, timeout time.Duration) ([]models.UserDeletionDAO, error)
+
+	HasRecentDataExport(ctx context.Context, userID int, interval time.Duration) (bool, error)
+	StartDataExport(ctx context.Context, export *models.DataExportDAO) (*models.DataExportDAO, error)
+	GetDataExport(ctx context.Context, userID int) (*models.DataExportDAO, error)
+	SetDataExportPart(ctx context.Context, exportID uuid.UUID, part *models.DataExportPartDAO) (*models.DataExportDAO, error)
+	CompleteDataExport(ctx context.Context, exportID uuid.UUID, archive []byte, tokenHash string, ttl time.Duration) (bool, error)
+	FailDataExport(ctx context.Context, exportID uuid.UUID, reason string) error
+	GetDataExportArchive(ctx context.Context, tokenHash string) ([]byte, time.Time, error)
+
+	CreateErasureToken(ctx context.Context, userID int, tokenHash string, ttl time.Duration) error
+	HasRecentErasureToken(ctx context.Context, userID int, interval time.Duration) (bool, error)
+	ConsumeErasureToken(ctx context.Context, tokenHash string) (int, error)
+
+	CreateVerificationToken(ctx context.Context, userID int, tokenHash string, ttl time.Duration) error
+	HasRecentVerificationToken(ctx context.Context, userID int, interval time.Duration) (bool, error)
+	ConsumeVerificationToken(ctx context.Context, tokenHash string) (int, error)
+
+	CreatePasswordResetToken(ctx context.Context, userID int, tokenHash string, ttl time.Duration) error
+	HasRecentPasswordResetToken(ctx context.Context, userID int, interval time.Duration) (bool, error)
+	GetPasswordResetTokenUser(ctx context.Context, tokenHash string) (int, error)
+	ResetPassword(ctx context.Context, tokenHash, newPassword string) (int, error)
+
+	CreateEmailChangeToken(ctx context.Context, userID int, newEmail, tokenHash string, ttl time.Duration) error
+	HasRecentEmailChangeToken(ctx context.Context, userID int, interval time.Duration) (bool, error)
+	ConsumeEmailChangeToken(ctx context.Context, tokenHash string) (int, error)
+
+	CreateSession(ctx context.Context, session *models.SessionDTO) (*models.SessionDAO, error)
+	RotateSession(ctx context.Context, rotate *models.RotateSessionDTO) (*models.SessionDAO, error)
+	RevokeSession(ctx context.Context, sessionID uuid.UUID, userID int) error
+	RevokeUserSessions(ctx context.Context, userID int) error
+
+	GetTwoFactor(ctx context.Context, userID int) (*models.TwoFactorDAO, error)
+	SaveTwoFactorSecret(ctx context.Context, userID int, secret string) error
+	ConfirmTwoFactor(ctx context.Context, userID int, step int64, codeHashes []string) error
+	UseTwoFactorStep(ctx context.Context, userID int, step int64) (bool, error)
+	UseRecoveryCode(ctx context.Context, userID int, codeHash string) (bool, error)
+	ReplaceRecoveryCodes(ctx context.Context, userID int, codeHashes []string) error
+	DeleteTwoFactor(ctx context.Context, userID int) error
+	CreateTwoFactorChallenge(ctx context.Context, userID int, tokenHash string, ttl time.Duration) error
+	GetTwoFactorChallenge(ctx context.Context, tokenHash string, maxAttempts int) (int, error)
+	FailTwoFactorChallenge(ctx context.Context, tokenHash string) error
+	ConsumeTwoFactorChallenge(ctx context.Context, tokenHash string) error
+
+	GetLoginThrottle(ctx context.Context, kind, key string) (*models.LoginThrottleDAO, error)
+	ReserveLoginAttempt(ctx context.Context, kind, key string, freeAttempts int, window, attemptTimeout time.Duration) (bool, error)
+	ReleaseLoginAttempt(ctx context.Context, kind, key string) error
+	RegisterLoginFailure(ctx context.Context, kind, key string, window time.Duration) (int, error)
+	BlockLogin(ctx context.Context, kind, key string, duration time.Duration, locked bool) error
+	ResetLoginThrottle(ctx context.Context, kind, key string) error
+
+	GetRolePermissions(ctx context.Context, role string) ([]string, error)
+	SetUserRole(ctx context.Context, userID int, role string) error
+
+	GetUserIdentity(ctx context.Context, issuer, subject string) (*models.UserIdentityDAO, error)
+	CreateUserIdentity(ctx context.Context, identity *models.UserIdentityDAO) error
+	CreateExternalUser(ctx context.Context, user *models.CreateUserDTO, identity *models.UserIdentityDAO) (int, error)
+	TouchUserIdentity(ctx context.Context, identity *models.UserIdentityDAO) error
+
+	CreateApiToken(ctx context.Context, token *models.ApiTokenDAO, maxActive int) (*models.ApiTokenDAO, error)
+	GetApiTokens(ctx context.Context, userID int) ([]models.ApiTokenDAO, error)
+	GetApiTokenByHash(ctx context.Context, tokenHash string) (*models.ApiTokenDAO, error)
+	TouchApiToken(ctx context.Context, tokenID uuid.UUID, interval time.Duration) error
+	RevokeApiToken(ctx context.Context, tokenID uuid.UUID, userID int) error
+
+	CreateOrganization(ctx context.Context, name string, userID int) (*models.OrganizationDAO, error)
+	GetUserOrganizations(ctx context.Context, userID int) ([]models.OrganizationDAO, error)
+	GetUserOrganization(ctx context.Context, organizationID, userID int) (*models.OrganizationDAO, error)
+	GetOrganizationMembers(ctx context.Context, organizationID int) ([]models.OrganizationMemberDAO, error)
+	SetMemberRole(ctx context.Context, organizationID, memberID int, role string) error
+	RemoveMember(ctx context.Context, organizationID, memberID int) error
+	CreateInvitation(ctx context.Context, invitation *models.InvitationDAO) (*models.InvitationDAO, error)
+	GetInvitationByHash(ctx context.Context, tokenHash string) (*models.InvitationDAO, error)
+	AcceptInvitation(ctx context.Context, invitationID uuid.UUID, userID int) error
+	CreateTeam(ctx context.Context, organizationID int, name string) (*models.TeamDAO, error)
+	GetTeams(ctx context.Context, organizationID int) ([]models.TeamDAO, error)
+	GetTeam(ctx context.Context, organizationID, teamID int) (*models.TeamDAO, error)
+	GetTeamMembers(ctx context.Context, teamID int) ([]models.OrganizationMemberDAO, error)
+	AddTeamMember(ctx context.Context, organizationID, teamID, memberID int) error
+	RemoveTeamMember(ctx context.Context, teamID, memberID int) error
+	GetUserMemberships(ctx context.Context, userID int) (*models.MembershipsDAO, error)
+
+	GetNotificationPreferences(ctx context.Context, userID int) ([]models.NotificationPreferenceDAO, error)
+	GetEventNotificationPreferences(ctx context.Context, event string, userIDs []int) ([]models.NotificationPreferenceDAO, error)
+	SetNotificationPreferences(ctx context.Context, userID int, preferences []models.NotificationPreferenceDAO) error
 }
 
 type RabbitProducer interface {
 	Publish(data []byte, requestId string) (err error)
 }
+
+// FileStorage - хранилище файлов, в нем лежат аватары. Файлы отдаются клиентам по URL
+type FileStorage interface {
+	Put(ctx context.Context, key string, data []byte) error
+	Delete(ctx context.Context, key string) error
+	URL(key string) string
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v4"
+	"github.com/opentracing/opentracing-go"
+	"strconv"
+	"time"
+	appErrors "users/internal/app_errors"
+	"users/internal/models"
+)
+
+// UnlockUser снимает блокировку входа и забывает неудачные попытки аккаунта
+func (s *UserService) UnlockUser(ctx context.Context, userID int) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.UnlockUser")
+	defer span.Finish()
+
+	if _, err := s.userRepo.GetUserByID(ctx, userID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("[UnlockUser] %w", appErrors.ErrNotFound)
+		}
+		return fmt.Errorf("[UnlockUser] get user:%w", err)
+	}
+
+	err := s.userRepo.ResetLoginThrottle(ctx, models.LoginThrottleKindUser, strconv.Itoa(userID))
+	if err != nil {
+		return fmt.Errorf("[UnlockUser] reset throttle:%w", err)
+	}
+
+	return nil
+}
+
+// reserveLoginAttempt занимает попытку входа по ключу до проверки пароля и возвращает LoginThrottledError,
+// если попытки сейчас запрещены. Проверка и учет попытки - один запрос, поэтому параллельные запросы
+// не проходят проверку раньше, чем записана неудача первого из них
+func (s *UserService) reserveLoginAttempt(ctx context.Context, kind, key string, freeAttempts int) error {
+	if key == "" {
+		return nil
+	}
+
+	reserved, err := s.userRepo.ReserveLoginAttempt(ctx, kind, key, freeAttempts, s.throttleConfig.Window, s.throttleConfig.AttemptTimeout)
+	if err != nil {
+		return fmt.Errorf("reserve attempt: %w", err)
+	}
+	if reserved {
+		return nil
+	}
+
+	throttle, err := s.userRepo.GetLoginThrottle(ctx, kind, key)
+	if err != nil {
+		return fmt.Errorf("check throttle: %w", err)
+	}
+
+	// без блокировки попытку не дают другие, еще не завершенные попытки
+	if throttle.RetryAfter <= 0 {
+		return &appErrors.LoginThrottledError{
+			Err:        appErrors.ErrLoginThrottled,
+			RetryAfter: s.throttleConfig.BaseDelay,
+		}
+	}
+
+	reason := appErrors.ErrLoginThrottled
+	if throttle.Locked {
+		reason = appErrors.ErrAccountLocked
+	}
+
+	return &appErrors.LoginThrottledError{
+		Err:        reason,
+		RetryAfter: throttle.RetryAfter,
+	}
+}
+
+// releaseLoginAttempt освобождает попытку после того, как ее результат учтен. Ошибка не мешает входу:
+// неосвобожденная попытка перестанет учитываться через AttemptTimeout
+func (s *UserService) releaseLoginAttempt(ctx context.Context, kind, key string) {
+	if key == "" {
+		return
+	}
+
+	_ = s.userRepo.ReleaseLoginAttempt(ctx, kind, key)
+}
+
+// registerUserLoginFailure учитывает неверный пароль аккаунта: откладывает следующую попытку,
+// а после LockoutThreshold неудач блокирует аккаунт и сообщает об этом владельцу
+func (s *UserService) registerUserLoginFailure(ctx context.Context, user *models.UserDAO) error {
+	key := strconv.Itoa(user.ID)
+	failures, err := s.userRepo.RegisterLoginFailure(ctx, models.LoginThrottleKindUser, key, s.throttleConfig.Window)
+	if err != nil {
+		return fmt.Errorf("register failure: %w", err)
+	}
+
+	if failures < s.throttleConfig.LockoutThreshold {
+		return s.delayLogin(ctx, models.LoginThrottleKindUser, key, failures, s.throttleConfig.FreeAttempts)
+	}
+
+	err = s.userRepo.BlockLogin(ctx, models.LoginThrottleKindUser, key, s.throttleConfig.LockoutDuration, true)
+	if err != nil {
+		return fmt.Errorf("lock account: %w", err)
+	}
+
+	err = s.publishUserMail(ctx, &models.UserMailItem{
+		UserEventType: models.UserEventTypeAccountLocked,
+		Receivers:     []string{user.Email},
+	})
+	if err != nil {
+		return fmt.Errorf("notify lockout: %w", err)
+	}
+
+	return nil
+}
+
+// registerIPLoginFailure учитывает неудачный вход с адреса клиента
+func (s *UserService) registerIPLoginFailure(ctx context.Context, ip string) error {
+	if ip == "" {
+		return nil
+	}
+
+	failures, err := s.userRepo.RegisterLoginFailure(ctx, models.LoginThrottleKindIP, ip, s.throttleConfig.Window)
+	if err != nil {
+		return fmt.Errorf("register failure: %w", err)
+	}
+
+	return s.delayLogin(ctx, models.LoginThrottleKindIP, ip, failures, s.throttleConfig.IPFreeAttempts)
+}
+
+func (s *UserService) delayLogin(ctx context.Context, kind, key string, failures, freeAttempts int) error {
+	delay := loginDelay(failures-freeAttempts, s.throttleConfig.BaseDelay, s.throttleConfig.MaxDelay)
+	if delay <= 0 {
+		return nil
+	}
+
+	if err := s.userRepo.BlockLogin(ctx, kind, key, delay, false); err != nil {
+		return fmt.Errorf("delay login: %w", err)
+	}
+
+	return nil
+}
+
+// loginDelay - задержка после excess неудач сверх бесплатных: base, 2*base, 4*base... но не больше max
+func loginDelay(excess int, base, max time.Duration) time.Duration {
+	if excess <= 0 {
+		return 0
+	}
+
+	delay := base
+	for i := 1; i < excess && delay < max; i++ {
+		delay *= 2
+	}
+
+	if delay > max {
+		return max
+	}
+
+	return delay
+}
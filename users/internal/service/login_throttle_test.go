@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+	"users/config"
+	appErrors "users/internal/app_errors"
+	"users/internal/models"
+)
+
+// throttleRepo записывает обращения к счетчикам входа по порядку. Остальные методы не вызываются
+type throttleRepo struct {
+	UserRepository
+	user     *models.UserDAO
+	refused  map[string]bool
+	throttle models.LoginThrottleDAO
+	calls    []string
+}
+
+func (r *throttleRepo) ReserveLoginAttempt(_ context.Context, kind, _ string, _ int, _, _ time.Duration) (bool, error) {
+	r.calls = append(r.calls, "reserve "+kind)
+	return !r.refused[kind], nil
+}
+
+func (r *throttleRepo) ReleaseLoginAttempt(_ context.Context, kind, _ string) error {
+	r.calls = append(r.calls, "release "+kind)
+	return nil
+}
+
+func (r *throttleRepo) GetLoginThrottle(_ context.Context, _, _ string) (*models.LoginThrottleDAO, error) {
+	throttle := r.throttle
+	return &throttle, nil
+}
+
+func (r *throttleRepo) RegisterLoginFailure(_ context.Context, kind, _ string, _ time.Duration) (int, error) {
+	r.calls = append(r.calls, "failure "+kind)
+	return 1, nil
+}
+
+func (r *throttleRepo) GetUserByUsernameOrEmail(_ context.Context, _, _ string) (*models.UserDAO, error) {
+	r.calls = append(r.calls, "get user")
+	return r.user, nil
+}
+
+func TestLoginReservesAttempts(t *testing.T) {
+	ctx := context.Background()
+	passConfig := &config.PasswordConfig{Time: 1, Memory: 1024, Threads: 1, KeyLen: 32}
+	hash, err := GeneratePassword(ctx, passConfig, "Correct-Pass1")
+	if err != nil {
+		t.Fatalf("GeneratePassword error: %v", err)
+	}
+
+	throttleConfig := &config.LoginThrottleConfig{
+		FreeAttempts:     3,
+		BaseDelay:        time.Second,
+		MaxDelay:         time.Minute,
+		Window:           time.Hour,
+		LockoutThreshold: 10,
+		LockoutDuration:  time.Hour,
+		IPFreeAttempts:   20,
+		AttemptTimeout:   time.Minute,
+	}
+
+	const (
+		ip   = models.LoginThrottleKindIP
+		user = models.LoginThrottleKindUser
+	)
+
+	tests := []struct {
+		name           string
+		refused        map[string]bool
+		throttle       models.LoginThrottleDAO
+		wantErr        error
+		wantRetryAfter time.Duration
+		wantCalls      []string
+	}{
+		{
+			name:      "wrong password is counted before the attempts are released",
+			wantErr:   appErrors.ErrWrongCredentials,
+			wantCalls: []string{"reserve " + ip, "get user", "reserve " + user, "failure " + user, "failure " + ip, "release " + user, "release " + ip},
+		},
+		{
+			name:           "address is blocked",
+			refused:        map[string]bool{ip: true},
+			throttle:       models.LoginThrottleDAO{RetryAfter: 30 * time.Second},
+			wantErr:        appErrors.ErrLoginThrottled,
+			wantRetryAfter: 30 * time.Second,
+			wantCalls:      []string{"reserve " + ip},
+		},
+		{
+			name:           "account is locked",
+			refused:        map[string]bool{user: true},
+			throttle:       models.LoginThrottleDAO{Locked: true, RetryAfter: time.Hour},
+			wantErr:        appErrors.ErrAccountLocked,
+			wantRetryAfter: time.Hour,
+			wantCalls:      []string{"reserve " + ip, "get user", "reserve " + user, "release " + ip},
+		},
+		{
+			name:           "other attempts are still in flight",
+			refused:        map[string]bool{user: true},
+			wantErr:        appErrors.ErrLoginThrottled,
+			wantRetryAfter: time.Second,
+			wantCalls:      []string{"reserve " + ip, "get user", "reserve " + user, "release " + ip},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &throttleRepo{
+				user:     &models.UserDAO{ID: 1, Username: "alice", Email: "alice@example.com", Password: hash},
+				refused:  tt.refused,
+				throttle: tt.throttle,
+			}
+			s := &UserService{passConfig: passConfig, throttleConfig: throttleConfig, userRepo: repo}
+
+			_, err := s.Login(ctx, &models.UserLoginDTO{Username: "alice", Password: "Wrong-Pass1", ClientIP: "203.0.113.7"})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Login error = %v, want %v", err, tt.wantErr)
+			}
+
+			var throttled *appErrors.LoginThrottledError
+			if errors.As(err, &throttled) != (tt.wantRetryAfter > 0) {
+				t.Fatalf("Login error = %v, throttled = %v", err, tt.wantRetryAfter > 0)
+			}
+			if throttled != nil && throttled.RetryAfter != tt.wantRetryAfter {
+				t.Errorf("RetryAfter = %s, want %s", throttled.RetryAfter, tt.wantRetryAfter)
+			}
+			if !reflect.DeepEqual(repo.calls, tt.wantCalls) {
+				t.Errorf("calls = %v, want %v", repo.calls, tt.wantCalls)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/opentracing/opentracing-go"
+	"strings"
+	appErrors "users/internal/app_errors"
+	"users/internal/models"
+)
+
+// GetNotificationPreferences возвращает настройки пользователя по всем событиям. Для событий,
+// которые пользователь не настраивал, возвращаются все каналы
+func (s *UserService) GetNotificationPreferences(ctx context.Context, userID int) (*models.NotificationPreferencesDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.GetNotificationPreferences")
+	defer span.Finish()
+
+	stored, err := s.userRepo.GetNotificationPreferences(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("[GetNotificationPreferences] get preferences: %w", err)
+	}
+
+	channels := make(map[string][]string, len(stored))
+	for _, preference := range stored {
+		channels[preference.Event] = preference.Channels
+	}
+
+	response := &models.NotificationPreferencesDTO{
+		UserID: userID,
+		Items:  make([]models.NotificationPreferenceDTO, len(models.NotificationEvents)),
+	}
+	for i, event := range models.NotificationEvents {
+		eventChannels, ok := channels[event]
+		if !ok {
+			eventChannels = models.NotificationChannels
+		}
+		response.Items[i] = models.NotificationPreferenceDTO{
+			Event:    event,
+			Channels: eventChannels,
+		}
+	}
+
+	return response, nil
+}
+
+// UpdateNotificationPreferences сохраняет каналы для переданных событий и возвращает настройки целиком
+func (s *UserService) UpdateNotificationPreferences(ctx context.Context, request *models.NotificationPreferencesDTO) (*models.NotificationPreferencesDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.UpdateNotificationPreferences")
+	defer span.Finish()
+
+	preferences := make([]models.NotificationPreferenceDAO, 0, len(request.Items))
+	for _, item := range request.Items {
+		event := strings.TrimSpace(item.Event)
+		if !containsString(models.NotificationEvents, event) {
+			return nil, fmt.Errorf("[UpdateNotificationPreferences] %w: %q", appErrors.ErrUnknownNotificationEvent, event)
+		}
+
+		channels, err := normalizeNotificationChannels(item.Channels)
+		if err != nil {
+			return nil, fmt.Errorf("[UpdateNotificationPreferences] %w", err)
+		}
+
+		preferences = append(preferences, models.NotificationPreferenceDAO{
+			UserID:   request.UserID,
+			Event:    event,
+			Channels: channels,
+		})
+	}
+
+	if err := s.userRepo.SetNotificationPreferences(ctx, request.UserID, preferences); err != nil {
+		return nil, fmt.Errorf("[UpdateNotificationPreferences] set preferences: %w", err)
+	}
+
+	response, err := s.GetNotificationPreferences(ctx, request.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("[UpdateNotificationPreferences] %w", err)
+	}
+
+	return response, nil
+}
+
+// FilterNotificationRecipients оставляет из переданных пользователей тех, кто хочет получать
+// уведомление о событии по каналу. Порядок пользователей сохраняется
+func (s *UserService) FilterNotificationRecipients(ctx context.Context, request *models.NotificationRecipientsDTO) ([]int, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.FilterNotificationRecipients")
+	defer span.Finish()
+
+	if !containsString(models.NotificationEvents, request.Event) {
+		return nil, fmt.Errorf("[FilterNotificationRecipients] %w: %q", appErrors.ErrUnknownNotificationEvent, request.Event)
+	}
+	if !containsString(models.NotificationChannels, request.Channel) {
+		return nil, fmt.Errorf("[FilterNotificationRecipients] %w: %q", appErrors.ErrUnknownNotificationChannel, request.Channel)
+	}
+
+	recipients := make([]int, 0, len(request.UserIDs))
+	if len(request.UserIDs) == 0 {
+		return recipients, nil
+	}
+
+	stored, err := s.userRepo.GetEventNotificationPreferences(ctx, request.Event, request.UserIDs)
+	if err != nil {
+		return nil, fmt.Errorf("[FilterNotificationRecipients] get preferences: %w", err)
+	}
+
+	channels := make(map[int][]string, len(stored))
+	for _, preference := range stored {
+		channels[preference.UserID] = preference.Channels
+	}
+
+	for _, userID := range request.UserIDs {
+		userChannels, ok := channels[userID]
+		if !ok || containsString(userChannels, request.Channel) {
+			recipients = append(recipients, userID)
+		}
+	}
+
+	return recipients, nil
+}
+
+func normalizeNotificationChannels(channels []string) ([]string, error) {
+	result := make([]string, 0, len(channels))
+	for _, channel := range channels {
+		channel = strings.TrimSpace(channel)
+		if !containsString(models.NotificationChannels, channel) {
+			return nil, fmt.Errorf("%w: %q", appErrors.ErrUnknownNotificationChannel, channel)
+		}
+		if !containsString(result, channel) {
+			result = append(result, channel)
+		}
+	}
+
+	return result, nil
+}
+
+func containsString(items []string, item string) bool {
+	for _, value := range items {
+		if value == item {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,363 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/opentracing/opentracing-go"
+	"net/mail"
+	"strings"
+	"time"
+	"unicode/utf8"
+	appErrors "users/internal/app_errors"
+	"users/internal/models"
+)
+
+// maxOrganizationNameLength - длина названий организаций и команд в символах
+const maxOrganizationNameLength = 128
+
+// CreateOrganization создает организацию, создатель становится ее владельцем
+func (s *UserService) CreateOrganization(ctx context.Context, request *models.CreateOrganizationDTO) (*models.OrganizationDAO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.CreateOrganization")
+	defer span.Finish()
+
+	name, ok := organizationName(request.Name)
+	if !ok {
+		return nil, fmt.Errorf("[CreateOrganization] %w", appErrors.ErrOrganizationNameInvalid)
+	}
+
+	organization, err := s.userRepo.CreateOrganization(ctx, name, request.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("[CreateOrganization] create organization: %w", err)
+	}
+
+	return organization, nil
+}
+
+func (s *UserService) GetUserOrganizations(ctx context.Context, userID int) ([]models.OrganizationDAO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.GetUserOrganizations")
+	defer span.Finish()
+
+	organizations, err := s.userRepo.GetUserOrganizations(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("[GetUserOrganizations] get organizations: %w", err)
+	}
+
+	return organizations, nil
+}
+
+// ListOrganizationMembers возвращает участников организации, их видит любой участник
+func (s *UserService) ListOrganizationMembers(ctx context.Context, organizationID, userID int) ([]models.OrganizationMemberDAO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.ListOrganizationMembers")
+	defer span.Finish()
+
+	if _, err := s.organizationAs(ctx, organizationID, userID); err != nil {
+		return nil, fmt.Errorf("[ListOrganizationMembers] %w", err)
+	}
+
+	members, err := s.userRepo.GetOrganizationMembers(ctx, organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("[ListOrganizationMembers] get members: %w", err)
+	}
+
+	return members, nil
+}
+
+// InviteMember отправляет на email приглашение в организацию со ссылкой для его принятия.
+// Приглашать могут владельцы и администраторы, владельцем по приглашению стать нельзя
+func (s *UserService) InviteMember(ctx context.Context, request *models.InviteMemberDTO) (*models.InvitationDAO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.InviteMember")
+	defer span.Finish()
+
+	organization, err := s.organizationAs(ctx, request.OrganizationID, request.UserID, models.OrganizationRoleOwner, models.OrganizationRoleAdmin)
+	if err != nil {
+		return nil, fmt.Errorf("[InviteMember] %w", err)
+	}
+
+	role := request.Role
+	if role == "" {
+		role = models.OrganizationRoleMember
+	}
+	if role != models.OrganizationRoleAdmin && role != models.OrganizationRoleMember {
+		return nil, fmt.Errorf("[InviteMember] %w: %q", appErrors.ErrUnknownOrganizationRole, role)
+	}
+
+	email := normalizeEmail(request.Email)
+	if address, err := mail.ParseAddress(email); err != nil || address.Address != email {
+		return nil, fmt.Errorf("[InviteMember] %w", appErrors.ErrInvalidEmail)
+	}
+
+	// зарегистрированного участника повторно не приглашаем
+	existingUser, err := s.userRepo.GetUserByUsernameOrEmail(ctx, "", email)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("[InviteMember] get user: %w", err)
+	}
+	if err == nil {
+		_, err = s.userRepo.GetUserOrganization(ctx, organization.ID, existingUser.ID)
+		if err == nil {
+			return nil, fmt.Errorf("[InviteMember] %w", appErrors.ErrAlreadyMember)
+		}
+		if !errors.Is(err, appErrors.ErrOrganizationNotFound) {
+			return nil, fmt.Errorf("[InviteMember] get membership: %w", err)
+		}
+	}
+
+	token, err := newSecretToken()
+	if err != nil {
+		return nil, fmt.Errorf("[InviteMember] generate token: %w", err)
+	}
+
+	invitation, err := s.userRepo.CreateInvitation(ctx, &models.InvitationDAO{
+		ID:             uuid.New(),
+		OrganizationID: organization.ID,
+		Email:          email,
+		Role:           role,
+		TokenHash:      hashSecretToken(token),
+		InvitedBy:      request.UserID,
+		ExpiresAt:      time.Now().Add(s.orgConfig.InviteTTL).Truncate(time.Second),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("[InviteMember] store invitation: %w", err)
+	}
+
+	link, err := tokenLink(s.orgConfig.InviteLinkURL, token)
+	if err != nil {
+		return nil, fmt.Errorf("[InviteMember] build link: %w", err)
+	}
+
+	err = s.publishUserMail(ctx, &models.UserMailItem{
+		UserEventType: models.UserEventTypeOrgInvitation,
+		Receivers:     []string{email},
+		Link:          link,
+		Organization:  organization.Name,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("[InviteMember] publish mssg: %w", err)
+	}
+
+	return invitation, nil
+}
+
+// AcceptInvitation добавляет пользователя в организацию по токену из письма. Приглашение действует
+// только для адреса, на который отправлено, пересланная ссылка чужому аккаунту не подойдет
+func (s *UserService) AcceptInvitation(ctx context.Context, token string, userID int) (*models.OrganizationDAO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.AcceptInvitation")
+	defer span.Finish()
+
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return nil, fmt.Errorf("[AcceptInvitation] %w", appErrors.ErrInvalidInvitation)
+	}
+
+	invitation, err := s.userRepo.GetInvitationByHash(ctx, hashSecretToken(token))
+	if err != nil {
+		return nil, fmt.Errorf("[AcceptInvitation] get invitation: %w", err)
+	}
+
+	existingUser, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("[AcceptInvitation] get user: %w", err)
+	}
+
+	if !strings.EqualFold(existingUser.Email, invitation.Email) {
+		return nil, fmt.Errorf("[AcceptInvitation] %w", appErrors.ErrInvitationEmailMismatch)
+	}
+
+	if err := s.userRepo.AcceptInvitation(ctx, invitation.ID, userID); err != nil {
+		return nil, fmt.Errorf("[AcceptInvitation] accept: %w", err)
+	}
+
+	organization, err := s.userRepo.GetUserOrganization(ctx, invitation.OrganizationID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("[AcceptInvitation] get organization: %w", err)
+	}
+
+	return organization, nil
+}
+
+// SetMemberRole меняет роль участника, это может только владелец. У организации всегда остается хотя бы один владелец
+func (s *UserService) SetMemberRole(ctx context.Context, request *models.MemberDTO) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.SetMemberRole")
+	defer span.Finish()
+
+	if !isOrganizationRole(request.Role) {
+		return fmt.Errorf("[SetMemberRole] %w: %q", appErrors.ErrUnknownOrganizationRole, request.Role)
+	}
+
+	if _, err := s.organizationAs(ctx, request.OrganizationID, request.UserID, models.OrganizationRoleOwner); err != nil {
+		return fmt.Errorf("[SetMemberRole] %w", err)
+	}
+
+	if err := s.userRepo.SetMemberRole(ctx, request.OrganizationID, request.MemberID, request.Role); err != nil {
+		return fmt.Errorf("[SetMemberRole] set role: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveMember исключает участника из организации. Выйти из организации может любой участник,
+// исключать других - владельцы и администраторы, причем администратор не может исключить владельца
+func (s *UserService) RemoveMember(ctx context.Context, request *models.MemberDTO) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.RemoveMember")
+	defer span.Finish()
+
+	if request.MemberID != request.UserID {
+		organization, err := s.organizationAs(ctx, request.OrganizationID, request.UserID, models.OrganizationRoleOwner, models.OrganizationRoleAdmin)
+		if err != nil {
+			return fmt.Errorf("[RemoveMember] %w", err)
+		}
+
+		if organization.Role != models.OrganizationRoleOwner {
+			member, err := s.userRepo.GetUserOrganization(ctx, request.OrganizationID, request.MemberID)
+			if err != nil {
+				if errors.Is(err, appErrors.ErrOrganizationNotFound) {
+					return fmt.Errorf("[RemoveMember] %w", appErrors.ErrMemberNotFound)
+				}
+				return fmt.Errorf("[RemoveMember] get member: %w", err)
+			}
+			if member.Role == models.OrganizationRoleOwner {
+				return fmt.Errorf("[RemoveMember] %w", appErrors.ErrOrganizationForbidden)
+			}
+		}
+	}
+
+	if err := s.userRepo.RemoveMember(ctx, request.OrganizationID, request.MemberID); err != nil {
+		if errors.Is(err, appErrors.ErrMemberNotFound) && request.MemberID == request.UserID {
+			return fmt.Errorf("[RemoveMember] %w", appErrors.ErrOrganizationNotFound)
+		}
+		return fmt.Errorf("[RemoveMember] remove member: %w", err)
+	}
+
+	return nil
+}
+
+// CreateTeam создает команду в организации, это могут владельцы и администраторы
+func (s *UserService) CreateTeam(ctx context.Context, request *models.CreateTeamDTO) (*models.TeamDAO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.CreateTeam")
+	defer span.Finish()
+
+	name, ok := organizationName(request.Name)
+	if !ok {
+		return nil, fmt.Errorf("[CreateTeam] %w", appErrors.ErrTeamNameInvalid)
+	}
+
+	if _, err := s.organizationAs(ctx, request.OrganizationID, request.UserID, models.OrganizationRoleOwner, models.OrganizationRoleAdmin); err != nil {
+		return nil, fmt.Errorf("[CreateTeam] %w", err)
+	}
+
+	team, err := s.userRepo.CreateTeam(ctx, request.OrganizationID, name)
+	if err != nil {
+		return nil, fmt.Errorf("[CreateTeam] create team: %w", err)
+	}
+
+	return team, nil
+}
+
+func (s *UserService) GetTeams(ctx context.Context, organizationID, userID int) ([]models.TeamDAO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.GetTeams")
+	defer span.Finish()
+
+	if _, err := s.organizationAs(ctx, organizationID, userID); err != nil {
+		return nil, fmt.Errorf("[GetTeams] %w", err)
+	}
+
+	teams, err := s.userRepo.GetTeams(ctx, organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("[GetTeams] get teams: %w", err)
+	}
+
+	return teams, nil
+}
+
+// AddTeamMember добавляет участника организации в команду, это могут владельцы и администраторы
+func (s *UserService) AddTeamMember(ctx context.Context, request *models.TeamMemberDTO) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.AddTeamMember")
+	defer span.Finish()
+
+	if err := s.teamAs(ctx, request, models.OrganizationRoleOwner, models.OrganizationRoleAdmin); err != nil {
+		return fmt.Errorf("[AddTeamMember] %w", err)
+	}
+
+	if err := s.userRepo.AddTeamMember(ctx, request.OrganizationID, request.TeamID, request.MemberID); err != nil {
+		return fmt.Errorf("[AddTeamMember] add member: %w", err)
+	}
+
+	return nil
+}
+
+func (s *UserService) RemoveTeamMember(ctx context.Context, request *models.TeamMemberDTO) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.RemoveTeamMember")
+	defer span.Finish()
+
+	if err := s.teamAs(ctx, request, models.OrganizationRoleOwner, models.OrganizationRoleAdmin); err != nil {
+		return fmt.Errorf("[RemoveTeamMember] %w", err)
+	}
+
+	if err := s.userRepo.RemoveTeamMember(ctx, request.TeamID, request.MemberID); err != nil {
+		return fmt.Errorf("[RemoveTeamMember] remove member: %w", err)
+	}
+
+	return nil
+}
+
+// ListTeamMembers возвращает участников команды, их видит любой участник организации
+func (s *UserService) ListTeamMembers(ctx context.Context, request *models.TeamMemberDTO) ([]models.OrganizationMemberDAO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.ListTeamMembers")
+	defer span.Finish()
+
+	if err := s.teamAs(ctx, request); err != nil {
+		return nil, fmt.Errorf("[ListTeamMembers] %w", err)
+	}
+
+	members, err := s.userRepo.GetTeamMembers(ctx, request.TeamID)
+	if err != nil {
+		return nil, fmt.Errorf("[ListTeamMembers] get members: %w", err)
+	}
+
+	return members, nil
+}
+
+// organizationAs возвращает организацию, если пользователь в ней состоит с одной из ролей roles.
+// Без ролей подходит любой участник
+func (s *UserService) organizationAs(ctx context.Context, organizationID, userID int, roles ...string) (*models.OrganizationDAO, error) {
+	organization, err := s.userRepo.GetUserOrganization(ctx, organizationID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get organization: %w", err)
+	}
+
+	if len(roles) == 0 {
+		return organization, nil
+	}
+	for _, role := range roles {
+		if organization.Role == role {
+			return organization, nil
+		}
+	}
+
+	return nil, appErrors.ErrOrganizationForbidden
+}
+
+// teamAs проверяет роль пользователя в организации и то, что команда принадлежит этой организации
+func (s *UserService) teamAs(ctx context.Context, request *models.TeamMemberDTO, roles ...string) error {
+	if _, err := s.organizationAs(ctx, request.OrganizationID, request.UserID, roles...); err != nil {
+		return err
+	}
+
+	if _, err := s.userRepo.GetTeam(ctx, request.OrganizationID, request.TeamID); err != nil {
+		return fmt.Errorf("get team: %w", err)
+	}
+
+	return nil
+}
+
+// organizationName убирает пробелы по краям названия организации или команды и проверяет его длину
+func organizationName(name string) (string, bool) {
+	name = strings.TrimSpace(name)
+	return name, name != "" && utf8.RuneCountInString(name) <= maxOrganizationNameLength
+}
+
+func isOrganizationRole(role string) bool {
+	return role == models.OrganizationRoleOwner || role == models.OrganizationRoleAdmin || role == models.OrganizationRoleMember
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/opentracing/opentracing-go"
+	appErrors "users/internal/app_errors"
+)
+
+// passwordField - поле с новым паролем во всех запросах, где пароль задается
+const passwordField = "password"
+
+// checkPassword проверяет новый пароль по требованиям из настроек и возвращает PasswordPolicyError со всеми
+// нарушениями. userID - владелец пароля, при регистрации 0: прежних паролей еще нет. userInfo - имя и email,
+// которые не должны быть частью пароля. Повтор прежних паролей проверяется последним и только для пароля,
+// прошедшего остальные требования, потому что сравнение с каждым хэшем стоит столько же, сколько вход
+func (s *UserService) checkPassword(ctx context.Context, userID int, password string, userInfo ...string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.checkPassword")
+	defer span.Finish()
+
+	policyErr := &appErrors.PasswordPolicyError{}
+	for _, violation := range s.passwordPolicy.Check(password, userInfo...) {
+		policyErr.Violations = append(policyErr.Violations, appErrors.FieldViolation{
+			Field:   passwordField,
+			Message: violation.Message,
+		})
+	}
+
+	if len(policyErr.Violations) == 0 && userID != 0 && s.policyConfig.HistorySize > 0 {
+		reused, err := s.isRecentPassword(ctx, userID, password)
+		if err != nil {
+			return fmt.Errorf("[checkPassword] %w", err)
+		}
+		if reused {
+			policyErr.Violations = append(policyErr.Violations, appErrors.FieldViolation{
+				Field:   passwordField,
+				Message: fmt.Sprintf("password must differ from the last %d passwords", s.policyConfig.HistorySize),
+			})
+		}
+	}
+
+	if len(policyErr.Violations) > 0 {
+		return policyErr
+	}
+
+	return nil
+}
+
+// isRecentPassword - пароль совпадает с текущим или с одним из недавних паролей пользователя
+func (s *UserService) isRecentPassword(ctx context.Context, userID int, password string) (bool, error) {
+	hashes, err := s.userRepo.GetPasswordHistory(ctx, userID, s.policyConfig.HistorySize)
+	if err != nil {
+		return false, fmt.Errorf("get password history: %w", err)
+	}
+
+	for _, hash := range hashes {
+		match, err := ComparePassword(ctx, password, hash)
+		if err != nil {
+			// поврежденный хэш в истории не мешает сменить пароль
+			if errors.Is(err, appErrors.ErrMalformedPasswordHash) {
+				continue
+			}
+			return false, fmt.Errorf("compare with previous password: %w", err)
+		}
+		if match {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// prunePasswordHistory удаляет прежние пароли, которые больше не участвуют в проверке. Ошибка не мешает
+// смене пароля: лишние записи удалятся при следующей смене
+func (s *UserService) prunePasswordHistory(ctx context.Context, userID int) {
+	// текущий пароль хранится в users, в истории нужны только предыдущие
+	keep := s.policyConfig.HistorySize - 1
+	if keep < 0 {
+		keep = 0
+	}
+
+	_ = s.userRepo.PrunePasswordHistory(ctx, userID, keep)
+}
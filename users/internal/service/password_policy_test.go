@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"users/config"
+	appErrors "users/internal/app_errors"
+	"users/pkg/passwordpolicy"
+)
+
+// passwordHistoryRepo - репозиторий, в котором есть только история паролей. Остальные методы не вызываются
+type passwordHistoryRepo struct {
+	UserRepository
+	hashes []string
+	calls  int
+}
+
+func (r *passwordHistoryRepo) GetPasswordHistory(_ context.Context, _ int, limit int) ([]string, error) {
+	r.calls++
+	if limit < len(r.hashes) {
+		return r.hashes[:limit], nil
+	}
+	return r.hashes, nil
+}
+
+func newPasswordPolicyService(t *testing.T, repo UserRepository, historySize int) *UserService {
+	t.Helper()
+
+	policyConfig := &config.PasswordPolicyConfig{
+		MinLength:        8,
+		MaxLength:        64,
+		RequireLowercase: true,
+		RequireUppercase: true,
+		RequireDigit:     true,
+		RequireSymbol:    true,
+		RejectUserInfo:   true,
+		HistorySize:      historySize,
+	}
+	policy, err := passwordpolicy.New(passwordpolicy.Rules{
+		MinLength:        policyConfig.MinLength,
+		MaxLength:        policyConfig.MaxLength,
+		RequireLowercase: policyConfig.RequireLowercase,
+		RequireUppercase: policyConfig.RequireUppercase,
+		RequireDigit:     policyConfig.RequireDigit,
+		RequireSymbol:    policyConfig.RequireSymbol,
+		RejectUserInfo:   policyConfig.RejectUserInfo,
+	})
+	if err != nil {
+		t.Fatalf("passwordpolicy.New error: %v", err)
+	}
+
+	return &UserService{
+		passConfig:     &config.PasswordConfig{Time: 1, Memory: 1024, Threads: 1, KeyLen: 32},
+		policyConfig:   policyConfig,
+		passwordPolicy: policy,
+		userRepo:       repo,
+	}
+}
+
+func TestCheckPasswordViolations(t *testing.T) {
+	s := newPasswordPolicyService(t, &passwordHistoryRepo{}, 0)
+
+	tests := []struct {
+		name     string
+		password string
+		want     []string
+	}{
+		{"valid", "Tr0ub4dor&3", nil},
+		{"too short", "Ab1!", []string{"at least 8 characters"}},
+		{"too long", "Ab1!" + strings.Repeat("x", 61), []string{"at most 64 characters"}},
+		{"missing lowercase", "TR0UB4DOR&3", []string{"lowercase letter"}},
+		{"missing uppercase", "tr0ub4dor&3", []string{"uppercase letter"}},
+		{"missing digit", "Troubador&x", []string{"digit"}},
+		{"missing symbol", "Tr0ub4dor3x", []string{"special character"}},
+		{"banned", "P@ssw0rd", []string{"too common"}},
+		{"similar to user", "Alice-2024!", []string{"username or email"}},
+		{"several at once", "alice", []string{"at least 8 characters", "uppercase letter", "digit", "special character", "username or email"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.checkPassword(context.Background(), 0, tt.password, "alice", "alice@example.com")
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("checkPassword(%q) error: %v", tt.password, err)
+				}
+				return
+			}
+
+			var policyErr *appErrors.PasswordPolicyError
+			if !errors.As(err, &policyErr) {
+				t.Fatalf("checkPassword(%q) error = %v, want *PasswordPolicyError", tt.password, err)
+			}
+			if !errors.Is(err, appErrors.ErrPasswordPolicy) {
+				t.Errorf("checkPassword(%q) error does not wrap ErrPasswordPolicy", tt.password)
+			}
+			if len(policyErr.Violations) != len(tt.want) {
+				t.Fatalf("checkPassword(%q) violations = %v, want %d", tt.password, policyErr.Violations, len(tt.want))
+			}
+			for i, violation := range policyErr.Violations {
+				if violation.Field != passwordField {
+					t.Errorf("violation %d field = %q, want %q", i, violation.Field, passwordField)
+				}
+				if !strings.Contains(violation.Message, tt.want[i]) {
+					t.Errorf("violation %d message = %q, want it to contain %q", i, violation.Message, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCheckPasswordHistory(t *testing.T) {
+	ctx := context.Background()
+	passConfig := &config.PasswordConfig{Time: 1, Memory: 1024, Threads: 1, KeyLen: 32}
+
+	var hashes []string
+	for _, password := range []string{"Current-Pass1", "Previous-Pass2", "Oldest-Pass3"} {
+		hash, err := GeneratePassword(ctx, passConfig, password)
+		if err != nil {
+			t.Fatalf("GeneratePassword error: %v", err)
+		}
+		hashes = append(hashes, hash)
+	}
+
+	tests := []struct {
+		name        string
+		userID      int
+		password    string
+		historySize int
+		wantReused  bool
+		wantLookups int
+	}{
+		{"current password", 1, "Current-Pass1", 3, true, 1},
+		{"recent password", 1, "Previous-Pass2", 3, true, 1},
+		{"password older than history", 1, "Oldest-Pass3", 2, false, 1},
+		{"new password", 1, "Brand-New-Pass4", 3, false, 1},
+		{"history disabled", 1, "Current-Pass1", 0, false, 0},
+		{"registration has no history", 0, "Current-Pass1", 3, false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &passwordHistoryRepo{hashes: hashes}
+			s := newPasswordPolicyService(t, repo, tt.historySize)
+
+			err := s.checkPassword(ctx, tt.userID, tt.password)
+			var policyErr *appErrors.PasswordPolicyError
+			reused := errors.As(err, &policyErr)
+			if err != nil && !reused {
+				t.Fatalf("checkPassword error: %v", err)
+			}
+			if reused != tt.wantReused {
+				t.Errorf("checkPassword(%q) reused = %v, want %v (%v)", tt.password, reused, tt.wantReused, err)
+			}
+			if reused && !strings.Contains(policyErr.Violations[0].Message, "differ from the last") {
+				t.Errorf("checkPassword(%q) message = %q, want history violation", tt.password, policyErr.Violations[0].Message)
+			}
+			if repo.calls != tt.wantLookups {
+				t.Errorf("GetPasswordHistory calls = %d, want %d", repo.calls, tt.wantLookups)
+			}
+		})
+	}
+
+	// пароль, не прошедший требования, не сравнивается с историей: это так же дорого, как вход
+	repo := &passwordHistoryRepo{hashes: hashes}
+	s := newPasswordPolicyService(t, repo, 3)
+	if err := s.checkPassword(ctx, 1, "short"); err == nil {
+		t.Fatal("checkPassword(\"short\") error = nil, want policy error")
+	}
+	if repo.calls != 0 {
+		t.Errorf("GetPasswordHistory calls for an invalid password = %d, want 0", repo.calls)
+	}
+}
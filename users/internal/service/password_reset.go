@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v4"
+	"github.com/opentracing/opentracing-go"
+	"strings"
+	appErrors "users/internal/app_errors"
+	"users/internal/models"
+)
+
+// RequestPasswordReset отправляет на email письмо со ссылкой для установки нового пароля.
+// Для неизвестного адреса и слишком частых запросов письмо не отправляется, но и ошибка не возвращается,
+// чтобы по ответу нельзя было проверить, зарегистрирован ли адрес.
+func (s *UserService) RequestPasswordReset(ctx context.Context, email string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.RequestPasswordReset")
+	defer span.Finish()
+
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return nil
+	}
+
+	existingUser, err := s.userRepo.GetUserByUsernameOrEmail(ctx, "", email)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return fmt.Errorf("[RequestPasswordReset] get user: %w", err)
+	}
+
+	recentlySent, err := s.userRepo.HasRecentPasswordResetToken(ctx, existingUser.ID, s.resetConfig.RequestInterval)
+	if err != nil {
+		return fmt.Errorf("[RequestPasswordReset] check last token: %w", err)
+	}
+	if recentlySent {
+		return nil
+	}
+
+	token, err := newSecretToken()
+	if err != nil {
+		return fmt.Errorf("[RequestPasswordReset] generate token: %w", err)
+	}
+
+	err = s.userRepo.CreatePasswordResetToken(ctx, existingUser.ID, hashSecretToken(token), s.resetConfig.TokenTTL)
+	if err != nil {
+		return fmt.Errorf("[RequestPasswordReset] store token: %w", err)
+	}
+
+	link, err := tokenLink(s.resetConfig.LinkURL, token)
+	if err != nil {
+		return fmt.Errorf("[RequestPasswordReset] build link: %w", err)
+	}
+
+	err = s.publishUserMail(ctx, &models.UserMailItem{
+		UserEventType: models.UserEventTypePasswordReset,
+		Receivers:     []string{existingUser.Email},
+		Link:          link,
+	})
+	if err != nil {
+		return fmt.Errorf("[RequestPasswordReset] publish mssg: %w", err)
+	}
+
+	return nil
+}
+
+// ResetPassword устанавливает новый пароль по токену из письма. После этого все выданные
+// пользователю ссылки на сброс пароля перестают работать.
+func (s *UserService) ResetPassword(ctx context.Context, request *models.ResetPasswordDTO) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.ResetPassword")
+	defer span.Finish()
+
+	token := strings.TrimSpace(request.Token)
+	if token == "" {
+		return fmt.Errorf("[ResetPassword] %w", appErrors.ErrInvalidPasswordResetToken)
+	}
+
+	// проверяем, совпадают ли новый пароль и подтверждение пароля
+	if request.Password != request.PasswordConfirmation {
+		return fmt.Errorf("[ResetPassword] confirm pass: %w", appErrors.ErrPassAndConfirmationDoesNotMatch)
+	}
+
+	// владелец токена нужен, чтобы проверить пароль на сходство с его данными и на повтор прежних паролей.
+	// Токен гасится только после проверки, поэтому неподходящий пароль можно исправить по той же ссылке
+	userID, err := s.userRepo.GetPasswordResetTokenUser(ctx, hashSecretToken(token))
+	if err != nil {
+		return fmt.Errorf("[ResetPassword] get token: %w", err)
+	}
+
+	existingUser, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("[ResetPassword] get user: %w", err)
+	}
+
+	if err := s.checkPassword(ctx, existingUser.ID, request.Password, existingUser.Username, existingUser.Email); err != nil {
+		return fmt.Errorf("[ResetPassword] check pass: %w", err)
+	}
+
+	// Хеширование пароля.
+	hashedPassword, err := GeneratePassword(ctx, s.passConfig, request.Password)
+	if err != nil {
+		return fmt.Errorf("[ResetPassword] generate pass: %w", err)
+	}
+
+	userID, err = s.userRepo.ResetPassword(ctx, hashSecretToken(token), hashedPassword)
+	if err != nil {
+		return fmt.Errorf("[ResetPassword] reset: %w", err)
+	}
+	s.prunePasswordHistory(ctx, userID)
+
+	return nil
+}
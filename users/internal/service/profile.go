@@ -0,0 +1,235 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v4"
+	"github.com/opentracing/opentracing-go"
+	"golang.org/x/text/language"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	"image/png"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+	appErrors "users/internal/app_errors"
+	"users/internal/models"
+	"users/pkg/thumbnail"
+)
+
+const (
+	maxUsernameLength    = 255
+	maxDisplayNameLength = 64
+
+	// avatarSize и avatarThumbnailSize - стороны квадратных копий аватара, оригинал не хранится
+	avatarSize          = 256
+	avatarThumbnailSize = 64
+)
+
+// UploadAvatar - проверяет изображение, строит из него квадратный аватар и миниатюру и сохраняет их в хранилище.
+// Копии перекодируются в PNG, поэтому метаданные оригинала, например координаты съемки, не сохраняются
+func (s *UserService) UploadAvatar(ctx context.Context, userID int, data []byte) (*models.UserDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.UploadAvatar")
+	defer span.Finish()
+
+	if len(data) > s.profileConfig.AvatarMaxBytes {
+		return nil, fmt.Errorf("[UploadAvatar] %w", appErrors.ErrAvatarTooLarge)
+	}
+
+	// размеры проверяются по заголовку до декодирования, чтобы не выделять память под огромную картинку
+	imageConfig, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("[UploadAvatar] %w", appErrors.ErrInvalidAvatar)
+	}
+	if imageConfig.Width <= 0 || imageConfig.Height <= 0 {
+		return nil, fmt.Errorf("[UploadAvatar] %w", appErrors.ErrInvalidAvatar)
+	}
+	if imageConfig.Width*imageConfig.Height > s.profileConfig.AvatarMaxPixels {
+		return nil, fmt.Errorf("[UploadAvatar] %w", appErrors.ErrAvatarTooLarge)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("[UploadAvatar] %w", appErrors.ErrInvalidAvatar)
+	}
+
+	// у каждой загрузки новый ключ: старые адреса можно кешировать навсегда
+	token, err := newSecretToken()
+	if err != nil {
+		return nil, fmt.Errorf("[UploadAvatar] generate key: %w", err)
+	}
+	avatarKey := fmt.Sprintf("avatars/%d/%s", userID, token)
+
+	avatar := thumbnail.Square(img, avatarSize)
+	for size, copyImage := range map[int]image.Image{
+		avatarSize:          avatar,
+		avatarThumbnailSize: thumbnail.Square(avatar, avatarThumbnailSize),
+	} {
+		var encoded bytes.Buffer
+		if err := png.Encode(&encoded, copyImage); err != nil {
+			return nil, fmt.Errorf("[UploadAvatar] encode: %w", err)
+		}
+
+		if err := s.avatarStorage.Put(ctx, avatarFile(avatarKey, size), encoded.Bytes()); err != nil {
+			s.deleteAvatarFiles(ctx, avatarKey)
+			return nil, fmt.Errorf("[UploadAvatar] store: %w", err)
+		}
+	}
+
+	previousKey, err := s.userRepo.UpdateAvatarKey(ctx, userID, avatarKey)
+	if err != nil {
+		s.deleteAvatarFiles(ctx, avatarKey)
+		return nil, fmt.Errorf("[UploadAvatar] update user: %w", err)
+	}
+	s.deleteAvatarFiles(ctx, previousKey)
+	s.publishUserUpdated(ctx, userID)
+
+	userResponse, err := s.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("[UploadAvatar] %w", err)
+	}
+
+	return userResponse, nil
+}
+
+// DeleteAvatar - удаляет аватар пользователя
+func (s *UserService) DeleteAvatar(ctx context.Context, userID int) (*models.UserDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.DeleteAvatar")
+	defer span.Finish()
+
+	previousKey, err := s.userRepo.UpdateAvatarKey(ctx, userID, "")
+	if err != nil {
+		return nil, fmt.Errorf("[DeleteAvatar] update user: %w", err)
+	}
+	s.deleteAvatarFiles(ctx, previousKey)
+	s.publishUserUpdated(ctx, userID)
+
+	userResponse, err := s.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("[DeleteAvatar] %w", err)
+	}
+
+	return userResponse, nil
+}
+
+// applyProfile проверяет и переносит в existingUser переданные поля профиля.
+// Пустое поле означает, что значение не меняется
+func (s *UserService) applyProfile(ctx context.Context, existingUser *models.UserDAO, updatedUser *models.UserDTO) error {
+	if username := strings.TrimSpace(updatedUser.Username); username != "" && username != existingUser.Username {
+		if utf8.RuneCountInString(username) > maxUsernameLength {
+			return appErrors.ErrUsernameInvalid
+		}
+
+		owner, err := s.userRepo.GetUserByUsername(ctx, username)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("check username: %w", err)
+		}
+		if err == nil && owner.ID != existingUser.ID {
+			return appErrors.ErrUsernameOrEmailIsUsed
+		}
+
+		existingUser.Username = username
+	}
+
+	// email меняется только через подтверждение нового адреса, тот же адрес в запросе не ошибка
+	if email := strings.TrimSpace(updatedUser.Email); email != "" && !strings.EqualFold(email, existingUser.Email) {
+		return appErrors.ErrEmailChangeRequired
+	}
+
+	if displayName := strings.TrimSpace(updatedUser.DisplayName); displayName != "" {
+		if utf8.RuneCountInString(displayName) > maxDisplayNameLength || !isPrintable(displayName) {
+			return appErrors.ErrDisplayNameInvalid
+		}
+		existingUser.DisplayName = displayName
+	}
+
+	if updatedUser.Timezone != "" {
+		timezone, err := parseTimezone(updatedUser.Timezone)
+		if err != nil {
+			return err
+		}
+		existingUser.Timezone = timezone
+	}
+
+	if updatedUser.Locale != "" {
+		locale, err := parseLocale(updatedUser.Locale)
+		if err != nil {
+			return err
+		}
+		existingUser.Locale = locale
+	}
+
+	return nil
+}
+
+// setProfile переносит в ответ поля профиля и адреса аватара
+func (s *UserService) setProfile(userResponse *models.UserDTO, storedUser *models.UserDAO) {
+	userResponse.DisplayName = storedUser.DisplayName
+	userResponse.Timezone = storedUser.Timezone
+	userResponse.Locale = storedUser.Locale
+	userResponse.AvatarURL, userResponse.AvatarThumbnailURL = s.avatarURLs(storedUser.AvatarKey)
+}
+
+// avatarURLs возвращает адреса аватара и его миниатюры, пустые, если аватар не загружен
+func (s *UserService) avatarURLs(avatarKey string) (string, string) {
+	if avatarKey == "" {
+		return "", ""
+	}
+
+	return s.avatarStorage.URL(avatarFile(avatarKey, avatarSize)),
+		s.avatarStorage.URL(avatarFile(avatarKey, avatarThumbnailSize))
+}
+
+// deleteAvatarFiles удаляет копии аватара. Ошибка не возвращается: оставшийся файл ни на что не ссылается
+// и не мешает пользователю, а откатывать из-за него уже сохраненный профиль нельзя
+func (s *UserService) deleteAvatarFiles(ctx context.Context, avatarKey string) {
+	if avatarKey == "" {
+		return
+	}
+
+	for _, size := range []int{avatarSize, avatarThumbnailSize} {
+		_ = s.avatarStorage.Delete(ctx, avatarFile(avatarKey, size))
+	}
+}
+
+func avatarFile(avatarKey string, size int) string {
+	return fmt.Sprintf("%s_%d.png", avatarKey, size)
+}
+
+// parseTimezone проверяет имя часового пояса по базе IANA. Local зависит от сервера и не принимается
+func parseTimezone(name string) (string, error) {
+	if name == "Local" {
+		return "", appErrors.ErrUnknownTimezone
+	}
+
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return "", appErrors.ErrUnknownTimezone
+	}
+
+	return location.String(), nil
+}
+
+// parseLocale проверяет тег языка и приводит его к каноническому виду: EN-us становится en-US
+func parseLocale(tag string) (string, error) {
+	locale, err := language.Parse(tag)
+	if err != nil || locale == language.Und {
+		return "", appErrors.ErrUnknownLocale
+	}
+
+	return locale.String(), nil
+}
+
+func isPrintable(value string) bool {
+	for _, r := range value {
+		if !unicode.IsPrint(r) {
+			return false
+		}
+	}
+
+	return true
+}
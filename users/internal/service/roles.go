@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/opentracing/opentracing-go"
+	"strings"
+	appErrors "users/internal/app_errors"
+	"users/internal/models"
+)
+
+// SetUserRole назначает пользователю роль. Новые разрешения попадут в токены пользователя при следующем обновлении.
+func (s *UserService) SetUserRole(ctx context.Context, userID int, role string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.SetUserRole")
+	defer span.Finish()
+
+	role = strings.TrimSpace(role)
+	if role == "" {
+		return fmt.Errorf("[SetUserRole] %w", appErrors.ErrUnknownRole)
+	}
+
+	if err := s.userRepo.SetUserRole(ctx, userID, role); err != nil {
+		return fmt.Errorf("[SetUserRole] set role: %w", err)
+	}
+	s.publishUserUpdated(ctx, userID)
+
+	return nil
+}
+
+// userResponse переносит данные пользователя в ответ вместе с разрешениями его роли,
+// организациями и командами. Если роль требует второй фактор, а он не включен, разрешений в ответе нет
+func (s *UserService) userResponse(ctx context.Context, storedUser *models.UserDAO) (*models.UserDTO, error) {
+	setupRequired, err := s.missingRequiredTwoFactor(ctx, storedUser)
+	if err != nil {
+		return nil, err
+	}
+
+	var permissions []string
+	if !setupRequired {
+		permissions, err = s.userRepo.GetRolePermissions(ctx, storedUser.Role)
+		if err != nil {
+			return nil, fmt.Errorf("get permissions: %w", err)
+		}
+	}
+
+	memberships, err := s.userRepo.GetUserMemberships(ctx, storedUser.ID)
+	if err != nil {
+		return nil, fmt.Errorf("get memberships: %w", err)
+	}
+
+	userResponse := &models.UserDTO{
+		ID:            storedUser.ID,
+		Username:      storedUser.Username,
+		Email:         storedUser.Email,
+		EmailVerified: storedUser.EmailVerified,
+		Role:          storedUser.Role,
+		Permissions:   permissions,
+
+		OrganizationIDs: memberships.OrganizationIDs,
+		TeamIDs:         memberships.TeamIDs,
+
+		TwoFactorSetupRequired: setupRequired,
+	}
+	s.setProfile(userResponse, storedUser)
+
+	return userResponse, nil
+}
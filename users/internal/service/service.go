@@ -4,35 +4,87 @@ import (
 	"context"
 	"crypto/rand"
 	"crypto/subtle"
-	"database/sql"
 	"encoding/base64"
-	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/jackc/pgx/v4"
 	"github.com/opentracing/opentracing-go"
 	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+	"strconv"
 	"strings"
 	"users/config"
 	appErrors "users/internal/app_errors"
 	"users/internal/models"
-	"users/pkg/ctxutil"
+	"users/pkg/passwordpolicy"
 )
 
 type UserService struct {
-	passConfig         *config.PasswordConfig
-	userRepo           UserRepository
-	userRabbitProducer RabbitProducer
+	passConfig          *config.PasswordConfig
+	policyConfig        *config.PasswordPolicyConfig
+	passwordPolicy      *passwordpolicy.Policy
+	verifyConfig        *config.VerificationConfig
+	resetConfig         *config.PasswordResetConfig
+	emailChangeConfig   *config.EmailChangeConfig
+	twoFactorConfig     *config.TwoFactorConfig
+	throttleConfig      *config.LoginThrottleConfig
+	apiTokenConfig      *config.ApiTokenConfig
+	orgConfig           *config.OrganizationConfig
+	profileConfig       *config.ProfileConfig
+	deletionConfig      *config.UserDeletionConfig
+	exportConfig        *config.DataExportConfig
+	erasureConfig       *config.ErasureConfig
+	userRepo            UserRepository
+	userRabbitProducer  RabbitProducer
+	userEventsProducer  RabbitProducer
+	userUpdatesProducer RabbitProducer
+	userExportsProducer RabbitProducer
+	avatarStorage       FileStorage
 }
 
 func NewUserService(
 	passwordConfig *config.PasswordConfig,
+	passwordPolicyConfig *config.PasswordPolicyConfig,
+	passwordPolicy *passwordpolicy.Policy,
+	verificationConfig *config.VerificationConfig,
+	passwordResetConfig *config.PasswordResetConfig,
+	emailChangeConfig *config.EmailChangeConfig,
+	twoFactorConfig *config.TwoFactorConfig,
+	loginThrottleConfig *config.LoginThrottleConfig,
+	apiTokenConfig *config.ApiTokenConfig,
+	organizationConfig *config.OrganizationConfig,
+	profileConfig *config.ProfileConfig,
+	userDeletionConfig *config.UserDeletionConfig,
+	dataExportConfig *config.DataExportConfig,
+	erasureConfig *config.ErasureConfig,
 	userRepo UserRepository,
 	userRabbitProducer RabbitProducer,
+	userEventsProducer RabbitProducer,
+	userUpdatesProducer RabbitProducer,
+	userExportsProducer RabbitProducer,
+	avatarStorage FileStorage,
 ) *UserService {
 	return &UserService{
-		passConfig:         passwordConfig,
-		userRepo:           userRepo,
-		userRabbitProducer: userRabbitProducer,
+		passConfig:          passwordConfig,
+		policyConfig:        passwordPolicyConfig,
+		passwordPolicy:      passwordPolicy,
+		verifyConfig:        verificationConfig,
+		resetConfig:         passwordResetConfig,
+		emailChangeConfig:   emailChangeConfig,
+		twoFactorConfig:     twoFactorConfig,
+		throttleConfig:      loginThrottleConfig,
+		apiTokenConfig:      apiTokenConfig,
+		orgConfig:           organizationConfig,
+		profileConfig:       profileConfig,
+		deletionConfig:      userDeletionConfig,
+		exportConfig:        dataExportConfig,
+		erasureConfig:       erasureConfig,
+		userRepo:            userRepo,
+		userRabbitProducer:  userRabbitProducer,
+		userEventsProducer:  userEventsProducer,
+		userUpdatesProducer: userUpdatesProducer,
+		userExportsProducer: userExportsProducer,
+		avatarStorage:       avatarStorage,
 	}
 }
 
@@ -40,12 +92,16 @@ func (s *UserService) RegisterUser(ctx context.Context, newUser *models.CreateUs
 	span, ctx := opentracing.StartSpanFromContext(ctx, "service.RegisterUser")
 	defer span.Finish()
 
-	// Проверка наличия пользователя с таким же именем или мэйлом.
-	_, err := s.userRepo.GetUserByUsernameOrEmail(ctx, newUser.Username, newUser.Email)
-	if err == nil {
-		return 0, fmt.Errorf("[RegisterUser] get user: %w", appErrors.ErrUsernameOrEmailIsUsed)
-	} else if !errors.As(err, &sql.ErrNoRows) {
-		return 0, err
+	newUser.Email = normalizeEmail(newUser.Email)
+
+	// Проверка наличия пользователя с таким же именем или мэйлом. Одновременную регистрацию
+	// с теми же данными остановит уникальный индекс, и CreateUser вернет ту же ошибку
+	used, err := s.userRepo.IsUsernameOrEmailUsed(ctx, newUser.Username, newUser.Email)
+	if err != nil {
+		return 0, fmt.Errorf("[RegisterUser] check user: %w", err)
+	}
+	if used {
+		return 0, fmt.Errorf("[RegisterUser] check user: %w", appErrors.ErrUsernameOrEmailIsUsed)
 	}
 
 	// проверяем, что пароль совпадает с подтверждением пароля
@@ -53,6 +109,11 @@ func (s *UserService) RegisterUser(ctx context.Context, newUser *models.CreateUs
 		return 0, fmt.Errorf("[RegisterUser] confirm pass: %w", appErrors.ErrPassAndConfirmationDoesNotMatch)
 	}
 
+	// проверяем пароль по требованиям из настроек
+	if err := s.checkPassword(ctx, 0, newUser.Password, newUser.Username, newUser.Email); err != nil {
+		return 0, fmt.Errorf("[RegisterUser] check pass: %w", err)
+	}
+
 	// Хеширование пароля - никогда не храните пароль в незашифрованном виде.
 	hashedPassword, err := GeneratePassword(ctx, s.passConfig, newUser.Password)
 	if err != nil {
@@ -68,19 +129,10 @@ func (s *UserService) RegisterUser(ctx context.Context, newUser *models.CreateUs
 		return 0, fmt.Errorf("[RegisterUser] store user:%w", err)
 	}
 
-	data, err := json.Marshal(models.UserMailItem{
-		UserEventType: models.UserEventTypeEmailVerification,
-		Receivers:     []string{newUser.Email},
-		Link:          "example.com/verify",
-	})
-	if err != nil {
-		return 0, fmt.Errorf("[RegisterUser] marshal email verification letter mssg:%w", err)
-	}
-
-	requestID, _ := ctxutil.GetRequestIDFromContext(ctx)
-	err = s.userRabbitProducer.Publish(data, requestID)
+	// отправляем письмо со ссылкой для подтверждения email
+	err = s.sendVerificationEmail(ctx, userID, newUser.Email)
 	if err != nil {
-		return 0, fmt.Errorf("[RegisterUser] publish email verification letter mssg:%w", err)
+		return 0, fmt.Errorf("[RegisterUser] send email verification letter:%w", err)
 	}
 
 	// возвращаем данные в слой хэндлера
@@ -94,20 +146,31 @@ func (s *UserService) UpdateUser(ctx context.Context, updatedUser *models.UserDT
 	// Проверка наличия пользователя.
 	existingUser, err := s.userRepo.GetUserByID(ctx, updatedUser.ID)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, appErrors.ErrNotFound
+		}
 		return nil, fmt.Errorf("[UpdateUser] get user:%w", err)
 	}
 
-	// Обновление информации о пользователе.
-	existingUser.Username = updatedUser.Username
+	// Обновление информации о пользователе. Поля, которые не переданы, остаются прежними
+	if err := s.applyProfile(ctx, existingUser, updatedUser); err != nil {
+		return nil, fmt.Errorf("[UpdateUser] %w", err)
+	}
 
 	// Передаем данные в слой репозитория
 	err = s.userRepo.UpdateUser(ctx, existingUser)
 	if err != nil {
 		return nil, fmt.Errorf("[UpdateUser] update user:%w", err)
 	}
+	s.publishUserUpdated(ctx, existingUser.ID)
 
 	// возвращаем данные в слой хэндлера
-	return updatedUser, nil
+	userResponse, err := s.userResponse(ctx, existingUser)
+	if err != nil {
+		return nil, fmt.Errorf("[UpdateUser] %w", err)
+	}
+
+	return userResponse, nil
 }
 
 func (s *UserService) UpdatePassword(ctx context.Context, request *models.UpdateUserPasswordDTO) error {
@@ -134,6 +197,11 @@ func (s *UserService) UpdatePassword(ctx context.Context, request *models.Update
 		return fmt.Errorf("[UpdatePassword] verify pass:%w", appErrors.ErrIncorrectOldPassword)
 	}
 
+	// проверяем новый пароль по требованиям из настроек, в том числе на повтор прежних паролей
+	if err := s.checkPassword(ctx, existingUser.ID, request.Password, existingUser.Username, existingUser.Email); err != nil {
+		return fmt.Errorf("[UpdatePassword] check pass: %w", err)
+	}
+
 	// Хеширование пароля.
 	hashedPassword, err := GeneratePassword(ctx, s.passConfig, request.Password)
 	if err != nil {
@@ -145,43 +213,31 @@ func (s *UserService) UpdatePassword(ctx context.Context, request *models.Update
 	if err != nil {
 		return fmt.Errorf("[UpdatePassword] verify pass:%w", err)
 	}
+	s.prunePasswordHistory(ctx, request.ID)
 
 	// возвращение ответа
 	return nil
 }
 
-func (s *UserService) DeleteUser(ctx context.Context, userID int) error {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "service.DeleteUser")
-	defer span.Finish()
-
-	// Удаление пользователя.
-	err := s.userRepo.DeleteUser(ctx, userID)
-	if err != nil {
-		return fmt.Errorf("[DeleteUser] delete user:%w", err)
-	}
-
-	return nil
-}
-
 func (s *UserService) GetUserByID(ctx context.Context, userID int) (*models.UserDTO, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "service.GetUserByID")
 	defer span.Finish()
 
 	// Получение пользователя по его идентификатору.
-	var userResponse = new(models.UserDTO)
 	storedUser, err := s.userRepo.GetUserByID(ctx, userID)
 	if err != nil {
-		if errors.As(err, &sql.ErrNoRows) {
+		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, appErrors.ErrNotFound
 		}
-		return userResponse, fmt.Errorf("[GetUserByID] get user:%w", err)
+		return new(models.UserDTO), fmt.Errorf("[GetUserByID] get user:%w", err)
 	}
 
 	// запись данных из DAO - data access object через которую мы работаем с базой данных
 	// в DTO - data transfer object, который мы возвращаем пользователю
-	userResponse.ID = storedUser.ID
-	userResponse.Username = storedUser.Username
-	userResponse.Email = storedUser.Email
+	userResponse, err := s.userResponse(ctx, storedUser)
+	if err != nil {
+		return nil, fmt.Errorf("[GetUserByID] %w", err)
+	}
 
 	// возврат данных пользователю
 	return userResponse, nil
@@ -195,7 +251,7 @@ func (s *UserService) GetUserByUsernameOrEmail(ctx context.Context, name, email
 	var userResponse = new(models.UserDTO)
 	storedUser, err := s.userRepo.GetUserByUsernameOrEmail(ctx, name, email)
 	if err != nil {
-		if errors.As(err, &sql.ErrNoRows) {
+		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, appErrors.ErrNotFound
 		}
 		return userResponse, fmt.Errorf("[GetUserByID] get user:%w", err)
@@ -206,6 +262,9 @@ func (s *UserService) GetUserByUsernameOrEmail(ctx context.Context, name, email
 	userResponse.ID = storedUser.ID
 	userResponse.Username = storedUser.Username
 	userResponse.Email = storedUser.Email
+	userResponse.EmailVerified = storedUser.EmailVerified
+	userResponse.Role = storedUser.Role
+	s.setProfile(userResponse, storedUser)
 
 	// возврат данных пользователю
 	return userResponse, nil
@@ -215,30 +274,89 @@ func (s *UserService) Login(ctx context.Context, login *models.UserLoginDTO) (*m
 	span, ctx := opentracing.StartSpanFromContext(ctx, "service.Login")
 	defer span.Finish()
 
+	// Адрес, с которого перебирают пароли, ограничивается независимо от того, какие аккаунты он пробует.
+	// Попытка занята, пока ее результат не учтен
+	if err := s.reserveLoginAttempt(ctx, models.LoginThrottleKindIP, login.ClientIP, s.throttleConfig.IPFreeAttempts); err != nil {
+		return nil, fmt.Errorf("[Login] %w", err)
+	}
+	defer s.releaseLoginAttempt(ctx, models.LoginThrottleKindIP, login.ClientIP)
+
 	// Проверка наличия пользователя.
 	existingUser, err := s.userRepo.GetUserByUsernameOrEmail(ctx, login.Username, login.Email)
 	if err != nil {
-		if errors.As(err, &sql.ErrNoRows) {
+		if errors.Is(err, pgx.ErrNoRows) {
+			if err := s.registerIPLoginFailure(ctx, login.ClientIP); err != nil {
+				return nil, fmt.Errorf("[Login] %w", err)
+			}
 			return nil, appErrors.ErrWrongCredentials
 		}
 		return nil, fmt.Errorf("[Login] get user: %w", err)
 	}
 
+	userKey := strconv.Itoa(existingUser.ID)
+	if err := s.reserveLoginAttempt(ctx, models.LoginThrottleKindUser, userKey, s.throttleConfig.FreeAttempts); err != nil {
+		return nil, fmt.Errorf("[Login] %w", err)
+	}
+	userAttempt := true
+	defer func() {
+		if userAttempt {
+			s.releaseLoginAttempt(ctx, models.LoginThrottleKindUser, userKey)
+		}
+	}()
+
 	// Проверка пароля
 	passMatch, err := ComparePassword(ctx, login.Password, existingUser.Password)
 	if err != nil {
 		return nil, fmt.Errorf("[Login] verify pass:%w", err)
 	}
 	if !passMatch {
+		if err := s.registerUserLoginFailure(ctx, existingUser); err != nil {
+			return nil, fmt.Errorf("[Login] %w", err)
+		}
+		if err := s.registerIPLoginFailure(ctx, login.ClientIP); err != nil {
+			return nil, fmt.Errorf("[Login] %w", err)
+		}
 		return nil, fmt.Errorf("[Login] verify pass:%w", appErrors.ErrWrongCredentials)
 	}
 
-	// возврат данных пользователю
-	return &models.UserDTO{
-		ID:       existingUser.ID,
-		Username: existingUser.Username,
-		Email:    existingUser.Email,
-	}, nil
+	// верный пароль обнуляет счетчик неудач аккаунта, счетчик адреса остается
+	if err := s.userRepo.ResetLoginThrottle(ctx, models.LoginThrottleKindUser, userKey); err != nil {
+		return nil, fmt.Errorf("[Login] reset throttle: %w", err)
+	}
+	// счетчик удален вместе с занятой попыткой
+	userAttempt = false
+
+	// хэш со старыми параметрами или в формате bcrypt заменяется без сброса пароля
+	if PasswordNeedsRehash(s.passConfig, existingUser.Password) {
+		s.rehashPassword(ctx, existingUser, login.Password)
+	}
+
+	// проверяется только после пароля, чтобы не раскрывать статус чужого адреса
+	if s.verifyConfig.RequireVerifiedLogin && !existingUser.EmailVerified {
+		return nil, fmt.Errorf("[Login] %w", appErrors.ErrEmailNotVerified)
+	}
+
+	// при включенном втором факторе вместо данных пользователя возвращается токен второго шага
+	challenge, err := s.startTwoFactorLogin(ctx, existingUser.ID)
+	if err != nil {
+		return nil, fmt.Errorf("[Login] %w", err)
+	}
+	if challenge != nil {
+		return challenge, nil
+	}
+
+	// возврат данных пользователю вместе с разрешениями, которые попадут в токен
+	userResponse, err := s.userResponse(ctx, existingUser)
+	if err != nil {
+		return nil, fmt.Errorf("[Login] %w", err)
+	}
+
+	return userResponse, nil
+}
+
+// normalizeEmail приводит email к виду, в котором он хранится: без пробелов по краям и в нижнем регистре
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
 }
 
 // GeneratePassword создает пароль на основе библиотеки golang.org/x/crypto/argon2
@@ -262,31 +380,101 @@ func GeneratePassword(ctx context.Context, c *config.PasswordConfig, password st
 	return full, nil
 }
 
-// ComparePassword сравниваем пароль и переданный хэш пароля на основе библиотеки golang.org/x/crypto/argon2
+// ComparePassword сравнивает пароль с хэшем. Кроме argon2id поддерживаются хэши bcrypt, оставшиеся
+// от прежней схемы хранения, формат определяется по префиксу хэша. Хэш в неизвестном или поврежденном формате
+// возвращает ErrMalformedPasswordHash
 func ComparePassword(ctx context.Context, password, hash string) (bool, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "ComparePassword")
 	defer span.Finish()
 
+	if isBcryptHash(hash) {
+		err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("%w: %v", appErrors.ErrMalformedPasswordHash, err)
+		}
+		return true, nil
+	}
+
+	c, salt, decodedHash, err := parseArgon2Hash(hash)
+	if err != nil {
+		return false, err
+	}
+
+	comparisonHash := argon2.IDKey([]byte(password), salt, c.Time, c.Memory, c.Threads, c.KeyLen)
+
+	return subtle.ConstantTimeCompare(decodedHash, comparisonHash) == 1, nil
+}
+
+// PasswordNeedsRehash сообщает, что хэш нужно пересчитать: он в устаревшем формате или посчитан
+// с параметрами слабее текущих. Более сильные параметры не понижаются
+func PasswordNeedsRehash(c *config.PasswordConfig, hash string) bool {
+	if isBcryptHash(hash) {
+		return true
+	}
+
+	current, _, _, err := parseArgon2Hash(hash)
+	if err != nil {
+		return false
+	}
+
+	return current.Memory < c.Memory || current.Time < c.Time || current.Threads < c.Threads || current.KeyLen < c.KeyLen
+}
+
+// isBcryptHash - хэш в формате bcrypt: $2a$, $2b$ или $2y$
+func isBcryptHash(hash string) bool {
+	for _, prefix := range []string{"$2a$", "$2b$", "$2y$"} {
+		if strings.HasPrefix(hash, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseArgon2Hash разбирает хэш вида $argon2id$v=19$m=65536,t=1,p=4$<salt>$<hash> на параметры, соль и сам хэш
+func parseArgon2Hash(hash string) (*config.PasswordConfig, []byte, []byte, error) {
 	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[0] != "" || parts[1] != "argon2id" {
+		return nil, nil, nil, appErrors.ErrMalformedPasswordHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return nil, nil, nil, appErrors.ErrMalformedPasswordHash
+	}
 
 	c := &config.PasswordConfig{}
 	_, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &c.Memory, &c.Time, &c.Threads)
-	if err != nil {
-		return false, err
+	// argon2 паникует при нулевом числе проходов или потоков
+	if err != nil || c.Time == 0 || c.Threads == 0 {
+		return nil, nil, nil, appErrors.ErrMalformedPasswordHash
 	}
 
 	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
 	if err != nil {
-		return false, err
+		return nil, nil, nil, fmt.Errorf("%w: salt: %v", appErrors.ErrMalformedPasswordHash, err)
 	}
 
 	decodedHash, err := base64.RawStdEncoding.DecodeString(parts[5])
-	if err != nil {
-		return false, err
+	if err != nil || len(decodedHash) == 0 {
+		return nil, nil, nil, appErrors.ErrMalformedPasswordHash
 	}
 	c.KeyLen = uint32(len(decodedHash))
 
-	comparisonHash := argon2.IDKey([]byte(password), salt, c.Time, c.Memory, c.Threads, c.KeyLen)
+	return c, salt, decodedHash, nil
+}
 
-	return subtle.ConstantTimeCompare(decodedHash, comparisonHash) == 1, nil
+// rehashPassword пересчитывает хэш с текущими параметрами, пока известен пароль. Хэш заменяется, только если
+// он не изменился с момента проверки, поэтому одновременная смена пароля не откатывается. Ошибка не мешает
+// входу: хэш обновится при следующем входе
+func (s *UserService) rehashPassword(ctx context.Context, user *models.UserDAO, password string) {
+	hashedPassword, err := GeneratePassword(ctx, s.passConfig, password)
+	if err != nil {
+		return
+	}
+
+	_ = s.userRepo.ReplacePasswordHash(ctx, user.ID, user.Password, hashedPassword)
 }
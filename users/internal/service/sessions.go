@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go"
+	"users/internal/models"
+)
+
+// CreateSession заводит сессию после успешного входа. Идентификатор сессии выдается здесь,
+// идентификатор refresh токена - тем, кто его подписывает.
+func (s *UserService) CreateSession(ctx context.Context, session *models.SessionDTO) (*models.SessionDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.CreateSession")
+	defer span.Finish()
+
+	session.ID = uuid.New()
+
+	createdSession, err := s.userRepo.CreateSession(ctx, session)
+	if err != nil {
+		return nil, fmt.Errorf("[CreateSession] create session: %w", err)
+	}
+
+	return createdSession.ToDTO(), nil
+}
+
+// RotateSession заменяет refresh токен сессии. Повторное предъявление уже замененного токена
+// завершает сессию и возвращает ErrRefreshTokenReused.
+func (s *UserService) RotateSession(ctx context.Context, rotate *models.RotateSessionDTO) (*models.SessionDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.RotateSession")
+	defer span.Finish()
+
+	rotatedSession, err := s.userRepo.RotateSession(ctx, rotate)
+	if err != nil {
+		return nil, fmt.Errorf("[RotateSession] rotate session: %w", err)
+	}
+
+	return rotatedSession.ToDTO(), nil
+}
+
+func (s *UserService) RevokeSession(ctx context.Context, sessionID uuid.UUID, userID int) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.RevokeSession")
+	defer span.Finish()
+
+	err := s.userRepo.RevokeSession(ctx, sessionID, userID)
+	if err != nil {
+		return fmt.Errorf("[RevokeSession] revoke session: %w", err)
+	}
+
+	return nil
+}
+
+func (s *UserService) RevokeUserSessions(ctx context.Context, userID int) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.RevokeUserSessions")
+	defer span.Finish()
+
+	err := s.userRepo.RevokeUserSessions(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("[RevokeUserSessions] revoke sessions: %w", err)
+	}
+
+	return nil
+}
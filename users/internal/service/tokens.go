@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"users/internal/models"
+	"users/pkg/ctxutil"
+)
+
+// secretTokenSize - количество случайных байт в одноразовых токенах из писем
+const secretTokenSize = 32
+
+// newSecretToken создает случайный одноразовый токен для ссылки в письме
+func newSecretToken() (string, error) {
+	raw := make([]byte, secretTokenSize)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// hashSecretToken возвращает хэш токена, который хранится в базе вместо самого токена
+func hashSecretToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// tokenLink добавляет токен параметром token к адресу страницы из настроек
+func tokenLink(baseURL, token string) (string, error) {
+	link, err := url.Parse(baseURL)
+	if err != nil {
+		return "", err
+	}
+
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+
+	return link.String(), nil
+}
+
+// publishUserMail отправляет письмо пользователю через сервис уведомлений
+func (s *UserService) publishUserMail(ctx context.Context, item *models.UserMailItem) error {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return fmt.Errorf("[publishUserMail] marshal mssg: %w", err)
+	}
+
+	requestID, _ := ctxutil.GetRequestIDFromContext(ctx)
+	err = s.userRabbitProducer.Publish(data, requestID)
+	if err != nil {
+		return fmt.Errorf("[publishUserMail] publish mssg: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,323 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"github.com/opentracing/opentracing-go"
+	"strings"
+	"time"
+	appErrors "users/internal/app_errors"
+	"users/internal/models"
+	"users/pkg/totp"
+)
+
+// recoveryCodeSize - количество случайных байт в одном коде восстановления
+const recoveryCodeSize = 5
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// EnrollTwoFactor выдает новый секрет для приложения-аутентификатора. Второй фактор
+// начинает требоваться при входе только после подтверждения кодом из приложения.
+func (s *UserService) EnrollTwoFactor(ctx context.Context, userID int) (*models.TwoFactorEnrollmentDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.EnrollTwoFactor")
+	defer span.Finish()
+
+	existingUser, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("[EnrollTwoFactor] get user: %w", err)
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, fmt.Errorf("[EnrollTwoFactor] generate secret: %w", err)
+	}
+
+	err = s.userRepo.SaveTwoFactorSecret(ctx, userID, secret)
+	if err != nil {
+		return nil, fmt.Errorf("[EnrollTwoFactor] store secret: %w", err)
+	}
+
+	return &models.TwoFactorEnrollmentDTO{
+		Secret:     secret,
+		OtpauthURI: totp.URI(s.twoFactorConfig.Issuer, existingUser.Email, secret),
+	}, nil
+}
+
+// ConfirmTwoFactor включает второй фактор по первому коду из приложения и возвращает коды восстановления
+func (s *UserService) ConfirmTwoFactor(ctx context.Context, request *models.TwoFactorCodeDTO) (*models.RecoveryCodesDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.ConfirmTwoFactor")
+	defer span.Finish()
+
+	twoFactor, err := s.userRepo.GetTwoFactor(ctx, request.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("[ConfirmTwoFactor] get two factor: %w", err)
+	}
+	if twoFactor.Enabled() {
+		return nil, fmt.Errorf("[ConfirmTwoFactor] %w", appErrors.ErrTwoFactorAlreadyEnabled)
+	}
+
+	step, ok := totp.Validate(twoFactor.Secret, request.Code, time.Now())
+	if !ok {
+		return nil, fmt.Errorf("[ConfirmTwoFactor] %w", appErrors.ErrInvalidTwoFactorCode)
+	}
+
+	codes, hashes, err := s.newRecoveryCodes()
+	if err != nil {
+		return nil, fmt.Errorf("[ConfirmTwoFactor] generate recovery codes: %w", err)
+	}
+
+	err = s.userRepo.ConfirmTwoFactor(ctx, request.UserID, step, hashes)
+	if err != nil {
+		return nil, fmt.Errorf("[ConfirmTwoFactor] confirm: %w", err)
+	}
+
+	return &models.RecoveryCodesDTO{Codes: codes}, nil
+}
+
+// DisableTwoFactor отключает второй фактор. Подтвердить действие можно кодом из приложения или кодом восстановления.
+// Ролям из RequiredRoles отключать второй фактор нельзя.
+func (s *UserService) DisableTwoFactor(ctx context.Context, request *models.TwoFactorCodeDTO) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.DisableTwoFactor")
+	defer span.Finish()
+
+	existingUser, err := s.userRepo.GetUserByID(ctx, request.UserID)
+	if err != nil {
+		return fmt.Errorf("[DisableTwoFactor] get user: %w", err)
+	}
+	if s.roleRequiresTwoFactor(existingUser.Role) {
+		return fmt.Errorf("[DisableTwoFactor] %w", appErrors.ErrTwoFactorRequiredByRole)
+	}
+
+	twoFactor, err := s.enabledTwoFactor(ctx, request.UserID)
+	if err != nil {
+		return fmt.Errorf("[DisableTwoFactor] %w", err)
+	}
+
+	err = s.checkSecondFactor(ctx, twoFactor, request.Code, true)
+	if err != nil {
+		return fmt.Errorf("[DisableTwoFactor] %w", err)
+	}
+
+	err = s.userRepo.DeleteTwoFactor(ctx, request.UserID)
+	if err != nil {
+		return fmt.Errorf("[DisableTwoFactor] delete: %w", err)
+	}
+
+	return nil
+}
+
+// RegenerateRecoveryCodes выдает новый набор кодов восстановления, старые коды перестают работать.
+// Подтвердить действие можно только кодом из приложения.
+func (s *UserService) RegenerateRecoveryCodes(ctx context.Context, request *models.TwoFactorCodeDTO) (*models.RecoveryCodesDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.RegenerateRecoveryCodes")
+	defer span.Finish()
+
+	twoFactor, err := s.enabledTwoFactor(ctx, request.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("[RegenerateRecoveryCodes] %w", err)
+	}
+
+	err = s.checkSecondFactor(ctx, twoFactor, request.Code, false)
+	if err != nil {
+		return nil, fmt.Errorf("[RegenerateRecoveryCodes] %w", err)
+	}
+
+	codes, hashes, err := s.newRecoveryCodes()
+	if err != nil {
+		return nil, fmt.Errorf("[RegenerateRecoveryCodes] generate recovery codes: %w", err)
+	}
+
+	err = s.userRepo.ReplaceRecoveryCodes(ctx, request.UserID, hashes)
+	if err != nil {
+		return nil, fmt.Errorf("[RegenerateRecoveryCodes] store: %w", err)
+	}
+
+	return &models.RecoveryCodesDTO{Codes: codes}, nil
+}
+
+// LoginTwoFactor завершает вход: проверяет код для токена, выданного Login, и возвращает пользователя.
+// После MaxAttempts неверных кодов токен перестает приниматься, и вход нужно начинать заново.
+func (s *UserService) LoginTwoFactor(ctx context.Context, request *models.TwoFactorLoginDTO) (*models.UserDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.LoginTwoFactor")
+	defer span.Finish()
+
+	token := strings.TrimSpace(request.ChallengeToken)
+	if token == "" {
+		return nil, fmt.Errorf("[LoginTwoFactor] %w", appErrors.ErrInvalidTwoFactorChallenge)
+	}
+	tokenHash := hashSecretToken(token)
+
+	userID, err := s.userRepo.GetTwoFactorChallenge(ctx, tokenHash, s.twoFactorConfig.MaxAttempts)
+	if err != nil {
+		return nil, fmt.Errorf("[LoginTwoFactor] get challenge: %w", err)
+	}
+
+	twoFactor, err := s.enabledTwoFactor(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("[LoginTwoFactor] %w", err)
+	}
+
+	err = s.checkSecondFactor(ctx, twoFactor, request.Code, true)
+	if err != nil {
+		if errors.Is(err, appErrors.ErrInvalidTwoFactorCode) {
+			if failErr := s.userRepo.FailTwoFactorChallenge(ctx, tokenHash); failErr != nil {
+				return nil, fmt.Errorf("[LoginTwoFactor] count attempt: %w", failErr)
+			}
+		}
+		return nil, fmt.Errorf("[LoginTwoFactor] %w", err)
+	}
+
+	err = s.userRepo.ConsumeTwoFactorChallenge(ctx, tokenHash)
+	if err != nil {
+		return nil, fmt.Errorf("[LoginTwoFactor] consume challenge: %w", err)
+	}
+
+	existingUser, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("[LoginTwoFactor] get user: %w", err)
+	}
+
+	userResponse, err := s.userResponse(ctx, existingUser)
+	if err != nil {
+		return nil, fmt.Errorf("[LoginTwoFactor] %w", err)
+	}
+
+	return userResponse, nil
+}
+
+// startTwoFactorLogin выдает токен второго шага входа. Если второй фактор не включен, возвращает nil.
+func (s *UserService) startTwoFactorLogin(ctx context.Context, userID int) (*models.UserDTO, error) {
+	twoFactor, err := s.userRepo.GetTwoFactor(ctx, userID)
+	if err != nil {
+		if errors.Is(err, appErrors.ErrTwoFactorNotEnabled) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("[startTwoFactorLogin] get two factor: %w", err)
+	}
+	if !twoFactor.Enabled() {
+		return nil, nil
+	}
+
+	token, err := newSecretToken()
+	if err != nil {
+		return nil, fmt.Errorf("[startTwoFactorLogin] generate token: %w", err)
+	}
+
+	err = s.userRepo.CreateTwoFactorChallenge(ctx, userID, hashSecretToken(token), s.twoFactorConfig.ChallengeTTL)
+	if err != nil {
+		return nil, fmt.Errorf("[startTwoFactorLogin] store token: %w", err)
+	}
+
+	return &models.UserDTO{
+		TwoFactorRequired: true,
+		ChallengeToken:    token,
+	}, nil
+}
+
+// roleRequiresTwoFactor сообщает, нужен ли роли второй фактор
+func (s *UserService) roleRequiresTwoFactor(role string) bool {
+	for _, required := range s.twoFactorConfig.RequiredRoles {
+		if strings.EqualFold(strings.TrimSpace(required), role) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// missingRequiredTwoFactor сообщает, что роль пользователя требует второй фактор, а он не включен.
+// Такому пользователю разрешения роли не выдаются, пока он не подключит второй фактор
+func (s *UserService) missingRequiredTwoFactor(ctx context.Context, user *models.UserDAO) (bool, error) {
+	if !s.roleRequiresTwoFactor(user.Role) {
+		return false, nil
+	}
+
+	twoFactor, err := s.userRepo.GetTwoFactor(ctx, user.ID)
+	if err != nil {
+		if errors.Is(err, appErrors.ErrTwoFactorNotEnabled) {
+			return true, nil
+		}
+		return false, fmt.Errorf("get two factor: %w", err)
+	}
+
+	return !twoFactor.Enabled(), nil
+}
+
+func (s *UserService) enabledTwoFactor(ctx context.Context, userID int) (*models.TwoFactorDAO, error) {
+	twoFactor, err := s.userRepo.GetTwoFactor(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get two factor: %w", err)
+	}
+	if !twoFactor.Enabled() {
+		return nil, appErrors.ErrTwoFactorNotEnabled
+	}
+
+	return twoFactor, nil
+}
+
+// checkSecondFactor проверяет код из приложения, а при allowRecovery еще и код восстановления.
+// Коды из приложения отличаются по длине: в них ровно totp.Digits цифр.
+func (s *UserService) checkSecondFactor(ctx context.Context, twoFactor *models.TwoFactorDAO, code string, allowRecovery bool) error {
+	code = strings.TrimSpace(code)
+
+	if len(code) == totp.Digits {
+		step, ok := totp.Validate(twoFactor.Secret, code, time.Now())
+		if !ok {
+			return appErrors.ErrInvalidTwoFactorCode
+		}
+
+		// код, которым уже воспользовались, повторно не принимается
+		used, err := s.userRepo.UseTwoFactorStep(ctx, twoFactor.UserID, step)
+		if err != nil {
+			return fmt.Errorf("use code: %w", err)
+		}
+		if !used {
+			return appErrors.ErrInvalidTwoFactorCode
+		}
+
+		return nil
+	}
+
+	if !allowRecovery || code == "" {
+		return appErrors.ErrInvalidTwoFactorCode
+	}
+
+	used, err := s.userRepo.UseRecoveryCode(ctx, twoFactor.UserID, hashSecretToken(normalizeRecoveryCode(code)))
+	if err != nil {
+		return fmt.Errorf("use recovery code: %w", err)
+	}
+	if !used {
+		return appErrors.ErrInvalidTwoFactorCode
+	}
+
+	return nil
+}
+
+// newRecoveryCodes создает коды восстановления вида "xxxx-xxxx" и их хэши для хранения в базе
+func (s *UserService) newRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, s.twoFactorConfig.RecoveryCodes)
+	hashes := make([]string, 0, s.twoFactorConfig.RecoveryCodes)
+
+	for i := 0; i < s.twoFactorConfig.RecoveryCodes; i++ {
+		raw := make([]byte, recoveryCodeSize)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, nil, err
+		}
+
+		code := strings.ToLower(recoveryCodeEncoding.EncodeToString(raw))
+		codes = append(codes, code[:4]+"-"+code[4:])
+		hashes = append(hashes, hashSecretToken(code))
+	}
+
+	return codes, hashes, nil
+}
+
+// normalizeRecoveryCode приводит введенный код к виду, от которого считается хэш
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	code = strings.ReplaceAll(code, "-", "")
+	return strings.ReplaceAll(code, " ", "")
+}
@@ -0,0 +1,246 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"users/config"
+	appErrors "users/internal/app_errors"
+	"users/internal/models"
+	"users/pkg/totp"
+)
+
+// twoFactorRepo повторяет условия репозитория: шаг принимается, только если он позже последнего
+// использованного, а код восстановления гасится один раз
+type twoFactorRepo struct {
+	UserRepository
+	lastUsedStep  int64
+	recoveryCodes map[string]bool
+	stepCalls     int
+}
+
+func (r *twoFactorRepo) UseTwoFactorStep(_ context.Context, _ int, step int64) (bool, error) {
+	r.stepCalls++
+	if step <= r.lastUsedStep {
+		return false, nil
+	}
+	r.lastUsedStep = step
+	return true, nil
+}
+
+func (r *twoFactorRepo) UseRecoveryCode(_ context.Context, _ int, codeHash string) (bool, error) {
+	used, ok := r.recoveryCodes[codeHash]
+	if !ok || used {
+		return false, nil
+	}
+	r.recoveryCodes[codeHash] = true
+	return true, nil
+}
+
+func TestCheckSecondFactor(t *testing.T) {
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret error: %v", err)
+	}
+
+	// коды считаются заранее, поэтому смена шага посреди теста сделала бы предыдущий код недействительным
+	now := time.Now()
+	if left := totp.Period - now.Unix()%totp.Period; left < 3 {
+		time.Sleep(time.Duration(left) * time.Second)
+		now = time.Now()
+	}
+	current, err := totp.Code(secret, now)
+	if err != nil {
+		t.Fatalf("Code error: %v", err)
+	}
+	previous, err := totp.Code(secret, now.Add(-totp.Period*time.Second))
+	if err != nil {
+		t.Fatalf("Code error: %v", err)
+	}
+	wrong := "000000"
+	if wrong == current || wrong == previous {
+		wrong = "999999"
+	}
+
+	type attempt struct {
+		code          string
+		allowRecovery bool
+		wantErr       error
+	}
+
+	tests := []struct {
+		name          string
+		attempts      []attempt
+		wantStepCalls int
+	}{
+		{
+			name:          "valid code",
+			attempts:      []attempt{{current, false, nil}},
+			wantStepCalls: 1,
+		},
+		{
+			name:          "code with spaces around",
+			attempts:      []attempt{{" " + current + "\n", false, nil}},
+			wantStepCalls: 1,
+		},
+		{
+			name: "same code twice",
+			attempts: []attempt{
+				{current, false, nil},
+				{current, false, appErrors.ErrInvalidTwoFactorCode},
+			},
+			wantStepCalls: 2,
+		},
+		{
+			name: "earlier step after a later one",
+			attempts: []attempt{
+				{current, false, nil},
+				{previous, false, appErrors.ErrInvalidTwoFactorCode},
+			},
+			wantStepCalls: 2,
+		},
+		{
+			name:          "wrong code does not spend a step",
+			attempts:      []attempt{{wrong, false, appErrors.ErrInvalidTwoFactorCode}, {current, false, nil}},
+			wantStepCalls: 1,
+		},
+		{
+			name:     "empty code",
+			attempts: []attempt{{"  ", true, appErrors.ErrInvalidTwoFactorCode}},
+		},
+		{
+			name:     "recovery code when it is not allowed",
+			attempts: []attempt{{"abcd-efgh-ijkl", false, appErrors.ErrInvalidTwoFactorCode}},
+		},
+		{
+			name:     "recovery code ignores case and separators",
+			attempts: []attempt{{"ABCD EFGH-ijkl", true, nil}},
+		},
+		{
+			name: "recovery code twice",
+			attempts: []attempt{
+				{"abcd-efgh-ijkl", true, nil},
+				{"abcd-efgh-ijkl", true, appErrors.ErrInvalidTwoFactorCode},
+			},
+		},
+		{
+			name:     "unknown recovery code",
+			attempts: []attempt{{"zzzz-zzzz-zzzz", true, appErrors.ErrInvalidTwoFactorCode}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &twoFactorRepo{
+				recoveryCodes: map[string]bool{hashSecretToken("abcdefghijkl"): false},
+			}
+			s := &UserService{userRepo: repo}
+			twoFactor := &models.TwoFactorDAO{UserID: 1, Secret: secret}
+
+			for i, a := range tt.attempts {
+				err := s.checkSecondFactor(context.Background(), twoFactor, a.code, a.allowRecovery)
+				if !errors.Is(err, a.wantErr) {
+					t.Errorf("attempt %d checkSecondFactor(%q) error = %v, want %v", i+1, a.code, err, a.wantErr)
+				}
+			}
+			if repo.stepCalls != tt.wantStepCalls {
+				t.Errorf("UseTwoFactorStep calls = %d, want %d", repo.stepCalls, tt.wantStepCalls)
+			}
+		})
+	}
+}
+
+// requiredTwoFactorRepo отдает пользователя с ролью и его второй фактор. Остальные методы не вызываются
+type requiredTwoFactorRepo struct {
+	UserRepository
+	user      *models.UserDAO
+	twoFactor *models.TwoFactorDAO
+	deleted   bool
+}
+
+func (r *requiredTwoFactorRepo) GetUserByID(_ context.Context, _ int) (*models.UserDAO, error) {
+	return r.user, nil
+}
+
+func (r *requiredTwoFactorRepo) GetTwoFactor(_ context.Context, _ int) (*models.TwoFactorDAO, error) {
+	if r.twoFactor == nil {
+		return nil, appErrors.ErrTwoFactorNotEnabled
+	}
+	return r.twoFactor, nil
+}
+
+func (r *requiredTwoFactorRepo) GetRolePermissions(_ context.Context, role string) ([]string, error) {
+	return []string{role + ":manage"}, nil
+}
+
+func (r *requiredTwoFactorRepo) GetUserMemberships(_ context.Context, _ int) (*models.MembershipsDAO, error) {
+	return &models.MembershipsDAO{}, nil
+}
+
+func (r *requiredTwoFactorRepo) UseTwoFactorStep(_ context.Context, _ int, _ int64) (bool, error) {
+	return true, nil
+}
+
+func (r *requiredTwoFactorRepo) DeleteTwoFactor(_ context.Context, _ int) error {
+	r.deleted = true
+	return nil
+}
+
+func TestRequiredTwoFactor(t *testing.T) {
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret error: %v", err)
+	}
+	confirmedAt := time.Now()
+
+	tests := []struct {
+		name            string
+		role            string
+		twoFactor       *models.TwoFactorDAO
+		wantPermissions bool
+		wantDisableErr  error
+	}{
+		{"admin without two factor", "admin", nil, false, appErrors.ErrTwoFactorRequiredByRole},
+		{"admin with unconfirmed two factor", "admin", &models.TwoFactorDAO{UserID: 1, Secret: secret}, false, appErrors.ErrTwoFactorRequiredByRole},
+		{"admin with two factor", "admin", &models.TwoFactorDAO{UserID: 1, Secret: secret, ConfirmedAt: &confirmedAt}, true, appErrors.ErrTwoFactorRequiredByRole},
+		{"user without two factor", "user", nil, true, appErrors.ErrTwoFactorNotEnabled},
+		{"user with two factor", "user", &models.TwoFactorDAO{UserID: 1, Secret: secret, ConfirmedAt: &confirmedAt}, true, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &requiredTwoFactorRepo{
+				user:      &models.UserDAO{ID: 1, Username: "alice", Role: tt.role},
+				twoFactor: tt.twoFactor,
+			}
+			s := &UserService{
+				twoFactorConfig: &config.TwoFactorConfig{RequiredRoles: []string{"auditor", " Admin"}},
+				userRepo:        repo,
+			}
+
+			response, err := s.userResponse(context.Background(), repo.user)
+			if err != nil {
+				t.Fatalf("userResponse error: %v", err)
+			}
+			if got := len(response.Permissions) > 0; got != tt.wantPermissions {
+				t.Errorf("permissions = %v, want granted %v", response.Permissions, tt.wantPermissions)
+			}
+			if response.TwoFactorSetupRequired == tt.wantPermissions {
+				t.Errorf("TwoFactorSetupRequired = %v, want %v", response.TwoFactorSetupRequired, !tt.wantPermissions)
+			}
+
+			code, err := totp.Code(secret, time.Now())
+			if err != nil {
+				t.Fatalf("Code error: %v", err)
+			}
+			err = s.DisableTwoFactor(context.Background(), &models.TwoFactorCodeDTO{UserID: 1, Code: code})
+			if !errors.Is(err, tt.wantDisableErr) {
+				t.Errorf("DisableTwoFactor error = %v, want %v", err, tt.wantDisableErr)
+			}
+			if repo.deleted != (tt.wantDisableErr == nil) {
+				t.Errorf("two factor deleted = %v, want %v", repo.deleted, tt.wantDisableErr == nil)
+			}
+		})
+	}
+}
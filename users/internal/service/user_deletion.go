@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go"
+	"strings"
+	appErrors "users/internal/app_errors"
+	"users/internal/models"
+	"users/pkg/ctxutil"
+)
+
+// DeleteUser начинает удаление пользователя: он сразу скрывается и теряет сессии, а сервисам-участникам
+// уходит событие user_deleted. Окончательно пользователь удаляется, когда все участники подтвердят удаление
+func (s *UserService) DeleteUser(ctx context.Context, userID int) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.DeleteUser")
+	defer span.Finish()
+
+	if _, err := s.startUserDeletion(ctx, userID, ""); err != nil {
+		return fmt.Errorf("[DeleteUser] %w", err)
+	}
+
+	return nil
+}
+
+// startUserDeletion запускает удаление пользователя. policy передается сервисам-участникам в событии,
+// пустая policy оставляет обработку данных на их настройки
+func (s *UserService) startUserDeletion(ctx context.Context, userID int, policy string) (*models.UserDeletionDAO, error) {
+	deletion := &models.UserDeletionDAO{
+		ID:     uuid.New(),
+		UserID: userID,
+		Steps:  make([]models.UserDeletionStepDAO, 0, len(s.deletionConfig.Participants)),
+	}
+	for _, participant := range s.deletionConfig.Participants {
+		if participant = strings.TrimSpace(participant); participant != "" {
+			deletion.Steps = append(deletion.Steps, models.UserDeletionStepDAO{Service: participant})
+		}
+	}
+
+	deletion, err := s.userRepo.StartUserDeletion(ctx, deletion)
+	if err != nil {
+		return nil, fmt.Errorf("[startUserDeletion] start deletion: %w", err)
+	}
+
+	// подтверждать удаление некому
+	if len(deletion.Steps) == 0 {
+		if err := s.completeUserDeletion(ctx, deletion); err != nil {
+			return nil, fmt.Errorf("[startUserDeletion] %w", err)
+		}
+		deletion.Status = models.UserDeletionStatusCompleted
+		return deletion, nil
+	}
+
+	if err := s.publishUserDeletionEvent(ctx, models.UserEventTypeUserDeleted, deletion, policy); err != nil {
+		// без события участники не узнают об удалении и не ответят, поэтому пользователь возвращается.
+		// Если вернуть его не удалось, удаление отменит проверка CancelStaleUserDeletions
+		if cancelErr := s.userRepo.CancelUserDeletion(ctx, deletion.ID, "user_deleted event was not published"); cancelErr != nil {
+			return nil, fmt.Errorf("[startUserDeletion] %w (cancel deletion: %v)", err, cancelErr)
+		}
+		return nil, fmt.Errorf("[startUserDeletion] %w", err)
+	}
+
+	return deletion, nil
+}
+
+// GetUserDeletion возвращает состояние последнего удаления пользователя
+func (s *UserService) GetUserDeletion(ctx context.Context, userID int) (*models.UserDeletionDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.GetUserDeletion")
+	defer span.Finish()
+
+	deletion, err := s.userRepo.GetUserDeletion(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("[GetUserDeletion] get deletion: %w", err)
+	}
+
+	return deletion.ToDTO(), nil
+}
+
+// HandleUserDeletionReply записывает ответ сервиса-участника. Когда подтвердили все участники, пользователь
+// удаляется окончательно. Если хотя бы один отказал, удаление отменяется и пользователь возвращается, а участники,
+// которые уже обработали удаление, откатывают его по событию user_deletion_cancelled.
+// На ответ, который пришел после завершения или отмены, исход удаления отправляется участникам еще раз
+func (s *UserService) HandleUserDeletionReply(ctx context.Context, reply *models.UserDeletionReply) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.HandleUserDeletionReply")
+	defer span.Finish()
+
+	step := &models.UserDeletionStepDAO{
+		Service: reply.Service,
+		Error:   reply.Error,
+	}
+	switch reply.EventType {
+	case models.UserEventTypeUserDeletionConfirmed:
+		step.Status = models.UserDeletionStepConfirmed
+	case models.UserEventTypeUserDeletionFailed:
+		step.Status = models.UserDeletionStepFailed
+	default:
+		return fmt.Errorf("[HandleUserDeletionReply] %w: %q", appErrors.ErrUnknownUserDeletionReply, reply.EventType)
+	}
+
+	deletion, err := s.userRepo.SetUserDeletionStep(ctx, reply.DeletionID, step)
+	if err != nil {
+		return fmt.Errorf("[HandleUserDeletionReply] set step: %w", err)
+	}
+
+	switch deletion.Status {
+	// ответ пришел после того, как удаление завершилось или было отменено. Участник мог обработать удаление
+	// уже после отмены, а сообщение об исходе - потеряться, поэтому исход отправляется повторно
+	case models.UserDeletionStatusCompleted:
+		if err := s.publishUserDeletionEvent(ctx, models.UserEventTypeUserDeletionCompleted, deletion, ""); err != nil {
+			return fmt.Errorf("[HandleUserDeletionReply] %w", err)
+		}
+		return nil
+	case models.UserDeletionStatusFailed:
+		if err := s.publishUserDeletionEvent(ctx, models.UserEventTypeUserDeletionCancelled, deletion, ""); err != nil {
+			return fmt.Errorf("[HandleUserDeletionReply] %w", err)
+		}
+		return nil
+	}
+
+	switch deletion.StepsStatus() {
+	case models.UserDeletionStepFailed:
+		if err := s.cancelUserDeletion(ctx, deletion, deletion.FailedStepsError()); err != nil {
+			return fmt.Errorf("[HandleUserDeletionReply] %w", err)
+		}
+	case models.UserDeletionStepConfirmed:
+		if err := s.completeUserDeletion(ctx, deletion); err != nil {
+			return fmt.Errorf("[HandleUserDeletionReply] %w", err)
+		}
+	}
+
+	return nil
+}
+
+// CancelStaleUserDeletions отменяет удаления, на которые сервисы-участники не ответили за время из настроек,
+// в том числе удаления, событие о которых так и не было отправлено. Пользователь возвращается, а участники
+// откатывают то, что успели обработать
+func (s *UserService) CancelStaleUserDeletions(ctx context.Context) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.CancelStaleUserDeletions")
+	defer span.Finish()
+
+	deletions, err := s.userRepo.GetStaleUserDeletions(ctx, s.deletionConfig.Timeout)
+	if err != nil {
+		return fmt.Errorf("[CancelStaleUserDeletions] get deletions: %w", err)
+	}
+
+	reason := fmt.Sprintf("participants did not reply within %s", s.deletionConfig.Timeout)
+	for i := range deletions {
+		if err := s.cancelUserDeletion(ctx, &deletions[i], reason); err != nil {
+			return fmt.Errorf("[CancelStaleUserDeletions] %w", err)
+		}
+	}
+
+	return nil
+}
+
+// cancelUserDeletion отменяет удаление и возвращает пользователя. Участники узнают об отмене до того, как она
+// записана, поэтому если записать ее не удалось, повторная попытка отправит событие еще раз, а не потеряет его
+func (s *UserService) cancelUserDeletion(ctx context.Context, deletion *models.UserDeletionDAO, reason string) error {
+	if err := s.publishUserDeletionEvent(ctx, models.UserEventTypeUserDeletionCancelled, deletion, ""); err != nil {
+		return fmt.Errorf("[cancelUserDeletion] %w", err)
+	}
+
+	if err := s.userRepo.CancelUserDeletion(ctx, deletion.ID, reason); err != nil {
+		return fmt.Errorf("[cancelUserDeletion] cancel deletion: %w", err)
+	}
+
+	return nil
+}
+
+// completeUserDeletion удаляет пользователя окончательно вместе с файлами аватара, после чего участники
+// стирают данные, сохраненные для отката
+func (s *UserService) completeUserDeletion(ctx context.Context, deletion *models.UserDeletionDAO) error {
+	avatarKey, err := s.userRepo.CompleteUserDeletion(ctx, deletion.ID)
+	if err != nil {
+		return fmt.Errorf("[completeUserDeletion] complete deletion: %w", err)
+	}
+
+	s.deleteAvatarFiles(ctx, avatarKey)
+
+	if len(deletion.Steps) == 0 {
+		return nil
+	}
+
+	if err := s.publishUserDeletionEvent(ctx, models.UserEventTypeUserDeletionCompleted, deletion, ""); err != nil {
+		return fmt.Errorf("[completeUserDeletion] %w", err)
+	}
+
+	return nil
+}
+
+// publishUserDeletionEvent отправляет сервисам-участникам событие об удалении пользователя
+func (s *UserService) publishUserDeletionEvent(ctx context.Context, eventType string, deletion *models.UserDeletionDAO, policy string) error {
+	data, err := json.Marshal(models.UserDeletedEvent{
+		EventType:  eventType,
+		DeletionID: deletion.ID,
+		UserID:     deletion.UserID,
+		Policy:     policy,
+	})
+	if err != nil {
+		return fmt.Errorf("marshal %s mssg: %w", eventType, err)
+	}
+
+	requestID, _ := ctxutil.GetRequestIDFromContext(ctx)
+	if err := s.userEventsProducer.Publish(data, requestID); err != nil {
+		return fmt.Errorf("publish %s mssg: %w", eventType, err)
+	}
+
+	return nil
+}
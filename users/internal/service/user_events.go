@@ -0,0 +1,27 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/opentracing/opentracing-go"
+	"users/internal/models"
+	"users/pkg/ctxutil"
+)
+
+// publishUserUpdated сообщает сервисам, что данные пользователя изменились. Ошибка не возвращается:
+// изменение уже сохранено, а закешированный пользователь устареет не дольше, чем на время жизни кеша
+func (s *UserService) publishUserUpdated(ctx context.Context, userID int) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.publishUserUpdated")
+	defer span.Finish()
+
+	data, err := json.Marshal(models.UserUpdatedEvent{
+		EventType: models.UserEventTypeUserUpdated,
+		UserID:    userID,
+	})
+	if err != nil {
+		return
+	}
+
+	requestID, _ := ctxutil.GetRequestIDFromContext(ctx)
+	_ = s.userUpdatesProducer.Publish(data, requestID)
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/opentracing/opentracing-go"
+	"strings"
+	appErrors "users/internal/app_errors"
+	"users/internal/models"
+)
+
+const (
+	defaultUsersPageSize = 20
+	maxUsersPageSize     = 100
+	// maxUserIDs - сколько пользователей можно запросить в GetUsersByIDs или GetUsersByUsernames за раз
+	maxUserIDs = 100
+)
+
+// usersSortColumns - поля, по которым можно сортировать список пользователей
+var usersSortColumns = map[string]string{
+	"id":       "id",
+	"username": "username",
+	"email":    "email",
+}
+
+// ListUsers ищет пользователей по началу username или email. Email участвует в поиске и сортировке
+// и попадает в ответ только с IncludeEmail, иначе о пользователе возвращаются только id и username.
+func (s *UserService) ListUsers(ctx context.Context, filter *models.ListUsersDTO) (*models.UsersPageDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.ListUsers")
+	defer span.Finish()
+
+	filter.Search = strings.TrimSpace(filter.Search)
+	if filter.Limit <= 0 {
+		filter.Limit = defaultUsersPageSize
+	}
+	if filter.Limit > maxUsersPageSize {
+		filter.Limit = maxUsersPageSize
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	sort := filter.Sort
+	if sort == "" {
+		sort = "username"
+	}
+	descending := strings.HasPrefix(sort, "-")
+	sortColumn, ok := usersSortColumns[strings.TrimPrefix(sort, "-")]
+	// по скрытому полю сортировать нельзя: порядок выдал бы email
+	if !ok || (sortColumn == "email" && !filter.IncludeEmail) {
+		return nil, fmt.Errorf("[ListUsers] %w: %q", appErrors.ErrInvalidSort, filter.Sort)
+	}
+
+	storedUsers, total, err := s.userRepo.ListUsers(ctx, filter, sortColumn, descending)
+	if err != nil {
+		return nil, fmt.Errorf("[ListUsers] list users: %w", err)
+	}
+
+	page := &models.UsersPageDTO{
+		Items:  make([]models.UserDTO, len(storedUsers)),
+		Total:  total,
+		Limit:  filter.Limit,
+		Offset: filter.Offset,
+	}
+	for i, storedUser := range storedUsers {
+		page.Items[i] = models.UserDTO{
+			ID:          storedUser.ID,
+			Username:    storedUser.Username,
+			DisplayName: storedUser.DisplayName,
+		}
+		page.Items[i].AvatarURL, page.Items[i].AvatarThumbnailURL = s.avatarURLs(storedUser.AvatarKey)
+		if filter.IncludeEmail {
+			page.Items[i].Email = storedUser.Email
+			page.Items[i].EmailVerified = storedUser.EmailVerified
+			page.Items[i].Role = storedUser.Role
+		}
+	}
+
+	return page, nil
+}
+
+// GetUsersByIDs возвращает пользователей по списку ID одним запросом к базе, повторяющиеся ID учитываются один раз.
+// Пользователей, которых нет или которые удаляются, в ответе нет. Разрешения роли, организации и команды
+// в ответ не попадают, за ними нужно обращаться к GetUserByID
+func (s *UserService) GetUsersByIDs(ctx context.Context, userIDs []int) ([]models.UserDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.GetUsersByIDs")
+	defer span.Finish()
+
+	uniqueIDs := make([]int, 0, len(userIDs))
+	seen := make(map[int]bool, len(userIDs))
+	for _, userID := range userIDs {
+		if seen[userID] {
+			continue
+		}
+		seen[userID] = true
+		uniqueIDs = append(uniqueIDs, userID)
+	}
+
+	if len(uniqueIDs) > maxUserIDs {
+		return nil, fmt.Errorf("[GetUsersByIDs] %w: %d, at most %d", appErrors.ErrTooManyUserIDs, len(uniqueIDs), maxUserIDs)
+	}
+	if len(uniqueIDs) == 0 {
+		return []models.UserDTO{}, nil
+	}
+
+	storedUsers, err := s.userRepo.GetUsersByIDs(ctx, uniqueIDs)
+	if err != nil {
+		return nil, fmt.Errorf("[GetUsersByIDs] get users: %w", err)
+	}
+
+	response := make([]models.UserDTO, len(storedUsers))
+	for i := range storedUsers {
+		response[i] = models.UserDTO{
+			ID:            storedUsers[i].ID,
+			Username:      storedUsers[i].Username,
+			Email:         storedUsers[i].Email,
+			EmailVerified: storedUsers[i].EmailVerified,
+			Role:          storedUsers[i].Role,
+		}
+		s.setProfile(&response[i], &storedUsers[i])
+	}
+
+	return response, nil
+}
+
+// GetUsersByUsernames возвращает пользователей по списку имен одним запросом к базе вместе с разрешениями их ролей,
+// имена сравниваются без учета регистра. Пользователей, которых нет или которые удаляются, в ответе нет
+func (s *UserService) GetUsersByUsernames(ctx context.Context, usernames []string) ([]models.UserDTO, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.GetUsersByUsernames")
+	defer span.Finish()
+
+	uniqueNames := make([]string, 0, len(usernames))
+	seen := make(map[string]bool, len(usernames))
+	for _, username := range usernames {
+		key := strings.ToLower(strings.TrimSpace(username))
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		uniqueNames = append(uniqueNames, key)
+	}
+
+	if len(uniqueNames) > maxUserIDs {
+		return nil, fmt.Errorf("[GetUsersByUsernames] %w: %d, at most %d", appErrors.ErrTooManyUserIDs, len(uniqueNames), maxUserIDs)
+	}
+	if len(uniqueNames) == 0 {
+		return []models.UserDTO{}, nil
+	}
+
+	storedUsers, err := s.userRepo.GetUsersByUsernames(ctx, uniqueNames)
+	if err != nil {
+		return nil, fmt.Errorf("[GetUsersByUsernames] get users: %w", err)
+	}
+
+	// у найденных пользователей обычно одна-две роли, разрешения каждой читаются один раз
+	rolePermissions := make(map[string][]string)
+	response := make([]models.UserDTO, len(storedUsers))
+	for i := range storedUsers {
+		permissions, ok := rolePermissions[storedUsers[i].Role]
+		if !ok {
+			permissions, err = s.userRepo.GetRolePermissions(ctx, storedUsers[i].Role)
+			if err != nil {
+				return nil, fmt.Errorf("[GetUsersByUsernames] get permissions: %w", err)
+			}
+			rolePermissions[storedUsers[i].Role] = permissions
+		}
+
+		response[i] = models.UserDTO{
+			ID:            storedUsers[i].ID,
+			Username:      storedUsers[i].Username,
+			Email:         storedUsers[i].Email,
+			EmailVerified: storedUsers[i].EmailVerified,
+			Role:          storedUsers[i].Role,
+			Permissions:   permissions,
+		}
+		s.setProfile(&response[i], &storedUsers[i])
+	}
+
+	return response, nil
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v4"
+	"github.com/opentracing/opentracing-go"
+	"strings"
+	appErrors "users/internal/app_errors"
+	"users/internal/models"
+)
+
+// VerifyEmail подтверждает email пользователя по токену из письма. Токен одноразовый.
+func (s *UserService) VerifyEmail(ctx context.Context, token string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.VerifyEmail")
+	defer span.Finish()
+
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return fmt.Errorf("[VerifyEmail] %w", appErrors.ErrInvalidVerificationToken)
+	}
+
+	_, err := s.userRepo.ConsumeVerificationToken(ctx, hashSecretToken(token))
+	if err != nil {
+		return fmt.Errorf("[VerifyEmail] consume token: %w", err)
+	}
+
+	return nil
+}
+
+// ResendVerificationEmail повторно отправляет письмо с подтверждением email не чаще, чем раз в ResendInterval.
+// Для неизвестного или уже подтвержденного адреса и слишком частых запросов ничего не отправляется и ошибка не возвращается,
+// чтобы по ответу нельзя было проверить, зарегистрирован ли адрес.
+func (s *UserService) ResendVerificationEmail(ctx context.Context, email string) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "service.ResendVerificationEmail")
+	defer span.Finish()
+
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return nil
+	}
+
+	existingUser, err := s.userRepo.GetUserByUsernameOrEmail(ctx, "", email)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return fmt.Errorf("[ResendVerificationEmail] get user: %w", err)
+	}
+
+	if existingUser.EmailVerified {
+		return nil
+	}
+
+	recentlySent, err := s.userRepo.HasRecentVerificationToken(ctx, existingUser.ID, s.verifyConfig.ResendInterval)
+	if err != nil {
+		return fmt.Errorf("[ResendVerificationEmail] check last token: %w", err)
+	}
+	if recentlySent {
+		// слишком частый запрос не отличается по ответу от запроса для неизвестного адреса
+		span.SetTag("throttled", true)
+		return nil
+	}
+
+	err = s.sendVerificationEmail(ctx, existingUser.ID, existingUser.Email)
+	if err != nil {
+		return fmt.Errorf("[ResendVerificationEmail] send letter: %w", err)
+	}
+
+	return nil
+}
+
+// sendVerificationEmail выдает пользователю новый токен и публикует письмо со ссылкой для подтверждения.
+// В базе хранится только хэш токена, сам токен есть только в письме.
+func (s *UserService) sendVerificationEmail(ctx context.Context, userID int, email string) error {
+	token, err := newSecretToken()
+	if err != nil {
+		return fmt.Errorf("[sendVerificationEmail] generate token: %w", err)
+	}
+
+	err = s.userRepo.CreateVerificationToken(ctx, userID, hashSecretToken(token), s.verifyConfig.TokenTTL)
+	if err != nil {
+		return fmt.Errorf("[sendVerificationEmail] store token: %w", err)
+	}
+
+	link, err := tokenLink(s.verifyConfig.LinkURL, token)
+	if err != nil {
+		return fmt.Errorf("[sendVerificationEmail] build link: %w", err)
+	}
+
+	err = s.publishUserMail(ctx, &models.UserMailItem{
+		UserEventType: models.UserEventTypeEmailVerification,
+		Receivers:     []string{email},
+		Link:          link,
+	})
+	if err != nil {
+		return fmt.Errorf("[sendVerificationEmail] publish mssg: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS sessions (
+    id               UUID      PRIMARY KEY,
+    user_id          INTEGER   NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    refresh_token_id UUID      NOT NULL,
+    created_at       TIMESTAMP NOT NULL DEFAULT now(),
+    refreshed_at     TIMESTAMP NOT NULL DEFAULT now(),
+    expires_at       TIMESTAMP NOT NULL,
+    revoked_at       TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS sessions_user_id_idx ON sessions (user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS sessions;
+-- +goose StatementEnd
@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS two_factor (
+    user_id        INTEGER     PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    secret         VARCHAR(64) NOT NULL,
+    confirmed_at   TIMESTAMP,
+    last_used_step BIGINT      NOT NULL DEFAULT 0,
+    created_at     TIMESTAMP   NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS recovery_codes (
+    id         SERIAL      PRIMARY KEY,
+    user_id    INTEGER     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    code_hash  VARCHAR(64) NOT NULL,
+    used_at    TIMESTAMP,
+    created_at TIMESTAMP   NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS recovery_codes_user_id_idx ON recovery_codes (user_id);
+
+CREATE TABLE IF NOT EXISTS two_factor_challenges (
+    id          SERIAL      PRIMARY KEY,
+    user_id     INTEGER     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    token_hash  VARCHAR(64) NOT NULL UNIQUE,
+    attempts    INTEGER     NOT NULL DEFAULT 0,
+    expires_at  TIMESTAMP   NOT NULL,
+    consumed_at TIMESTAMP,
+    created_at  TIMESTAMP   NOT NULL DEFAULT now()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS two_factor_challenges;
+DROP TABLE IF EXISTS recovery_codes;
+DROP TABLE IF EXISTS two_factor;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS login_throttles (
+    kind            VARCHAR(16)  NOT NULL,
+    key             VARCHAR(255) NOT NULL,
+    failures        INTEGER      NOT NULL DEFAULT 0,
+    last_failure_at TIMESTAMP    NOT NULL DEFAULT now(),
+    blocked_until   TIMESTAMP,
+    locked          BOOLEAN      NOT NULL DEFAULT false,
+    PRIMARY KEY (kind, key)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS login_throttles;
+-- +goose StatementEnd
@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS roles (
+    name        VARCHAR(32)  PRIMARY KEY,
+    description VARCHAR(255) NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role       VARCHAR(32) NOT NULL REFERENCES roles (name) ON DELETE CASCADE,
+    permission VARCHAR(64) NOT NULL,
+    PRIMARY KEY (role, permission)
+);
+
+INSERT INTO roles (name, description)
+VALUES
+    ('user', 'Regular user, manages only own account and todos'),
+    ('auditor', 'Read-only access to all users and todos'),
+    ('admin', 'Full access to all users and todos')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role, permission)
+VALUES
+    ('auditor', 'users:read'),
+    ('auditor', 'todos:read'),
+    ('admin', 'users:read'),
+    ('admin', 'users:manage'),
+    ('admin', 'todos:read'),
+    ('admin', 'todos:manage')
+ON CONFLICT (role, permission) DO NOTHING;
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role VARCHAR(32) NOT NULL DEFAULT 'user' REFERENCES roles (name);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users DROP COLUMN IF EXISTS role;
+DROP TABLE IF EXISTS role_permissions;
+DROP TABLE IF EXISTS roles;
+-- +goose StatementEnd
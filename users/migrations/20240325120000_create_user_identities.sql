@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS user_identities (
+    issuer        VARCHAR(255) NOT NULL,
+    subject       VARCHAR(255) NOT NULL,
+    user_id       INTEGER      NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    email         VARCHAR(255) NOT NULL DEFAULT '',
+    created_at    TIMESTAMP    NOT NULL DEFAULT now(),
+    last_login_at TIMESTAMP    NOT NULL DEFAULT now(),
+    PRIMARY KEY (issuer, subject)
+);
+
+CREATE INDEX IF NOT EXISTS user_identities_user_id_idx ON user_identities (user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS user_identities;
+-- +goose StatementEnd
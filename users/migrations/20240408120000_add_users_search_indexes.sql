@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- поиск пользователей по началу username или email без учета регистра: lower(...) LIKE 'prefix%'
+CREATE INDEX IF NOT EXISTS users_username_lower_prefix_idx ON users (lower(username) text_pattern_ops);
+CREATE INDEX IF NOT EXISTS users_email_lower_prefix_idx ON users (lower(email) text_pattern_ops);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS users_email_lower_prefix_idx;
+DROP INDEX IF EXISTS users_username_lower_prefix_idx;
+-- +goose StatementEnd
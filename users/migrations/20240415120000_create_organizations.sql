@@ -0,0 +1,63 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS organizations (
+    id         SERIAL       PRIMARY KEY,
+    name       VARCHAR(128) NOT NULL,
+    created_by INTEGER      REFERENCES users (id) ON DELETE SET NULL,
+    created_at TIMESTAMP    NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS organization_members (
+    organization_id INTEGER     NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+    user_id         INTEGER     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    role            VARCHAR(16) NOT NULL CHECK (role IN ('owner', 'admin', 'member')),
+    created_at      TIMESTAMP   NOT NULL DEFAULT now(),
+    PRIMARY KEY (organization_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS organization_members_user_id_idx ON organization_members (user_id);
+
+CREATE TABLE IF NOT EXISTS teams (
+    id              SERIAL       PRIMARY KEY,
+    organization_id INTEGER      NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+    name            VARCHAR(128) NOT NULL,
+    created_at      TIMESTAMP    NOT NULL DEFAULT now(),
+    UNIQUE (organization_id, name)
+);
+
+-- участник команды всегда состоит в ее организации: исключение из организации убирает его из команд
+CREATE TABLE IF NOT EXISTS team_members (
+    team_id         INTEGER   NOT NULL REFERENCES teams (id) ON DELETE CASCADE,
+    organization_id INTEGER   NOT NULL,
+    user_id         INTEGER   NOT NULL,
+    created_at      TIMESTAMP NOT NULL DEFAULT now(),
+    PRIMARY KEY (team_id, user_id),
+    FOREIGN KEY (organization_id, user_id) REFERENCES organization_members (organization_id, user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS team_members_user_id_idx ON team_members (user_id);
+
+CREATE TABLE IF NOT EXISTS organization_invitations (
+    id              UUID         PRIMARY KEY,
+    organization_id INTEGER      NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+    email           VARCHAR(255) NOT NULL,
+    role            VARCHAR(16)  NOT NULL CHECK (role IN ('admin', 'member')),
+    token_hash      VARCHAR(64)  NOT NULL UNIQUE,
+    invited_by      INTEGER      REFERENCES users (id) ON DELETE SET NULL,
+    expires_at      TIMESTAMP    NOT NULL,
+    accepted_at     TIMESTAMP,
+    revoked_at      TIMESTAMP,
+    created_at      TIMESTAMP    NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS organization_invitations_organization_id_idx ON organization_invitations (organization_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS organization_invitations;
+DROP TABLE IF EXISTS team_members;
+DROP TABLE IF EXISTS teams;
+DROP TABLE IF EXISTS organization_members;
+DROP TABLE IF EXISTS organizations;
+-- +goose StatementEnd
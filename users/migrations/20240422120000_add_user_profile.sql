@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS display_name VARCHAR(64)  NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS timezone     VARCHAR(64)  NOT NULL DEFAULT 'UTC',
+    ADD COLUMN IF NOT EXISTS locale       VARCHAR(35)  NOT NULL DEFAULT 'en',
+    ADD COLUMN IF NOT EXISTS avatar_key   VARCHAR(255) NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users
+    DROP COLUMN IF EXISTS avatar_key,
+    DROP COLUMN IF EXISTS locale,
+    DROP COLUMN IF EXISTS timezone,
+    DROP COLUMN IF EXISTS display_name;
+-- +goose StatementEnd
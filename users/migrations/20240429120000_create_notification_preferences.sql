@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- строки нет - пользователь получает уведомление о событии по всем каналам по умолчанию,
+-- пустой массив каналов - событие отключено
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id    INTEGER     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    event      VARCHAR(32) NOT NULL,
+    channels   TEXT[]      NOT NULL DEFAULT '{}',
+    updated_at TIMESTAMP   NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, event)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS notification_preferences;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS email_change_requests (
+    id          SERIAL       PRIMARY KEY,
+    user_id     INTEGER      NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    new_email   VARCHAR(255) NOT NULL,
+    token_hash  VARCHAR(64)  NOT NULL UNIQUE,
+    expires_at  TIMESTAMP    NOT NULL,
+    consumed_at TIMESTAMP,
+    created_at  TIMESTAMP    NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS email_change_requests_user_id_idx ON email_change_requests (user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS email_change_requests;
+-- +goose StatementEnd
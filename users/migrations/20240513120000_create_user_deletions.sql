@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+-- deleted_at - пользователь ждет удаления: он не виден в сервисе и не может войти, пока все сервисы
+-- не подтвердят, что обработали удаление. Если удаление отменено, поле снова становится пустым
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+
+-- строки хранятся и после удаления пользователя, поэтому user_id без внешнего ключа
+CREATE TABLE IF NOT EXISTS user_deletions (
+    id         UUID        PRIMARY KEY,
+    user_id    INTEGER     NOT NULL,
+    status     VARCHAR(16) NOT NULL DEFAULT 'pending',
+    error      TEXT        NOT NULL DEFAULT '',
+    created_at TIMESTAMP   NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP   NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS user_deletions_user_id_idx ON user_deletions (user_id, created_at);
+
+-- шаги удаления - по строке на каждый сервис, который должен подтвердить удаление
+CREATE TABLE IF NOT EXISTS user_deletion_steps (
+    deletion_id UUID        NOT NULL REFERENCES user_deletions (id) ON DELETE CASCADE,
+    service     VARCHAR(32) NOT NULL,
+    status      VARCHAR(16) NOT NULL DEFAULT 'pending',
+    error       TEXT        NOT NULL DEFAULT '',
+    updated_at  TIMESTAMP   NOT NULL DEFAULT now(),
+    PRIMARY KEY (deletion_id, service)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS user_deletion_steps;
+DROP TABLE IF EXISTS user_deletions;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS deleted_at;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- прежние хэши паролей, чтобы пользователь не возвращался к недавним паролям. Текущий пароль хранится в users
+CREATE TABLE IF NOT EXISTS password_history (
+    id         SERIAL       PRIMARY KEY,
+    user_id    INTEGER      NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    password   VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP    NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS password_history_user_id_idx ON password_history (user_id, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS password_history;
+-- +goose StatementEnd
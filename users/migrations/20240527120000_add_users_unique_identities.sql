@@ -0,0 +1,66 @@
+-- +goose Up
+-- +goose StatementBegin
+-- уникальные индексы не создадутся, пока в таблице есть совпадающие без учета регистра username или email.
+-- Такие пользователи перечисляются в ошибке миграции, объединить или переименовать их нужно вручную
+DO $$
+DECLARE
+    duplicates TEXT;
+BEGIN
+    SELECT
+        string_agg(format('%s %L: users %s', kind, value, ids), E'\n')
+    INTO
+        duplicates
+    FROM (
+        SELECT
+            'username' AS kind,
+            lower(username) AS value,
+            string_agg(id::TEXT, ', ' ORDER BY id) AS ids
+        FROM
+            users
+        WHERE
+            username <> ''
+        GROUP BY
+            lower(username)
+        HAVING
+            count(*) > 1
+        UNION ALL
+        SELECT
+            'email' AS kind,
+            lower(trim(email)) AS value,
+            string_agg(id::TEXT, ', ' ORDER BY id) AS ids
+        FROM
+            users
+        WHERE
+            trim(email) <> ''
+        GROUP BY
+            lower(trim(email))
+        HAVING
+            count(*) > 1
+    ) found;
+
+    IF duplicates IS NOT NULL THEN
+        RAISE EXCEPTION 'users with duplicate username or email found:%', E'\n' || duplicates
+            USING HINT = 'rename or merge the listed users and run the migration again';
+    END IF;
+END
+$$;
+
+-- email хранится в нормализованном виде: без пробелов по краям и в нижнем регистре
+UPDATE
+    users
+SET
+    email = lower(trim(email))
+WHERE
+    email <> lower(trim(email));
+
+-- пустые значения не считаются занятыми. Пользователь, который ждет удаления, занимает свои username и email,
+-- пока удаление не завершится: его могут отменить
+CREATE UNIQUE INDEX IF NOT EXISTS users_username_lower_unique_idx ON users (lower(username)) WHERE username <> '';
+CREATE UNIQUE INDEX IF NOT EXISTS users_email_lower_unique_idx ON users (lower(email)) WHERE email <> '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS users_email_lower_unique_idx;
+DROP INDEX IF EXISTS users_username_lower_unique_idx;
+-- +goose StatementEnd
@@ -0,0 +1,46 @@
+-- +goose Up
+-- +goose StatementBegin
+-- выгрузки данных пользователя. Архив хранится в базе и скачивается по токену из письма до expires_at
+CREATE TABLE IF NOT EXISTS data_exports (
+    id         UUID        PRIMARY KEY,
+    user_id    INTEGER     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    status     VARCHAR(16) NOT NULL DEFAULT 'pending',
+    error      TEXT        NOT NULL DEFAULT '',
+    token_hash VARCHAR(64) UNIQUE,
+    archive    BYTEA,
+    expires_at TIMESTAMP,
+    created_at TIMESTAMP   NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP   NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS data_exports_user_id_idx ON data_exports (user_id, created_at);
+
+-- части выгрузки - по строке на каждый сервис, который должен прислать данные пользователя
+CREATE TABLE IF NOT EXISTS data_export_parts (
+    export_id  UUID        NOT NULL REFERENCES data_exports (id) ON DELETE CASCADE,
+    service    VARCHAR(32) NOT NULL,
+    status     VARCHAR(16) NOT NULL DEFAULT 'pending',
+    data       BYTEA,
+    error      TEXT        NOT NULL DEFAULT '',
+    updated_at TIMESTAMP   NOT NULL DEFAULT now(),
+    PRIMARY KEY (export_id, service)
+);
+
+CREATE TABLE IF NOT EXISTS erasure_requests (
+    id          SERIAL      PRIMARY KEY,
+    user_id     INTEGER     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    token_hash  VARCHAR(64) NOT NULL UNIQUE,
+    expires_at  TIMESTAMP   NOT NULL,
+    consumed_at TIMESTAMP,
+    created_at  TIMESTAMP   NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS erasure_requests_user_id_idx ON erasure_requests (user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS erasure_requests;
+DROP TABLE IF EXISTS data_export_parts;
+DROP TABLE IF EXISTS data_exports;
+-- +goose StatementEnd
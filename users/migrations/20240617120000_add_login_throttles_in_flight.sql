@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- попытки входа, которые уже начались, но еще не учтены как удачные или неудачные. Пароль проверяется долго,
+-- и без этого счетчика параллельные запросы проходили бы проверку раньше, чем записана первая неудача
+ALTER TABLE login_throttles
+    ADD COLUMN IF NOT EXISTS in_flight          INTEGER   NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS attempt_started_at TIMESTAMP NOT NULL DEFAULT now();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE login_throttles
+    DROP COLUMN IF EXISTS attempt_started_at,
+    DROP COLUMN IF EXISTS in_flight;
+-- +goose StatementEnd
@@ -0,0 +1,9 @@
+package filestorage
+
+// FileStorage config
+type FileStorage struct {
+	// Dir - каталог, в котором хранятся файлы
+	Dir string `envconfig:"FILE_STORAGE_DIR" default:"./data/files"`
+	// PublicURL - адрес, по которому файлы из каталога раздаются клиентам
+	PublicURL string `envconfig:"FILE_STORAGE_PUBLIC_URL" default:"http://localhost:8000/files"`
+}
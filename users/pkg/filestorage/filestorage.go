@@ -0,0 +1,120 @@
+// Package filestorage хранит файлы в локальном каталоге и раздает их по http.
+// Подходит для одного экземпляра сервиса или общего тома, для нескольких реплик
+// нужна реализация того же интерфейса поверх объектного хранилища.
+package filestorage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ErrInvalidKey - ключ выходит за пределы каталога хранилища
+var ErrInvalidKey = errors.New("invalid file key")
+
+type Storage struct {
+	dir       string
+	publicURL string
+}
+
+func New(cfg *FileStorage) (*Storage, error) {
+	dir, err := filepath.Abs(cfg.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("[New] resolve dir: %w", err)
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("[New] create dir: %w", err)
+	}
+
+	return &Storage{
+		dir:       dir,
+		publicURL: strings.TrimSuffix(cfg.PublicURL, "/"),
+	}, nil
+}
+
+// Put сохраняет файл под ключом. Файл сначала пишется во временный и затем переименовывается,
+// поэтому по http никогда не отдается недописанный файл
+func (s *Storage) Put(_ context.Context, key string, data []byte) error {
+	filePath, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+		return fmt.Errorf("[Put] create dir: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(filePath), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("[Put] create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("[Put] write: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("[Put] close: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return fmt.Errorf("[Put] chmod: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), filePath); err != nil {
+		return fmt.Errorf("[Put] rename: %w", err)
+	}
+
+	return nil
+}
+
+// Delete удаляет файл. Отсутствие файла ошибкой не считается
+func (s *Storage) Delete(_ context.Context, key string) error {
+	filePath, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(filePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("[Delete] remove: %w", err)
+	}
+
+	return nil
+}
+
+// URL возвращает адрес, по которому файл доступен клиентам
+func (s *Storage) URL(key string) string {
+	return s.publicURL + "/" + key
+}
+
+// Handler раздает файлы хранилища. Список файлов каталога не отдается
+func (s *Storage) Handler() http.Handler {
+	files := http.FileServer(http.Dir(s.dir))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/") {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+		files.ServeHTTP(w, r)
+	})
+}
+
+// path переводит ключ вида "dir/name" в путь внутри каталога хранилища
+func (s *Storage) path(key string) (string, error) {
+	cleaned := path.Clean("/" + key)
+	if key == "" || cleaned == "/" || cleaned[1:] != key {
+		return "", ErrInvalidKey
+	}
+
+	return filepath.Join(s.dir, filepath.FromSlash(cleaned)), nil
+}
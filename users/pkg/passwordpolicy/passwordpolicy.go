@@ -0,0 +1,166 @@
+// Package passwordpolicy проверяет новые пароли по настраиваемым правилам: длина, классы символов,
+// список запрещенных паролей и сходство с данными пользователя. Проверка возвращает все нарушенные
+// правила сразу, чтобы пользователь мог исправить пароль за одну попытку.
+package passwordpolicy
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Правила, которые может нарушить пароль
+const (
+	RuleTooShort         = "too_short"
+	RuleTooLong          = "too_long"
+	RuleMissingLowercase = "missing_lowercase"
+	RuleMissingUppercase = "missing_uppercase"
+	RuleMissingDigit     = "missing_digit"
+	RuleMissingSymbol    = "missing_symbol"
+	RuleBanned           = "banned"
+	RuleSimilarToUser    = "similar_to_user"
+)
+
+// minUserInfoLength - более короткие имя и часть email до @ не проверяются, иначе запрещались бы случайные совпадения
+const minUserInfoLength = 3
+
+//go:embed banned.txt
+var defaultBanned string
+
+// Rules - требования к паролю, нулевое значение требования его отключает
+type Rules struct {
+	MinLength        int
+	MaxLength        int
+	RequireLowercase bool
+	RequireUppercase bool
+	RequireDigit     bool
+	RequireSymbol    bool
+	// RejectUserInfo - пароль не должен содержать имя пользователя или email
+	RejectUserInfo bool
+}
+
+// Violation - нарушенное правило и понятное пользователю описание
+type Violation struct {
+	Rule    string
+	Message string
+}
+
+// Policy - правила вместе со списком запрещенных паролей
+type Policy struct {
+	rules  Rules
+	banned map[string]struct{}
+}
+
+// New создает политику со встроенным списком запрещенных паролей, к которому добавляются пароли из lists
+func New(rules Rules, lists ...io.Reader) (*Policy, error) {
+	p := &Policy{
+		rules:  rules,
+		banned: make(map[string]struct{}),
+	}
+
+	if err := p.addBanned(strings.NewReader(defaultBanned)); err != nil {
+		return nil, fmt.Errorf("read default list: %w", err)
+	}
+	for _, list := range lists {
+		if err := p.addBanned(list); err != nil {
+			return nil, fmt.Errorf("read list: %w", err)
+		}
+	}
+
+	return p, nil
+}
+
+// addBanned читает пароли по одному в строке, пустые строки и строки с # пропускаются
+func (p *Policy) addBanned(list io.Reader) error {
+	scanner := bufio.NewScanner(list)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		p.banned[strings.ToLower(line)] = struct{}{}
+	}
+
+	return scanner.Err()
+}
+
+// Check возвращает все правила, которые нарушает password. userInfo - имя пользователя, email и другие данные,
+// которые не должны быть частью пароля. Пустой результат - пароль подходит
+func (p *Policy) Check(password string, userInfo ...string) []Violation {
+	violations := make([]Violation, 0)
+
+	length := utf8.RuneCountInString(password)
+	if p.rules.MinLength > 0 && length < p.rules.MinLength {
+		violations = append(violations, Violation{
+			Rule:    RuleTooShort,
+			Message: fmt.Sprintf("password must be at least %d characters long", p.rules.MinLength),
+		})
+	}
+	if p.rules.MaxLength > 0 && length > p.rules.MaxLength {
+		violations = append(violations, Violation{
+			Rule:    RuleTooLong,
+			Message: fmt.Sprintf("password must be at most %d characters long", p.rules.MaxLength),
+		})
+	}
+
+	var hasLower, hasUpper, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			hasSymbol = true
+		}
+	}
+	if p.rules.RequireLowercase && !hasLower {
+		violations = append(violations, Violation{Rule: RuleMissingLowercase, Message: "password must contain a lowercase letter"})
+	}
+	if p.rules.RequireUppercase && !hasUpper {
+		violations = append(violations, Violation{Rule: RuleMissingUppercase, Message: "password must contain an uppercase letter"})
+	}
+	if p.rules.RequireDigit && !hasDigit {
+		violations = append(violations, Violation{Rule: RuleMissingDigit, Message: "password must contain a digit"})
+	}
+	if p.rules.RequireSymbol && !hasSymbol {
+		violations = append(violations, Violation{Rule: RuleMissingSymbol, Message: "password must contain a special character"})
+	}
+
+	lower := strings.ToLower(password)
+	if _, ok := p.banned[lower]; ok {
+		violations = append(violations, Violation{Rule: RuleBanned, Message: "password is too common, choose another one"})
+	}
+
+	if p.rules.RejectUserInfo && containsUserInfo(lower, userInfo) {
+		violations = append(violations, Violation{Rule: RuleSimilarToUser, Message: "password must not contain the username or email"})
+	}
+
+	return violations
+}
+
+// containsUserInfo - пароль содержит имя, email или часть email до @. Сравнение без учета регистра
+func containsUserInfo(password string, userInfo []string) bool {
+	for _, info := range userInfo {
+		info = strings.ToLower(strings.TrimSpace(info))
+
+		parts := []string{info}
+		if local, _, ok := strings.Cut(info, "@"); ok {
+			parts = append(parts, local)
+		}
+
+		for _, part := range parts {
+			if utf8.RuneCountInString(part) >= minUserInfoLength && strings.Contains(password, part) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,86 @@
+package passwordpolicy
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// strictRules включает все требования, чтобы каждое нарушение можно было получить по отдельности
+var strictRules = Rules{
+	MinLength:        8,
+	MaxLength:        16,
+	RequireLowercase: true,
+	RequireUppercase: true,
+	RequireDigit:     true,
+	RequireSymbol:    true,
+	RejectUserInfo:   true,
+}
+
+func rulesOf(violations []Violation) []string {
+	rules := make([]string, len(violations))
+	for i, violation := range violations {
+		rules[i] = violation.Rule
+	}
+	return rules
+}
+
+func TestCheck(t *testing.T) {
+	policy, err := New(strictRules, strings.NewReader("# свой список\n\nCorrect-Horse-1\n"))
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		password string
+		userInfo []string
+		want     []string
+	}{
+		{"meets every rule", "Tr0ub4dor&3", nil, []string{}},
+		{"too short", "Ab1!", nil, []string{RuleTooShort}},
+		{"too long", "Abcdefgh1!abcdefg", nil, []string{RuleTooLong}},
+		{"length counts characters, not bytes", "Пароль-Пароль1", nil, []string{}},
+		{"missing lowercase", "ABCDEFG1!", nil, []string{RuleMissingLowercase}},
+		{"missing uppercase", "abcdefg1!", nil, []string{RuleMissingUppercase}},
+		{"missing digit", "Abcdefgh!", nil, []string{RuleMissingDigit}},
+		{"missing symbol", "Abcdefgh1", nil, []string{RuleMissingSymbol}},
+		{"space counts as symbol", "Abcd efg1", nil, []string{}},
+		{"banned from default list", "password", nil, []string{RuleMissingUppercase, RuleMissingDigit, RuleMissingSymbol, RuleBanned}},
+		{"banned ignores case", "QWERTY", nil, []string{RuleTooShort, RuleMissingLowercase, RuleMissingDigit, RuleMissingSymbol, RuleBanned}},
+		{"banned from extra list", "correct-horse-1", nil, []string{RuleMissingUppercase, RuleBanned}},
+		{"contains username", "xJohnDoe1!", []string{"johndoe", "jd@example.com"}, []string{RuleSimilarToUser}},
+		{"contains email local part", "Mail-alice7", []string{"bob", "Alice@example.com"}, []string{RuleSimilarToUser}},
+		{"short user info is ignored", "Tr0ub4dor&3", []string{"tr", "tr@example.com"}, []string{}},
+		{"every rule at once", "", nil, []string{RuleTooShort, RuleMissingLowercase, RuleMissingUppercase, RuleMissingDigit, RuleMissingSymbol}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations := policy.Check(tt.password, tt.userInfo...)
+			if got := rulesOf(violations); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Check(%q) rules = %v, want %v", tt.password, got, tt.want)
+			}
+			for _, violation := range violations {
+				if violation.Message == "" {
+					t.Errorf("Check(%q) rule %s has no message", tt.password, violation.Rule)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckDisabledRules(t *testing.T) {
+	policy, err := New(Rules{})
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	// без требований остается только встроенный список запрещенных паролей
+	if got := rulesOf(policy.Check("x", "x@example.com")); len(got) != 0 {
+		t.Errorf("Check with no rules = %v, want no violations", got)
+	}
+	if got := rulesOf(policy.Check("123456")); !reflect.DeepEqual(got, []string{RuleBanned}) {
+		t.Errorf("Check(\"123456\") with no rules = %v, want [%s]", got, RuleBanned)
+	}
+}
@@ -0,0 +1,67 @@
+// Package thumbnail строит квадратные миниатюры изображений без внешних зависимостей.
+// Изображение обрезается по центру до квадрата и уменьшается усреднением пикселей,
+// поэтому мелкие детали не дают ряби, как при выборке ближайшего пикселя.
+package thumbnail
+
+import (
+	"image"
+	"image/color"
+)
+
+// Square возвращает миниатюру size x size. Изображения меньше size растягиваются
+func Square(src image.Image, size int) *image.RGBA {
+	bounds := src.Bounds()
+	side := bounds.Dx()
+	if bounds.Dy() < side {
+		side = bounds.Dy()
+	}
+
+	// квадрат по центру исходного изображения
+	left := bounds.Min.X + (bounds.Dx()-side)/2
+	top := bounds.Min.Y + (bounds.Dy()-side)/2
+
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	if side == 0 {
+		return dst
+	}
+
+	for y := 0; y < size; y++ {
+		y0, y1 := span(y, side, size)
+		for x := 0; x < size; x++ {
+			x0, x1 := span(x, side, size)
+
+			var r, g, b, a uint64
+			for sy := top + y0; sy < top+y1; sy++ {
+				for sx := left + x0; sx < left+x1; sx++ {
+					pr, pg, pb, pa := src.At(sx, sy).RGBA()
+					r += uint64(pr)
+					g += uint64(pg)
+					b += uint64(pb)
+					a += uint64(pa)
+				}
+			}
+
+			// RGBA возвращает 16-битные компоненты с умножением на альфу, как и ожидает color.RGBA
+			count := uint64((x1 - x0) * (y1 - y0))
+			dst.SetRGBA(x, y, color.RGBA{
+				R: uint8(r / count >> 8),
+				G: uint8(g / count >> 8),
+				B: uint8(b / count >> 8),
+				A: uint8(a / count >> 8),
+			})
+		}
+	}
+
+	return dst
+}
+
+// span возвращает отрезок исходных пикселей, из которых складывается пиксель i миниатюры
+func span(i, side, size int) (int, int) {
+	from := i * side / size
+	to := (i + 1) * side / size
+	if to <= from {
+		to = from + 1
+	}
+
+	return from, to
+}